@@ -0,0 +1,392 @@
+// Package archive implements a read only overlay backend which shows
+// the contents of zip and tar archives as directories.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/configstruct"
+	"github.com/rclone/rclone/fs/hash"
+	libcache "github.com/rclone/rclone/lib/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// Archive formats
+const (
+	formatZip = "zip"
+	formatTar = "tar"
+)
+
+// archiveSuffixes maps file name suffixes onto archive formats
+var archiveSuffixes = []struct {
+	suffix string
+	format string
+}{
+	{".zip", formatZip},
+	{".tar", formatTar},
+}
+
+// errorReadOnly is returned for any attempt to modify the remote
+var errorReadOnly = errors.New("archive remotes are read only")
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "archive",
+		Description: "Read archives on a remote as directories",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Required: true,
+			Help: `Remote containing the archives to read.
+
+Normally should contain a ':' and a path, e.g. "myremote:path/to/dir",
+"myremote:bucket" or maybe "myremote:".`,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote string `config:"remote"`
+}
+
+// Fs shows the archives in a remote as directories
+type Fs struct {
+	name     string
+	root     string
+	opt      *Options
+	features *fs.Features
+	base     fs.Fs              // the remote being read - root is not adjusted
+	reads    singleflight.Group // reads of indexes in progress by path in base
+	indexes  *libcache.Cache    // indexes of archives by path in base, dropped when unused
+}
+
+// NewFs constructs an Fs from the remote:path string
+func NewFs(ctx context.Context, fsname, rpath string, cmap configmap.Mapper) (fs.Fs, error) {
+	opt := &Options{}
+	err := configstruct.Set(cmap, opt)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(opt.Remote, fsname+":") {
+		return nil, errors.New("can't point remote at itself")
+	}
+	// The root is applied by us rather than the base remote as
+	// it may point inside an archive.
+	baseFs, err := cache.Get(ctx, opt.Remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make remote %q to wrap: %w", opt.Remote, err)
+	}
+
+	f := &Fs{
+		name:    fsname,
+		root:    strings.Trim(rpath, "/"),
+		opt:     opt,
+		base:    baseFs,
+		indexes: libcache.New(),
+	}
+	f.features = (&fs.Features{
+		CanHaveEmptyDirectories: true,
+	}).Fill(ctx, f)
+
+	cache.Pin(f.base)
+	runtime.SetFinalizer(f, func(f *Fs) {
+		cache.Unpin(f.base)
+	})
+
+	// Correct root if pointing to a file
+	if f.root != "" {
+		_, err := f.NewObject(ctx, "")
+		if err == nil {
+			f.root = path.Dir(f.root)
+			if f.root == "." {
+				f.root = ""
+			}
+			return f, fs.ErrorIsFile
+		}
+	}
+	return f, nil
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string { return f.name }
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string { return f.root }
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features { return f.features }
+
+// Hashes returns the supported hash sets.
+//
+// These are the hashes of the base remote for files which aren't in
+// an archive. Zip archives store a CRC32 of each member.
+func (f *Fs) Hashes() hash.Set {
+	hashes := f.base.Hashes()
+	return hashes.Add(hash.CRC32)
+}
+
+// Precision returns the precision of this Fs
+//
+// Tar archives store modification times to the second but zip
+// archives use DOS times which only have a resolution of 2 seconds.
+// Unless the root is inside a tar archive there may be zip archives
+// below it so the coarser precision is used.
+func (f *Fs) Precision() time.Duration {
+	precision := 2 * time.Second
+	if f.root != "" {
+		for _, element := range strings.Split(f.root, "/") {
+			if format := archiveFormat(element); format != "" {
+				if format == formatTar {
+					precision = time.Second
+				}
+				break
+			}
+		}
+	}
+	return max(f.base.Precision(), precision)
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Archive '%s:%s'", f.name, f.root)
+}
+
+// archiveFormat returns the archive format for the file name or ""
+// if it isn't an archive.
+func archiveFormat(name string) string {
+	lowerName := strings.ToLower(name)
+	for _, x := range archiveSuffixes {
+		if strings.HasSuffix(lowerName, x.suffix) {
+			return x.format
+		}
+	}
+	return ""
+}
+
+// basePath returns the path in the base remote of remote
+func (f *Fs) basePath(remote string) string {
+	return path.Join(f.root, remote)
+}
+
+// remote returns the path relative to the root of basePath
+func (f *Fs) remote(basePath string) string {
+	if f.root == "" {
+		return basePath
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(basePath, f.root), "/")
+}
+
+// findArchive looks for an archive containing basePath.
+//
+// If found it returns the index of the archive and the path within
+// it, otherwise it returns a nil index.
+func (f *Fs) findArchive(ctx context.Context, basePath string) (idx *archiveIndex, inner string, err error) {
+	if basePath == "" {
+		return nil, "", nil
+	}
+	elements := strings.Split(basePath, "/")
+	for i := range elements {
+		format := archiveFormat(elements[i])
+		if format == "" {
+			continue
+		}
+		archivePath := strings.Join(elements[:i+1], "/")
+		o, err := f.base.NewObject(ctx, archivePath)
+		if errors.Is(err, fs.ErrorObjectNotFound) || errors.Is(err, fs.ErrorIsDir) || errors.Is(err, fs.ErrorNotAFile) {
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		idx, err = f.getIndex(ctx, o, format)
+		if err != nil {
+			return nil, "", err
+		}
+		return idx, strings.Join(elements[i+1:], "/"), nil
+	}
+	return nil, "", nil
+}
+
+// getIndex returns the index for the archive o, reading it if it
+// isn't cached or o has changed since it was read.
+//
+// Only one read of the index of each archive is done at once, with
+// any other callers wanting it waiting for its result.
+func (f *Fs) getIndex(ctx context.Context, o fs.Object, format string) (*archiveIndex, error) {
+	key := o.Remote()
+	if value, found := f.indexes.GetMaybe(key); found {
+		if idx := value.(*archiveIndex); idx.matches(ctx, o) {
+			return idx, nil
+		}
+	}
+	value, err, _ := f.reads.Do(key, func() (any, error) {
+		fs.Debugf(o, "Reading %s archive index", format)
+		idx, err := newArchiveIndex(ctx, o, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive %q: %w", key, err)
+		}
+		f.indexes.Put(key, idx)
+		return idx, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*archiveIndex), nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// Archives in the remote are shown as directories.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	basePath := f.basePath(dir)
+	idx, inner, err := f.findArchive(ctx, basePath)
+	if err != nil {
+		return nil, err
+	}
+	if idx != nil {
+		return idx.list(f, inner)
+	}
+	baseEntries, err := f.base.List(ctx, basePath)
+	if err != nil {
+		return nil, err
+	}
+	entries = make(fs.DirEntries, 0, len(baseEntries))
+	for _, entry := range baseEntries {
+		remote := f.remote(entry.Remote())
+		switch x := entry.(type) {
+		case fs.Object:
+			if archiveFormat(remote) != "" {
+				entries = append(entries, fs.NewDir(remote, x.ModTime(ctx)).SetSize(x.Size()))
+			} else {
+				entries = append(entries, &Object{Object: x, f: f, remote: remote})
+			}
+		case fs.Directory:
+			entries = append(entries, fs.NewDirCopy(ctx, x).SetRemote(remote))
+		default:
+			return nil, fmt.Errorf("unknown object type %T", entry)
+		}
+	}
+	return entries, nil
+}
+
+// NewObject finds the Object at remote.  If it can't be found
+// it returns the error ErrorObjectNotFound.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	basePath := f.basePath(remote)
+	idx, inner, err := f.findArchive(ctx, basePath)
+	if err != nil {
+		return nil, err
+	}
+	if idx != nil {
+		if inner == "" {
+			return nil, fs.ErrorIsDir
+		}
+		m := idx.files[inner]
+		if m == nil {
+			return nil, fs.ErrorObjectNotFound
+		}
+		return &Member{f: f, idx: idx, m: m, remote: remote}, nil
+	}
+	if basePath == "" {
+		return nil, fs.ErrorIsDir
+	}
+	o, err := f.base.NewObject(ctx, basePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{Object: o, f: f, remote: remote}, nil
+}
+
+// Put is not supported as archive remotes are read only
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return nil, errorReadOnly
+}
+
+// Mkdir is not supported as archive remotes are read only
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return errorReadOnly
+}
+
+// Rmdir is not supported as archive remotes are read only
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	return errorReadOnly
+}
+
+// Object is a file in the remote which isn't in an archive
+type Object struct {
+	fs.Object
+	f      *Fs
+	remote string
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info { return o.f }
+
+// Remote returns the remote path
+func (o *Object) Remote() string { return o.remote }
+
+// String returns a description of the Object
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Hash returns the selected checksum of the file
+//
+// Only the hashes of the base remote are available, otherwise "" is
+// returned for the CRC32 of files in archives.
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if !o.f.Hashes().Contains(ht) {
+		return "", hash.ErrUnsupported
+	}
+	if !o.f.base.Hashes().Contains(ht) {
+		return "", nil
+	}
+	return o.Object.Hash(ctx, ht)
+}
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object { return o.Object }
+
+// SetModTime is not supported as archive remotes are read only
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	return errorReadOnly
+}
+
+// Update is not supported as archive remotes are read only
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errorReadOnly
+}
+
+// Remove is not supported as archive remotes are read only
+func (o *Object) Remove(ctx context.Context) error {
+	return errorReadOnly
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+)
@@ -0,0 +1,338 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = fstest.Time("2017-02-03T04:05:06Z")
+	t2 = fstest.Time("2020-06-07T08:09:10Z")
+)
+
+// testFile is a file to put in the test archives
+type testFile struct {
+	name    string
+	content string
+	modTime time.Time
+}
+
+var testFiles = []testFile{
+	{"file1.txt", "hello world", t1},
+	{"dir/file2.txt", strings.Repeat("potato ", 10000), t2},
+	{"dir/sub/empty.txt", "", t1},
+}
+
+// writeZip writes testFiles to a zip archive at filePath
+func writeZip(t *testing.T, filePath string) {
+	out, err := os.Create(filePath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(out)
+	_, err = zw.CreateHeader(&zip.FileHeader{Name: "dir/", Modified: t2})
+	require.NoError(t, err)
+	for i, file := range testFiles {
+		method := zip.Deflate
+		if i%2 == 0 {
+			method = zip.Store
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: method, Modified: file.modTime})
+		require.NoError(t, err)
+		_, err = io.WriteString(w, file.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, out.Close())
+}
+
+// writeTar writes testFiles to a tar archive at filePath
+func writeTar(t *testing.T, filePath string) {
+	out, err := os.Create(filePath)
+	require.NoError(t, err)
+	tw := tar.NewWriter(out)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "dir/", Mode: 0755, ModTime: t2}))
+	for _, file := range testFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Size:     int64(len(file.content)),
+			Mode:     0644,
+			ModTime:  file.modTime,
+		}))
+		_, err = io.WriteString(tw, file.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, out.Close())
+}
+
+// makeFs makes an archive Fs with root rpath on a directory
+// containing test.zip, test.tar and plain.txt
+func makeFs(t *testing.T, rpath string) (fs.Fs, string, error) {
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "test.zip"))
+	writeTar(t, filepath.Join(dir, "test.tar"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.txt"), []byte("plain"), 0666))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "plain.txt"), t1, t1))
+	f, err := NewFs(context.Background(), "TestArchive", rpath, configmap.Simple{"remote": dir})
+	return f, dir, err
+}
+
+// readAll reads the contents of o opened with options
+func readAll(ctx context.Context, o fs.Object, options ...fs.OpenOption) (string, error) {
+	rc, err := o.Open(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(rc)
+	if closeErr := rc.Close(); err == nil {
+		err = closeErr
+	}
+	return string(data), err
+}
+
+func TestArchiveListing(t *testing.T) {
+	f, _, err := makeFs(t, "")
+	require.NoError(t, err)
+
+	var items []fstest.Item
+	items = append(items, fstest.NewItem("plain.txt", "plain", t1))
+	for _, archive := range []string{"test.zip", "test.tar"} {
+		for _, file := range testFiles {
+			items = append(items, fstest.NewItem(archive+"/"+file.name, file.content, file.modTime))
+		}
+	}
+	fstest.CheckListingWithPrecision(t, f, items, []string{
+		"test.zip",
+		"test.zip/dir",
+		"test.zip/dir/sub",
+		"test.tar",
+		"test.tar/dir",
+		"test.tar/dir/sub",
+	}, time.Second)
+
+	ctx := context.Background()
+	_, err = f.List(ctx, "test.zip/missing")
+	assert.ErrorIs(t, err, fs.ErrorDirNotFound)
+	_, err = f.NewObject(ctx, "test.tar/missing")
+	assert.ErrorIs(t, err, fs.ErrorObjectNotFound)
+	_, err = f.NewObject(ctx, "test.zip")
+	assert.ErrorIs(t, err, fs.ErrorIsDir)
+
+	// Check the modification time of an explicit directory
+	entries, err := f.List(ctx, "test.zip")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.Remote() == "test.zip/dir" {
+			assert.True(t, entry.ModTime(ctx).Equal(t2))
+		}
+	}
+}
+
+func TestArchiveOpen(t *testing.T) {
+	f, _, err := makeFs(t, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+	want := testFiles[1].content
+
+	for _, archive := range []string{"test.zip", "test.tar"} {
+		t.Run(archive, func(t *testing.T) {
+			o, err := f.NewObject(ctx, archive+"/dir/file2.txt")
+			require.NoError(t, err)
+
+			for _, test := range []struct {
+				options []fs.OpenOption
+				want    string
+			}{
+				{nil, want},
+				{[]fs.OpenOption{&fs.SeekOption{Offset: 1000}}, want[1000:]},
+				{[]fs.OpenOption{&fs.RangeOption{Start: 7, End: 20}}, want[7:21]},
+				{[]fs.OpenOption{&fs.RangeOption{Start: -1, End: 5}}, want[len(want)-5:]},
+			} {
+				got, err := readAll(ctx, o, test.options...)
+				require.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+
+			// Check an empty file
+			o, err = f.NewObject(ctx, archive+"/dir/sub/empty.txt")
+			require.NoError(t, err)
+			got, err := readAll(ctx, o)
+			require.NoError(t, err)
+			assert.Equal(t, "", got)
+		})
+	}
+}
+
+func TestArchiveIndexShared(t *testing.T) {
+	f, _, err := makeFs(t, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Concurrent lookups in one archive share the one index read
+	const n = 8
+	var wg sync.WaitGroup
+	idxs := make([]*archiveIndex, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o, err := f.NewObject(ctx, "test.zip/file1.txt")
+			assert.NoError(t, err)
+			if o != nil {
+				idxs[i] = o.(*Member).idx
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range n {
+		require.NotNil(t, idxs[i])
+		assert.Equal(t, idxs[0], idxs[i])
+	}
+}
+
+func TestArchiveCRC(t *testing.T) {
+	f, _, err := makeFs(t, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// dir/file2.txt is deflated and file1.txt is stored
+	for _, name := range []string{"dir/file2.txt", "file1.txt"} {
+		o, err := f.NewObject(ctx, "test.zip/"+name)
+		require.NoError(t, err)
+		m := o.(*Member).m
+		m.zf.CRC32 ^= 1
+
+		// Reading the whole file checks the CRC32
+		_, err = readAll(ctx, o)
+		assert.ErrorContains(t, err, "CRC32", name)
+
+		// Reading part of it can't
+		_, err = readAll(ctx, o, &fs.RangeOption{Start: 1, End: 5})
+		assert.NoError(t, err, name)
+	}
+
+	// Files outside archives have the hashes of the base remote
+	assert.True(t, f.Hashes().Contains(hash.MD5))
+	assert.True(t, f.Hashes().Contains(hash.CRC32))
+	o, err := f.NewObject(ctx, "plain.txt")
+	require.NoError(t, err)
+	sum, err := o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.NotEqual(t, "", sum)
+
+	// Files in archives only have the CRC32
+	o, err = f.NewObject(ctx, "test.zip/file1.txt")
+	require.NoError(t, err)
+	sum, err = o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, "", sum)
+	sum, err = o.Hash(ctx, hash.CRC32)
+	require.NoError(t, err)
+	assert.NotEqual(t, "", sum)
+}
+
+func TestArchiveRoot(t *testing.T) {
+	ctx := context.Background()
+
+	// Root inside an archive
+	f, _, err := makeFs(t, "test.zip/dir")
+	require.NoError(t, err)
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Remote())
+	}
+	assert.ElementsMatch(t, []string{"file2.txt", "sub"}, names)
+
+	// Root pointing at a file in an archive
+	f, _, err = makeFs(t, "test.tar/file1.txt")
+	assert.Equal(t, fs.ErrorIsFile, err)
+	require.NotNil(t, f)
+	assert.Equal(t, "test.tar", f.Root())
+	o, err := f.NewObject(ctx, "file1.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("hello world")), o.Size())
+
+	// Root pointing at an ordinary file
+	_, _, err = makeFs(t, "plain.txt")
+	assert.Equal(t, fs.ErrorIsFile, err)
+}
+
+func TestArchivePrecision(t *testing.T) {
+	for _, test := range []struct {
+		root string
+		want time.Duration
+	}{
+		{"", 2 * time.Second},
+		{"test.zip/dir", 2 * time.Second},
+		{"test.tar", time.Second},
+	} {
+		f, _, err := makeFs(t, test.root)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, f.Precision(), test.root)
+	}
+}
+
+func TestArchiveReadOnly(t *testing.T) {
+	f, _, err := makeFs(t, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	src := object.NewStaticObjectInfo("new.txt", t1, 1, true, nil, nil)
+	_, err = f.Put(ctx, strings.NewReader("x"), src)
+	assert.Equal(t, errorReadOnly, err)
+	assert.Equal(t, errorReadOnly, f.Mkdir(ctx, "dir"))
+
+	o, err := f.NewObject(ctx, "plain.txt")
+	require.NoError(t, err)
+	assert.Equal(t, errorReadOnly, o.Remove(ctx))
+	o, err = f.NewObject(ctx, "test.zip/file1.txt")
+	require.NoError(t, err)
+	assert.Equal(t, errorReadOnly, o.Remove(ctx))
+}
+
+func TestArchiveIndexRefresh(t *testing.T) {
+	f, dir, err := makeFs(t, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = f.NewObject(ctx, "test.tar/file1.txt")
+	require.NoError(t, err)
+
+	// Replace the archive and check the new contents are seen
+	out, err := os.Create(filepath.Join(dir, "test.tar"))
+	require.NoError(t, err)
+	tw := tar.NewWriter(out)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "new.txt", Size: 3, Mode: 0644, ModTime: t2}))
+	_, err = io.WriteString(tw, "new")
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, out.Close())
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "test.tar"), t2, t2))
+
+	_, err = f.NewObject(ctx, "test.tar/file1.txt")
+	assert.ErrorIs(t, err, fs.ErrorObjectNotFound)
+	o, err := f.NewObject(ctx, "test.tar/new.txt")
+	require.NoError(t, err)
+	got, err := readAll(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, "new", got)
+}
@@ -0,0 +1,226 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	libarchive "github.com/rclone/rclone/lib/archive"
+)
+
+// member describes a file in an archive
+type member struct {
+	name    string    // path within the archive
+	size    int64     // uncompressed size
+	modTime time.Time // modification time
+	crc32   string    // hex CRC32 or "" if not known
+	offset  int64     // offset of the data in the archive or -1 if not known yet
+	zf      *zip.File // set for zip archives
+}
+
+// archiveIndex is the directory tree of an archive
+type archiveIndex struct {
+	o        fs.Object            // the archive
+	path     string               // path of the archive in the base remote
+	size     int64                // size of the archive when read
+	modTime  time.Time            // modification time of the archive when read
+	files    map[string]*member   // files by path
+	dirs     map[string]time.Time // directories by path, including the root ""
+	children map[string][]string  // paths of the entries in each directory
+
+	// Reading the data offset of a zip member needs the local
+	// header which is read with ra on demand.
+	mu sync.Mutex
+	ra *object.ReaderAt
+}
+
+// newArchiveIndex reads the index of the archive o in format
+func newArchiveIndex(ctx context.Context, o fs.Object, format string) (*archiveIndex, error) {
+	idx := &archiveIndex{
+		o:        o,
+		path:     o.Remote(),
+		size:     o.Size(),
+		modTime:  o.ModTime(ctx),
+		files:    make(map[string]*member),
+		dirs:     map[string]time.Time{"": o.ModTime(ctx)},
+		children: make(map[string][]string),
+	}
+	var err error
+	switch format {
+	case formatZip:
+		err = idx.readZip(ctx)
+	case formatTar:
+		err = idx.readTar(ctx)
+	default:
+		err = fmt.Errorf("unknown archive format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// matches returns true if o is unchanged since the index was read
+func (idx *archiveIndex) matches(ctx context.Context, o fs.Object) bool {
+	return o.Size() == idx.size && o.ModTime(ctx).Equal(idx.modTime)
+}
+
+// parent returns the directory containing name
+func parent(name string) string {
+	dir := path.Dir(name)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// addDir adds the directory name and any missing parents
+//
+// If explicit is set modTime is used even if the directory exists
+// already as it came from the archive.
+func (idx *archiveIndex) addDir(name string, modTime time.Time, explicit bool) {
+	if _, found := idx.dirs[name]; found {
+		if explicit {
+			idx.dirs[name] = modTime
+		}
+		return
+	}
+	idx.dirs[name] = modTime
+	dir := parent(name)
+	idx.children[dir] = append(idx.children[dir], name)
+	idx.addDir(dir, idx.modTime, false)
+}
+
+// addFile adds the file m and any missing parent directories
+//
+// Later files with the same name replace earlier ones as they would
+// when extracting the archive.
+func (idx *archiveIndex) addFile(m *member) {
+	if _, found := idx.dirs[m.name]; found {
+		fs.Debugf(idx.o, "Ignoring file %q with the same name as a directory", m.name)
+		return
+	}
+	if _, found := idx.files[m.name]; !found {
+		dir := parent(m.name)
+		idx.children[dir] = append(idx.children[dir], m.name)
+		idx.addDir(dir, idx.modTime, false)
+	}
+	idx.files[m.name] = m
+}
+
+// readZip reads the central directory of a zip archive
+func (idx *archiveIndex) readZip(ctx context.Context) error {
+	// The ReaderAt is kept for reading the local headers later so
+	// mustn't be cancelled with ctx.
+	idx.ra = object.NewReaderAt(context.WithoutCancel(ctx), idx.o)
+	defer func() {
+		_ = idx.ra.Close()
+	}()
+	zr, err := zip.NewReader(idx.ra, idx.size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip directory: %w", err)
+	}
+	for _, zf := range zr.File {
+		name := libarchive.CleanName(zf.Name)
+		if name == "" {
+			continue
+		}
+		if zf.FileInfo().IsDir() {
+			idx.addDir(name, zf.Modified, true)
+			continue
+		}
+		idx.addFile(&member{
+			name:    name,
+			size:    int64(zf.UncompressedSize64),
+			modTime: zf.Modified,
+			crc32:   fmt.Sprintf("%08x", zf.CRC32),
+			offset:  -1,
+			zf:      zf,
+		})
+	}
+	return nil
+}
+
+// readTar reads the headers of a tar archive
+//
+// The tar reader seeks over the file data so only the headers are
+// downloaded unless the files are small.
+func (idx *archiveIndex) readTar(ctx context.Context) (err error) {
+	ra := object.NewReaderAt(ctx, idx.o)
+	defer fs.CheckClose(ra, &err)
+	in := io.NewSectionReader(ra, 0, idx.size)
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+		name := libarchive.CleanName(hdr.Name)
+		if name == "" {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			idx.addDir(name, hdr.ModTime, true)
+		case tar.TypeReg:
+			offset, err := in.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			idx.addFile(&member{
+				name:    name,
+				size:    hdr.Size,
+				modTime: hdr.ModTime,
+				offset:  offset,
+			})
+		default:
+			fs.Debugf(idx.o, "Skipping %q: unsupported tar entry type %q", hdr.Name, string(hdr.Typeflag))
+		}
+	}
+}
+
+// dataOffset returns the offset of the data of m in the archive
+func (idx *archiveIndex) dataOffset(m *member) (int64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if m.offset >= 0 {
+		return m.offset, nil
+	}
+	offset, err := m.zf.DataOffset()
+	// Don't keep the stream open as the next read is unlikely to
+	// be nearby.
+	_ = idx.ra.Close()
+	if err != nil {
+		return -1, fmt.Errorf("failed to read zip header for %q: %w", m.name, err)
+	}
+	m.offset = offset
+	return offset, nil
+}
+
+// list returns the entries in dir within the archive
+func (idx *archiveIndex) list(f *Fs, dir string) (entries fs.DirEntries, err error) {
+	if _, found := idx.dirs[dir]; !found {
+		return nil, fs.ErrorDirNotFound
+	}
+	children := idx.children[dir]
+	entries = make(fs.DirEntries, 0, len(children))
+	for _, name := range children {
+		remote := f.remote(path.Join(idx.path, name))
+		if m := idx.files[name]; m != nil {
+			entries = append(entries, &Member{f: f, idx: idx, m: m, remote: remote})
+		} else {
+			entries = append(entries, fs.NewDir(remote, idx.dirs[name]))
+		}
+	}
+	return entries, nil
+}
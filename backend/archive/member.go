@@ -0,0 +1,183 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/lib/readers"
+)
+
+// Member is a file inside an archive
+type Member struct {
+	f      *Fs
+	idx    *archiveIndex
+	m      *member
+	remote string
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Member) Fs() fs.Info { return o.f }
+
+// String returns a description of the Object
+func (o *Member) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *Member) Remote() string { return o.remote }
+
+// Size returns the uncompressed size of the file
+func (o *Member) Size() int64 { return o.m.size }
+
+// ModTime returns the modification time of the file
+func (o *Member) ModTime(ctx context.Context) time.Time { return o.m.modTime }
+
+// Storable returns whether object is storable
+func (o *Member) Storable() bool { return true }
+
+// Hash returns the selected checksum of the file
+//
+// Only zip archives store a CRC32, otherwise "" is returned, as it
+// is for the other hashes of the Fs.
+func (o *Member) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if !o.f.Hashes().Contains(ht) {
+		return "", hash.ErrUnsupported
+	}
+	if ht != hash.CRC32 {
+		return "", nil
+	}
+	return o.m.crc32, nil
+}
+
+// SetModTime is not supported as archive remotes are read only
+func (o *Member) SetModTime(ctx context.Context, t time.Time) error {
+	return errorReadOnly
+}
+
+// Update is not supported as archive remotes are read only
+func (o *Member) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errorReadOnly
+}
+
+// Remove is not supported as archive remotes are read only
+func (o *Member) Remove(ctx context.Context) error {
+	return errorReadOnly
+}
+
+// Open the file for read. Call Close() on the returned io.ReadCloser
+//
+// Uncompressed files are read with a range request for just the part
+// wanted. Compressed files are read from the start of their data.
+//
+// If the whole of a zip member is read its CRC32 is checked against
+// the one in the archive.
+func (o *Member) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	in, offset, limit, err := o.open(ctx, options...)
+	if err != nil || o.m.zf == nil || offset != 0 || limit != o.m.size {
+		return in, err
+	}
+	return &crcReadCloser{ReadCloser: in, want: o.m.zf.CRC32}, nil
+}
+
+// open the part of the file wanted by options returning the offset
+// and length of it
+func (o *Member) open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, offset, limit int64, err error) {
+	limit = -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.m.size)
+		default:
+			if option.Mandatory() {
+				fs.Logf(o, "Unsupported mandatory option: %v", option)
+			}
+		}
+	}
+	offset = min(offset, o.m.size)
+	if limit < 0 || offset+limit > o.m.size {
+		limit = o.m.size - offset
+	}
+	if limit == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), offset, limit, nil
+	}
+	dataOffset, err := o.idx.dataOffset(o.m)
+	if err != nil {
+		return nil, offset, limit, err
+	}
+
+	method := zip.Store
+	if o.m.zf != nil {
+		method = o.m.zf.Method
+	}
+	switch method {
+	case zip.Store:
+		start := dataOffset + offset
+		in, err = o.idx.o.Open(ctx, &fs.RangeOption{Start: start, End: start + limit - 1})
+		return in, offset, limit, err
+	case zip.Deflate:
+		compressedSize := int64(o.m.zf.CompressedSize64)
+		rc, err := o.idx.o.Open(ctx, &fs.RangeOption{Start: dataOffset, End: dataOffset + compressedSize - 1})
+		if err != nil {
+			return nil, offset, limit, err
+		}
+		fr := flate.NewReader(rc)
+		if _, err = io.CopyN(io.Discard, fr, offset); err != nil {
+			_ = fr.Close()
+			_ = rc.Close()
+			return nil, offset, limit, fmt.Errorf("failed to seek in compressed file: %w", err)
+		}
+		return readers.NewLimitedReadCloser(&flateReadCloser{ReadCloser: fr, rc: rc}, limit), offset, limit, nil
+	}
+	return nil, offset, limit, fmt.Errorf("unsupported zip compression method %d", method)
+}
+
+// crcReadCloser checks the CRC32 of the data read against the one in
+// the archive when it gets to the end
+type crcReadCloser struct {
+	io.ReadCloser
+	sum  uint32 // CRC32 of the data read so far
+	want uint32 // CRC32 from the archive
+}
+
+// Read the data, returning an error instead of io.EOF at the end if
+// the CRC32 doesn't match
+func (r *crcReadCloser) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	r.sum = crc32.Update(r.sum, crc32.IEEETable, p[:n])
+	if err == io.EOF && r.sum != r.want {
+		err = fmt.Errorf("corrupted file: CRC32 is %08x but archive says %08x", r.sum, r.want)
+	}
+	return n, err
+}
+
+// flateReadCloser closes the underlying stream as well as the
+// decompressor
+type flateReadCloser struct {
+	io.ReadCloser
+	rc io.ReadCloser
+}
+
+// Close the decompressor and the underlying stream
+func (r *flateReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	if rcErr := r.rc.Close(); err == nil {
+		err = rcErr
+	}
+	return err
+}
+
+// Check the interfaces are satisfied
+var _ fs.Object = (*Member)(nil)
@@ -579,6 +579,7 @@ type Object struct {
 	accessTier blob.AccessTier   // Blob Access Tier
 	meta       map[string]string // blob metadata - take metadataMu when accessing
 	tags       map[string]string // blob tags
+	versionID  string            // set if this is an old version of the blob
 }
 
 // ------------------------------------------------------------
@@ -1187,7 +1188,10 @@ type listFn func(remote string, object *container.BlobItem, isDirectory bool) er
 //
 // The remote has prefix removed from it and if addContainer is set then
 // it adds the container to the start.
-func (f *Fs) list(ctx context.Context, containerName, directory, prefix string, addContainer bool, recurse bool, maxResults int32, fn listFn) error {
+//
+// If versionAt is set then the blobs are listed as they were at that
+// time using the blob versions.
+func (f *Fs) list(ctx context.Context, containerName, directory, prefix string, addContainer bool, recurse bool, maxResults int32, versionAt time.Time, fn listFn) error {
 	if f.cache.IsDeleted(containerName) {
 		return fs.ErrorDirNotFound
 	}
@@ -1210,14 +1214,49 @@ func (f *Fs) list(ctx context.Context, containerName, directory, prefix string,
 			Snapshots:        false,
 			UncommittedBlobs: false,
 			Deleted:          false,
+			Versions:         !versionAt.IsZero(),
 		},
 		Prefix:     &directory,
 		MaxResults: &maxResults,
 	})
 	foundItems := 0
+	// sendItem sends a blob to fn
+	sendItem := func(file *container.BlobItem) error {
+		remote := f.opt.Enc.ToStandardPath(*file.Name)
+		if !strings.HasPrefix(remote, prefix) {
+			fs.Debugf(f, "Odd name received %q", remote)
+			return nil
+		}
+		isDirectory := isDirectoryMarker(*file.Properties.ContentLength, file.Metadata, remote)
+		if isDirectory {
+			// Don't insert the root directory
+			if remote == f.opt.Enc.ToStandardPath(directory) {
+				return nil
+			}
+			// process directory markers as directories
+			remote, _ = strings.CutSuffix(remote, "/")
+		}
+		remote = remote[len(prefix):]
+		if addContainer {
+			remote = path.Join(containerName, remote)
+		}
+		return fn(remote, file, isDirectory)
+	}
+	// When listing at a time the versions of each blob are
+	// returned together, so the latest version before versionAt
+	// is kept in pending until the name changes.
+	var pending *container.BlobItem
+	flushPending := func() error {
+		if pending == nil {
+			return nil
+		}
+		file := pending
+		pending = nil
+		return sendItem(file)
+	}
 	for pager.More() {
 		var response container.ListBlobsHierarchyResponse
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			var err error
 			response, err = pager.NextPage(ctx)
 			//response, err = f.srv.ListBlobsHierarchySegment(ctx, marker, delimiter, options)
@@ -1244,26 +1283,23 @@ func (f *Fs) list(ctx context.Context, containerName, directory, prefix string,
 				fs.Debugf(f, "Nil name received")
 				continue
 			}
-			remote := f.opt.Enc.ToStandardPath(*file.Name)
-			if !strings.HasPrefix(remote, prefix) {
-				fs.Debugf(f, "Odd name received %q", remote)
-				continue
-			}
-			isDirectory := isDirectoryMarker(*file.Properties.ContentLength, file.Metadata, remote)
-			if isDirectory {
-				// Don't insert the root directory
-				if remote == f.opt.Enc.ToStandardPath(directory) {
+			if !versionAt.IsZero() {
+				if !versionBefore(file, versionAt) {
 					continue
 				}
-				// process directory markers as directories
-				remote, _ = strings.CutSuffix(remote, "/")
-			}
-			remote = remote[len(prefix):]
-			if addContainer {
-				remote = path.Join(containerName, remote)
+				if pending != nil && *pending.Name != *file.Name {
+					err = flushPending()
+					if err != nil {
+						return err
+					}
+				}
+				if pending == nil || versionNewer(file, pending) {
+					pending = file
+				}
+				continue
 			}
 			// Send object
-			err = fn(remote, file, isDirectory)
+			err = sendItem(file)
 			if err != nil {
 				return err
 			}
@@ -1296,6 +1332,10 @@ func (f *Fs) list(ctx context.Context, containerName, directory, prefix string,
 			}
 		}
 	}
+	err := flushPending()
+	if err != nil {
+		return err
+	}
 	if f.opt.DirectoryMarkers && foundItems == 0 && directory != "" {
 		// Determine whether the directory exists or not by whether it has a marker
 		_, err := f.readMetaData(ctx, containerName, directory)
@@ -1309,6 +1349,34 @@ func (f *Fs) list(ctx context.Context, containerName, directory, prefix string,
 	return nil
 }
 
+// versionTime returns the time the version of the blob was created
+//
+// Version IDs are the creation time of the version. Blobs without a
+// version ID were written before versioning was enabled so the last
+// modified time is used.
+func versionTime(file *container.BlobItem) time.Time {
+	if file.VersionID != nil {
+		t, err := time.Parse(time.RFC3339Nano, *file.VersionID)
+		if err == nil {
+			return t
+		}
+	}
+	if file.Properties != nil && file.Properties.LastModified != nil {
+		return *file.Properties.LastModified
+	}
+	return time.Time{}
+}
+
+// versionBefore returns true if the version of the blob existed at t
+func versionBefore(file *container.BlobItem, t time.Time) bool {
+	return !versionTime(file).After(t)
+}
+
+// versionNewer returns true if the version of a is newer than b
+func versionNewer(a, b *container.BlobItem) bool {
+	return versionTime(a).After(versionTime(b))
+}
+
 // Convert a list item into a DirEntry
 func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *container.BlobItem, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
@@ -1342,7 +1410,7 @@ func (f *Fs) listDir(ctx context.Context, containerName, directory, prefix strin
 	if !f.containerOK(containerName) {
 		return fs.ErrorDirNotFound
 	}
-	err = f.list(ctx, containerName, directory, prefix, addContainer, false, int32(f.opt.ListChunkSize), func(remote string, object *container.BlobItem, isDirectory bool) error {
+	err = f.list(ctx, containerName, directory, prefix, addContainer, false, int32(f.opt.ListChunkSize), time.Time{}, func(remote string, object *container.BlobItem, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
@@ -1436,6 +1504,38 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 	return list.Flush()
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t using the blob versions.
+//
+// Blobs which have been deleted can't be told apart from blobs which
+// existed at t, so the last version of a deleted blob is listed.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	containerName, directory := f.split(dir)
+	if containerName == "" {
+		if directory != "" {
+			return nil, fs.ErrorListBucketRequired
+		}
+		return f.listContainers(ctx)
+	}
+	if !f.containerOK(containerName) {
+		return nil, fs.ErrorDirNotFound
+	}
+	err = f.list(ctx, containerName, directory, f.rootDirectory, f.rootContainer == "", false, int32(f.opt.ListChunkSize), t, func(remote string, object *container.BlobItem, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // ListR lists the objects and directories of the Fs starting
 // from dir recursively into out.
 //
@@ -1456,7 +1556,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	containerName, directory := f.split(dir)
 	list := list.NewHelper(callback)
 	listR := func(containerName, directory, prefix string, addContainer bool) error {
-		return f.list(ctx, containerName, directory, prefix, addContainer, true, int32(f.opt.ListChunkSize), func(remote string, object *container.BlobItem, isDirectory bool) error {
+		return f.list(ctx, containerName, directory, prefix, addContainer, true, int32(f.opt.ListChunkSize), time.Time{}, func(remote string, object *container.BlobItem, isDirectory bool) error {
 			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 			if err != nil {
 				return err
@@ -1509,7 +1609,7 @@ func (f *Fs) listContainersToFn(fn listContainerFn) error {
 	ctx := context.Background()
 	for pager.More() {
 		var response service.ListContainersResponse
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			var err error
 			response, err = pager.NextPage(ctx)
 			return f.shouldRetry(ctx, err)
@@ -1640,7 +1740,7 @@ func (f *Fs) makeContainer(ctx context.Context, container string) error {
 			opt.Access = &f.publicAccess
 		}
 		// now try to create the container
-		return f.pacer.Call(func() (bool, error) {
+		return f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.svc.CreateContainer(ctx, container, &opt)
 			if err != nil {
 				if storageErr, ok := err.(*azcore.ResponseError); ok {
@@ -1671,7 +1771,7 @@ func (f *Fs) makeContainer(ctx context.Context, container string) error {
 // isEmpty checks to see if a given (container, directory) is empty and returns an error if not
 func (f *Fs) isEmpty(ctx context.Context, containerName, directory string) (err error) {
 	empty := true
-	err = f.list(ctx, containerName, directory, f.rootDirectory, f.rootContainer == "", true, 1, func(remote string, object *container.BlobItem, isDirectory bool) error {
+	err = f.list(ctx, containerName, directory, f.rootDirectory, f.rootContainer == "", true, 1, time.Time{}, func(remote string, object *container.BlobItem, isDirectory bool) error {
 		empty = false
 		return nil
 	})
@@ -1690,7 +1790,7 @@ func (f *Fs) deleteContainer(ctx context.Context, containerName string) error {
 	return f.cache.Remove(containerName, func() error {
 		getOptions := container.GetPropertiesOptions{}
 		delOptions := container.DeleteOptions{}
-		return f.pacer.Call(func() (bool, error) {
+		return f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.cntSVC(containerName).GetProperties(ctx, &getOptions)
 			if err == nil {
 				_, err = f.cntSVC(containerName).Delete(ctx, &delOptions)
@@ -1927,7 +2027,7 @@ func (f *Fs) copyMultipart(ctx context.Context, remote, dstContainer, dstPath st
 				options.Range.Count = remaining
 			}
 			fs.Debugf(o, "multipart copy: starting chunk %d size %v offset %v/%v", partNum, fs.SizeSuffix(options.Range.Count), fs.SizeSuffix(options.Range.Offset), fs.SizeSuffix(srcSize))
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				checker.start()
 				_, err := dstBlockBlobSVC.StageBlockFromURL(ctx, blockID, srcURL, &options)
 				checker.stop()
@@ -1966,7 +2066,7 @@ func (f *Fs) copyMultipart(ctx context.Context, remote, dstContainer, dstPath st
 	}
 
 	// Finalise the upload session
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := dstBlockBlobSVC.CommitBlockList(ctx, blockIDs, &options)
 		return f.shouldRetry(ctx, err)
 	})
@@ -1998,7 +2098,7 @@ func (f *Fs) copySinglepart(ctx context.Context, remote, dstContainer, dstPath s
 		Tier: parseTier(f.opt.AccessTier),
 	}
 	var startCopy blob.StartCopyFromURLResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		startCopy, err = dstBlobSVC.StartCopyFromURL(ctx, srcURL, &options)
 		return f.shouldRetry(ctx, err)
 	})
@@ -2015,7 +2115,7 @@ func (f *Fs) copySinglepart(ctx context.Context, remote, dstContainer, dstPath s
 	for copyStatus != nil && string(*copyStatus) == string(container.CopyStatusTypePending) {
 		time.Sleep(pollTime)
 		var getMetadata blob.GetPropertiesResponse
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			getMetadata, err = dstBlobSVC.GetProperties(ctx, &getOptions)
 			return f.shouldRetry(ctx, err)
 		})
@@ -2286,6 +2386,9 @@ func (o *Object) decodeMetaDataFromBlob(info *container.BlobItem) (err error) {
 	} else {
 		o.accessTier = *info.Properties.AccessTier
 	}
+	if info.VersionID != nil && (info.IsCurrentVersion == nil || !*info.IsCurrentVersion) {
+		o.versionID = *info.VersionID
+	}
 	o.setMetadata(metadata)
 
 	return nil
@@ -2300,9 +2403,21 @@ func (o *Object) getTags() (tags map[string]string) {
 }
 
 // getBlobSVC creates a blob client
+//
+// If the Object is an old version of the blob then the client refers
+// to that version.
 func (o *Object) getBlobSVC() *blob.Client {
 	container, directory := o.split()
-	return o.fs.getBlobSVC(container, directory)
+	blb := o.fs.getBlobSVC(container, directory)
+	if o.versionID == "" {
+		return blb
+	}
+	versionBlb, err := blb.WithVersionID(o.versionID)
+	if err != nil {
+		fs.Errorf(o, "Failed to read version %q - using current version: %v", o.versionID, err)
+		return blb
+	}
+	return versionBlb
 }
 
 // getBlockBlobSVC creates a block blob client
@@ -2326,7 +2441,7 @@ func (f *Fs) readMetaData(ctx context.Context, container, containerPath string)
 	// Read metadata (this includes metadata)
 	options := blob.GetPropertiesOptions{}
 	var resp blob.GetPropertiesResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = blb.GetProperties(ctx, &options)
 		return f.shouldRetry(ctx, err)
 	})
@@ -2393,7 +2508,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 
 	blb := o.getBlobSVC()
 	opt := blob.SetMetadataOptions{}
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := blb.SetMetadata(ctx, o.getMetadata(), &opt)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -2451,7 +2566,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		// CpkScopeInfo     *CpkScopeInfo
 	}
 	var downloadResponse blob.DownloadStreamResponse
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		downloadResponse, err = blb.DownloadStream(ctx, &opt)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -2725,15 +2840,7 @@ func (w *azChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, reader
 	// Create a new blockID
 	blockID := w.bic.newBlockID(uint64(chunkNumber))
 
-	// Save the blockID for the commit
-	w.blocksMu.Lock()
-	w.blocks = append(w.blocks, azBlock{
-		chunkNumber: uint64(chunkNumber),
-		id:          blockID,
-	})
-	w.blocksMu.Unlock()
-
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		// rewind the reader on retry and after reading md5
 		_, err = reader.Seek(0, io.SeekStart)
 		if err != nil {
@@ -2765,10 +2872,85 @@ func (w *azChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, reader
 		return -1, fmt.Errorf("failed to upload chunk %d with %v bytes: %w", chunkNumber+1, currentChunkSize, err)
 	}
 
+	// Save the blockID for the commit
+	w.addBlock(uint64(chunkNumber), blockID)
+
 	fs.Debugf(w.o, "multipart upload wrote chunk %d with %v bytes", chunkNumber+1, currentChunkSize)
 	return currentChunkSize, err
 }
 
+// addBlock records a block which has been staged
+func (w *azChunkWriter) addBlock(chunkNumber uint64, blockID string) {
+	w.blocksMu.Lock()
+	defer w.blocksMu.Unlock()
+	w.blocks = append(w.blocks, azBlock{
+		chunkNumber: chunkNumber,
+		id:          blockID,
+	})
+}
+
+// State returns the state of the multipart upload so far
+//
+// The upload ID is the random part of the block IDs.
+func (w *azChunkWriter) State() fs.ChunkWriterState {
+	w.blocksMu.Lock()
+	defer w.blocksMu.Unlock()
+	state := fs.ChunkWriterState{
+		UploadID:  hex.EncodeToString(w.bic.random[:]),
+		ChunkSize: w.chunkSize,
+		Parts:     make(map[int]string, len(w.blocks)),
+	}
+	for _, block := range w.blocks {
+		state.Parts[int(block.chunkNumber)] = block.id
+	}
+	return state
+}
+
+// ResumeChunkWriter resumes the multipart upload described by state
+//
+// It checks the blocks in state are still staged. Uncommitted blocks
+// are removed by Azure after 7 days.
+func (f *Fs) ResumeChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, state fs.ChunkWriterState, options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	random, err := hex.DecodeString(state.UploadID)
+	if err != nil || len(random) != len(blockIDCreator{}.random) {
+		return info, nil, fmt.Errorf("invalid upload ID %q", state.UploadID)
+	}
+	info, writer, err = f.OpenChunkWriter(ctx, remote, src, options...)
+	if err != nil {
+		return info, nil, err
+	}
+	w := writer.(*azChunkWriter)
+	copy(w.bic.random[:], random)
+
+	// Read the staged blocks
+	var blockList blockblob.GetBlockListResponse
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
+		blockList, err = w.ui.blb.GetBlockList(ctx, blockblob.BlockListTypeUncommitted, nil)
+		return f.shouldRetry(ctx, err)
+	})
+	if err != nil {
+		return info, nil, fmt.Errorf("failed to read uncommitted block list: %w", err)
+	}
+	staged := make(map[string]struct{}, len(blockList.UncommittedBlocks))
+	for _, block := range blockList.UncommittedBlocks {
+		if block.Name != nil {
+			staged[*block.Name] = struct{}{}
+		}
+	}
+	for chunkNumber, blockID := range state.Parts {
+		if _, found := staged[blockID]; !found {
+			return info, nil, fmt.Errorf("block for chunk %d is no longer staged", chunkNumber+1)
+		}
+		err = w.bic.checkID(uint64(chunkNumber), blockID)
+		if err != nil {
+			return info, nil, err
+		}
+		w.addBlock(uint64(chunkNumber), blockID)
+	}
+	fs.Debugf(w.o, "open chunk writer: resumed multipart upload with %d blocks", len(state.Parts))
+	return info, w, nil
+}
+
 // Clear uncommitted blocks
 //
 // There isn't an API to clear uncommitted blocks.
@@ -2808,7 +2990,7 @@ func (o *Object) clearUncommittedBlocks(ctx context.Context) (err error) {
 
 	if objectExists {
 		// Get the committed block list
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			blockList, err = blockBlobSVC.GetBlockList(ctx, blockblob.BlockListTypeAll, nil)
 			return o.fs.shouldRetry(ctx, err)
 		})
@@ -2850,7 +3032,7 @@ func (o *Object) clearUncommittedBlocks(ctx context.Context) (err error) {
 
 	// Commit only the committed blocks
 	fs.Debugf(o, "Committing %d blocks to remove uncommitted blocks", len(blockIDs))
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := blockBlobSVC.CommitBlockList(ctx, blockIDs, options)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -2902,7 +3084,7 @@ func (w *azChunkWriter) Close(ctx context.Context) (err error) {
 	}
 
 	// Finalise the upload session
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := w.ui.blb.CommitBlockList(ctx, blockIDs, &options)
 		return w.f.shouldRetry(ctx, err)
 	})
@@ -2957,7 +3139,7 @@ func (o *Object) uploadSinglepart(ctx context.Context, in io.Reader, size int64,
 		HTTPHeaders: &ui.httpHeaders,
 	}
 
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		// rewind the reader on retry
 		_, err = rs.Seek(0, io.SeekStart)
 		if err != nil {
@@ -3116,7 +3298,7 @@ func (o *Object) Remove(ctx context.Context) error {
 		action := blob.DeleteSnapshotsOptionType(o.fs.opt.DeleteSnapshots)
 		opt.DeleteSnapshots = &action
 	}
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := blb.Delete(ctx, &opt)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -3149,7 +3331,7 @@ func (o *Object) SetTier(tier string) error {
 	opt := blob.SetTierOptions{
 		RehydratePriority: &priority,
 	}
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := blb.SetTier(ctx, desiredAccessTier, &opt)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -3181,15 +3363,18 @@ func parseTier(tier string) *blob.AccessTier {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs              = &Fs{}
-	_ fs.Copier          = &Fs{}
-	_ fs.PutStreamer     = &Fs{}
-	_ fs.Purger          = &Fs{}
-	_ fs.ListRer         = &Fs{}
-	_ fs.ListPer         = &Fs{}
-	_ fs.OpenChunkWriter = &Fs{}
-	_ fs.Object          = &Object{}
-	_ fs.MimeTyper       = &Object{}
-	_ fs.GetTierer       = &Object{}
-	_ fs.SetTierer       = &Object{}
+	_ fs.Fs                = &Fs{}
+	_ fs.Copier            = &Fs{}
+	_ fs.PutStreamer       = &Fs{}
+	_ fs.Purger            = &Fs{}
+	_ fs.ListRer           = &Fs{}
+	_ fs.ListPer           = &Fs{}
+	_ fs.ListAter          = &Fs{}
+	_ fs.ResumeChunkWriter = &Fs{}
+	_ fs.ChunkWriterStater = &azChunkWriter{}
+	_ fs.OpenChunkWriter   = &Fs{}
+	_ fs.Object            = &Object{}
+	_ fs.MimeTyper         = &Object{}
+	_ fs.GetTierer         = &Object{}
+	_ fs.SetTierer         = &Object{}
 )
@@ -323,6 +323,19 @@ type CancelLargeFileResponse struct {
 	BucketID  string `json:"bucketId"`  // The unique ID of the bucket.
 }
 
+// ListPartsRequest is passed to b2_list_parts
+type ListPartsRequest struct {
+	ID              string `json:"fileId"`                    // The unique identifier of the file being uploaded.
+	StartPartNumber int64  `json:"startPartNumber,omitempty"` // The first part to return.
+	MaxPartCount    int    `json:"maxPartCount,omitempty"`    // The maximum number of parts to return from this call.
+}
+
+// ListPartsResponse is the response to b2_list_parts
+type ListPartsResponse struct {
+	Parts          []UploadPartResponse `json:"parts"`          // The parts uploaded so far.
+	NextPartNumber *int64               `json:"nextPartNumber"` // What to pass in to startPartNumber for the next search to continue where this one ended.
+}
+
 // CopyFileRequest is as passed to b2_copy_file
 type CopyFileRequest struct {
 	SourceID          string            `json:"sourceFileId"`                  // The ID of the source file being copied.
@@ -25,6 +25,7 @@ import (
 	"github.com/rclone/rclone/backend/b2/api"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/chunksize"
 	"github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/config/configstruct"
@@ -577,7 +578,7 @@ func (f *Fs) authorizeAccount(ctx context.Context) error {
 		Password:     f.opt.Key,
 		ExtraHeaders: map[string]string{"Authorization": ""}, // unset the Authorization for this request
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, &f.info)
 		return f.shouldRetryNoReauth(ctx, resp, err)
 	})
@@ -618,7 +619,7 @@ func (f *Fs) getUploadURL(ctx context.Context, bucket string) (upload *api.GetUp
 	var request = api.GetUploadURLRequest{
 		BucketID: bucketID,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &upload)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -720,7 +721,10 @@ var errEndList = errors.New("end list")
 // If hidden is set then it will list the hidden (deleted) files too.
 //
 // if findFile is set it will look for files called (bucket, directory)
-func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, limit int, hidden bool, findFile bool, fn listFn) error {
+//
+// If versionAt is set then the files are listed as they were at that
+// time.
+func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, limit int, hidden bool, findFile bool, versionAt fs.Time, fn listFn) error {
 	if !findFile {
 		if prefix != "" {
 			prefix += "/"
@@ -754,7 +758,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 		Method: "POST",
 		Path:   "/b2_list_file_names",
 	}
-	if hidden || f.opt.VersionAt.IsSet() {
+	if hidden || versionAt.IsSet() {
 		opts.Path = "/b2_list_file_versions"
 	}
 
@@ -762,7 +766,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 
 	for {
 		var response api.ListFileNamesResponse
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -790,8 +794,8 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 				remote = path.Join(bucket, remote)
 			}
 
-			if f.opt.VersionAt.IsSet() {
-				if time.Time(file.UploadTimestamp).After(time.Time(f.opt.VersionAt)) {
+			if versionAt.IsSet() {
+				if time.Time(file.UploadTimestamp).After(time.Time(versionAt)) {
 					// Ignore versions that were created after the specified time
 					continue
 				}
@@ -847,9 +851,11 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *api.File
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, callback func(fs.DirEntry) error) (err error) {
+//
+// If versionAt is set the files are listed as they were at that time.
+func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, versionAt fs.Time, callback func(fs.DirEntry) error) (err error) {
 	last := ""
-	err = f.list(ctx, bucket, directory, prefix, f.rootBucket == "", false, 0, f.opt.Versions, false, func(remote string, object *api.File, isDirectory bool) error {
+	err = f.list(ctx, bucket, directory, prefix, f.rootBucket == "", false, 0, f.opt.Versions, false, versionAt, func(remote string, object *api.File, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory, &last)
 		if err != nil {
 			return err
@@ -924,7 +930,7 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 			}
 		}
 	} else {
-		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", list.Add)
+		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", f.opt.VersionAt, list.Add)
 		if err != nil {
 			return err
 		}
@@ -953,7 +959,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	list := list.NewHelper(callback)
 	listR := func(bucket, directory, prefix string, addBucket bool) error {
 		last := ""
-		return f.list(ctx, bucket, directory, prefix, addBucket, true, 0, f.opt.Versions, false, func(remote string, object *api.File, isDirectory bool) error {
+		return f.list(ctx, bucket, directory, prefix, addBucket, true, 0, f.opt.Versions, false, f.opt.VersionAt, func(remote string, object *api.File, isDirectory bool) error {
 			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory, &last)
 			if err != nil {
 				return err
@@ -990,6 +996,44 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	return list.Flush()
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t using the old versions of the files.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		if directory != "" {
+			return nil, fs.ErrorListBucketRequired
+		}
+		return f.listBuckets(ctx)
+	}
+	err = f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", fs.Time(t), func(entry fs.DirEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NewObjectAt finds the Object at remote as it was at time t.  If it
+// can't be found it returns the error fs.ErrorObjectNotFound.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	o := &Object{
+		fs:     f,
+		remote: remote,
+	}
+	info, err := o.getMetaDataListing(ctx, fs.Time(t))
+	if err != nil {
+		return nil, err
+	}
+	if info.Action == "hide" {
+		// The file was deleted at that time
+		return nil, fs.ErrorObjectNotFound
+	}
+	return f.newObjectWithInfo(ctx, remote, info)
+}
+
 // listBucketFn is called from listBucketsToFn to handle a bucket
 type listBucketFn func(*api.Bucket) error
 
@@ -1008,7 +1052,7 @@ func (f *Fs) listBucketsToFn(ctx context.Context, bucketName string, fn listBuck
 		Method: "POST",
 		Path:   "/b2_list_buckets",
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &account, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1152,7 +1196,7 @@ func (f *Fs) makeBucket(ctx context.Context, bucket string) error {
 			}}
 		}
 		var response api.Bucket
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -1201,7 +1245,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 			AccountID: f.info.AccountID,
 		}
 		var response api.Bucket
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -1235,7 +1279,7 @@ func (f *Fs) hide(ctx context.Context, bucket, bucketPath string) error {
 		Name:     f.opt.Enc.FromStandardPath(bucketPath),
 	}
 	var response api.File
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1263,7 +1307,7 @@ func (f *Fs) deleteByID(ctx context.Context, ID, Name string) error {
 		Name: f.opt.Enc.FromStandardPath(Name),
 	}
 	var response api.File
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1335,7 +1379,7 @@ func (f *Fs) purge(ctx context.Context, dir string, oldOnly bool, deleteHidden b
 	}
 
 	last := ""
-	checkErr(f.list(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", true, 0, true, false, func(remote string, object *api.File, isDirectory bool) error {
+	checkErr(f.list(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", true, 0, true, false, f.opt.VersionAt, func(remote string, object *api.File, isDirectory bool) error {
 		if !isDirectory {
 			oi, err := f.newObjectWithInfo(ctx, object.Name, object)
 			if err != nil {
@@ -1443,7 +1487,7 @@ func (f *Fs) copy(ctx context.Context, dstObj *Object, srcObj *Object, newInfo *
 		request.Info = newInfo.Info
 	}
 	var response api.FileInfo
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1509,7 +1553,7 @@ func (f *Fs) getDownloadAuthorization(ctx context.Context, bucket, remote string
 		ValidDurationInSeconds: validDurationInSeconds,
 	}
 	var response api.GetDownloadAuthorizationResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1530,7 +1574,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	}
 	_, err = f.NewObject(ctx, remote)
 	if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
-		err2 := f.list(ctx, bucket, bucketPath, f.rootDirectory, f.rootBucket == "", false, 1, f.opt.Versions, false, func(remote string, object *api.File, isDirectory bool) error {
+		err2 := f.list(ctx, bucket, bucketPath, f.rootDirectory, f.rootBucket == "", false, 1, f.opt.Versions, false, f.opt.VersionAt, func(remote string, object *api.File, isDirectory bool) error {
 			err = nil
 			return nil
 		})
@@ -1663,16 +1707,20 @@ func (o *Object) decodeMetaDataFileInfo(info *api.FileInfo) (err error) {
 //
 // Note that listing is a class C transaction which costs more than
 // the B transaction used in getMetaData
-func (o *Object) getMetaDataListing(ctx context.Context) (info *api.File, err error) {
+//
+// If versionAt is set it finds the version current at that time.
+func (o *Object) getMetaDataListing(ctx context.Context, versionAt fs.Time) (info *api.File, err error) {
 	bucket, bucketPath := o.split()
 	maxSearched := 1
 	var timestamp api.Timestamp
-	if o.fs.opt.Versions {
+	if versionAt.IsSet() {
+		maxSearched = maxVersions
+	} else if o.fs.opt.Versions {
 		timestamp, bucketPath = api.RemoveVersion(bucketPath)
 		maxSearched = maxVersions
 	}
 
-	err = o.fs.list(ctx, bucket, bucketPath, "", false, true, maxSearched, o.fs.opt.Versions, true, func(remote string, object *api.File, isDirectory bool) error {
+	err = o.fs.list(ctx, bucket, bucketPath, "", false, true, maxSearched, o.fs.opt.Versions, true, versionAt, func(remote string, object *api.File, isDirectory bool) error {
 		if isDirectory {
 			return nil
 		}
@@ -1702,13 +1750,13 @@ func (o *Object) getMetaData(ctx context.Context) (info *api.File, err error) {
 	if o.fs.opt.Versions {
 		timestamp, _ := api.RemoveVersion(o.remote)
 		if !timestamp.IsZero() {
-			return o.getMetaDataListing(ctx)
+			return o.getMetaDataListing(ctx, o.fs.opt.VersionAt)
 		}
 	}
 
 	// If using versionAt we need to list the find the correct version.
 	if o.fs.opt.VersionAt.IsSet() {
-		info, err := o.getMetaDataListing(ctx)
+		info, err := o.getMetaDataListing(ctx, o.fs.opt.VersionAt)
 		if err != nil {
 			return nil, err
 		}
@@ -1886,7 +1934,7 @@ func (o *Object) getOrHead(ctx context.Context, method string, options []fs.Open
 		bucket, bucketPath := o.split()
 		opts.Path += "/file/" + urlEncode(o.fs.opt.Enc.FromStandardName(bucket)) + "/" + urlEncode(o.fs.opt.Enc.FromStandardPath(bucketPath))
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -2152,7 +2200,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 	var response api.FileInfo
 	// Don't retry, return a retry error instead
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, nil, &response)
 		retry, err := o.fs.shouldRetry(ctx, resp, err)
 		// On retryable error clear UploadURL
@@ -2237,6 +2285,41 @@ func (f *Fs) OpenChunkWriter(ctx context.Context, remote string, src fs.ObjectIn
 	return info, up, nil
 }
 
+// ResumeChunkWriter resumes the large file upload described by state
+func (f *Fs) ResumeChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, state fs.ChunkWriterState, options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	if f.opt.Versions {
+		return info, nil, errNotWithVersions
+	}
+	if f.opt.VersionAt.IsSet() {
+		return info, nil, errNotWithVersionAt
+	}
+	o := &Object{
+		fs:     f,
+		remote: remote,
+	}
+	size := src.Size()
+	chunkSize := int64(chunksize.Calculator(o, size, maxParts, f.opt.ChunkSize))
+	up := &largeUpload{
+		f:         f,
+		o:         o,
+		what:      "upload",
+		size:      size,
+		parts:     int((size + chunkSize - 1) / chunkSize),
+		chunkSize: chunkSize,
+		sha1s:     make([]string, 0, 16),
+	}
+	up.in, up.wrap = accounting.UnWrap(nil)
+	err = up.resume(ctx, state)
+	if err != nil {
+		return info, nil, err
+	}
+	info = fs.ChunkWriterInfo{
+		ChunkSize:   up.chunkSize,
+		Concurrency: o.fs.opt.UploadConcurrency,
+	}
+	return info, up, nil
+}
+
 // Remove an object
 func (o *Object) Remove(ctx context.Context) error {
 	bucket, bucketPath := o.split()
@@ -2359,7 +2442,7 @@ func (f *Fs) lifecycleCommand(ctx context.Context, name string, arg []string, op
 			LifecycleRules: []api.LifecycleRule{newRule},
 		}
 		var response api.Bucket
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -2458,17 +2541,21 @@ func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[str
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs              = &Fs{}
-	_ fs.Purger          = &Fs{}
-	_ fs.Copier          = &Fs{}
-	_ fs.PutStreamer     = &Fs{}
-	_ fs.CleanUpper      = &Fs{}
-	_ fs.ListRer         = &Fs{}
-	_ fs.ListPer         = &Fs{}
-	_ fs.PublicLinker    = &Fs{}
-	_ fs.OpenChunkWriter = &Fs{}
-	_ fs.Commander       = &Fs{}
-	_ fs.Object          = &Object{}
-	_ fs.MimeTyper       = &Object{}
-	_ fs.IDer            = &Object{}
+	_ fs.Fs                = &Fs{}
+	_ fs.Purger            = &Fs{}
+	_ fs.Copier            = &Fs{}
+	_ fs.PutStreamer       = &Fs{}
+	_ fs.CleanUpper        = &Fs{}
+	_ fs.ListRer           = &Fs{}
+	_ fs.ListPer           = &Fs{}
+	_ fs.ListAter          = &Fs{}
+	_ fs.NewObjectAter     = &Fs{}
+	_ fs.PublicLinker      = &Fs{}
+	_ fs.OpenChunkWriter   = &Fs{}
+	_ fs.ResumeChunkWriter = &Fs{}
+	_ fs.ChunkWriterStater = &largeUpload{}
+	_ fs.Commander         = &Fs{}
+	_ fs.Object            = &Object{}
+	_ fs.MimeTyper         = &Object{}
+	_ fs.IDer              = &Object{}
 )
@@ -150,7 +150,7 @@ func (f *Fs) newLargeUpload(ctx context.Context, o *Object, in io.Reader, src fs
 		Options: optionsToSend,
 	}
 	var response api.StartLargeFileResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &response)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -198,7 +198,7 @@ func (up *largeUpload) getUploadURL(ctx context.Context) (upload *api.GetUploadP
 	var request = api.GetUploadPartURLRequest{
 		ID: up.id,
 	}
-	err = up.f.pacer.Call(func() (bool, error) {
+	err = up.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := up.f.srv.CallJSON(ctx, &opts, &request, &upload)
 		return up.f.shouldRetry(ctx, resp, err)
 	})
@@ -237,7 +237,7 @@ func (up *largeUpload) WriteChunk(ctx context.Context, chunkNumber int, reader i
 		do.DelayAccounting(1)
 	}
 
-	err = up.f.pacer.Call(func() (bool, error) {
+	err = up.f.pacer.CallContext(ctx, func() (bool, error) {
 		// Discover the size by seeking to the end
 		size, err = reader.Seek(0, io.SeekEnd)
 		if err != nil {
@@ -308,7 +308,9 @@ func (up *largeUpload) WriteChunk(ctx context.Context, chunkNumber int, reader i
 			upload = nil
 		}
 		up.returnUploadURL(upload)
-		up.addSha1(chunkNumber, in.HexSum())
+		if err == nil {
+			up.addSha1(chunkNumber, in.HexSum())
+		}
 		return retry, err
 	})
 	if err != nil {
@@ -321,7 +323,7 @@ func (up *largeUpload) WriteChunk(ctx context.Context, chunkNumber int, reader i
 
 // Copy a chunk
 func (up *largeUpload) copyChunk(ctx context.Context, part int, partSize int64) error {
-	err := up.f.pacer.Call(func() (bool, error) {
+	err := up.f.pacer.CallContext(ctx, func() (bool, error) {
 		fs.Debugf(up.o, "Copying chunk %d length %d", part, partSize)
 		opts := rest.Opts{
 			Method: "POST",
@@ -351,6 +353,64 @@ func (up *largeUpload) copyChunk(ctx context.Context, part int, partSize int64)
 	return err
 }
 
+// State returns the state of the large upload so far
+func (up *largeUpload) State() fs.ChunkWriterState {
+	up.sha1smu.Lock()
+	defer up.sha1smu.Unlock()
+	state := fs.ChunkWriterState{
+		UploadID:  up.id,
+		ChunkSize: up.chunkSize,
+		Parts:     make(map[int]string, len(up.sha1s)),
+	}
+	for chunkNumber, sha1 := range up.sha1s {
+		if sha1 != "" {
+			state.Parts[chunkNumber] = sha1
+		}
+	}
+	return state
+}
+
+// resume carries on the large upload in state using the parts
+// already uploaded
+//
+// It checks the parts in state have been uploaded with the same SHA1.
+func (up *largeUpload) resume(ctx context.Context, state fs.ChunkWriterState) error {
+	opts := rest.Opts{
+		Method: "POST",
+		Path:   "/b2_list_parts",
+	}
+	var request = api.ListPartsRequest{
+		ID:           state.UploadID,
+		MaxPartCount: 1000,
+	}
+	uploaded := make(map[int]string)
+	for {
+		var response api.ListPartsResponse
+		err := up.f.pacer.CallContext(ctx, func() (bool, error) {
+			resp, err := up.f.srv.CallJSON(ctx, &opts, &request, &response)
+			return up.f.shouldRetry(ctx, resp, err)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list parts of large file: %w", err)
+		}
+		for _, part := range response.Parts {
+			uploaded[int(part.PartNumber)-1] = part.SHA1
+		}
+		if response.NextPartNumber == nil {
+			break
+		}
+		request.StartPartNumber = *response.NextPartNumber
+	}
+	up.id = state.UploadID
+	for chunkNumber, sha1 := range state.Parts {
+		if uploaded[chunkNumber] != sha1 {
+			return fmt.Errorf("part %d of large file is missing or has changed", chunkNumber+1)
+		}
+		up.addSha1(chunkNumber, sha1)
+	}
+	return nil
+}
+
 // Close closes off the large upload
 func (up *largeUpload) Close(ctx context.Context) error {
 	fs.Debugf(up.o, "Finishing large file %s with %d parts", up.what, up.parts)
@@ -363,7 +423,7 @@ func (up *largeUpload) Close(ctx context.Context) error {
 		SHA1s: up.sha1s,
 	}
 	var response api.FileInfo
-	err := up.f.pacer.Call(func() (bool, error) {
+	err := up.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := up.f.srv.CallJSON(ctx, &opts, &request, &response)
 		return up.f.shouldRetry(ctx, resp, err)
 	})
@@ -385,7 +445,7 @@ func (up *largeUpload) Abort(ctx context.Context) error {
 		ID: up.id,
 	}
 	var response api.CancelLargeFileResponse
-	err := up.f.pacer.Call(func() (bool, error) {
+	err := up.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := up.f.srv.CallJSON(ctx, &opts, &request, &response)
 		return up.f.shouldRetry(ctx, resp, err)
 	})
@@ -405,7 +405,7 @@ func (f *Fs) readMetaDataForPath(ctx context.Context, path string) (info *api.It
 		Parameters: fieldsValue(),
 	}
 	var item api.Item
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, &item)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -614,7 +614,7 @@ func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (newID string,
 			ID: pathID,
 		},
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -654,7 +654,7 @@ OUTER:
 
 		var result api.FolderItems
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -788,7 +788,7 @@ func (f *Fs) preUploadCheck(ctx context.Context, leaf, directoryID string, size
 	}
 	var result api.PreUploadCheckResponse
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &check, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -881,7 +881,7 @@ func (f *Fs) deleteObject(ctx context.Context, id string) error {
 		Path:       "/files/" + id,
 		NoResponse: true,
 	}
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -908,7 +908,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) error {
 	}
 	opts.Parameters.Set("recursive", strconv.FormatBool(!check))
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1000,7 +1000,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	var resp *http.Response
 	var info *api.Item
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &copyFile, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1038,7 +1038,7 @@ func (f *Fs) move(ctx context.Context, endpoint, id, leaf, directoryID string) (
 		},
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &move, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1056,7 +1056,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 	}
 	var user api.User
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &user)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1169,7 +1169,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	shareLink := api.CreateSharedLink{}
 	var info api.Item
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &shareLink, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1187,7 +1187,7 @@ func (f *Fs) deletePermanently(ctx context.Context, itemType, id string) error {
 	} else {
 		opts.Path = "/folders/" + id + "/trash"
 	}
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1306,7 +1306,7 @@ func (f *Fs) changeNotifyStreamPosition(ctx context.Context) (streamPosition str
 
 	var result api.Events
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1343,33 +1343,12 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	nextStreamPosition = streamPosition
 
 	for {
-		// box only allows a max of 500 events
-		limit := min(f.opt.ListChunk, 500)
-
-		opts := rest.Opts{
-			Method:     "GET",
-			Path:       "/events",
-			Parameters: fieldsValue(),
-		}
-		opts.Parameters.Set("stream_position", nextStreamPosition)
-		opts.Parameters.Set("stream_type", "changes")
-		opts.Parameters.Set("limit", strconv.Itoa(limit))
-
-		var result api.Events
-		var resp *http.Response
-		fs.Debugf(f, "Checking for changes on remote (next_stream_position: %q)", nextStreamPosition)
-		err = f.pacer.Call(func() (bool, error) {
-			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
-			return shouldRetry(ctx, resp, err)
-		})
+		var result *api.Events
+		result, err = f.readEvents(ctx, nextStreamPosition)
 		if err != nil {
 			return "", err
 		}
 
-		if result.ChunkSize != int64(len(result.Entries)) {
-			return "", fmt.Errorf("invalid response to event request, chunk_size (%v) not equal to number of entries (%v)", result.ChunkSize, len(result.Entries))
-		}
-
 		nextStreamPosition = strconv.FormatInt(result.NextStreamPosition, 10)
 		if result.ChunkSize == 0 {
 			return nextStreamPosition, nil
@@ -1494,6 +1473,168 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	}
 }
 
+// readEvents reads the next chunk of changes from streamPosition
+func (f *Fs) readEvents(ctx context.Context, streamPosition string) (*api.Events, error) {
+	// box only allows a max of 500 events
+	limit := min(f.opt.ListChunk, 500)
+
+	opts := rest.Opts{
+		Method:     "GET",
+		Path:       "/events",
+		Parameters: fieldsValue(),
+	}
+	opts.Parameters.Set("stream_position", streamPosition)
+	opts.Parameters.Set("stream_type", "changes")
+	opts.Parameters.Set("limit", strconv.Itoa(limit))
+
+	var result api.Events
+	var resp *http.Response
+	var err error
+	fs.Debugf(f, "Checking for changes on remote (next_stream_position: %q)", streamPosition)
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
+		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
+		return shouldRetry(ctx, resp, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ChunkSize != int64(len(result.Entries)) {
+		return nil, fmt.Errorf("invalid response to event request, chunk_size (%v) not equal to number of entries (%v)", result.ChunkSize, len(result.Entries))
+	}
+	return &result, nil
+}
+
+// ChangesSince calls notifyFunc with each path which has changed since
+// the stream position token was issued and returns the next stream
+// position.
+//
+// Box events don't include the old path of an item which has been
+// moved, renamed or trashed. It is reported if the item has been
+// listed, otherwise the root is reported as changed for trashed items.
+// The old path of moved and renamed items isn't reported, so callers
+// need to find it from the item's ID.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if token == "" {
+		return f.changeNotifyStreamPosition(ctx)
+	}
+	rootID, err := f.dirCache.RootID(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	// paths of the directories found - "" and false if outside the root
+	type dirPath struct {
+		path   string
+		inside bool
+	}
+	dirs := map[string]dirPath{rootID: {"", true}}
+	var findDir func(ID string, depth int) (string, bool, error)
+	findDir = func(ID string, depth int) (string, bool, error) {
+		if p, ok := dirs[ID]; ok {
+			return p.path, p.inside, nil
+		}
+		if p, ok := f.dirCache.GetInv(ID); ok {
+			return p, true, nil
+		}
+		if ID == "0" {
+			// the top of All Files
+			return "", false, nil
+		}
+		if depth > 100 {
+			return "", false, fmt.Errorf("directory %q is nested too deeply", ID)
+		}
+		opts := rest.Opts{
+			Method:     "GET",
+			Path:       "/folders/" + ID,
+			Parameters: url.Values{},
+		}
+		opts.Parameters.Set("fields", "name,parent")
+		var info api.Item
+		var resp *http.Response
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
+			resp, err = f.srv.CallJSON(ctx, &opts, nil, &info)
+			return shouldRetry(ctx, resp, err)
+		})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			dirs[ID] = dirPath{}
+			return "", false, nil
+		} else if err != nil {
+			return "", false, err
+		}
+		var p dirPath
+		parentPath, inside, err := findDir(info.Parent.ID, depth+1)
+		if err != nil {
+			return "", false, err
+		}
+		if inside {
+			p = dirPath{path.Join(parentPath, f.opt.Enc.ToStandardName(info.Name)), true}
+		}
+		dirs[ID] = p
+		return p.path, p.inside, nil
+	}
+	// box can send duplicate Event IDs
+	processedEventIDs := make(map[string]struct{})
+	streamPosition := token
+	for {
+		result, err := f.readEvents(ctx, streamPosition)
+		if err != nil {
+			return "", err
+		}
+		streamPosition = strconv.FormatInt(result.NextStreamPosition, 10)
+		newEventIDs := 0
+		for _, entry := range result.Entries {
+			if entry.EventID == "" {
+				continue
+			}
+			if _, ok := processedEventIDs[entry.EventID]; ok {
+				continue
+			}
+			processedEventIDs[entry.EventID] = struct{}{}
+			newEventIDs++
+			if entry.Source.ID == "" || (entry.Source.Type != api.ItemTypeFile && entry.Source.Type != api.ItemTypeFolder) {
+				continue
+			}
+			if _, found := api.FileTreeChangeEventTypes[entry.EventType]; !found {
+				continue
+			}
+			entryType := fs.EntryDirectory
+			if entry.Source.Type == api.ItemTypeFile {
+				entryType = fs.EntryObject
+			}
+			// the old path if the item has been listed
+			f.itemMetaCacheMu.Lock()
+			itemMeta, cachedItemMetaFound := f.itemMetaCache[entry.Source.ID]
+			f.itemMetaCacheMu.Unlock()
+			oldPath := ""
+			if cachedItemMetaFound {
+				oldPath = f.getFullPath(itemMeta.ParentID, itemMeta.Name)
+				if oldPath != "" {
+					notifyFunc(oldPath, entryType)
+				}
+			}
+			if entry.Source.ItemStatus != api.ItemStatusActive || entry.Source.Parent.ID == "" {
+				if oldPath == "" {
+					fs.Debugf(f, "Can't find path of removed item %q - treating everything as changed", entry.Source.ID)
+					notifyFunc("", fs.EntryDirectory)
+				}
+				continue
+			}
+			parentPath, inside, err := findDir(entry.Source.Parent.ID, 0)
+			if err != nil {
+				return "", err
+			}
+			if inside {
+				notifyFunc(path.Join(parentPath, f.opt.Enc.ToStandardName(entry.Source.Name)), entryType)
+			}
+		}
+		// box can sometimes repeatedly return the same Event IDs
+		// so stop when there aren't any new ones
+		if result.ChunkSize == 0 || newEventIDs == 0 {
+			return streamPosition, nil
+		}
+	}
+}
+
 // DirCacheFlush resets the directory cache - used in testing as an
 // optional interface
 func (f *Fs) DirCacheFlush() {
@@ -1603,7 +1744,7 @@ func (o *Object) setModTime(ctx context.Context, modTime time.Time) (*api.Item,
 		ContentModifiedAt: api.Time(modTime),
 	}
 	var info *api.Item
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, &update, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1636,7 +1777,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:    "/files/" + o.id + "/content",
 		Options: options,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1676,7 +1817,7 @@ func (o *Object) upload(ctx context.Context, in io.Reader, leaf, directoryID str
 	} else {
 		opts.Path = "/files/content"
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, &upload, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1741,6 +1882,7 @@ var (
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
 	_ fs.Object          = (*Object)(nil)
 	_ fs.IDer            = (*Object)(nil)
@@ -42,7 +42,7 @@ func (o *Object) createUploadSession(ctx context.Context, leaf, directoryID stri
 		request.FileName = o.fs.opt.Enc.FromStandardName(leaf)
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, &request, &response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -71,7 +71,7 @@ func (o *Object) uploadPart(ctx context.Context, SessionID string, offset, total
 		},
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		opts.Body = wrap(bytes.NewReader(chunk))
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &response)
 		return shouldRetry(ctx, resp, err)
@@ -106,7 +106,7 @@ func (o *Object) commitUpload(ctx context.Context, SessionID string, parts []api
 	var tries int
 outer:
 	for tries = range maxTries {
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = o.fs.srv.CallJSON(ctx, &opts, &request, nil)
 			if err != nil {
 				return shouldRetry(ctx, resp, err)
@@ -165,7 +165,7 @@ func (o *Object) abortUpload(ctx context.Context, SessionID string) (err error)
 		NoResponse: true,
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
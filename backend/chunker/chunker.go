@@ -1961,7 +1961,25 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	if do == nil {
 		return
 	}
-	wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+	do(ctx, f.wrapNotifyFunc(ctx, notifyFunc), pollIntervalChan)
+}
+
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+//
+// Paths are translated in the same way as ChangeNotify.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	do := f.base.Features().ChangesSince
+	if do == nil {
+		return "", fs.ErrorNotImplemented
+	}
+	return do(ctx, token, f.wrapNotifyFunc(ctx, notifyFunc))
+}
+
+// wrapNotifyFunc returns a notifyFunc which replaces data chunk names
+// by the name of the composite file before calling notifyFunc.
+func (f *Fs) wrapNotifyFunc(ctx context.Context, notifyFunc func(string, fs.EntryType)) func(string, fs.EntryType) {
+	return func(path string, entryType fs.EntryType) {
 		// fs.Debugf(f, "ChangeNotify: path %q entryType %d", path, entryType)
 		if entryType == fs.EntryObject {
 			mainPath, _, _, xactID := f.parseChunkName(path)
@@ -1977,7 +1995,6 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 		}
 		notifyFunc(path, entryType)
 	}
-	do(ctx, wrappedNotifyFunc, pollIntervalChan)
 }
 
 // Shutdown the backend, closing any background tasks and any
@@ -2584,6 +2601,7 @@ var (
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.Wrapper         = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
 	_ fs.ObjectInfo      = (*ObjectInfo)(nil)
 	_ fs.Object          = (*Object)(nil)
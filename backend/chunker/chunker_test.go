@@ -42,11 +42,14 @@ func TestIntegration(t *testing.T) {
 			"PublicLink",
 			"OpenWriterAt",
 			"OpenChunkWriter",
+			"ResumeChunkWriter",
 			"MergeDirs",
 			"DirCacheFlush",
 			"UserInfo",
 			"Disconnect",
 			"ListP",
+			"ListAt",
+			"NewObjectAt",
 		},
 	}
 	if *fstest.RemoteName == "" {
@@ -412,7 +412,7 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	}
 	var results *admin.SearchResult
 	f.WaitEventuallyConsistent()
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		var err1 error
 		results, err1 = f.cld.Admin.Search(ctx, searchParams)
 		if err1 == nil && results.TotalCount != len(results.Assets) {
@@ -688,7 +688,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		opts.ExtraHeaders[key] = value
 	}
 	// Make sure that the asset is fully available
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		if err == nil {
 			cl, clErr := strconv.Atoi(resp.Header.Get("content-length"))
@@ -7,6 +7,7 @@ package combine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -663,6 +664,49 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	}()
 }
 
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+//
+// The token holds the tokens of each upstream, keyed by the
+// directory the upstream is mounted on.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	tokens := map[string]string{}
+	if token != "" {
+		err = json.Unmarshal([]byte(token), &tokens)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", fs.ErrorChangeTokenInvalid, err)
+		}
+	}
+	newTokens := make(map[string]string, len(f.upstreams))
+	for dir, u := range f.upstreams {
+		do := u.f.Features().ChangesSince
+		if do == nil {
+			return "", fs.ErrorNotImplemented
+		}
+		uToken, found := tokens[dir]
+		if token != "" && !found {
+			return "", fmt.Errorf("%w: no token for upstream %q", fs.ErrorChangeTokenInvalid, dir)
+		}
+		wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+			newPath, err := u.pathAdjustment.do(path)
+			if err != nil {
+				fs.Logf(f, "ChangesSince: unable to process %q: %s", path, err)
+				return
+			}
+			notifyFunc(newPath, entryType)
+		}
+		newTokens[dir], err = do(ctx, uToken, wrappedNotifyFunc)
+		if err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(newTokens)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // DirCacheFlush resets the directory cache - used in testing
 // as an optional interface
 func (f *Fs) DirCacheFlush() {
@@ -941,6 +985,47 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return u.newObject(o), nil
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	if f.root == "" && dir == "" {
+		return f.List(ctx, dir)
+	}
+	u, uRemote, err := f.findUpstream(dir)
+	if err != nil {
+		return nil, err
+	}
+	do := u.f.Features().ListAt
+	if do == nil {
+		return nil, fs.ErrorNotImplemented
+	}
+	entries, err = do(ctx, uRemote, t)
+	if err != nil {
+		return nil, err
+	}
+	return u.wrapEntries(ctx, entries)
+}
+
+// NewObjectAt finds the Object at remote as it was at time t.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	u, uRemote, err := f.findUpstream(remote)
+	if err != nil {
+		return nil, err
+	}
+	if uRemote == "" || strings.HasSuffix(uRemote, "/") {
+		return nil, fs.ErrorIsDir
+	}
+	do := u.f.Features().NewObjectAt
+	if do == nil {
+		return nil, fs.ErrorNotImplemented
+	}
+	o, err := do(ctx, uRemote, t)
+	if err != nil {
+		return nil, err
+	}
+	return u.newObject(o), nil
+}
+
 // Precision is the greatest Precision of all upstreams
 func (f *Fs) Precision() time.Duration {
 	var greatestPrecision time.Duration
@@ -1183,6 +1268,9 @@ var (
 	_ fs.DirMover        = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
+	_ fs.ListAter        = (*Fs)(nil)
+	_ fs.NewObjectAter   = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
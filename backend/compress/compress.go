@@ -46,6 +46,7 @@ const (
 	minCompressionRatio = 1.1
 
 	gzFileExt           = ".gz"
+	zstdFileExt         = ".zst"
 	metaFileExt         = ".json"
 	uncompressedFileExt = ".bin"
 )
@@ -54,6 +55,7 @@ const (
 const (
 	Uncompressed = 0
 	Gzip         = 2
+	Zstd         = 3
 )
 
 var nameRegexp = regexp.MustCompile(`^(.+?)\.([A-Za-z0-9-_]{11})$`)
@@ -65,6 +67,9 @@ func init() {
 		{ // Default compression mode options {
 			Value: "gzip",
 			Help:  "Standard gzip compression with fastest parameters.",
+		}, {
+			Value: "zstd",
+			Help:  "Zstandard compression with better ratio and faster decompression than gzip.",
 		},
 	}
 
@@ -87,7 +92,9 @@ func init() {
 			Examples: compressionModeOptions,
 		}, {
 			Name: "level",
-			Help: `GZIP compression level (-2 to 9).
+			Help: `Compression level.
+
+For gzip this is -2 to 9.
 
 Generally -1 (default, equivalent to 5) is recommended.
 Levels 1 to 9 increase compression at the cost of speed. Going past 6 
@@ -95,7 +102,10 @@ generally offers very little return.
 
 Level -2 uses Huffman encoding only. Only use if you know what you
 are doing.
-Level 0 turns off compression.`,
+Level 0 turns off compression.
+
+For zstd this is 1 to 22 which is mapped onto the nearest level the
+encoder supports. Levels of 0 or below use the default level.`,
 			Default:  sgzip.DefaultCompression,
 			Advanced: true,
 		}, {
@@ -142,6 +152,11 @@ func NewFs(ctx context.Context, name, rpath string, m configmap.Mapper) (fs.Fs,
 		return nil, err
 	}
 
+	mode := compressionModeFromName(opt.CompressionMode)
+	if mode == Uncompressed {
+		return nil, fmt.Errorf("unknown compression mode %q", opt.CompressionMode)
+	}
+
 	remote := opt.Remote
 	if strings.HasPrefix(remote, name+":") {
 		return nil, errors.New("can't point press remote at itself - check the value of the remote setting")
@@ -173,7 +188,7 @@ func NewFs(ctx context.Context, name, rpath string, m configmap.Mapper) (fs.Fs,
 		name: name,
 		root: rpath,
 		opt:  *opt,
-		mode: compressionModeFromName(opt.CompressionMode),
+		mode: mode,
 	}
 	// Correct root if definitely pointing to a file
 	if err == fs.ErrorIsFile {
@@ -219,6 +234,8 @@ func compressionModeFromName(name string) int {
 	switch name {
 	case "gzip":
 		return Gzip
+	case "zstd":
+		return Zstd
 	default:
 		return Uncompressed
 	}
@@ -261,7 +278,22 @@ func processFileName(compressedFileName string) (origFileName string, extension
 	if err != nil {
 		return "", "", 0, errors.New("could not decode size")
 	}
-	return match[1], gzFileExt, size, nil
+	if extension != zstdFileExt {
+		extension = gzFileExt
+	}
+	return match[1], extension, size, nil
+}
+
+// Returns the file extension used for data files of the compression mode
+func compressionModeFileExt(mode int) string {
+	switch mode {
+	case Uncompressed:
+		return uncompressedFileExt
+	case Zstd:
+		return zstdFileExt
+	default:
+		return gzFileExt
+	}
 }
 
 // Generates the file name for a metadata file
@@ -287,7 +319,7 @@ func unwrapMetadataFile(filename string) (string, bool) {
 // makeDataName generates the file name for a data file with specified compression mode
 func makeDataName(remote string, size int64, mode int) (newRemote string) {
 	if mode != Uncompressed {
-		newRemote = remote + "." + int64ToBase64(size) + gzFileExt
+		newRemote = remote + "." + int64ToBase64(size) + compressionModeFileExt(mode)
 	} else {
 		newRemote = remote + uncompressedFileExt
 	}
@@ -428,7 +460,7 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 		return nil, fmt.Errorf("error decoding metadata: %w", err)
 	}
 	// Create our Object
-	o, err := f.Fs.NewObject(ctx, makeDataName(remote, meta.CompressionMetadata.Size, meta.Mode))
+	o, err := f.Fs.NewObject(ctx, makeDataName(remote, meta.Size, meta.Mode))
 	if err != nil {
 		return nil, err
 	}
@@ -495,8 +527,56 @@ func (f *Fs) verifyObjectHash(ctx context.Context, o fs.Object, hasher *hash.Mul
 type putFn func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
 
 type compressionResult struct {
-	err  error
-	meta sgzip.GzipMetadata
+	err      error
+	size     int64
+	meta     sgzip.GzipMetadata
+	zstdMeta *SzstdMetadata
+}
+
+// compressor is implemented by the writers for each compression mode
+type compressor interface {
+	io.WriteCloser
+	// result returns the compression metadata - only valid after Close
+	result() compressionResult
+}
+
+// gzipCompressor adapts an sgzip.Writer to compressor
+type gzipCompressor struct {
+	*sgzip.Writer
+}
+
+func (c gzipCompressor) result() compressionResult {
+	meta := c.MetaData()
+	return compressionResult{size: meta.Size, meta: meta}
+}
+
+// zstdCompressor adapts an szstdWriter to compressor
+type zstdCompressor struct {
+	*szstdWriter
+}
+
+func (c zstdCompressor) result() compressionResult {
+	meta := c.MetaData()
+	return compressionResult{size: meta.Size, zstdMeta: &meta}
+}
+
+// newCompressor returns a compressor for the configured mode writing to w
+func (f *Fs) newCompressor(w io.Writer) (compressor, error) {
+	switch f.mode {
+	case Gzip:
+		gz, err := sgzip.NewWriterLevel(w, f.opt.CompressionLevel)
+		if err != nil {
+			return nil, err
+		}
+		return gzipCompressor{gz}, nil
+	case Zstd:
+		z, err := newSzstdWriter(w, f.opt.CompressionLevel)
+		if err != nil {
+			return nil, err
+		}
+		return zstdCompressor{z}, nil
+	}
+	return nil, fmt.Errorf("unknown compression mode %d", f.mode)
 }
 
 // replicating some of operations.Rcat functionality because we want to support remotes without streaming
@@ -559,17 +639,18 @@ func (f *Fs) putCompress(ctx context.Context, in io.Reader, src fs.ObjectInfo, o
 	pipeReader, pipeWriter := io.Pipe()
 	results := make(chan compressionResult)
 	go func() {
-		gz, err := sgzip.NewWriterLevel(pipeWriter, f.opt.CompressionLevel)
+		c, err := f.newCompressor(pipeWriter)
 		if err != nil {
-			results <- compressionResult{err: err, meta: sgzip.GzipMetadata{}}
+			_ = pipeWriter.CloseWithError(err)
+			results <- compressionResult{err: err}
 			return
 		}
-		_, err = io.Copy(gz, in)
-		gzErr := gz.Close()
-		if gzErr != nil {
-			fs.Errorf(nil, "Failed to close compress: %v", gzErr)
+		_, err = io.Copy(c, in)
+		cErr := c.Close()
+		if cErr != nil {
+			fs.Errorf(nil, "Failed to close compress: %v", cErr)
 			if err == nil {
-				err = gzErr
+				err = cErr
 			}
 		}
 		closeErr := pipeWriter.Close()
@@ -579,7 +660,9 @@ func (f *Fs) putCompress(ctx context.Context, in io.Reader, src fs.ObjectInfo, o
 				err = closeErr
 			}
 		}
-		results <- compressionResult{err: err, meta: gz.MetaData()}
+		result := c.result()
+		result.err = err
+		results <- result
 	}()
 	wrappedIn := wrap(bufio.NewReaderSize(pipeReader, bufferSize)) // Probably no longer needed as sgzip has it's own buffering
 
@@ -626,7 +709,8 @@ func (f *Fs) putCompress(ctx context.Context, in io.Reader, src fs.ObjectInfo, o
 	}
 
 	// Generate metadata
-	meta := newMetadata(result.meta.Size, f.mode, result.meta, hex.EncodeToString(metaHasher.Sum(nil)), mimeType)
+	meta := newMetadata(result.size, f.mode, result.meta, hex.EncodeToString(metaHasher.Sum(nil)), mimeType)
+	meta.ZstdMetadata = result.zstdMeta
 
 	// Check the hashes of the compressed data if we were comparing them
 	if ht != hash.None && hasher != nil {
@@ -1047,7 +1131,23 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	if do == nil {
 		return
 	}
-	wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+	do(ctx, f.wrapNotifyFunc(notifyFunc), pollIntervalChan)
+}
+
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	do := f.Fs.Features().ChangesSince
+	if do == nil {
+		return "", fs.ErrorNotImplemented
+	}
+	return do(ctx, token, f.wrapNotifyFunc(notifyFunc))
+}
+
+// wrapNotifyFunc returns a notifyFunc which reports changes to the
+// metadata files as changes to the objects they describe.
+func (f *Fs) wrapNotifyFunc(notifyFunc func(string, fs.EntryType)) func(string, fs.EntryType) {
+	return func(path string, entryType fs.EntryType) {
 		fs.Logf(f, "path %q entryType %d", path, entryType)
 		var (
 			wrappedPath    string
@@ -1069,7 +1169,6 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 		}
 		notifyFunc(wrappedPath, entryType)
 	}
-	do(ctx, wrappedNotifyFunc, pollIntervalChan)
 }
 
 // PublicLink generates a public link to the remote path (usually readable by anyone)
@@ -1095,6 +1194,7 @@ type ObjectMetadata struct {
 	MD5                 string // MD5 hash of the file.
 	MimeType            string // Mime type of the file
 	CompressionMetadata sgzip.GzipMetadata
+	ZstdMetadata        *SzstdMetadata `json:",omitempty"` // Seek table if Mode is Zstd
 }
 
 // Object with external metadata
@@ -1395,14 +1495,30 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.Read
 	}
 	// Get a chunkedreader for the wrapped object
 	chunkedReader := chunkedreader.New(ctx, o.Object, initialChunkSize, maxChunkSize, chunkStreams)
+	var closer io.Closer = chunkedReader
 	// Get file handle
 	var file io.Reader
-	if offset != 0 {
-		file, err = sgzip.NewReaderAt(chunkedReader, &o.meta.CompressionMetadata, offset)
-	} else {
-		file, err = sgzip.NewReader(chunkedReader)
+	switch o.meta.Mode {
+	case Zstd:
+		if o.meta.ZstdMetadata == nil {
+			_ = chunkedReader.Close()
+			return nil, errors.New("missing zstd seek table in metadata")
+		}
+		var zr *szstdReader
+		zr, err = newSzstdReaderAt(chunkedReader, o.meta.ZstdMetadata, offset)
+		if err == nil {
+			file = zr
+			closer = multiCloser{zr, chunkedReader}
+		}
+	default:
+		if offset != 0 {
+			file, err = sgzip.NewReaderAt(chunkedReader, &o.meta.CompressionMetadata, offset)
+		} else {
+			file, err = sgzip.NewReader(chunkedReader)
+		}
 	}
 	if err != nil {
+		_ = chunkedReader.Close()
 		return nil, err
 	}
 
@@ -1413,7 +1529,20 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.Read
 		fileReader = file
 	}
 	// Return a ReadCloser
-	return ReadCloserWrapper{Reader: fileReader, Closer: chunkedReader}, nil
+	return ReadCloserWrapper{Reader: fileReader, Closer: closer}, nil
+}
+
+// multiCloser closes all of its Closers in order returning the first error
+type multiCloser []io.Closer
+
+// Close all the Closers
+func (mc multiCloser) Close() (err error) {
+	for _, c := range mc {
+		if closeErr := c.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
 // ObjectInfo describes a wrapped fs.ObjectInfo for being the source
@@ -1572,6 +1701,7 @@ var (
 	_ fs.MergeDirser     = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
 	_ fs.FullObjectInfo  = (*ObjectInfo)(nil)
@@ -20,12 +20,15 @@ var defaultOpt = fstests.Opt{
 	UnimplementableFsMethods: []string{
 		"OpenWriterAt",
 		"OpenChunkWriter",
+		"ResumeChunkWriter",
 		"MergeDirs",
 		"DirCacheFlush",
 		"PutUnchecked",
 		"PutStream",
 		"UserInfo",
 		"Disconnect",
+		"ListAt",
+		"NewObjectAt",
 	},
 	TiersToTest:                  []string{"STANDARD", "STANDARD_IA"},
 	UnimplementableObjectMethods: []string{},
@@ -53,3 +56,21 @@ func TestRemoteGzip(t *testing.T) {
 	opt.QuickTestOK = true
 	fstests.Run(t, &opt)
 }
+
+// TestRemoteZstd tests ZSTD compression
+func TestRemoteZstd(t *testing.T) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping as -remote set")
+	}
+	tempdir := filepath.Join(os.TempDir(), "rclone-compress-test-zstd")
+	name := "TestCompressZstd"
+	opt := defaultOpt
+	opt.RemoteName = name + ":"
+	opt.ExtraConfig = []fstests.ExtraConfigItem{
+		{Name: name, Key: "type", Value: "compress"},
+		{Name: name, Key: "remote", Value: tempdir},
+		{Name: name, Key: "mode", Value: "zstd"},
+	}
+	opt.QuickTestOK = true
+	fstests.Run(t, &opt)
+}
@@ -0,0 +1,171 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Seekable zstd
+//
+// The data is split into blocks of szstdBlockSize uncompressed bytes
+// and each block is compressed into an independent zstd frame. The
+// result is a standard zstd stream which any zstd decoder can read,
+// and the compressed size of each frame is recorded in the metadata
+// so that a reader can start decoding at any block boundary.
+
+const szstdBlockSize = 1 << 20
+
+// SzstdMetadata holds the seek table for a seekable zstd stream.
+type SzstdMetadata struct {
+	BlockSize int      // Uncompressed size of each block (except possibly the last)
+	Size      int64    // Uncompressed size of the stream
+	BlockData []uint32 // Compressed size of each block
+}
+
+// szstdWriter compresses data written to it into a seekable zstd
+// stream written to w.
+type szstdWriter struct {
+	w      io.Writer
+	enc    *zstd.Encoder
+	buf    []byte // uncompressed data for the current block
+	out    []byte // compressed data for the current block
+	meta   SzstdMetadata
+	closed bool
+}
+
+// zstdEncoderLevel converts the level config option into a zstd
+// encoder level. Levels <= 0 select the default level.
+func zstdEncoderLevel(level int) zstd.EncoderLevel {
+	if level <= 0 {
+		return zstd.SpeedDefault
+	}
+	return zstd.EncoderLevelFromZstd(level)
+}
+
+// newSzstdWriter returns a seekable zstd writer compressing at level
+func newSzstdWriter(w io.Writer, level int) (*szstdWriter, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdEncoderLevel(level)), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &szstdWriter{
+		w:   w,
+		enc: enc,
+		buf: make([]byte, 0, szstdBlockSize),
+		meta: SzstdMetadata{
+			BlockSize: szstdBlockSize,
+		},
+	}, nil
+}
+
+// Write compresses p, writing whole frames to the underlying writer
+// as blocks fill up.
+func (z *szstdWriter) Write(p []byte) (n int, err error) {
+	if z.closed {
+		return 0, errors.New("szstd: write on closed writer")
+	}
+	for len(p) > 0 {
+		chunk := min(len(p), szstdBlockSize-len(z.buf))
+		z.buf = append(z.buf, p[:chunk]...)
+		p = p[chunk:]
+		n += chunk
+		if len(z.buf) == szstdBlockSize {
+			if err = z.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// flush compresses the current block into a frame and writes it out
+func (z *szstdWriter) flush() error {
+	if len(z.buf) == 0 {
+		return nil
+	}
+	z.out = z.enc.EncodeAll(z.buf, z.out[:0])
+	if _, err := z.w.Write(z.out); err != nil {
+		return err
+	}
+	z.meta.Size += int64(len(z.buf))
+	z.meta.BlockData = append(z.meta.BlockData, uint32(len(z.out)))
+	z.buf = z.buf[:0]
+	return nil
+}
+
+// Close flushes the last block. It does not close the underlying writer.
+func (z *szstdWriter) Close() error {
+	if z.closed {
+		return nil
+	}
+	z.closed = true
+	err := z.flush()
+	closeErr := z.enc.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// MetaData returns the seek table. Only valid after Close.
+func (z *szstdWriter) MetaData() SzstdMetadata {
+	return z.meta
+}
+
+// szstdReader decompresses a seekable zstd stream
+type szstdReader struct {
+	io.Reader
+	dec *zstd.Decoder
+}
+
+// Close releases the resources held by the decoder. It does not
+// close the underlying reader.
+func (z *szstdReader) Close() error {
+	z.dec.Close()
+	return nil
+}
+
+// newSzstdReaderAt returns a reader for the uncompressed data in rs
+// starting at offset.
+//
+// It uses the seek table in meta to seek rs to the start of the frame
+// containing offset so only the data from that frame on is read.
+func newSzstdReaderAt(rs io.ReadSeeker, meta *SzstdMetadata, offset int64) (*szstdReader, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("szstd: negative offset %d", offset)
+	}
+	var (
+		compressedOffset int64
+		skip             = offset
+	)
+	if offset > 0 {
+		if meta.BlockSize <= 0 {
+			return nil, errors.New("szstd: invalid block size in metadata")
+		}
+		block := offset / int64(meta.BlockSize)
+		if block > int64(len(meta.BlockData)) {
+			block = int64(len(meta.BlockData))
+		}
+		for _, size := range meta.BlockData[:block] {
+			compressedOffset += int64(size)
+		}
+		skip = offset - block*int64(meta.BlockSize)
+	}
+	if _, err := rs.Seek(compressedOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(rs)
+	if err != nil {
+		return nil, err
+	}
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, dec, skip); err != nil && err != io.EOF {
+			dec.Close()
+			return nil, err
+		}
+	}
+	return &szstdReader{Reader: dec, dec: dec}, nil
+}
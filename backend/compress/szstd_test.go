@@ -0,0 +1,87 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSzstdRoundTrip(t *testing.T) {
+	// Compressible data spanning several blocks with a partial last block
+	data := make([]byte, 3*szstdBlockSize+12345)
+	rng := rand.New(rand.NewSource(1))
+	for i := range data {
+		data[i] = byte('a' + rng.Intn(4))
+	}
+
+	var compressed bytes.Buffer
+	w, err := newSzstdWriter(&compressed, -1)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	meta := w.MetaData()
+
+	assert.Equal(t, int64(len(data)), meta.Size)
+	assert.Equal(t, szstdBlockSize, meta.BlockSize)
+	assert.Len(t, meta.BlockData, 4)
+	var total int64
+	for _, size := range meta.BlockData {
+		total += int64(size)
+	}
+	assert.Equal(t, int64(compressed.Len()), total)
+
+	// The output must be readable by a standard zstd decoder
+	dec, err := zstd.NewReader(bytes.NewReader(compressed.Bytes()))
+	require.NoError(t, err)
+	got, err := io.ReadAll(dec)
+	dec.Close()
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	for _, offset := range []int64{0, 1, szstdBlockSize - 1, szstdBlockSize, 2*szstdBlockSize + 17, int64(len(data)) - 1, int64(len(data)), int64(len(data)) + 10} {
+		r, err := newSzstdReaderAt(bytes.NewReader(compressed.Bytes()), &meta, offset)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		want := data[min(offset, int64(len(data))):]
+		assert.Equal(t, want, got, "offset %d", offset)
+	}
+}
+
+func TestZstdNewObject(t *testing.T) {
+	ctx := context.Background()
+	f, err := NewFs(ctx, "TestZstdNewObject", "", configmap.Simple{
+		"type":   "compress",
+		"remote": t.TempDir(),
+		"mode":   "zstd",
+	})
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("potato "), 10000)
+	src := object.NewStaticObjectInfo("file.txt", time.Now(), int64(len(data)), true, nil, nil)
+	_, err = f.Put(ctx, bytes.NewReader(data), src)
+	require.NoError(t, err)
+
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, o.(*Object).meta.Mode)
+	assert.Equal(t, int64(len(data)), o.Size())
+	rc, err := o.Open(ctx)
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, data, got)
+}
@@ -491,6 +491,33 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.newObject(o), nil
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	do := f.Fs.Features().ListAt
+	if do == nil {
+		return nil, fs.ErrorNotImplemented
+	}
+	entries, err = do(ctx, f.cipher.EncryptDirName(dir), t)
+	if err != nil {
+		return nil, err
+	}
+	return f.encryptEntries(ctx, entries)
+}
+
+// NewObjectAt finds the Object at remote as it was at time t.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	do := f.Fs.Features().NewObjectAt
+	if do == nil {
+		return nil, fs.ErrorNotImplemented
+	}
+	o, err := do(ctx, f.cipher.EncryptFileName(remote), t)
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(o), nil
+}
+
 type putFn func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
 
 // put implements Put or PutStream
@@ -888,15 +915,10 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	return do(ctx, o.(*Object).Object.Remote(), expire, unlink)
 }
 
-// ChangeNotify calls the passed function with a path
-// that has had changes. If the implementation
-// uses polling, it should adhere to the given interval.
-func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
-	do := f.Fs.Features().ChangeNotify
-	if do == nil {
-		return
-	}
-	wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+// wrapNotifyFunc returns a notifyFunc which decrypts the paths
+// passed to it before calling notifyFunc.
+func (f *Fs) wrapNotifyFunc(notifyFunc func(string, fs.EntryType)) func(string, fs.EntryType) {
+	return func(path string, entryType fs.EntryType) {
 		// fs.Debugf(f, "ChangeNotify: path %q entryType %d", path, entryType)
 		var (
 			err       error
@@ -917,7 +939,27 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 		}
 		notifyFunc(decrypted, entryType)
 	}
-	do(ctx, wrappedNotifyFunc, pollIntervalChan)
+}
+
+// ChangeNotify calls the passed function with a path
+// that has had changes. If the implementation
+// uses polling, it should adhere to the given interval.
+func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
+	do := f.Fs.Features().ChangeNotify
+	if do == nil {
+		return
+	}
+	do(ctx, f.wrapNotifyFunc(notifyFunc), pollIntervalChan)
+}
+
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	do := f.Fs.Features().ChangesSince
+	if do == nil {
+		return "", fs.ErrorNotImplemented
+	}
+	return do(ctx, token, f.wrapNotifyFunc(notifyFunc))
 }
 
 var commandHelp = []fs.CommandHelp{
@@ -1321,6 +1363,9 @@ var (
 	_ fs.MkdirMetadataer = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
+	_ fs.ListAter        = (*Fs)(nil)
+	_ fs.NewObjectAter   = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.UserInfoer      = (*Fs)(nil)
 	_ fs.Disconnecter    = (*Fs)(nil)
@@ -0,0 +1,599 @@
+// Package dedup implements a deduplicating overlay backend which
+// stores files as content defined chunks.
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/configstruct"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/list"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/lib/cdc"
+	"golang.org/x/sync/errgroup"
+)
+
+// Names of the directories in the base remote
+const (
+	filesDir  = "files"  // manifests mirroring the user visible tree
+	chunksDir = "chunks" // chunk store shared by all files
+	refsDir   = "refs"   // references to the chunks from the manifests
+	locksDir  = "locks"  // locks held by the users of the remote
+)
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "dedup",
+		Description: "Deduplicate a remote using content defined chunking",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Required: true,
+			Help: `Remote to store the deduplicated data in.
+
+Normally should contain a ':' and a path, e.g. "myremote:path/to/dir",
+"myremote:bucket" or maybe "myremote:" (not recommended).
+
+The manifests are stored under "files", the chunks under "chunks", the
+chunk references under "refs" and the locks under "locks" inside this
+remote.`,
+		}, {
+			Name: "chunk_size",
+			Help: `Average size of the chunks files are split into.
+
+This is rounded down to a power of 2. Chunks will be between a quarter
+and four times this size. Smaller chunks find more duplicate data at the
+cost of more objects on the remote.
+
+Changing this on an existing remote is safe but new uploads won't
+share chunks with data uploaded with the old size.`,
+			Default:  fs.SizeSuffix(1024 * 1024),
+			Advanced: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote    string        `config:"remote"`
+	ChunkSize fs.SizeSuffix `config:"chunk_size"`
+}
+
+// Fs represents a wrapped fs.Fs
+//
+// The embedded fs.Fs holds the manifests in the same tree as the
+// user visible files.
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	wrapper  fs.Fs
+	features *fs.Features
+	opt      *Options
+	chunks   fs.Fs       // where the chunks are stored
+	refs     fs.Fs       // where the chunk references are stored
+	locks    fs.Fs       // where the locks are stored
+	shared   *sharedLock // shared lock on the remote used by all its Fs
+}
+
+// NewFs constructs an Fs from the remote:path string
+func NewFs(ctx context.Context, fsname, rpath string, cmap configmap.Mapper) (fs.Fs, error) {
+	opt := &Options{}
+	err := configstruct.Set(cmap, opt)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(opt.Remote, fsname+":") {
+		return nil, errors.New("can't point remote at itself")
+	}
+	if opt.ChunkSize < 256 {
+		return nil, fmt.Errorf("chunk_size %v is too small", opt.ChunkSize)
+	}
+
+	chunksFs, err := cache.Get(ctx, fspath.JoinRootPath(opt.Remote, chunksDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make remote for chunks: %w", err)
+	}
+	refsFs, err := cache.Get(ctx, fspath.JoinRootPath(opt.Remote, refsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make remote for chunk references: %w", err)
+	}
+	locksFs, err := cache.Get(ctx, fspath.JoinRootPath(opt.Remote, locksDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make remote for locks: %w", err)
+	}
+	filesPath := fspath.JoinRootPath(fspath.JoinRootPath(opt.Remote, filesDir), rpath)
+	baseFs, err := cache.Get(ctx, filesPath)
+	if err != nil && err != fs.ErrorIsFile {
+		return nil, fmt.Errorf("failed to make remote for manifests %q: %w", filesPath, err)
+	}
+
+	f := &Fs{
+		Fs:     baseFs,
+		name:   fsname,
+		root:   rpath,
+		opt:    opt,
+		chunks: chunksFs,
+		refs:   refsFs,
+		locks:  locksFs,
+		shared: getSharedLock(locksFs),
+	}
+	// Correct root if definitely pointing to a file
+	if err == fs.ErrorIsFile {
+		f.root = path.Dir(f.root)
+		if f.root == "." || f.root == "/" {
+			f.root = ""
+		}
+	}
+
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from baseFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          false,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(ctx, f).Mask(ctx, baseFs).WrapsFs(f, baseFs)
+	// We can always copy, and stream as the manifest is written last
+	f.features.Copy = f.Copy
+	f.features.PutStream = f.PutStream
+	// CleanUp removes the chunks, so doesn't need the base remote
+	f.features.CleanUp = f.CleanUp
+	// Enable ListP always
+	f.features.ListP = f.ListP
+
+	cache.Pin(f.Fs)
+	cache.Pin(f.chunks)
+	cache.Pin(f.refs)
+	cache.Pin(f.locks)
+	runtime.SetFinalizer(f, func(f *Fs) {
+		cache.Unpin(f.Fs)
+		cache.Unpin(f.chunks)
+		cache.Unpin(f.refs)
+		cache.Unpin(f.locks)
+	})
+	return f, err
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string { return f.name }
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string { return f.root }
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features { return f.features }
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set { return hash.NewHashSet(hash.MD5, hash.SHA1) }
+
+// Precision returns the precision of this Fs
+//
+// Modification times are stored in the manifest so are exact.
+func (f *Fs) Precision() time.Duration { return time.Nanosecond }
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Dedup '%s:%s'", f.name, f.root)
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs { return f.Fs }
+
+// WrapFs returns the Fs that is wrapping this Fs
+func (f *Fs) WrapFs() fs.Fs { return f.wrapper }
+
+// SetWrapper sets the Fs that is wrapping this Fs
+func (f *Fs) SetWrapper(wrapper fs.Fs) { f.wrapper = wrapper }
+
+// wrapEntries turns the manifests in baseEntries into Objects
+//
+// The manifests are read in parallel as we need them for the size.
+func (f *Fs) wrapEntries(ctx context.Context, baseEntries fs.DirEntries) (fs.DirEntries, error) {
+	objs := make([]*Object, len(baseEntries))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(fs.GetConfig(ctx).Checkers)
+	for i, entry := range baseEntries {
+		mo, ok := entry.(fs.Object)
+		if !ok {
+			continue
+		}
+		g.Go(func() error {
+			o := f.newObject(mo)
+			if err := o.readManifest(gCtx); err != nil {
+				if errors.Is(err, errInvalidManifest) {
+					fs.Errorf(mo, "Ignoring: %v", err)
+					return nil
+				}
+				return err
+			}
+			objs[i] = o
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	entries := baseEntries[:0] // work inplace
+	for i, entry := range baseEntries {
+		switch entry.(type) {
+		case fs.Object:
+			if objs[i] != nil {
+				entries = append(entries, objs[i])
+			}
+		default:
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// List the objects and directories in dir into entries.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	return list.WithListP(ctx, dir, f)
+}
+
+// ListP lists the objects and directories of the Fs starting
+// from dir non recursively into out.
+//
+// dir should be "" to start from the root, and should not
+// have trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// It should call callback for each tranche of entries read.
+// These need not be returned in any particular order.  If
+// callback returns an error then the listing will stop
+// immediately.
+func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) error {
+	wrappedCallback := func(entries fs.DirEntries) error {
+		entries, err := f.wrapEntries(ctx, entries)
+		if err != nil {
+			return err
+		}
+		return callback(entries)
+	}
+	listP := f.Fs.Features().ListP
+	if listP == nil {
+		entries, err := f.Fs.List(ctx, dir)
+		if err != nil {
+			return err
+		}
+		return wrappedCallback(entries)
+	}
+	return listP(ctx, dir, wrappedCallback)
+}
+
+// ListR lists the objects and directories recursively into out.
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.Fs.Features().ListR(ctx, dir, func(baseEntries fs.DirEntries) error {
+		entries, err := f.wrapEntries(ctx, baseEntries)
+		if err != nil {
+			return err
+		}
+		return callback(entries)
+	})
+}
+
+// NewObject finds the Object at remote.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	mo, err := f.Fs.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	o := f.newObject(mo)
+	if err := o.readManifest(ctx); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// chunkName returns the path of the chunk with the hex encoded hash
+// given in the chunk store.
+func chunkName(hashHex string) string {
+	return path.Join(hashHex[:2], hashHex)
+}
+
+// putChunk uploads data to the chunk store unless it is there already
+//
+// Call with the shared lock held so CleanUp can't remove the chunk
+// before the manifest referencing it is written.
+func (f *Fs) putChunk(ctx context.Context, data []byte) (ref chunkRef, err error) {
+	sum := sha256.Sum256(data)
+	ref = chunkRef{
+		Hash: hex.EncodeToString(sum[:]),
+		Size: int64(len(data)),
+	}
+	name := chunkName(ref.Hash)
+	if _, ok := f.shared.known.Load(name); ok {
+		return ref, nil
+	}
+	o, err := f.chunks.NewObject(ctx, name)
+	if err == nil && o.Size() == ref.Size {
+		f.shared.known.Store(name, struct{}{})
+		return ref, nil
+	}
+	if err != nil && err != fs.ErrorObjectNotFound {
+		return ref, err
+	}
+	src := object.NewStaticObjectInfo(name, time.Now(), ref.Size, true, nil, f.chunks)
+	if _, err = f.chunks.Put(ctx, bytes.NewReader(data), src); err != nil {
+		return ref, fmt.Errorf("failed to upload chunk %s: %w", ref.Hash, err)
+	}
+	f.shared.known.Store(name, struct{}{})
+	return ref, nil
+}
+
+// putChunks reads in, uploading any chunks not already stored, and
+// returns the manifest describing it.
+func (f *Fs) putChunks(ctx context.Context, in io.Reader, src fs.ObjectInfo) (*manifest, error) {
+	hasher, err := hash.NewMultiHasherTypes(f.Hashes())
+	if err != nil {
+		return nil, err
+	}
+	chunker, err := cdc.New(io.TeeReader(in, hasher), int(f.opt.ChunkSize))
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{
+		Version: manifestVersion,
+		ModTime: src.ModTime(ctx),
+		Chunks:  []chunkRef{},
+	}
+	for {
+		data, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ref, err := f.putChunk(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		m.Chunks = append(m.Chunks, ref)
+		m.Size += ref.Size
+	}
+	if src.Size() >= 0 && m.Size != src.Size() {
+		return nil, fmt.Errorf("upload size mismatch: expecting %d got %d", src.Size(), m.Size)
+	}
+	m.Hashes = map[string]string{}
+	for ht, sum := range hasher.Sums() {
+		m.Hashes[ht.String()] = sum
+	}
+	return m, nil
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	unlock, err := f.lockShared(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	m, err := f.putChunks(ctx, in, src)
+	if err != nil {
+		return nil, err
+	}
+	return f.putManifest(ctx, src.Remote(), m, options)
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
+}
+
+// Copy src to this remote using server-side copy operations.
+//
+// Only the manifest needs to be written as the chunks are shared.
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantCopy
+	}
+	if o.f.opt.Remote != f.opt.Remote {
+		fs.Debugf(src, "Can't copy - different chunk store")
+		return nil, fs.ErrorCantCopy
+	}
+	unlock, err := f.lockShared(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if err := o.readManifest(ctx); err != nil {
+		return nil, err
+	}
+	return f.putManifest(ctx, remote, o.m, nil)
+}
+
+// Move src to this remote using server-side move operations.
+//
+// The references to the chunks of any file it replaces are removed.
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantMove
+	}
+	if o.f.opt.Remote != f.opt.Remote {
+		fs.Debugf(src, "Can't move - different chunk store")
+		return nil, fs.ErrorCantMove
+	}
+	unlock, err := f.lockShared(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if err := o.readManifest(ctx); err != nil {
+		return nil, err
+	}
+	// A case only rename finds the source on case insensitive remotes
+	var old *manifest
+	if !strings.EqualFold(path.Join(f.root, remote), path.Join(o.f.root, o.Remote())) {
+		old = f.existingManifest(ctx, remote)
+	}
+	mo, err := do(ctx, o.mo, remote)
+	if err != nil {
+		return nil, err
+	}
+	newO := f.newObject(mo)
+	newO.m = o.m
+	return newO, f.journalRefs(ctx, nil, chunkHashes(old))
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server-side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok || srcFs.opt.Remote != f.opt.Remote {
+		fs.Debugf(srcFs, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	return do(ctx, srcFs.Fs, srcRemote, dstRemote)
+}
+
+// Purge all files in the directory
+//
+// The references to the chunks are removed but the chunks are left
+// behind for CleanUp to collect as they may still be referenced by
+// other files.
+func (f *Fs) Purge(ctx context.Context, dir string) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	unlock, err := f.lockShared(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	var remove []string
+	err = walk.ListR(ctx, f.Fs, dir, true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			mo, ok := entry.(fs.Object)
+			if !ok {
+				continue
+			}
+			m, err := readManifest(ctx, mo)
+			if errors.Is(err, errInvalidManifest) {
+				fs.Errorf(mo, "Ignoring: %v", err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			remove = append(remove, chunkHashes(m)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = do(ctx, dir); err != nil {
+		return err
+	}
+	return f.journalRefs(ctx, nil, remove)
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	if do := f.Fs.Features().About; do != nil {
+		return do(ctx)
+	}
+	return nil, errors.New("not supported by underlying remote")
+}
+
+// ChangeNotify calls the passed function with a path that has had changes.
+//
+// The manifests have the same names as the files so the paths can be
+// passed straight through.
+func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
+	if do := f.Fs.Features().ChangeNotify; do != nil {
+		do(ctx, notifyFunc, pollIntervalChan)
+	}
+}
+
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if do := f.Fs.Features().ChangesSince; do != nil {
+		return do(ctx, token, notifyFunc)
+	}
+	return "", fs.ErrorNotImplemented
+}
+
+// DirCacheFlush resets the directory cache - used in testing
+// as an optional interface
+func (f *Fs) DirCacheFlush() {
+	if do := f.Fs.Features().DirCacheFlush; do != nil {
+		do()
+	}
+}
+
+// Shutdown the backend, closing any background tasks and any
+// cached connections.
+func (f *Fs) Shutdown(ctx context.Context) error {
+	f.shared.releaseIdle()
+	do := f.Fs.Features().Shutdown
+	if do == nil {
+		return nil
+	}
+	return do(ctx)
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListPer         = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Wrapper         = (*Fs)(nil)
+	_ fs.DirCacheFlusher = (*Fs)(nil)
+	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
+	_ fs.Shutdowner      = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+)
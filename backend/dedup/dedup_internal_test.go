@@ -0,0 +1,232 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/fstest/fstests"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countChunks returns the number of chunks in the chunk store
+func (f *Fs) countChunks(ctx context.Context, t *testing.T) (n int) {
+	err := walk.ListR(ctx, f.chunks, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		n += len(entries)
+		return nil
+	})
+	if err == fs.ErrorDirNotFound {
+		err = nil
+	}
+	require.NoError(t, err)
+	return n
+}
+
+func (f *Fs) testDedupAndCleanUp(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, f.CleanUp(ctx))
+	before := f.countChunks(ctx, t)
+
+	data := make([]byte, 32*int(f.opt.ChunkSize))
+	rand.New(rand.NewSource(2)).Read(data)
+	edited := append(append(append([]byte{}, data[:len(data)/2]...), "inserted"...), data[len(data)/2:]...)
+
+	item1 := fstest.NewItem("dedup/file1", string(data), fstest.Time("2001-02-03T04:05:06.499999999Z"))
+	o1 := fstests.PutTestContents(ctx, t, f, &item1, string(data), true)
+	afterFirst := f.countChunks(ctx, t)
+	item2 := fstest.NewItem("dedup/file2", string(edited), fstest.Time("2001-02-03T04:05:06.499999999Z"))
+	o2 := fstests.PutTestContents(ctx, t, f, &item2, string(edited), true)
+	afterSecond := f.countChunks(ctx, t)
+
+	firstChunks := afterFirst - before
+	assert.Greater(t, firstChunks, 1)
+	assert.LessOrEqual(t, afterSecond-afterFirst, 2, "second file should reuse most chunks")
+
+	// Removing the first file and cleaning up should only remove
+	// the chunks unique to it
+	require.NoError(t, o1.Remove(ctx))
+	require.NoError(t, f.CleanUp(ctx))
+	afterCleanUp := f.countChunks(ctx, t)
+	assert.Less(t, afterCleanUp, afterSecond)
+	assert.Equal(t, len(o2.(*Object).m.Chunks), afterCleanUp-before)
+
+	got := fstests.ReadObject(ctx, t, o2, -1)
+	assert.Equal(t, string(edited), got)
+
+	require.NoError(t, o2.Remove(ctx))
+	require.NoError(t, f.CleanUp(ctx))
+	assert.Equal(t, before, f.countChunks(ctx, t))
+}
+
+func (f *Fs) testRefs(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, f.CleanUp(ctx))
+
+	// CleanUp leaves an index and an empty journal
+	_, err := f.refs.NewObject(ctx, indexName)
+	require.NoError(t, err)
+	journal, err := f.readJournal(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, journal)
+
+	// Uploads and removes are recorded in the journal
+	contents := random.String(4 * int(f.opt.ChunkSize))
+	item := fstest.NewItem("refs/file", contents, fstest.Time("2001-02-03T04:05:06.499999999Z"))
+	o := fstests.PutTestContents(ctx, t, f, &item, contents, true)
+	hashes := chunkHashes(o.(*Object).m)
+	require.NotEmpty(t, hashes)
+	journal, err = f.readJournal(ctx)
+	require.NoError(t, err)
+	refs, err := f.readRefs(ctx, journal)
+	require.NoError(t, err)
+	for _, hash := range hashes {
+		assert.Greater(t, refs[hash], 0)
+	}
+	require.NoError(t, o.Remove(ctx))
+	journal, err = f.readJournal(ctx)
+	require.NoError(t, err)
+	refs, err = f.readRefs(ctx, journal)
+	require.NoError(t, err)
+	for _, hash := range hashes {
+		assert.Equal(t, 0, refs[hash])
+	}
+
+	// After CleanUp has removed the chunks uploading the same
+	// contents must upload them again
+	require.NoError(t, f.CleanUp(ctx))
+	o = fstests.PutTestContents(ctx, t, f, &item, contents, true)
+	assert.Equal(t, contents, fstests.ReadObject(ctx, t, o, -1))
+	require.NoError(t, o.Remove(ctx))
+
+	// Missing references are counted from the manifests
+	index, err := f.refs.NewObject(ctx, indexName)
+	require.NoError(t, err)
+	require.NoError(t, index.Remove(ctx))
+	require.NoError(t, f.CleanUp(ctx))
+	_, err = f.refs.NewObject(ctx, indexName)
+	require.NoError(t, err)
+}
+
+func (f *Fs) testLocks(t *testing.T) {
+	ctx := context.Background()
+
+	// CleanUp can't run while files are being changed
+	unlock, err := f.lockShared(ctx)
+	require.NoError(t, err)
+	err = f.CleanUp(ctx)
+	assert.ErrorIs(t, err, errLocked)
+	unlock()
+
+	// The shared lock is kept for the next change
+	l := f.shared.held
+	require.NotNil(t, l)
+	unlock, err = f.lockShared(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, l, f.shared.held)
+	unlock()
+	locks, err := f.readLocks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, locks, 1)
+
+	// Until it is released when idle
+	f.shared.releaseIdle()
+	assert.Nil(t, f.shared.held)
+
+	// and files can't be changed while CleanUp runs
+	l, err = f.lock(ctx, true)
+	require.NoError(t, err)
+	_, err = f.lockShared(ctx)
+	assert.ErrorIs(t, err, errLocked)
+	assert.True(t, fserrors.IsRetryError(err))
+	l.release(ctx)
+
+	// Expired locks are ignored
+	l, err = f.lock(ctx, true)
+	require.NoError(t, err)
+	close(l.stop)
+	<-l.done
+	l.stop = nil
+	l.info.Time = time.Now().Add(-2 * lockExpiry)
+	data, err := json.Marshal(&l.info)
+	require.NoError(t, err)
+	src := object.NewStaticObjectInfo(l.name, time.Now(), int64(len(data)), true, nil, f.locks)
+	_, err = f.locks.Put(ctx, bytes.NewReader(data), src)
+	require.NoError(t, err)
+	unlock, err = f.lockShared(ctx)
+	require.NoError(t, err)
+	unlock()
+	f.shared.releaseIdle()
+	l.release(ctx)
+
+	locks, err = f.readLocks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
+
+func (f *Fs) testOverwriteRefs(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, f.CleanUp(ctx))
+
+	put := func(remote string) (fs.Object, []string) {
+		contents := random.String(4 * int(f.opt.ChunkSize))
+		item := fstest.NewItem(remote, contents, fstest.Time("2001-02-03T04:05:06.499999999Z"))
+		o := fstests.PutTestContents(ctx, t, f, &item, contents, true)
+		hashes := chunkHashes(o.(*Object).m)
+		require.NotEmpty(t, hashes)
+		return o, hashes
+	}
+	checkRefs := func(hashes []string, want int) {
+		journal, err := f.readJournal(ctx)
+		require.NoError(t, err)
+		refs, err := f.readRefs(ctx, journal)
+		require.NoError(t, err)
+		for _, hash := range hashes {
+			assert.Equal(t, want, refs[hash], hash)
+		}
+	}
+	oA, hashesA := put("overwrite/a")
+	_, hashesB := put("overwrite/b")
+	_, hashesC := put("overwrite/c")
+
+	// Copying onto an existing file removes its references
+	oB, err := f.Copy(ctx, oA, "overwrite/b")
+	require.NoError(t, err)
+	checkRefs(hashesA, 2)
+	checkRefs(hashesB, 0)
+
+	// and so does moving onto one
+	oC, err := f.Move(ctx, oB, "overwrite/c")
+	require.NoError(t, err)
+	checkRefs(hashesA, 2)
+	checkRefs(hashesC, 0)
+
+	// and uploading over one
+	oA, hashesD := put("overwrite/a")
+	checkRefs(hashesA, 1)
+	checkRefs(hashesD, 1)
+
+	require.NoError(t, oA.Remove(ctx))
+	require.NoError(t, oC.Remove(ctx))
+	checkRefs(hashesA, 0)
+	checkRefs(hashesD, 0)
+}
+
+// InternalTest dispatches all internal tests
+func (f *Fs) InternalTest(t *testing.T) {
+	t.Run("DedupAndCleanUp", f.testDedupAndCleanUp)
+	t.Run("Refs", f.testRefs)
+	t.Run("OverwriteRefs", f.testOverwriteRefs)
+	t.Run("Locks", f.testLocks)
+}
+
+var _ fstests.InternalTester = (*Fs)(nil)
@@ -0,0 +1,68 @@
+// Test Dedup filesystem interface
+package dedup_test
+
+import (
+	"testing"
+
+	"github.com/rclone/rclone/backend/dedup"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/fstest/fstests"
+
+	_ "github.com/rclone/rclone/backend/all" // for integration tests
+)
+
+var defaultOpt = fstests.Opt{
+	RemoteName: "TestDedup:",
+	NilObject:  (*dedup.Object)(nil),
+	UnimplementableFsMethods: []string{
+		"OpenWriterAt",
+		"OpenChunkWriter",
+		"ResumeChunkWriter",
+		"MergeDirs",
+		"PutUnchecked",
+		"PublicLink",
+		"UserInfo",
+		"Disconnect",
+		"DirSetModTime",
+		"MkdirMetadata",
+		"ListAt",
+		"NewObjectAt",
+	},
+	UnimplementableObjectMethods: []string{
+		"MimeType",
+		"GetTier",
+		"SetTier",
+		"Metadata",
+		"SetMetadata",
+		"ID",
+	},
+}
+
+// TestIntegration runs integration tests against the remote
+func TestIntegration(t *testing.T) {
+	if *fstest.RemoteName == "" {
+		t.Skip("Skipping as -remote not set")
+	}
+	opt := defaultOpt
+	opt.RemoteName = *fstest.RemoteName
+	fstests.Run(t, &opt)
+}
+
+// TestLocal tests dedup on top of the local backend with small chunks
+// so files are split into many chunks
+func TestLocal(t *testing.T) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping as -remote set")
+	}
+	tempDir := t.TempDir()
+	name := "TestDedupLocal"
+	opt := defaultOpt
+	opt.RemoteName = name + ":"
+	opt.ExtraConfig = []fstests.ExtraConfigItem{
+		{Name: name, Key: "type", Value: "dedup"},
+		{Name: name, Key: "remote", Value: tempDir},
+		{Name: name, Key: "chunk_size", Value: "1k"},
+	}
+	opt.QuickTestOK = true
+	fstests.Run(t, &opt)
+}
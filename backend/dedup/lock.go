@@ -0,0 +1,291 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/lib/random"
+)
+
+// Locks stop CleanUp removing chunks while files are being changed.
+//
+// Each user of the remote writes an object to locksDir while it is
+// changing files and removes it once it has stopped changing them for
+// lockIdle. Changing files needs a shared lock and CleanUp needs an
+// exclusive lock. Whoever writes a
+// conflicting lock last sees the other lock when it reads them back
+// and gives up.
+const (
+	lockExpiry  = 30 * time.Minute // locks not refreshed for this long are ignored
+	lockRefresh = 5 * time.Minute  // how often held locks are refreshed
+	lockIdle    = time.Minute      // how long an unused shared lock is kept
+)
+
+// errLocked is returned when the remote is locked by someone else
+var errLocked = errors.New("dedup remote is locked")
+
+// lockInfo is the contents of a lock object
+type lockInfo struct {
+	Exclusive bool      `json:"exclusive"`
+	Host      string    `json:"host"`
+	PID       int       `json:"pid"`
+	Time      time.Time `json:"time"`
+}
+
+// heldLock is a lock on the remote which is refreshed until released
+type heldLock struct {
+	f    *Fs
+	name string
+	info lockInfo
+	stop chan struct{}
+	done chan struct{}
+}
+
+// write writes the lock object with the current time
+func (l *heldLock) write(ctx context.Context) error {
+	l.info.Time = time.Now()
+	data, err := json.Marshal(&l.info)
+	if err != nil {
+		return err
+	}
+	src := object.NewStaticObjectInfo(l.name, l.info.Time, int64(len(data)), true, nil, l.f.locks)
+	_, err = l.f.locks.Put(ctx, bytes.NewReader(data), src)
+	return err
+}
+
+// refresh rewrites the lock until it is released
+func (l *heldLock) refresh() {
+	defer close(l.done)
+	ticker := time.NewTicker(lockRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.write(context.Background()); err != nil {
+				fs.Errorf(l.f, "Failed to refresh lock %q: %v", l.name, err)
+			}
+		}
+	}
+}
+
+// release stops refreshing the lock and removes it
+func (l *heldLock) release(ctx context.Context) {
+	if l.stop != nil {
+		close(l.stop)
+		<-l.done
+	}
+	o, err := l.f.locks.NewObject(ctx, l.name)
+	if err == nil {
+		err = o.Remove(ctx)
+	}
+	if err != nil {
+		fs.Errorf(l.f, "Failed to remove lock %q: %v", l.name, err)
+	}
+}
+
+// readLocks reads the locks which haven't expired
+func (f *Fs) readLocks(ctx context.Context) (map[string]lockInfo, error) {
+	entries, err := f.locks.List(ctx, "")
+	if err == fs.ErrorDirNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	locks := map[string]lockInfo{}
+	for _, entry := range entries {
+		o, ok := entry.(fs.Object)
+		if !ok {
+			continue
+		}
+		var info lockInfo
+		err := readJSON(ctx, o, &info)
+		if errors.Is(err, fs.ErrorObjectNotFound) {
+			continue // released while we were reading
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lock %q: %w", o.Remote(), err)
+		}
+		if time.Since(info.Time) > lockExpiry {
+			fs.Debugf(f, "Ignoring expired lock %q", o.Remote())
+			continue
+		}
+		locks[o.Remote()] = info
+	}
+	return locks, nil
+}
+
+// lock takes a lock on the remote
+func (f *Fs) lock(ctx context.Context, exclusive bool) (*heldLock, error) {
+	host, _ := os.Hostname()
+	l := &heldLock{
+		f:    f,
+		name: random.String(16) + ".json",
+		info: lockInfo{
+			Exclusive: exclusive,
+			Host:      host,
+			PID:       os.Getpid(),
+		},
+	}
+	if err := l.write(ctx); err != nil {
+		return nil, fmt.Errorf("failed to write lock: %w", err)
+	}
+	// Now our lock is visible, check for conflicting locks
+	locks, err := f.readLocks(ctx)
+	if err != nil {
+		l.release(ctx)
+		return nil, err
+	}
+	for name, info := range locks {
+		if name == l.name || !(exclusive || info.Exclusive) {
+			continue
+		}
+		l.release(ctx)
+		kind := "shared"
+		if info.Exclusive {
+			kind = "exclusive"
+		}
+		return nil, fmt.Errorf("%w: %s lock held by %s pid %d since %v", errLocked, kind, info.Host, info.PID, info.Time)
+	}
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.refresh()
+	return l, nil
+}
+
+// sharedLock is the shared lock on a remote. It is used by all the Fs
+// in this process which store their data on the remote, so they don't
+// each hold a lock which would stop CleanUp.
+type sharedLock struct {
+	mu        sync.Mutex   // protects the following
+	users     int          // number of users of held
+	held      *heldLock    // the lock, kept for lockIdle after its last use
+	locking   *lockAttempt // set while the lock is being taken
+	idleTimer *time.Timer  // releases held when it has been unused for lockIdle
+	known     sync.Map     // chunk names known to exist while held is set
+}
+
+var (
+	sharedLocksMu sync.Mutex
+	sharedLocks   = map[string]*sharedLock{} // shared locks by the remote the locks are stored in
+)
+
+// getSharedLock returns the shared lock for the locks stored in locks
+func getSharedLock(locks fs.Fs) *sharedLock {
+	sharedLocksMu.Lock()
+	defer sharedLocksMu.Unlock()
+	key := fs.ConfigString(locks)
+	s := sharedLocks[key]
+	if s == nil {
+		s = &sharedLock{}
+		sharedLocks[key] = s
+	}
+	return s
+}
+
+// lockAttempt is an attempt to take the shared lock which other users
+// can wait for
+type lockAttempt struct {
+	done chan struct{} // closed when the attempt has finished
+	err  error         // error taking the lock - valid once done is closed
+}
+
+// lockShared takes a shared lock on the remote returning a function
+// to release it.
+//
+// The lock is kept for lockIdle after it was last used, so files can
+// be changed one after another without writing a lock object each
+// time. Chunks in known are only trusted while it is held, as once it
+// is released CleanUp may remove them.
+func (f *Fs) lockShared(ctx context.Context) (unlock func(), err error) {
+	s := f.shared
+	for {
+		s.mu.Lock()
+		if s.held != nil {
+			s.users++
+			if s.idleTimer != nil {
+				s.idleTimer.Stop()
+				s.idleTimer = nil
+			}
+			s.mu.Unlock()
+			return s.unlock(), nil
+		}
+		attempt := s.locking
+		if attempt == nil {
+			// Take the lock with s.mu released
+			attempt = &lockAttempt{done: make(chan struct{})}
+			s.locking = attempt
+			s.mu.Unlock()
+			l, err := f.lock(ctx, false)
+			if errors.Is(err, errLocked) {
+				// Try again when CleanUp has finished
+				err = fserrors.RetryError(err)
+			}
+			s.mu.Lock()
+			s.locking = nil
+			s.held = l
+			attempt.err = err
+			close(attempt.done)
+			s.mu.Unlock()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Wait for the attempt in progress
+		s.mu.Unlock()
+		select {
+		case <-attempt.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if attempt.err != nil {
+			return nil, attempt.err
+		}
+	}
+}
+
+// unlock returns a function which gives up one use of the lock,
+// releasing it when it hasn't been used for lockIdle.
+func (s *sharedLock) unlock() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.users--
+			if s.users == 0 {
+				s.idleTimer = time.AfterFunc(lockIdle, s.releaseIdle)
+			}
+		})
+	}
+}
+
+// releaseIdle releases the lock if nothing is using it
+func (s *sharedLock) releaseIdle() {
+	s.mu.Lock()
+	l := s.held
+	if l == nil || s.users > 0 {
+		s.mu.Unlock()
+		return
+	}
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+	s.held = nil
+	s.known.Clear()
+	s.mu.Unlock()
+	l.release(context.Background())
+}
@@ -0,0 +1,391 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/object"
+)
+
+// manifestVersion is the current version of the manifest format
+const manifestVersion = 1
+
+// maxManifestSize is the largest manifest we will read
+const maxManifestSize = 256 * 1024 * 1024
+
+// errInvalidManifest is returned when an object isn't a manifest
+var errInvalidManifest = errors.New("invalid dedup manifest")
+
+// chunkRef describes one chunk of a file
+type chunkRef struct {
+	Hash string `json:"h"` // hex SHA-256 of the chunk
+	Size int64  `json:"s"` // size of the chunk
+}
+
+// manifest describes a file as a list of chunks
+type manifest struct {
+	Version int               `json:"ver"`
+	Size    int64             `json:"size"`
+	ModTime time.Time         `json:"mtime"`
+	Hashes  map[string]string `json:"hashes"`
+	Chunks  []chunkRef        `json:"chunks"`
+}
+
+// readManifest reads and validates the manifest in mo
+func readManifest(ctx context.Context, mo fs.Object) (m *manifest, err error) {
+	if mo.Size() > maxManifestSize {
+		return nil, fmt.Errorf("%w: too large", errInvalidManifest)
+	}
+	rc, err := mo.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(rc, &err)
+	m = new(manifest)
+	if err = json.NewDecoder(rc).Decode(m); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidManifest, err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", errInvalidManifest, m.Version)
+	}
+	var size int64
+	for _, chunk := range m.Chunks {
+		if len(chunk.Hash) != 64 || chunk.Size < 0 {
+			return nil, fmt.Errorf("%w: bad chunk %q", errInvalidManifest, chunk.Hash)
+		}
+		size += chunk.Size
+	}
+	if size != m.Size {
+		return nil, fmt.Errorf("%w: chunks add up to %d bytes but size is %d", errInvalidManifest, size, m.Size)
+	}
+	return m, nil
+}
+
+// putManifest writes the manifest m for remote returning the Object
+//
+// The references to the chunks are recorded before the manifest is
+// written so they are never too low. If it replaces an existing
+// manifest the references to its chunks are removed afterwards. Call
+// with the shared lock held.
+func (f *Fs) putManifest(ctx context.Context, remote string, m *manifest, options []fs.OpenOption) (fs.Object, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	old := f.existingManifest(ctx, remote)
+	if err = f.journalRefs(ctx, chunkHashes(m), nil); err != nil {
+		return nil, err
+	}
+	src := object.NewStaticObjectInfo(remote, m.ModTime, int64(len(data)), true, nil, f.Fs)
+	mo, err := f.Fs.Put(ctx, bytes.NewReader(data), src, options...)
+	if err != nil {
+		f.undoRefs(ctx, m)
+		return nil, err
+	}
+	o := f.newObject(mo)
+	o.m = m
+	return o, f.journalRefs(ctx, nil, chunkHashes(old))
+}
+
+// existingManifest reads the manifest at remote which is about to be
+// replaced
+//
+// It returns nil if there isn't one or it can't be read.
+func (f *Fs) existingManifest(ctx context.Context, remote string) *manifest {
+	mo, err := f.Fs.NewObject(ctx, remote)
+	if err != nil {
+		return nil
+	}
+	return f.newObject(mo).oldManifest(ctx)
+}
+
+// Object is a file stored as a manifest and a list of chunks
+type Object struct {
+	f  *Fs
+	mo fs.Object // the manifest object
+	m  *manifest // the manifest, nil if not read yet
+}
+
+// newObject makes an Object from the manifest object mo
+func (f *Fs) newObject(mo fs.Object) *Object {
+	return &Object{
+		f:  f,
+		mo: mo,
+	}
+}
+
+// readManifest reads the manifest if it hasn't been read already
+func (o *Object) readManifest(ctx context.Context) (err error) {
+	if o.m != nil {
+		return nil
+	}
+	o.m, err = readManifest(ctx, o.mo)
+	return err
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info { return o.f }
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.mo.String()
+}
+
+// Remote returns the remote path
+func (o *Object) Remote() string { return o.mo.Remote() }
+
+// UnWrap returns the manifest Object
+func (o *Object) UnWrap() fs.Object { return o.mo }
+
+// Storable returns whether object is storable
+func (o *Object) Storable() bool { return true }
+
+// Size returns the size of the file
+func (o *Object) Size() int64 {
+	if o.m == nil {
+		return -1
+	}
+	return o.m.Size
+}
+
+// ModTime returns the modification time of the file
+func (o *Object) ModTime(ctx context.Context) time.Time {
+	if err := o.readManifest(ctx); err != nil {
+		fs.Errorf(o, "Failed to read manifest: %v", err)
+		return o.mo.ModTime(ctx)
+	}
+	return o.m.ModTime
+}
+
+// SetModTime sets the modification time of the file
+//
+// This rewrites the manifest.
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	if err := o.readManifest(ctx); err != nil {
+		return err
+	}
+	m := *o.m
+	m.ModTime = modTime
+	if err := o.updateManifest(ctx, &m, nil); err != nil {
+		return err
+	}
+	o.m = &m
+	return nil
+}
+
+// Hash returns the selected checksum of the file
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if !o.f.Hashes().Contains(ht) {
+		return "", hash.ErrUnsupported
+	}
+	if err := o.readManifest(ctx); err != nil {
+		return "", err
+	}
+	return o.m.Hashes[ht.String()], nil
+}
+
+// updateManifest replaces the manifest with m
+func (o *Object) updateManifest(ctx context.Context, m *manifest, options []fs.OpenOption) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	src := object.NewStaticObjectInfo(o.mo.Remote(), m.ModTime, int64(len(data)), true, nil, o.f.Fs)
+	return o.mo.Update(ctx, bytes.NewReader(data), src, options...)
+}
+
+// Update the object with the contents of the io.Reader, modTime and size
+//
+// The references to the chunks of the old contents are removed but
+// the chunks are left for CleanUp to remove.
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	unlock, err := o.f.lockShared(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	old := o.oldManifest(ctx)
+	m, err := o.f.putChunks(ctx, in, src)
+	if err != nil {
+		return err
+	}
+	if err = o.f.journalRefs(ctx, chunkHashes(m), nil); err != nil {
+		return err
+	}
+	if err = o.updateManifest(ctx, m, options); err != nil {
+		o.f.undoRefs(ctx, m)
+		return err
+	}
+	o.m = m
+	return o.f.journalRefs(ctx, nil, chunkHashes(old))
+}
+
+// Remove the object
+//
+// The references to the chunks are removed but the chunks are left
+// for CleanUp to remove as they may be shared with other files.
+func (o *Object) Remove(ctx context.Context) error {
+	unlock, err := o.f.lockShared(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	old := o.oldManifest(ctx)
+	if err = o.mo.Remove(ctx); err != nil {
+		return err
+	}
+	return o.f.journalRefs(ctx, nil, chunkHashes(old))
+}
+
+// oldManifest reads the manifest about to be replaced or removed
+//
+// It returns nil if it can't be read, in which case the references to
+// its chunks are kept, which is safe but wastes space until the
+// references are counted again.
+func (o *Object) oldManifest(ctx context.Context) *manifest {
+	m, err := readManifest(ctx, o.mo)
+	if err != nil {
+		fs.Debugf(o, "Can't read old manifest: %v", err)
+		return nil
+	}
+	return m
+}
+
+// undoRefs records that the references to the chunks in m were not
+// used after all
+//
+// If this fails the references are kept, which is safe but wastes
+// space until they are counted again.
+func (f *Fs) undoRefs(ctx context.Context, m *manifest) {
+	if err := f.journalRefs(ctx, nil, chunkHashes(m)); err != nil {
+		fs.Errorf(f, "Failed to undo chunk references: %v", err)
+	}
+}
+
+// Open the file for read. Call Close() on the returned io.ReadCloser
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	if err := o.readManifest(ctx); err != nil {
+		return nil, err
+	}
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.m.Size)
+		default:
+			if option.Mandatory() {
+				fs.Logf(o, "Unsupported mandatory option: %v", option)
+			}
+		}
+	}
+	if offset > o.m.Size {
+		offset = o.m.Size
+	}
+	if limit < 0 || offset+limit > o.m.Size {
+		limit = o.m.Size - offset
+	}
+	return &chunkReader{
+		ctx:    ctx,
+		o:      o,
+		offset: offset,
+		remain: limit,
+	}, nil
+}
+
+// chunkReader reads a range of a file by opening its chunks in turn
+type chunkReader struct {
+	ctx    context.Context
+	o      *Object
+	i      int           // index of the next chunk to open
+	start  int64         // offset in the file of chunk i
+	offset int64         // offset in the file of the next byte to read
+	remain int64         // bytes left to read
+	cur    io.ReadCloser // current chunk or nil
+	curN   int64         // bytes left to read from cur
+}
+
+// openNext opens the chunk containing offset
+func (r *chunkReader) openNext() error {
+	chunks := r.o.m.Chunks
+	for r.i < len(chunks) && r.start+chunks[r.i].Size <= r.offset {
+		r.start += chunks[r.i].Size
+		r.i++
+	}
+	if r.i >= len(chunks) {
+		return io.ErrUnexpectedEOF
+	}
+	ref := chunks[r.i]
+	co, err := r.o.f.chunks.NewObject(r.ctx, chunkName(ref.Hash))
+	if err != nil {
+		return fmt.Errorf("failed to find chunk %s: %w", ref.Hash, err)
+	}
+	if co.Size() != ref.Size {
+		return fmt.Errorf("chunk %s is corrupted: expecting size %d got %d", ref.Hash, ref.Size, co.Size())
+	}
+	skip := r.offset - r.start
+	n := min(ref.Size-skip, r.remain)
+	rc, err := co.Open(r.ctx, &fs.RangeOption{Start: skip, End: skip + n - 1})
+	if err != nil {
+		return err
+	}
+	r.cur = rc
+	r.curN = n
+	r.offset += n
+	r.start += ref.Size
+	r.i++
+	return nil
+}
+
+// Read bytes from the chunks
+func (r *chunkReader) Read(p []byte) (n int, err error) {
+	for {
+		if r.remain <= 0 {
+			return 0, io.EOF
+		}
+		if r.cur == nil {
+			if err = r.openNext(); err != nil {
+				return 0, err
+			}
+		}
+		if int64(len(p)) > r.remain {
+			p = p[:r.remain]
+		}
+		n, err = r.cur.Read(p)
+		r.remain -= int64(n)
+		r.curN -= int64(n)
+		if err == io.EOF {
+			if r.curN != 0 {
+				return n, io.ErrUnexpectedEOF
+			}
+			err = r.cur.Close()
+			r.cur = nil
+			if n > 0 || err != nil {
+				return n, err
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close the current chunk
+func (r *chunkReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	err := r.cur.Close()
+	r.cur = nil
+	return err
+}
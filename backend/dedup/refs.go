@@ -0,0 +1,324 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/lib/random"
+	"golang.org/x/sync/errgroup"
+)
+
+// The number of references to each chunk is stored in refsDir.
+//
+// indexName holds the counts as of the last CleanUp. Each change
+// since then is a new object in journalDir so writers never need to
+// read, modify and write a shared object. CleanUp folds the journal
+// into the index with the remote locked exclusively.
+const (
+	indexName         = "index.json"
+	journalDir        = "journal"
+	refsVersion       = 1
+	maxJSONSize       = 1024 * 1024 * 1024
+	journalTimeFormat = "20060102T150405.000000000Z"
+)
+
+// refsIndex is the contents of indexName
+type refsIndex struct {
+	Version int            `json:"ver"`
+	Applied []string       `json:"applied"` // journal entries counted in Refs
+	Refs    map[string]int `json:"refs"`    // references to each chunk hash
+}
+
+// refsDelta is the contents of a journal entry
+type refsDelta struct {
+	Version int      `json:"ver"`
+	Add     []string `json:"add,omitempty"`    // chunk hashes gaining a reference
+	Remove  []string `json:"remove,omitempty"` // chunk hashes losing a reference
+}
+
+// readJSON reads the JSON in o into v
+func readJSON(ctx context.Context, o fs.Object, v any) (err error) {
+	if o.Size() > maxJSONSize {
+		return fmt.Errorf("%s: too large", o.Remote())
+	}
+	rc, err := o.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(rc, &err)
+	return json.NewDecoder(io.LimitReader(rc, maxJSONSize)).Decode(v)
+}
+
+// writeJSON writes v as JSON to remote in f
+func writeJSON(ctx context.Context, f fs.Fs, remote string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	src := object.NewStaticObjectInfo(remote, time.Now(), int64(len(data)), true, nil, f)
+	_, err = f.Put(ctx, bytes.NewReader(data), src)
+	return err
+}
+
+// chunkHashes returns the hashes of the chunks in m or nil if m is nil
+func chunkHashes(m *manifest) []string {
+	if m == nil {
+		return nil
+	}
+	hashes := make([]string, len(m.Chunks))
+	for i, chunk := range m.Chunks {
+		hashes[i] = chunk.Hash
+	}
+	return hashes
+}
+
+// journalRefs records chunks gaining and losing references
+//
+// Call with a lock held.
+func (f *Fs) journalRefs(ctx context.Context, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	name := path.Join(journalDir, time.Now().UTC().Format(journalTimeFormat)+"-"+random.String(8)+".json")
+	err := writeJSON(ctx, f.refs, name, &refsDelta{
+		Version: refsVersion,
+		Add:     add,
+		Remove:  remove,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record chunk references: %w", err)
+	}
+	return nil
+}
+
+// readJournal lists the journal entries
+func (f *Fs) readJournal(ctx context.Context) ([]fs.Object, error) {
+	var journal []fs.Object
+	err := walk.ListR(ctx, f.refs, journalDir, true, 1, walk.ListObjects, func(entries fs.DirEntries) error {
+		entries.ForObject(func(o fs.Object) {
+			journal = append(journal, o)
+		})
+		return nil
+	})
+	if err == fs.ErrorDirNotFound {
+		err = nil
+	}
+	return journal, err
+}
+
+// readRefs reads the references to each chunk from the index and the
+// journal entries in journal.
+//
+// It returns errInvalidRefs if they need to be rebuilt from the
+// manifests.
+func (f *Fs) readRefs(ctx context.Context, journal []fs.Object) (map[string]int, error) {
+	o, err := f.refs.NewObject(ctx, indexName)
+	if err == fs.ErrorObjectNotFound {
+		return nil, fmt.Errorf("%w: no index", errInvalidRefs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var index refsIndex
+	if err = readJSON(ctx, o, &index); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidRefs, err)
+	}
+	if index.Version != refsVersion {
+		return nil, fmt.Errorf("%w: unsupported index version %d", errInvalidRefs, index.Version)
+	}
+	refs := index.Refs
+	if refs == nil {
+		refs = map[string]int{}
+	}
+	applied := make(map[string]bool, len(index.Applied))
+	for _, name := range index.Applied {
+		applied[name] = true
+	}
+	for _, entry := range journal {
+		if applied[path.Base(entry.Remote())] {
+			continue
+		}
+		var delta refsDelta
+		if err = readJSON(ctx, entry, &delta); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", errInvalidRefs, entry.Remote(), err)
+		}
+		for _, hash := range delta.Add {
+			refs[hash]++
+		}
+		for _, hash := range delta.Remove {
+			refs[hash]--
+			if refs[hash] < 0 {
+				return nil, fmt.Errorf("%w: chunk %s has a negative reference count", errInvalidRefs, hash)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// errInvalidRefs is returned if the references can't be read from
+// the index and the journal
+var errInvalidRefs = errors.New("chunk reference index invalid")
+
+// CleanUp removes chunks which are no longer referenced by any file
+//
+// It locks the remote, so it fails if files are being changed, then
+// adds up the changes to the references recorded since the last
+// CleanUp. If the references are missing or don't add up they are
+// counted again from every manifest in the remote (not just those
+// under this root). Finally the chunks with no references are
+// removed.
+func (f *Fs) CleanUp(ctx context.Context) error {
+	// Give up our own shared lock if nothing is using it
+	f.shared.releaseIdle()
+	l, err := f.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer l.release(context.Background())
+	// Don't trust the chunks we have seen as they may be removed
+	defer f.shared.known.Clear()
+
+	journal, err := f.readJournal(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk reference journal: %w", err)
+	}
+	refs, err := f.readRefs(ctx, journal)
+	if errors.Is(err, errInvalidRefs) {
+		fs.Infof(f, "Counting chunk references from the manifests: %v", err)
+		refs, err = f.countReferences(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to count chunk references - not deleting anything: %w", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		deleted int
+		kept    int
+		found   = map[string]bool{}
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(fs.GetConfig(ctx).Checkers)
+	err = walk.ListR(ctx, f.chunks, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		entries.ForObject(func(o fs.Object) {
+			hashHex := path.Base(o.Remote())
+			mu.Lock()
+			referenced := refs[hashHex] > 0
+			if referenced {
+				found[hashHex] = true
+				kept++
+			} else {
+				delete(refs, hashHex)
+			}
+			mu.Unlock()
+			if referenced {
+				return
+			}
+			g.Go(func() error {
+				if err := operations.DeleteFile(gCtx, o); err != nil {
+					return err
+				}
+				mu.Lock()
+				deleted++
+				mu.Unlock()
+				return nil
+			})
+		})
+		return nil
+	})
+	if err == fs.ErrorDirNotFound {
+		err = nil
+	}
+	if gErr := g.Wait(); err == nil {
+		err = gErr
+	}
+	fs.Infof(f, "Chunks: %d referenced, %d unreferenced removed", kept, deleted)
+	if err != nil {
+		return err
+	}
+	for hashHex, n := range refs {
+		if n <= 0 {
+			delete(refs, hashHex)
+		} else if !found[hashHex] {
+			fs.Errorf(f, "Chunk %s is referenced %d times but is missing", hashHex, n)
+		}
+	}
+	if fs.GetConfig(ctx).DryRun {
+		return nil
+	}
+	return f.writeRefs(ctx, refs, journal)
+}
+
+// writeRefs writes the index with the journal entries in journal
+// applied then removes them.
+func (f *Fs) writeRefs(ctx context.Context, refs map[string]int, journal []fs.Object) error {
+	index := refsIndex{
+		Version: refsVersion,
+		Applied: make([]string, len(journal)),
+		Refs:    refs,
+	}
+	for i, entry := range journal {
+		index.Applied[i] = path.Base(entry.Remote())
+	}
+	if err := writeJSON(ctx, f.refs, indexName, &index); err != nil {
+		return fmt.Errorf("failed to write chunk reference index: %w", err)
+	}
+	// The index says these have been applied so failing to
+	// remove them is harmless
+	for _, entry := range journal {
+		if err := entry.Remove(ctx); err != nil {
+			fs.Errorf(entry, "Failed to remove chunk reference journal entry: %v", err)
+		}
+	}
+	return nil
+}
+
+// countReferences reads every manifest in the remote returning the
+// number of references to each chunk hash.
+func (f *Fs) countReferences(ctx context.Context) (map[string]int, error) {
+	filesFs, err := cache.Get(ctx, fspath.JoinRootPath(f.opt.Remote, filesDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make remote for manifests: %w", err)
+	}
+	var mu sync.Mutex
+	refs := map[string]int{}
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(fs.GetConfig(ctx).Checkers)
+	err = walk.ListR(ctx, filesFs, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		entries.ForObject(func(mo fs.Object) {
+			g.Go(func() error {
+				m, err := readManifest(gCtx, mo)
+				if err != nil {
+					return fmt.Errorf("%s: %w", mo.Remote(), err)
+				}
+				mu.Lock()
+				for _, chunk := range m.Chunks {
+					refs[chunk.Hash]++
+				}
+				mu.Unlock()
+				return nil
+			})
+		})
+		return nil
+	})
+	if err == fs.ErrorDirNotFound {
+		err = nil
+	}
+	if gErr := g.Wait(); err == nil {
+		err = gErr
+	}
+	return refs, err
+}
@@ -63,7 +63,7 @@ func (dp *dataverseProvider) ListEntries(ctx context.Context) (entries []*Object
 		Path:       strings.TrimLeft(filesURL.EscapedPath(), "/"),
 		Parameters: filesURL.Query(),
 	}
-	err = dp.f.pacer.Call(func() (bool, error) {
+	err = dp.f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = dp.f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, res, err)
 	})
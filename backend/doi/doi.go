@@ -175,7 +175,7 @@ func resolveDoiURL(ctx context.Context, srv *rest.Client, pacer *fs.Pacer, opt *
 		Path:       "/handles/" + opt.Doi,
 		Parameters: params,
 	}
-	err = pacer.Call(func() (bool, error) {
+	err = pacer.CallContext(ctx, func() (bool, error) {
 		res, err := srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, res, err)
 	})
@@ -522,7 +522,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Options: options,
 	}
 	var res *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err)
 	})
@@ -535,7 +535,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		newURL, err := res.Location()
 		if err == nil {
 			opts.RootURL = newURL.String()
-			err = o.fs.pacer.Call(func() (bool, error) {
+			err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = o.fs.srv.Call(ctx, &opts)
 				return shouldRetry(ctx, res, err)
 			})
@@ -31,7 +31,7 @@ func resolveInvenioEndpoint(ctx context.Context, srv *rest.Client, pacer *fs.Pac
 		Method:  "GET",
 		RootURL: resolvedURL.String(),
 	}
-	err = pacer.Call(func() (bool, error) {
+	err = pacer.CallContext(ctx, func() (bool, error) {
 		res, err = srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err)
 	})
@@ -85,7 +85,7 @@ func checkInvenioAPIURL(ctx context.Context, srv *rest.Client, pacer *fs.Pacer,
 		Method:  "GET",
 		RootURL: resolvedURL.String(),
 	}
-	err = pacer.Call(func() (bool, error) {
+	err = pacer.CallContext(ctx, func() (bool, error) {
 		res, err := srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, res, err)
 	})
@@ -124,7 +124,7 @@ func (ip *invenioProvider) ListEntries(ctx context.Context) (entries []*Object,
 		Method: "GET",
 		Path:   strings.TrimLeft(filesURL.EscapedPath(), "/"),
 	}
-	err = ip.f.pacer.Call(func() (bool, error) {
+	err = ip.f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := ip.f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, res, err)
 	})
@@ -30,7 +30,7 @@ func resolveZenodoEndpoint(ctx context.Context, srv *rest.Client, pacer *fs.Pace
 		Method:  "GET",
 		RootURL: endpointURL.String(),
 	}
-	err = pacer.Call(func() (bool, error) {
+	err = pacer.CallContext(ctx, func() (bool, error) {
 		res, err := srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, res, err)
 	})
@@ -48,6 +48,7 @@ import (
 	"github.com/rclone/rclone/lib/readers"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/errgroup"
 	drive_v2 "google.golang.org/api/drive/v2"
 	drive "google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
@@ -959,7 +960,7 @@ func containsString(slice []string, s string) bool {
 
 // getFile returns drive.File for the ID passed and fields passed in
 func (f *Fs) getFile(ctx context.Context, ID string, fields googleapi.Field) (info *drive.File, err error) {
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = f.svc.Files.Get(ID).
 			Fields(fields).
 			SupportsAllDrives(true).
@@ -1105,7 +1106,7 @@ func (f *Fs) list(ctx context.Context, dirIDs []string, title string, directorie
 OUTER:
 	for {
 		var files *drive.FileList
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			files, err = list.Fields(googleapi.Field(fields)).Context(ctx).Do()
 			return f.shouldRetry(ctx, err)
 		})
@@ -1750,7 +1751,7 @@ func (f *Fs) createDir(ctx context.Context, pathID, leaf string, metadata fs.Met
 			return nil, fmt.Errorf("create dir: failed to update metadata: %w", err)
 		}
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = f.svc.Files.Create(createInfo).
 			Fields(f.getFileFields(ctx)).
 			SupportsAllDrives(true).
@@ -1780,7 +1781,7 @@ func (f *Fs) updateDir(ctx context.Context, dirID string, metadata fs.Metadata)
 	if err != nil {
 		return nil, fmt.Errorf("update dir: failed to update metadata from source object: %w", err)
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = f.svc.Files.Update(dirID, updateInfo).
 			Fields(f.getFileFields(ctx)).
 			SupportsAllDrives(true).
@@ -1838,7 +1839,7 @@ func (f *Fs) fetchFormats(ctx context.Context) {
 	fetchFormatsOnce.Do(func() {
 		var about *drive.About
 		var err error
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			about, err = f.svc.About.Get().
 				Fields("exportFormats,importFormats").
 				Context(ctx).Do()
@@ -2000,6 +2001,183 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 	return entries, nil
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t using the file revisions.
+//
+// Drive only keeps old revisions for a limited time unless they are
+// marked to be kept forever, so files whose revisions have been
+// purged won't be shown. Trashed files aren't shown and Google
+// documents are only shown if they haven't been modified since t.
+//
+// The revisions of the files are read --checkers at a time.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
+	if err != nil {
+		return nil, err
+	}
+	directoryID = actualID(directoryID)
+
+	var (
+		items []*drive.File
+		iErr  error
+	)
+	_, err = f.list(ctx, []string{directoryID}, "", false, false, false, false, func(item *drive.File) bool {
+		entry, err := f.itemToDirEntry(ctx, path.Join(dir, item.Name), item)
+		if err != nil {
+			iErr = err
+			return true
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+			items = append(items, item)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if iErr != nil {
+		return nil, iErr
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(f.ci.Checkers)
+	for i := range entries {
+		g.Go(func() (err error) {
+			entries[i], err = f.entryAt(gCtx, entries[i], items[i], t)
+			return err
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	// Remove the entries which didn't exist at t
+	return slices.DeleteFunc(entries, func(entry fs.DirEntry) bool { return entry == nil }), nil
+}
+
+// NewObjectAt finds the Object at remote as it was at time t using
+// its revisions, with the same limitations as ListAt. If it can't be
+// found it returns the error fs.ErrorObjectNotFound.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	if strings.HasSuffix(remote, "/") {
+		return nil, fs.ErrorIsDir
+	}
+	info, extension, exportName, exportMimeType, isDocument, err := f.getRemoteInfoWithExport(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	remote = remote[:len(remote)-len(extension)]
+	obj, err := f.newObjectWithExportInfo(ctx, remote, info, extension, exportName, exportMimeType, isDocument)
+	if err != nil {
+		return nil, err
+	} else if obj == nil {
+		return nil, fs.ErrorObjectNotFound
+	}
+	entry, err := f.entryAt(ctx, obj, info, t)
+	if err != nil {
+		return nil, err
+	} else if entry == nil {
+		return nil, fs.ErrorObjectNotFound
+	}
+	return entry.(fs.Object), nil
+}
+
+// entryAt returns entry, made from item, as it was at time t or nil
+// if it didn't exist then.
+//
+// This reads the revisions of objects.
+func (f *Fs) entryAt(ctx context.Context, entry fs.DirEntry, item *drive.File, t time.Time) (fs.DirEntry, error) {
+	if timeAfter(item.CreatedTime, t) {
+		return nil, nil
+	}
+	switch x := entry.(type) {
+	case *Object:
+		o, err := x.revisionAt(ctx, t)
+		if err != nil || o == nil {
+			return nil, err
+		}
+		return o, nil
+	case *documentObject, *linkObject:
+		if timeAfter(item.ModifiedTime, t) {
+			fs.Debugf(entry, "Skipping document modified after %v", t)
+			return nil, nil
+		}
+	}
+	return entry, nil
+}
+
+// timeAfter returns true if the RFC3339 time s is after t
+//
+// Times which can't be parsed are treated as being before t.
+func timeAfter(s string, t time.Time) bool {
+	when, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return false
+	}
+	return when.After(t)
+}
+
+// maxRevisionsPageSize is the largest page of revisions drive returns
+const maxRevisionsPageSize = 1000
+
+// revisionAt returns the Object as it was at time t using its
+// revisions, or nil if it didn't exist then.
+func (o *Object) revisionAt(ctx context.Context, t time.Time) (fs.Object, error) {
+	var (
+		found, latest         *drive.Revision
+		foundTime, latestTime time.Time
+		pageToken             string
+	)
+	for {
+		var revisions *drive.RevisionList
+		err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
+			var err error
+			revisions, err = o.fs.svc.Revisions.List(actualID(o.id)).
+				Fields("nextPageToken,revisions(id,modifiedTime,md5Checksum,size)").
+				PageSize(maxRevisionsPageSize).
+				PageToken(pageToken).
+				Context(ctx).Do()
+			return o.fs.shouldRetry(ctx, err)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list revisions of %q: %w", o.remote, err)
+		}
+		for _, revision := range revisions.Revisions {
+			modTime, err := time.Parse(time.RFC3339, revision.ModifiedTime)
+			if err != nil {
+				fs.Debugf(o, "Ignoring revision %q with bad time: %v", revision.Id, err)
+				continue
+			}
+			if latest == nil || modTime.After(latestTime) {
+				latest, latestTime = revision, modTime
+			}
+			if !modTime.After(t) && (found == nil || modTime.After(foundTime)) {
+				found, foundTime = revision, modTime
+			}
+		}
+		if revisions.NextPageToken == "" {
+			break
+		}
+		pageToken = revisions.NextPageToken
+	}
+	if found == nil {
+		return nil, nil
+	}
+	if found == latest {
+		return o, nil
+	}
+	revisionObject := *o
+	revisionObject.url = fmt.Sprintf("%sfiles/%s/revisions/%s?alt=media", o.fs.svc.BasePath, actualID(o.id), found.Id)
+	revisionObject.md5sum = strings.ToLower(found.Md5Checksum)
+	revisionObject.sha1sum = ""
+	revisionObject.sha256sum = ""
+	revisionObject.bytes = found.Size
+	revisionObject.modifiedDate = found.ModifiedTime
+	revisionObject.v2Download = false
+	return &revisionObject, nil
+}
+
 // listREntry is a task to be executed by a litRRunner
 type listREntry struct {
 	id, path string
@@ -2496,7 +2674,7 @@ func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo,
 	if size >= 0 && size < int64(f.opt.UploadCutoff) {
 		// Make the API request to upload metadata and file data.
 		// Don't retry, return a retry error instead
-		err = f.pacer.CallNoRetry(func() (bool, error) {
+		err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			info, err = f.svc.Files.Create(createInfo).
 				Media(in, googleapi.ContentType(srcMimeType), googleapi.ChunkSize(0)).
 				Fields(partialFields).
@@ -2555,7 +2733,7 @@ func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 		for _, info := range infos {
 			fs.Infof(srcDir, "merging %q", info.Name)
 			// Move the file into the destination
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				_, err = f.svc.Files.Update(info.Id, nil).
 					RemoveParents(srcDir.ID()).
 					AddParents(dstDir.ID()).
@@ -2639,7 +2817,7 @@ func (f *Fs) DirSetModTime(ctx context.Context, dir string, modTime time.Time) e
 
 // delete a file or directory unconditionally by ID
 func (f *Fs) delete(ctx context.Context, id string, useTrash bool) error {
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		if useTrash {
 			info := drive.File{
@@ -2797,7 +2975,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 
 	var info *drive.File
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		copy := f.svc.Files.Copy(id, createInfo).
 			Fields(f.getFileFields(ctx)).
 			SupportsAllDrives(true).
@@ -2906,7 +3084,7 @@ func (f *Fs) CleanUp(ctx context.Context) error {
 		_, err = f.cleanupTeamDrive(ctx, "", directoryID)
 		return err
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		err := f.svc.Files.EmptyTrash().Context(ctx).Do()
 		return f.shouldRetry(ctx, err)
 	})
@@ -2923,7 +3101,7 @@ func (f *Fs) teamDriveOK(ctx context.Context) (err error) {
 		return nil
 	}
 	var td *drive.Drive
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		td, err = f.svc.Drives.Get(f.opt.TeamDriveID).Fields("name,id,capabilities,createdTime,restrictions").Context(ctx).Do()
 		return f.shouldRetry(ctx, err)
 	})
@@ -2946,7 +3124,7 @@ func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	}
 	var about *drive.About
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		about, err = f.svc.About.Get().Fields("storageQuota").Context(ctx).Do()
 		return f.shouldRetry(ctx, err)
 	})
@@ -3020,7 +3198,7 @@ func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object,
 
 	// Do the move
 	var info *drive.File
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = f.svc.Files.Update(shortcutID(srcObj.id), dstInfo).
 			RemoveParents(srcParentID).
 			AddParents(dstParents).
@@ -3062,7 +3240,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 		Type:               "anyone",
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		// TODO: On TeamDrives this might fail if lacking permissions to change ACLs.
 		// Need to either check `canShare` attribute on the object or see if a sufficient permission is already present.
 		_, err = f.svc.Permissions.Create(id, permission).
@@ -3105,7 +3283,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 	patch := drive.File{
 		Name: dstLeaf,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.svc.Files.Update(shortcutID(srcID), &patch).
 			RemoveParents(srcDirectoryID).
 			AddParents(dstDirectoryID).
@@ -3173,7 +3351,7 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 
 func (f *Fs) changeNotifyStartPageToken(ctx context.Context) (pageToken string, err error) {
 	var startPageToken *drive.StartPageToken
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		changes := f.svc.Changes.GetStartPageToken().SupportsAllDrives(true)
 		if f.isTeamDrive {
 			changes.DriveId(f.opt.TeamDriveID)
@@ -3192,7 +3370,7 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	for {
 		var changeList *drive.ChangeList
 
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			changesCall := f.svc.Changes.List(pageToken).
 				Fields("nextPageToken,newStartPageToken,changes(fileId,file(name,parents,mimeType))")
 			if f.opt.ListChunk > 0 {
@@ -3274,6 +3452,123 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	}
 }
 
+// ChangesSince calls notifyFunc with each path which has changed since
+// the page token was issued and returns the next page token.
+//
+// Unlike ChangeNotify this finds the paths of changed items which are
+// not in the directory cache. Items removed without being trashed
+// first can't be found, so the root is reported as changed if there
+// are any of those.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if token == "" {
+		return f.changeNotifyStartPageToken(ctx)
+	}
+	rootID, err := f.dirCache.RootID(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	// paths of the directories found - "" and false if outside the root
+	type dirPath struct {
+		path   string
+		inside bool
+	}
+	dirs := map[string]dirPath{rootID: {"", true}}
+	var findDir func(ID string, depth int) (string, bool, error)
+	findDir = func(ID string, depth int) (string, bool, error) {
+		if p, ok := dirs[ID]; ok {
+			return p.path, p.inside, nil
+		}
+		if p, ok := f.dirCache.GetInv(ID); ok {
+			return p, true, nil
+		}
+		if depth > 100 {
+			return "", false, fmt.Errorf("directory %q is nested too deeply", ID)
+		}
+		info, err := f.getFile(ctx, ID, "name,parents")
+		if isGoogleError(err, "notFound") {
+			dirs[ID] = dirPath{}
+			return "", false, nil
+		} else if err != nil {
+			return "", false, err
+		}
+		var p dirPath
+		if len(info.Parents) > 0 {
+			parentPath, inside, err := findDir(info.Parents[0], depth+1)
+			if err != nil {
+				return "", false, err
+			}
+			if inside {
+				p = dirPath{path.Join(parentPath, f.opt.Enc.ToStandardName(info.Name)), true}
+			}
+		}
+		dirs[ID] = p
+		return p.path, p.inside, nil
+	}
+	pageToken := token
+	for {
+		var changeList *drive.ChangeList
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
+			changesCall := f.svc.Changes.List(pageToken).
+				Fields("nextPageToken,newStartPageToken,changes(fileId,removed,file(name,parents,mimeType))")
+			if f.opt.ListChunk > 0 {
+				changesCall.PageSize(f.opt.ListChunk)
+			}
+			changesCall.SupportsAllDrives(true)
+			changesCall.IncludeItemsFromAllDrives(true)
+			if f.isTeamDrive {
+				changesCall.DriveId(f.opt.TeamDriveID)
+			}
+			if f.rootFolderID == "appDataFolder" {
+				changesCall.Spaces("appDataFolder")
+			}
+			changesCall.RestrictToMyDrive(!f.opt.SharedWithMe)
+			changeList, err = changesCall.Context(ctx).Do()
+			return f.shouldRetry(ctx, err)
+		})
+		var gerr *googleapi.Error
+		if errors.As(err, &gerr) && (gerr.Code == http.StatusBadRequest || gerr.Code == http.StatusNotFound) {
+			return "", fs.ErrorChangeTokenInvalid
+		} else if err != nil {
+			return "", err
+		}
+		for _, change := range changeList.Changes {
+			// the old path if it was a directory we know about
+			if p, ok := f.dirCache.GetInv(change.FileId); ok {
+				notifyFunc(p, fs.EntryDirectory)
+			}
+			if change.Removed || change.File == nil {
+				if _, ok := f.dirCache.GetInv(change.FileId); !ok {
+					fs.Debugf(f, "Can't find path of removed item %q - treating everything as changed", change.FileId)
+					notifyFunc("", fs.EntryDirectory)
+				}
+				continue
+			}
+			entryType := fs.EntryObject
+			if change.File.MimeType == driveFolderType {
+				entryType = fs.EntryDirectory
+			}
+			name := f.opt.Enc.ToStandardName(change.File.Name)
+			for _, parent := range change.File.Parents {
+				parentPath, inside, err := findDir(parent, 0)
+				if err != nil {
+					return "", err
+				}
+				if inside {
+					notifyFunc(path.Join(parentPath, name), entryType)
+				}
+			}
+		}
+		switch {
+		case changeList.NewStartPageToken != "":
+			return changeList.NewStartPageToken, nil
+		case changeList.NextPageToken != "":
+			pageToken = changeList.NextPageToken
+		default:
+			return pageToken, nil
+		}
+	}
+}
+
 // DirCacheFlush resets the directory cache - used in testing as an
 // optional interface
 func (f *Fs) DirCacheFlush() {
@@ -3400,7 +3695,7 @@ func (f *Fs) makeShortcut(ctx context.Context, srcPath string, dstFs *Fs, dstPat
 	}
 
 	var info *drive.File
-	err = dstFs.pacer.Call(func() (bool, error) {
+	err = dstFs.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = dstFs.svc.Files.Create(createInfo).
 			Fields(partialFields).
 			SupportsAllDrives(true).
@@ -3424,7 +3719,7 @@ func (f *Fs) listTeamDrives(ctx context.Context) (drives []*drive.Drive, err err
 	var defaultFs Fs // default Fs with default Options
 	for {
 		var teamDrives *drive.DriveList
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			teamDrives, err = listTeamDrives.Context(ctx).Do()
 			return defaultFs.shouldRetry(ctx, err)
 		})
@@ -3464,7 +3759,7 @@ func (f *Fs) unTrash(ctx context.Context, dir string, directoryID string, recurs
 				ForceSendFields: []string{"Trashed"}, // necessary to set false value
 				Trashed:         false,
 			}
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				_, err := f.svc.Files.Update(item.Id, &update).
 					SupportsAllDrives(true).
 					Fields("trashed").
@@ -3573,7 +3868,7 @@ func (f *Fs) queryFn(ctx context.Context, query string, fn func(*drive.File)) (e
 	fields := fmt.Sprintf("files(%s),nextPageToken,incompleteSearch", f.getFileFields(ctx))
 	for {
 		var files *drive.FileList
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			files, err = list.Fields(googleapi.Field(fields)).Context(ctx).Do()
 			return f.shouldRetry(ctx, err)
 		})
@@ -3623,7 +3918,7 @@ func (f *Fs) rescue(ctx context.Context, dirID string, delete bool) (err error)
 			operations.SyncPrintf("%q, %q\n", item.Name, item.Id)
 		} else {
 			fs.Infof(item.Name, "Rescuing orphan %q", item.Id)
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				_, err = f.svc.Files.Update(item.Id, nil).
 					AddParents(dirID).
 					Fields(f.getFileFields(ctx)).
@@ -4141,7 +4436,7 @@ func (o *baseObject) SetModTime(ctx context.Context, modTime time.Time) error {
 	}
 	// Set modified date
 	var info *drive.File
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		info, err = o.fs.svc.Files.Update(actualID(o.id), updateInfo).
 			Fields(partialFields).
@@ -4186,7 +4481,7 @@ func (o *baseObject) httpResponse(ctx context.Context, url, method string, optio
 		delete(req.Header, "Range")
 	}
 	o.addResourceKey(req.Header)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.client.Do(req)
 		if err == nil {
 			err = googleapi.CheckResponse(res)
@@ -4282,7 +4577,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 	if o.v2Download {
 		var v2File *drive_v2.File
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			v2File, err = o.fs.v2Svc.Files.Get(actualID(o.id)).
 				Fields("downloadUrl").
 				SupportsAllDrives(true).
@@ -4364,7 +4659,7 @@ func (o *baseObject) update(ctx context.Context, updateInfo *drive.File, uploadM
 	size := src.Size()
 	if size >= 0 && size < int64(o.fs.opt.UploadCutoff) {
 		// Don't retry, return a retry error instead
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			info, err = o.fs.svc.Files.Update(actualID(o.id), updateInfo).
 				Media(in, googleapi.ContentType(uploadMimeType), googleapi.ChunkSize(0)).
 				Fields(partialFields).
@@ -4614,9 +4909,12 @@ var (
 	_ fs.Commander       = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.PutUncheckeder  = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListAter        = (*Fs)(nil)
+	_ fs.NewObjectAter   = (*Fs)(nil)
 	_ fs.MergeDirser     = (*Fs)(nil)
 	_ fs.DirSetModTimer  = (*Fs)(nil)
 	_ fs.MkdirMetadataer = (*Fs)(nil)
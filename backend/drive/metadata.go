@@ -115,7 +115,7 @@ func (f *Fs) getPermission(ctx context.Context, fileID, permissionID string, use
 		}
 	}
 	fs.Debugf(f, "Fetching permission %q", permissionID)
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		perm, err = f.svc.Permissions.Get(fileID, permissionID).
 			Fields(permissionsFields).
 			SupportsAllDrives(true).
@@ -145,7 +145,7 @@ func (f *Fs) setPermissions(ctx context.Context, info *drive.File, permissions [
 			continue
 		}
 		cleanPermissionForWrite(perm)
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.svc.Permissions.Create(info.Id, perm).
 				SupportsAllDrives(true).
 				SendNotificationEmail(false).
@@ -226,7 +226,7 @@ func (f *Fs) getLabels(ctx context.Context, fileID string) (labels []*drive.Labe
 		Context(ctx)
 	for {
 		var info *drive.LabelList
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			info, err = listLabels.Do()
 			return f.shouldRetry(ctx, err)
 		})
@@ -257,7 +257,7 @@ func (f *Fs) setLabels(ctx context.Context, info *drive.File, labels []*drive.La
 			LabelId:            label.Id,
 		})
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.svc.Files.ModifyLabels(info.Id, &req).
 			Context(ctx).Do()
 		return f.shouldRetry(ctx, err)
@@ -478,7 +478,7 @@ func (f *Fs) setOwner(ctx context.Context, info *drive.File, owner string) (err
 		// extra information required for an `anyone` type.
 		Type: "user",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.svc.Permissions.Create(info.Id, &perm).
 			SupportsAllDrives(true).
 			TransferOwnership(true).
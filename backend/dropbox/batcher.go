@@ -18,7 +18,7 @@ func (f *Fs) finishBatch(ctx context.Context, items []*files.UploadSessionFinish
 	var arg = &files.UploadSessionFinishBatchArg{
 		Entries: items,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		complete, err = f.srv.UploadSessionFinishBatchV2(arg)
 		if retry, err := shouldRetryExclude(ctx, err); !retry {
 			return retry, err
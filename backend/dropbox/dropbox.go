@@ -605,7 +605,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	} else if strings.HasPrefix(root, "/") {
 		// If root starts with / then use the actual root
 		var acc *users.FullAccount
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			acc, err = f.users.GetCurrentAccount()
 			return shouldRetry(ctx, err)
 		})
@@ -668,7 +668,7 @@ type getMetadataResult struct {
 
 // getMetadata gets the metadata for a file or directory
 func (f *Fs) getMetadata(ctx context.Context, objPath string) (res getMetadataResult) {
-	res.err = f.pacer.Call(func() (bool, error) {
+	res.err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res.entry, res.err = f.srv.GetMetadata(&files.GetMetadataArg{
 			Path: f.opt.Enc.FromStandardPath(objPath),
 		})
@@ -842,7 +842,7 @@ func (f *Fs) listSharedFolders(ctx context.Context) (entries fs.DirEntries, err
 			arg := sharing.ListFoldersArgs{
 				Limit: 100,
 			}
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.sharing.ListFolders(&arg)
 				return shouldRetry(ctx, err)
 			})
@@ -854,7 +854,7 @@ func (f *Fs) listSharedFolders(ctx context.Context) (entries fs.DirEntries, err
 			arg := sharing.ListFoldersContinueArg{
 				Cursor: res.Cursor,
 			}
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.sharing.ListFoldersContinue(&arg)
 				return shouldRetry(ctx, err)
 			})
@@ -899,7 +899,7 @@ func (f *Fs) mountSharedFolder(ctx context.Context, id string) error {
 	arg := sharing.MountFolderArg{
 		SharedFolderId: id,
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := f.sharing.MountFolder(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -916,7 +916,7 @@ func (f *Fs) listReceivedFiles(ctx context.Context) (entries fs.DirEntries, err
 			arg := sharing.ListFilesArg{
 				Limit: 100,
 			}
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.sharing.ListReceivedFiles(&arg)
 				return shouldRetry(ctx, err)
 			})
@@ -928,7 +928,7 @@ func (f *Fs) listReceivedFiles(ctx context.Context) (entries fs.DirEntries, err
 			arg := sharing.ListFilesContinueArg{
 				Cursor: res.Cursor,
 			}
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.sharing.ListReceivedFilesContinue(&arg)
 				return shouldRetry(ctx, err)
 			})
@@ -1003,7 +1003,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 			if root == "/" {
 				arg.Path = "" // Specify root folder as empty string
 			}
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.srv.ListFolder(arg)
 				return shouldRetry(ctx, err)
 			})
@@ -1021,7 +1021,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 			arg := files.ListFolderContinueArg{
 				Cursor: res.Cursor,
 			}
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				res, err = f.srv.ListFolderContinue(&arg)
 				return shouldRetry(ctx, err)
 			})
@@ -1119,7 +1119,7 @@ func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	if cErr := checkPathLength(arg2.Path); cErr != nil {
 		return cErr
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.srv.CreateFolderV2(&arg2)
 		return shouldRetry(ctx, err)
 	})
@@ -1151,7 +1151,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) (err error)
 			arg.Path = "" // Specify root folder as empty string
 		}
 		var res *files.ListFolderResult
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			res, err = f.srv.ListFolder(arg)
 			return shouldRetry(ctx, err)
 		})
@@ -1164,7 +1164,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) (err error)
 	}
 
 	// remove it
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.srv.DeleteV2(&files.DeleteArg{Path: encRoot})
 		return shouldRetry(ctx, err)
 	})
@@ -1223,7 +1223,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (dst fs.Obj
 		},
 	}
 	var result *files.RelocationResult
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		result, err = f.srv.CopyV2(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -1284,7 +1284,7 @@ func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	var err error
 	var result *files.RelocationResult
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		result, err = f.srv.MoveV2(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -1328,7 +1328,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	}
 
 	var linkRes sharing.IsSharedLinkMetadata
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		linkRes, err = f.sharing.CreateSharedLinkWithSettings(&createArg)
 		return shouldRetry(ctx, err)
 	})
@@ -1337,7 +1337,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 		// Some plans can't create links with expiry
 		fs.Debugf(absPath, "can't create link with expiry, trying without")
 		createArg.Settings.Expires = nil
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			linkRes, err = f.sharing.CreateSharedLinkWithSettings(&createArg)
 			return shouldRetry(ctx, err)
 		})
@@ -1351,7 +1351,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 			DirectOnly: true,
 		}
 		var listRes *sharing.ListSharedLinksResult
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			listRes, err = f.sharing.ListSharedLinks(&listArg)
 			return shouldRetry(ctx, err)
 		})
@@ -1412,7 +1412,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 			ToPath:   f.opt.Enc.FromStandardPath(dstPath),
 		},
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = f.srv.MoveV2(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -1426,7 +1426,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 // About gets quota information
 func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 	var q *users.SpaceUsage
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		q, err = f.users.GetSpaceUsage()
 		return shouldRetry(ctx, err)
 	})
@@ -1506,7 +1506,7 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 func (f *Fs) changeNotifyCursor(ctx context.Context) (cursor string, err error) {
 	var startCursor *files.ListFolderGetLatestCursorResult
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		arg := files.NewListFolderArg(f.opt.Enc.FromStandardPath(f.slashRoot))
 		arg.Recursive = true
 
@@ -1541,7 +1541,7 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 		fs.Debugf(f, "Decreasing poll interval to maximum 480s")
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		args := files.ListFolderLongpollArg{
 			Cursor:  cursor,
 			Timeout: timeout,
@@ -1563,13 +1563,19 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 		time.Sleep(time.Duration(res.Backoff) * time.Second)
 	}
 
+	return f.changeNotifyList(ctx, notifyFunc, cursor)
+}
+
+// changeNotifyList calls notifyFunc for each change since cursor and
+// returns the new cursor
+func (f *Fs) changeNotifyList(ctx context.Context, notifyFunc func(string, fs.EntryType), cursor string) (newCursor string, err error) {
 	for {
 		var changeList *files.ListFolderResult
 
 		arg := files.ListFolderContinueArg{
 			Cursor: cursor,
 		}
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			changeList, err = f.srv.ListFolderContinue(&arg)
 			return shouldRetry(ctx, err)
 		})
@@ -1606,6 +1612,23 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	return cursor, nil
 }
 
+// ChangesSince calls notifyFunc with each path which has changed since
+// the cursor token was issued and returns the next cursor.
+//
+// Deleted folders are reported as objects as dropbox doesn't say
+// what type of item was deleted.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if token == "" {
+		return f.changeNotifyCursor(ctx)
+	}
+	newToken, err = f.changeNotifyList(ctx, notifyFunc, token)
+	var apiErr files.ListFolderContinueAPIError
+	if errors.As(err, &apiErr) && apiErr.EndpointError != nil && apiErr.EndpointError.Tag == files.ListFolderContinueErrorReset {
+		return "", fs.ErrorChangeTokenInvalid
+	}
+	return newToken, err
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() hash.Set {
 	return hash.Set(DbHashType)
@@ -1814,7 +1837,7 @@ func (o *Object) export(ctx context.Context) (in io.ReadCloser, err error) {
 
 	arg := files.ExportArg{Path: o.id, ExportFormat: string(o.exportAPIFormat)}
 	var exportResult *files.ExportResult
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		exportResult, in, err = o.fs.srv.Export(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -1836,7 +1859,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		arg := sharing.GetSharedLinkMetadataArg{
 			Url: o.url,
 		}
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			_, in, err = o.fs.sharing.GetSharedLinkFile(&arg)
 			return shouldRetry(ctx, err)
 		})
@@ -1856,7 +1879,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:         o.id,
 		ExtraHeaders: headers,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, in, err = o.fs.srv.Download(&arg)
 		return shouldRetry(ctx, err)
 	})
@@ -1880,7 +1903,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 func (o *Object) uploadChunked(ctx context.Context, in0 io.Reader, commitInfo *files.CommitInfo, size int64) (entry *files.FileMetadata, err error) {
 	// start upload
 	var res *files.UploadSessionStartResult
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.srv.UploadSessionStart(&files.UploadSessionStartArg{}, nil)
 		return shouldRetry(ctx, err)
 	})
@@ -1913,7 +1936,7 @@ func (o *Object) uploadChunked(ctx context.Context, in0 io.Reader, commitInfo *f
 
 		chunk := readers.NewRepeatableLimitReaderBuffer(in, buf, chunkSize)
 		skip := int64(0)
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			// seek to the start in case this is a retry
 			if _, err = chunk.Seek(skip, io.SeekStart); err != nil {
 				return false, err
@@ -1976,7 +1999,7 @@ func (o *Object) uploadChunked(ctx context.Context, in0 io.Reader, commitInfo *f
 		return o.fs.batcher.Commit(ctx, o.remote, args)
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		entry, err = o.fs.srv.UploadSessionFinish(args, nil)
 		if retry, err := shouldRetryExclude(ctx, err); !retry {
 			return retry, err
@@ -2044,7 +2067,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	if size > int64(o.fs.opt.ChunkSize) || size < 0 || o.fs.batcher.Batching() {
 		entry, err = o.uploadChunked(ctx, in, commitInfo, size)
 	} else {
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			entry, err = o.fs.srv.Upload(&files.UploadArg{CommitInfo: *commitInfo}, in)
 			return shouldRetry(ctx, err)
 		})
@@ -2069,7 +2092,7 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 	if o.fs.opt.SharedFiles || o.fs.opt.SharedFolders {
 		return errNotSupportedInSharedMode
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = o.fs.srv.DeleteV2(&files.DeleteArg{
 			Path: o.fs.opt.Enc.FromStandardPath(o.remotePath()),
 		})
@@ -2080,15 +2103,17 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs           = (*Fs)(nil)
-	_ fs.Copier       = (*Fs)(nil)
-	_ fs.Purger       = (*Fs)(nil)
-	_ fs.PutStreamer  = (*Fs)(nil)
-	_ fs.Mover        = (*Fs)(nil)
-	_ fs.PublicLinker = (*Fs)(nil)
-	_ fs.DirMover     = (*Fs)(nil)
-	_ fs.Abouter      = (*Fs)(nil)
-	_ fs.Shutdowner   = &Fs{}
-	_ fs.Object       = (*Object)(nil)
-	_ fs.IDer         = (*Object)(nil)
+	_ fs.Fs             = (*Fs)(nil)
+	_ fs.Copier         = (*Fs)(nil)
+	_ fs.Purger         = (*Fs)(nil)
+	_ fs.PutStreamer    = (*Fs)(nil)
+	_ fs.Mover          = (*Fs)(nil)
+	_ fs.PublicLinker   = (*Fs)(nil)
+	_ fs.DirMover       = (*Fs)(nil)
+	_ fs.Abouter        = (*Fs)(nil)
+	_ fs.ChangeNotifier = (*Fs)(nil)
+	_ fs.ChangesSincer  = (*Fs)(nil)
+	_ fs.Shutdowner     = &Fs{}
+	_ fs.Object         = (*Object)(nil)
+	_ fs.IDer           = (*Object)(nil)
 )
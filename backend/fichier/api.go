@@ -96,7 +96,7 @@ func (f *Fs) readFileInfo(ctx context.Context, url string) (*File, error) {
 	}
 
 	var file File
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, &request, &file)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -127,7 +127,7 @@ func (f *Fs) getDownloadToken(ctx context.Context, url string) (*GetTokenRespons
 	}
 
 	var token GetTokenResponse
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, &request, &token)
 		doretry, err := shouldRetry(ctx, resp, err)
 		return doretry || !validToken(&token), err
@@ -162,7 +162,7 @@ func (f *Fs) listSharedFiles(ctx context.Context, id string) (entries fs.DirEntr
 	}
 
 	var sharedFiles SharedFolderResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, nil, &sharedFiles)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -191,7 +191,7 @@ func (f *Fs) listFiles(ctx context.Context, directoryID int) (filesList *FilesLi
 	}
 
 	filesList = &FilesList{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, &request, filesList)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -219,7 +219,7 @@ func (f *Fs) listFolders(ctx context.Context, directoryID int) (foldersList *Fol
 	}
 
 	foldersList = &FoldersList{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, &request, foldersList)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -313,7 +313,7 @@ func (f *Fs) makeFolder(ctx context.Context, leaf string, folderID int) (respons
 	}
 
 	response = &MakeFolderResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, &request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -340,7 +340,7 @@ func (f *Fs) removeFolder(ctx context.Context, name string, folderID int) (respo
 
 	response = &GenericOKResponse{}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -369,7 +369,7 @@ func (f *Fs) deleteFile(ctx context.Context, url string) (response *GenericOKRes
 	}
 
 	response = &GenericOKResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -396,7 +396,7 @@ func (f *Fs) moveFile(ctx context.Context, url string, folderID int, rename stri
 	}
 
 	response = &MoveFileResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -422,7 +422,7 @@ func (f *Fs) moveDir(ctx context.Context, folderID int, newLeaf string, destinat
 	}
 
 	response = &MoveDirResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -447,7 +447,7 @@ func (f *Fs) copyFile(ctx context.Context, url string, folderID int, rename stri
 	}
 
 	response = &CopyFileResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -475,7 +475,7 @@ func (f *Fs) renameFile(ctx context.Context, url string, newName string) (respon
 	}
 
 	response = &RenameFileResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, request, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -497,7 +497,7 @@ func (f *Fs) getUploadNode(ctx context.Context) (response *GetUploadNodeResponse
 	}
 
 	response = &GetUploadNodeResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, nil, response)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -540,7 +540,7 @@ func (f *Fs) uploadFile(ctx context.Context, in io.Reader, size int64, fileName,
 		opts.RootURL = "https://" + node
 	}
 
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, nil, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -574,7 +574,7 @@ func (f *Fs) endUpload(ctx context.Context, uploadID string, nodeurl string) (re
 	}
 
 	response = &EndFileUploadResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.rest.CallJSON(ctx, &opts, nil, response)
 		return shouldRetry(ctx, resp, err)
 	})
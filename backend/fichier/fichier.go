@@ -586,7 +586,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 	}
 	var accountInfo AccountInfo
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rest.CallJSON(ctx, &opts, nil, &accountInfo)
 		return shouldRetry(ctx, resp, err)
 	})
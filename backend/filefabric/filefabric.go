@@ -406,7 +406,7 @@ func (f *Fs) rpc(ctx context.Context, function string, p params, result api.OKEr
 		Options:     options,
 	}
 	try := 0
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		try++
 		// Refresh the body each retry
 		opts.Body = strings.NewReader(data.Encode())
@@ -1285,7 +1285,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		opts.ContentLength = &contentLength // NB CallJSON scribbles on this which is naughty
 	}
 	try := 0
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		try++
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, nil, &uploader)
 		return o.fs.shouldRetry(ctx, resp, err, nil, try)
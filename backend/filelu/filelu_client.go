@@ -32,7 +32,7 @@ func (f *Fs) createFolder(ctx context.Context, dirPath string) (*api.CreateFolde
 
 	var resp *http.Response
 	result := api.CreateFolderResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		var innerErr error
 		resp, innerErr = f.client.Do(req)
 		return fserrors.ShouldRetry(innerErr), innerErr
@@ -68,7 +68,7 @@ func (f *Fs) getFolderList(ctx context.Context, path string) (*api.FolderListRes
 	)
 
 	var body []byte
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 		if err != nil {
 			return false, fmt.Errorf("failed to create request: %w", err)
@@ -128,7 +128,7 @@ func (f *Fs) deleteFolder(ctx context.Context, fullPath string) error {
 	)
 
 	delResp := api.DeleteFolderResponse{}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", deleteURL, nil)
 		if err != nil {
 			return false, err
@@ -174,7 +174,7 @@ func (f *Fs) getDirectLink(ctx context.Context, filePath string) (string, int64,
 	)
 
 	result := api.FileDirectLinkResponse{}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 		if err != nil {
 			return false, fmt.Errorf("failed to create request: %w", err)
@@ -217,7 +217,7 @@ func (f *Fs) deleteFile(ctx context.Context, filePath string) error {
 	)
 
 	result := api.DeleteFileResponse{}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 		if err != nil {
 			return false, fmt.Errorf("failed to create request: %w", err)
@@ -257,7 +257,7 @@ func (f *Fs) getAccountInfo(ctx context.Context) (*api.AccountInfoResponse, erro
 	}
 
 	var result api.AccountInfoResponse
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		_, callErr := f.srv.CallJSON(ctx, &opts, nil, &result)
 		return fserrors.ShouldRetry(callErr), callErr
 	})
@@ -284,7 +284,7 @@ func (f *Fs) getFileInfo(ctx context.Context, fileCode string) (*api.FileInfoRes
 	apiURL := f.endpoint + "/file/info2?" + u.RawQuery
 
 	var body []byte
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 		if err != nil {
 			return false, fmt.Errorf("failed to create request: %w", err)
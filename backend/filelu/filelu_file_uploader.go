@@ -79,7 +79,7 @@ func (f *Fs) getUploadServer(ctx context.Context) (string, string, error) {
 		Msg    string `json:"msg"`
 	}
 
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 		if err != nil {
 			return false, fmt.Errorf("failed to create request: %w", err)
@@ -148,7 +148,7 @@ func (f *Fs) uploadFileWithDestination(ctx context.Context, uploadURL, sessID, f
 	}()
 
 	var fileCode string
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, pr)
 		if err != nil {
 			return false, fmt.Errorf("failed to create upload request: %w", err)
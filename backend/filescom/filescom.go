@@ -186,7 +186,7 @@ func (f *Fs) readMetaDataForPath(ctx context.Context, path string) (info *files_
 	}
 
 	var file files_sdk.File
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		file, err = f.fileClient.Find(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -336,7 +336,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 		Path: f.absPath(dir),
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		it, err = f.folderClient.ListFor(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -419,7 +419,7 @@ func (f *Fs) mkdir(ctx context.Context, path string) error {
 		MkdirParents: ptr(true),
 	}
 
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := f.folderClient.Create(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -461,7 +461,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) error {
 		Recursive: ptr(!check),
 	}
 
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		err := f.fileClient.Delete(params, files_sdk.WithContext(ctx))
 		// Allow for eventual consistency deletion of child objects.
 		if isFolderNotEmpty(err) {
@@ -533,7 +533,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (dstObj fs.
 	}
 
 	var action files_sdk.FileAction
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		action, err = f.fileClient.Copy(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -568,7 +568,7 @@ func (f *Fs) move(ctx context.Context, src *Fs, srcRemote string, dstRemote stri
 	}
 
 	var action files_sdk.FileAction
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		action, err = f.fileClient.Move(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -587,7 +587,7 @@ func (f *Fs) move(ctx context.Context, src *Fs, srcRemote string, dstRemote stri
 
 func (f *Fs) waitForAction(ctx context.Context, action files_sdk.FileAction, operation string) (err error) {
 	var migration files_sdk.FileMigration
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		migration, err = f.migrationClient.Wait(action, func(migration files_sdk.FileMigration) {
 			// noop
 		}, files_sdk.WithContext(ctx))
@@ -676,7 +676,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	}
 
 	var bundle files_sdk.Bundle
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		bundle, err = f.bundleClient.Create(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -776,7 +776,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) (err error)
 	}
 
 	var file files_sdk.File
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		file, err = o.fs.fileClient.Update(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -819,7 +819,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 
 	headers := &http.Header{}
 	headers.Set("Range", fmt.Sprintf("bytes=%v-%v", offset, offset+count-1))
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = o.fs.fileClient.Download(
 			params,
 			files_sdk.WithContext(ctx),
@@ -858,7 +858,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		file.UploadWithProvidedMtime(src.ModTime(ctx)),
 	}
 
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err := o.fs.fileClient.Upload(uploadOpts...)
 		return shouldRetry(ctx, err)
 	})
@@ -875,7 +875,7 @@ func (o *Object) Remove(ctx context.Context) error {
 		Path: o.fs.absPath(o.remote),
 	}
 
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err := o.fs.fileClient.Delete(params, files_sdk.WithContext(ctx))
 		return shouldRetry(ctx, err)
 	})
@@ -288,11 +288,12 @@ type Fs struct {
 	pool     []*ftp.ServerConn
 	drain    *time.Timer // used to drain the pool when we stop using the connections
 	tokens   *pacer.TokenDispenser
-	proxyURL *url.URL  // address of HTTP proxy read from environment
-	pacer    *fs.Pacer // pacer for FTP connections
-	fGetTime bool      // true if the ftp library accepts GetTime
-	fSetTime bool      // true if the ftp library accepts SetTime
-	fLstTime bool      // true if the List call returns precise time
+	proxyURL *url.URL            // address of HTTP proxy read from environment
+	pacer    *fs.Pacer           // pacer for FTP connections
+	fGetTime bool                // true if the ftp library accepts GetTime
+	fSetTime bool                // true if the ftp library accepts SetTime
+	fLstTime bool                // true if the List call returns precise time
+	limiter  *accounting.Limiter // bandwidth and transaction limits for this remote
 }
 
 // Object describes an FTP file
@@ -456,6 +457,7 @@ func (f *Fs) ftpConnection(ctx context.Context) (c *ftp.ServerConn, err error) {
 			}
 		}()
 		baseDialer := fshttp.NewDialer(ctx)
+		baseDialer.SetLimiter(f.limiter)
 		if f.opt.SocksProxy != "" {
 			conn, err = proxy.SOCKS5Dial(network, address, f.opt.SocksProxy, baseDialer)
 		} else if f.proxyURL != nil {
@@ -532,7 +534,7 @@ func (f *Fs) ftpConnection(ctx context.Context) (c *ftp.ServerConn, err error) {
 	if f.ci.Dump&(fs.DumpHeaders|fs.DumpBodies|fs.DumpRequests|fs.DumpResponses) != 0 {
 		ftpConfig = append(ftpConfig, ftp.DialWithDebugOutput(&debugLog{auth: f.ci.Dump&fs.DumpAuth != 0}))
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		c, err = ftp.Dial(f.dialAddr, ftpConfig...)
 		if err != nil {
 			return shouldRetry(ctx, err)
@@ -555,7 +557,7 @@ func (f *Fs) getFtpConnection(ctx context.Context) (c *ftp.ServerConn, err error
 	if f.opt.Concurrency > 0 {
 		f.tokens.Get()
 	}
-	accounting.LimitTPS(ctx)
+	accounting.LimitTPS(ctx, f.limiter)
 	f.poolMu.Lock()
 	if len(f.pool) > 0 {
 		c = f.pool[0]
@@ -671,6 +673,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (ff fs.Fs
 		root:     root,
 		opt:      *opt,
 		ci:       ci,
+		limiter:  accounting.GetLimiter(ctx),
 		url:      u,
 		user:     user,
 		pass:     pass,
@@ -1319,7 +1322,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.Read
 		fd *ftp.Response
 		c  *ftp.ServerConn
 	)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		c, err = o.fs.getFtpConnection(ctx)
 		if err != nil {
 			return false, err // getFtpConnection has retries already
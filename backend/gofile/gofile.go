@@ -255,7 +255,7 @@ func (f *Fs) readMetaDataForID(ctx context.Context, id string) (info *api.Item,
 		},
 	}
 	var result api.Contents
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, &result)
 		// Retry not found errors - when looking for an ID it should really exist
 		if isAPIErr(err, "error-notFound") {
@@ -383,7 +383,7 @@ func (f *Fs) readAccountID(ctx context.Context, m configmap.Mapper) (err error)
 	}
 	var result api.AccountsGetID
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -403,7 +403,7 @@ func (f *Fs) getAccounts(ctx context.Context) (result *api.AccountsGet, err erro
 	}
 	result = new(api.AccountsGet)
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -488,7 +488,7 @@ func (f *Fs) createDir(ctx context.Context, pathID, leaf string, modTime time.Ti
 		ParentFolderID: pathID,
 		ModTime:        api.ToNativeTime(modTime),
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -538,7 +538,7 @@ OUTER:
 		opts.Parameters.Set("pageSize", strconv.Itoa(f.opt.ListChunk))
 		var result api.Contents
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -650,7 +650,7 @@ func (f *Fs) listR(ctx context.Context, dir string, list *list.Helper) (err erro
 		opts.Parameters.Set("pageSize", strconv.Itoa(f.opt.ListChunk))
 		var result api.Contents
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -816,7 +816,7 @@ func (f *Fs) deleteObject(ctx context.Context, id string) error {
 		ContentsID: id,
 	}
 	var result api.DeleteResponse
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -919,7 +919,7 @@ func (f *Fs) patch(ctx context.Context, id, attribute string, value any) (item *
 		Method: "PUT",
 		Path:   "/contents/" + id + "/update",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -951,7 +951,7 @@ func (f *Fs) move(ctx context.Context, id, newDirID string) (item *api.Item, err
 		Method: "PUT",
 		Path:   "/contents/move",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1071,7 +1071,7 @@ func (f *Fs) copy(ctx context.Context, id, newDirID string) (item *api.Item, err
 		Method: "POST",
 		Path:   "/contents/copy",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1181,7 +1181,7 @@ func (f *Fs) unLink(ctx context.Context, remote string, id string, info *api.Ite
 			Path:   "/contents/" + id + "/directlinks/" + linkID,
 		}
 		var result api.Error
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -1221,7 +1221,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 		fs.Debugf(f, "Link expires at %v (duration %v)", when, expire)
 		request.ExpireTime = api.ToNativeTime(when)
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1401,7 +1401,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		// },
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1457,7 +1457,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		RootURL:              api.DirectUploadURL(),
 		Options:              options,
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -592,7 +592,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	if f.rootBucket != "" && f.rootDirectory != "" {
 		// Check to see if the object exists
 		encodedDirectory := f.opt.Enc.FromStandardPath(f.rootDirectory)
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			get := f.svc.Objects.Get(f.rootBucket, encodedDirectory).Context(ctx)
 			if f.opt.UserProject != "" {
 				get = get.UserProject(f.opt.UserProject)
@@ -649,7 +649,10 @@ type listFn func(remote string, object *storage.Object, isDirectory bool) error
 //
 // The remote has prefix removed from it and if addBucket is set
 // then it adds the bucket to the start.
-func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, fn listFn) (err error) {
+//
+// If versionAt is set then the objects are listed as they were at
+// that time using the object generations.
+func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, versionAt time.Time, fn listFn) (err error) {
 	if prefix != "" {
 		prefix += "/"
 	}
@@ -663,10 +666,13 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 	if !recurse {
 		list = list.Delimiter("/")
 	}
+	if !versionAt.IsZero() {
+		list = list.Versions(true)
+	}
 	foundItems := 0
 	for {
 		var objects *storage.Objects
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			objects, err = list.Context(ctx).Do()
 			return shouldRetry(ctx, err)
 		})
@@ -702,6 +708,9 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 		}
 		foundItems += len(objects.Items)
 		for _, object := range objects.Items {
+			if !versionAt.IsZero() && !liveAt(object, versionAt) {
+				continue
+			}
 			remote := f.opt.Enc.ToStandardPath(object.Name)
 			if !strings.HasPrefix(remote, prefix) {
 				fs.Logf(f, "Odd name received %q", object.Name)
@@ -746,6 +755,20 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 	return nil
 }
 
+// liveAt returns true if the generation of the object was the live
+// one at time t
+func liveAt(object *storage.Object, t time.Time) bool {
+	created, err := time.Parse(time.RFC3339, object.TimeCreated)
+	if err != nil || created.After(t) {
+		return false
+	}
+	if object.TimeDeleted == "" {
+		return true
+	}
+	deleted, err := time.Parse(time.RFC3339, object.TimeDeleted)
+	return err == nil && deleted.After(t)
+}
+
 // Convert a list item into a DirEntry
 func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *storage.Object, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
@@ -760,9 +783,11 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *storage.
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, callback func(fs.DirEntry) error) (err error) {
+//
+// If versionAt is set the objects are listed as they were at that time.
+func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, versionAt time.Time, callback func(fs.DirEntry) error) (err error) {
 	// List the objects
-	err = f.list(ctx, bucket, directory, prefix, addBucket, false, func(remote string, object *storage.Object, isDirectory bool) error {
+	err = f.list(ctx, bucket, directory, prefix, addBucket, false, versionAt, func(remote string, object *storage.Object, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
@@ -791,7 +816,7 @@ func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error)
 	}
 	for {
 		var buckets *storage.Buckets
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			buckets, err = listBuckets.Context(ctx).Do()
 			return shouldRetry(ctx, err)
 		})
@@ -854,7 +879,7 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 			}
 		}
 	} else {
-		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", list.Add)
+		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", time.Time{}, list.Add)
 		if err != nil {
 			return err
 		}
@@ -862,6 +887,28 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 	return list.Flush()
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t using the noncurrent generations of the objects.
+//
+// This needs object versioning to be enabled on the bucket.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		if directory != "" {
+			return nil, fs.ErrorListBucketRequired
+		}
+		return f.listBuckets(ctx)
+	}
+	err = f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", t, func(entry fs.DirEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // ListR lists the objects and directories of the Fs starting
 // from dir recursively into out.
 //
@@ -882,7 +929,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	bucket, directory := f.split(dir)
 	list := list.NewHelper(callback)
 	listR := func(bucket, directory, prefix string, addBucket bool) error {
-		return f.list(ctx, bucket, directory, prefix, addBucket, true, func(remote string, object *storage.Object, isDirectory bool) error {
+		return f.list(ctx, bucket, directory, prefix, addBucket, true, time.Time{}, func(remote string, object *storage.Object, isDirectory bool) error {
 			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 			if err != nil {
 				return err
@@ -1007,7 +1054,7 @@ func (f *Fs) makeBucket(ctx context.Context, bucket string) (err error) {
 	return f.cache.Create(bucket, func() error {
 		// List something from the bucket to see if it exists.  Doing it like this enables the use of a
 		// service account that only has the "Storage Object Admin" role.  See #2193 for details.
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			list := f.svc.Objects.List(bucket).MaxResults(1).Context(ctx)
 			if f.opt.UserProject != "" {
 				list = list.UserProject(f.opt.UserProject)
@@ -1042,7 +1089,7 @@ func (f *Fs) makeBucket(ctx context.Context, bucket string) (err error) {
 				},
 			}
 		}
-		return f.pacer.Call(func() (bool, error) {
+		return f.pacer.CallContext(ctx, func() (bool, error) {
 			insertBucket := f.svc.Buckets.Insert(f.opt.ProjectNumber, &bucket)
 			if !f.opt.BucketPolicyOnly {
 				insertBucket.PredefinedAcl(f.opt.BucketACL)
@@ -1087,7 +1134,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) (err error) {
 		return nil
 	}
 	return f.cache.Remove(bucket, func() error {
-		return f.pacer.Call(func() (bool, error) {
+		return f.pacer.CallContext(ctx, func() (bool, error) {
 			deleteBucket := f.svc.Buckets.Delete(bucket).Context(ctx)
 			if f.opt.UserProject != "" {
 				deleteBucket = deleteBucket.UserProject(f.opt.UserProject)
@@ -1137,7 +1184,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	var rewriteResponse *storage.RewriteResponse
 	for {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			rewriteRequest = rewriteRequest.Context(ctx)
 			if f.opt.UserProject != "" {
 				rewriteRequest.UserProject(f.opt.UserProject)
@@ -1257,7 +1304,7 @@ func (o *Object) readObjectInfo(ctx context.Context) (object *storage.Object, er
 
 // readObjectInfo reads the definition for an object
 func (f *Fs) readObjectInfo(ctx context.Context, bucket, bucketPath string) (object *storage.Object, err error) {
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		get := f.svc.Objects.Get(bucket, bucketPath).Context(ctx)
 		if f.opt.UserProject != "" {
 			get = get.UserProject(f.opt.UserProject)
@@ -1329,7 +1376,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) (err error)
 	// Using PATCH requires too many permissions
 	bucket, bucketPath := o.split()
 	var newObject *storage.Object
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		copyObject := o.fs.svc.Objects.Copy(bucket, bucketPath, bucket, bucketPath, object)
 		if !o.fs.opt.BucketPolicyOnly {
 			copyObject.DestinationPredefinedAcl(o.fs.opt.ObjectACL)
@@ -1378,7 +1425,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 	fs.OpenOptionAddHTTPHeaders(req.Header, options)
 	var res *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.client.Do(req)
 		if err == nil {
 			err = googleapi.CheckResponse(res)
@@ -1449,7 +1496,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		}
 	}
 	var newObject *storage.Object
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		insertObject := o.fs.svc.Objects.Insert(bucket, &object).Media(in, googleapi.ContentType("")).Name(object.Name)
 		if !o.fs.opt.BucketPolicyOnly {
 			insertObject.PredefinedAcl(o.fs.opt.ObjectACL)
@@ -1472,7 +1519,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 // Remove an object
 func (o *Object) Remove(ctx context.Context) (err error) {
 	bucket, bucketPath := o.split()
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		deleteBucket := o.fs.svc.Objects.Delete(bucket, bucketPath).Context(ctx)
 		if o.fs.opt.UserProject != "" {
 			deleteBucket = deleteBucket.UserProject(o.fs.opt.UserProject)
@@ -1495,6 +1542,7 @@ var (
 	_ fs.PutStreamer = &Fs{}
 	_ fs.ListRer     = &Fs{}
 	_ fs.ListPer     = &Fs{}
+	_ fs.ListAter    = &Fs{}
 	_ fs.Object      = &Object{}
 	_ fs.MimeTyper   = &Object{}
 )
@@ -408,7 +408,7 @@ func (f *Fs) fetchEndpoint(ctx context.Context, name string) (endpoint string, e
 		RootURL: "https://accounts.google.com/.well-known/openid-configuration",
 	}
 	var openIDconfig map[string]any
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.unAuth.CallJSON(ctx, &opts, nil, &openIDconfig)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -437,7 +437,7 @@ func (f *Fs) UserInfo(ctx context.Context) (userInfo map[string]string, err erro
 		Method:  "GET",
 		RootURL: endpoint,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, &userInfo)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -468,7 +468,7 @@ func (f *Fs) Disconnect(ctx context.Context) (err error) {
 		},
 	}
 	var res any
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, &res)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -555,7 +555,7 @@ func (f *Fs) listAlbums(ctx context.Context, shared bool) (all *albums, err erro
 	for {
 		var result api.ListAlbums
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -612,7 +612,7 @@ func (f *Fs) list(ctx context.Context, filter api.SearchFilter, fn listFn) (err
 	for {
 		var result api.MediaItems
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, &filter, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -754,7 +754,7 @@ func (f *Fs) createAlbum(ctx context.Context, albumTitle string) (album *api.Alb
 	}
 	var result api.Album
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -896,7 +896,7 @@ func (o *Object) Size() int64 {
 		Method:  "HEAD",
 		RootURL: o.downloadURL(),
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -947,7 +947,7 @@ func (o *Object) readMetaData(ctx context.Context) (err error) {
 		}
 		var item api.MediaItem
 		var resp *http.Response
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &item)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -1028,7 +1028,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		RootURL: url,
 		Options: options,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1068,7 +1068,7 @@ func (f *Fs) commitBatchAlbumID(ctx context.Context, items []uploadedItem, resul
 	var result api.BatchCreateResponse
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, request, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1161,7 +1161,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 	var token []byte
 	var resp *http.Response
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		if err != nil {
 			return shouldRetry(ctx, resp, err)
@@ -1230,7 +1230,7 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 		MediaItemIDs: []string{o.id},
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, &request, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -354,6 +354,15 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	}
 }
 
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if do := f.Fs.Features().ChangesSince; do != nil {
+		return do(ctx, token, notifyFunc)
+	}
+	return "", fs.ErrorNotImplemented
+}
+
 // UserInfo returns info about the connected user
 func (f *Fs) UserInfo(ctx context.Context) (map[string]string, error) {
 	if do := f.Fs.Features().UserInfo; do != nil {
@@ -489,6 +498,27 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.wrapObject(o, err)
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	if do := f.Fs.Features().ListAt; do != nil {
+		entries, err = do(ctx, dir, t)
+		if err != nil {
+			return nil, err
+		}
+		return f.wrapEntries(entries)
+	}
+	return nil, fs.ErrorNotImplemented
+}
+
+// NewObjectAt finds the Object at remote as it was at time t.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	if do := f.Fs.Features().NewObjectAt; do != nil {
+		return f.wrapObject(do(ctx, remote, t))
+	}
+	return nil, fs.ErrorNotImplemented
+}
+
 //
 // Object
 //
@@ -599,6 +629,9 @@ var (
 	_ fs.MkdirMetadataer = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
+	_ fs.ListAter        = (*Fs)(nil)
+	_ fs.NewObjectAter   = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.UserInfoer      = (*Fs)(nil)
 	_ fs.Disconnecter    = (*Fs)(nil)
@@ -152,7 +152,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	// times until it completes without an error. The Java client,
 	// for context, always chooses to retry, with exponential
 	// backoff.
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err := out.Close()
 		if err == nil {
 			return false, nil
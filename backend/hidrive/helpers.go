@@ -199,7 +199,7 @@ func (f *Fs) paginateDirectoryAccess(ctx context.Context, opts *rest.Opts, limit
 
 		var result api.DirectoryContent
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, opts, nil, &result)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -232,7 +232,7 @@ func (f *Fs) fetchMetadataForPath(ctx context.Context, path string, fields []str
 	var result api.HiDriveObject
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -284,7 +284,7 @@ func (f *Fs) copyOrMove(ctx context.Context, isDirectory bool, operationType Cop
 	var result api.HiDriveObject
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -349,7 +349,7 @@ func (f *Fs) createDirectory(ctx context.Context, directory string, onExist OnEx
 	var result api.HiDriveObject
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -412,7 +412,7 @@ func (f *Fs) deleteDirectory(ctx context.Context, directory string, recursive bo
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -442,7 +442,7 @@ func (f *Fs) deleteObject(ctx context.Context, path string) error {
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -489,7 +489,7 @@ func (f *Fs) createFile(ctx context.Context, path string, content io.ReadSeeker,
 
 	var result api.HiDriveObject
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		// Reset the reading index (in case this is a retry).
 		if _, err = content.Seek(0, io.SeekStart); err != nil {
 			return false, err
@@ -541,7 +541,7 @@ func (f *Fs) overwriteFile(ctx context.Context, path string, content io.ReadSeek
 
 	var result api.HiDriveObject
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		// Reset the reading index (in case this is a retry).
 		if _, err = content.Seek(0, io.SeekStart); err != nil {
 			return false, err
@@ -734,7 +734,7 @@ func (f *Fs) patchFile(ctx context.Context, path string, content io.ReadSeeker,
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		// Reset the reading index (in case this is a retry).
 		_, err = content.Seek(0, io.SeekStart)
 		if err != nil {
@@ -791,7 +791,7 @@ func (f *Fs) resizeFile(ctx context.Context, path string, size uint64, modTime t
 	var result api.HiDriveObject
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -564,7 +564,7 @@ func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo,
 	}
 
 	// Try to remove object at path after the its content could not be uploaded.
-	deleteErr := f.pacer.Call(func() (bool, error) {
+	deleteErr := f.pacer.CallContext(ctx, func() (bool, error) {
 		deleteErr := o.Remove(ctx)
 		return deleteErr == fs.ErrorObjectNotFound, deleteErr
 	})
@@ -881,7 +881,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	}
 
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -912,7 +912,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadClo
 	}
 	var resp *http.Response
 	var err error
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -206,7 +206,7 @@ func Config(ctx context.Context, name string, m configmap.Mapper, config fs.Conf
 // find item by path. Will not return any children for the item
 func (f *Fs) findItem(ctx context.Context, dir string) (item *api.DriveItem, found bool, err error) {
 	var resp *http.Response
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		item, resp, err = f.service.GetItemByPath(ctx, path.Join(f.root, dir))
 		return shouldRetry(ctx, resp, err)
 	}); err != nil {
@@ -287,7 +287,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) error {
 
 	var _ *api.DriveItem
 	var resp *http.Response
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		_, resp, err = f.service.MoveItemToTrashByID(ctx, directoryID, etag, true)
 		return retryResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -317,7 +317,7 @@ func (f *Fs) listAll(ctx context.Context, dirID string) (items []*api.DriveItem,
 	var item *api.DriveItem
 	var resp *http.Response
 
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		id, _ := f.parseNormalizedID(dirID)
 		item, resp, err = f.service.GetItemByDriveID(ctx, id, true)
 		return shouldRetry(ctx, resp, err)
@@ -417,7 +417,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	var info *api.DriveItemRaw
 
 	// make a copy
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		info, resp, err = f.service.CopyDocByItemID(ctx, srcObj.itemID)
 		return retryResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -428,7 +428,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 
 	// get new document
 	var doc *api.Document
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		doc, resp, err = f.service.GetDocByItemID(ctx, info.ItemID)
 		return shouldRetry(ctx, resp, err)
 	}); err != nil {
@@ -437,7 +437,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 
 	// get parentdrive id
 	var dirDoc *api.Document
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		dirDoc, resp, err = f.service.GetDocByItemID(ctx, pathID)
 		return shouldRetry(ctx, resp, err)
 	}); err != nil {
@@ -459,7 +459,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	r.Btime = srcObj.modTime.UnixMilli()
 
 	var item *api.DriveItem
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		item, resp, err = f.service.UpdateFile(ctx, &r)
 		return retryResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -600,7 +600,7 @@ func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (string, error)
 	var err error
 	var found bool
 	var resp *http.Response
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		id, _ := f.parseNormalizedID(pathID)
 		item, resp, err = f.service.CreateNewFolderByDriveID(ctx, id, f.opt.Enc.FromStandardName(leaf))
 
@@ -672,7 +672,7 @@ func (f *Fs) move(ctx context.Context, ID, srcDirectoryID, srcLeaf, srcEtag, dst
 
 	// move
 	if srcDirectoryID != dstDirectoryID {
-		if err = f.pacer.Call(func() (bool, error) {
+		if err = f.pacer.CallContext(ctx, func() (bool, error) {
 			id, _ := f.parseNormalizedID(ID)
 			item, resp, err = f.service.MoveItemByDriveID(ctx, id, srcEtag, dstDirectoryID, true)
 			return ignoreResultUnknown(ctx, resp, err)
@@ -685,7 +685,7 @@ func (f *Fs) move(ctx context.Context, ID, srcDirectoryID, srcLeaf, srcEtag, dst
 
 	// rename
 	if srcLeaf != dstLeaf {
-		if err = f.pacer.Call(func() (bool, error) {
+		if err = f.pacer.CallContext(ctx, func() (bool, error) {
 			id, _ := f.parseNormalizedID(ID)
 			item, resp, err = f.service.RenameItemByDriveID(ctx, id, srcEtag, dstLeaf, true)
 			return ignoreResultUnknown(ctx, resp, err)
@@ -1010,7 +1010,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadClo
 	var resp *http.Response
 	var err error
 
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var url string
 
 		//var doc *api.Document
@@ -1043,7 +1043,7 @@ func (o *Object) Remove(ctx context.Context) error {
 
 	var resp *http.Response
 	var err error
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, resp, err = o.fs.service.MoveItemToTrashByID(ctx, o.driveID, o.etag, true)
 		return retryResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -1104,7 +1104,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 	// Create document
 	var uploadInfo *api.UploadResponse
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		uploadInfo, resp, err = o.fs.service.CreateUpload(ctx, size, name)
 		return ignoreResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -1113,7 +1113,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 	// Upload content
 	var upload *api.SingleFileResponse
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		upload, resp, err = o.fs.service.Upload(ctx, in, size, name, uploadInfo.URL)
 		return ignoreResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -1121,7 +1121,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 
 	//var doc *api.Document
-	//if err = o.fs.pacer.Call(func() (bool, error) {
+	//if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 	//	doc, resp, err = o.fs.service.GetDocByItemID(ctx, dirID)
 	//	return ignoreResultUnknown(ctx, resp, err)
 	//}); err != nil {
@@ -1143,7 +1143,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 	// Update metadata
 	var item *api.DriveItem
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		item, resp, err = o.fs.service.UpdateFile(ctx, &r)
 		return ignoreResultUnknown(ctx, resp, err)
 	}); err != nil {
@@ -437,7 +437,7 @@ func (f *Fs) Mkdir(ctx context.Context, dir string) (err error) {
 	parentFolderPath = f.EncodePath(parentFolderPath)
 	folderName = f.EncodeFileName(folderName)
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		var res *http.Response
 		res, err = f.ik.CreateFolder(ctx, client.CreateFolderParam{
 			ParentFolderPath: parentFolderPath,
@@ -465,7 +465,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) (err error) {
 		return errors.New("directory is not empty")
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		var res *http.Response
 		res, err = f.ik.DeleteFolder(ctx, client.DeleteFolderParam{
 			FolderPath: f.EncodePath(path.Join(f.root, dir)),
@@ -490,7 +490,7 @@ func (f *Fs) Purge(ctx context.Context, dir string) (err error) {
 
 	remote := path.Join(f.root, dir)
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		var res *http.Response
 		res, err = f.ik.DeleteFolder(ctx, client.DeleteFolderParam{
 			FolderPath: f.EncodePath(remote),
@@ -676,7 +676,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 	var resp *client.UploadResult
 
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		var res *http.Response
 		res, resp, err = o.fs.ik.Upload(ctx, in, client.UploadParam{
 			FileName:      fileName,
@@ -706,7 +706,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 // Remove this object
 func (o *Object) Remove(ctx context.Context) (err error) {
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var res *http.Response
 		res, err = o.fs.ik.DeleteFile(ctx, o.file.FileID)
 
@@ -731,7 +731,7 @@ func uploadFile(ctx context.Context, f *Fs, in io.Reader, srcRemote string, opti
 	UseUniqueFileName := new(bool)
 	*UseUniqueFileName = false
 
-	err := f.pacer.CallNoRetry(func() (bool, error) {
+	err := f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		var res *http.Response
 		var err error
 		res, _, err = f.ik.Upload(ctx, in, client.UploadParam{
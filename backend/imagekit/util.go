@@ -21,7 +21,7 @@ func (f *Fs) getFiles(ctx context.Context, path string, includeVersions bool) (f
 	var hasMore = true
 
 	for hasMore {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var data *[]client.File
 			var res *http.Response
 			res, data, err = f.ik.Files(ctx, client.FilesOrFolderParam{
@@ -54,7 +54,7 @@ func (f *Fs) getFolders(ctx context.Context, path string) (folders []client.Fold
 	var hasMore = true
 
 	for hasMore {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var data *[]client.Folder
 			var res *http.Response
 			res, data, err = f.ik.Folders(ctx, client.FilesOrFolderParam{
@@ -82,7 +82,7 @@ func (f *Fs) getFolders(ctx context.Context, path string) (folders []client.Fold
 
 func (f *Fs) getFileByName(ctx context.Context, path string, name string) (file *client.File) {
 
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		res, data, err := f.ik.Files(ctx, client.FilesOrFolderParam{
 			Limit:       1,
 			Path:        path,
@@ -106,7 +106,7 @@ func (f *Fs) getFileByName(ctx context.Context, path string, name string) (file
 }
 
 func (f *Fs) getFolderByName(ctx context.Context, path string, name string) (folder *client.Folder, err error) {
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, data, err := f.ik.Folders(ctx, client.FilesOrFolderParam{
 			Limit:       1,
 			Path:        path,
@@ -472,7 +472,7 @@ func (o *Object) SetModTime(ctx context.Context, t time.Time) (err error) {
 		ContentType:   "application/x-www-form-urlencoded",
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.front.CallJSON(ctx, &opts, nil, &result)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -641,7 +641,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (_ fs.Objec
 		ExtraHeaders: headers,
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(resp, err)
 	})
@@ -781,7 +781,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:    path.Join("/download/", o.fs.root, rest.URLPathEscapeAll(o.fs.opt.Enc.FromStandardPath(o.remote))),
 		Options: optionsFixed,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.front.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -863,7 +863,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		ExtraHeaders:  headers,
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -941,7 +941,7 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 		Path:   "/" + url.PathEscape(path.Join(bucket, bucketPath)),
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -1023,7 +1023,7 @@ func (f *Fs) requestMetadata(ctx context.Context, bucket string) (result *Metada
 	}
 
 	var temp MetadataResponseRaw
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.front.CallJSON(ctx, &opts, nil, &temp)
 		return f.shouldRetry(resp, err)
 	})
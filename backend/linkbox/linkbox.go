@@ -194,7 +194,7 @@ func (o *Object) set(e *entity) {
 //
 // This will be checked for error and an error will be returned if Status != 1
 func getUnmarshaledResponse(ctx context.Context, f *Fs, opts *rest.Opts, result any) error {
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -539,7 +539,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadClo
 		Options: options,
 	}
 
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		res, err = o.fs.srv.Call(ctx, opts)
 		return o.fs.shouldRetry(ctx, res, err)
@@ -626,7 +626,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 			Options:    options,
 			NoRedirect: true,
 		}
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			res, err = o.fs.srv.Call(ctx, opts)
 			return o.fs.shouldRetry(ctx, res, err)
 		})
@@ -648,7 +648,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		opts.Body = file
 		opts.ContentLength = &size
 
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			res, err = o.fs.srv.Call(ctx, opts)
 			return o.fs.shouldRetry(ctx, res, err)
 		})
@@ -559,7 +559,7 @@ func (f *Fs) metaServer(ctx context.Context) (string, error) {
 		url string
 		err error
 	)
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.Call(ctx, &opts)
 		if err == nil {
 			url, err = readBodyWord(res)
@@ -612,7 +612,7 @@ func (f *Fs) readItemMetaData(ctx context.Context, path string) (entry fs.DirEnt
 	}
 
 	var info api.ItemInfoResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -744,7 +744,7 @@ func (f *Fs) listM1(ctx context.Context, dirPath string, offset int, limit int)
 		info api.FolderInfoResponse
 		res  *http.Response
 	)
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.CallJSON(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -808,7 +808,7 @@ func (f *Fs) listBin(ctx context.Context, dirPath string, depth int) (entries fs
 	}
 
 	var res *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1081,7 +1081,7 @@ func (f *Fs) CreateDir(ctx context.Context, path string) error {
 	}
 
 	var res *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1224,7 +1224,7 @@ func (f *Fs) delete(ctx context.Context, path string, hardDelete bool) error {
 	}
 
 	var response api.GenericResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &response)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1296,7 +1296,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 
 	var response api.GenericBodyResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &response)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1400,7 +1400,7 @@ func (f *Fs) moveItemBin(ctx context.Context, srcPath, dstPath, opName string) e
 	}
 
 	var res *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1491,7 +1491,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	}
 
 	var response api.GenericBodyResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &response)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1532,7 +1532,7 @@ func (f *Fs) CleanUp(ctx context.Context) error {
 	}
 
 	var response api.CleanupResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &response)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1565,7 +1565,7 @@ func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	}
 
 	var info api.UserInfoResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err := f.srv.CallJSON(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, res, err, f, &opts)
 	})
@@ -1865,7 +1865,7 @@ func (o *Object) upload(ctx context.Context, in io.Reader, size int64, options .
 		res     *http.Response
 		strHash string
 	)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.srv.Call(ctx, &opts)
 		if err == nil {
 			strHash, err = readBodyWord(res)
@@ -1904,7 +1904,7 @@ func (f *Fs) uploadShard(ctx context.Context) (string, error) {
 		url string
 		err error
 	)
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = f.srv.Call(ctx, &opts)
 		if err == nil {
 			url, err = readBodyWord(res)
@@ -2084,7 +2084,7 @@ func (o *Object) addFileMetaData(ctx context.Context, overwrite bool) error {
 	}
 
 	var res *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, res, err, o.fs, &opts)
 	})
@@ -2172,7 +2172,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 
 	var res *http.Response
 	server := ""
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		server, err = o.fs.fileServers.Dispatch(ctx, server)
 		if err != nil {
 			return false, err
@@ -2307,7 +2307,7 @@ func (p *serverPool) Dispatch(ctx context.Context, current string) (string, erro
 		res *http.Response
 		err error
 	)
-	err = p.fs.pacer.Call(func() (bool, error) {
+	err = p.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = p.fs.srv.Call(ctx, &opts)
 		if err != nil {
 			return fserrors.ShouldRetry(err), err
@@ -388,7 +388,7 @@ func (f *Fs) mkdir(ctx context.Context, rootNode *mega.Node, dir string) (node *
 	// node is directory to create them from
 	for _, name := range parts[len(parts)-i:] {
 		// create directory called name in node
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			node, err = f.srv.CreateDir(name, node)
 			return shouldRetry(ctx, err)
 		})
@@ -463,7 +463,7 @@ func (f *Fs) CleanUp(ctx context.Context) (err error) {
 	// similar to f.deleteNode(trash) but with HardDelete as true
 	for _, item := range items {
 		fs.Debugf(f, "Deleting trash %q", f.opt.Enc.ToStandardName(item.GetName()))
-		deleteErr := f.pacer.Call(func() (bool, error) {
+		deleteErr := f.pacer.CallContext(ctx, func() (bool, error) {
 			err := f.srv.Delete(item, true)
 			return shouldRetry(ctx, err)
 		})
@@ -642,7 +642,7 @@ func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 
 // deleteNode removes a file or directory, observing useTrash
 func (f *Fs) deleteNode(ctx context.Context, node *mega.Node) (err error) {
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		err = f.srv.Delete(node, f.opt.HardDelete)
 		return shouldRetry(ctx, err)
 	})
@@ -751,7 +751,7 @@ func (f *Fs) move(ctx context.Context, dstRemote string, srcFs *Fs, srcRemote st
 	// move the object into its new directory if required
 	if srcDirNode != dstDirNode && srcDirNode.GetHash() != dstDirNode.GetHash() {
 		//log.Printf("move src %p %q dst %p %q", srcDirNode, srcDirNode.GetName(), dstDirNode, dstDirNode.GetName())
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			err = f.srv.Move(info, dstDirNode)
 			return shouldRetry(ctx, err)
 		})
@@ -765,7 +765,7 @@ func (f *Fs) move(ctx context.Context, dstRemote string, srcFs *Fs, srcRemote st
 	// rename the object if required
 	if srcLeaf != dstLeaf {
 		//log.Printf("rename %q to %q", srcLeaf, dstLeaf)
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			err = f.srv.Rename(info, f.opt.Enc.FromStandardName(dstLeaf))
 			return shouldRetry(ctx, err)
 		})
@@ -916,7 +916,7 @@ func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 		// move them into place
 		for _, info := range infos {
 			fs.Infof(srcDir, "merging %q", f.opt.Enc.ToStandardName(info.GetName()))
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				err = f.srv.Move(info, dstDirNode)
 				return shouldRetry(ctx, err)
 			})
@@ -938,7 +938,7 @@ func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	var q mega.QuotaResp
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		q, err = f.srv.GetQuota()
 		return shouldRetry(ctx, err)
 	})
@@ -1045,7 +1045,7 @@ func (oo *openObject) getChunk(ctx context.Context) (err error) {
 		return io.EOF
 	}
 	var chunk []byte
-	err = oo.o.fs.pacer.Call(func() (bool, error) {
+	err = oo.o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		chunk, err = oo.d.DownloadChunk(oo.id)
 		return shouldRetry(ctx, err)
 	})
@@ -1098,7 +1098,7 @@ func (oo *openObject) Close() (err error) {
 	if oo.closed {
 		return nil
 	}
-	err = oo.o.fs.pacer.Call(func() (bool, error) {
+	err = oo.o.fs.pacer.CallContext(oo.ctx, func() (bool, error) {
 		err = oo.d.Finish()
 		return shouldRetry(oo.ctx, err)
 	})
@@ -1126,7 +1126,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 
 	var d *mega.Download
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		d, err = o.fs.srv.NewDownload(o.info)
 		return shouldRetry(ctx, err)
 	})
@@ -1164,7 +1164,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 
 	var u *mega.Upload
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		u, err = o.fs.srv.NewUpload(dirNode, o.fs.opt.Enc.FromStandardName(leaf), size)
 		return shouldRetry(ctx, err)
 	})
@@ -1185,7 +1185,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 			return fmt.Errorf("upload failed to read data: %w", err)
 		}
 
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			err = u.UploadChunk(id, chunk)
 			return shouldRetry(ctx, err)
 		})
@@ -1196,7 +1196,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 	// Finish the upload
 	var info *mega.Node
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		info, err = u.Finish()
 		return shouldRetry(ctx, err)
 	})
@@ -872,7 +872,7 @@ func (f *Fs) callBackend(ctx context.Context, URL, method, actionHeader string,
 	}
 
 	var resp *http.Response
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		if response != nil {
 			resp, err = f.srv.CallXML(ctx, &opts, nil, response)
@@ -1028,7 +1028,7 @@ func (o *Object) netStorageUploadRequest(ctx context.Context, in io.Reader, src
 			"*X-Akamai-ACS-Action": actionHeader,
 		},
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -319,7 +319,7 @@ func (m *Metadata) Write(ctx context.Context, updatePermissions bool) (*api.Item
 	}
 	opts := m.fs.newOptsCallWithPath(ctx, m.remote, "PATCH", "")
 	var info *api.Item
-	err := m.fs.pacer.Call(func() (bool, error) {
+	err := m.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := m.fs.srv.CallJSON(ctx, &opts, &update, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -635,7 +635,7 @@ func (m *Metadata) addPermission(ctx context.Context, p *api.PermissionsType) (n
 	}
 
 	newP := &api.PermissionsResponse{}
-	err = m.fs.pacer.Call(func() (bool, error) {
+	err = m.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = m.fs.srv.CallJSON(ctx, &opts, &req, &newP)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -655,7 +655,7 @@ func (m *Metadata) updatePermission(ctx context.Context, p *api.PermissionsType)
 	}
 
 	newP = &api.PermissionsType{}
-	err = m.fs.pacer.Call(func() (bool, error) {
+	err = m.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = m.fs.srv.CallJSON(ctx, &opts, &req, &newP)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -669,7 +669,7 @@ func (m *Metadata) removePermission(ctx context.Context, p *api.PermissionsType)
 	opts := m.fs.newOptsCall(m.normalizedID, "DELETE", "/permissions/"+p.ID)
 	opts.NoResponse = true
 
-	err = m.fs.pacer.Call(func() (bool, error) {
+	err = m.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = m.fs.srv.CallJSON(ctx, &opts, nil, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -681,7 +681,7 @@ func (f *Fs) getPermissions(ctx context.Context, normalizedID string) (p []*api.
 	opts := f.newOptsCall(normalizedID, "GET", "/permissions")
 
 	permResp := &api.PermissionsResponse{}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &permResp)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -880,7 +880,7 @@ func (f *Fs) createDir(ctx context.Context, pathID, dirWithLeaf, leaf string, me
 		}
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &info)
 		return shouldRetry(ctx, resp, err)
 	})
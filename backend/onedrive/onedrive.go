@@ -931,7 +931,7 @@ func shouldRetry(ctx context.Context, resp *http.Response, err error) (bool, err
 func (f *Fs) readMetaDataForPathRelativeToID(ctx context.Context, normalizedID string, relPath string) (info *api.Item, resp *http.Response, err error) {
 	opts, _ := f.newOptsCallWithIDPath(normalizedID, relPath, true, "GET", "")
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -946,7 +946,7 @@ func (f *Fs) readMetaDataForPath(ctx context.Context, path string) (info *api.It
 	if f.driveType != driveTypePersonal || firstSlashIndex == -1 {
 		opts := f.newOptsCallWithPath(ctx, path, "GET", "")
 		opts.Path = strings.TrimSuffix(opts.Path, ":")
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &info)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -1125,6 +1125,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	// See: https://github.com/rclone/rclone/issues/6444
 	if f.opt.Region == regionCN {
 		f.features.ChangeNotify = nil
+		f.features.ChangesSince = nil
 	}
 
 	// Renew the token in the background
@@ -1255,7 +1256,7 @@ func (f *Fs) CreateDir(ctx context.Context, dirID, leaf string) (newID string, e
 		Name:             f.opt.Enc.FromStandardName(leaf),
 		ConflictBehavior: "fail",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1284,7 +1285,7 @@ type listAllFn func(*api.Item) error
 func (f *Fs) _listAll(ctx context.Context, dirID string, directoriesOnly bool, filesOnly bool, fn listAllFn, opts *rest.Opts, result any, pValue *[]api.Item, pNextLink *string) (err error) {
 	for {
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, opts, nil, result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -1577,7 +1578,7 @@ func (f *Fs) deleteObject(ctx context.Context, id string) error {
 	}
 	opts.NoResponse = true
 
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1637,7 +1638,7 @@ func (f *Fs) waitForJob(ctx context.Context, location string, o *Object) error {
 		var resp *http.Response
 		var err error
 		var body []byte
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = http.Get(location)
 			if err != nil {
 				return fserrors.ShouldRetry(err), err
@@ -1744,7 +1745,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (dst fs.Obj
 		},
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &copyReq, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1846,7 +1847,7 @@ func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	var resp *http.Response
 	var info *api.Item
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &move, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1922,7 +1923,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 	}
 	var resp *http.Response
 	var info api.Item
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &move, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1948,7 +1949,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 		Path:   "",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &drive)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1995,7 +1996,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 
 	var resp *http.Response
 	var result api.CreateShareLinkResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &share, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2119,7 +2120,7 @@ func (f *Fs) CleanUp(ctx context.Context) error {
 func (o *Object) deleteVersions(ctx context.Context) error {
 	opts := o.fs.newOptsCall(o.id, "GET", "/versions")
 	var versions api.VersionsResponse
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, nil, &versions)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2146,7 +2147,7 @@ func (o *Object) deleteVersion(ctx context.Context, ID string) error {
 	fs.Infof(o, "removing version %q", ID)
 	opts := o.fs.newOptsCall(o.id, "DELETE", "/versions/"+ID)
 	opts.NoResponse = true
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2319,7 +2320,7 @@ func (o *Object) setModTime(ctx context.Context, modTime time.Time) (*api.Item,
 		},
 	}
 	var info *api.Item
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, &update, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2374,7 +2375,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		return http.ErrUseLastResponse
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		if redirectReq != nil {
 			// It is a redirect which we are expecting
@@ -2391,7 +2392,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		return nil, err
 	}
 	if redirectReq != nil {
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = o.fs.unAuth.Do(redirectReq)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -2420,7 +2421,7 @@ func (o *Object) createUploadSession(ctx context.Context, src fs.ObjectInfo, mod
 		return nil, metadata, err
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, &createRequest, &response)
 		if apiErr, ok := err.(*api.Error); ok {
 			if apiErr.ErrorInfo.Code == "nameAlreadyExists" {
@@ -2441,7 +2442,7 @@ func (o *Object) getPosition(ctx context.Context, url string) (pos int64, err er
 	}
 	var info api.UploadFragmentResponse
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2470,7 +2471,7 @@ func (o *Object) uploadFragment(ctx context.Context, url string, start int64, to
 	var resp *http.Response
 	var body []byte
 	skip := int64(0)
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		toSend := chunkSize - skip
 		opts := rest.Opts{
 			Method:        "PUT",
@@ -2532,7 +2533,7 @@ func (o *Object) cancelUploadSession(ctx context.Context, url string) (err error
 		NoResponse: true,
 	}
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -2610,7 +2611,7 @@ func (o *Object) uploadSinglepart(ctx context.Context, in io.Reader, src fs.Obje
 	opts.Body = in
 	opts.Options = options
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &info)
 		if apiErr, ok := err.(*api.Error); ok {
 			if apiErr.ErrorInfo.Code == "nameAlreadyExists" {
@@ -2964,6 +2965,92 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	return
 }
 
+// ChangesSince calls notifyFunc with each path which has changed since
+// the delta token was issued and returns the next delta token.
+//
+// The delta API doesn't always return the path of the parent of an
+// item, for example for deleted items or on OneDrive for Business. If
+// it can't be found from the directory cache either the root is
+// reported as changed.
+func (f *Fs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (newToken string, err error) {
+	if token == "" {
+		return f.changeNotifyStartPageToken(ctx)
+	}
+	opts := f.buildDriveDeltaOpts(token)
+	for {
+		var delta api.DeltaResponse
+		var resp *http.Response
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
+			resp, err = f.srv.CallJSON(ctx, &opts, nil, &delta)
+			return shouldRetry(ctx, resp, err)
+		})
+		if resp != nil && resp.StatusCode == http.StatusGone {
+			return "", fs.ErrorChangeTokenInvalid
+		} else if err != nil {
+			return "", err
+		}
+		for i := range delta.Value {
+			f.notifyDeltaItem(&delta.Value[i], notifyFunc)
+		}
+		if delta.NextLink != "" {
+			opts = rest.Opts{
+				Method:  "GET",
+				RootURL: delta.NextLink,
+			}
+			continue
+		}
+		parsedURL, err := url.Parse(delta.DeltaLink)
+		if err != nil {
+			return "", err
+		}
+		return parsedURL.Query().Get("token"), nil
+	}
+}
+
+// notifyDeltaItem calls notifyFunc with the paths inside the root
+// changed by item
+func (f *Fs) notifyDeltaItem(item *api.Item, notifyFunc func(string, fs.EntryType)) {
+	parent := item.GetParentReference()
+	if parent == nil || parent.ID == "" {
+		// the root of the drive
+		return
+	}
+	// the old path if it was a directory we know about
+	if p, ok := f.dirCache.GetInv(item.GetID()); ok {
+		notifyFunc(p, fs.EntryDirectory)
+	}
+	entryType := fs.EntryObject
+	if item.GetFolder() != nil || item.GetPackage() != nil {
+		entryType = fs.EntryDirectory
+	}
+	name := f.opt.Enc.ToStandardName(item.GetName())
+	var relName string
+	if parent.Path != "" && item.Deleted == nil {
+		fullPath, err := getItemFullPath(item)
+		if err != nil {
+			fs.Debugf(f, "Can't find path of changed item %q - treating everything as changed: %v", item.GetID(), err)
+			notifyFunc("", fs.EntryDirectory)
+			return
+		}
+		fullPath = f.opt.Enc.ToStandardPath(fullPath)
+		if fullPath == f.root {
+			return
+		}
+		var insideRoot bool
+		relName, insideRoot = getRelativePathInsideBase(f.root, fullPath)
+		if !insideRoot {
+			return
+		}
+	} else if parentPath, ok := f.dirCache.GetInv(parent.GetID()); ok {
+		relName = path.Join(parentPath, name)
+	} else {
+		fs.Debugf(f, "Can't find path of changed item %q - treating everything as changed", item.GetID())
+		notifyFunc("", fs.EntryDirectory)
+		return
+	}
+	notifyFunc(relName, entryType)
+}
+
 func getItemFullPath(item *api.Item) (fullPath string, err error) {
 	err = nil
 	fullPath = item.GetName()
@@ -3023,6 +3110,7 @@ var (
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.CleanUpper      = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
 	_ fs.Object          = (*Object)(nil)
 	_ fs.MimeTyper       = &Object{}
@@ -192,7 +192,7 @@ func (f *Fs) rename(ctx context.Context, remote, newName string) (any, error) {
 		RequestMetadata:     common.RequestMetadata{},
 	}
 	var response objectstorage.RenameObjectResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		response, err = f.srv.RenameObject(ctx, request)
 		return shouldRetry(ctx, response.HTTPResponse(), err)
 	})
@@ -273,7 +273,7 @@ func (f *Fs) listMultipartUploadsObject(ctx context.Context, bucketName, directo
 
 	var response objectstorage.ListMultipartUploadsResponse
 	for {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			response, err = f.srv.ListMultipartUploads(ctx, req)
 			return shouldRetry(ctx, response.HTTPResponse(), err)
 		})
@@ -314,7 +314,7 @@ func (f *Fs) listMultipartUploadParts(ctx context.Context, bucketName, bucketPat
 
 	var response objectstorage.ListMultipartUploadPartsResponse
 	for {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			response, err = f.srv.ListMultipartUploadParts(ctx, req)
 			return shouldRetry(ctx, response.HTTPResponse(), err)
 		})
@@ -378,7 +378,7 @@ func (f *Fs) restore(ctx context.Context, opt map[string]string) (any, error) {
 		reqCopy.BucketName = &bucket
 		reqCopy.ObjectName = &bucketPath
 		var response objectstorage.RestoreObjectsResponse
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			response, err = f.srv.RestoreObjects(ctx, reqCopy)
 			return shouldRetry(ctx, response.HTTPResponse(), err)
 		})
@@ -75,7 +75,7 @@ func (f *Fs) copy(ctx context.Context, dstObj *Object, srcObj *Object) (err erro
 	}
 	useBYOKCopyObject(f, &req)
 	var resp objectstorage.CopyObjectResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CopyObject(ctx, req)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
 	})
@@ -68,6 +68,74 @@ func (f *Fs) OpenChunkWriter(
 	remote string,
 	src fs.ObjectInfo,
 	options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	info, chunkWriter, err := f.newChunkWriter(ctx, remote, src, options...)
+	if err != nil {
+		return info, nil, err
+	}
+	o := chunkWriter.o
+	uploadID, existingParts, err := o.createMultipartUpload(ctx, chunkWriter.ui.req)
+	if err != nil {
+		return info, nil, fmt.Errorf("create multipart upload request failed: %w", err)
+	}
+	chunkWriter.uploadID = &uploadID
+	chunkWriter.existingParts = existingParts
+	fs.Debugf(o, "open chunk writer: started multipart upload: %v", uploadID)
+	return info, chunkWriter, err
+}
+
+// ResumeChunkWriter resumes the multipart upload described by state
+//
+// It checks the parts in state are still part of the upload.
+func (f *Fs) ResumeChunkWriter(
+	ctx context.Context,
+	remote string,
+	src fs.ObjectInfo,
+	state fs.ChunkWriterState,
+	options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	info, chunkWriter, err := f.newChunkWriter(ctx, remote, src, options...)
+	if err != nil {
+		return info, nil, err
+	}
+	existingParts, err := f.listMultipartUploadParts(ctx, *chunkWriter.bucket, *chunkWriter.key, state.UploadID)
+	if err != nil {
+		return info, nil, fmt.Errorf("failed to list parts of multipart upload %q: %w", state.UploadID, err)
+	}
+	chunkWriter.uploadID = common.String(state.UploadID)
+	chunkWriter.existingParts = existingParts
+	for chunkNumber, eTag := range state.Parts {
+		partNumber := chunkNumber + 1
+		existing, ok := existingParts[partNumber]
+		if !ok || existing.Etag == nil || *existing.Etag != eTag {
+			return info, nil, fmt.Errorf("part %d of multipart upload is missing or has changed", partNumber)
+		}
+		chunkWriter.addCompletedPart(&partNumber, existing.Etag)
+	}
+	fs.Debugf(chunkWriter.o, "open chunk writer: resumed multipart upload: %v", state.UploadID)
+	return info, chunkWriter, nil
+}
+
+// State returns the state of the multipart upload so far
+func (w *objectChunkWriter) State() fs.ChunkWriterState {
+	w.partsToCommitMu.Lock()
+	defer w.partsToCommitMu.Unlock()
+	state := fs.ChunkWriterState{
+		UploadID:  *w.uploadID,
+		ChunkSize: w.chunkSize,
+		Parts:     make(map[int]string, len(w.partsToCommit)),
+	}
+	for _, part := range w.partsToCommit {
+		state.Parts[*part.PartNum-1] = *part.Etag
+	}
+	return state
+}
+
+// newChunkWriter makes an objectChunkWriter for remote without
+// starting the multipart upload
+func (f *Fs) newChunkWriter(
+	ctx context.Context,
+	remote string,
+	src fs.ObjectInfo,
+	options ...fs.OpenOption) (info fs.ChunkWriterInfo, chunkWriter *objectChunkWriter, err error) {
 	// Temporary Object under construction
 	o := &Object{
 		fs:     f,
@@ -101,29 +169,22 @@ func (f *Fs) OpenChunkWriter(
 		chunkSize = chunksize.Calculator(src, size, uploadParts, chunkSize)
 	}
 
-	uploadID, existingParts, err := o.createMultipartUpload(ctx, ui.req)
-	if err != nil {
-		return info, nil, fmt.Errorf("create multipart upload request failed: %w", err)
-	}
 	bucketName, bucketPath := o.split()
-	chunkWriter := &objectChunkWriter{
-		chunkSize:     int64(chunkSize),
-		size:          size,
-		f:             f,
-		bucket:        &bucketName,
-		key:           &bucketPath,
-		uploadID:      &uploadID,
-		existingParts: existingParts,
-		ui:            ui,
-		o:             o,
+	chunkWriter = &objectChunkWriter{
+		chunkSize: int64(chunkSize),
+		size:      size,
+		f:         f,
+		bucket:    &bucketName,
+		key:       &bucketPath,
+		ui:        ui,
+		o:         o,
 	}
 	info = fs.ChunkWriterInfo{
 		ChunkSize:         int64(chunkSize),
 		Concurrency:       o.fs.opt.UploadConcurrency,
 		LeavePartsOnError: o.fs.opt.LeavePartsOnError,
 	}
-	fs.Debugf(o, "open chunk writer: started multipart upload: %v", uploadID)
-	return info, chunkWriter, err
+	return info, chunkWriter, nil
 }
 
 // WriteChunk will write chunk number with reader bytes, where chunk number >= 0
@@ -171,7 +232,7 @@ func (w *objectChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, rea
 	}
 	w.o.applyPartUploadOptions(w.ui.req, &req)
 	var resp objectstorage.UploadPartResponse
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		// req.UploadPartBody = io.NopCloser(bytes.NewReader(buf))
 		// rewind the reader on retry and after reading md5
 		_, err = reader.Seek(0, io.SeekStart)
@@ -220,7 +281,7 @@ func (w *objectChunkWriter) Close(ctx context.Context) (err error) {
 	}
 	req.PartsToCommit = w.partsToCommit
 	var resp objectstorage.CommitMultipartUploadResponse
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = w.f.srv.CommitMultipartUpload(ctx, req)
 		// if multipart is corrupted, we will abort the uploadId
 		if isMultiPartUploadCorrupted(err) {
@@ -434,7 +495,7 @@ func (o *Object) createMultipartUpload(ctx context.Context, putReq *objectstorag
 	o.applyMultipartUploadOptions(putReq, &req)
 
 	var resp objectstorage.CreateMultipartUploadResponse
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CreateMultipartUpload(ctx, req)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
 	})
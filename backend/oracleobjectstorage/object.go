@@ -88,7 +88,7 @@ func (o *Object) headObject(ctx context.Context) (info *objectstorage.HeadObject
 	}
 	useBYOKHeadObject(o.fs, &req)
 	var response objectstorage.HeadObjectResponse
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		response, err = o.fs.srv.HeadObject(ctx, req)
 		return shouldRetry(ctx, response.HTTPResponse(), err)
@@ -316,7 +316,7 @@ func (o *Object) Remove(ctx context.Context) error {
 		BucketName:    common.String(bucketName),
 		ObjectName:    common.String(bucketPath),
 	}
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.DeleteObject(ctx, req)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
 	})
@@ -334,7 +334,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadClo
 	o.applyGetObjectOptions(&req, options...)
 	useBYOKGetObject(o.fs, &req)
 	var resp objectstorage.GetObjectResponse
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		resp, err = o.fs.srv.GetObject(ctx, req)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
@@ -410,7 +410,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 			return fmt.Errorf("failed to prepare upload: %w", err)
 		}
 		var resp objectstorage.PutObjectResponse
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			ui.req.PutObjectBody = io.NopCloser(in)
 			resp, err = o.fs.srv.PutObject(ctx, *ui.req)
 			return shouldRetry(ctx, resp.HTTPResponse(), err)
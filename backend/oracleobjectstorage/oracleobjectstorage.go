@@ -339,7 +339,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 
 	for {
 		var resp objectstorage.ListObjectsResponse
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var err error
 			resp, err = f.srv.ListObjects(ctx, request)
 			return shouldRetry(ctx, resp.HTTPResponse(), err)
@@ -474,7 +474,7 @@ func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error)
 	}
 	var resp objectstorage.ListBucketsResponse
 	for {
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.ListBuckets(ctx, request)
 			return shouldRetry(ctx, resp.HTTPResponse(), err)
 		})
@@ -571,7 +571,7 @@ func (f *Fs) makeBucket(ctx context.Context, bucketName string) error {
 			NamespaceName:       common.String(f.opt.Namespace),
 			CreateBucketDetails: details,
 		}
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.CreateBucket(ctx, req)
 			return shouldRetry(ctx, resp.HTTPResponse(), err)
 		})
@@ -598,7 +598,7 @@ func (f *Fs) bucketExists(ctx context.Context, bucketName string) (bool, error)
 		NamespaceName: common.String(f.opt.Namespace),
 		BucketName:    common.String(bucketName),
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.HeadBucket(ctx, req)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
 	})
@@ -624,7 +624,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 			NamespaceName: common.String(f.opt.Namespace),
 			BucketName:    common.String(bucketName),
 		}
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.DeleteBucket(ctx, req)
 			return shouldRetry(ctx, resp.HTTPResponse(), err)
 		})
@@ -645,7 +645,7 @@ func (f *Fs) abortMultiPartUpload(ctx context.Context, bucketName, bucketPath, u
 		ObjectName:    bucketPath,
 		UploadId:      uploadID,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.AbortMultipartUpload(ctx, request)
 		return shouldRetry(ctx, resp.HTTPResponse(), err)
 	})
@@ -793,14 +793,16 @@ func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error)
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs              = &Fs{}
-	_ fs.Copier          = &Fs{}
-	_ fs.PutStreamer     = &Fs{}
-	_ fs.ListRer         = &Fs{}
-	_ fs.ListPer         = &Fs{}
-	_ fs.Commander       = &Fs{}
-	_ fs.CleanUpper      = &Fs{}
-	_ fs.OpenChunkWriter = &Fs{}
+	_ fs.Fs                = &Fs{}
+	_ fs.Copier            = &Fs{}
+	_ fs.PutStreamer       = &Fs{}
+	_ fs.ListRer           = &Fs{}
+	_ fs.ListPer           = &Fs{}
+	_ fs.Commander         = &Fs{}
+	_ fs.CleanUpper        = &Fs{}
+	_ fs.OpenChunkWriter   = &Fs{}
+	_ fs.ResumeChunkWriter = &Fs{}
+	_ fs.ChunkWriterStater = &objectChunkWriter{}
 
 	_ fs.Object    = &Object{}
 	_ fs.MimeTyper = &Object{}
@@ -122,7 +122,7 @@ func fileOpenNew(ctx context.Context, c *rest.Client, srcFs *Fs, directoryID, fi
 	opts.Parameters.Set("flags", "0x0042") // O_CREAT, O_WRITE
 
 	result := &api.FileOpenResponse{}
-	err := srcFs.pacer.CallNoRetry(func() (bool, error) {
+	err := srcFs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := c.CallJSON(ctx, &opts, nil, result)
 		err = result.Error.Update(err)
 		return shouldRetry(ctx, resp, err)
@@ -149,7 +149,7 @@ func fileOpen(ctx context.Context, c *rest.Client, srcFs *Fs, fileID int64) (*ap
 	opts.Parameters.Set("flags", "0x0002") // O_WRITE
 
 	result := &api.FileOpenResponse{}
-	err := srcFs.pacer.CallNoRetry(func() (bool, error) {
+	err := srcFs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := c.CallJSON(ctx, &opts, nil, result)
 		err = result.Error.Update(err)
 		return shouldRetry(ctx, resp, err)
@@ -182,7 +182,7 @@ func fileChecksum(
 	opts.Parameters.Set("count", strconv.FormatInt(count, 10))
 
 	result := &api.FileChecksumResponse{}
-	err := pacer.CallNoRetry(func() (bool, error) {
+	err := pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := client.CallJSON(ctx, &opts, nil, result)
 		err = result.Error.Update(err)
 		return shouldRetry(ctx, resp, err)
@@ -220,7 +220,7 @@ func filePWrite(
 	opts.Parameters.Set("offset", strconv.FormatInt(offset, 10))
 
 	result := &api.FilePWriteResponse{}
-	err := pacer.CallNoRetry(func() (bool, error) {
+	err := pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := client.CallJSON(ctx, &opts, nil, result)
 		err = result.Error.Update(err)
 		return shouldRetry(ctx, resp, err)
@@ -249,7 +249,7 @@ func fileClose(
 	opts.Parameters.Set("fd", strconv.FormatInt(fd, 10))
 
 	result := &api.FileCloseResponse{}
-	err := pacer.CallNoRetry(func() (bool, error) {
+	err := pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := client.CallJSON(ctx, &opts, nil, result)
 		err = result.Error.Update(err)
 		return shouldRetry(ctx, resp, err)
@@ -45,7 +45,7 @@ func (f *Fs) requestDecompress(ctx context.Context, file *api.File, password str
 		Path:   "/decompress/v1/decompress",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -59,7 +59,7 @@ func (f *Fs) getUserInfo(ctx context.Context) (info *api.User, err error) {
 		RootURL: "https://user.mypikpak.com/v1/user/me",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -76,7 +76,7 @@ func (f *Fs) getVIPInfo(ctx context.Context) (info *api.VIP, err error) {
 		RootURL: "https://api-drive.mypikpak.com/drive/v1/privilege/vip",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -98,7 +98,7 @@ func (f *Fs) requestBatchAction(ctx context.Context, action string, req *api.Req
 		TaskID string `json:"task_id"`
 	}{}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -116,7 +116,7 @@ func (f *Fs) requestNewTask(ctx context.Context, req *api.RequestNewTask) (info
 	}
 	var newTask api.NewTask
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &newTask)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -133,7 +133,7 @@ func (f *Fs) requestNewFile(ctx context.Context, req *api.RequestNewFile) (info
 		Path:   "/drive/v1/files",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -152,7 +152,7 @@ func (f *Fs) getFile(ctx context.Context, ID string) (info *api.File, err error)
 		Path:   "/drive/v1/files/" + ID,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		if err == nil && !info.Links.ApplicationOctetStream.Valid() {
 			time.Sleep(5 * time.Second)
@@ -176,7 +176,7 @@ func (f *Fs) patchFile(ctx context.Context, ID string, req *api.File) (info *api
 		Path:   "/drive/v1/files/" + ID,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -190,7 +190,7 @@ func (f *Fs) getTask(ctx context.Context, ID string, checkPhase bool) (info *api
 		Path:   "/drive/v1/tasks/" + ID,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		if checkPhase {
 			if err == nil && info.Phase != api.PhaseTypeComplete {
@@ -227,7 +227,7 @@ func (f *Fs) deleteTask(ctx context.Context, ID string, deleteFiles bool) (err e
 		NoResponse: true,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -241,7 +241,7 @@ func (f *Fs) getAbout(ctx context.Context) (info *api.About, err error) {
 		Path:   "/drive/v1/about",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -255,7 +255,7 @@ func (f *Fs) requestShare(ctx context.Context, req *api.RequestShare) (info *api
 		Path:   "/drive/v1/share",
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, &req, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -288,7 +288,7 @@ func (f *Fs) getGcid(ctx context.Context, src fs.ObjectInfo) (gcid string, err e
 		Gcid string `json:"gcid,omitempty"`
 	}{}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -210,7 +210,7 @@ func (f *Fs) newChunkWriter(ctx context.Context, remote string, size int64, p *a
 			req.ContentType = aws.String(value)
 		}
 	}
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		w.mOut, err = w.client.CreateMultipartUpload(ctx, req)
 		return w.shouldRetry(ctx, err)
 	})
@@ -249,7 +249,7 @@ func (w *pikpakChunkWriter) WriteChunk(ctx context.Context, chunkNumber int32, r
 
 	partNumber := chunkNumber + 1
 	var res *s3.UploadPartOutput
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		// Discover the size by seeking to the end
 		currentChunkSize, err = reader.Seek(0, io.SeekEnd)
 		if err != nil {
@@ -292,7 +292,7 @@ func (w *pikpakChunkWriter) WriteChunk(ctx context.Context, chunkNumber int32, r
 // Abort the multipart upload
 func (w *pikpakChunkWriter) Abort(ctx context.Context) (err error) {
 	// Abort the upload session
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = w.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
 			Bucket:   w.mOut.Bucket,
 			Key:      w.mOut.Key,
@@ -314,7 +314,7 @@ func (w *pikpakChunkWriter) Close(ctx context.Context) (err error) {
 		return *w.completedParts[i].PartNumber < *w.completedParts[j].PartNumber
 	})
 	// Finalise the upload session
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err = w.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
 			Bucket:   w.mOut.Bucket,
 			Key:      w.mOut.Key,
@@ -799,7 +799,7 @@ OUTER:
 
 		var info api.FileList
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 			return f.shouldRetry(ctx, resp, err)
 		})
@@ -1071,7 +1071,7 @@ func (f *Fs) CleanUp(ctx context.Context) (err error) {
 		TaskID string `json:"task_id"`
 	}{}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.rst.CallJSON(ctx, &opts, nil, &info)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1402,7 +1402,7 @@ func (f *Fs) uploadByForm(ctx context.Context, in io.Reader, name string, size i
 	}
 
 	var resp *http.Response
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = f.rst.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1474,7 +1474,7 @@ func (f *Fs) uploadByResumable(ctx context.Context, in io.Reader, name string, s
 	s3opts = append(s3opts, func(o *s3.Options) {
 		o.RetryMaxAttempts = 1
 	})
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		_, err = client.PutObject(ctx, req, s3opts...)
 		return f.shouldRetry(ctx, nil, err)
 	})
@@ -1921,7 +1921,7 @@ func (o *Object) httpResponse(ctx context.Context, url, method string, options [
 		// Don't supply range requests for 0 length objects as they always fail
 		delete(req.Header, "Range")
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		res, err = o.fs.client.Do(req)
 		return o.fs.shouldRetry(ctx, res, err)
 	})
@@ -208,7 +208,7 @@ func (f *Fs) put(
 	// exist yet
 	params.Set("make_parents", "true")
 
-	return node, f.pacer.Call(func() (bool, error) {
+	return node, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -227,7 +227,7 @@ func (f *Fs) put(
 
 func (f *Fs) read(ctx context.Context, path string, options []fs.OpenOption) (in io.ReadCloser, err error) {
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &rest.Opts{
 			Method:  "GET",
 			Path:    f.escapePath(path),
@@ -242,7 +242,7 @@ func (f *Fs) read(ctx context.Context, path string, options []fs.OpenOption) (in
 }
 
 func (f *Fs) stat(ctx context.Context, path string) (fsp FilesystemPath, err error) {
-	return fsp, f.pacer.Call(func() (bool, error) {
+	return fsp, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -261,7 +261,7 @@ func (f *Fs) stat(ctx context.Context, path string) (fsp FilesystemPath, err err
 }
 
 func (f *Fs) changeLog(ctx context.Context, start, end time.Time) (changeLog ChangeLog, err error) {
-	return changeLog, f.pacer.Call(func() (bool, error) {
+	return changeLog, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -284,7 +284,7 @@ func (f *Fs) update(ctx context.Context, path string, fields fs.Metadata) (node
 	var params = paramsFromMetadata(fields)
 	params.Set("action", "update")
 
-	return node, f.pacer.Call(func() (bool, error) {
+	return node, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -300,7 +300,7 @@ func (f *Fs) update(ctx context.Context, path string, fields fs.Metadata) (node
 }
 
 func (f *Fs) mkdir(ctx context.Context, dir string) (err error) {
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -340,7 +340,7 @@ func (f *Fs) rename(ctx context.Context, src fs.Fs, from, to string, meta fs.Met
 	// does not exist yet
 	params.Set("make_parents", "true")
 
-	return node, f.pacer.Call(func() (bool, error) {
+	return node, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -363,7 +363,7 @@ func (f *Fs) delete(ctx context.Context, path string, recursive bool) (err error
 		params = url.Values{"recursive": []string{"true"}}
 	}
 
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
@@ -379,7 +379,7 @@ func (f *Fs) delete(ctx context.Context, path string, recursive bool) (err error
 }
 
 func (f *Fs) userInfo(ctx context.Context) (user UserInfo, err error) {
-	return user, f.pacer.Call(func() (bool, error) {
+	return user, f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(
 			ctx,
 			&rest.Opts{
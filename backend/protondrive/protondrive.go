@@ -474,7 +474,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 
 // CleanUp deletes all files currently in trash
 func (f *Fs) CleanUp(ctx context.Context) error {
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		err := f.protonDrive.EmptyTrash(ctx)
 		return shouldRetry(ctx, err)
 	})
@@ -503,7 +503,7 @@ func (f *Fs) getObjectLink(ctx context.Context, remote string) (*proton.Link, er
 	}
 
 	var link *proton.Link
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		link, err = f.protonDrive.SearchByNameInActiveFolderByID(ctx, folderLinkID, leaf, true, false, proton.LinkStateActive)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -520,7 +520,7 @@ func (f *Fs) getObjectLink(ctx context.Context, remote string) (*proton.Link, er
 func (f *Fs) readMetaDataForLink(ctx context.Context, link *proton.Link) (*protonDriveAPI.FileSystemAttrs, error) {
 	var fileSystemAttrs *protonDriveAPI.FileSystemAttrs
 	var err error
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		fileSystemAttrs, err = f.protonDrive.GetActiveRevisionAttrs(ctx, link)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -589,7 +589,7 @@ func (f *Fs) List(ctx context.Context, dir string) (fs.DirEntries, error) {
 	}
 
 	var foldersAndFiles []*protonDriveAPI.ProtonDirectoryData
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		foldersAndFiles, err = f.protonDrive.ListDirectory(ctx, folderLinkID)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -625,7 +625,7 @@ func (f *Fs) FindLeaf(ctx context.Context, pathID, leaf string) (string, bool, e
 
 	var link *proton.Link
 	var err error
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		link, err = f.protonDrive.SearchByNameInActiveFolderByID(ctx, pathID, leaf, false, true, proton.LinkStateActive)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -647,7 +647,7 @@ func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (string, error)
 
 	var newID string
 	var err error
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		newID, err = f.protonDrive.CreateNewFolderByID(ctx, pathID, leaf)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -744,7 +744,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 		return err
 	}
 
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		err = f.protonDrive.MoveFolderToTrashByID(ctx, folderLinkID, true)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -777,7 +777,7 @@ func (f *Fs) Hashes() hash.Set {
 func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	var user *proton.User
 	var err error
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		user, err = f.protonDrive.About(ctx)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -894,7 +894,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadClo
 	var fileSystemAttrs *protonDriveAPI.FileSystemAttrs
 	var sizeOnServer int64
 	var err error
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		reader, sizeOnServer, fileSystemAttrs, err = o.fs.protonDrive.DownloadFileByID(ctx, o.id, offset)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -940,7 +940,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	modTime := src.ModTime(ctx)
 	var linkID string
 	var fileSystemAttrs *proton.RevisionXAttrCommon
-	if err = o.fs.pacer.Call(func() (bool, error) {
+	if err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		linkID, fileSystemAttrs, err = o.fs.protonDrive.UploadFileByReader(ctx, folderLinkID, leaf, modTime, in, 0)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -965,7 +965,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 // Remove an object
 func (o *Object) Remove(ctx context.Context) error {
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err := o.fs.protonDrive.MoveFileToTrashByID(ctx, o.id)
 		return shouldRetry(ctx, err)
 	})
@@ -994,7 +994,7 @@ func (f *Fs) Purge(ctx context.Context, dir string) error {
 		return err
 	}
 
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		err = f.protonDrive.MoveFolderToTrashByID(ctx, folderLinkID, false)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -1012,7 +1012,7 @@ func (o *Object) MimeType(ctx context.Context) string {
 
 // Disconnect the current user
 func (f *Fs) Disconnect(ctx context.Context) error {
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		err := f.protonDrive.Logout(ctx)
 		return shouldRetry(ctx, err)
 	})
@@ -1051,7 +1051,7 @@ func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	if err != nil {
 		return nil, err
 	}
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		err = f.protonDrive.MoveFileByID(ctx, srcObj.id, dstDirectoryID, dstLeaf)
 		return shouldRetry(ctx, err)
 	}); err != nil {
@@ -1083,7 +1083,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 		return err
 	}
 
-	if err = f.pacer.Call(func() (bool, error) {
+	if err = f.pacer.CallContext(ctx, func() (bool, error) {
 		err = f.protonDrive.MoveFolderByID(ctx, srcID, dstDirectoryID, dstLeaf)
 		return shouldRetry(ctx, err)
 	}); err != nil {
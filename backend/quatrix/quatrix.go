@@ -264,7 +264,7 @@ func (f *Fs) fileID(ctx context.Context, parentID, path string) (result *api.Fil
 
 	result = &api.FileInfo{}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, payload, result)
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
 			return false, nil
@@ -317,7 +317,7 @@ func (f *Fs) createDir(ctx context.Context, pathID, leaf string, resolve bool) (
 
 	newDir = &api.File{}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, payload, newDir)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -579,7 +579,7 @@ func (f *Fs) metadata(ctx context.Context, id string, withContent bool) (result
 	result = &api.File{}
 
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -608,7 +608,7 @@ func (f *Fs) setMTime(ctx context.Context, id string, t time.Time) (result *api.
 	result = &api.File{}
 
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, params, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -636,7 +636,7 @@ func (f *Fs) deleteObject(ctx context.Context, id string) error {
 		Path:   "file/delete",
 	}
 
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, payload, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -755,7 +755,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 
 	var resp *http.Response
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, params, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -819,7 +819,7 @@ func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	var resp *http.Response
 	result := &api.File{}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, params, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -880,7 +880,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 	var resp *http.Response
 	result := &api.File{}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, params, result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -908,7 +908,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 		resp *http.Response
 	)
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &user)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1031,7 +1031,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 
 	var resp *http.Response
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1053,7 +1053,7 @@ func (f *Fs) downloadLink(ctx context.Context, id string) (linkID string, err er
 	var resp *http.Response
 	link := &api.DownloadLinkResponse{}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, linkParams, &link)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1168,7 +1168,7 @@ func (f *Fs) uploadLink(ctx context.Context, parentID, name string) (upload *api
 		Resolve:  false,
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &payload, &upload)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1190,7 +1190,7 @@ func (f *Fs) modifyLink(ctx context.Context, fileID string) (upload *api.UploadL
 		Truncate: 0,
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &payload, &upload)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1227,7 +1227,7 @@ func (o *Object) upload(ctx context.Context, uploadKey string, chunk io.Reader,
 
 	var fileID string
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, nil, &fileID)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1250,7 +1250,7 @@ func (o *Object) finalize(ctx context.Context, uploadKey string, mtime time.Time
 
 	result = &api.UploadFinalizeResponse{}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, nil, result)
 		return shouldRetry(ctx, resp, err)
 	})
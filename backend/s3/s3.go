@@ -4031,6 +4031,7 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	if !opt.UseMultipartUploads.Value {
 		fs.Debugf(f, "Disabling multipart uploads")
 		f.features.OpenChunkWriter = nil
+		f.features.ResumeChunkWriter = nil
 	}
 
 	if f.rootBucket != "" && f.rootDirectory != "" && !opt.NoHeadObject && !strings.HasSuffix(root, "/") {
@@ -4055,11 +4056,11 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 // This is needed to find versioned objects from their paths.
 //
 // It may return info == nil and err == nil if a HEAD would be more appropriate
-func (f *Fs) getMetaDataListing(ctx context.Context, wantRemote string) (info *types.Object, versionID *string, err error) {
+func (f *Fs) getMetaDataListing(ctx context.Context, wantRemote string, versionAt fs.Time) (info *types.Object, versionID *string, err error) {
 	bucket, bucketPath := f.split(wantRemote)
 
 	// Strip the version string off if using versions
-	if f.opt.Versions {
+	if f.opt.Versions && !versionAt.IsSet() {
 		var timestamp time.Time
 		timestamp, bucketPath = version.Remove(bucketPath)
 		// If the path had no version string return no info, to force caller to look it up
@@ -4075,7 +4076,7 @@ func (f *Fs) getMetaDataListing(ctx context.Context, wantRemote string) (info *t
 		recurse:      true,
 		withVersions: f.opt.Versions,
 		findFile:     true,
-		versionAt:    f.opt.VersionAt,
+		versionAt:    versionAt,
 		hidden:       f.opt.VersionDeleted,
 	}, func(gotRemote string, object *types.Object, objectVersionID *string, isDirectory bool) error {
 		if isDirectory {
@@ -4129,7 +4130,7 @@ func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *types.O
 	}
 	if info == nil && ((f.opt.Versions && version.Match(remote)) || f.opt.VersionAt.IsSet()) {
 		// If versions, have to read the listing to find the correct version ID
-		info, versionID, err = f.getMetaDataListing(ctx, remote)
+		info, versionID, err = f.getMetaDataListing(ctx, remote, f.opt.VersionAt)
 		if err != nil {
 			return nil, err
 		}
@@ -4545,7 +4546,7 @@ func (f *Fs) list(ctx context.Context, opt listOpt, fn listFn) error {
 		var resp *s3.ListObjectsV2Output
 		var err error
 		var versionIDs []*string
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 
 			listBucket.URLEncodeListings(urlEncodeListings)
 			resp, versionIDs, err = listBucket.List(ctx)
@@ -4698,7 +4699,9 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *types.Ob
 }
 
 // listDir lists files and directories to out
-func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, callback func(fs.DirEntry) error) (err error) {
+//
+// If versionAt is set the files are listed as they were at that time.
+func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, versionAt fs.Time, callback func(fs.DirEntry) error) (err error) {
 	// List the objects and directories
 	err = f.list(ctx, listOpt{
 		bucket:       bucket,
@@ -4706,7 +4709,7 @@ func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addB
 		prefix:       prefix,
 		addBucket:    addBucket,
 		withVersions: f.opt.Versions,
-		versionAt:    f.opt.VersionAt,
+		versionAt:    versionAt,
 		hidden:       f.opt.VersionDeleted,
 	}, func(remote string, object *types.Object, versionID *string, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, versionID, isDirectory)
@@ -4730,7 +4733,7 @@ func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addB
 func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error) {
 	req := s3.ListBucketsInput{}
 	var resp *s3.ListBucketsOutput
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.c.ListBuckets(ctx, &req)
 		return f.shouldRetry(ctx, err)
 	})
@@ -4790,7 +4793,7 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 			}
 		}
 	} else {
-		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", list.Add)
+		err := f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", f.opt.VersionAt, list.Add)
 		if err != nil {
 			return err
 		}
@@ -4798,6 +4801,38 @@ func (f *Fs) ListP(ctx context.Context, dir string, callback fs.ListRCallback) e
 	return list.Flush()
 }
 
+// ListAt lists the objects and directories in dir as they were at
+// time t using the old versions of the objects.
+//
+// This needs versioning to be enabled on the bucket.
+func (f *Fs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		if directory != "" {
+			return nil, fs.ErrorListBucketRequired
+		}
+		return f.listBuckets(ctx)
+	}
+	err = f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", fs.Time(t), func(entry fs.DirEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NewObjectAt finds the Object at remote as it was at time t.  If it
+// can't be found it returns the error ErrorObjectNotFound.
+func (f *Fs) NewObjectAt(ctx context.Context, remote string, t time.Time) (fs.Object, error) {
+	info, versionID, err := f.getMetaDataListing(ctx, remote, fs.Time(t))
+	if err != nil {
+		return nil, err
+	}
+	return f.newObjectWithInfo(ctx, remote, info, versionID)
+}
+
 // ListR lists the objects and directories of the Fs starting
 // from dir recursively into out.
 //
@@ -4886,7 +4921,7 @@ func (f *Fs) bucketExists(ctx context.Context, bucket string) (bool, error) {
 	req := s3.HeadBucketInput{
 		Bucket: &bucket,
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := f.c.HeadBucket(ctx, &req)
 		return f.shouldRetry(ctx, err)
 	})
@@ -4980,7 +5015,7 @@ func (f *Fs) makeBucket(ctx context.Context, bucket string) error {
 				LocationConstraint: types.BucketLocationConstraint(f.opt.LocationConstraint),
 			}
 		}
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.c.CreateBucket(ctx, &req)
 			return f.shouldRetry(ctx, err)
 		})
@@ -5032,7 +5067,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 		req := s3.DeleteBucketInput{
 			Bucket: &bucket,
 		}
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.c.DeleteBucket(ctx, &req)
 			return f.shouldRetry(ctx, err)
 		})
@@ -5095,7 +5130,7 @@ func (f *Fs) copy(ctx context.Context, req *s3.CopyObjectInput, dstBucket, dstPa
 	if src.bytes >= int64(f.opt.CopyCutoff) {
 		return f.copyMultipart(ctx, req, dstBucket, dstPath, srcBucket, srcPath, src)
 	}
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := f.c.CopyObject(ctx, req)
 		return f.shouldRetry(ctx, err)
 	})
@@ -5140,7 +5175,7 @@ func (f *Fs) copyMultipart(ctx context.Context, copyReq *s3.CopyObjectInput, dst
 	req.Key = &dstPath
 
 	var cout *s3.CreateMultipartUploadOutput
-	if err := f.pacer.Call(func() (bool, error) {
+	if err := f.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		cout, err = f.c.CreateMultipartUpload(ctx, req)
 		return f.shouldRetry(ctx, err)
@@ -5152,7 +5187,7 @@ func (f *Fs) copyMultipart(ctx context.Context, copyReq *s3.CopyObjectInput, dst
 	defer atexit.OnError(&err, func() {
 		// Try to abort the upload, but ignore the error.
 		fs.Debugf(src, "Cancelling multipart copy")
-		_ = f.pacer.Call(func() (bool, error) {
+		_ = f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err := f.c.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
 				Bucket:       &dstBucket,
 				Key:          &dstPath,
@@ -5191,7 +5226,7 @@ func (f *Fs) copyMultipart(ctx context.Context, copyReq *s3.CopyObjectInput, dst
 			uploadPartReq.PartNumber = &partNum
 			uploadPartReq.UploadId = uid
 			uploadPartReq.CopySourceRange = aws.String(calculateRange(partSize, int64(partNum-1), numParts, srcSize))
-			err := f.pacer.Call(func() (bool, error) {
+			err := f.pacer.CallContext(ctx, func() (bool, error) {
 				uout, err = f.c.UploadPartCopy(gCtx, uploadPartReq)
 				return f.shouldRetry(gCtx, err)
 			})
@@ -5211,7 +5246,7 @@ func (f *Fs) copyMultipart(ctx context.Context, copyReq *s3.CopyObjectInput, dst
 		return err
 	}
 
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := f.c.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
 			Bucket: &dstBucket,
 			Key:    &dstPath,
@@ -5564,7 +5599,7 @@ func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[str
 			reqCopy.Bucket = &bucket
 			reqCopy.Key = &bucketPath
 			reqCopy.VersionId = o.versionID
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				_, err = f.c.RestoreObject(ctx, &reqCopy)
 				return f.shouldRetry(ctx, err)
 			})
@@ -5685,7 +5720,7 @@ func (f *Fs) listMultipartUploads(ctx context.Context, bucket, key string) (uplo
 			Prefix:         &key,
 		}
 		var resp *s3.ListMultipartUploadsOutput
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.c.ListMultipartUploads(ctx, &req)
 			return f.shouldRetry(ctx, err)
 		})
@@ -5810,7 +5845,7 @@ func (f *Fs) setGetVersioning(ctx context.Context, arg ...string) (status types.
 			Bucket:                  &f.rootBucket,
 			VersioningConfiguration: &versioning,
 		}
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			_, err = f.c.PutBucketVersioning(ctx, &req)
 			return f.shouldRetry(ctx, err)
 		})
@@ -5822,7 +5857,7 @@ func (f *Fs) setGetVersioning(ctx context.Context, arg ...string) (status types.
 		Bucket: &f.rootBucket,
 	}
 	var resp *s3.GetBucketVersioningOutput
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.c.GetBucketVersioning(ctx, &req)
 		return f.shouldRetry(ctx, err)
 	})
@@ -6033,7 +6068,7 @@ func (f *Fs) headObject(ctx context.Context, req *s3.HeadObjectInput) (resp *s3.
 	if f.opt.SSECustomerKeyMD5 != "" {
 		req.SSECustomerKeyMD5 = &f.opt.SSECustomerKeyMD5
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		resp, err = f.c.HeadObject(ctx, req)
 		return f.shouldRetry(ctx, err)
@@ -6226,7 +6261,7 @@ func (o *Object) downloadFromURL(ctx context.Context, bucketPath string, options
 		RootURL: url,
 		Options: options,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srvRest.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -6348,7 +6383,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 
 	var resp *s3.GetObjectOutput
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var err error
 		resp, err = o.fs.c.GetObject(ctx, &req, s3.WithAPIOptions(APIOptions...))
 		return o.fs.shouldRetry(ctx, err)
@@ -6424,6 +6459,65 @@ type s3ChunkWriter struct {
 // Pass in the remote and the src object
 // You can also use options to hint at the desired chunk size
 func (f *Fs) OpenChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	info, chunkWriter, err := f.newChunkWriter(ctx, remote, src, options...)
+	if err != nil {
+		return info, nil, err
+	}
+
+	var mOut *s3.CreateMultipartUploadOutput
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
+		mOut, err = f.c.CreateMultipartUpload(ctx, chunkWriter.multiPartUploadInput)
+		if err == nil {
+			if mOut == nil {
+				err = fserrors.RetryErrorf("internal error: no info from multipart upload")
+			} else if mOut.UploadId == nil {
+				err = fserrors.RetryErrorf("internal error: no UploadId in multipart upload: %#v", *mOut)
+			}
+		}
+		return f.shouldRetry(ctx, err)
+	})
+	if err != nil {
+		return info, nil, fmt.Errorf("create multipart upload failed: %w", err)
+	}
+	chunkWriter.uploadID = mOut.UploadId
+	fs.Debugf(chunkWriter.o, "open chunk writer: started multipart upload: %v", *mOut.UploadId)
+	return info, chunkWriter, err
+}
+
+// ResumeChunkWriter resumes the multipart upload described by state
+//
+// The parts already uploaded are used when the upload is completed.
+func (f *Fs) ResumeChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, state fs.ChunkWriterState, options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, err error) {
+	info, chunkWriter, err := f.newChunkWriter(ctx, remote, src, options...)
+	if err != nil {
+		return info, nil, err
+	}
+	chunkWriter.uploadID = aws.String(state.UploadID)
+
+	// Check the upload still exists
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
+		_, err = f.c.ListParts(ctx, &s3.ListPartsInput{
+			Bucket:       chunkWriter.bucket,
+			Key:          chunkWriter.key,
+			UploadId:     chunkWriter.uploadID,
+			MaxParts:     aws.Int32(1),
+			RequestPayer: chunkWriter.multiPartUploadInput.RequestPayer,
+		})
+		return f.shouldRetry(ctx, err)
+	})
+	if err != nil {
+		return info, nil, fmt.Errorf("failed to find multipart upload %q: %w", state.UploadID, err)
+	}
+	for chunkNumber, eTag := range state.Parts {
+		chunkWriter.addCompletedPart(aws.Int32(int32(chunkNumber+1)), aws.String(eTag))
+	}
+	fs.Debugf(chunkWriter.o, "open chunk writer: resumed multipart upload: %v", state.UploadID)
+	return info, chunkWriter, nil
+}
+
+// newChunkWriter makes an s3ChunkWriter for remote without starting
+// the multipart upload
+func (f *Fs) newChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, options ...fs.OpenOption) (info fs.ChunkWriterInfo, chunkWriter *s3ChunkWriter, err error) {
 	// Temporary Object under construction
 	o := &Object{
 		fs:     f,
@@ -6461,29 +6555,12 @@ func (f *Fs) OpenChunkWriter(ctx context.Context, remote string, src fs.ObjectIn
 		chunkSize = chunksize.Calculator(src, size, uploadParts, chunkSize)
 	}
 
-	var mOut *s3.CreateMultipartUploadOutput
-	err = f.pacer.Call(func() (bool, error) {
-		mOut, err = f.c.CreateMultipartUpload(ctx, &mReq)
-		if err == nil {
-			if mOut == nil {
-				err = fserrors.RetryErrorf("internal error: no info from multipart upload")
-			} else if mOut.UploadId == nil {
-				err = fserrors.RetryErrorf("internal error: no UploadId in multipart upload: %#v", *mOut)
-			}
-		}
-		return f.shouldRetry(ctx, err)
-	})
-	if err != nil {
-		return info, nil, fmt.Errorf("create multipart upload failed: %w", err)
-	}
-
-	chunkWriter := &s3ChunkWriter{
+	chunkWriter = &s3ChunkWriter{
 		chunkSize:            int64(chunkSize),
 		size:                 size,
 		f:                    f,
 		bucket:               ui.req.Bucket,
 		key:                  ui.req.Key,
-		uploadID:             mOut.UploadId,
 		multiPartUploadInput: &mReq,
 		completedParts:       make([]types.CompletedPart, 0),
 		ui:                   ui,
@@ -6494,8 +6571,22 @@ func (f *Fs) OpenChunkWriter(ctx context.Context, remote string, src fs.ObjectIn
 		Concurrency:       o.fs.opt.UploadConcurrency,
 		LeavePartsOnError: o.fs.opt.LeavePartsOnError,
 	}
-	fs.Debugf(o, "open chunk writer: started multipart upload: %v", *mOut.UploadId)
-	return info, chunkWriter, err
+	return info, chunkWriter, nil
+}
+
+// State returns the state of the multipart upload so far
+func (w *s3ChunkWriter) State() fs.ChunkWriterState {
+	w.completedPartsMu.Lock()
+	defer w.completedPartsMu.Unlock()
+	state := fs.ChunkWriterState{
+		UploadID:  *w.uploadID,
+		ChunkSize: w.chunkSize,
+		Parts:     make(map[int]string, len(w.completedParts)),
+	}
+	for _, part := range w.completedParts {
+		state.Parts[int(*part.PartNumber)-1] = *part.ETag
+	}
+	return state
 }
 
 // add a part number and etag to the completed parts
@@ -6575,7 +6666,7 @@ func (w *s3ChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, reader
 		uploadPartReq.ContentMD5 = nil
 	}
 	var uout *s3.UploadPartOutput
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		// rewind the reader on retry and after reading md5
 		_, err = reader.Seek(0, io.SeekStart)
 		if err != nil {
@@ -6606,7 +6697,7 @@ func (w *s3ChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, reader
 
 // Abort the multipart upload
 func (w *s3ChunkWriter) Abort(ctx context.Context) error {
-	err := w.f.pacer.Call(func() (bool, error) {
+	err := w.f.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := w.f.c.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
 			Bucket:       w.bucket,
 			Key:          w.key,
@@ -6629,7 +6720,7 @@ func (w *s3ChunkWriter) Close(ctx context.Context) (err error) {
 		return *w.completedParts[i].PartNumber < *w.completedParts[j].PartNumber
 	})
 	var resp *s3.CompleteMultipartUploadOutput
-	err = w.f.pacer.Call(func() (bool, error) {
+	err = w.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = w.f.c.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
 			Bucket: w.bucket,
 			Key:    w.key,
@@ -6693,7 +6784,7 @@ func (o *Object) uploadSinglepartPutObject(ctx context.Context, req *s3.PutObjec
 		s3opt.RetryMaxAttempts = 1
 	})
 	var resp *s3.PutObjectOutput
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.c.PutObject(ctx, req, options...)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -6732,7 +6823,7 @@ func (o *Object) uploadSinglepartPresignedRequest(ctx context.Context, req *s3.P
 	httpReq.ContentLength = size
 
 	var resp *http.Response
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		var err error
 		resp, err = o.fs.srv.Do(httpReq)
 		if err != nil {
@@ -7031,7 +7122,7 @@ func (o *Object) Remove(ctx context.Context) error {
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = types.RequestPayerRequester
 	}
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		_, err := o.fs.c.DeleteObject(ctx, &req)
 		return o.fs.shouldRetry(ctx, err)
 	})
@@ -7123,18 +7214,22 @@ func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error)
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs              = &Fs{}
-	_ fs.Purger          = &Fs{}
-	_ fs.Copier          = &Fs{}
-	_ fs.PutStreamer     = &Fs{}
-	_ fs.ListRer         = &Fs{}
-	_ fs.ListPer         = &Fs{}
-	_ fs.Commander       = &Fs{}
-	_ fs.CleanUpper      = &Fs{}
-	_ fs.OpenChunkWriter = &Fs{}
-	_ fs.Object          = &Object{}
-	_ fs.MimeTyper       = &Object{}
-	_ fs.GetTierer       = &Object{}
-	_ fs.SetTierer       = &Object{}
-	_ fs.Metadataer      = &Object{}
+	_ fs.Fs                = &Fs{}
+	_ fs.Purger            = &Fs{}
+	_ fs.Copier            = &Fs{}
+	_ fs.PutStreamer       = &Fs{}
+	_ fs.ListRer           = &Fs{}
+	_ fs.ListPer           = &Fs{}
+	_ fs.ListAter          = &Fs{}
+	_ fs.NewObjectAter     = &Fs{}
+	_ fs.Commander         = &Fs{}
+	_ fs.CleanUpper        = &Fs{}
+	_ fs.OpenChunkWriter   = &Fs{}
+	_ fs.ResumeChunkWriter = &Fs{}
+	_ fs.ChunkWriterStater = &s3ChunkWriter{}
+	_ fs.Object            = &Object{}
+	_ fs.MimeTyper         = &Object{}
+	_ fs.GetTierer         = &Object{}
+	_ fs.SetTierer         = &Object{}
+	_ fs.Metadataer        = &Object{}
 )
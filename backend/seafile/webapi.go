@@ -83,7 +83,7 @@ func (f *Fs) getServerInfo(ctx context.Context) (account *api.ServerInfo, err er
 	result := api.ServerInfo{}
 
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -109,7 +109,7 @@ func (f *Fs) getUserAccountInfo(ctx context.Context) (account *api.AccountInfo,
 	result := api.AccountInfo{}
 
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -136,7 +136,7 @@ func (f *Fs) getLibraries(ctx context.Context) ([]api.Library, error) {
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -167,7 +167,7 @@ func (f *Fs) createLibrary(ctx context.Context, libraryName, password string) (l
 	result := &api.CreateLibrary{}
 
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -194,7 +194,7 @@ func (f *Fs) deleteLibrary(ctx context.Context, libraryID string) error {
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -225,7 +225,7 @@ func (f *Fs) decryptLibrary(ctx context.Context, libraryID, password string) err
 	}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -268,7 +268,7 @@ func (f *Fs) getDirectoryEntriesAPIv21(ctx context.Context, libraryID, dirPath s
 	result := &api.DirEntries{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -313,7 +313,7 @@ func (f *Fs) getDirectoryDetails(ctx context.Context, libraryID, dirPath string)
 	result := &api.DirectoryDetail{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -355,7 +355,7 @@ func (f *Fs) createDir(ctx context.Context, libraryID, dirPath string) error {
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -395,7 +395,7 @@ func (f *Fs) renameDir(ctx context.Context, libraryID, dirPath, newName string)
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -435,7 +435,7 @@ func (f *Fs) moveDir(ctx context.Context, srcLibraryID, srcDir, srcName, dstLibr
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -471,7 +471,7 @@ func (f *Fs) deleteDir(ctx context.Context, libraryID, filePath string) error {
 
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -502,7 +502,7 @@ func (f *Fs) getFileDetails(ctx context.Context, libraryID, filePath string) (*a
 	result := &api.FileDetail{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -536,7 +536,7 @@ func (f *Fs) deleteFile(ctx context.Context, libraryID, filePath string) error {
 		Parameters: url.Values{"p": {f.opt.Enc.FromStandardPath(filePath)}},
 		NoResponse: true,
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, nil, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -562,7 +562,7 @@ func (f *Fs) getDownloadLink(ctx context.Context, libraryID, filePath string) (s
 	result := ""
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -618,7 +618,7 @@ func (f *Fs) download(ctx context.Context, downloadLink string, size int64, opti
 		opts.Path = downloadLink
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -663,7 +663,7 @@ func (f *Fs) getUploadLink(ctx context.Context, libraryID string) (string, error
 	result := ""
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -711,7 +711,7 @@ func (f *Fs) upload(ctx context.Context, in io.Reader, uploadLink, filePath stri
 	result := make([]api.FileDetail, 1)
 	var resp *http.Response
 	// If an error occurs during the call, do not attempt to retry: The upload link is single use only
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetryUpload(ctx, resp, err)
 	})
@@ -751,7 +751,7 @@ func (f *Fs) listShareLinks(ctx context.Context, libraryID, remote string) ([]ap
 	result := make([]api.SharedLink, 1)
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -789,7 +789,7 @@ func (f *Fs) createShareLink(ctx context.Context, libraryID, remote string) (*ap
 	result := &api.SharedLink{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -830,7 +830,7 @@ func (f *Fs) copyFile(ctx context.Context, srcLibraryID, srcPath, dstLibraryID,
 	result := &api.FileInfo{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -872,7 +872,7 @@ func (f *Fs) moveFile(ctx context.Context, srcLibraryID, srcPath, dstLibraryID,
 	result := &api.FileInfo{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -912,7 +912,7 @@ func (f *Fs) renameFile(ctx context.Context, libraryID, filePath, newname string
 	result := &api.FileInfo{}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &request, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -950,7 +950,7 @@ func (f *Fs) emptyLibraryTrash(ctx context.Context, libraryID string) error {
 	}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -986,7 +986,7 @@ func (f *Fs) getDirectoryEntriesAPIv2(ctx context.Context, libraryID, dirPath st
 	result := make([]api.DirEntry, 1)
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -616,7 +616,8 @@ type Fs struct {
 	savedpswd    string
 	sessions     atomic.Int32 // count in use sessions
 	tokens       *pacer.TokenDispenser
-	proxyURL     *url.URL // address of HTTP proxy read from environment
+	proxyURL     *url.URL            // address of HTTP proxy read from environment
+	limiter      *accounting.Limiter // bandwidth and transaction limits for this remote
 }
 
 // Object is a remote SFTP file that has been stat'd (so it exists, but is not necessarily open for reading)
@@ -783,7 +784,7 @@ func (f *Fs) newSftpClient(client sshClient, opts ...sftp.ClientOption) (*sftp.C
 
 // Get an SFTP connection from the pool, or open a new one
 func (f *Fs) getSftpConnection(ctx context.Context) (c *conn, err error) {
-	accounting.LimitTPS(ctx)
+	accounting.LimitTPS(ctx, f.limiter)
 	if f.opt.Connections > 0 {
 		f.tokens.Get()
 	}
@@ -802,7 +803,7 @@ func (f *Fs) getSftpConnection(ctx context.Context) (c *conn, err error) {
 	if c != nil {
 		return c, nil
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		c, err = f.sftpConnection(ctx)
 		if err != nil {
 			return true, err
@@ -899,7 +900,8 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	// so we can refer to it in the SSH callback, but it's populated
 	// in NewFsWithConnection
 	f := &Fs{
-		ci: fs.GetConfig(ctx),
+		ci:      fs.GetConfig(ctx),
+		limiter: accounting.GetLimiter(ctx),
 	}
 	// Parse config into Options struct
 	opt := new(Options)
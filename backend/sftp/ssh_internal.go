@@ -25,6 +25,7 @@ type sshClientInternal struct {
 func (f *Fs) newSSHClientInternal(ctx context.Context, network, addr string, sshConfig *ssh.ClientConfig) (sshClient, error) {
 
 	baseDialer := fshttp.NewDialer(ctx)
+	baseDialer.SetLimiter(f.limiter)
 	var (
 		conn net.Conn
 		err  error
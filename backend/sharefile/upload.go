@@ -111,7 +111,7 @@ func (up *largeUpload) transferChunk(ctx context.Context, part int64, offset int
 		ContentLength: &size,
 	}
 	var respBody []byte
-	err := up.f.pacer.Call(func() (bool, error) {
+	err := up.f.pacer.CallContext(ctx, func() (bool, error) {
 		fs.Debugf(up.o, "Sending chunk %d length %d", part, len(body))
 		opts.Body = up.wrap(bytes.NewReader(body))
 		resp, err := up.f.srv.Call(ctx, &opts)
@@ -148,7 +148,7 @@ func (up *largeUpload) finish(ctx context.Context) error {
 		RootURL: up.info.FinishURI,
 	}
 	var respBody []byte
-	err := up.f.pacer.Call(func() (bool, error) {
+	err := up.f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := up.f.srv.Call(ctx, &opts)
 		if err != nil {
 			return shouldRetry(ctx, resp, err)
@@ -163,7 +163,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:    path.Join("/renter/stream/", o.fs.root, o.fs.opt.Enc.FromStandardPath(o.remote)),
 		Options: optionsFixed,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -186,7 +186,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 	opts.Parameters.Set("force", "true")
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -205,7 +205,7 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 		Method: "POST",
 		Path:   path.Join("/renter/delete/", o.fs.opt.Enc.FromStandardPath(path.Join(o.fs.root, o.remote))),
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -222,7 +222,7 @@ func (o *Object) readMetaData(ctx context.Context) (err error) {
 
 	var result api.FileResponse
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &result)
 		return o.fs.shouldRetry(resp, err)
 	})
@@ -278,7 +278,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 		Path:   path.Join("/renter/dir/", dirPrefix) + "/",
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(resp, err)
 	})
@@ -369,7 +369,7 @@ func (f *Fs) Mkdir(ctx context.Context, dir string) (err error) {
 	}
 	opts.Parameters.Set("action", "create")
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(resp, err)
 	})
@@ -390,7 +390,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) (err error) {
 	}
 
 	var result api.DirectoriesResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return f.shouldRetry(resp, err)
 	})
@@ -408,7 +408,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) (err error) {
 	}
 	opts.Parameters.Set("action", "delete")
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return f.shouldRetry(resp, err)
 	})
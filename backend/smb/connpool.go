@@ -23,6 +23,7 @@ import (
 // The context is only used for establishing the connection, not after.
 func (f *Fs) dial(ctx context.Context, network, addr string) (*conn, error) {
 	dialer := fshttp.NewDialer(ctx)
+	dialer.SetLimiter(accounting.GetLimiter(f.ctx))
 	tconn, err := dialer.DialContext(ctx, network, addr)
 	if err != nil {
 		return nil, err
@@ -157,7 +158,7 @@ func (c *conn) mountShare(share string) (err error) {
 
 // Get a SMB connection from the pool, or open a new one
 func (f *Fs) getConnection(ctx context.Context, share string) (c *conn, err error) {
-	accounting.LimitTPS(ctx)
+	accounting.LimitTPS(ctx, accounting.GetLimiter(f.ctx))
 	f.poolMu.Lock()
 	for len(f.pool) > 0 {
 		c = f.pool[0]
@@ -173,7 +174,7 @@ func (f *Fs) getConnection(ctx context.Context, share string) (c *conn, err erro
 	if c != nil {
 		return c, nil
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		c, err = f.newConnection(ctx, share)
 		if err != nil {
 			return true, err
@@ -120,7 +120,7 @@ func init() {
 				srv := rest.NewClient(fshttp.NewClient(ctx)).SetRoot(rootURL) //  FIXME
 
 				// FIXME
-				// err = f.pacer.Call(func() (bool, error) {
+				// err = f.pacer.CallContext(ctx, func() (bool, error) {
 				resp, err = srv.CallXML(context.Background(), &opts, &authRequest, nil)
 				//	return shouldRetry(ctx, resp, err)
 				//})
@@ -310,7 +310,7 @@ func (f *Fs) readMetaDataForID(ctx context.Context, ID string) (info *api.File,
 		Method:  "GET",
 		RootURL: ID,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -347,7 +347,7 @@ func (f *Fs) getAuthToken(ctx context.Context) error {
 			"Authorization": "", // unset Authorization
 		},
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &authRequest, &authResponse)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -395,7 +395,7 @@ func (f *Fs) getUser(ctx context.Context) (user *api.User, err error) {
 		Method: "GET",
 		Path:   "/user",
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &user)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -589,7 +589,7 @@ func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (newID string,
 			Name: f.opt.Enc.FromStandardName(leaf),
 		}
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, mkdir, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -640,7 +640,7 @@ OUTER:
 
 		var result api.CollectionContents
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallXML(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -796,7 +796,7 @@ func (f *Fs) delete(ctx context.Context, isFile bool, id string, remote string,
 			RootURL:    id,
 			NoResponse: true,
 		}
-		return f.pacer.Call(func() (bool, error) {
+		return f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err := f.srv.Call(ctx, &opts)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -909,7 +909,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (dst fs.Obj
 		Source: srcObj.id,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &copyFile, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -963,7 +963,7 @@ func (f *Fs) moveFile(ctx context.Context, id, leaf, directoryID string) (info *
 		Parent: directoryID,
 	}
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &move, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -993,7 +993,7 @@ func (f *Fs) moveDir(ctx context.Context, id, leaf, directoryID string) (err err
 		Parent: directoryID,
 	}
 	var resp *http.Response
-	return f.pacer.Call(func() (bool, error) {
+	return f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &move, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1082,7 +1082,7 @@ func (f *Fs) PublicLink(ctx context.Context, remote string, expire fs.Duration,
 	}
 	var resp *http.Response
 	var info *api.File
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &linkFile, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1210,7 +1210,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:    "/data",
 		Options: options,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1232,7 +1232,7 @@ func (f *Fs) createFile(ctx context.Context, pathID, leaf, mimeType string) (new
 		Name:      f.opt.Enc.FromStandardName(leaf),
 		MediaType: mimeType,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, &mkdir, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1290,7 +1290,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	if size >= 0 {
 		opts.ContentLength = &size
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -595,7 +595,7 @@ func NewFsWithConnection(ctx context.Context, opt *Options, name, root string, c
 		var info swift.Object
 		var err error
 		encodedDirectory := f.opt.Enc.FromStandardPath(f.rootDirectory)
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var rxHeaders swift.Headers
 			info, rxHeaders, err = f.c.Object(ctx, f.rootContainer, encodedDirectory)
 			return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -704,7 +704,7 @@ func (f *Fs) listContainerRoot(ctx context.Context, container, directory, prefix
 	return f.c.ObjectsWalk(ctx, container, &opts, func(ctx context.Context, opts *swift.ObjectsOpts) (any, error) {
 		var objects []swift.Object
 		var err error
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			objects, err = f.c.Objects(ctx, container, opts)
 			return shouldRetry(ctx, err)
 		})
@@ -792,7 +792,7 @@ func (f *Fs) listDir(ctx context.Context, container, directory, prefix string, a
 // listContainers lists the containers
 func (f *Fs) listContainers(ctx context.Context) (entries fs.DirEntries, err error) {
 	var containers []swift.Container
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		containers, err = f.c.ContainersAll(ctx, nil)
 		return shouldRetry(ctx, err)
 	})
@@ -917,7 +917,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 	var used, objects, total int64
 	if f.rootContainer != "" {
 		var container swift.Container
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			container, _, err = f.c.Container(ctx, f.rootContainer)
 			return shouldRetry(ctx, err)
 		})
@@ -929,7 +929,7 @@ func (f *Fs) About(ctx context.Context) (usage *fs.Usage, err error) {
 		total = container.QuotaBytes
 	} else {
 		var containers []swift.Container
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			containers, err = f.c.ContainersAll(ctx, nil)
 			return shouldRetry(ctx, err)
 		})
@@ -985,7 +985,7 @@ func (f *Fs) makeContainer(ctx context.Context, container string) error {
 		// Check to see if container exists first
 		var err error = swift.ContainerNotFound
 		if !f.noCheckContainer {
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				var rxHeaders swift.Headers
 				_, rxHeaders, err = f.c.Container(ctx, container)
 				return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -996,7 +996,7 @@ func (f *Fs) makeContainer(ctx context.Context, container string) error {
 			if f.opt.StoragePolicy != "" {
 				headers["X-Storage-Policy"] = f.opt.StoragePolicy
 			}
-			err = f.pacer.Call(func() (bool, error) {
+			err = f.pacer.CallContext(ctx, func() (bool, error) {
 				err = f.c.ContainerCreate(ctx, container, headers)
 				return shouldRetry(ctx, err)
 			})
@@ -1017,7 +1017,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 		return nil
 	}
 	err := f.cache.Remove(container, func() error {
-		err := f.pacer.Call(func() (bool, error) {
+		err := f.pacer.CallContext(ctx, func() (bool, error) {
 			err := f.c.ContainerDelete(ctx, container)
 			return shouldRetry(ctx, err)
 		})
@@ -1094,7 +1094,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		err = f.copyLargeObject(ctx, srcObj, dstContainer, dstPath)
 	} else {
 		srcContainer, srcPath := srcObj.split()
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var rxHeaders swift.Headers
 			rxHeaders, err = f.c.ObjectCopy(ctx, srcContainer, srcPath, dstContainer, dstPath, nil)
 			return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -1189,7 +1189,7 @@ func (su *segmentedUpload) uploadManifest(ctx context.Context, contentType strin
 	headers["Content-Length"] = "0" // set Content-Length as we know it
 	emptyReader := bytes.NewReader(nil)
 	fs.Debugf(su.f, "uploading manifest %q to %q", su.dstPath, su.dstContainer)
-	err = su.f.pacer.Call(func() (bool, error) {
+	err = su.f.pacer.CallContext(ctx, func() (bool, error) {
 		var rxHeaders swift.Headers
 		rxHeaders, err = su.f.c.ObjectPut(ctx, su.dstContainer, su.dstPath, emptyReader, true, "", contentType, headers)
 		return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -1213,7 +1213,7 @@ func (f *Fs) copyLargeObject(ctx context.Context, src *Object, dstContainer stri
 	defer atexit.OnError(&err, su.onFail)()
 	for i, srcSegment := range srcSegments {
 		dstSegment := su.segmentPath(i)
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			var rxHeaders swift.Headers
 			rxHeaders, err = f.c.ObjectCopy(ctx, srcSegmentsContainer, srcSegment, su.container, dstSegment, nil)
 			return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -1361,7 +1361,7 @@ func (o *Object) readMetaData(ctx context.Context) (err error) {
 	var info swift.Object
 	var h swift.Headers
 	container, containerPath := o.split()
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		info, h, err = o.fs.c.Object(ctx, container, containerPath)
 		return shouldRetryHeaders(ctx, h, err)
 	})
@@ -1417,7 +1417,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 		}
 	}
 	container, containerPath := o.split()
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err = o.fs.c.ObjectUpdate(ctx, container, containerPath, newHeaders)
 		return shouldRetry(ctx, err)
 	})
@@ -1437,7 +1437,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	headers := fs.OpenOptionHeaders(options)
 	_, isRanging := headers["Range"]
 	container, containerPath := o.split()
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		var rxHeaders swift.Headers
 		in, rxHeaders, err = o.fs.c.ObjectOpen(ctx, container, containerPath, !isRanging, headers)
 		return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -1521,7 +1521,7 @@ func (o *Object) updateChunks(ctx context.Context, in0 io.Reader, headers swift.
 		segmentReader := io.LimitReader(in, n)
 		segmentPath := su.segmentPath(i)
 		fs.Debugf(o, "Uploading segment file %q into %q", segmentPath, su.container)
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			var rxHeaders swift.Headers
 			rxHeaders, err = o.fs.c.ObjectPut(ctx, su.container, segmentPath, segmentReader, true, "", "", headers)
 			return shouldRetryHeaders(ctx, rxHeaders, err)
@@ -1586,7 +1586,7 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 			in = inCount
 		}
 		var rxHeaders swift.Headers
-		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+		err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 			rxHeaders, err = o.fs.c.ObjectPut(ctx, container, containerPath, in, true, "", contentType, headers)
 			return shouldRetryHeaders(ctx, rxHeaders, err)
 		})
@@ -1647,7 +1647,7 @@ func (o *Object) Remove(ctx context.Context) (err error) {
 		}
 	}
 	// Remove file/manifest first
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		err = o.fs.c.ObjectDelete(ctx, container, containerPath)
 		if err == swift.ObjectNotFound {
 			fs.Errorf(o, "Dangling object - ignoring: %v", err)
@@ -249,7 +249,7 @@ func (f *Fs) authenticate(ctx context.Context) (response *api.AuthenticateRespon
 		Password: clearPassword,
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, &authRequest, &response)
 		return f.shouldRetry(ctx, httpResp, err, false)
 	})
@@ -306,7 +306,7 @@ func (session *UploadSession) renewUploadSession(ctx context.Context) error {
 	var err error
 	var response api.CreateUploadURLResponse
 
-	err = session.Filesystem.pacer.Call(func() (bool, error) {
+	err = session.Filesystem.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := session.Filesystem.rest.CallJSON(ctx, &opts, &createUploadURLReq, &response)
 		return session.Filesystem.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -356,7 +356,7 @@ func (f *Fs) uploadUnchecked(ctx context.Context, name, parentSlug string, info
 
 	var uploadResponse api.SendFilePayloadResponse
 
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		httpResp, err := f.cdn.CallJSON(ctx, &opts, nil, &uploadResponse)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -408,7 +408,7 @@ func (f *Fs) uploadUnchecked(ctx context.Context, name, parentSlug string, info
 		Parameters: url.Values{},
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := session.Filesystem.rest.CallJSON(ctx, &opts, &updateReq, &updateResponse)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -434,7 +434,7 @@ func (f *Fs) uploadUnchecked(ctx context.Context, name, parentSlug string, info
 
 	var commitResponse api.CommitUploadBatchResponse
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := session.Filesystem.rest.CallJSON(ctx, &opts, &commitRequest, &commitResponse)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -530,7 +530,7 @@ func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	}
 
 	req := api.DeleteFoldersRequest{Slugs: []string{slug}}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, req, nil)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -596,7 +596,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 		NewParentFolderSlug: dstParentSlug,
 	}
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, &req, nil)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -619,7 +619,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 			NewName: dstName,
 		}
 
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			httpResp, err := f.rest.CallJSON(ctx, &opts, &renameReq, nil)
 			return f.shouldRetry(ctx, httpResp, err, true)
 		})
@@ -737,7 +737,7 @@ func (o *Object) updateFileProperties(ctx context.Context, req any) (err error)
 		Path:   "/v8/file/" + o.slug + "/private",
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := o.fs.rest.CallJSON(ctx, &opts, &req, &resp)
 		return o.fs.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -784,7 +784,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.Read
 
 	var resp *api.GetDownloadLinkResponse
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := o.fs.rest.CallJSON(ctx, &opts, &req, &resp)
 		return o.fs.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -800,7 +800,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.Read
 
 	var httpResp *http.Response
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err = o.fs.cdn.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -894,7 +894,7 @@ func (o *Object) Remove(ctx context.Context) error {
 			Method: "DELETE",
 			Path:   "/v6/file/" + o.slug + "/private",
 		}
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			httpResp, err := o.fs.rest.CallJSON(ctx, &opts, nil, nil)
 			return o.fs.shouldRetry(ctx, httpResp, err, true)
 		})
@@ -1010,7 +1010,7 @@ func (f *Fs) CreateDir(ctx context.Context, parentSlug, leaf string) (newID stri
 		Name:             f.opt.Enc.FromStandardName(leaf),
 		ParentFolderSlug: parentSlug,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, &mkdir, &folder)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -1156,7 +1156,7 @@ func (f *Fs) fetchListFolderPage(
 
 	var respBody *api.ListFoldersResponse
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, nil, &respBody)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
@@ -1223,7 +1223,7 @@ func (f *Fs) fetchListFilePage(
 
 	var respBody *api.ListFilesResponse
 
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		httpResp, err := f.rest.CallJSON(ctx, &opts, nil, &respBody)
 		return f.shouldRetry(ctx, httpResp, err, true)
 	})
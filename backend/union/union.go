@@ -4,6 +4,7 @@ package union
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -489,6 +490,42 @@ func (f *Fs) ChangeNotify(ctx context.Context, fn func(string, fs.EntryType), ch
 	}()
 }
 
+// ChangesSince calls notifyFunc with each path which has changed
+// since token was issued and returns the token to use next time.
+//
+// The token holds the tokens of each upstream, keyed by the
+// upstream's config string.
+func (f *Fs) ChangesSince(ctx context.Context, token string, fn func(string, fs.EntryType)) (newToken string, err error) {
+	tokens := map[string]string{}
+	if token != "" {
+		err = json.Unmarshal([]byte(token), &tokens)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", fs.ErrorChangeTokenInvalid, err)
+		}
+	}
+	newTokens := make(map[string]string, len(f.upstreams))
+	for _, u := range f.upstreams {
+		do := u.Features().ChangesSince
+		if do == nil {
+			return "", fs.ErrorNotImplemented
+		}
+		key := fs.ConfigString(u)
+		uToken, found := tokens[key]
+		if token != "" && !found {
+			return "", fmt.Errorf("%w: no token for upstream %q", fs.ErrorChangeTokenInvalid, key)
+		}
+		newTokens[key], err = do(ctx, uToken, fn)
+		if err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(newTokens)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // DirCacheFlush resets the directory cache - used in testing
 // as an optional interface
 func (f *Fs) DirCacheFlush() {
@@ -1071,6 +1108,7 @@ var (
 	_ fs.MkdirMetadataer = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangesSincer   = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
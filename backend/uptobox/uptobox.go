@@ -285,7 +285,7 @@ func (f *Fs) readMetaDataForPath(ctx context.Context, path string, options *api.
 	var err error
 	var info api.ReadMetadataResponse
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -418,7 +418,7 @@ func (f *Fs) uploadFile(ctx context.Context, in io.Reader, size int64, filename
 	var err error
 	var resp *http.Response
 	var ul api.UploadResponse
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &ul)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -448,7 +448,7 @@ func (f *Fs) move(ctx context.Context, dstPath string, fileID string) (err error
 
 	var resp *http.Response
 	var info api.UpdateResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mv, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -470,7 +470,7 @@ func (f *Fs) updateFileInformation(ctx context.Context, update *api.UpdateFileIn
 
 	var resp *http.Response
 	var info api.UpdateResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, update, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -500,7 +500,7 @@ func (f *Fs) putUnchecked(ctx context.Context, in io.Reader, remote string, size
 		Token: f.opt.AccessToken,
 	}
 	var info api.UploadInfo
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.CallJSON(ctx, &opts, &token, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -605,7 +605,7 @@ func (f *Fs) CreateDir(ctx context.Context, base string, leaf string) (err error
 		Path:  f.opt.Enc.FromStandardPath(base),
 		Token: f.opt.AccessToken,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &apiErr)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -658,7 +658,7 @@ func (f *Fs) purge(ctx context.Context, folderID uint64) (err error) {
 		FolderID: folderID,
 		Token:    f.opt.AccessToken,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &rm, &apiErr)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -746,7 +746,7 @@ func (f *Fs) renameDir(ctx context.Context, folderID uint64, newName string) (er
 		FolderID: folderID,
 		NewName:  newName,
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &rename, &apiErr)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -829,7 +829,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 		}
 		var resp *http.Response
 		var apiErr api.Error
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, &move, &apiErr)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -870,7 +870,7 @@ func (f *Fs) copy(ctx context.Context, dstPath string, fileID string) (err error
 
 	var resp *http.Response
 	var info api.UpdateResponse
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &cp, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -993,7 +993,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 	var dl api.Download
 	var resp *http.Response
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.CallJSON(ctx, &opts, nil, &dl)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1008,7 +1008,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Options: options,
 	}
 
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1064,7 +1064,7 @@ func (o *Object) Remove(ctx context.Context) error {
 		FileCodes: o.code,
 	}
 	var info api.UpdateResponse
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallJSON(ctx, &opts, &delete, &info)
 		return shouldRetry(ctx, resp, err)
 	})
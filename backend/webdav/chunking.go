@@ -158,7 +158,7 @@ func (o *Object) createChunksUploadDirectory(ctx context.Context) (string, error
 		NoResponse: true,
 		RootURL:    o.fs.chunksUploadURL,
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -187,7 +187,7 @@ func (o *Object) mergeChunks(ctx context.Context, uploadDir string, options []fs
 	opts.ExtraHeaders["Destination"] = destinationURL.String()
 	sleepTime := 5 * time.Second
 	wasLocked := false
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetryChunkMerge(ctx, resp, err, &sleepTime, &wasLocked)
 	})
@@ -206,7 +206,7 @@ func (o *Object) purgeUploadedChunks(ctx context.Context, uploadDir string) erro
 		RootURL:    o.fs.chunksUploadURL,
 	}
 
-	err := o.fs.pacer.Call(func() (bool, error) {
+	err := o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.CallXML(ctx, &opts, nil, nil)
 
 		// directory doesn't exist, no need to purge
@@ -91,7 +91,7 @@ func (u *Uploader) uploadChunk(ctx context.Context, body io.Reader, size int64,
 
 	var newOffset int64
 
-	err = u.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = u.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		res, err := u.fs.srv.Call(ctx, &opts)
 		return u.fs.shouldRetryChunk(ctx, res, err, &newOffset)
 	})
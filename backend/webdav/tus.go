@@ -92,7 +92,7 @@ func (o *Object) CreateUploader(ctx context.Context, u *Upload, options ...fs.Op
 
 	var tusLocation string
 	// rclone http call
-	err := o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err := o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		var retry bool
 		res, err := o.fs.srv.Call(ctx, &opts)
 		retry, tusLocation, err = o.fs.getTusLocationOrRetry(ctx, res, err)
@@ -352,7 +352,7 @@ func (f *Fs) readMetaDataForPath(ctx context.Context, path string, depth string)
 	}
 	var result api.Multistatus
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -784,7 +784,7 @@ func (f *Fs) listAll(ctx context.Context, dir string, directoriesOnly bool, file
 	}
 	var result api.Multistatus
 	var resp *http.Response
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -964,7 +964,7 @@ func (f *Fs) _dirExists(ctx context.Context, dirPath string) (exists bool) {
 	var result api.Multistatus
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &result)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -986,7 +986,7 @@ func (f *Fs) _mkdir(ctx context.Context, dirPath string) error {
 		Path:       dirPath,
 		NoResponse: true,
 	}
-	err := f.pacer.Call(func() (bool, error) {
+	err := f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := f.srv.Call(ctx, &opts)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1075,7 +1075,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) error {
 	}
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, nil)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1140,7 +1140,7 @@ func (f *Fs) copyOrMove(ctx context.Context, src fs.Object, remote string, metho
 		opts.ExtraHeaders["X-OC-Mtime"] = fmt.Sprintf("%d", src.ModTime(ctx).Unix())
 	}
 	// Direct the MOVE/COPY to the source server
-	err = srcFs.pacer.Call(func() (bool, error) {
+	err = srcFs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = srcFs.srv.Call(ctx, &opts)
 		return srcFs.shouldRetry(ctx, resp, err)
 	})
@@ -1244,7 +1244,7 @@ func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string
 		},
 	}
 	// Direct the MOVE/COPY to the source server
-	err = srcFs.pacer.Call(func() (bool, error) {
+	err = srcFs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = srcFs.srv.Call(ctx, &opts)
 		return srcFs.shouldRetry(ctx, resp, err)
 	})
@@ -1286,7 +1286,7 @@ func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	var q api.Quota
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallXML(ctx, &opts, nil, &q)
 		return f.shouldRetry(ctx, resp, err)
 	})
@@ -1440,7 +1440,7 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 		var result api.Multistatus
 		var resp *http.Response
 		var err error
-		err = o.fs.pacer.Call(func() (bool, error) {
+		err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = o.fs.srv.CallXML(ctx, &opts, nil, &result)
 			return o.fs.shouldRetry(ctx, resp, err)
 		})
@@ -1491,7 +1491,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		},
 		AuthRedirect: o.fs.opt.AuthRedirect, // allow redirects to preserve Auth
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -1589,7 +1589,7 @@ func (o *Object) updateSimple(ctx context.Context, body io.Reader, getBody func(
 		ExtraHeaders:  extraHeaders,
 		RootURL:       rootURL,
 	}
-	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
+	err = o.fs.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -1614,7 +1614,7 @@ func (o *Object) Remove(ctx context.Context) error {
 		Path:       o.filePath(),
 		NoResponse: true,
 	}
-	return o.fs.pacer.Call(func() (bool, error) {
+	return o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err := o.fs.srv.Call(ctx, &opts)
 		return o.fs.shouldRetry(ctx, resp, err)
 	})
@@ -482,7 +482,7 @@ func (f *Fs) readMetaDataForID(ctx context.Context, id string) (*api.Item, error
 	var result *api.ItemInfo
 	var resp *http.Response
 	var err error
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -589,7 +589,7 @@ OUTER:
 	for {
 		var result api.ItemList
 		var resp *http.Response
-		err = f.pacer.Call(func() (bool, error) {
+		err = f.pacer.CallContext(ctx, func() (bool, error) {
 			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
 			return shouldRetry(ctx, resp, err)
 		})
@@ -702,7 +702,7 @@ func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (newID string,
 			Type: "files",
 		},
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &mkdir, &info)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -780,7 +780,7 @@ func (f *Fs) uploadLargeFile(ctx context.Context, name string, parent string, si
 	var err error
 	var resp *http.Response
 	var uploadResponse *api.LargeUploadResponse
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = f.uploadsrv.CallJSON(ctx, &opts, nil, &uploadResponse)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -836,7 +836,7 @@ func (f *Fs) upload(ctx context.Context, name string, parent string, size int64,
 
 	var resp *http.Response
 	var uploadResponse *api.UploadResponse
-	err = f.pacer.CallNoRetry(func() (bool, error) {
+	err = f.pacer.CallNoRetryContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, nil, &uploadResponse)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -935,7 +935,7 @@ func (f *Fs) deleteObject(ctx context.Context, id string) (err error) {
 			},
 		},
 	}
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &delete, nil)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1005,7 +1005,7 @@ func (f *Fs) rename(ctx context.Context, id, name string) (item *api.Item, err e
 		},
 	}
 	var result *api.ItemInfo
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &rename, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1058,7 +1058,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	var resp *http.Response
 	var result *api.ItemList
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &copyFile, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1103,7 +1103,7 @@ func (f *Fs) move(ctx context.Context, srcID, parentID string) (item *api.Item,
 	}
 	var resp *http.Response
 	var result *api.ItemList
-	err = f.pacer.Call(func() (bool, error) {
+	err = f.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = f.srv.CallJSON(ctx, &opts, &moveFile, &result)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -1346,7 +1346,7 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 		Path:    "/download/" + o.id,
 		Options: options,
 	}
-	err = o.fs.pacer.Call(func() (bool, error) {
+	err = o.fs.pacer.CallContext(ctx, func() (bool, error) {
 		resp, err = o.fs.downloadsrv.Call(ctx, &opts)
 		return shouldRetry(ctx, resp, err)
 	})
@@ -32,6 +32,7 @@ docs = [
     "fichier.md",
     "alias.md",
     "s3.md",
+    "archive.md",
     "b2.md",
     "box.md",
     "cache.md",
@@ -41,6 +42,7 @@ docs = [
     "crypt.md",
     "compress.md",
     "combine.md",
+    "dedup.md",
     "doi.md",
     "dropbox.md",
     "filefabric.md",
@@ -0,0 +1,194 @@
+// Package archive provides the archive command.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/spf13/cobra"
+)
+
+// Archive formats
+const (
+	FormatZip   = "zip"
+	FormatTar   = "tar"
+	FormatTarGz = "tar.gz"
+)
+
+// formatSuffixes maps file name suffixes onto formats
+var formatSuffixes = []struct {
+	suffix string
+	format string
+}{
+	{".zip", FormatZip},
+	{".tar.gz", FormatTarGz},
+	{".tgz", FormatTarGz},
+	{".tar", FormatTar},
+}
+
+var (
+	format string
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	commandDefinition.AddCommand(createCommand)
+	commandDefinition.AddCommand(extractCommand)
+	commandDefinition.AddCommand(listCommand)
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "archive <action> [opts] <source> [<destination>]",
+	Short: `Create, extract and list archives on remotes.`,
+	Long: `Create, extract and list zip and tar archives directly on remotes.
+
+Archives are read from and written to remotes as streams so nothing
+is stored on the local disk. Zip archives are read using range requests
+so their contents can be listed and extracted without reading the whole
+archive.
+
+The archive format is worked out from the file name, or can be set
+with ` + "`--format`" + `. The supported formats are:
+
+- ` + "`zip`" + ` - ` + "`.zip`" + `
+- ` + "`tar`" + ` - ` + "`.tar`" + `
+- ` + "`tar.gz`" + ` - ` + "`.tar.gz`" + ` or ` + "`.tgz`" + `
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+}
+
+// DetectFormat works out the archive format from the file name
+// unless override is set, in which case it is checked and returned.
+func DetectFormat(name, override string) (string, error) {
+	if override != "" {
+		for _, x := range formatSuffixes {
+			if x.format == override {
+				return override, nil
+			}
+		}
+		return "", fmt.Errorf("unknown archive format %q", override)
+	}
+	lowerName := strings.ToLower(name)
+	for _, x := range formatSuffixes {
+		if strings.HasSuffix(lowerName, x.suffix) {
+			return x.format, nil
+		}
+	}
+	return "", fmt.Errorf("can't work out archive format from %q - use --format", name)
+}
+
+// Member describes an entry in an archive
+type Member struct {
+	Name    string    // path within the archive
+	Size    int64     // uncompressed size
+	ModTime time.Time // modification time
+	IsDir   bool      // set if this is a directory
+}
+
+// memberFn is called for each member of an archive by walkArchive.
+//
+// open returns the contents of the member and may only be called
+// during the call. It is nil for directories.
+type memberFn func(m Member, open func() (io.ReadCloser, error)) error
+
+// walkArchive calls fn for each member of the archive in o
+func walkArchive(ctx context.Context, o fs.Object, format string, fn memberFn) error {
+	switch format {
+	case FormatZip:
+		return walkZip(ctx, o, fn)
+	case FormatTar, FormatTarGz:
+		return walkTar(ctx, o, format, fn)
+	}
+	return fmt.Errorf("unknown archive format %q", format)
+}
+
+// walkZip calls fn for each member of the zip archive in o
+//
+// The central directory and each member are read with range requests.
+func walkZip(ctx context.Context, o fs.Object, fn memberFn) (err error) {
+	ra := object.NewReaderAt(ctx, o)
+	defer fs.CheckClose(ra, &err)
+	zr, err := zip.NewReader(ra, o.Size())
+	if err != nil {
+		return fmt.Errorf("failed to read zip directory: %w", err)
+	}
+	for _, file := range zr.File {
+		m := Member{
+			Name:    file.Name,
+			Size:    int64(file.UncompressedSize64),
+			ModTime: file.Modified,
+			IsDir:   file.FileInfo().IsDir(),
+		}
+		var open func() (io.ReadCloser, error)
+		if !m.IsDir {
+			open = file.Open
+		}
+		if err = fn(m, open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkTar calls fn for each member of the tar archive in o
+//
+// The archive is read sequentially from the start.
+func walkTar(ctx context.Context, o fs.Object, format string, fn memberFn) (err error) {
+	rc, err := o.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(rc, &err)
+	var in io.Reader = rc
+	if format == FormatTarGz {
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		defer fs.CheckClose(gz, &err)
+		in = gz
+	}
+	tarReader := tar.NewReader(in)
+	open := func() (io.ReadCloser, error) {
+		return io.NopCloser(tarReader), nil
+	}
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+		m := Member{
+			Name:    hdr.Name,
+			Size:    hdr.Size,
+			ModTime: hdr.ModTime,
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			m.IsDir = true
+			m.Size = 0
+			err = fn(m, nil)
+		case tar.TypeReg:
+			err = fn(m, open)
+		default:
+			fs.Debugf(o, "Skipping %q: unsupported tar entry type %q", hdr.Name, string(hdr.Typeflag))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
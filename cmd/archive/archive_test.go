@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = fstest.Time("2017-02-03T04:05:06Z")
+	t2 = fstest.Time("2020-06-07T08:09:10Z")
+)
+
+// TestMain drives the tests
+func TestMain(m *testing.M) {
+	fstest.TestMain(m)
+}
+
+func TestDetectFormat(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		override string
+		want     string
+		wantErr  bool
+	}{
+		{"a.zip", "", FormatZip, false},
+		{"dir/A.ZIP", "", FormatZip, false},
+		{"a.tar", "", FormatTar, false},
+		{"a.tar.gz", "", FormatTarGz, false},
+		{"a.tgz", "", FormatTarGz, false},
+		{"a.txt", "", "", true},
+		{"a.txt", "tar", FormatTar, false},
+		{"a.zip", "rar", "", true},
+	} {
+		got, err := DetectFormat(test.name, test.override)
+		if test.wantErr {
+			assert.Error(t, err, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+		}
+		assert.Equal(t, test.want, got, test.name)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	for _, archiveName := range []string{"test.zip", "test.tar", "test.tar.gz"} {
+		t.Run(archiveName, func(t *testing.T) {
+			testArchive(t, archiveName)
+		})
+	}
+}
+
+func testArchive(t *testing.T, archiveName string) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	file1 := r.WriteFile("file1.txt", "hello world", t1)
+	file2 := r.WriteFile("dir/file2.txt", "potato potato potato", t2)
+	r.CheckLocalItems(t, file1, file2)
+
+	// Create the archive
+	dst, err := Create(ctx, r.Flocal, r.Fremote, archiveName, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, archiveName, dst.Remote())
+
+	// List it
+	var out bytes.Buffer
+	require.NoError(t, List(ctx, dst, "", false, &out))
+	assert.Equal(t, "dir/\ndir/file2.txt\nfile1.txt\n", out.String())
+
+	out.Reset()
+	require.NoError(t, List(ctx, dst, "", true, &out))
+	assert.Contains(t, out.String(), "          20 ")
+	assert.Contains(t, out.String(), " dir/file2.txt\n")
+
+	// Extract it
+	fextract, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, Extract(ctx, dst, "", fextract))
+	fstest.CheckListingWithPrecision(t, fextract, []fstest.Item{file1, file2}, []string{"dir"}, time.Second)
+}
+
+func TestArchivePrefix(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	r.WriteFile("file1.txt", "hello world", t1)
+
+	dst, err := Create(ctx, r.Flocal, r.Fremote, "out.bin", FormatTar, "/backup/")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, List(ctx, dst, FormatTar, false, &out))
+	assert.Equal(t, "backup/\nbackup/file1.txt\n", out.String())
+
+	// Check the format is needed
+	_, err = Create(ctx, r.Flocal, r.Fremote, "out.bin", "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,264 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+	libarchive "github.com/rclone/rclone/lib/archive"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prefix string
+)
+
+func init() {
+	cmdFlags := createCommand.Flags()
+	flags.StringVarP(cmdFlags, &format, "format", "", "", "Archive format (zip|tar|tar.gz) - default is from the file name", "")
+	flags.StringVarP(cmdFlags, &prefix, "prefix", "", "", "Directory to put the files in inside the archive", "")
+}
+
+var createCommand = &cobra.Command{
+	Use:   "create [flags] source:path dest:path/archive.zip",
+	Short: `Create an archive from a remote directory.`,
+	Long: `Create an archive containing the files in source:path and upload it
+to dest:path/archive.zip.
+
+The archive is streamed straight to the destination as it is made so
+nothing is stored on the local disk. Filters may be used to choose which
+files go into the archive.
+
+` + "```sh" + `
+rclone archive create remote:photos remote:backups/photos.tar.gz
+rclone archive create --include "*.txt" /home/user/docs s3:bucket/docs.zip
+` + "```" + `
+
+Use ` + "`--prefix`" + ` to put the files inside a directory in the
+archive.
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc := cmd.NewFsSrc(args[:1])
+		fdst, dstFileName := cmd.NewFsDstFile(args[1:2])
+		cmd.Run(false, true, command, func() error {
+			_, err := Create(context.Background(), fsrc, fdst, dstFileName, format, prefix)
+			return err
+		})
+	},
+}
+
+// archiveWriter is implemented by the writers for each archive format
+type archiveWriter interface {
+	// addDir adds a directory called name
+	addDir(name string, modTime time.Time) error
+	// addFile adds a file called name with the contents read from in
+	addFile(name string, size int64, modTime time.Time, in io.Reader) error
+	// Close finishes the archive but doesn't close the underlying writer
+	Close() error
+}
+
+// newArchiveWriter makes an archiveWriter for format writing to w
+func newArchiveWriter(w io.Writer, format string) (archiveWriter, error) {
+	switch format {
+	case FormatZip:
+		return &zipWriter{zw: zip.NewWriter(w)}, nil
+	case FormatTar:
+		return &tarWriter{tw: tar.NewWriter(w)}, nil
+	case FormatTarGz:
+		gz := gzip.NewWriter(w)
+		return &tarWriter{tw: tar.NewWriter(gz), gz: gz}, nil
+	}
+	return nil, fmt.Errorf("unknown archive format %q", format)
+}
+
+// zipWriter writes zip archives
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (z *zipWriter) addDir(name string, modTime time.Time) error {
+	_, err := z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name + "/",
+		Method:   zip.Store,
+		Modified: modTime,
+	})
+	return err
+}
+
+func (z *zipWriter) addFile(name string, size int64, modTime time.Time, in io.Reader) error {
+	w, err := z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, in)
+	return err
+}
+
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
+
+// tarWriter writes tar archives, optionally gzipped
+type tarWriter struct {
+	tw *tar.Writer
+	gz *gzip.Writer // nil if not compressing
+}
+
+func (t *tarWriter) addDir(name string, modTime time.Time) error {
+	return t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     0755,
+		ModTime:  modTime,
+		Format:   tar.FormatPAX,
+	})
+}
+
+func (t *tarWriter) addFile(name string, size int64, modTime time.Time, in io.Reader) error {
+	if size < 0 {
+		return errors.New("can't add files of unknown size to tar archives")
+	}
+	err := t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  modTime,
+		Format:   tar.FormatPAX,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(t.tw, in)
+	return err
+}
+
+func (t *tarWriter) Close() error {
+	err := t.tw.Close()
+	if t.gz != nil {
+		if gzErr := t.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	return err
+}
+
+// Create makes an archive containing the files in fsrc and uploads
+// it to dstFileName in fdst.
+//
+// If format is "" it is worked out from dstFileName. The names in the
+// archive are prefixed with prefix if set.
+func Create(ctx context.Context, fsrc fs.Fs, fdst fs.Fs, dstFileName string, format string, prefix string) (fs.Object, error) {
+	format, err := DetectFormat(dstFileName, format)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	errChan := make(chan error, 1)
+	go func() {
+		err := writeArchive(ctx, fsrc, pipeWriter, format, prefix)
+		_ = pipeWriter.CloseWithError(err)
+		errChan <- err
+	}()
+	dst, err := operations.Rcat(ctx, fdst, dstFileName, pipeReader, time.Now(), nil)
+	// Make sure writeArchive stops if the upload failed
+	_ = pipeReader.CloseWithError(errors.New("archive upload stopped"))
+	writeErr := <-errChan
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload archive: %w", err)
+	}
+	if writeErr != nil {
+		if dst != nil {
+			if removeErr := dst.Remove(ctx); removeErr != nil {
+				fs.Errorf(dst, "Failed to remove incomplete archive: %v", removeErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to create archive: %w", writeErr)
+	}
+	return dst, nil
+}
+
+// writeArchive writes an archive of format containing the files in
+// fsrc to w.
+func writeArchive(ctx context.Context, fsrc fs.Fs, w io.Writer, format string, prefix string) (err error) {
+	// Read the whole listing first so the archive is in a
+	// predictable order.
+	var entries fs.DirEntries
+	err = walk.ListR(ctx, fsrc, "", true, -1, walk.ListAll, func(tranche fs.DirEntries) error {
+		entries = append(entries, tranche...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list source: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Remote() < entries[j].Remote()
+	})
+
+	aw, err := newArchiveWriter(w, format)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(aw, &err)
+
+	prefix = libarchive.CleanName(prefix)
+	if prefix != "" {
+		if err = aw.addDir(prefix, time.Now()); err != nil {
+			return err
+		}
+	}
+	for _, entry := range entries {
+		name := path.Join(prefix, entry.Remote())
+		switch x := entry.(type) {
+		case fs.Directory:
+			err = aw.addDir(name, x.ModTime(ctx))
+		case fs.Object:
+			err = addObject(ctx, aw, name, x)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add %q: %w", entry.Remote(), err)
+		}
+	}
+	return nil
+}
+
+// addObject adds the contents of o to the archive as name
+func addObject(ctx context.Context, aw archiveWriter, name string, o fs.Object) (err error) {
+	tr := accounting.Stats(ctx).NewTransfer(o, nil)
+	defer func() {
+		tr.Done(ctx, err)
+	}()
+	var options []fs.OpenOption
+	for _, option := range fs.GetConfig(ctx).DownloadHeaders {
+		options = append(options, option)
+	}
+	rc, err := operations.Open(ctx, o, options...)
+	if err != nil {
+		return err
+	}
+	in := tr.Account(ctx, rc).WithBuffer()
+	defer fs.CheckClose(in, &err)
+	return aw.addFile(name, o.Size(), o.ModTime(ctx), in)
+}
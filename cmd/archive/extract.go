@@ -0,0 +1,100 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/operations"
+	libarchive "github.com/rclone/rclone/lib/archive"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmdFlags := extractCommand.Flags()
+	flags.StringVarP(cmdFlags, &format, "format", "", "", "Archive format (zip|tar|tar.gz) - default is from the file name", "")
+}
+
+var extractCommand = &cobra.Command{
+	Use:   "extract [flags] source:path/archive.zip dest:path",
+	Short: `Extract an archive on a remote to a remote directory.`,
+	Long: `Extract the archive at source:path/archive.zip into dest:path.
+
+The archive is read from the source and its contents uploaded to the
+destination as they are read so nothing is stored on the local disk.
+Zip archives are read with range requests, and tar archives are read
+in a single pass.
+
+Filters may be used to choose which files are extracted.
+
+` + "```sh" + `
+rclone archive extract remote:backups/photos.tar.gz remote:photos
+rclone archive extract --include "*.txt" s3:bucket/docs.zip /home/user/docs
+` + "```" + `
+
+Files already in the destination with the same name are overwritten.
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc, srcFileName := cmd.NewFsSrcFile(args[0])
+		if srcFileName == "" {
+			fs.Fatalf(nil, "%q is not a file", args[0])
+		}
+		fdst := cmd.NewFsDir(args[1:2])
+		cmd.Run(false, true, command, func() error {
+			ctx := context.Background()
+			src, err := fsrc.NewObject(ctx, srcFileName)
+			if err != nil {
+				return err
+			}
+			return Extract(ctx, src, format, fdst)
+		})
+	},
+}
+
+// Extract the archive in src into fdst
+//
+// If format is "" it is worked out from the name of src. Only the
+// members included by the filters are extracted.
+func Extract(ctx context.Context, src fs.Object, format string, fdst fs.Fs) error {
+	format, err := DetectFormat(src.Remote(), format)
+	if err != nil {
+		return err
+	}
+	fi := filter.GetConfig(ctx)
+	canMkdir := fdst.Features().CanHaveEmptyDirectories
+	return walkArchive(ctx, src, format, func(m Member, open func() (io.ReadCloser, error)) error {
+		name := libarchive.CleanName(m.Name)
+		if name == "" {
+			return nil
+		}
+		if m.IsDir {
+			// Only make directories explicitly if not filtering
+			// as files will make their parent directories
+			if canMkdir && fi.InActive() {
+				return operations.Mkdir(ctx, fdst, name)
+			}
+			return nil
+		}
+		if !fi.Include(name, m.Size, m.ModTime, nil) {
+			fs.Debugf(name, "Excluded from extract")
+			return nil
+		}
+		in, err := open()
+		if err != nil {
+			return fmt.Errorf("failed to open %q in archive: %w", m.Name, err)
+		}
+		_, err = operations.Rcat(ctx, fdst, name, in, m.ModTime, nil)
+		if err != nil {
+			return fmt.Errorf("failed to extract %q: %w", m.Name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/filter"
+	libarchive "github.com/rclone/rclone/lib/archive"
+	"github.com/spf13/cobra"
+)
+
+var (
+	long bool
+)
+
+func init() {
+	cmdFlags := listCommand.Flags()
+	flags.StringVarP(cmdFlags, &format, "format", "", "", "Archive format (zip|tar|tar.gz) - default is from the file name", "")
+	flags.BoolVarP(cmdFlags, &long, "long", "l", false, "Show the size and modification time of each member", "")
+}
+
+var listCommand = &cobra.Command{
+	Use:   "list [flags] source:path/archive.zip",
+	Short: `List the contents of an archive on a remote.`,
+	Long: `List the files and directories in the archive at source:path/archive.zip.
+
+Directories are shown with a trailing ` + "`/`" + `. Use ` + "`--long`" + `
+to show the size and modification time of each member too.
+
+Zip archives are listed by reading just the central directory at the
+end of the archive with range requests. Tar archives have to be read
+in full.
+
+` + "```sh" + `
+rclone archive list remote:backups/photos.zip
+rclone archive list --long remote:backups/photos.tar.gz
+` + "```" + `
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		fsrc, srcFileName := cmd.NewFsSrcFile(args[0])
+		if srcFileName == "" {
+			fs.Fatalf(nil, "%q is not a file", args[0])
+		}
+		cmd.Run(false, false, command, func() error {
+			ctx := context.Background()
+			src, err := fsrc.NewObject(ctx, srcFileName)
+			if err != nil {
+				return err
+			}
+			return List(ctx, src, format, long, os.Stdout)
+		})
+	},
+}
+
+// List writes the members of the archive in src to out
+//
+// If format is "" it is worked out from the name of src. If long is
+// set the size and modification time are shown too.
+func List(ctx context.Context, src fs.Object, format string, long bool, out io.Writer) error {
+	format, err := DetectFormat(src.Remote(), format)
+	if err != nil {
+		return err
+	}
+	fi := filter.GetConfig(ctx)
+	return walkArchive(ctx, src, format, func(m Member, _ func() (io.ReadCloser, error)) error {
+		name := m.Name
+		if m.IsDir {
+			if name != "" && name[len(name)-1] != '/' {
+				name += "/"
+			}
+		} else if !fi.Include(libarchive.CleanName(name), m.Size, m.ModTime, nil) {
+			return nil
+		}
+		if long {
+			_, err = fmt.Fprintf(out, "%12d %s %s\n", m.Size, m.ModTime.Local().Format("2006-01-02 15:04:05"), name)
+		} else {
+			_, err = fmt.Fprintln(out, name)
+		}
+		return err
+	})
+}
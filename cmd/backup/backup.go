@@ -0,0 +1,301 @@
+// Package backup provides the backup command.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/lib/cdc"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	commandDefinition.AddCommand(listCommand)
+	commandDefinition.AddCommand(restoreCommand)
+	commandDefinition.AddCommand(pruneCommand)
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "backup source:path crypt:repository",
+	Short: `Make an encrypted, deduplicated snapshot of source in a backup repository.`,
+	Long: `Make a snapshot of the files in source:path in the backup repository
+at crypt:repository, which must be on a [crypt](/crypt/) remote so
+everything stored is encrypted.
+
+Each run records a snapshot manifest listing the files in the source
+along with their sizes, modification times and SHA-256 hashes.
+
+The contents of the files are split into variable sized chunks of
+about 1 MiB using content defined chunking, so the chunk boundaries
+depend on the data rather than on the position in the file. Each chunk
+is stored once in the repository named by its SHA-256 hash, so only
+chunks which aren't in the repository already are uploaded. This means
+that when part of a large file changes, or data is inserted or removed
+in the middle of it, only the chunks around the change are uploaded
+again, and identical data in different files is only stored once.
+
+Unchanged files in a source that has been backed up before aren't read
+again as the chunks from the previous snapshot of the same source are
+used if the size and modification time match.
+
+The repository is created on the first run. It contains
+
+- ` + "`config.json`" + ` - the version of the repository format
+- ` + "`snapshots/`" + ` - a manifest for each snapshot
+- ` + "`data/`" + ` - the chunks of the files named by their SHA-256 hash
+- ` + "`locks/`" + ` - a lock for each backup or prune running
+
+Use the subcommands to list, restore and prune the snapshots.
+
+` + "```sh" + `
+rclone backup /home/user/documents secret:backups
+rclone backup list secret:backups
+rclone backup restore secret:backups latest /tmp/restore
+rclone backup prune --keep-daily 7 secret:backups
+` + "```" + `
+
+Filters can be used to choose which files are backed up, and
+` + "`--dry-run`" + ` shows which chunks would be uploaded without changing
+the repository.
+
+While a backup is running the repository is locked so that
+` + "`rclone backup prune`" + ` can't remove data which the backup is
+about to refer to. Any number of backups can run at the same time. If
+a backup or prune is interrupted its lock is left in the ` + "`locks/`" + `
+directory of the repository and needs removing by hand.
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+		"groups":            "Copy,Filter,Listing,Important",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc, fdst := cmd.NewFsSrcDst(args)
+		cmd.Run(true, true, command, func() error {
+			ctx := context.Background()
+			repo, err := Init(ctx, fdst)
+			if err != nil {
+				return err
+			}
+			_, err = Backup(ctx, repo, fsrc)
+			return err
+		})
+	},
+}
+
+// uploader uploads the chunks of the files in a backup, making sure
+// each chunk is only uploaded once
+type uploader struct {
+	repo     *Repo
+	mu       sync.Mutex
+	stored   map[string]bool // chunks in the repository
+	claimed  map[string]bool // chunks being uploaded
+	uploaded int             // number of chunks uploaded
+	newBytes int64           // size of the chunks uploaded
+}
+
+// storedAll returns true if all of chunks are in the repository
+func (u *uploader) storedAll(chunks []string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, sum := range chunks {
+		if !u.stored[sum] {
+			return false
+		}
+	}
+	return true
+}
+
+// claim returns true if the chunk named sum isn't in the repository
+// or being uploaded already, in which case the caller must upload it
+// and call done with the result.
+func (u *uploader) claim(sum string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.stored[sum] || u.claimed[sum] {
+		return false
+	}
+	u.claimed[sum] = true
+	return true
+}
+
+// done marks the chunk named sum of size bytes claimed by the caller
+// as stored if err is nil or unclaims it so it can be uploaded again
+// otherwise.
+func (u *uploader) done(sum string, size int, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.claimed, sum)
+	if err != nil {
+		return
+	}
+	u.stored[sum] = true
+	u.uploaded++
+	u.newBytes += int64(size)
+}
+
+// put reads o, splitting it into chunks and uploading the ones which
+// aren't in the repository, and fills in the size, hash and chunks of
+// file.
+func (u *uploader) put(ctx context.Context, o fs.Object, file *File) (err error) {
+	in, err := operations.Open(ctx, o)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	hasher, err := hash.NewMultiHasherTypes(hash.NewHashSet(hashType))
+	if err != nil {
+		return err
+	}
+	chunker, err := cdc.New(io.TeeReader(in, hasher), chunkSize)
+	if err != nil {
+		return err
+	}
+	file.Chunks = []string{}
+	for {
+		data, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		name := hex.EncodeToString(sum[:])
+		file.Chunks = append(file.Chunks, name)
+		if !u.claim(name) {
+			continue
+		}
+		_, err = operations.RcatSize(ctx, u.repo.f, dataPath(name), io.NopCloser(bytes.NewReader(data)), int64(len(data)), time.Now(), nil)
+		u.done(name, len(data), err)
+		if err != nil {
+			return fmt.Errorf("failed to upload chunk: %w", err)
+		}
+	}
+	// Record what was read in case the file changed
+	file.Size = hasher.Size()
+	file.Hash, err = hasher.SumString(hashType, false)
+	return err
+}
+
+// Backup makes a snapshot of fsrc in repo
+func Backup(ctx context.Context, repo *Repo, fsrc fs.Fs) (*Snapshot, error) {
+	ci := fs.GetConfig(ctx)
+	now := time.Now()
+	hostname, _ := os.Hostname()
+	snapshot := &Snapshot{
+		ID:       newSnapshotID(now, hostname),
+		Time:     now,
+		Source:   fs.ConfigString(fsrc),
+		Hostname: hostname,
+		Files:    []File{},
+		Dirs:     []string{},
+	}
+
+	// Stop prune removing the data this refers to
+	lock, err := repo.Lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(ctx, lock)
+
+	// Find the previous snapshot of this source to reuse its chunks
+	snapshots, err := repo.Snapshots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	parentFiles := map[string]File{}
+	for _, previous := range snapshots {
+		if previous.Source == snapshot.Source {
+			snapshot.Parent = previous.ID
+			clear(parentFiles)
+			for _, file := range previous.Files {
+				parentFiles[file.Path] = file
+			}
+		}
+	}
+
+	// Find the chunks already in the repository
+	stored, err := repo.data(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup data: %w", err)
+	}
+	u := &uploader{
+		repo:    repo,
+		stored:  make(map[string]bool, len(stored)),
+		claimed: map[string]bool{},
+	}
+	for sum := range stored {
+		u.stored[sum] = true
+	}
+
+	// Read the source
+	var objects []fs.Object
+	err = walk.ListR(ctx, fsrc, "", false, ci.MaxDepth, walk.ListAll, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			switch x := entry.(type) {
+			case fs.Object:
+				objects = append(objects, x)
+			case fs.Directory:
+				snapshot.Dirs = append(snapshot.Dirs, x.Remote())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source: %w", err)
+	}
+
+	// Chunk the files and upload any new chunks
+	files := make([]File, len(objects))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(ci.Transfers)
+	for i, o := range objects {
+		g.Go(func() error {
+			file := &files[i]
+			file.Path = o.Remote()
+			file.Size = o.Size()
+			file.ModTime = o.ModTime(gCtx)
+			if parent, ok := parentFiles[file.Path]; ok && parent.Size == file.Size && parent.ModTime.Equal(file.ModTime) && u.storedAll(parent.Chunks) {
+				fs.Debugf(o, "Unchanged since the previous snapshot")
+				file.Hash = parent.Hash
+				file.Chunks = parent.Chunks
+				return nil
+			}
+			err := u.put(gCtx, o, file)
+			if err != nil {
+				return fmt.Errorf("failed to back up %q: %w", file.Path, err)
+			}
+			return nil
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Files = files
+
+	// Save the manifest now all the data is stored
+	err = repo.saveSnapshot(ctx, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	fs.Logf(nil, "Snapshot %s: %d files (%v), %d new chunks (%v) uploaded", snapshot.ID,
+		len(snapshot.Files), fs.SizeSuffix(snapshot.Size()).ByteUnit(),
+		u.uploaded, fs.SizeSuffix(u.newBytes).ByteUnit())
+	return snapshot, nil
+}
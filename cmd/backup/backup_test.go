@@ -0,0 +1,338 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/chunker"
+	_ "github.com/rclone/rclone/backend/crypt"
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/obscure"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = fstest.Time("2017-02-03T04:05:06Z")
+	t2 = fstest.Time("2020-06-07T08:09:10Z")
+)
+
+// TestMain drives the tests
+func TestMain(m *testing.M) {
+	fstest.TestMain(m)
+}
+
+// newRepo makes a repository on a crypt remote in a temporary directory
+func newTestRepo(t *testing.T) (*Repo, string) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	f, err := fs.NewFs(ctx, ":crypt,remote="+dir+",password="+obscure.MustObscure("potato")+":")
+	require.NoError(t, err)
+	repo, err := Init(ctx, f)
+	require.NoError(t, err)
+	return repo, dir
+}
+
+// dataCount returns the number of data objects in the repository
+func dataCount(t *testing.T, repo *Repo) int {
+	data, err := repo.data(context.Background())
+	require.NoError(t, err)
+	return len(data)
+}
+
+func TestRepoNeedsCrypt(t *testing.T) {
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+	_, err = Init(ctx, f)
+	assert.ErrorContains(t, err, "must be on a crypt remote")
+}
+
+func TestRepoWrappedCrypt(t *testing.T) {
+	ctx := context.Background()
+	crypt := ":crypt,remote=" + t.TempDir() + ",password=" + obscure.MustObscure("potato") + ":"
+	f, err := fs.NewFs(ctx, `:chunker,remote="`+crypt+`":`)
+	require.NoError(t, err)
+	_, err = Init(ctx, f)
+	assert.NoError(t, err)
+}
+
+func TestSaveSnapshotExists(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t)
+	now := time.Now()
+	snapshot := &Snapshot{ID: newSnapshotID(now, "host"), Time: now}
+	assert.NotEqual(t, snapshot.ID, newSnapshotID(now, "host"))
+	require.NoError(t, repo.saveSnapshot(ctx, snapshot))
+	assert.ErrorContains(t, repo.saveSnapshot(ctx, snapshot), "already exists")
+}
+
+func TestOpenNotRepo(t *testing.T) {
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, ":crypt,remote="+t.TempDir()+",password="+obscure.MustObscure("potato")+":")
+	require.NoError(t, err)
+	_, err = Open(ctx, f)
+	assert.ErrorContains(t, err, "is not a backup repository")
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	repo, repoDir := newTestRepo(t)
+
+	// The first backup uploads the data once for identical files
+	file1 := r.WriteFile("file1", "hello world", t1)
+	file2 := r.WriteFile("dir/file2", "potato", t1)
+	file3 := r.WriteFile("dir/file3", "hello world", t2)
+	require.NoError(t, os.Mkdir(filepath.Join(r.LocalName, "empty"), 0777))
+	snapshot1, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	assert.Len(t, snapshot1.Files, 3)
+	assert.ElementsMatch(t, []string{"dir", "empty"}, snapshot1.Dirs)
+	assert.Equal(t, "", snapshot1.Parent)
+	assert.Equal(t, 2, dataCount(t, repo))
+
+	// The contents are encrypted in the repository
+	err = filepath.Walk(repoDir, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		assert.NotContains(t, path, "snapshots")
+		if !info.IsDir() {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.False(t, bytes.Contains(data, []byte("hello world")), path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// A second backup only uploads the new data
+	time.Sleep(time.Second) // make sure the snapshot ID changes
+	file2 = r.WriteFile("dir/file2", "potato2", t2)
+	file4 := r.WriteFile("file4", "potato", t2)
+	snapshot2, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	assert.Len(t, snapshot2.Files, 4)
+	assert.Equal(t, snapshot1.ID, snapshot2.Parent)
+	assert.Equal(t, 3, dataCount(t, repo))
+
+	// The snapshots can be listed
+	var out bytes.Buffer
+	require.NoError(t, List(ctx, repo, &out))
+	assert.Contains(t, out.String(), snapshot1.ID)
+	assert.Contains(t, out.String(), snapshot2.ID)
+	latest, err := repo.Snapshot(ctx, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot2.ID, latest.ID)
+	_, err = repo.Snapshot(ctx, "potato")
+	assert.ErrorContains(t, err, "not found")
+
+	// The first snapshot restores the old contents
+	oldFile2 := fstest.NewItem("dir/file2", "potato", t1)
+	old, err := repo.Snapshot(ctx, snapshot1.ID)
+	require.NoError(t, err)
+	require.NoError(t, Restore(ctx, repo, old, r.Fremote))
+	r.CheckRemoteListing(t, []fstest.Item{file1, oldFile2, file3}, []string{"dir", "empty"})
+
+	// The second snapshot only restores what changed
+	require.NoError(t, Restore(ctx, repo, snapshot2, r.Fremote))
+	r.CheckRemoteItems(t, file1, file2, file3, file4)
+
+	// Restores can be filtered
+	fi, err := filter.NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, fi.AddRule("+ file1"))
+	require.NoError(t, fi.AddRule("- **"))
+	filterCtx := filter.ReplaceConfig(ctx, fi)
+	require.NoError(t, r.Fremote.Mkdir(ctx, "sub"))
+	fsub, err := fs.NewFs(ctx, filepath.Join(r.FremoteName, "sub"))
+	require.NoError(t, err)
+	require.NoError(t, Restore(filterCtx, repo, snapshot2, fsub))
+	fstest.CheckListingWithPrecision(t, fsub, []fstest.Item{file1}, []string{}, fs.GetModifyWindow(ctx, fsub))
+}
+
+func TestPruneKeep(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2025, 3, d, h, 0, 0, 0, time.Local)
+	}
+	var snapshots []*Snapshot
+	for _, x := range []struct {
+		id     string
+		t      time.Time
+		source string
+	}{
+		{"a", day(1, 1), "src"},
+		{"b", day(1, 2), "src"},
+		{"c", day(2, 1), "src"},
+		{"d", day(3, 1), "src"},
+		{"e", day(3, 2), "src"},
+		{"f", day(1, 1), "other"},
+	} {
+		snapshots = append(snapshots, &Snapshot{ID: x.id, Time: x.t, Source: x.source})
+	}
+	for _, test := range []struct {
+		opt  PruneOptions
+		want []string
+	}{
+		{PruneOptions{KeepLast: 1}, []string{"e", "f"}},
+		{PruneOptions{KeepLast: 2}, []string{"d", "e", "f"}},
+		{PruneOptions{KeepDaily: 1}, []string{"e", "f"}},
+		{PruneOptions{KeepDaily: 2}, []string{"c", "e", "f"}},
+		{PruneOptions{KeepDaily: 10}, []string{"b", "c", "e", "f"}},
+		{PruneOptions{KeepDaily: 1, KeepLast: 2}, []string{"d", "e", "f"}},
+	} {
+		var got []string
+		keep := test.opt.keep(snapshots)
+		for _, snapshot := range snapshots {
+			if keep[snapshot.ID] {
+				got = append(got, snapshot.ID)
+			}
+		}
+		assert.Equal(t, test.want, got, test.opt)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	repo, _ := newTestRepo(t)
+
+	r.WriteFile("file1", "hello world", t1)
+	r.WriteFile("file2", "potato", t1)
+	snapshot1, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	time.Sleep(time.Second) // make sure the snapshot ID changes
+	r.WriteFile("file2", "potato2", t2)
+	snapshot2, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	assert.Equal(t, 3, dataCount(t, repo))
+
+	assert.Error(t, Prune(ctx, repo, PruneOptions{}))
+
+	// Dry run doesn't remove anything
+	dryCtx, ci := fs.AddConfig(ctx)
+	ci.DryRun = true
+	require.NoError(t, Prune(dryCtx, repo, PruneOptions{KeepLast: 1}))
+	snapshots, err := repo.Snapshots(ctx)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, 3, dataCount(t, repo))
+
+	// The old snapshot and its unique data are removed
+	require.NoError(t, Prune(ctx, repo, PruneOptions{KeepLast: 1}))
+	snapshots, err = repo.Snapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, snapshot2.ID, snapshots[0].ID)
+	assert.Equal(t, 2, dataCount(t, repo))
+	_, err = repo.Snapshot(ctx, snapshot1.ID)
+	assert.Error(t, err)
+}
+
+func TestBackupChunks(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	repo, _ := newTestRepo(t)
+
+	data := make([]byte, 8*chunkSize)
+	rand.New(rand.NewSource(1)).Read(data)
+	r.WriteFile("big", string(data), t1)
+	snapshot1, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	require.Len(t, snapshot1.Files, 1)
+	chunks := snapshot1.Files[0].Chunks
+	assert.Greater(t, len(chunks), 2)
+	assert.Equal(t, len(chunks), dataCount(t, repo))
+
+	// Inserting data in the middle only uploads the chunks around it
+	// and a copy of the file doesn't upload anything
+	time.Sleep(time.Second) // make sure the snapshot ID changes
+	edited := append(append(append([]byte{}, data[:len(data)/2]...), "inserted"...), data[len(data)/2:]...)
+	big := r.WriteFile("big", string(edited), t2)
+	copied := r.WriteFile("copy", string(data), t1)
+	snapshot2, err := Backup(ctx, repo, r.Flocal)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, dataCount(t, repo), len(chunks)+2)
+
+	// The files are put back together from the chunks
+	require.NoError(t, Restore(ctx, repo, snapshot2, r.Fremote))
+	r.CheckRemoteItems(t, big, copied)
+
+	// The chunks can be read from any offset
+	var file *File
+	for i := range snapshot2.Files {
+		if snapshot2.Files[i].Path == "big" {
+			file = &snapshot2.Files[i]
+		}
+	}
+	require.NotNil(t, file)
+	o := &restoreObject{repo: repo, file: file}
+	for _, test := range []struct {
+		option fs.OpenOption
+		want   []byte
+	}{
+		{&fs.SeekOption{Offset: 3 * chunkSize}, edited[3*chunkSize:]},
+		{&fs.RangeOption{Start: chunkSize - 10, End: 5*chunkSize + 9}, edited[chunkSize-10 : 5*chunkSize+10]},
+		{&fs.RangeOption{Start: -1, End: 100}, edited[len(edited)-100:]},
+	} {
+		in, err := o.Open(ctx, test.option)
+		require.NoError(t, err)
+		got, err := io.ReadAll(in)
+		require.NoError(t, err)
+		require.NoError(t, in.Close())
+		assert.True(t, bytes.Equal(test.want, got), test.option)
+	}
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t)
+
+	// Backups can run together but not with a prune
+	lock1, err := repo.Lock(ctx, false)
+	require.NoError(t, err)
+	lock2, err := repo.Lock(ctx, false)
+	require.NoError(t, err)
+	err = Prune(ctx, repo, PruneOptions{KeepLast: 1})
+	assert.ErrorContains(t, err, "backup repository is locked by backup on")
+	require.NoError(t, lock1.Unlock(ctx))
+	require.NoError(t, lock2.Unlock(ctx))
+	require.NoError(t, Prune(ctx, repo, PruneOptions{KeepLast: 1}))
+
+	// Nothing can run with a prune
+	lock3, err := repo.Lock(ctx, true)
+	require.NoError(t, err)
+	_, err = repo.Lock(ctx, false)
+	assert.ErrorContains(t, err, "backup repository is locked by prune on")
+	require.NoError(t, lock3.Unlock(ctx))
+	locks, err := repo.locks(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, locks, 0)
+}
+
+func TestUploaderClaim(t *testing.T) {
+	u := &uploader{stored: map[string]bool{"a": true}, claimed: map[string]bool{}}
+	assert.False(t, u.claim("a"))
+	assert.True(t, u.claim("b"))
+	assert.False(t, u.claim("b"))
+	assert.False(t, u.storedAll([]string{"a", "b"}))
+
+	// A failed upload can be tried again
+	u.done("b", 10, errors.New("upload failed"))
+	assert.False(t, u.storedAll([]string{"b"}))
+	assert.True(t, u.claim("b"))
+	u.done("b", 10, nil)
+	assert.True(t, u.storedAll([]string{"a", "b"}))
+	assert.False(t, u.claim("b"))
+	assert.Equal(t, 1, u.uploaded)
+	assert.Equal(t, int64(10), u.newBytes)
+}
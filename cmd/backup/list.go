@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/spf13/cobra"
+)
+
+var listCommand = &cobra.Command{
+	Use:   "list crypt:repository",
+	Short: `List the snapshots in a backup repository.`,
+	Long: `List the snapshots in the backup repository at crypt:repository
+oldest first, showing the ID, the time, the number of files, the total
+size and the source of each one.
+
+` + "```sh" + `
+$ rclone backup list secret:backups
+ID                               Time                   Files       Size Source
+20250314T023000Z-laptop-dohuyaf3 2025-03-14 02:30:00      412  1.203 GiB /home/user/documents
+20250315T023000Z-laptop-kabepiz7 2025-03-15 02:30:00      415  1.207 GiB /home/user/documents
+` + "```" + `
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsDir(args)
+		cmd.Run(false, false, command, func() error {
+			ctx := context.Background()
+			repo, err := Open(ctx, f)
+			if err != nil {
+				return err
+			}
+			return List(ctx, repo, os.Stdout)
+		})
+	},
+}
+
+// List writes the snapshots in repo to out
+func List(ctx context.Context, repo *Repo, out io.Writer) error {
+	snapshots, err := repo.Snapshots(ctx)
+	if err != nil {
+		return err
+	}
+	idWidth := len("ID")
+	for _, snapshot := range snapshots {
+		idWidth = max(idWidth, len(snapshot.ID))
+	}
+	_, err = fmt.Fprintf(out, "%-*s %-19s %8s %10s %s\n", idWidth, "ID", "Time", "Files", "Size", "Source")
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		_, err = fmt.Fprintf(out, "%-*s %-19s %8d %10s %s\n",
+			idWidth, snapshot.ID,
+			snapshot.Time.Local().Format("2006-01-02 15:04:05"),
+			len(snapshot.Files),
+			fs.SizeSuffix(snapshot.Size()).ByteUnit(),
+			snapshot.Source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// Lock stops prune from running at the same time as a backup
+//
+// Each lock is a file in the locks directory. Any number of backups
+// can run at once as they only add to the repository, but prune
+// removes data so it needs the repository to itself.
+type Lock struct {
+	ID        string    `json:"id"`        // unique ID made from the time, host and process
+	Time      time.Time `json:"time"`      // when the lock was taken
+	Hostname  string    `json:"hostname"`  // host the lock was taken on
+	PID       int       `json:"pid"`       // process which took the lock
+	Exclusive bool      `json:"exclusive"` // set if no other locks are allowed
+	repo      *Repo
+}
+
+// lockPath returns the path of the lock with the given ID
+func lockPath(id string) string {
+	return path.Join(locksDir, id+".json")
+}
+
+// String returns a description of the lock for errors
+func (l *Lock) String() string {
+	kind := "backup"
+	if l.Exclusive {
+		kind = "prune"
+	}
+	return fmt.Sprintf("%s on %s (pid %d) since %v", kind, l.Hostname, l.PID, l.Time.Local().Format(time.DateTime))
+}
+
+// locks reads the locks in the repository other than the one with
+// the ID skip
+func (r *Repo) locks(ctx context.Context, skip string) (locks []*Lock, err error) {
+	entries, err := r.f.List(ctx, locksDir)
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		o, ok := entry.(fs.Object)
+		if !ok || !strings.HasSuffix(o.Remote(), ".json") || o.Remote() == lockPath(skip) {
+			continue
+		}
+		lock := new(Lock)
+		err = r.readJSON(ctx, o.Remote(), lock)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	return locks, nil
+}
+
+// checkLocks returns an error if any of the locks other than skip
+// conflict with a lock which is exclusive if set
+func (r *Repo) checkLocks(ctx context.Context, skip string, exclusive bool) error {
+	locks, err := r.locks(ctx, skip)
+	if err != nil {
+		return fmt.Errorf("failed to read locks: %w", err)
+	}
+	for _, lock := range locks {
+		if exclusive || lock.Exclusive {
+			return fmt.Errorf("backup repository is locked by %v - if that isn't running any more remove %q from the repository", lock, lockPath(lock.ID))
+		}
+	}
+	return nil
+}
+
+// Lock locks the repository, exclusively for prune, returning an
+// error if that conflicts with a lock somebody else holds
+//
+// Call Unlock on the result when finished.
+func (r *Repo) Lock(ctx context.Context, exclusive bool) (*Lock, error) {
+	err := r.checkLocks(ctx, "", exclusive)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	hostname, _ := os.Hostname()
+	l := &Lock{
+		ID:        fmt.Sprintf("%s-%s-%d", now.UTC().Format(idFormat), hostname, os.Getpid()),
+		Time:      now,
+		Hostname:  hostname,
+		PID:       os.Getpid(),
+		Exclusive: exclusive,
+		repo:      r,
+	}
+	err = r.writeJSON(ctx, lockPath(l.ID), l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write lock: %w", err)
+	}
+	// Check again in case a conflicting lock was taken at the
+	// same time
+	err = r.checkLocks(ctx, l.ID, exclusive)
+	if err != nil {
+		_ = l.Unlock(ctx)
+		return nil, err
+	}
+	return l, nil
+}
+
+// Unlock removes the lock
+func (l *Lock) Unlock(ctx context.Context) error {
+	o, err := l.repo.f.NewObject(ctx, lockPath(l.ID))
+	if errors.Is(err, fs.ErrorObjectNotFound) {
+		// Not written with --dry-run
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return operations.DeleteFile(ctx, o)
+}
+
+// unlock removes lock logging any error, for use with defer
+func unlock(ctx context.Context, lock *Lock) {
+	if err := lock.Unlock(ctx); err != nil {
+		fs.Errorf(nil, "Failed to remove backup repository lock: %v", err)
+	}
+}
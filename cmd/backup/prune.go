@@ -0,0 +1,149 @@
+package backup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/spf13/cobra"
+)
+
+// PruneOptions says which snapshots to keep when pruning
+type PruneOptions struct {
+	KeepLast  int // keep this many of the most recent snapshots
+	KeepDaily int // keep the most recent snapshot for this many days
+}
+
+var pruneOpt PruneOptions
+
+func init() {
+	cmdFlags := pruneCommand.Flags()
+	flags.IntVarP(cmdFlags, &pruneOpt.KeepLast, "keep-last", "", 0, "Keep the most recent N snapshots", "")
+	flags.IntVarP(cmdFlags, &pruneOpt.KeepDaily, "keep-daily", "", 0, "Keep the most recent snapshot of each of the last N days with snapshots", "")
+}
+
+var pruneCommand = &cobra.Command{
+	Use:   "prune [flags] crypt:repository",
+	Short: `Remove old snapshots and unused data from a backup repository.`,
+	Long: `Remove the snapshots which don't match the keep flags from the backup
+repository at crypt:repository, then remove the chunks of data which
+aren't used by any of the remaining snapshots.
+
+The keep flags are applied separately to the snapshots of each source
+and a snapshot is kept if any of them match.
+
+- ` + "`--keep-last N`" + ` keeps the N most recent snapshots.
+- ` + "`--keep-daily N`" + ` keeps the most recent snapshot of each of
+  the last N days which have snapshots.
+
+At least one of the keep flags must be given. Use ` + "`--dry-run`" + `
+to see what would be removed.
+
+` + "```sh" + `
+rclone backup prune --keep-daily 7 --keep-last 3 secret:backups
+` + "```" + `
+
+This locks the repository so it fails if a backup or another prune is
+running as it could remove data the backup has just uploaded.
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsDir(args)
+		cmd.Run(true, false, command, func() error {
+			ctx := context.Background()
+			repo, err := Open(ctx, f)
+			if err != nil {
+				return err
+			}
+			return Prune(ctx, repo, pruneOpt)
+		})
+	},
+}
+
+// keep returns the IDs of the snapshots to keep
+func (opt *PruneOptions) keep(snapshots []*Snapshot) map[string]bool {
+	keep := map[string]bool{}
+	// snapshots of each source newest first
+	bySource := map[string][]*Snapshot{}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snapshot := snapshots[i]
+		bySource[snapshot.Source] = append(bySource[snapshot.Source], snapshot)
+	}
+	for _, group := range bySource {
+		days := map[string]bool{}
+		for i, snapshot := range group {
+			if i < opt.KeepLast {
+				keep[snapshot.ID] = true
+			}
+			day := snapshot.Time.Local().Format("2006-01-02")
+			if !days[day] && len(days) < opt.KeepDaily {
+				days[day] = true
+				keep[snapshot.ID] = true
+			}
+		}
+	}
+	return keep
+}
+
+// Prune removes the snapshots not selected by opt from repo and then
+// removes the chunks no longer in use
+func Prune(ctx context.Context, repo *Repo, opt PruneOptions) error {
+	if opt.KeepLast <= 0 && opt.KeepDaily <= 0 {
+		return errors.New("need at least one of --keep-last or --keep-daily")
+	}
+	lock, err := repo.Lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock(ctx, lock)
+	snapshots, err := repo.Snapshots(ctx)
+	if err != nil {
+		return err
+	}
+	keep := opt.keep(snapshots)
+	used := map[string]bool{}
+	removedSnapshots := 0
+	for _, snapshot := range snapshots {
+		if keep[snapshot.ID] {
+			for _, file := range snapshot.Files {
+				for _, sum := range file.Chunks {
+					used[sum] = true
+				}
+			}
+			continue
+		}
+		fs.Infof(nil, "Removing snapshot %s", snapshot.ID)
+		err = repo.deleteSnapshot(ctx, snapshot)
+		if err != nil {
+			return err
+		}
+		removedSnapshots++
+	}
+
+	data, err := repo.data(ctx)
+	if err != nil {
+		return err
+	}
+	removedData := 0
+	var removedBytes int64
+	for sum, o := range data {
+		if used[sum] {
+			continue
+		}
+		err = operations.DeleteFile(ctx, o)
+		if err != nil {
+			return err
+		}
+		removedData++
+		removedBytes += o.Size()
+	}
+	fs.Logf(nil, "Removed %d snapshots and %d unused chunks (%v), kept %d snapshots",
+		removedSnapshots, removedData, fs.SizeSuffix(removedBytes).ByteUnit(), len(keep))
+	return nil
+}
@@ -0,0 +1,300 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/backend/crypt"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/lib/random"
+)
+
+// Layout of the repository
+const (
+	configName   = "config.json" // repository config
+	snapshotsDir = "snapshots"   // snapshot manifests
+	dataDir      = "data"        // content addressed chunks of file data
+	locksDir     = "locks"       // locks held by running backups and prunes
+	repoVersion  = 1             // current version of the repository format
+	idFormat     = "20060102T150405Z"
+	chunkSize    = 1024 * 1024 // average size of the chunks the files are split into
+)
+
+// hashType is the hash used to address the chunks
+var hashType = hash.SHA256
+
+// repoConfig is the contents of the config file in the repository
+type repoConfig struct {
+	Version   int    `json:"version"`
+	Hash      string `json:"hash"`
+	ChunkSize int    `json:"chunkSize"`
+}
+
+// Snapshot is the manifest of a backup run
+type Snapshot struct {
+	ID       string    `json:"id"`               // unique ID made from the time
+	Time     time.Time `json:"time"`             // when the backup started
+	Source   string    `json:"source"`           // the source backed up
+	Hostname string    `json:"hostname"`         // host the backup ran on
+	Parent   string    `json:"parent,omitempty"` // ID of the snapshot used to skip hashing
+	Files    []File    `json:"files"`            // the files in the snapshot
+	Dirs     []string  `json:"dirs"`             // the directories in the snapshot
+}
+
+// File is a file in a Snapshot
+type File struct {
+	Path    string    `json:"path"`    // path relative to the source
+	Size    int64     `json:"size"`    // size in bytes
+	ModTime time.Time `json:"modTime"` // modification time
+	Hash    string    `json:"sha256"`  // SHA-256 of the contents
+	Chunks  []string  `json:"chunks"`  // SHA-256 of each chunk of the contents in order
+}
+
+// Size returns the total size of the files in the snapshot
+func (s *Snapshot) Size() (size int64) {
+	for _, file := range s.Files {
+		size += file.Size
+	}
+	return size
+}
+
+// Repo is a backup repository on a crypt remote
+type Repo struct {
+	f fs.Fs
+}
+
+// onCrypt returns true if f is a crypt remote or wraps one
+func onCrypt(f fs.Fs) bool {
+	for f != nil {
+		if _, ok := f.(*crypt.Fs); ok {
+			return true
+		}
+		unwrap := f.Features().UnWrap
+		if unwrap == nil {
+			return false
+		}
+		f = unwrap()
+	}
+	return false
+}
+
+// newRepo checks f is suitable for a repository
+func newRepo(f fs.Fs) (*Repo, error) {
+	if !onCrypt(f) {
+		return nil, fmt.Errorf("backup repository %v must be on a crypt remote", fs.ConfigString(f))
+	}
+	return &Repo{f: f}, nil
+}
+
+// Init opens the repository in f, creating it if it doesn't exist
+func Init(ctx context.Context, f fs.Fs) (*Repo, error) {
+	r, err := newRepo(f)
+	if err != nil {
+		return nil, err
+	}
+	err = r.checkConfig(ctx)
+	if errors.Is(err, fs.ErrorObjectNotFound) || errors.Is(err, fs.ErrorDirNotFound) {
+		fs.Infof(f, "Creating backup repository")
+		return r, r.writeJSON(ctx, configName, repoConfig{
+			Version:   repoVersion,
+			Hash:      hashType.String(),
+			ChunkSize: chunkSize,
+		})
+	}
+	return r, err
+}
+
+// Open opens an existing repository in f
+func Open(ctx context.Context, f fs.Fs) (*Repo, error) {
+	r, err := newRepo(f)
+	if err != nil {
+		return nil, err
+	}
+	err = r.checkConfig(ctx)
+	if errors.Is(err, fs.ErrorObjectNotFound) || errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, fmt.Errorf("%v is not a backup repository", fs.ConfigString(f))
+	}
+	return r, err
+}
+
+// checkConfig reads the config and checks it is compatible
+func (r *Repo) checkConfig(ctx context.Context) error {
+	var config repoConfig
+	err := r.readJSON(ctx, configName, &config)
+	if err != nil {
+		return err
+	}
+	if config.Version != repoVersion {
+		return fmt.Errorf("unsupported backup repository version %d", config.Version)
+	}
+	if config.Hash != hashType.String() {
+		return fmt.Errorf("unsupported backup repository hash %q", config.Hash)
+	}
+	if config.ChunkSize != chunkSize {
+		return fmt.Errorf("unsupported backup repository chunk size %d", config.ChunkSize)
+	}
+	return nil
+}
+
+// readJSON reads the object at remote as JSON into out
+func (r *Repo) readJSON(ctx context.Context, remote string, out any) (err error) {
+	o, err := r.f.NewObject(ctx, remote)
+	if err != nil {
+		return err
+	}
+	in, err := operations.Open(ctx, o)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, out)
+	if err != nil {
+		return fmt.Errorf("failed to decode %q: %w", remote, err)
+	}
+	return nil
+}
+
+// writeJSON writes in as JSON to remote
+func (r *Repo) writeJSON(ctx context.Context, remote string, in any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	_, err = operations.RcatSize(ctx, r.f, remote, io.NopCloser(bytes.NewReader(data)), int64(len(data)), time.Now(), nil)
+	return err
+}
+
+// dataPath returns the path of the chunk with the given hash
+func dataPath(sum string) string {
+	return path.Join(dataDir, sum[:2], sum)
+}
+
+// snapshotPath returns the path of the snapshot with the given ID
+func snapshotPath(id string) string {
+	return path.Join(snapshotsDir, id+".json")
+}
+
+// Snapshots reads all the snapshots sorted oldest first
+func (r *Repo) Snapshots(ctx context.Context) (snapshots []*Snapshot, err error) {
+	entries, err := r.f.List(ctx, snapshotsDir)
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		o, ok := entry.(fs.Object)
+		if !ok || !strings.HasSuffix(o.Remote(), ".json") {
+			continue
+		}
+		snapshot := new(Snapshot)
+		err = r.readJSON(ctx, o.Remote(), snapshot)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// Snapshot finds the snapshot with the given ID
+//
+// The ID can be "latest" for the most recent snapshot or a unique
+// prefix of an ID.
+func (r *Repo) Snapshot(ctx context.Context, id string) (*Snapshot, error) {
+	snapshots, err := r.Snapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, errors.New("no snapshots in backup repository")
+	}
+	if id == "latest" {
+		return snapshots[len(snapshots)-1], nil
+	}
+	var found *Snapshot
+	for _, snapshot := range snapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+		if strings.HasPrefix(snapshot.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("snapshot ID %q is ambiguous", id)
+			}
+			found = snapshot
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("snapshot %q not found", id)
+	}
+	return found, nil
+}
+
+// newSnapshotID makes an ID for a snapshot taken at now on hostname
+//
+// As backups only take a shared lock the ID has a random suffix so
+// backups started at the same time don't get the same ID.
+func newSnapshotID(now time.Time, hostname string) string {
+	return fmt.Sprintf("%s-%s-%s", now.UTC().Format(idFormat), hostname, random.String(8))
+}
+
+// saveSnapshot writes the manifest of snapshot, returning an error
+// if there is already a snapshot with its ID
+func (r *Repo) saveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	remote := snapshotPath(snapshot.ID)
+	_, err := r.f.NewObject(ctx, remote)
+	if err == nil {
+		return fmt.Errorf("snapshot %s already exists", snapshot.ID)
+	} else if !errors.Is(err, fs.ErrorObjectNotFound) && !errors.Is(err, fs.ErrorDirNotFound) {
+		return fmt.Errorf("failed to check for snapshot %s: %w", snapshot.ID, err)
+	}
+	return r.writeJSON(ctx, remote, snapshot)
+}
+
+// deleteSnapshot removes the manifest of snapshot
+func (r *Repo) deleteSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	o, err := r.f.NewObject(ctx, snapshotPath(snapshot.ID))
+	if err != nil {
+		return err
+	}
+	return operations.DeleteFile(ctx, o)
+}
+
+// data lists the chunks in the repository keyed by hash
+func (r *Repo) data(ctx context.Context) (map[string]fs.Object, error) {
+	objects := make(map[string]fs.Object)
+	// Check the data directory exists first so listing it doesn't log an error
+	_, err := r.f.List(ctx, dataDir)
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return objects, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = walk.ListR(ctx, r.f, dataDir, true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			if o, ok := entry.(fs.Object); ok {
+				objects[path.Base(o.Remote())] = o
+			}
+		}
+		return nil
+	})
+	return objects, err
+}
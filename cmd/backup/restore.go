@@ -0,0 +1,243 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/lib/readers"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var restoreCommand = &cobra.Command{
+	Use:   "restore crypt:repository snapshot dest:path",
+	Short: `Restore a snapshot from a backup repository.`,
+	Long: `Restore the files in the snapshot from the backup repository at
+crypt:repository to dest:path.
+
+The snapshot is given by its ID as shown by ` + "`rclone backup list`" + `,
+a unique prefix of the ID, or ` + "`latest`" + ` for the most recent
+snapshot.
+
+Files which already exist in the destination with the same size and
+modification time are skipped. Files in the destination which aren't
+in the snapshot are left alone. Use filters to restore some of the
+files only. Empty directories are restored too unless filters are in
+use.
+
+` + "```sh" + `
+rclone backup restore secret:backups latest /tmp/restore
+rclone backup restore --include "*.doc" secret:backups 20250314 /tmp/restore
+` + "```" + `
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(3, 3, command, args)
+		f := cmd.NewFsDir(args[0:1])
+		fdst := cmd.NewFsDir(args[2:3])
+		cmd.Run(true, true, command, func() error {
+			ctx := context.Background()
+			repo, err := Open(ctx, f)
+			if err != nil {
+				return err
+			}
+			snapshot, err := repo.Snapshot(ctx, args[1])
+			if err != nil {
+				return err
+			}
+			return Restore(ctx, repo, snapshot, fdst)
+		})
+	},
+}
+
+// restoreObject is a file in a snapshot which reads its contents from
+// the chunks in the repository
+type restoreObject struct {
+	repo *Repo
+	file *File
+}
+
+// Fs returns the repository the chunks are in
+func (o *restoreObject) Fs() fs.Info { return o.repo.f }
+
+// Remote returns the path of the file in the snapshot
+func (o *restoreObject) Remote() string { return o.file.Path }
+
+// String returns a description of the object
+func (o *restoreObject) String() string { return o.file.Path }
+
+// ModTime returns the modification time of the file in the snapshot
+func (o *restoreObject) ModTime(ctx context.Context) time.Time { return o.file.ModTime }
+
+// Size returns the size of the file in the snapshot
+func (o *restoreObject) Size() int64 { return o.file.Size }
+
+// Storable says whether this object can be stored
+func (o *restoreObject) Storable() bool { return true }
+
+// Hash returns the SHA-256 of the file in the snapshot
+func (o *restoreObject) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if ht != hashType {
+		return "", hash.ErrUnsupported
+	}
+	return o.file.Hash, nil
+}
+
+// SetModTime is not supported
+func (o *restoreObject) SetModTime(ctx context.Context, t time.Time) error {
+	return fs.ErrorCantSetModTime
+}
+
+// Open reads the chunks of the file in order
+func (o *restoreObject) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.file.Size)
+		default:
+			if option.Mandatory() {
+				fs.Logf(o, "Unsupported mandatory option: %v", option)
+			}
+		}
+	}
+	in := &chunkReader{ctx: ctx, repo: o.repo, chunks: o.file.Chunks, offset: offset}
+	return readers.NewLimitedReadCloser(in, limit), nil
+}
+
+// Update is not supported
+func (o *restoreObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errors.New("can't update a file in a snapshot")
+}
+
+// Remove is not supported
+func (o *restoreObject) Remove(ctx context.Context) error {
+	return errors.New("can't remove a file from a snapshot")
+}
+
+// chunkReader reads the chunks named by their hashes one after another
+type chunkReader struct {
+	ctx    context.Context
+	repo   *Repo
+	chunks []string      // chunks still to be opened
+	offset int64         // bytes to skip before reading
+	in     io.ReadCloser // the chunk being read or nil
+}
+
+// Read reads from the current chunk, opening the next one when it is done
+func (r *chunkReader) Read(p []byte) (n int, err error) {
+	for {
+		if r.in == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			o, err := r.repo.f.NewObject(r.ctx, dataPath(r.chunks[0]))
+			if err != nil {
+				return 0, fmt.Errorf("failed to find chunk %s: %w", r.chunks[0], err)
+			}
+			r.chunks = r.chunks[1:]
+			// Skip whole chunks without reading them
+			if r.offset >= o.Size() {
+				r.offset -= o.Size()
+				continue
+			}
+			var options []fs.OpenOption
+			if r.offset > 0 {
+				options = append(options, &fs.SeekOption{Offset: r.offset})
+				r.offset = 0
+			}
+			r.in, err = operations.Open(r.ctx, o, options...)
+			if err != nil {
+				return 0, err
+			}
+		}
+		n, err = r.in.Read(p)
+		if err == io.EOF {
+			err = r.in.Close()
+			r.in = nil
+			if err != nil || n > 0 {
+				return n, err
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close closes the chunk being read
+func (r *chunkReader) Close() error {
+	if r.in == nil {
+		return nil
+	}
+	err := r.in.Close()
+	r.in = nil
+	return err
+}
+
+// Restore copies the files in snapshot from repo to fdst
+func Restore(ctx context.Context, repo *Repo, snapshot *Snapshot, fdst fs.Fs) error {
+	ci := fs.GetConfig(ctx)
+	fi := filter.GetConfig(ctx)
+
+	// Make the directories so empty ones are restored
+	if fdst.Features().CanHaveEmptyDirectories && fi.InActive() {
+		for _, dir := range snapshot.Dirs {
+			err := operations.Mkdir(ctx, fdst, dir)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		copied int
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(ci.Transfers)
+	for i := range snapshot.Files {
+		file := &snapshot.Files[i]
+		if !fi.Include(file.Path, file.Size, file.ModTime, nil) {
+			continue
+		}
+		g.Go(func() error {
+			src := &restoreObject{repo: repo, file: file}
+			dst, err := fdst.NewObject(gCtx, file.Path)
+			if errors.Is(err, fs.ErrorObjectNotFound) {
+				dst = nil
+			} else if err != nil {
+				return err
+			}
+			if dst != nil && !operations.NeedTransfer(gCtx, dst, src) {
+				return nil
+			}
+			_, err = operations.Copy(gCtx, fdst, dst, file.Path, src)
+			if err != nil {
+				return fmt.Errorf("failed to restore %q: %w", file.Path, err)
+			}
+			mu.Lock()
+			copied++
+			mu.Unlock()
+			return nil
+		})
+	}
+	err := g.Wait()
+	if err != nil {
+		return err
+	}
+	fs.Logf(nil, "Snapshot %s: restored %d files", snapshot.ID, copied)
+	return nil
+}
@@ -0,0 +1,273 @@
+package bilib
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+// MaxMergeEdits is the largest number of inserted plus deleted lines
+// between the common ancestor and either side which MergeLines will
+// diff before giving up.
+const MaxMergeEdits = 5000
+
+// ErrMergeConflict is returned by MergeLines when both sides changed
+// the same or adjacent lines in different ways.
+var ErrMergeConflict = errors.New("changes overlap")
+
+// ErrMergeTooComplex is returned by MergeLines when a side differs
+// from the common ancestor by more than MaxMergeEdits lines.
+var ErrMergeTooComplex = errors.New("too many changes to merge")
+
+// IsText returns true if data looks like text, using the same test as
+// git: there are no NUL bytes in the first 8000 bytes.
+func IsText(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) < 0
+}
+
+// MergeLines does a line based three-way merge of a and b which both
+// derive from base.
+//
+// Regions changed on one side only take the changed version and
+// regions changed identically on both sides are taken once. If both
+// sides changed the same or adjacent lines differently it returns
+// ErrMergeConflict.
+func MergeLines(base, a, b []byte) ([]byte, error) {
+	o, x, y := splitLines(base), splitLines(a), splitLines(b)
+	mx, err := matchLines(o, x)
+	if err != nil {
+		return nil, err
+	}
+	my, err := matchLines(o, y)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	io, ix, iy := 0, 0, 0
+	for io < len(o) || ix < len(x) || iy < len(y) {
+		// Output lines which are unchanged on both sides
+		n := 0
+		for io+n < len(o) && mx[io+n] == ix+n && my[io+n] == iy+n {
+			n++
+		}
+		if n > 0 {
+			for _, line := range o[io : io+n] {
+				out.WriteString(line)
+			}
+			io, ix, iy = io+n, ix+n, iy+n
+			continue
+		}
+
+		// Find the end of the changed region, which is the next
+		// line of base present on both sides
+		eo := io
+		for eo < len(o) && (mx[eo] < 0 || my[eo] < 0) {
+			eo++
+		}
+		ex, ey := len(x), len(y)
+		if eo < len(o) {
+			ex, ey = mx[eo], my[eo]
+		}
+		co, cx, cy := o[io:eo], x[ix:ex], y[iy:ey]
+		switch {
+		case equalLines(co, cx):
+			cx = cy // only changed on b
+		case equalLines(co, cy), equalLines(cx, cy):
+			// only changed on a or changed the same on both
+		default:
+			return nil, ErrMergeConflict
+		}
+		for _, line := range cx {
+			out.WriteString(line)
+		}
+		io, ix, iy = eo, ex, ey
+	}
+	return out.Bytes(), nil
+}
+
+// splitLines splits data into lines keeping the line endings
+func splitLines(data []byte) (lines []string) {
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n') + 1
+		if i == 0 {
+			i = len(data)
+		}
+		lines = append(lines, string(data[:i]))
+		data = data[i:]
+	}
+	return lines
+}
+
+// equalLines returns true if a and b are the same
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLines finds a longest common subsequence of o and x
+//
+// It returns a slice the length of o holding the index of the matching
+// line in x, or -1 if the line of o was removed in x.
+func matchLines(o, x []string) ([]int, error) {
+	match := make([]int, len(o))
+	for i := range match {
+		match[i] = -1
+	}
+
+	// Match the common prefix and suffix without diffing
+	start := 0
+	for start < len(o) && start < len(x) && o[start] == x[start] {
+		match[start] = start
+		start++
+	}
+	endO, endX := len(o), len(x)
+	for endO > start && endX > start && o[endO-1] == x[endX-1] {
+		endO--
+		endX--
+		match[endO] = endX
+	}
+
+	pairs, err := myersDiff(o[start:endO], x[start:endX])
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		match[start+pair[0]] = start + pair[1]
+	}
+	return match, nil
+}
+
+// myersDiff finds the matching lines of a and b with the fewest edits
+// using the linear space variant of Myers' O(ND) algorithm, which
+// finds the middle of the edit script then recurses on each half.
+//
+// It returns the pairs of matching indexes in order.
+func myersDiff(a, b []string) (pairs [][2]int, err error) {
+	d := &differ{a: a, b: b}
+	if err = d.diff(0, len(a), 0, len(b)); err != nil {
+		return nil, err
+	}
+	sort.Slice(d.pairs, func(i, j int) bool { return d.pairs[i][0] < d.pairs[j][0] })
+	return d.pairs, nil
+}
+
+// differ holds the state of myersDiff
+type differ struct {
+	a, b  []string
+	pairs [][2]int // matching lines found so far in any order
+	edits int      // inserted plus deleted lines found so far
+}
+
+// match records that a[x:x+n] matches b[y:y+n]
+func (d *differ) match(x, y, n int) {
+	for i := range n {
+		d.pairs = append(d.pairs, [2]int{x + i, y + i})
+	}
+}
+
+// diff finds the matching lines of a[aLo:aHi] and b[bLo:bHi]
+func (d *differ) diff(aLo, aHi, bLo, bHi int) error {
+	// Match the common prefix and suffix
+	n := 0
+	for aLo+n < aHi && bLo+n < bHi && d.a[aLo+n] == d.b[bLo+n] {
+		n++
+	}
+	d.match(aLo, bLo, n)
+	aLo, bLo = aLo+n, bLo+n
+	n = 0
+	for aHi-n > aLo && bHi-n > bLo && d.a[aHi-n-1] == d.b[bHi-n-1] {
+		n++
+	}
+	d.match(aHi-n, bHi-n, n)
+	aHi, bHi = aHi-n, bHi-n
+	if aLo == aHi || bLo == bHi {
+		// What is left is all inserts or all deletes
+		d.edits += (aHi - aLo) + (bHi - bLo)
+		if d.edits > MaxMergeEdits {
+			return ErrMergeTooComplex
+		}
+		return nil
+	}
+	x, y, u, v, err := d.middleSnake(aLo, aHi, bLo, bHi)
+	if err != nil {
+		return err
+	}
+	if err = d.diff(aLo, x, bLo, y); err != nil {
+		return err
+	}
+	d.match(x, y, u-x)
+	return d.diff(u, aHi, v, bHi)
+}
+
+// middleSnake finds the snake (a run of matching lines) in the middle
+// of an edit script of a[aLo:aHi] and b[bLo:bHi] with the fewest
+// edits, returning its start (x, y) and end (u, v).
+//
+// It searches forwards from the start and backwards from the end at
+// the same time so it only needs space for one row of each search. It
+// gives up if the edits would take the running total of the whole diff
+// over MaxMergeEdits.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int, err error) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// vf[offset+k] is the furthest x reached on diagonal k = x-y
+	// searching forwards and vb[offset+k] the furthest x reached
+	// on diagonal k counting back from the end.
+	vf := make([]int, 2*maxD+3)
+	vb := make([]int, 2*maxD+3)
+	limit := MaxMergeEdits - d.edits
+	for e := 0; e <= maxD; e++ {
+		if 2*e-1 > limit {
+			break
+		}
+		for k := -e; k <= e; k += 2 {
+			var x0 int
+			if k == -e || (k != e && vf[offset+k-1] < vf[offset+k+1]) {
+				x0 = vf[offset+k+1]
+			} else {
+				x0 = vf[offset+k-1] + 1
+			}
+			x, y := x0, x0-k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			if kb := delta - k; odd && kb >= -(e-1) && kb <= e-1 && x+vb[offset+kb] >= n {
+				return aLo + x0, bLo + x0 - k, aLo + x, bLo + y, nil
+			}
+		}
+		if 2*e > limit {
+			break
+		}
+		for k := -e; k <= e; k += 2 {
+			var x0 int
+			if k == -e || (k != e && vb[offset+k-1] < vb[offset+k+1]) {
+				x0 = vb[offset+k+1]
+			} else {
+				x0 = vb[offset+k-1] + 1
+			}
+			x, y := x0, x0-k
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+			if kf := delta - k; !odd && kf >= -e && kf <= e && x+vf[offset+kf] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - x0 + k, nil
+			}
+		}
+	}
+	return 0, 0, 0, 0, ErrMergeTooComplex
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/watch"
 
 	"github.com/spf13/cobra"
 )
@@ -61,6 +62,9 @@ type Options struct {
 	ConflictSuffixFlag    string
 	ConflictSuffix1       string
 	ConflictSuffix2       string
+	Changes               *watch.ChangeSet // if set only look at these paths (for --watch)
+	listing1              string           // if set use this Path1 listing instead of the default (for BisyncN)
+	listing2              string           // if set use this Path2 listing instead of the default (for BisyncN)
 }
 
 // Default values
@@ -151,26 +155,33 @@ func init() {
 	flags.FVarP(cmdFlags, &Opt.ConflictResolve, "conflict-resolve", "", "Automatically resolve conflicts by preferring the version that is: "+ConflictResolveList+" (default: none)", "")
 	flags.FVarP(cmdFlags, &Opt.ConflictLoser, "conflict-loser", "", "Action to take on the loser of a sync conflict (when there is a winner) or on both files (when there is no winner): "+ConflictLoserList+" (default: num)", "")
 	flags.StringVarP(cmdFlags, &Opt.ConflictSuffixFlag, "conflict-suffix", "", Opt.ConflictSuffixFlag, "Suffix to use when renaming a --conflict-loser. Can be either one string or two comma-separated strings to assign different suffixes to Path1/Path2. (default: 'conflict')", "")
+	watch.AddFlags(cmdFlags)
 	_ = cmdFlags.MarkHidden("debugname")
 	_ = cmdFlags.MarkHidden("localtime")
 }
 
 // bisync command definition
 var commandDefinition = &cobra.Command{
-	Use:   "bisync remote1:path1 remote2:path2",
+	Use:   "bisync remote1:path1 remote2:path2 [remote3:path3 ...]",
 	Short: shortHelp,
 	Long:  longHelp,
 	Annotations: map[string]string{
 		"versionIntroduced": "v1.58",
 		"groups":            "Filter,Copy,Important",
 	},
+	// Any number of paths from 2 up
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(command *cobra.Command, args []string) error {
 		// NOTE: avoid putting too much handling here, as it won't apply to the rc.
 		// Generally it's best to put init-type stuff in Bisync() (operations.go)
-		cmd.CheckArgs(2, 2, command, args)
-		fs1, file1, fs2, file2 := cmd.NewFsSrcDstFiles(args)
-		if file1 != "" || file2 != "" {
-			return errors.New("paths must be existing directories")
+		cmd.CheckNoVersionAt(command)
+		fss := make([]fs.Fs, len(args))
+		for i, arg := range args {
+			var fileName string
+			fss[i], fileName = cmd.NewFsFile(arg)
+			if fileName != "" {
+				return errors.New("paths must be existing directories")
+			}
 		}
 
 		ctx := context.Background()
@@ -180,10 +191,13 @@ var commandDefinition = &cobra.Command{
 			TZ = time.Local
 		}
 
-		commonHashes := fs1.Hashes().Overlap(fs2.Hashes())
-		isDropbox1 := strings.HasPrefix(fs1.String(), "Dropbox")
-		isDropbox2 := strings.HasPrefix(fs2.String(), "Dropbox")
-		if commonHashes == hash.Set(0) && (isDropbox1 || isDropbox2) {
+		commonHashes := fss[0].Hashes()
+		isDropbox := false
+		for _, f := range fss {
+			commonHashes = commonHashes.Overlap(f.Hashes())
+			isDropbox = isDropbox || strings.HasPrefix(f.String(), "Dropbox")
+		}
+		if commonHashes == hash.Set(0) && isDropbox {
 			ci := fs.GetConfig(ctx)
 			if !ci.DryRun && !ci.RefreshTimes {
 				fs.Debugf(nil, "Using flag --refresh-times is recommended")
@@ -191,8 +205,11 @@ var commandDefinition = &cobra.Command{
 		}
 
 		cmd.Run(false, true, command, func() error {
-			err := Bisync(ctx, fs1, fs2, &opt)
-			if err == ErrBisyncAborted {
+			if watch.Opt.Watch {
+				return Watch(ctx, fss, &opt, &watch.Opt)
+			}
+			err := BisyncN(ctx, fss, &opt)
+			if errors.Is(err, ErrBisyncAborted) {
 				return fserrors.FatalError(err)
 			}
 			return err
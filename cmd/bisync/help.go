@@ -21,6 +21,8 @@ var rcHelp = makeHelp(`This takes the following parameters
 
 - path1 - a remote directory string e.g. |drive:path1|
 - path2 - a remote directory string e.g. |drive:path2|
+- path3, path4, ... - optional further remote directory strings to
+  keep in sync with path1 and path2
 - dryRun - dry-run mode
 - resync - performs the resync run
 - checkAccess - abort if {CHECKFILE} files are not found on both filesystems
@@ -56,6 +58,13 @@ On each successive run it will:
   Changes include |New|, |Newer|, |Older|, and |Deleted| files.
 - Propagate changes on Path1 to Path2, and vice-versa.
 
+More than two paths can be given to keep them all in sync, for example
+a laptop, a NAS and a cloud remote. Each path keeps one listing of its
+state as of the last run and each of the other paths is bisynced with
+Path1 in turn, so a change on any of them reaches all the others in one
+run. See [syncing more than two paths](https://rclone.org/bisync/#multiple-paths)
+for details.
+
 Bisync is considered an **advanced command**, so use with care.
 Make sure you have read and understood the entire [manual](https://rclone.org/bisync)
 (especially the [Limitations](https://rclone.org/bisync/#limitations) section)
@@ -411,13 +411,9 @@ func (b *bisyncRun) checkListing(ls *fileList, listing, msg string) error {
 
 // listingNum should be 1 for path1 or 2 for path2
 func (b *bisyncRun) loadListingNum(listingNum int) (*fileList, error) {
-	listingpath := b.basePath + ".path1.lst-new"
+	listingpath := b.newListing1
 	if listingNum == 2 {
-		listingpath = b.basePath + ".path2.lst-new"
-	}
-
-	if b.opt.DryRun {
-		listingpath = strings.Replace(listingpath, ".lst-", ".lst-dry-", 1)
+		listingpath = b.newListing2
 	}
 
 	fs.Debugf(nil, "loading listing for path %d at: %s", listingNum, listingpath)
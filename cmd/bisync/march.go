@@ -2,6 +2,7 @@ package bisync
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -47,6 +48,9 @@ func (b *bisyncRun) makeMarchListing(ctx context.Context) (*fileList, *fileList,
 	if b.march.err == nil {
 		b.march.err = b.march.firstErr
 	}
+	if b.march.err == nil && b.opt.Changes != nil {
+		b.march.err = b.addUnchanged()
+	}
 	if b.march.err != nil {
 		b.handleErr("march", "error during march", b.march.err, true, true)
 		b.abort = true
@@ -68,8 +72,41 @@ func (b *bisyncRun) makeMarchListing(ctx context.Context) (*fileList, *fileList,
 	return b.march.ls1, b.march.ls2, b.march.err
 }
 
+// addUnchanged adds the entries which weren't listed as they haven't
+// changed (with --watch) to the listings from the prior listings
+func (b *bisyncRun) addUnchanged() error {
+	for _, x := range []struct {
+		listing string
+		ls      *fileList
+	}{
+		{b.listing1, b.march.ls1},
+		{b.listing2, b.march.ls2},
+	} {
+		prior, err := b.loadListing(x.listing)
+		if err != nil {
+			return fmt.Errorf("failed to read prior listing for unchanged files: %w", err)
+		}
+		for _, file := range prior.list {
+			fi := prior.get(file)
+			if !b.opt.Changes.IncludePath(file, fi.flags == "d") {
+				x.ls.put(file, fi.size, fi.time, fi.hash, fi.id, fi.flags)
+			}
+		}
+	}
+	return nil
+}
+
+// skipUnchanged returns true if only changed paths are being synced
+// and o hasn't changed
+func (b *bisyncRun) skipUnchanged(o fs.DirEntry) bool {
+	return b.opt.Changes != nil && !b.opt.Changes.Include(o)
+}
+
 // SrcOnly have an object which is on path1 only
 func (b *bisyncRun) SrcOnly(o fs.DirEntry) (recurse bool) {
+	if b.skipUnchanged(o) {
+		return false
+	}
 	fs.Debugf(o, "path1 only")
 	b.parse(o, true)
 	return isDir(o)
@@ -77,6 +114,9 @@ func (b *bisyncRun) SrcOnly(o fs.DirEntry) (recurse bool) {
 
 // DstOnly have an object which is on path2 only
 func (b *bisyncRun) DstOnly(o fs.DirEntry) (recurse bool) {
+	if b.skipUnchanged(o) {
+		return false
+	}
 	fs.Debugf(o, "path2 only")
 	b.parse(o, false)
 	return isDir(o)
@@ -84,6 +124,9 @@ func (b *bisyncRun) DstOnly(o fs.DirEntry) (recurse bool) {
 
 // Match is called when object exists on both path1 and path2 (whether equal or not)
 func (b *bisyncRun) Match(ctx context.Context, o2, o1 fs.DirEntry) (recurse bool) {
+	if b.skipUnchanged(o1) {
+		return false
+	}
 	fs.Debugf(o1, "both path1 and path2")
 	b.march.marchAliasLock.Lock()
 	b.aliases.Add(o1.Remote(), o2.Remote())
@@ -0,0 +1,223 @@
+package bisync
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/lib/terminal"
+)
+
+// maxMergeSize is the largest file --conflict-resolve merge will
+// keep an ancestor of or try to merge
+const maxMergeSize = 4 * 1024 * 1024
+
+// errNotMergeable is returned for files which are too big or not text
+var errNotMergeable = errors.New("not a text file under 4 MiB")
+
+// ancestorDir returns the directory in the workdir holding the last
+// synced version of files for --conflict-resolve merge
+func (b *bisyncRun) ancestorDir() string {
+	return b.basePath + ".ancestors"
+}
+
+// ancestorPath returns the file holding the ancestor of remote
+func (b *bisyncRun) ancestorPath(remote string) string {
+	sum := md5.Sum([]byte(remote))
+	return filepath.Join(b.ancestorDir(), hex.EncodeToString(sum[:]))
+}
+
+// ancestorTimeWindow is how close the modification time of a saved
+// ancestor must be to the time in the prior listing when there is no
+// hash to check, to allow for the precision of the workdir.
+const ancestorTimeWindow = time.Second
+
+// isAncestor checks base, the saved ancestor of file, is the version
+// in the prior Path1 listing.
+//
+// The sizes must match then the hashes are compared if the listing
+// has one, otherwise the modification times, as the ancestor's is set
+// to the time in the listing when it is saved.
+func (b *bisyncRun) isAncestor(file string, base []byte) bool {
+	prior := b.mergeListing.get(file)
+	if prior == nil || prior.size != int64(len(base)) {
+		return false
+	}
+	if ht := b.mergeListing.hash; ht != hash.None && prior.hash != "" {
+		sums, err := hash.StreamTypes(bytes.NewReader(base), hash.NewHashSet(ht))
+		if err != nil {
+			fs.Debugf(file, "Failed to hash merge ancestor: %v", err)
+			return false
+		}
+		return hash.Equals(sums[ht], prior.hash)
+	}
+	info, err := os.Stat(b.ancestorPath(file))
+	if err != nil {
+		return false
+	}
+	dt := info.ModTime().Sub(prior.time)
+	return dt >= -ancestorTimeWindow && dt <= ancestorTimeWindow
+}
+
+// readMergeable reads the contents of remote on f if it is small
+// enough to merge and looks like text
+func readMergeable(ctx context.Context, f fs.Fs, remote string) (data []byte, err error) {
+	o, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	if o.Size() > maxMergeSize {
+		return nil, errNotMergeable
+	}
+	in, err := operations.Open(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(in, &err)
+	data, err = io.ReadAll(io.LimitReader(in, maxMergeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMergeSize || !bilib.IsText(data) {
+		return nil, errNotMergeable
+	}
+	return data, nil
+}
+
+// merge tries a three-way merge of a conflict using the ancestor saved
+// on the last run. If it succeeds the merged file is written to Path1
+// and queued for copying to Path2.
+//
+// It returns false if the conflict couldn't be merged, in which case
+// the normal conflict handling should be used.
+func (b *bisyncRun) merge(ctx context.Context, path1, path2, file, alias string, copy1to2 *bilib.Names) (merged bool, err error) {
+	fail := func(reason string, args ...any) (bool, error) {
+		fs.Infof(file, Color(terminal.YellowFg, "Can't merge: %s"), fmt.Sprintf(reason, args...))
+		return false, nil
+	}
+
+	// Check the ancestor is the version from the last sync
+	base, err := os.ReadFile(b.ancestorPath(file))
+	if err != nil {
+		return fail("no common ancestor saved from the prior sync")
+	}
+	if b.mergeListing == nil {
+		if b.mergeListing, err = b.loadListing(b.listing1); err != nil {
+			return fail("failed to load prior listing: %v", err)
+		}
+	}
+	if !b.isAncestor(file, base) {
+		return fail("saved common ancestor is out of date")
+	}
+
+	data1, err := readMergeable(ctx, b.fs1, file)
+	if err != nil {
+		return fail("Path1 version: %v", err)
+	}
+	data2, err := readMergeable(ctx, b.fs2, alias)
+	if err != nil {
+		return fail("Path2 version: %v", err)
+	}
+	result, err := bilib.MergeLines(base, data1, data2)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	if !operations.SkipDestructive(ctx, file, "merge") {
+		b.indent("!Path1", path1+file, "Writing merged version")
+		_, err = operations.RcatSize(ctx, b.fs1, file, io.NopCloser(bytes.NewReader(result)), int64(len(result)), time.Now(), nil)
+		if err != nil {
+			b.critical = true
+			return false, fmt.Errorf("%s merge failed for %s: %w", path1, path1+file, err)
+		}
+	}
+	fs.Infoc(file, Color(terminal.GreenFg, "Changes from both paths were merged"))
+	b.indent("!Path1", path2+alias, "Queue copy to Path2")
+	copy1to2.Add(file)
+	return true, nil
+}
+
+// updateAncestors saves the current version of the files in from1 and
+// from2 (which are read from Path1 and Path2) as the common ancestors
+// for --conflict-resolve merge, then removes the ancestors of files
+// which are no longer in the listings.
+//
+// Errors are logged but not returned as they only stop future
+// conflicts being merged.
+func (b *bisyncRun) updateAncestors(ctx context.Context, from1, from2 bilib.Names) {
+	if b.opt.ConflictResolve != PreferMerge || b.opt.DryRun {
+		return
+	}
+	ls1, err := b.loadListing(b.listing1)
+	if err != nil {
+		fs.Errorf(nil, "Failed to update merge ancestors: %v", err)
+		return
+	}
+	dir := b.ancestorDir()
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		fs.Errorf(nil, "Failed to update merge ancestors: %v", err)
+		return
+	}
+	save := func(f fs.Fs, remote, key string) {
+		if !ls1.has(key) || ls1.isDir(key) {
+			return
+		}
+		path := b.ancestorPath(key)
+		data, err := readMergeable(ctx, f, remote)
+		if errors.Is(err, errNotMergeable) {
+			_ = os.Remove(path)
+			return
+		} else if err != nil {
+			fs.Errorf(remote, "Failed to read merge ancestor: %v", err)
+			_ = os.Remove(path)
+			return
+		}
+		// Set the modification time to the one in the listing
+		// so isAncestor can check it
+		modTime := ls1.getTime(key)
+		tmp := path + ".tmp"
+		err = os.WriteFile(tmp, data, bilib.PermSecure)
+		if err == nil {
+			err = os.Chtimes(tmp, modTime, modTime)
+		}
+		if err == nil {
+			err = os.Rename(tmp, path)
+		}
+		if err != nil {
+			fs.Errorf(remote, "Failed to save merge ancestor: %v", err)
+		}
+	}
+	for _, name := range from1.ToList() {
+		save(b.fs1, name, name)
+	}
+	for _, name := range from2.ToList() {
+		save(b.fs2, name, b.aliases.Alias(name))
+	}
+
+	// Remove the ancestors of files which have gone
+	keep := map[string]bool{}
+	for _, name := range ls1.list {
+		keep[filepath.Base(b.ancestorPath(name))] = true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		fs.Errorf(nil, "Failed to clean up merge ancestors: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if !keep[entry.Name()] {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package bisync_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLines(t *testing.T) {
+	const base = "one\ntwo\nthree\nfour\nfive\n"
+	for _, test := range []struct {
+		name string
+		a, b string
+		want string
+		err  error
+	}{
+		{"unchanged", base, base, base, nil},
+		{"one side", "one\nTWO\nthree\nfour\nfive\n", base, "one\nTWO\nthree\nfour\nfive\n", nil},
+		{"other side", base, "one\ntwo\nthree\nfour\nFIVE\n", "one\ntwo\nthree\nfour\nFIVE\n", nil},
+		{"both sides", "one\nTWO\nthree\nfour\nfive\n", "one\ntwo\nthree\nfour\nFIVE\n", "one\nTWO\nthree\nfour\nFIVE\n", nil},
+		{"insert and delete", "zero\none\ntwo\nthree\nfour\nfive\n", "one\ntwo\nfour\nfive\nsix\n", "zero\none\ntwo\nfour\nfive\nsix\n", nil},
+		{"same change", "one\nTWO\nthree\nfour\nfive\n", "one\nTWO\nthree\nfour\nfive\n", "one\nTWO\nthree\nfour\nfive\n", nil},
+		{"no final newline", "one\ntwo\nthree\nfour\nfive", "ONE\ntwo\nthree\nfour\nfive\n", "ONE\ntwo\nthree\nfour\nfive", nil},
+		{"overlap", "one\nTWO\nthree\nfour\nfive\n", "one\nTwo\nthree\nfour\nfive\n", "", bilib.ErrMergeConflict},
+		{"adjacent", "one\nTWO\nthree\nfour\nfive\n", "one\ntwo\nTHREE\nfour\nfive\n", "", bilib.ErrMergeConflict},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := bilib.MergeLines([]byte(base), []byte(test.a), []byte(test.b))
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, string(got))
+		})
+	}
+
+	// Both sides appending to an empty file overlap
+	_, err := bilib.MergeLines(nil, []byte("a\n"), []byte("b\n"))
+	assert.ErrorIs(t, err, bilib.ErrMergeConflict)
+
+	// Larger files with changes scattered through them
+	var lines []string
+	for range 2000 {
+		lines = append(lines, random.String(20)+"\n")
+	}
+	big := strings.Join(lines, "")
+	a := strings.Replace(big, lines[10], "changed a\n", 1)
+	a = strings.Replace(a, lines[1500], "", 1)
+	b := strings.Replace(big, lines[1000], "changed b\n", 1)
+	b = strings.Replace(b, lines[1999], lines[1999]+"appended b\n", 1)
+	want := strings.Replace(a, lines[1000], "changed b\n", 1)
+	want = strings.Replace(want, lines[1999], lines[1999]+"appended b\n", 1)
+	got, err := bilib.MergeLines([]byte(big), []byte(a), []byte(b))
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+
+	// Changes are limited to MaxMergeEdits lines in total, however
+	// they are spread through the file
+	edit := func(every int) string {
+		edited := slices.Clone(lines)
+		for i := 0; i < len(edited); i += every {
+			edited[i] = "changed\n"
+		}
+		return strings.Join(edited, "")
+	}
+	_, err = bilib.MergeLines([]byte(big), []byte(edit(2)), []byte(big))
+	require.NoError(t, err) // 2000 edits
+	_, err = bilib.MergeLines([]byte(big), []byte(edit(1)), []byte(big))
+	require.NoError(t, err) // 4000 edits
+	more := strings.Repeat("more\n", bilib.MaxMergeEdits-4000+1)
+	_, err = bilib.MergeLines([]byte(big), []byte(edit(1)+more), []byte(big))
+	assert.ErrorIs(t, err, bilib.ErrMergeTooComplex)
+}
+
+func TestBisyncConflictMerge(t *testing.T) {
+	if !isLocal(*fstest.RemoteName) {
+		t.Skip("TestBisyncConflictMerge is skipped on non-local")
+	}
+	ctx, _ := fs.AddConfig(context.Background())
+	ctx = accounting.WithStatsGroup(ctx, random.String(8)) // keep stats separate
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	f1, err := fs.NewFs(ctx, dir1)
+	require.NoError(t, err)
+	f2, err := fs.NewFs(ctx, dir2)
+	require.NoError(t, err)
+	opt := &bisync.Options{
+		Workdir:   t.TempDir(),
+		MaxDelete: bisync.DefaultMaxDelete,
+	}
+	require.NoError(t, opt.ConflictResolve.Set("merge"))
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0666))
+		modTime = modTime.Add(time.Hour)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	check := func(want map[string]string) {
+		t.Helper()
+		for _, dir := range []string{dir1, dir2} {
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			got := map[string]string{}
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				got[entry.Name()] = string(data)
+			}
+			assert.Equal(t, want, got, dir)
+		}
+	}
+
+	write(dir1, "notes.txt", "one\ntwo\nthree\n")
+	write(dir1, "image.bin", "\x00\x01\x02")
+	write(dir1, "other.txt", "other")
+	resyncOpt := *opt
+	resyncOpt.Resync = true
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, &resyncOpt))
+
+	// Separate edits on both sides are merged
+	write(dir1, "notes.txt", "ONE\ntwo\nthree\n")
+	write(dir2, "notes.txt", "one\ntwo\nTHREE\n")
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	check(map[string]string{"notes.txt": "ONE\ntwo\nTHREE\n", "image.bin": "\x00\x01\x02", "other.txt": "other"})
+
+	// The merged version is the ancestor for the next merge
+	write(dir1, "notes.txt", "ONE\ntwo\nTHREE\nfour\n")
+	write(dir2, "notes.txt", "ONE\nTWO\nTHREE\n")
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	check(map[string]string{"notes.txt": "ONE\nTWO\nTHREE\nfour\n", "image.bin": "\x00\x01\x02", "other.txt": "other"})
+
+	// Overlapping edits and binary files are renamed as usual
+	write(dir1, "notes.txt", "1\nTWO\nTHREE\nfour\n")
+	write(dir2, "notes.txt", "uno\nTWO\nTHREE\nfour\n")
+	write(dir1, "image.bin", "\x00\x01")
+	write(dir2, "image.bin", "\x00\x02")
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	check(map[string]string{
+		"notes.txt.conflict1": "1\nTWO\nTHREE\nfour\n",
+		"notes.txt.conflict2": "uno\nTWO\nTHREE\nfour\n",
+		"image.bin.conflict1": "\x00\x01",
+		"image.bin.conflict2": "\x00\x02",
+		"other.txt":           "other",
+	})
+
+	// An ancestor which isn't the version in the prior listing isn't
+	// used, even if it is the same size
+	write(dir1, "other.txt", "1\n2\n3\n4\n5\n")
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	sum := md5.Sum([]byte("other.txt"))
+	ancestors, err := filepath.Glob(filepath.Join(opt.Workdir, "*.ancestors", hex.EncodeToString(sum[:])))
+	require.NoError(t, err)
+	require.Len(t, ancestors, 1)
+	require.NoError(t, os.WriteFile(ancestors[0], []byte("1\n2\nX\n4\n5\n"), 0600))
+	write(dir1, "other.txt", "1a\n2\n3\n4\n5\n")
+	write(dir2, "other.txt", "1\n2\n3\n4\n5b\n")
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	check(map[string]string{
+		"notes.txt.conflict1": "1\nTWO\nTHREE\nfour\n",
+		"notes.txt.conflict2": "uno\nTWO\nTHREE\nfour\n",
+		"image.bin.conflict1": "\x00\x01",
+		"image.bin.conflict2": "\x00\x02",
+		"other.txt.conflict1": "1a\n2\n3\n4\n5\n",
+		"other.txt.conflict2": "1\n2\n3\n4\n5b\n",
+	})
+}
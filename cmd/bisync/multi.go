@@ -0,0 +1,162 @@
+package bisync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/terminal"
+)
+
+// BisyncN bisyncs any number of paths so that a change on any of them
+// is propagated to all the others in one run.
+//
+// Each path has a single listing holding its state as of the last run,
+// which is shared by every pair it is bisynced in, so the normal
+// deltas, conflict detection and --conflict-resolve handling see the
+// changes made to each path since the last run.
+//
+// The first path is used as the hub. In the first pass each of the
+// other paths is bisynced with it in turn, always starting from the
+// hub's listing at the start of the run. So the hub's deltas include
+// the changes it received from earlier paths in this pass, and a
+// conflict between two of the other paths is seen as a conflict
+// between the hub (holding the earlier path's version) and the later
+// path. After this the hub and the last path are up to date.
+//
+// The hub's listing after each pair is kept for the second pass,
+// which bisyncs all but the last path with the hub again to pass on
+// the changes which arrived from later paths. Finally the hub's
+// listing is set to its state at the end of the first pass, so
+// anything which reached the hub after that is passed on again by the
+// next run.
+func BisyncN(ctx context.Context, fss []fs.Fs, opt *Options) (err error) {
+	if len(fss) < 2 {
+		return errors.New("bisync needs at least 2 paths")
+	}
+	if len(fss) == 2 {
+		return Bisync(ctx, fss[0], fss[1], opt)
+	}
+	if opt.BackupDir2 != "" {
+		return errors.New("--backup-dir2 can't be used with more than 2 paths - use --backup-dir instead")
+	}
+	listings, err := multiListings(opt, fss)
+	if err != nil {
+		return err
+	}
+	hub := fss[0]
+	hubListing := listings[0]
+	members := fss[1:]
+
+	// pairListings[i] is the hub's listing used with members[i]
+	pairListings := make([]string, len(members))
+	for i := range members {
+		pairListings[i] = fmt.Sprintf("%s-path%d", hubListing, i+2)
+	}
+	if !opt.NoCleanup {
+		defer func() {
+			for _, listing := range pairListings {
+				removeListings(listing)
+			}
+		}()
+	}
+
+	run := func(pass int, i int) error {
+		pairOpt := *opt
+		pairOpt.listing1 = pairListings[i]
+		pairOpt.listing2 = listings[i+1]
+		fs.Infof(nil, "Bisync pass %d: Path1 %s with Path%d %s", pass, quotePath(bilib.FsPath(hub)), i+2, quotePath(bilib.FsPath(members[i])))
+		err := Bisync(ctx, hub, members[i], &pairOpt)
+		if errors.Is(err, ErrBisyncAborted) && bilib.FileExists(pairListings[i]+"-err") {
+			// The hub's listing can't be trusted either
+			markFailed(hubListing)
+		}
+		if err != nil {
+			return fmt.Errorf("bisync of Path1 with Path%d failed: %w", i+2, err)
+		}
+		return nil
+	}
+
+	// Gather the changes from all the paths on the hub, starting each
+	// pair from the hub's listing as of the last run
+	for i := range members {
+		removeListings(pairListings[i])
+		for _, suffix := range []string{"", "-old"} {
+			if err = bilib.CopyFileIfExists(hubListing+suffix, pairListings[i]+suffix); err != nil {
+				return fmt.Errorf("failed to copy Path1 listing: %w", err)
+			}
+		}
+		if err = run(1, i); err != nil {
+			return err
+		}
+	}
+
+	// Nothing has changed so there is nothing more to pass on
+	if opt.CheckSync == CheckSyncOnly {
+		return nil
+	}
+	if opt.DryRun {
+		fs.Logf(nil, "Skipping bisync pass 2 as --dry-run is set - changes from later paths won't be shown for earlier ones")
+		return nil
+	}
+
+	// Pass the changes from later paths back to the earlier ones.
+	// The last path is already up to date.
+	for i := range members[:len(members)-1] {
+		if err = run(2, i); err != nil {
+			return err
+		}
+	}
+
+	// Save the hub's listing as of the end of the first pass
+	last := pairListings[len(pairListings)-1]
+	if err = bilib.CopyFileIfExists(hubListing, hubListing+"-old"); err != nil {
+		return fmt.Errorf("failed to save old Path1 listing: %w", err)
+	}
+	if err = bilib.CopyFile(last, hubListing); err != nil {
+		return fmt.Errorf("failed to save Path1 listing: %w", err)
+	}
+	fs.Infoc(nil, Color(terminal.GreenFg, fmt.Sprintf("Bisync of %d paths successful", len(fss))))
+	return nil
+}
+
+// multiListings returns the names of the listings of each of fss when
+// they are bisynced together.
+//
+// They are named after all of fss so each path has its own listing for
+// each set of paths it is in.
+func multiListings(opt *Options, fss []fs.Fs) ([]string, error) {
+	workDir := opt.Workdir
+	if workDir == "" {
+		workDir = DefaultWorkdir
+	}
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make workdir absolute: %w", err)
+	}
+	if err = os.MkdirAll(workDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create workdir: %w", err)
+	}
+	names := make([]string, len(fss))
+	for i, f := range fss {
+		names[i] = bilib.StripHexString(bilib.CanonicalPath(bilib.FsPath(f)))
+	}
+	basePath := filepath.Join(workDir, strings.Join(names, ".."))
+	listings := make([]string, len(fss))
+	for i := range fss {
+		listings[i] = fmt.Sprintf("%s.path%d.lst", basePath, i+1)
+	}
+	return listings, nil
+}
+
+// removeListings removes listing and the files bisync makes from it
+func removeListings(listing string) {
+	for _, suffix := range []string{"", "-new", "-old", "-err", "-dry", "-dry-new", "-dry-old"} {
+		_ = os.Remove(listing + suffix)
+	}
+}
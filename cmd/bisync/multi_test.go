@@ -0,0 +1,118 @@
+package bisync_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBisyncMultiplePaths checks changes on any of 3 paths reach the others
+func TestBisyncMultiplePaths(t *testing.T) {
+	if !isLocal(*fstest.RemoteName) {
+		t.Skip("TestBisyncMultiplePaths is skipped on non-local")
+	}
+	ctx, _ := fs.AddConfig(context.Background())
+	ctx = accounting.WithStatsGroup(ctx, random.String(8)) // keep stats separate
+	dirs := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	fss := make([]fs.Fs, len(dirs))
+	for i, dir := range dirs {
+		var err error
+		fss[i], err = fs.NewFs(ctx, dir)
+		require.NoError(t, err)
+	}
+	opt := &bisync.Options{
+		Workdir:   t.TempDir(),
+		MaxDelete: bisync.DefaultMaxDelete,
+	}
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	write := func(dir int, name, content string) {
+		path := filepath.Join(dirs[dir], name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0666))
+		modTime = modTime.Add(time.Hour)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	check := func(want map[string]string) {
+		t.Helper()
+		for _, dir := range dirs {
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			got := map[string]string{}
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				got[entry.Name()] = string(data)
+			}
+			assert.Equal(t, want, got, dir)
+		}
+	}
+
+	// Resync gives every path all the files
+	write(0, "a.txt", "a")
+	write(1, "b.txt", "b")
+	write(2, "c.txt", "c")
+	resyncOpt := *opt
+	resyncOpt.Resync = true
+	require.NoError(t, bisync.BisyncN(ctx, fss, &resyncOpt))
+	check(map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+
+	// Each path has one listing shared by all the pairs it is in
+	checkListings := func() {
+		t.Helper()
+		listings, err := filepath.Glob(filepath.Join(opt.Workdir, "*.lst*"))
+		require.NoError(t, err)
+		var got []string
+		for _, listing := range listings {
+			if !strings.HasSuffix(listing, "-old") {
+				got = append(got, listing[strings.LastIndex(listing, ".path"):])
+			}
+		}
+		assert.Equal(t, []string{".path1.lst", ".path2.lst", ".path3.lst"}, got)
+	}
+	checkListings()
+
+	// Changes on the last and middle paths reach all the others
+	write(2, "c.txt", "c2")
+	write(1, "d.txt", "d")
+	require.NoError(t, os.Remove(filepath.Join(dirs[1], "a.txt")))
+	require.NoError(t, bisync.BisyncN(ctx, fss, opt))
+	check(map[string]string{"b.txt": "b", "c.txt": "c2", "d.txt": "d"})
+	checkListings()
+
+	// Changes on the first path and deletions on the last reach the
+	// others without being seen as conflicts
+	write(0, "c.txt", "c3")
+	require.NoError(t, os.Remove(filepath.Join(dirs[2], "d.txt")))
+	require.NoError(t, bisync.BisyncN(ctx, fss, opt))
+	check(map[string]string{"b.txt": "b", "c.txt": "c3"})
+	write(1, "d.txt", "d2")
+	require.NoError(t, bisync.BisyncN(ctx, fss, opt))
+	check(map[string]string{"b.txt": "b", "c.txt": "c3", "d.txt": "d2"})
+
+	// A conflict between two of the other paths is resolved with the
+	// loser kept under a new name everywhere
+	conflictOpt := *opt
+	require.NoError(t, conflictOpt.ConflictResolve.Set("newer"))
+	write(1, "b.txt", "b-older")
+	write(2, "b.txt", "b-newer")
+	require.NoError(t, bisync.BisyncN(ctx, fss, &conflictOpt))
+	check(map[string]string{"b.txt": "b-newer", "b.txt.conflict1": "b-older", "c.txt": "c3", "d.txt": "d2"})
+	checkListings()
+
+	// A run with nothing changed finds nothing to do
+	require.NoError(t, bisync.BisyncN(ctx, fss, opt))
+	check(map[string]string{"b.txt": "b-newer", "b.txt.conflict1": "b-older", "c.txt": "c3", "d.txt": "d2"})
+
+	// Too few paths is an error
+	assert.Error(t, bisync.BisyncN(ctx, fss[:1], opt))
+}
@@ -50,6 +50,7 @@ type bisyncRun struct {
 	DebugName          string
 	lockFile           string
 	renames            renames
+	mergeListing       *fileList // prior Path1 listing for --conflict-resolve merge
 	resyncIs1to2       bool
 	march              bisyncMarch
 	check              bisyncCheck
@@ -115,6 +116,12 @@ func Bisync(ctx context.Context, fs1, fs2 fs.Fs, optArg *Options) (err error) {
 	b.basePath = bilib.BasePath(ctx, b.workDir, b.fs1, b.fs2)
 	b.listing1 = b.basePath + ".path1.lst"
 	b.listing2 = b.basePath + ".path2.lst"
+	if opt.listing1 != "" {
+		b.listing1 = opt.listing1
+	}
+	if opt.listing2 != "" {
+		b.listing2 = opt.listing2
+	}
 	b.newListing1 = b.listing1 + "-new"
 	b.newListing2 = b.listing2 + "-new"
 	b.aliases = bilib.AliasMap{}
@@ -430,6 +437,9 @@ func (b *bisyncRun) runLocked(octx context.Context) (err error) {
 		_ = os.Remove(b.newListing2)
 	}
 
+	// Save the synced files as the common ancestors for --conflict-resolve merge
+	b.updateAncestors(fctx, queues.copy1to2, queues.copy2to1)
+
 	if opt.CheckSync == CheckSyncTrue && !opt.DryRun {
 		fs.Infof(nil, "Validating listings for Path1 %s vs Path2 %s", quotePath(path1), quotePath(path2))
 		if err := b.checkSync(b.listing1, b.listing2); err != nil {
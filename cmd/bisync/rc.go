@@ -3,6 +3,7 @@ package bisync
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/rclone/rclone/cmd/bisync/bilib"
@@ -102,8 +103,19 @@ func rcBisync(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 		return nil, err
 	}
 
+	fss := []fs.Fs{fs1, fs2}
+	for i := 3; ; i++ {
+		f, err := rc.GetFsNamed(octx, in, fmt.Sprintf("path%d", i))
+		if rc.IsErrParamNotFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		fss = append(fss, f)
+	}
+
 	output := bilib.CaptureOutput(func() {
-		err = Bisync(octx, fs1, fs2, opt)
+		err = BisyncN(octx, fss, opt)
 	})
 	_, _ = log.Writer().Write(output)
 	return rc.Params{"output": string(output)}, err
@@ -26,6 +26,7 @@ const (
 	PreferOlder
 	PreferLarger
 	PreferSmaller
+	PreferMerge
 )
 
 type preferChoices struct{}
@@ -39,6 +40,7 @@ func (preferChoices) Choices() []string {
 		PreferSmaller: "smaller",
 		PreferPath1:   "path1",
 		PreferPath2:   "path2",
+		PreferMerge:   "merge",
 	}
 }
 
@@ -136,8 +138,15 @@ type namePair struct {
 }
 
 func (b *bisyncRun) resolve(ctxMove context.Context, path1, path2, file, alias string, renameSkipped, copy1to2, copy2to1 *bilib.Names, ds1, ds2 *deltaSet) (err error) {
+	if b.opt.ConflictResolve == PreferMerge {
+		merged, err := b.merge(ctxMove, path1, path2, file, alias, copy1to2)
+		if err != nil || merged {
+			return err
+		}
+	}
+
 	winningPath := 0
-	if b.opt.ConflictResolve != PreferNone {
+	if b.opt.ConflictResolve != PreferNone && b.opt.ConflictResolve != PreferMerge {
 		winningPath = b.conflictWinner(ds1, ds2, file, alias)
 		if winningPath > 0 {
 			fs.Infof(file, Color(terminal.GreenFg, "The winner is: Path%d"), winningPath)
@@ -34,6 +34,10 @@ func (b *bisyncRun) setResyncDefaults() {
 		fs.Logf(nil, Color(terminal.YellowFg, "WARNING: ignoring --resync-mode %s as --compare does not include size."), b.opt.ResyncMode.String())
 		b.opt.ResyncMode = PreferPath1
 	}
+	if b.opt.ResyncMode == PreferMerge {
+		fs.Logf(nil, Color(terminal.YellowFg, "WARNING: ignoring --resync-mode %s as it is only supported by --conflict-resolve."), b.opt.ResyncMode.String())
+		b.opt.ResyncMode = PreferPath1
+	}
 }
 
 // resync implements the --resync mode.
@@ -158,6 +162,9 @@ func (b *bisyncRun) resync(fctx context.Context) (err error) {
 		return err
 	}
 
+	// save the copied files as the common ancestors for --conflict-resolve merge
+	b.updateAncestors(fctx, queues.copy1to2, queues.copy2to1)
+
 	if b.opt.CheckSync == CheckSyncTrue && !b.opt.DryRun {
 		path1 := bilib.FsPath(b.fs1)
 		path2 := bilib.FsPath(b.fs2)
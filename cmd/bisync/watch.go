@@ -0,0 +1,44 @@
+package bisync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/watch"
+)
+
+// Watch bisyncs fss then keeps running, bisyncing again each time any
+// of them change until ctx is cancelled.
+//
+// The first run is a normal run (or a resync if opt.Resync is set).
+// Later runs only list the paths which changed on any of fss and take
+// everything else from the prior listings.
+func Watch(ctx context.Context, fss []fs.Fs, opt *Options, watchOpt *watch.Options) error {
+	first := true
+	return watch.Run(ctx, fss, watchOpt, func(ctx context.Context, changes []*watch.ChangeSet) error {
+		runOpt := *opt
+		if !first {
+			runOpt.Resync = false
+			runOpt.ResyncMode = PreferNone
+			runOpt.Changes = watch.NewChangeSet()
+			for _, c := range changes {
+				if c == nil {
+					runOpt.Changes = nil
+					break
+				}
+				runOpt.Changes.AddSet(c)
+			}
+		}
+		err := BisyncN(ctx, fss, &runOpt)
+		if errors.Is(err, ErrBisyncAborted) {
+			// Later runs will fail too until the problem is fixed
+			return fserrors.FatalError(err)
+		} else if err != nil {
+			return err
+		}
+		first = false
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+package bisync_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/watch"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBisyncWatch checks changes are bisynced while watching
+func TestBisyncWatch(t *testing.T) {
+	if !isLocal(*fstest.RemoteName) {
+		t.Skip("TestBisyncWatch is skipped on non-local")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("TestBisyncWatch needs inotify")
+	}
+	ctx, _ := fs.AddConfig(context.Background())
+	ctx = accounting.WithStatsGroup(ctx, random.String(8)) // keep stats separate
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	f1, err := fs.NewFs(ctx, dir1)
+	require.NoError(t, err)
+	f2, err := fs.NewFs(ctx, dir2)
+	require.NoError(t, err)
+	opt := &bisync.Options{
+		Workdir:   t.TempDir(),
+		MaxDelete: 100,
+	}
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0666))
+	}
+	// waitFor waits until name has content in dir, or is missing if
+	// content is empty
+	waitFor := func(dir, name, content string) {
+		t.Helper()
+		var data []byte
+		for range 100 {
+			data, err = os.ReadFile(filepath.Join(dir, name))
+			if content == "" && os.IsNotExist(err) {
+				return
+			} else if err == nil && string(data) == content {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %q to be %q: got %q, %v", name, content, data, err)
+	}
+
+	write(dir1, "sub/a.txt", "a")
+	write(dir1, "sub/b.txt", "b")
+	write(dir2, "c.txt", "c")
+
+	// The first run is a resync
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		resyncOpt := *opt
+		resyncOpt.Resync = true
+		done <- bisync.Watch(watchCtx, []fs.Fs{f1, f2}, &resyncOpt, &watch.Options{
+			Delay:        fs.Duration(100 * time.Millisecond),
+			PollInterval: fs.Duration(time.Hour),
+		})
+	}()
+	waitFor(dir2, "sub/a.txt", "a")
+	waitFor(dir1, "c.txt", "c")
+
+	// Changes on either side are bisynced
+	write(dir1, "sub/a.txt", "a2")
+	waitFor(dir2, "sub/a.txt", "a2")
+	require.NoError(t, os.Remove(filepath.Join(dir2, "sub", "b.txt")))
+	waitFor(dir1, "sub/b.txt", "")
+	write(dir2, "new/d.txt", "d")
+	waitFor(dir1, "new/d.txt", "d")
+
+	cancel()
+	require.NoError(t, <-done)
+
+	// The listings of the unchanged files were kept, so a full run
+	// finds nothing to do
+	require.NoError(t, bisync.Bisync(ctx, f1, f2, opt))
+	for _, dir := range []string{dir1, dir2} {
+		for name, content := range map[string]string{"sub/a.txt": "a2", "c.txt": "c", "new/d.txt": "d"} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err)
+			assert.Equal(t, content, string(data))
+		}
+		_, err := os.Stat(filepath.Join(dir, "sub", "b.txt"))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/operations/operationsflags"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -27,6 +28,8 @@ var (
 	match             = ""
 	differ            = ""
 	errFile           = ""
+	report            = ""
+	reportFormat      = ""
 	checkFileHashType = ""
 )
 
@@ -47,6 +50,7 @@ func AddFlags(cmdFlags *pflag.FlagSet) {
 	flags.StringVarP(cmdFlags, &match, "match", "", match, "Report all matching files to this file", "")
 	flags.StringVarP(cmdFlags, &differ, "differ", "", differ, "Report all non-matching files to this file", "")
 	flags.StringVarP(cmdFlags, &errFile, "error", "", errFile, "Report all files with errors (hashing or reading) to this file", "")
+	operationsflags.AddReportFlags(cmdFlags, &report, &reportFormat)
 }
 
 // FlagsHelp describes the flags for the help
@@ -73,6 +77,13 @@ you what happened to it. These are reminiscent of diff files.
 - |* path| means path was present in source and destination but different.
 - |! path| means there was an error reading or hashing the source or dest.
 
+The |--report| flag writes a report of each file checked to the file
+name (or stdout if it is |-|) supplied. It is in JSON unless the file
+name ends in |.csv| or |--report-format csv| is used. Each entry has the
+path, the action (|match|, |differ|, |missing_on_src|,
+|missing_on_dst| or |error|), the sizes and hashes of the source and
+destination where known and the error text if any.
+
 The default number of parallel checks is 8. See the [--checkers](/docs/#checkers-int)
 option for more information.`, "|", "`")
 
@@ -122,7 +133,21 @@ func GetCheckOpt(fsrc, fdst fs.Fs) (opt *operations.CheckOpt, close func(), err
 		return nil, nil, err
 	}
 
+	var closeReport func() error
+	if report != "" {
+		ci := fs.GetConfig(context.Background())
+		opt.Report, closeReport, err = operationsflags.OpenReport(report, reportFormat, !download && !ci.SizeOnly)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	close = func() {
+		if closeReport != nil {
+			if err := closeReport(); err != nil {
+				fs.Errorf(nil, "Failed to write --report: %v", err)
+			}
+		}
 		for _, closer := range closers {
 			err := closer.Close()
 			if err != nil {
@@ -33,12 +33,15 @@ import (
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fs/rc/rcserver"
 	fssync "github.com/rclone/rclone/fs/sync"
+	"github.com/rclone/rclone/fs/tracing"
+	"github.com/rclone/rclone/fs/versionat"
 	"github.com/rclone/rclone/lib/atexit"
 	"github.com/rclone/rclone/lib/buildinfo"
 	"github.com/rclone/rclone/lib/exitcode"
 	"github.com/rclone/rclone/lib/terminal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Globals
@@ -104,6 +107,48 @@ func NewFsFile(remote string) (fs.Fs, string) {
 	return nil, ""
 }
 
+// versionAt returns a read only view of f as it was at the time
+// given by --version-at, or f if it isn't set.
+func versionAt(ctx context.Context, f fs.Fs) fs.Fs {
+	ci := fs.GetConfig(ctx)
+	if !ci.VersionAt.IsSet() {
+		return f
+	}
+	vf, err := versionat.New(ctx, f, time.Time(ci.VersionAt))
+	if err != nil {
+		err = fs.CountError(ctx, err)
+		fs.Fatalf(nil, "Failed to use --version-at: %v", err)
+	}
+	return vf
+}
+
+// NewFsSrcFile creates a src Fs from a name but may point to a file.
+//
+// This works the same as NewFsFile but shows the Fs as it was at the
+// time given by --version-at if set.
+func NewFsSrcFile(remote string) (fs.Fs, string) {
+	f, fileName := NewFsFile(remote)
+	return versionAt(context.Background(), f), fileName
+}
+
+// checkNoVersionAt returns an error if --version-at is set
+func checkNoVersionAt(ctx context.Context) error {
+	if fs.GetConfig(ctx).VersionAt.IsSet() {
+		return errors.New("can't use --version-at with commands which modify the remote")
+	}
+	return nil
+}
+
+// CheckNoVersionAt exits with an error if --version-at is set.
+//
+// This should be called by commands which modify the remotes they
+// are given.
+func CheckNoVersionAt(cmd *cobra.Command) {
+	if err := checkNoVersionAt(context.Background()); err != nil {
+		fs.Fatalf(nil, "Command %s: %v", cmd.Name(), err)
+	}
+}
+
 // newFsFileAddFilter creates an src Fs from a name
 //
 // This works the same as NewFsFile however it adds filters to the Fs
@@ -111,7 +156,7 @@ func NewFsFile(remote string) (fs.Fs, string) {
 func newFsFileAddFilter(remote string) (fs.Fs, string) {
 	ctx := context.Background()
 	fi := filter.GetConfig(ctx)
-	f, fileName := NewFsFile(remote)
+	f, fileName := NewFsSrcFile(remote)
 	if fileName != "" {
 		if !fi.InActive() {
 			err := fmt.Errorf("can't limit to single files when using filters: %v", remote)
@@ -170,7 +215,7 @@ func NewFsSrcDst(args []string) (fs.Fs, fs.Fs) {
 //
 // The source may be a file, in which case the source Fs and file name is returned
 func NewFsSrcFileDst(args []string) (fsrc fs.Fs, srcFileName string, fdst fs.Fs) {
-	fsrc, srcFileName = NewFsFile(args[0])
+	fsrc, srcFileName = NewFsSrcFile(args[0])
 	fdst = newFsDir(args[1])
 	return fsrc, srcFileName, fdst
 }
@@ -251,7 +296,11 @@ func Run(Retry bool, showStats bool, cmd *cobra.Command, f func() error) {
 		stopStats = StartStats()
 	}
 	SigInfoHandler()
+	spanCtx, endSpan := tracing.StartCommand(ctx, cmd.CommandPath())
 	for try := 1; try <= ci.Retries; try++ {
+		if try > 1 {
+			tracing.Event(spanCtx, "retry", attribute.Int("rclone.try", try))
+		}
 		cmdErr = f()
 		cmdErr = fs.CountError(ctx, cmdErr)
 		lastErr := accounting.GlobalStats().GetLastError()
@@ -292,6 +341,7 @@ func Run(Retry bool, showStats bool, cmd *cobra.Command, f func() error) {
 		}
 	}
 	stopStats()
+	endSpan(cmdErr)
 	if showStats && (accounting.GlobalStats().Errored() || *statsInterval > 0) {
 		accounting.GlobalStats().Log()
 	}
@@ -404,6 +454,12 @@ func initConfig() {
 	// Start accounting
 	accounting.Start(ctx)
 
+	// Start tracing if configured
+	err = tracing.Start(ctx, &tracing.Opt)
+	if err != nil {
+		fs.Fatalf(nil, "Failed to start tracing: %v", err)
+	}
+
 	// Configure console
 	if ci.NoConsole {
 		// Hide the console window
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/versionat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFsFileVersionAt(t *testing.T) {
+	ctx := context.Background()
+	ci := fs.GetConfig(ctx)
+	oldVersionAt := ci.VersionAt
+	defer func() { ci.VersionAt = oldVersionAt }()
+
+	ci.VersionAt = fs.Time(time.Now())
+	assert.Error(t, checkNoVersionAt(ctx))
+
+	// deletefile and bisync get their remotes from NewFsFile so
+	// mustn't be given a read only view of them. The local backend
+	// doesn't support --version-at so this would be fatal if it was
+	// wrapped.
+	f, fileName := NewFsFile(t.TempDir())
+	assert.Equal(t, "", fileName)
+	_, isVersionAt := f.(*versionat.Fs)
+	assert.False(t, isVersionAt)
+
+	ci.VersionAt = fs.Time{}
+	assert.NoError(t, checkNoVersionAt(ctx))
+}
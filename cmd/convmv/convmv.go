@@ -117,6 +117,7 @@ To minimize risks, users should:
 	},
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
+		cmd.CheckNoVersionAt(command)
 		fdst, srcFileName := cmd.NewFsFile(args[0])
 		cmd.Run(false, true, command, func() error {
 			if !transform.Transforming(context.Background()) {
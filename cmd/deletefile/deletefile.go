@@ -27,6 +27,7 @@ specified file exists, it will always be removed.`,
 	},
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
+		cmd.CheckNoVersionAt(command)
 		f, fileName := cmd.NewFsFile(args[0])
 		cmd.Run(true, false, command, func() error {
 			if fileName == "" {
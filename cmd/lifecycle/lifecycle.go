@@ -0,0 +1,110 @@
+// Package lifecycle provides the lifecycle command.
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	commandDefinition.AddCommand(applyCommand)
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "lifecycle",
+	Short: `Apply lifecycle and retention policies to a remote.`,
+	Long: `Apply lifecycle and retention policies to the objects in a remote.
+
+Some providers, such as S3, can expire objects or move them to a
+cheaper storage tier as they get older, but most backends can't. The
+lifecycle commands make rclone enforce these policies itself so they
+work on any backend. Run them regularly, for example from cron, to
+keep the remote in line with the policy.
+
+See ` + "`rclone lifecycle apply`" + ` for the format of the policy.
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+	},
+}
+
+var applyCommand = &cobra.Command{
+	Use:   "apply policy.yaml remote:path",
+	Short: `Apply the lifecycle policy in policy.yaml to remote:path.`,
+	Long: `Apply the lifecycle rules in the YAML file policy.yaml to the objects
+in remote:path.
+
+The policy is a list of rules. Each rule chooses objects with these
+optional conditions
+
+- ` + "`prefix`" + ` - the path of the object relative to remote:path
+  starts with this, e.g. ` + "`logs/`" + `
+- ` + "`filter`" + ` - the object matches this expression in the same
+  format as [--filter-expr](/filtering/#filter-expr)
+- ` + "`min_age`" + ` - the object was modified longer ago than this,
+  e.g. ` + "`30d`" + `
+
+and has exactly one of these actions
+
+- ` + "`set_tier: TIER`" + ` - move the objects to the storage tier or
+  class TIER if they aren't in it already. The backend must support
+  ` + "`rclone settier`" + `.
+- ` + "`delete: true`" + ` - delete the objects.
+- ` + "`keep_versions: N`" + ` - delete all but the newest N versions
+  of each object. If ` + "`min_age`" + ` is set too then old versions
+  are only deleted once they are older than it, measured from the
+  time in their version suffix rather than their modification time.
+
+For example
+
+` + "```yaml" + `
+rules:
+  - name: archive-logs
+    prefix: logs/
+    min_age: 30d
+    set_tier: GLACIER
+  - name: expire
+    min_age: 365d
+    delete: true
+  - name: versions
+    keep_versions: 10
+` + "```" + `
+
+The rules are applied in order and objects deleted by a rule aren't
+seen by the rules after it.
+
+Versions are recognised by the version suffix in their names, e.g.
+` + "`file-v2024-01-02-150405-000.txt`" + `, so to use
+` + "`keep_versions`" + ` the remote must be set up to list old versions
+this way, for example with ` + "`--s3-versions`" + ` or
+` + "`--b2-versions`" + `. The version without a suffix is the current
+version and is always counted as the newest.
+
+The global filter flags apply too, so they can restrict the objects
+the policy sees. Use ` + "`--dry-run`" + ` or ` + "`--interactive`" + `
+to check what a policy will do before running it.
+
+` + "```sh" + `
+rclone lifecycle apply --dry-run policy.yaml s3:bucket
+rclone lifecycle apply --s3-versions policy.yaml s3:bucket
+` + "```" + `
+`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.72",
+		"groups":            "Important,Filter,Listing",
+	},
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		f := cmd.NewFsDir(args[1:])
+		cmd.Run(true, false, command, func() error {
+			policy, err := Load(args[0])
+			if err != nil {
+				return err
+			}
+			return Apply(context.Background(), f, policy)
+		})
+	},
+}
@@ -0,0 +1,210 @@
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/lib/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	policy, err := Parse([]byte(`
+rules:
+  - name: archive
+    prefix: /logs/
+    filter: 'name =~ "*.log"'
+    min_age: 30d
+    set_tier: GLACIER
+  - delete: true
+    min_age: 1y
+  - keep_versions: 3
+`))
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 3)
+	assert.Equal(t, "archive", policy.Rules[0].Name)
+	assert.Equal(t, "logs/", policy.Rules[0].Prefix)
+	assert.Equal(t, 30*24*time.Hour, policy.Rules[0].minAge)
+	assert.NotNil(t, policy.Rules[0].fi)
+	assert.Equal(t, "rule 2", policy.Rules[1].Name)
+	assert.Equal(t, 3, policy.Rules[2].KeepVersions)
+
+	for _, test := range []struct {
+		in  string
+		err string
+	}{
+		{"", "failed to parse lifecycle policy"},
+		{"rules: []", "no rules"},
+		{"rules:\n  - potato: true", "field potato not found"},
+		{"rules:\n  - prefix: logs/", "need exactly one of"},
+		{"rules:\n  - delete: true\n    set_tier: Cool", "need exactly one of"},
+		{"rules:\n  - keep_versions: -1", "can't be negative"},
+		{"rules:\n  - delete: true\n    min_age: potato", "bad min_age"},
+		{"rules:\n  - delete: true\n    filter: 'size >'", "--filter-expr"},
+	} {
+		_, err := Parse([]byte(test.in))
+		assert.ErrorContains(t, err, test.err, test.in)
+	}
+}
+
+// writeFile makes a file in dir with the modification time age ago
+func writeFile(t *testing.T, dir, name string, age time.Duration) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+	require.NoError(t, os.WriteFile(path, []byte(name), 0666))
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// listFiles returns the sorted paths of the files in dir
+func listFiles(t *testing.T, dir string) (files []string) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if !info.IsDir() {
+			rel, err := filepath.Rel(dir, path)
+			require.NoError(t, err)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(files)
+	return files
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	v := func(name string, daysAgo int) string {
+		return version.Add(name, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(daysAgo)*day))
+	}
+	writeFile(t, dir, "logs/new.log", day)
+	writeFile(t, dir, "logs/old.log", 40*day)
+	writeFile(t, dir, "logs/old.txt", 40*day)
+	writeFile(t, dir, "ancient.txt", 400*day)
+	writeFile(t, dir, "doc.txt", 0)
+	writeFile(t, dir, v("doc.txt", 1), 2*day)
+	writeFile(t, dir, v("doc.txt", 2), 3*day)
+	writeFile(t, dir, v("doc.txt", 3), 4*day)
+	writeFile(t, dir, v("other.txt", 1), 2*day)
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+
+	policy, err := Parse([]byte(`
+rules:
+  - name: old logs
+    prefix: logs/
+    filter: 'name =~ "*.log"'
+    min_age: 30d
+    delete: true
+  - name: expire
+    min_age: 365d
+    delete: true
+  - name: versions
+    keep_versions: 2
+`))
+	require.NoError(t, err)
+	require.NoError(t, Apply(ctx, f, policy))
+	assert.Equal(t, []string{
+		v("doc.txt", 1),
+		"doc.txt",
+		"logs/new.log",
+		"logs/old.txt",
+		v("other.txt", 1),
+	}, listFiles(t, dir))
+
+	// The local backend can't set tiers
+	policy, err = Parse([]byte("rules:\n  - set_tier: GLACIER"))
+	require.NoError(t, err)
+	assert.ErrorContains(t, Apply(ctx, f, policy), "does not support setting the tier")
+}
+
+func TestApplyDryRun(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.DryRun = true
+	dir := t.TempDir()
+	writeFile(t, dir, "old.txt", 400*24*time.Hour)
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+	policy, err := Parse([]byte("rules:\n  - delete: true\n    min_age: 1y"))
+	require.NoError(t, err)
+	require.NoError(t, Apply(ctx, f, policy))
+	assert.Equal(t, []string{"old.txt"}, listFiles(t, dir))
+}
+
+func TestApplyVersionAge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	// Both old versions were written long ago but one was only
+	// replaced recently
+	recent := version.Add("doc.txt", now.Add(-10*day))
+	old := version.Add("doc.txt", now.Add(-60*day))
+	writeFile(t, dir, "doc.txt", 0)
+	writeFile(t, dir, recent, 100*day)
+	writeFile(t, dir, old, 100*day)
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+
+	policy, err := Parse([]byte("rules:\n  - keep_versions: 1\n    min_age: 30d"))
+	require.NoError(t, err)
+	require.NoError(t, Apply(ctx, f, policy))
+	assert.Equal(t, []string{recent, "doc.txt"}, listFiles(t, dir))
+}
+
+func TestApplyDryRunLaterRules(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.DryRun = true
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	ancient := version.Add("doc.txt", now.Add(-400*day))
+	recent := version.Add("doc.txt", now.Add(-day))
+	writeFile(t, dir, "doc.txt", 0)
+	writeFile(t, dir, ancient, 400*day)
+	writeFile(t, dir, recent, day)
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+
+	policy, err := Parse([]byte("rules:\n  - delete: true\n    min_age: 1y\n  - keep_versions: 1"))
+	require.NoError(t, err)
+	a := newApplier(f)
+	require.NoError(t, a.apply(ctx, policy))
+
+	// Nothing is deleted but both old versions are reported, the
+	// second rule not seeing the one the first would have deleted
+	assert.Equal(t, []string{ancient, recent, "doc.txt"}, listFiles(t, dir))
+	assert.Len(t, a.deleted, 0)
+	var skipped []string
+	for o := range a.skipped {
+		skipped = append(skipped, o.Remote())
+	}
+	sort.Strings(skipped)
+	assert.Equal(t, []string{ancient, recent}, skipped)
+}
+
+func TestApplyFiltered(t *testing.T) {
+	fi, err := filter.NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, fi.AddRule("- keep/**"))
+	ctx := filter.ReplaceConfig(context.Background(), fi)
+	dir := t.TempDir()
+	writeFile(t, dir, "keep/old.txt", 400*24*time.Hour)
+	writeFile(t, dir, "old.txt", 400*24*time.Hour)
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+	policy, err := Parse([]byte("rules:\n  - delete: true\n    min_age: 1y"))
+	require.NoError(t, err)
+	require.NoError(t, Apply(ctx, f, policy))
+	assert.Equal(t, []string{"keep/old.txt"}, listFiles(t, dir))
+}
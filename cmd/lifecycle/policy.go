@@ -0,0 +1,328 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/rclone/rclone/lib/version"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a single lifecycle rule
+//
+// It selects objects with Prefix, Filter and MinAge and applies
+// exactly one of the actions SetTier, Delete or KeepVersions to them.
+type Rule struct {
+	Name         string `yaml:"name"`          // name of the rule for logging
+	Prefix       string `yaml:"prefix"`        // only objects whose path starts with this
+	Filter       string `yaml:"filter"`        // only objects matching this --filter-expr
+	MinAge       string `yaml:"min_age"`       // only objects older than this
+	SetTier      string `yaml:"set_tier"`      // move the objects to this tier
+	Delete       bool   `yaml:"delete"`        // delete the objects
+	KeepVersions int    `yaml:"keep_versions"` // delete all but this many of the newest versions
+
+	minAge time.Duration
+	fi     *filter.Filter
+}
+
+// Policy is a list of lifecycle rules applied in order
+type Policy struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Load reads and checks the policy in the YAML file at path
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle policy: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and checks a policy in YAML
+func Parse(data []byte) (*Policy, error) {
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lifecycle policy: %w", err)
+	}
+	if len(policy.Rules) == 0 {
+		return nil, errors.New("lifecycle policy has no rules")
+	}
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		err = rule.init()
+		if err != nil {
+			return nil, fmt.Errorf("lifecycle policy %q: %w", rule.Name, err)
+		}
+	}
+	return &policy, nil
+}
+
+// init checks the rule and parses its conditions
+func (r *Rule) init() (err error) {
+	actions := 0
+	if r.SetTier != "" {
+		actions++
+	}
+	if r.Delete {
+		actions++
+	}
+	if r.KeepVersions < 0 {
+		return errors.New("keep_versions can't be negative")
+	} else if r.KeepVersions > 0 {
+		actions++
+	}
+	if actions != 1 {
+		return errors.New("need exactly one of set_tier, delete or keep_versions")
+	}
+	if r.MinAge != "" {
+		r.minAge, err = fs.ParseDuration(r.MinAge)
+		if err != nil {
+			return fmt.Errorf("bad min_age: %w", err)
+		}
+	}
+	r.Prefix = strings.TrimLeft(r.Prefix, "/")
+	if r.Filter != "" {
+		r.fi, err = filter.NewFilter(&filter.Options{
+			FilterExpr: r.Filter,
+			MinAge:     fs.DurationOff,
+			MaxAge:     fs.DurationOff,
+			MinSize:    fs.SizeSuffix(-1),
+			MaxSize:    fs.SizeSuffix(-1),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String describes the rule
+func (r *Rule) String() string {
+	return r.Name
+}
+
+// match returns true if o is selected by the rule ignoring its age
+func (r *Rule) match(ctx context.Context, o fs.Object) bool {
+	if !strings.HasPrefix(o.Remote(), r.Prefix) {
+		return false
+	}
+	return r.fi == nil || r.fi.IncludeObject(ctx, o)
+}
+
+// old returns true if o is old enough for the rule
+//
+// The age of an old version kept by keep_versions is measured from
+// its version time, when it stopped being the current version,
+// rather than its modification time.
+func (r *Rule) old(ctx context.Context, o fs.Object, now time.Time) bool {
+	if r.minAge <= 0 {
+		return true
+	}
+	t := time.Time{}
+	if r.KeepVersions > 0 {
+		t, _ = version.Remove(o.Remote())
+	}
+	if t.IsZero() {
+		t = o.ModTime(ctx)
+	}
+	return t.Before(now.Add(-r.minAge))
+}
+
+// applier applies a policy to an Fs
+type applier struct {
+	f       fs.Fs
+	now     time.Time
+	mu      sync.Mutex
+	deleted map[fs.Object]struct{} // objects deleted by earlier rules
+	skipped map[fs.Object]struct{} // objects earlier rules would have deleted but for --dry-run
+	errs    int                    // number of errors
+	lastErr error
+}
+
+// Apply lists all the objects in f and applies the rules in policy to
+// them in order. Objects deleted by a rule aren't seen by the rules
+// after it, nor are those it would have deleted with --dry-run.
+//
+// The global filters are applied to the listing so can be used to
+// restrict the objects the policy sees.
+func Apply(ctx context.Context, f fs.Fs, policy *Policy) error {
+	return newApplier(f).apply(ctx, policy)
+}
+
+// newApplier makes an applier for f
+func newApplier(f fs.Fs) *applier {
+	return &applier{
+		f:       f,
+		now:     time.Now(),
+		deleted: map[fs.Object]struct{}{},
+		skipped: map[fs.Object]struct{}{},
+	}
+}
+
+// apply the policy to the objects in a.f
+func (a *applier) apply(ctx context.Context, policy *Policy) error {
+	f := a.f
+	var objects []fs.Object
+	err := walk.ListR(ctx, f, "", false, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		entries.ForObject(func(o fs.Object) {
+			objects = append(objects, o)
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+	fs.Debugf(f, "Found %d objects", len(objects))
+	for _, rule := range policy.Rules {
+		err = a.applyRule(ctx, rule, objects)
+		if err != nil {
+			return fmt.Errorf("lifecycle rule %q: %w", rule, err)
+		}
+	}
+	if a.errs > 0 {
+		return fmt.Errorf("failed to apply lifecycle policy with %d errors: last error: %w", a.errs, a.lastErr)
+	}
+	return nil
+}
+
+// applyRule applies a single rule to the objects
+func (a *applier) applyRule(ctx context.Context, rule *Rule, objects []fs.Object) error {
+	if rule.SetTier != "" && !a.f.Features().SetTier {
+		return fmt.Errorf("remote %s does not support setting the tier", fs.ConfigString(a.f))
+	}
+	var selected []fs.Object
+	for _, o := range objects {
+		if _, found := a.deleted[o]; found {
+			continue
+		}
+		if _, found := a.skipped[o]; found {
+			continue
+		}
+		if rule.match(ctx, o) {
+			selected = append(selected, o)
+		}
+	}
+	if rule.KeepVersions > 0 {
+		selected = oldVersions(selected, rule.KeepVersions)
+	}
+	var actioned int
+	ci := fs.GetConfig(ctx)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(ci.Checkers)
+	for _, o := range selected {
+		if !rule.old(ctx, o, a.now) {
+			continue
+		}
+		actioned++
+		g.Go(func() error {
+			var err error
+			if rule.SetTier != "" {
+				err = a.setTier(gCtx, o, rule.SetTier)
+			} else if ci.DryRun {
+				// Report what would be deleted without
+				// counting it as deleted
+				_ = operations.SkipDestructive(gCtx, o, "delete")
+				a.mu.Lock()
+				a.skipped[o] = struct{}{}
+				a.mu.Unlock()
+			} else {
+				err = operations.DeleteFile(gCtx, o)
+				if err == nil {
+					a.mu.Lock()
+					a.deleted[o] = struct{}{}
+					a.mu.Unlock()
+				}
+			}
+			if err != nil {
+				a.mu.Lock()
+				a.errs++
+				a.lastErr = err
+				a.mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	action := "deleted"
+	if rule.SetTier != "" {
+		action = "set tier " + rule.SetTier + " on"
+	}
+	if ci.DryRun {
+		action = "would have " + action
+	}
+	fs.Logf(a.f, "Lifecycle rule %q: %s %d of %d matching objects", rule, action, actioned, len(selected))
+	return nil
+}
+
+// setTier sets the tier of o to tier if it isn't already
+func (a *applier) setTier(ctx context.Context, o fs.Object, tier string) error {
+	if do, ok := o.(fs.GetTierer); ok && strings.EqualFold(do.GetTier(), tier) {
+		fs.Debugf(o, "Already in tier %s", tier)
+		return nil
+	}
+	if operations.SkipDestructive(ctx, o, "set tier") {
+		return nil
+	}
+	err := operations.SetTierFile(ctx, o, tier)
+	if err != nil {
+		return fs.CountError(ctx, err)
+	}
+	fs.Infof(o, "Set tier to %s", tier)
+	return nil
+}
+
+// objectVersion is an object with the time of its version
+type objectVersion struct {
+	o       fs.Object
+	version time.Time // zero for the current version
+}
+
+// oldVersions returns the objects which aren't in the newest keep
+// versions of each file.
+//
+// Old versions are recognised by the version suffix added by
+// backends when listing versions, e.g. file-v2024-01-02-150405-000.txt
+// The current version, without a suffix, is always the newest.
+func oldVersions(objects []fs.Object, keep int) (old []fs.Object) {
+	files := map[string][]objectVersion{}
+	for _, o := range objects {
+		t, remote := version.Remove(o.Remote())
+		files[remote] = append(files[remote], objectVersion{o: o, version: t})
+	}
+	for _, versions := range files {
+		slices.SortFunc(versions, func(a, b objectVersion) int {
+			switch {
+			case a.version.IsZero() && !b.version.IsZero():
+				return -1
+			case !a.version.IsZero() && b.version.IsZero():
+				return 1
+			}
+			return b.version.Compare(a.version)
+		})
+		for i := keep; i < len(versions); i++ {
+			old = append(old, versions[i].o)
+		}
+	}
+	return old
+}
@@ -54,6 +54,7 @@ expiry, no password protection, accessible without account.`,
 	},
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
+		cmd.CheckNoVersionAt(command)
 		fsrc, remote := cmd.NewFsFile(args[0])
 		cmd.Run(false, false, command, func() error {
 			link, err := operations.PublicLink(context.Background(), fsrc, remote, expire, unlink)
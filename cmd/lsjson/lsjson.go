@@ -137,7 +137,7 @@ can be processed line by line as each item is written on individual lines
 		var fsrc fs.Fs
 		var remote string
 		if statOnly {
-			fsrc, remote = cmd.NewFsFile(args[0])
+			fsrc, remote = cmd.NewFsSrcFile(args[0])
 		} else {
 			fsrc = cmd.NewFsSrc(args)
 		}
@@ -55,9 +55,14 @@ func mount(VFS *vfs.VFS, mountpoint string, opt *mountlib.Options) (asyncerrors
 	// Options
 	options := []string{
 		"-o", fmt.Sprintf("port=%s", port),
-		"-o", fmt.Sprintf("mountport=%s", port),
 		"-o", "tcp",
 	}
+	if nfs.Opt.Version.String() == "4.1" {
+		// NFSv4 doesn't use the mount protocol
+		options = append(options, "-o", "vers=4.1")
+	} else {
+		options = append(options, "-o", fmt.Sprintf("mountport=%s", port))
+	}
 	for _, option := range opt.ExtraOptions {
 		options = append(options, "-o", option)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fs/rc/rcflags"
 	"github.com/rclone/rclone/fs/rc/rcserver"
+	"github.com/rclone/rclone/fs/rc/schedule"
 	libhttp "github.com/rclone/rclone/lib/http"
 	"github.com/rclone/rclone/lib/systemd"
 	"github.com/spf13/cobra"
@@ -30,6 +31,11 @@ If you pass in a path to a directory, rclone will serve that directory
 for GET requests on the URL passed in.  It will also open the URL in
 the browser when rclone is run.
 
+Use ` + "`--rc-schedule`" + ` to pass a file of rc commands to run on a
+schedule, for example a nightly ` + "`sync/sync`" + `. See the
+[scheduling section of the rc documentation](/rc/#scheduling) for the
+file format.
+
 See the [rc documentation](/rc/) for more info on the rc flags.
 
 ` + strings.TrimSpace(libhttp.Help(rcflags.FlagPrefix)+libhttp.TemplateHelp(rcflags.FlagPrefix)+libhttp.AuthHelp(rcflags.FlagPrefix)),
@@ -57,6 +63,15 @@ See the [rc documentation](/rc/) for more info on the rc flags.
 			fs.Fatal(nil, "rc server not configured")
 		}
 
+		// Start the scheduler if required
+		if rc.Opt.Schedule != "" {
+			sched, err := schedule.Start(context.Background(), rc.Opt.Schedule)
+			if err != nil {
+				fs.Fatalf(nil, "Failed to start schedule: %v", err)
+			}
+			defer sched.Stop()
+		}
+
 		// Notify stopping on exit
 		defer systemd.Notify()()
 
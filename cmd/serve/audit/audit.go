@@ -0,0 +1,325 @@
+// Package audit implements a structured log of the file operations
+// done through the serve commands.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/log"
+	"github.com/rclone/rclone/vfs"
+)
+
+// Help contains text describing the audit log to add to the command
+// help.
+var Help = `### Audit log
+
+Use ` + "`--audit-log /path/to/audit.log`" + ` to write a line of JSON to the
+file for every upload, download, delete, mkdir, rename and copy done
+by the clients of the server. Use ` + "`--audit-log-syslog`" + ` to send the
+records to syslog instead (using ` + "`--syslog-facility`" + `). Both may be
+used at once. The audit log is independent of the normal rclone log and
+its ` + "`--log-level`" + `.
+
+Each record looks like this (wrapped for clarity):
+
+` + "```json" + `
+{"time":"2025-01-01T12:00:00.123456Z","protocol":"sftp","user":"alice",
+ "remote_addr":"192.0.2.1:51234","op":"upload","path":"/dir/file.txt",
+ "bytes":1048576,"result":"ok"}
+` + "```" + `
+
+- ` + "`op`" + ` is one of ` + "`upload`, `download`, `delete`, `mkdir`, `rename` or `copy`" + `
+- ` + "`new_path`" + ` is set to the destination of renames and copies
+- ` + "`bytes`" + ` is the number of bytes transferred for uploads and downloads
+- ` + "`result`" + ` is ` + "`ok` or `error`" + ` in which case ` + "`error`" + ` has the reason
+
+Downloads which read no data (for example opening a directory) are not
+logged.
+
+`
+
+// Options is options for the audit log
+type Options struct {
+	AuditLog       string // write the audit log to this file if set
+	AuditLogSyslog bool   // write the audit log to syslog if set
+}
+
+// Opt is the options set by the command line flags
+var Opt Options
+
+// Operations which are logged
+const (
+	OpUpload   = "upload"
+	OpDownload = "download"
+	OpDelete   = "delete"
+	OpMkdir    = "mkdir"
+	OpRename   = "rename"
+	OpCopy     = "copy"
+)
+
+// Results of the operations
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// Record is a single entry in the audit log
+type Record struct {
+	Time       time.Time `json:"time"`
+	Protocol   string    `json:"protocol"`
+	User       string    `json:"user,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Op         string    `json:"op"`
+	Path       string    `json:"path"`
+	NewPath    string    `json:"new_path,omitempty"`
+	Bytes      int64     `json:"bytes"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Logger writes audit records for a server
+//
+// A nil *Logger is valid and logs nothing.
+type Logger struct {
+	protocol string
+	mu       sync.Mutex
+	w        io.Writer
+	closers  []io.Closer
+}
+
+// New makes a Logger for the protocol from opt
+//
+// It returns a nil Logger if no audit log is configured.
+func New(protocol string, opt *Options) (*Logger, error) {
+	if opt.AuditLog == "" && !opt.AuditLogSyslog {
+		return nil, nil
+	}
+	l := &Logger{protocol: protocol}
+	var writers []io.Writer
+	if opt.AuditLog != "" {
+		f, err := os.OpenFile(opt.AuditLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		writers = append(writers, f)
+		l.closers = append(l.closers, f)
+	}
+	if opt.AuditLogSyslog {
+		w, err := log.NewSyslogWriter("rclone-audit")
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("audit log: %w", err)
+		}
+		writers = append(writers, w)
+		if c, ok := w.(io.Closer); ok {
+			l.closers = append(l.closers, c)
+		}
+	}
+	l.w = io.MultiWriter(writers...)
+	return l, nil
+}
+
+// Close the audit log
+func (l *Logger) Close() (err error) {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range l.closers {
+		err = errors.Join(err, c.Close())
+	}
+	l.closers = nil
+	return err
+}
+
+// conn identifies the client doing the operations
+type conn struct {
+	user       string
+	remoteAddr string
+}
+
+type connKey struct{}
+
+// WithConn returns a copy of ctx recording the user and remote address
+// of the client for the audit records
+func WithConn(ctx context.Context, user, remoteAddr string) context.Context {
+	return context.WithValue(ctx, connKey{}, conn{user: user, remoteAddr: remoteAddr})
+}
+
+// WithUser returns a copy of ctx recording the user of the client for
+// the audit records, keeping the remote address set by WithConn.
+//
+// Use this when the user is only known once they have been
+// authenticated.
+func WithUser(ctx context.Context, user string) context.Context {
+	c, _ := ctx.Value(connKey{}).(conn)
+	c.user = user
+	return context.WithValue(ctx, connKey{}, c)
+}
+
+// cleanPath makes p into an absolute slash separated path
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// log writes a record of op on oldPath (and newPath for renames and
+// copies) which transferred bytes and returned err
+func (l *Logger) log(ctx context.Context, op, oldPath, newPath string, bytes int64, err error) {
+	if l == nil {
+		return
+	}
+	r := Record{
+		Time:     time.Now().UTC(),
+		Protocol: l.protocol,
+		Op:       op,
+		Path:     cleanPath(oldPath),
+		Bytes:    bytes,
+		Result:   ResultOK,
+	}
+	if newPath != "" {
+		r.NewPath = cleanPath(newPath)
+	}
+	if c, ok := ctx.Value(connKey{}).(conn); ok {
+		r.User = c.user
+		r.RemoteAddr = c.remoteAddr
+	}
+	if err != nil {
+		r.Result = ResultError
+		r.Error = err.Error()
+	}
+	data, jsonErr := json.Marshal(r)
+	if jsonErr != nil {
+		fs.Errorf(nil, "Failed to encode audit record: %v", jsonErr)
+		return
+	}
+	data = append(data, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, writeErr := l.w.Write(data); writeErr != nil {
+		fs.Errorf(nil, "Failed to write audit log: %v", writeErr)
+	}
+}
+
+// wrapHandle wraps h, opened as path with flags, so the bytes read or
+// written are logged when it is closed.
+func (l *Logger) wrapHandle(ctx context.Context, path string, flags int, h vfs.Handle) vfs.Handle {
+	if l == nil {
+		return h
+	}
+	return &handle{
+		Handle: h,
+		l:      l,
+		ctx:    ctx,
+		path:   path,
+		write:  flags&(os.O_WRONLY|os.O_RDWR) != 0,
+	}
+}
+
+// handle counts the bytes going through a vfs.Handle
+type handle struct {
+	vfs.Handle
+	l       *Logger
+	ctx     context.Context
+	path    string
+	write   bool // opened for writing
+	read    atomic.Int64
+	written atomic.Int64
+	closed  atomic.Bool
+}
+
+// Read from the handle
+func (h *handle) Read(b []byte) (n int, err error) {
+	n, err = h.Handle.Read(b)
+	h.read.Add(int64(n))
+	return n, err
+}
+
+// ReadAt reads from the handle at off
+func (h *handle) ReadAt(b []byte, off int64) (n int, err error) {
+	n, err = h.Handle.ReadAt(b, off)
+	h.read.Add(int64(n))
+	return n, err
+}
+
+// Write to the handle
+func (h *handle) Write(b []byte) (n int, err error) {
+	n, err = h.Handle.Write(b)
+	h.written.Add(int64(n))
+	return n, err
+}
+
+// WriteAt writes to the handle at off
+func (h *handle) WriteAt(b []byte, off int64) (n int, err error) {
+	n, err = h.Handle.WriteAt(b, off)
+	h.written.Add(int64(n))
+	return n, err
+}
+
+// WriteString writes s to the handle
+func (h *handle) WriteString(s string) (n int, err error) {
+	n, err = h.Handle.WriteString(s)
+	h.written.Add(int64(n))
+	return n, err
+}
+
+// Close the handle logging the transfer
+func (h *handle) Close() error {
+	err := h.Handle.Close()
+	if h.closed.Swap(true) {
+		return err
+	}
+	if h.write {
+		h.l.log(h.ctx, OpUpload, h.path, "", h.written.Load(), err)
+	}
+	if read := h.read.Load(); read > 0 {
+		h.l.log(h.ctx, OpDownload, h.path, "", read, err)
+	}
+	return err
+}
+
+// wrapReader wraps rc, the contents of path, so the bytes read are
+// logged as a download when it is closed.
+func (l *Logger) wrapReader(ctx context.Context, path string, rc io.ReadCloser) io.ReadCloser {
+	if l == nil {
+		return rc
+	}
+	return &reader{ReadCloser: rc, l: l, ctx: ctx, path: path}
+}
+
+// reader counts the bytes read through an io.ReadCloser
+type reader struct {
+	io.ReadCloser
+	l      *Logger
+	ctx    context.Context
+	path   string
+	read   int64
+	closed bool
+}
+
+// Read from the reader
+func (r *reader) Read(b []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(b)
+	r.read += int64(n)
+	return n, err
+}
+
+// Close the reader logging the transfer
+func (r *reader) Close() error {
+	err := r.ReadCloser.Close()
+	if !r.closed {
+		r.closed = true
+		r.l.log(r.ctx, OpDownload, r.path, "", r.read, err)
+	}
+	return err
+}
@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readRecords reads the records from the audit log file
+func readRecords(t *testing.T, fileName string) (records []Record) {
+	in, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, in.Close())
+	}()
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		var r Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		assert.False(t, r.Time.IsZero())
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+// clearTimes zeroes the times in the records for comparison
+func clearTimes(records []Record) []Record {
+	for i := range records {
+		records[i].Time = time.Time{}
+	}
+	return records
+}
+
+// newVFS makes a VFS on a temporary local directory
+func newVFS(t *testing.T) *vfs.VFS {
+	f, err := fs.NewFs(context.Background(), filepath.Join(t.TempDir(), "root"))
+	require.NoError(t, err)
+	VFS := vfs.New(f, &vfscommon.Opt)
+	t.Cleanup(VFS.Shutdown)
+	return VFS
+}
+
+func TestNewDisabled(t *testing.T) {
+	l, err := New("test", &Options{})
+	require.NoError(t, err)
+	assert.Nil(t, l)
+
+	// A nil Logger does nothing
+	v := l.VFS(context.Background(), newVFS(t))
+	require.NoError(t, v.Mkdir("dir", 0777))
+	require.NoError(t, v.Do(OpCopy, "a", "b", func() (int64, error) { return 0, nil }))
+	rc := io.NopCloser(strings.NewReader("hello"))
+	assert.Equal(t, rc, v.Reader("file", rc))
+	assert.NoError(t, l.Close())
+}
+
+func TestLog(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	l, err := New("test", &Options{AuditLog: fileName})
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	VFS := newVFS(t)
+
+	ctx := WithConn(context.Background(), "alice", "192.0.2.1:1234")
+	v := l.VFS(ctx, VFS)
+	require.NoError(t, v.Mkdir("dir", 0777))
+	err = l.VFS(context.Background(), VFS).Do(OpDelete, "/dir/../file.txt", "", func() (int64, error) {
+		return 0, errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	require.NoError(t, v.Do(OpCopy, "a", "dir/b", func() (int64, error) { return 0, nil }))
+	rc := v.Reader("dir/b", io.NopCloser(strings.NewReader("hello")))
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, rc.Close())
+	require.NoError(t, rc.Close()) // only logged once
+	require.NoError(t, l.Close())
+
+	assert.Equal(t, []Record{{
+		Protocol:   "test",
+		User:       "alice",
+		RemoteAddr: "192.0.2.1:1234",
+		Op:         OpMkdir,
+		Path:       "/dir",
+		Result:     ResultOK,
+	}, {
+		Protocol: "test",
+		Op:       OpDelete,
+		Path:     "/file.txt",
+		Result:   ResultError,
+		Error:    "boom",
+	}, {
+		Protocol:   "test",
+		User:       "alice",
+		RemoteAddr: "192.0.2.1:1234",
+		Op:         OpCopy,
+		Path:       "/a",
+		NewPath:    "/dir/b",
+		Result:     ResultOK,
+	}, {
+		Protocol:   "test",
+		User:       "alice",
+		RemoteAddr: "192.0.2.1:1234",
+		Op:         OpDownload,
+		Path:       "/dir/b",
+		Bytes:      5,
+		Result:     ResultOK,
+	}}, clearTimes(readRecords(t, fileName)))
+}
+
+func TestWithUser(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	l, err := New("test", &Options{AuditLog: fileName})
+	require.NoError(t, err)
+	VFS := newVFS(t)
+
+	ctx := WithConn(context.Background(), "", "192.0.2.1:1234")
+	require.NoError(t, l.VFS(ctx, VFS).Mkdir("dir", 0777))
+	require.NoError(t, l.VFS(WithUser(ctx, "bob"), VFS).Mkdir("dir2", 0777))
+	require.NoError(t, l.Close())
+
+	records := readRecords(t, fileName)
+	require.Len(t, records, 2)
+	assert.Equal(t, "", records[0].User)
+	assert.Equal(t, "bob", records[1].User)
+	assert.Equal(t, "192.0.2.1:1234", records[1].RemoteAddr)
+}
+
+func TestVFS(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "audit.log")
+	l, err := New("test", &Options{AuditLog: fileName})
+	require.NoError(t, err)
+	v := l.VFS(WithConn(context.Background(), "bob", "192.0.2.2:22"), newVFS(t))
+
+	// Upload
+	h, err := v.OpenFile("file.txt", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	require.NoError(t, err)
+	_, err = h.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = h.WriteString("world")
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	// Download
+	h, err = v.Open("file.txt")
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = h.ReadAt(buf, 6)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(buf))
+	require.NoError(t, h.Close())
+
+	// Opening without reading isn't logged
+	h, err = v.Open("file.txt")
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	// Failing to open is
+	_, err = v.Open("missing.txt")
+	require.Error(t, err)
+
+	// Changes
+	require.NoError(t, v.Mkdir("dir", 0777))
+	require.NoError(t, v.Rename("file.txt", "dir/file.txt"))
+	require.NoError(t, v.RemoveAll("dir"))
+
+	// Operations on the embedded VFS aren't logged
+	require.NoError(t, v.VFS.Mkdir("quiet", 0777))
+	require.NoError(t, v.VFS.Remove("quiet"))
+
+	require.NoError(t, l.Close())
+
+	records := readRecords(t, fileName)
+	var got []string
+	for _, r := range records {
+		assert.Equal(t, "bob", r.User)
+		assert.Equal(t, "192.0.2.2:22", r.RemoteAddr)
+		got = append(got, strings.TrimSpace(fmt.Sprintf("%s %s %d %s %s", r.Op, r.Path, r.Bytes, r.Result, r.NewPath)))
+	}
+	assert.Equal(t, []string{
+		"upload /file.txt 11 ok",
+		"download /file.txt 5 ok",
+		"download /missing.txt 0 error",
+		"mkdir /dir 0 ok",
+		"rename /file.txt 0 ok /dir/file.txt",
+		"delete /dir 0 ok",
+	}, got)
+}
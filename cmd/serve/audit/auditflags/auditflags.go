@@ -0,0 +1,14 @@
+// Package auditflags implements command line flags to set up the audit log
+package auditflags
+
+import (
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/spf13/pflag"
+)
+
+// AddFlags adds the audit log flags to the command
+func AddFlags(flagSet *pflag.FlagSet) {
+	flags.StringVarP(flagSet, &audit.Opt.AuditLog, "audit-log", "", audit.Opt.AuditLog, "Write a JSON audit log of file operations to this file", "Logging")
+	flags.BoolVarP(flagSet, &audit.Opt.AuditLogSyslog, "audit-log-syslog", "", audit.Opt.AuditLogSyslog, "Write a JSON audit log of file operations to syslog", "Logging")
+}
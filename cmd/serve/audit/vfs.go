@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/rclone/rclone/vfs"
+)
+
+// VFS is the view of a vfs.VFS for one client of a server which
+// writes an audit record for every change made through it and every
+// file read or written through it.
+//
+// The serve commands do the file operations their clients ask for
+// through this so they are all logged in the same way. Use the
+// embedded vfs.VFS directly for operations which shouldn't be logged.
+type VFS struct {
+	*vfs.VFS
+	l   *Logger
+	ctx context.Context
+}
+
+// VFS returns the view of v for the client identified by ctx (see
+// WithConn and WithUser).
+//
+// If l is nil nothing is logged.
+func (l *Logger) VFS(ctx context.Context, v *vfs.VFS) *VFS {
+	return &VFS{VFS: v, l: l, ctx: ctx}
+}
+
+// OpenFile opens name with flags
+//
+// The bytes read and written are logged when the handle is closed.
+func (v *VFS) OpenFile(name string, flags int, perm os.FileMode) (vfs.Handle, error) {
+	h, err := v.VFS.OpenFile(name, flags, perm)
+	if err != nil {
+		op := OpDownload
+		if flags&(os.O_WRONLY|os.O_RDWR) != 0 {
+			op = OpUpload
+		}
+		v.l.log(v.ctx, op, name, "", 0, err)
+		return nil, err
+	}
+	return v.l.wrapHandle(v.ctx, name, flags, h), nil
+}
+
+// Open opens name for reading
+func (v *VFS) Open(name string) (vfs.Handle, error) {
+	return v.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates name, truncating it if it already exists
+func (v *VFS) Create(name string) (vfs.Handle, error) {
+	return v.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Remove removes the file or empty directory name
+func (v *VFS) Remove(name string) error {
+	err := v.VFS.Remove(name)
+	v.l.log(v.ctx, OpDelete, name, "", 0, err)
+	return err
+}
+
+// RemoveAll removes name and everything in it
+func (v *VFS) RemoveAll(name string) error {
+	node, err := v.VFS.Stat(name)
+	if err == nil {
+		err = node.RemoveAll()
+	}
+	v.l.log(v.ctx, OpDelete, name, "", 0, err)
+	return err
+}
+
+// Mkdir makes the directory name
+func (v *VFS) Mkdir(name string, perm os.FileMode) error {
+	err := v.VFS.Mkdir(name, perm)
+	v.l.log(v.ctx, OpMkdir, name, "", 0, err)
+	return err
+}
+
+// Rename renames oldName to newName
+func (v *VFS) Rename(oldName, newName string) error {
+	err := v.VFS.Rename(oldName, newName)
+	v.l.log(v.ctx, OpRename, oldName, newName, 0, err)
+	return err
+}
+
+// Do runs fn, which does op on path (to newPath for copies and
+// renames), and logs it.
+//
+// Use this for operations a server can't do with a single call above,
+// for example the s3 server keeping versions of objects. fn returns
+// the number of bytes transferred, if any.
+func (v *VFS) Do(op, path, newPath string, fn func() (int64, error)) error {
+	bytes, err := fn()
+	v.l.log(v.ctx, op, path, newPath, bytes, err)
+	return err
+}
+
+// Reader wraps rc, the contents of path opened without using this,
+// so the bytes read are logged as a download when it is closed.
+func (v *VFS) Reader(path string, rc io.ReadCloser) io.ReadCloser {
+	return v.l.wrapReader(v.ctx, path, rc)
+}
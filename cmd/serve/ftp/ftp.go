@@ -20,6 +20,8 @@ import (
 
 	"github.com/rclone/rclone/cmd"
 	"github.com/rclone/rclone/cmd/serve"
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/cmd/serve/audit/auditflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
@@ -91,6 +93,7 @@ func AddFlags(flagSet *pflag.FlagSet) {
 func init() {
 	vfsflags.AddFlags(Command.Flags())
 	proxyflags.AddFlags(Command.Flags())
+	auditflags.AddFlags(Command.Flags())
 	AddFlags(Command.Flags())
 	serve.Command.AddCommand(Command)
 	serve.AddRc("ftp", func(ctx context.Context, f fs.Fs, in rc.Params) (serve.Handle, error) {
@@ -141,7 +144,7 @@ By default this will serve files without needing a login.
 
 You can set a single username and password with the --user and --pass flags.
 
-` + strings.TrimSpace(vfs.Help()+proxy.Help),
+` + strings.TrimSpace(vfs.Help()+proxy.Help+audit.Help),
 	Annotations: map[string]string{
 		"versionIntroduced": "v1.44",
 		"groups":            "Filter",
@@ -175,6 +178,7 @@ type driver struct {
 	useTLS     bool
 	userPassMu sync.Mutex        // to protect userPass
 	userPass   map[string]string // cache of username => password when using vfs proxy
+	audit      *audit.Logger
 }
 
 func init() {
@@ -199,13 +203,17 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 		ctx: ctx,
 		opt: *opt,
 	}
-	if proxy.Opt.AuthProxy != "" {
-		d.proxy = proxy.New(ctx, proxyOpt, vfsOpt)
+	if proxy.Opt.InUse() {
+		d.proxy = proxy.New(ctx, f, proxyOpt, vfsOpt)
 		d.userPass = make(map[string]string, 16)
 	} else {
 		d.globalVFS = vfs.New(f, vfsOpt)
 	}
 	d.useTLS = d.opt.TLSKey != ""
+	d.audit, err = audit.New("ftp", &audit.Opt)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check PassivePorts format since the server library doesn't!
 	if !passivePortsRe.MatchString(opt.PassivePorts) {
@@ -250,7 +258,11 @@ func (d *driver) Serve() error {
 //lint:ignore U1000 unused when not building linux
 func (d *driver) Shutdown() error {
 	fs.Logf(d.f, "Stopping FTP on %s", d.srv.Hostname+":"+strconv.Itoa(d.srv.Port))
-	return d.srv.Shutdown()
+	err := d.srv.Shutdown()
+	if auditErr := d.audit.Close(); err == nil {
+		err = auditErr
+	}
+	return err
 }
 
 // Return the first address of the server
@@ -344,10 +356,13 @@ func (d *driver) CheckPasswd(sctx *ftp.Context, user, pass string) (ok bool, err
 }
 
 // Get the VFS for this connection
-func (d *driver) getVFS(sctx *ftp.Context) (VFS *vfs.VFS, err error) {
+//
+// The file operations done through it are audit logged.
+func (d *driver) getVFS(sctx *ftp.Context) (*audit.VFS, error) {
+	ctx := audit.WithConn(d.ctx, sctx.Sess.LoginUser(), sctx.Sess.RemoteAddr().String())
 	if d.proxy == nil {
 		// If no proxy always use the same VFS
-		return d.globalVFS, nil
+		return d.audit.VFS(ctx, d.globalVFS), nil
 	}
 	user := sctx.Sess.LoginUser()
 	d.userPassMu.Lock()
@@ -360,11 +375,11 @@ func (d *driver) getVFS(sctx *ftp.Context) (VFS *vfs.VFS, err error) {
 	if err != nil {
 		return nil, err
 	}
-	VFS, _, err = d.proxy.Call(user, pass, false)
+	VFS, _, err := d.proxy.Call(user, pass, false)
 	if err != nil {
 		return nil, fmt.Errorf("proxy login failed: %w", err)
 	}
-	return VFS, nil
+	return d.audit.VFS(ctx, VFS), nil
 }
 
 // Stat get information on file or folder
@@ -450,11 +465,7 @@ func (d *driver) DeleteDir(sctx *ftp.Context, path string) (err error) {
 	if !node.IsDir() {
 		return errors.New("not a directory")
 	}
-	err = node.Remove()
-	if err != nil {
-		return err
-	}
-	return nil
+	return VFS.Remove(path)
 }
 
 // DeleteFile delete a file
@@ -471,11 +482,7 @@ func (d *driver) DeleteFile(sctx *ftp.Context, path string) (err error) {
 	if !node.IsFile() {
 		return errors.New("not a file")
 	}
-	err = node.Remove()
-	if err != nil {
-		return err
-	}
-	return nil
+	return VFS.Remove(path)
 }
 
 // Rename rename a file or folder
@@ -495,12 +502,7 @@ func (d *driver) MakeDir(sctx *ftp.Context, path string) (err error) {
 	if err != nil {
 		return err
 	}
-	dir, leaf, err := VFS.StatParent(path)
-	if err != nil {
-		return err
-	}
-	_, err = dir.Mkdir(leaf)
-	return err
+	return VFS.Mkdir(path, 0777)
 }
 
 // GetFile download a file
@@ -521,7 +523,7 @@ func (d *driver) GetFile(sctx *ftp.Context, path string, offset int64) (size int
 		return 0, nil, errors.New("not a file")
 	}
 
-	handle, err := node.Open(os.O_RDONLY)
+	handle, err := VFS.Open(path)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -568,7 +570,8 @@ func (d *driver) PutFile(sctx *ftp.Context, path string, data io.Reader, offset
 
 	if offset == -1 {
 		if isExist {
-			err = VFS.Remove(path)
+			// Replacing the file is logged as an upload only
+			err = VFS.VFS.Remove(path)
 			if err != nil {
 				return 0, err
 			}
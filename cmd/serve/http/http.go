@@ -179,8 +179,8 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 		opt: *opt,
 	}
 
-	if proxyOpt.AuthProxy != "" {
-		s.proxy = proxy.New(ctx, proxyOpt, vfsOpt)
+	if proxyOpt.InUse() {
+		s.proxy = proxy.New(ctx, f, proxyOpt, vfsOpt)
 		// override auth
 		s.opt.Auth.CustomAuthFn = s.auth
 	} else {
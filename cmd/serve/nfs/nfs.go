@@ -1,6 +1,7 @@
 //go:build unix
 
-// Package nfs implements a server to serve a VFS remote over the NFSv3 protocol
+// Package nfs implements a server to serve a VFS remote over the NFSv3
+// or NFSv4.1 protocols
 //
 // There is no authentication available on this server and it is
 // served on the loopback interface by default.
@@ -43,6 +44,10 @@ var OptionsInfo = fs.Options{{
 	Name:    "nfs_cache_dir",
 	Default: "",
 	Help:    "The directory the NFS handle cache will use if set",
+}, {
+	Name:    "nfs_version",
+	Default: nfsVersion3,
+	Help:    "NFS protocol version to serve",
 }}
 
 func init() {
@@ -67,12 +72,29 @@ func (handleCacheChoices) Choices() []string {
 	}
 }
 
+type nfsVersion = fs.Enum[nfsVersionChoices]
+
+const (
+	nfsVersion3 nfsVersion = iota
+	nfsVersion41
+)
+
+type nfsVersionChoices struct{}
+
+func (nfsVersionChoices) Choices() []string {
+	return []string{
+		nfsVersion3:  "3",
+		nfsVersion41: "4.1",
+	}
+}
+
 // Options contains options for the NFS Server
 type Options struct {
 	ListenAddr     string      `config:"addr"`                   // Port to listen on
 	HandleLimit    int         `config:"nfs_cache_handle_limit"` // max file handles cached by go-nfs CachingHandler
 	HandleCache    handleCache `config:"nfs_cache_type"`         // what kind of handle cache to use
 	HandleCacheDir string      `config:"nfs_cache_dir"`          // where the handle cache should be stored
+	Version        nfsVersion  `config:"nfs_version"`            // which version of NFS to serve
 }
 
 // Opt is the default set of serve nfs options
@@ -126,7 +148,8 @@ var Command = &cobra.Command{
 	Short: `Serve the remote as an NFS mount`,
 	Long: strings.ReplaceAll(`Create an NFS server that serves the given remote over the network.
 
-This implements an NFSv3 server to serve any rclone remote via NFS.
+This implements an NFSv3 or NFSv4.1 server to serve any rclone remote
+via NFS.
 
 The primary purpose for this command is to enable the [mount
 command](/commands/rclone_mount/) on recent macOS versions where
@@ -194,6 +217,29 @@ Where |$PORT| is the same port number used in the |serve nfs| command
 and |$HOSTNAME| is the network address of the machine that |serve nfs|
 was run on.
 
+### NFSv4.1
+
+By default the server speaks NFSv3. Use |--nfs-version 4.1| to serve
+NFSv4.1 instead. This is a stateful protocol so the server tracks the
+files clients have open and supports byte-range locks (|fcntl| and
+|flock| locks on Linux clients) which are shared between all the
+clients of the server. NFSv4.1 clients also get close-to-open
+consistency: changes are flushed to the VFS when a file is closed and
+clients check for changes when they open a file.
+
+NFSv4.1 uses a single port and doesn't need the mount protocol so
+mount it under Linux like this:
+
+|||sh
+mount -t nfs -o vers=4.1,port=$PORT $HOSTNAME:/ path/to/mountpoint
+|||
+
+The server doesn't grant delegations and its state isn't kept over a
+restart, so clients lose their locks if the server is restarted. Use
+|--nfs-cache-type disk| if clients need to keep working over a restart.
+Owners and groups of files are sent as numbers set by |--uid| and
+|--gid|.
+
 If |--vfs-metadata-extension| is in use then for the |--nfs-cache-type disk|
 and |--nfs-cache-type cache| the metadata files will have the file
 handle of their parent file suffixed with |0x00, 0x00, 0x00, 0x01|.
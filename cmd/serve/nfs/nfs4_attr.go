@@ -0,0 +1,329 @@
+//go:build unix
+
+package nfs
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rclone/rclone/vfs"
+)
+
+// supportedAttrs are the attributes the server can return
+var supportedAttrs = newBitmap(
+	attrSupportedAttrs, attrType, attrFhExpireType, attrChange, attrSize,
+	attrLinkSupport, attrSymlinkSupport, attrNamedAttr, attrFsid,
+	attrUniqueHandles, attrLeaseTime, attrRdattrError, attrAclsupport,
+	attrCansettime, attrCaseInsensitive, attrCasePreserving,
+	attrChownRestricted, attrFilehandle, attrFileid, attrFilesAvail,
+	attrFilesFree, attrFilesTotal, attrHomogeneous, attrMaxfilesize,
+	attrMaxlink, attrMaxname, attrMaxread, attrMaxwrite, attrMode,
+	attrNoTrunc, attrNumlinks, attrOwner, attrOwnerGroup, attrRawdev,
+	attrSpaceAvail, attrSpaceFree, attrSpaceTotal, attrSpaceUsed,
+	attrTimeAccess, attrTimeAccessSet, attrTimeDelta, attrTimeMetadata,
+	attrTimeModify, attrTimeModifySet, attrMountedOnFileid,
+	attrSuppattrExclcreat,
+)
+
+// exclusiveCreateAttrs are the attributes which can be set with an
+// EXCLUSIVE4_1 create
+var exclusiveCreateAttrs = newBitmap(attrSize, attrMode, attrOwner, attrOwnerGroup)
+
+// writeOnlyAttrs can be set but not read
+var writeOnlyAttrs = newBitmap(attrTimeAccessSet, attrTimeModifySet)
+
+// settableAttrs can be set with SETATTR
+var settableAttrs = newBitmap(attrSize, attrMode, attrOwner, attrOwnerGroup, attrTimeAccessSet, attrTimeModifySet)
+
+// nfs4FSID is the fsid of the exported filesystem
+const nfs4FSID = 0x72636c6f6e65 // "rclone"
+
+// fileType returns the NFSv4 type of the node
+func fileType(node vfs.Node) uint32 {
+	switch {
+	case node.IsDir():
+		return nf4Dir
+	case node.Mode()&os.ModeSymlink != 0:
+		return nf4Lnk
+	}
+	return nf4Reg
+}
+
+// changeAttr returns the change attribute of the node
+//
+// The VFS doesn't change the modification time of directories when
+// their entries change so the count of changes made through this
+// server is added in for them.
+func (s *server4) changeAttr(node vfs.Node) uint64 {
+	change := uint64(node.ModTime().UnixNano())
+	if node.IsDir() {
+		s.mu.Lock()
+		change += s.dirChanges[node.Inode()]
+		s.mu.Unlock()
+	} else {
+		change += uint64(node.Size())
+	}
+	return change
+}
+
+// writeTime writes a nfstime4
+func writeTime(w *xdrWriter, t time.Time) {
+	w.Int64(t.Unix())
+	w.Uint32(uint32(t.Nanosecond()))
+}
+
+// readTime reads a nfstime4
+func readTime(r *xdrReader) time.Time {
+	secs := r.Int64()
+	nsecs := r.Uint32()
+	return time.Unix(secs, int64(nsecs))
+}
+
+// writeAttrs writes the fattr4 for the node with the attributes in
+// request which are supported
+func (s *server4) writeAttrs(w *xdrWriter, node vfs.Node, fh []byte, request bitmap4) {
+	var (
+		mask bitmap4
+		vals = &xdrWriter{}
+	)
+	for word := range min(len(request), len(supportedAttrs)) {
+		for bit := range 32 {
+			attr := word*32 + bit
+			if !request.isSet(attr) || !supportedAttrs.isSet(attr) || writeOnlyAttrs.isSet(attr) {
+				continue
+			}
+			mask.set(attr)
+			s.writeAttr(vals, node, fh, attr)
+		}
+	}
+	w.Bitmap(mask)
+	w.Opaque(vals.Bytes())
+}
+
+// writeAttr writes the value of the attribute for the node
+func (s *server4) writeAttr(w *xdrWriter, node vfs.Node, fh []byte, attr int) {
+	opt := &s.vfs.Opt
+	switch attr {
+	case attrSupportedAttrs:
+		w.Bitmap(supportedAttrs)
+	case attrType:
+		w.Uint32(fileType(node))
+	case attrFhExpireType:
+		w.Uint32(0) // FH4_PERSISTENT
+	case attrChange:
+		w.Uint64(s.changeAttr(node))
+	case attrSize:
+		w.Uint64(uint64(max(node.Size(), 0)))
+	case attrLinkSupport:
+		w.Bool(false)
+	case attrSymlinkSupport:
+		w.Bool(opt.Links)
+	case attrNamedAttr:
+		w.Bool(false)
+	case attrFsid:
+		w.Uint64(nfs4FSID)
+		w.Uint64(0)
+	case attrUniqueHandles:
+		w.Bool(true)
+	case attrLeaseTime:
+		w.Uint32(uint32(nfs4LeaseTime / time.Second))
+	case attrRdattrError:
+		w.Uint32(uint32(nfs4OK))
+	case attrAclsupport:
+		w.Uint32(0)
+	case attrCansettime:
+		w.Bool(true)
+	case attrCaseInsensitive:
+		w.Bool(false)
+	case attrCasePreserving:
+		w.Bool(true)
+	case attrChownRestricted:
+		w.Bool(true)
+	case attrFilehandle:
+		w.Opaque(fh)
+	case attrFileid, attrMountedOnFileid:
+		w.Uint64(node.Inode())
+	case attrFilesAvail, attrFilesFree, attrFilesTotal:
+		w.Uint64(math.MaxInt32)
+	case attrHomogeneous:
+		w.Bool(true)
+	case attrMaxfilesize:
+		w.Uint64(math.MaxInt64)
+	case attrMaxlink:
+		w.Uint32(1)
+	case attrMaxname:
+		w.Uint32(255)
+	case attrMaxread, attrMaxwrite:
+		w.Uint64(nfs4MaxIO)
+	case attrMode:
+		w.Uint32(uint32(node.Mode().Perm()))
+	case attrNoTrunc:
+		w.Bool(true)
+	case attrNumlinks:
+		if node.IsDir() {
+			w.Uint32(2)
+		} else {
+			w.Uint32(1)
+		}
+	case attrOwner:
+		w.String(strconv.FormatUint(uint64(opt.UID), 10))
+	case attrOwnerGroup:
+		w.String(strconv.FormatUint(uint64(opt.GID), 10))
+	case attrRawdev:
+		w.Uint32(0)
+		w.Uint32(0)
+	case attrSpaceAvail, attrSpaceFree:
+		_, _, free := s.vfs.Statfs()
+		w.Uint64(statfsValue(free))
+	case attrSpaceTotal:
+		total, _, _ := s.vfs.Statfs()
+		w.Uint64(statfsValue(total))
+	case attrSpaceUsed:
+		w.Uint64(uint64(max(node.Size(), 0)))
+	case attrTimeAccess, attrTimeMetadata, attrTimeModify:
+		writeTime(w, node.ModTime())
+	case attrTimeDelta:
+		writeTime(w, time.Unix(0, 1))
+	case attrSuppattrExclcreat:
+		w.Bitmap(exclusiveCreateAttrs)
+	}
+}
+
+// statfsValue converts a value from Statfs where -1 means unknown
+func statfsValue(x int64) uint64 {
+	if x < 0 {
+		return 1 << 50
+	}
+	return uint64(x)
+}
+
+// setAttrs are the decoded attributes from a fattr4 to set
+type setAttrs struct {
+	mask  bitmap4    // attributes present
+	size  *uint64    // new size if set
+	atime *time.Time // new access time if set
+	mtime *time.Time // new modification time if set
+}
+
+// readSettime reads a settime4
+func readSettime(r *xdrReader) *time.Time {
+	var t time.Time
+	if r.Uint32() == 1 { // SET_TO_CLIENT_TIME4
+		t = readTime(r)
+	} else {
+		t = time.Now()
+	}
+	return &t
+}
+
+// readSetAttrs reads a fattr4 with the attributes to set
+//
+// If allowed is not nil then only those attributes may be set.
+func readSetAttrs(r *xdrReader, allowed bitmap4) (a setAttrs, status nfsstat4) {
+	a.mask = r.Bitmap()
+	vals := newXDRReader(r.Opaque(nfs4MaxRequestSize))
+	if r.err != nil {
+		return a, nfs4errBadxdr
+	}
+	for word := range a.mask {
+		for bit := range 32 {
+			attr := word*32 + bit
+			if !a.mask.isSet(attr) {
+				continue
+			}
+			if !supportedAttrs.isSet(attr) {
+				return a, nfs4errAttrnotsupp
+			}
+			if !settableAttrs.isSet(attr) || (allowed != nil && !allowed.isSet(attr)) {
+				return a, nfs4errInval
+			}
+			switch attr {
+			case attrSize:
+				size := vals.Uint64()
+				a.size = &size
+			case attrMode:
+				_ = vals.Uint32()
+			case attrOwner, attrOwnerGroup:
+				_ = vals.String(1024)
+			case attrTimeAccessSet:
+				a.atime = readSettime(vals)
+			case attrTimeModifySet:
+				a.mtime = readSettime(vals)
+			}
+		}
+	}
+	if vals.err != nil || vals.Remaining() != 0 {
+		return a, nfs4errBadxdr
+	}
+	return a, nfs4OK
+}
+
+// setAttrs applies the attributes to the node returning those which
+// were set
+//
+// Mode and owners can't be changed in the VFS so are accepted and
+// ignored like the NFSv3 server does.
+func (s *server4) setAttrs(node vfs.Node, a setAttrs) (set bitmap4, status nfsstat4) {
+	if a.size != nil {
+		if node.IsDir() {
+			return set, nfs4errIsdir
+		}
+		if s.readOnly {
+			return set, nfs4errRofs
+		}
+		if *a.size > math.MaxInt64 {
+			return set, nfs4errFbig
+		}
+		err := node.Truncate(int64(*a.size))
+		if err != nil {
+			return set, nfs4Status(err)
+		}
+		set.set(attrSize)
+	}
+	if a.mtime != nil {
+		err := node.SetModTime(*a.mtime)
+		if err != nil {
+			return set, nfs4Status(err)
+		}
+		set.set(attrTimeModifySet)
+	}
+	if a.atime != nil {
+		// The VFS doesn't keep access times
+		set.set(attrTimeAccessSet)
+	}
+	for _, attr := range []int{attrMode, attrOwner, attrOwnerGroup} {
+		if a.mask.isSet(attr) {
+			set.set(attr)
+		}
+	}
+	return set, nfs4OK
+}
+
+// compareAttrs reads a fattr4 and returns true if it matches the
+// attributes of node
+func (s *server4) compareAttrs(r *xdrReader, node vfs.Node, fh []byte) (same bool, status nfsstat4) {
+	mask := r.Bitmap()
+	vals := r.Opaque(nfs4MaxRequestSize)
+	if r.err != nil {
+		return false, nfs4errBadxdr
+	}
+	for word := range mask {
+		for bit := range 32 {
+			attr := word*32 + bit
+			switch {
+			case !mask.isSet(attr):
+			case !supportedAttrs.isSet(attr):
+				return false, nfs4errAttrnotsupp
+			case writeOnlyAttrs.isSet(attr) || attr == attrRdattrError:
+				return false, nfs4errInval
+			}
+		}
+	}
+	w := &xdrWriter{}
+	s.writeAttrs(w, node, fh, mask)
+	r2 := newXDRReader(w.Bytes())
+	_ = r2.Bitmap()
+	return string(r2.Opaque(nfs4MaxRequestSize)) == string(vals), nfs4OK
+}
@@ -0,0 +1,1197 @@
+//go:build unix
+
+package nfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+func init() {
+	ops = map[uint32]opFn{
+		opAccess:            opAccessFn,
+		opBackchannelCtl:    opBackchannelCtlFn,
+		opBindConnToSession: opBindConnToSessionFn,
+		opClose:             opCloseFn,
+		opCommit:            opCommitFn,
+		opCreate:            opCreateFn,
+		opCreateSession:     opCreateSessionFn,
+		opDelegreturn:       opDelegreturnFn,
+		opDestroyClientid:   opDestroyClientidFn,
+		opDestroySession:    opDestroySessionFn,
+		opExchangeID:        opExchangeIDFn,
+		opFreeStateid:       opFreeStateidFn,
+		opGetattr:           opGetattrFn,
+		opGetfh:             opGetfhFn,
+		opLock:              opLockFn,
+		opLockt:             opLocktFn,
+		opLocku:             opLockuFn,
+		opLookup:            opLookupFn,
+		opLookupp:           opLookuppFn,
+		opNverify:           opNverifyFn,
+		opOpen:              opOpenFn,
+		opOpenDowngrade:     opOpenDowngradeFn,
+		opPutfh:             opPutfhFn,
+		opPutpubfh:          opPutrootfhFn,
+		opPutrootfh:         opPutrootfhFn,
+		opRead:              opReadFn,
+		opReaddir:           opReaddirFn,
+		opReadlink:          opReadlinkFn,
+		opReclaimComplete:   opReclaimCompleteFn,
+		opRemove:            opRemoveFn,
+		opRename:            opRenameFn,
+		opRestorefh:         opRestorefhFn,
+		opSavefh:            opSavefhFn,
+		opSecinfo:           opSecinfoFn,
+		opSecinfoNoName:     opSecinfoNoNameFn,
+		opSetattr:           opSetattrFn,
+		opTestStateid:       opTestStateidFn,
+		opVerify:            opVerifyFn,
+		opWrite:             opWriteFn,
+	}
+}
+
+// childPath returns the path of name in the current directory
+func (c *compoundState) childPath(name string) []string {
+	return append(slices.Clone(c.path), name)
+}
+
+// writeChangeInfo writes a change_info4
+func writeChangeInfo(res *xdrWriter, before, after uint64) {
+	res.Bool(false) // not atomic
+	res.Uint64(before)
+	res.Uint64(after)
+}
+
+// readName reads a component name and checks it
+func readName(args *xdrReader) (string, nfsstat4) {
+	name := args.String(1024)
+	if args.err != nil {
+		return "", nfs4errBadxdr
+	}
+	return name, checkName(name)
+}
+
+// closeHandles closes VFS handles which are no longer needed
+func closeHandles(handles []vfs.Handle) {
+	for _, h := range handles {
+		err := h.Close()
+		if err != nil {
+			fs.Errorf(h.Node().Path(), "NFS failed to close file: %v", err)
+		}
+	}
+}
+
+// opPutfhFn runs PUTFH
+func opPutfhFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	fh := args.Opaque(128)
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	_, p, err := c.s.h.FromHandle(fh)
+	if err != nil {
+		return nfs4errStale
+	}
+	c.fh = slices.Clone(fh)
+	c.path = p
+	c.haveSID = false
+	return nfs4OK
+}
+
+// opPutrootfhFn runs PUTROOTFH and PUTPUBFH
+func opPutrootfhFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	c.setFH([]string{})
+	return nfs4OK
+}
+
+// opGetfhFn runs GETFH
+func opGetfhFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	if c.fh == nil {
+		return nfs4errNofilehandle
+	}
+	res.Opaque(c.fh)
+	return nfs4OK
+}
+
+// opSavefhFn runs SAVEFH
+func opSavefhFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	if c.fh == nil {
+		return nfs4errNofilehandle
+	}
+	c.savedFH, c.savedPath = c.fh, c.path
+	c.savedSID, c.haveSaved = c.stateid, c.haveSID
+	return nfs4OK
+}
+
+// opRestorefhFn runs RESTOREFH
+func opRestorefhFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	if c.savedFH == nil {
+		return nfs4errRestorefh
+	}
+	c.fh, c.path = c.savedFH, c.savedPath
+	c.stateid, c.haveSID = c.savedSID, c.haveSaved
+	return nfs4OK
+}
+
+// opLookupFn runs LOOKUP
+func opLookupFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	name, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	if _, status = c.currentDir(); status != nfs4OK {
+		return status
+	}
+	p := c.childPath(name)
+	_, err := c.s.vfs.Stat(path.Join(p...))
+	if err != nil {
+		return nfs4Status(err)
+	}
+	c.setFH(p)
+	return nfs4OK
+}
+
+// opLookuppFn runs LOOKUPP
+func opLookuppFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	if _, status := c.currentDir(); status != nfs4OK {
+		return status
+	}
+	if len(c.path) == 0 {
+		return nfs4errNoent
+	}
+	c.setFH(c.path[:len(c.path)-1])
+	return nfs4OK
+}
+
+// opGetattrFn runs GETATTR
+func opGetattrFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	request := args.Bitmap()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	c.s.writeAttrs(res, node, c.fh, request)
+	return nfs4OK
+}
+
+// opVerifyFn runs VERIFY
+func opVerifyFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	same, status := c.s.compareAttrs(args, node, c.fh)
+	if status == nfs4OK && !same {
+		status = nfs4errNotSame
+	}
+	return status
+}
+
+// opNverifyFn runs NVERIFY
+func opNverifyFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	same, status := c.s.compareAttrs(args, node, c.fh)
+	if status == nfs4OK && same {
+		status = nfs4errSame
+	}
+	return status
+}
+
+// opAccessFn runs ACCESS
+func opAccessFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	request := args.Uint32()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	if _, status := c.current(); status != nfs4OK {
+		return status
+	}
+	allowed := uint32(access4Read | access4Lookup | access4Execute)
+	if !c.s.readOnly {
+		allowed |= access4Modify | access4Extend | access4Delete
+	}
+	supported := request & (access4Read | access4Lookup | access4Modify | access4Extend | access4Delete | access4Execute)
+	res.Uint32(supported)
+	res.Uint32(supported & allowed)
+	return nfs4OK
+}
+
+// opReaddirFn runs READDIR
+func opReaddirFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	cookie := args.Uint64()
+	_ = args.Fixed(8) // cookieverf
+	_ = args.Uint32() // dircount
+	maxCount := int(args.Uint32())
+	request := args.Bitmap()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	dir, status := c.currentDir()
+	if status != nfs4OK {
+		return status
+	}
+	if cookie == 1 || cookie == 2 {
+		return nfs4errBadCookie
+	}
+	items, err := dir.ReadDirAll()
+	if err != nil {
+		return nfs4Status(err)
+	}
+	// The cookie of entry i is i+3 as 0, 1 and 2 are reserved
+	start := 0
+	if cookie != 0 {
+		start = int(min(cookie-2, uint64(len(items))))
+	}
+	res.Fixed(make([]byte, 8)) // cookieverf
+	used := res.Len() + 8      // space for the end of the list and eof
+	eof := true
+	for i := start; i < len(items); i++ {
+		item := items[i]
+		var fh []byte
+		if request.isSet(attrFilehandle) {
+			fh = c.s.h.ToHandle(c.s.h.billyFS, c.childPath(item.Name()))
+		}
+		entry := &xdrWriter{}
+		entry.Bool(true) // value follows
+		entry.Uint64(uint64(i + 3))
+		entry.String(item.Name())
+		c.s.writeAttrs(entry, item, fh, request)
+		if used+entry.Len() > maxCount {
+			if i == start {
+				return nfs4errToosmall
+			}
+			eof = false
+			break
+		}
+		used += entry.Len()
+		res.buf = append(res.buf, entry.Bytes()...)
+	}
+	res.Bool(false) // no more entries
+	res.Bool(eof)
+	return nfs4OK
+}
+
+// opOpenFn runs OPEN
+func opOpenFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint32() // seqid is unused in NFSv4.1
+	access := args.Uint32() & openShareAccessBoth
+	deny := args.Uint32()
+	_ = args.Uint64() // clientid comes from the session in NFSv4.1
+	owner := args.String(1024)
+	var (
+		create     = args.Uint32() == open4Create
+		createMode uint32
+		attrs      setAttrs
+		verifier   [8]byte
+		status     nfsstat4
+	)
+	if create {
+		createMode = args.Uint32()
+		switch createMode {
+		case createUnchecked, createGuarded:
+			attrs, status = readSetAttrs(args, nil)
+		case createExclusive:
+			copy(verifier[:], args.Fixed(8))
+		case createExclusive1:
+			copy(verifier[:], args.Fixed(8))
+			attrs, status = readSetAttrs(args, exclusiveCreateAttrs)
+		default:
+			return nfs4errInval
+		}
+		if status != nfs4OK {
+			return status
+		}
+	}
+	var name string
+	claim := args.Uint32()
+	switch claim {
+	case claimNull:
+		name = args.String(1024)
+	case claimFH:
+	case claimPrevious:
+		// No state survives a restart so there is no grace period
+		return nfs4errNoGrace
+	default:
+		return nfs4errNotsupp
+	}
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	if access == 0 || deny > openShareDenyBoth {
+		return nfs4errInval
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	if s.readOnly && (create || access&openShareAccessWrite != 0) {
+		return nfs4errRofs
+	}
+
+	// Find the file
+	var (
+		p      []string
+		parent *vfs.Dir
+		before uint64
+	)
+	if claim == claimNull {
+		if status = checkName(name); status != nfs4OK {
+			return status
+		}
+		if parent, status = c.currentDir(); status != nfs4OK {
+			return status
+		}
+		before = s.changeAttr(parent)
+		p = c.childPath(name)
+	} else {
+		if create {
+			return nfs4errInval
+		}
+		if c.fh == nil {
+			return nfs4errNofilehandle
+		}
+		p = c.path
+	}
+	filePath := path.Join(p...)
+	node, err := s.vfs.Stat(filePath)
+	exists := err == nil
+	switch {
+	case err != nil && !errors.Is(err, vfs.ENOENT):
+		return nfs4Status(err)
+	case !exists && !create:
+		return nfs4errNoent
+	case exists && node.IsDir():
+		return nfs4errIsdir
+	case exists && fileType(node) == nf4Lnk:
+		return nfs4errSymlink
+	case exists && create && createMode == createGuarded:
+		return nfs4errExist
+	case exists && create && (createMode == createExclusive || createMode == createExclusive1):
+		// Only a retry of the create which made the file may open it
+		s.mu.Lock()
+		v, found := s.exclVerifiers[filePath]
+		s.mu.Unlock()
+		if !found || v != verifier {
+			return nfs4errExist
+		}
+		attrs = setAttrs{}
+	}
+	if exists {
+		s.mu.Lock()
+		conflict := false
+		if f := s.files[node.Inode()]; f != nil {
+			conflict = f.shareConflict(access, deny, f.findOpen(client, owner))
+		}
+		s.mu.Unlock()
+		if conflict {
+			return nfs4errShareDenied
+		}
+	}
+
+	// Open a VFS handle
+	flags := os.O_RDONLY
+	if access&openShareAccessWrite != 0 || !exists {
+		flags = os.O_RDWR
+	}
+	if !exists {
+		flags |= os.O_CREATE
+		if createMode != createUnchecked {
+			flags |= os.O_EXCL
+		}
+	}
+	handle, err := s.vfs.OpenFile(filePath, flags, os.FileMode(s.vfs.Opt.FilePerms))
+	if err != nil {
+		return nfs4Status(err)
+	}
+	node = handle.Node()
+	var attrsSet bitmap4
+	if create {
+		attrsSet, status = s.setAttrs(node, attrs)
+		if status != nfs4OK {
+			closeHandles([]vfs.Handle{handle})
+			return status
+		}
+	}
+
+	// Record the open
+	var handles []vfs.Handle
+	s.mu.Lock()
+	if !exists {
+		if createMode == createExclusive || createMode == createExclusive1 {
+			s.exclVerifiers[filePath] = verifier
+		}
+		s.dirChanged(parent.Inode())
+	}
+	file := s.getFile(node.Inode())
+	state := file.findOpen(client, owner)
+	if file.shareConflict(access, deny, state) {
+		s.putFile(file)
+		s.mu.Unlock()
+		closeHandles([]vfs.Handle{handle})
+		return nfs4errShareDenied
+	}
+	if state == nil {
+		state = s.newState(client, file, owner)
+		state.locks = map[string]*nfs4State{}
+		file.opens = append(file.opens, state)
+		state.handle, handle = &openHandle{Handle: handle}, nil
+		state.writable = flags&os.O_RDWR != 0
+	} else {
+		// Upgrade the existing open
+		if flags&os.O_RDWR != 0 && !state.writable {
+			oldHandle := state.handle
+			state.handle, handle = &openHandle{Handle: handle}, nil
+			state.writable = true
+			handles = oldHandle.finish()
+		}
+		state.bumpSeqid()
+	}
+	state.access |= access
+	state.deny |= deny
+	sid := state.sid
+	s.mu.Unlock()
+	if handle != nil {
+		handles = append(handles, handle)
+	}
+	closeHandles(handles)
+
+	res.Stateid(sid)
+	if parent != nil {
+		writeChangeInfo(res, before, s.changeAttr(parent))
+	} else {
+		writeChangeInfo(res, 0, 0)
+	}
+	res.Uint32(open4ResultLocktypePosix)
+	res.Bitmap(attrsSet)
+	res.Uint32(openDelegateNone)
+	c.setFH(p)
+	c.setStateid(sid)
+	return nfs4OK
+}
+
+// openState reads an open stateid and returns its state
+//
+// It must be called with s.mu held.
+func (c *compoundState) openState(sid stateid4) (*nfs4State, nfsstat4) {
+	sid, status := c.resolveStateid(sid)
+	if status != nfs4OK {
+		return nil, status
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return nil, status
+	}
+	state, status := c.s.lookupState(client, sid)
+	if status != nfs4OK {
+		return nil, status
+	}
+	if state.isLock() {
+		return nil, nfs4errBadStateid
+	}
+	return state, nfs4OK
+}
+
+// opCloseFn runs CLOSE
+func opCloseFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint32() // seqid
+	sid := args.Stateid()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	s := c.s
+	s.mu.Lock()
+	state, status := c.openState(sid)
+	if status != nfs4OK {
+		s.mu.Unlock()
+		return status
+	}
+	for _, lockState := range state.locks {
+		if state.file.hasLocks(lockState.lockOwner()) {
+			s.mu.Unlock()
+			return nfs4errLocksHeld
+		}
+	}
+	handles := s.closeOpen(state)
+	s.mu.Unlock()
+	closeHandles(handles)
+	// NFSv4.1 servers should return the invalid stateid
+	res.Stateid(invalidStateid)
+	return nfs4OK
+}
+
+// opOpenDowngradeFn runs OPEN_DOWNGRADE
+func opOpenDowngradeFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	sid := args.Stateid()
+	_ = args.Uint32() // seqid
+	access := args.Uint32() & openShareAccessBoth
+	deny := args.Uint32()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, status := c.openState(sid)
+	if status != nfs4OK {
+		return status
+	}
+	if access == 0 || access&^state.access != 0 || deny&^state.deny != 0 {
+		return nfs4errInval
+	}
+	state.access = access
+	state.deny = deny
+	state.bumpSeqid()
+	res.Stateid(state.sid)
+	c.setStateid(state.sid)
+	return nfs4OK
+}
+
+// ioHandle returns a VFS handle for node to do I/O with stateid sid
+//
+// The anonymous stateids can be used for I/O without an open so these
+// open a handle for the duration of the operation. The handle of an
+// open is kept open until the I/O is finished even if the open is
+// closed meanwhile. The release function returned must be called when
+// the I/O is finished.
+func (c *compoundState) ioHandle(node vfs.Node, sid stateid4, write bool) (handle vfs.Handle, release func(), status nfsstat4) {
+	s := c.s
+	sid, status = c.resolveStateid(sid)
+	if status != nfs4OK {
+		return nil, nil, status
+	}
+	if sid == anonymousStateid || sid == bypassStateid {
+		flags := os.O_RDONLY
+		if write {
+			flags = os.O_RDWR
+		}
+		handle, err := s.vfs.OpenFile(c.currentPath(), flags, 0)
+		if err != nil {
+			return nil, nil, nfs4Status(err)
+		}
+		return handle, func() { closeHandles([]vfs.Handle{handle}) }, nfs4OK
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return nil, nil, status
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, status := s.lookupState(client, sid)
+	if status != nfs4OK {
+		return nil, nil, status
+	}
+	if state.isLock() {
+		state = state.open
+	}
+	switch {
+	case state.file.inode != node.Inode():
+		return nil, nil, nfs4errBadStateid
+	case write && state.access&openShareAccessWrite == 0:
+		return nil, nil, nfs4errOpenmode
+	}
+	h := state.handle
+	h.acquire()
+	return h.Handle, func() {
+		s.mu.Lock()
+		handles := h.release()
+		s.mu.Unlock()
+		closeHandles(handles)
+	}, nfs4OK
+}
+
+// opReadFn runs READ
+func opReadFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	sid := args.Stateid()
+	offset := args.Uint64()
+	count := args.Uint32()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	switch fileType(node) {
+	case nf4Dir:
+		return nfs4errIsdir
+	case nf4Lnk:
+		return nfs4errSymlink
+	}
+	handle, release, status := c.ioHandle(node, sid, false)
+	if status != nfs4OK {
+		return status
+	}
+	defer release()
+	buf := make([]byte, min(count, nfs4MaxIO))
+	n, err := handle.ReadAt(buf, int64(offset))
+	eof := errors.Is(err, io.EOF)
+	if err != nil && !eof {
+		return nfs4Status(err)
+	}
+	eof = eof || int64(offset)+int64(n) >= node.Size()
+	res.Bool(eof)
+	res.Opaque(buf[:n])
+	return nfs4OK
+}
+
+// opWriteFn runs WRITE
+func opWriteFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	sid := args.Stateid()
+	offset := args.Uint64()
+	stable := args.Uint32()
+	data := args.Opaque(nfs4MaxIO)
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	if c.s.readOnly {
+		return nfs4errRofs
+	}
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	switch fileType(node) {
+	case nf4Dir:
+		return nfs4errIsdir
+	case nf4Lnk:
+		return nfs4errSymlink
+	}
+	handle, release, status := c.ioHandle(node, sid, true)
+	if status != nfs4OK {
+		return status
+	}
+	defer release()
+	n, err := handle.WriteAt(data, int64(offset))
+	if err != nil {
+		return nfs4Status(err)
+	}
+	committed := uint32(unstable4)
+	if stable != unstable4 {
+		if err = handle.Sync(); err != nil {
+			return nfs4Status(err)
+		}
+		committed = fileSync4
+	}
+	res.Uint32(uint32(n))
+	res.Uint32(committed)
+	res.Fixed(c.s.bootVerifier[:])
+	return nfs4OK
+}
+
+// opCommitFn runs COMMIT
+func opCommitFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint64() // offset
+	_ = args.Uint32() // count
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	if node.IsDir() {
+		return nfs4errIsdir
+	}
+	// Sync all the handles open for writing on the file
+	var handles []*openHandle
+	s := c.s
+	s.mu.Lock()
+	if f := s.files[node.Inode()]; f != nil {
+		for _, o := range f.opens {
+			if o.writable {
+				o.handle.acquire()
+				handles = append(handles, o.handle)
+			}
+		}
+	}
+	s.mu.Unlock()
+	var err error
+	for _, h := range handles {
+		if err == nil {
+			err = h.Sync()
+		}
+	}
+	var toClose []vfs.Handle
+	s.mu.Lock()
+	for _, h := range handles {
+		toClose = append(toClose, h.release()...)
+	}
+	s.mu.Unlock()
+	closeHandles(toClose)
+	if err != nil {
+		return nfs4Status(err)
+	}
+	res.Fixed(s.bootVerifier[:])
+	return nfs4OK
+}
+
+// opCreateFn runs CREATE which makes directories and symlinks
+func opCreateFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	var linkData string
+	typ := args.Uint32()
+	switch typ {
+	case nf4Lnk:
+		linkData = args.String(4096)
+	case nf4Blk, nf4Chr:
+		_ = args.Uint32() // specdata1
+		_ = args.Uint32() // specdata2
+	}
+	name, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	attrs, status := readSetAttrs(args, nil)
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	if s.readOnly {
+		return nfs4errRofs
+	}
+	dir, status := c.currentDir()
+	if status != nfs4OK {
+		return status
+	}
+	before := s.changeAttr(dir)
+	p := c.childPath(name)
+	fullPath := path.Join(p...)
+	if _, err := s.vfs.Stat(fullPath); err == nil {
+		return nfs4errExist
+	}
+	var (
+		node vfs.Node
+		err  error
+	)
+	switch typ {
+	case nf4Dir:
+		node, err = dir.Mkdir(name)
+	case nf4Lnk:
+		if !s.vfs.Opt.Links {
+			return nfs4errNotsupp
+		}
+		node, err = s.vfs.CreateSymlink(linkData, fullPath)
+	default:
+		return nfs4errBadtype
+	}
+	if err != nil {
+		return nfs4Status(err)
+	}
+	s.mu.Lock()
+	s.dirChanged(dir.Inode())
+	s.mu.Unlock()
+	attrsSet, status := s.setAttrs(node, attrs)
+	if status != nfs4OK {
+		return status
+	}
+	writeChangeInfo(res, before, s.changeAttr(dir))
+	res.Bitmap(attrsSet)
+	c.setFH(p)
+	return nfs4OK
+}
+
+// invalidateHandle invalidates the handle for p after it has been
+// removed or renamed
+func (s *server4) invalidateHandle(p []string) {
+	err := s.h.InvalidateHandle(s.h.billyFS, s.h.ToHandle(s.h.billyFS, p))
+	if err != nil {
+		fs.Debugf("nfs", "Failed to invalidate handle for %q: %v", path.Join(p...), err)
+	}
+}
+
+// opRemoveFn runs REMOVE
+func opRemoveFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	name, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	if s.readOnly {
+		return nfs4errRofs
+	}
+	dir, status := c.currentDir()
+	if status != nfs4OK {
+		return status
+	}
+	before := s.changeAttr(dir)
+	p := c.childPath(name)
+	err := s.vfs.Remove(path.Join(p...))
+	if err != nil {
+		return nfs4Status(err)
+	}
+	s.invalidateHandle(p)
+	s.mu.Lock()
+	s.dirChanged(dir.Inode())
+	s.mu.Unlock()
+	writeChangeInfo(res, before, s.changeAttr(dir))
+	return nfs4OK
+}
+
+// opRenameFn runs RENAME from the saved directory to the current one
+func opRenameFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	oldName, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	newName, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	if c.savedFH == nil {
+		return nfs4errNofilehandle
+	}
+	s := c.s
+	if s.readOnly {
+		return nfs4errRofs
+	}
+	dstDir, status := c.currentDir()
+	if status != nfs4OK {
+		return status
+	}
+	node, err := s.vfs.Stat(path.Join(c.savedPath...))
+	if err != nil {
+		return nfs4errStale
+	}
+	srcDir, ok := node.(*vfs.Dir)
+	if !ok {
+		return nfs4errNotdir
+	}
+	srcBefore, dstBefore := s.changeAttr(srcDir), s.changeAttr(dstDir)
+	oldPath := append(slices.Clone(c.savedPath), oldName)
+	newPath := c.childPath(newName)
+	if path.Join(oldPath...) != path.Join(newPath...) {
+		err = s.vfs.Rename(path.Join(oldPath...), path.Join(newPath...))
+		if err != nil {
+			return nfs4Status(err)
+		}
+		s.invalidateHandle(oldPath)
+		s.mu.Lock()
+		s.dirChanged(srcDir.Inode())
+		if dstDir != srcDir {
+			s.dirChanged(dstDir.Inode())
+		}
+		s.mu.Unlock()
+	}
+	writeChangeInfo(res, srcBefore, s.changeAttr(srcDir))
+	writeChangeInfo(res, dstBefore, s.changeAttr(dstDir))
+	return nfs4OK
+}
+
+// opSetattrFn runs SETATTR
+//
+// The reply always has the attributes which were set, even on error.
+func opSetattrFn(c *compoundState, args *xdrReader, res *xdrWriter) (status nfsstat4) {
+	var set bitmap4
+	defer func() {
+		res.Bitmap(set)
+	}()
+	_ = args.Stateid()
+	attrs, status := readSetAttrs(args, nil)
+	if status != nfs4OK {
+		return status
+	}
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	if c.s.readOnly && attrs.mask != nil {
+		return nfs4errRofs
+	}
+	set, status = c.s.setAttrs(node, attrs)
+	return status
+}
+
+// opReadlinkFn runs READLINK
+func opReadlinkFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	node, status := c.current()
+	if status != nfs4OK {
+		return status
+	}
+	if fileType(node) != nf4Lnk {
+		return nfs4errInval
+	}
+	target, err := c.s.vfs.Readlink(c.currentPath())
+	if err != nil {
+		return nfs4Status(err)
+	}
+	res.String(target)
+	return nfs4OK
+}
+
+// writeSecinfo writes the security flavors the server accepts
+func writeSecinfo(res *xdrWriter) {
+	res.Uint32(2)
+	res.Uint32(rpcAuthSys)
+	res.Uint32(rpcAuthNone)
+}
+
+// opSecinfoFn runs SECINFO which consumes the current filehandle
+func opSecinfoFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	name, status := readName(args)
+	if status != nfs4OK {
+		return status
+	}
+	if _, status = c.currentDir(); status != nfs4OK {
+		return status
+	}
+	if _, err := c.s.vfs.Stat(path.Join(c.childPath(name)...)); err != nil {
+		return nfs4Status(err)
+	}
+	writeSecinfo(res)
+	c.fh, c.path = nil, nil
+	return nfs4OK
+}
+
+// opSecinfoNoNameFn runs SECINFO_NO_NAME which consumes the current
+// filehandle
+func opSecinfoNoNameFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint32() // style
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	if c.fh == nil {
+		return nfs4errNofilehandle
+	}
+	writeSecinfo(res)
+	c.fh, c.path = nil, nil
+	return nfs4OK
+}
+
+// writeLockDenied writes a LOCK4denied for the conflicting lock
+func writeLockDenied(res *xdrWriter, l *byteLock) {
+	res.Uint64(l.start)
+	res.Uint64(lockLength(l.start, l.end))
+	if l.write {
+		res.Uint32(writeLT)
+	} else {
+		res.Uint32(readLT)
+	}
+	res.Uint64(l.owner.clientID)
+	res.String(l.owner.owner)
+}
+
+// isWriteLock checks the lock type returning whether it is a write
+// lock
+func isWriteLock(lockType uint32) (write bool, status nfsstat4) {
+	switch lockType {
+	case readLT, readwLT:
+		return false, nfs4OK
+	case writeLT, writewLT:
+		return true, nfs4OK
+	}
+	return false, nfs4errInval
+}
+
+// lockFile returns the current file for a lock operation
+func (c *compoundState) lockFile() (vfs.Node, nfsstat4) {
+	node, status := c.current()
+	if status != nfs4OK {
+		return nil, status
+	}
+	if fileType(node) == nf4Dir {
+		return nil, nfs4errIsdir
+	}
+	return node, nfs4OK
+}
+
+// opLockFn runs LOCK
+//
+// Blocking locks are treated as non-blocking ones and the client
+// polls for them.
+func opLockFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	lockType := args.Uint32()
+	_ = args.Bool() // reclaim
+	offset := args.Uint64()
+	length := args.Uint64()
+	newOwner := args.Bool()
+	var (
+		openSID, lockSID stateid4
+		ownerName        string
+	)
+	if newOwner {
+		_ = args.Uint32() // open_seqid
+		openSID = args.Stateid()
+		_ = args.Uint32() // lock_seqid
+		_ = args.Uint64() // clientid
+		ownerName = args.String(1024)
+	} else {
+		lockSID = args.Stateid()
+		_ = args.Uint32() // lock_seqid
+	}
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	write, status := isWriteLock(lockType)
+	if status != nfs4OK {
+		return status
+	}
+	start, end, status := lockRange(offset, length)
+	if status != nfs4OK {
+		return status
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	node, status := c.lockFile()
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var lockState *nfs4State
+	if newOwner {
+		open, status := c.openState(openSID)
+		if status != nfs4OK {
+			return status
+		}
+		if open.file.inode != node.Inode() {
+			return nfs4errBadStateid
+		}
+		lockState = open.locks[ownerName]
+		if lockState == nil {
+			lockState = s.newState(client, open.file, ownerName)
+			lockState.open = open
+			open.locks[ownerName] = lockState
+			defer func() {
+				// Don't keep a new lock state if the lock failed
+				if status != nfs4OK {
+					delete(open.locks, ownerName)
+					s.removeState(lockState)
+				}
+			}()
+		} else {
+			lockState.bumpSeqid()
+		}
+	} else {
+		sid, status := c.resolveStateid(lockSID)
+		if status != nfs4OK {
+			return status
+		}
+		lockState, status = s.lookupState(client, sid)
+		if status != nfs4OK {
+			return status
+		}
+		if !lockState.isLock() || lockState.file.inode != node.Inode() {
+			return nfs4errBadStateid
+		}
+		lockState.bumpSeqid()
+	}
+	if write && lockState.open.access&openShareAccessWrite == 0 {
+		status = nfs4errOpenmode
+		return status
+	}
+	owner := lockState.lockOwner()
+	if l := lockState.file.conflict(owner, write, start, end); l != nil {
+		writeLockDenied(res, l)
+		status = nfs4errDenied
+		return status
+	}
+	lockState.file.lock(owner, write, start, end)
+	res.Stateid(lockState.sid)
+	c.setStateid(lockState.sid)
+	return nfs4OK
+}
+
+// opLocktFn runs LOCKT
+func opLocktFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	lockType := args.Uint32()
+	offset := args.Uint64()
+	length := args.Uint64()
+	_ = args.Uint64() // clientid comes from the session in NFSv4.1
+	ownerName := args.String(1024)
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	write, status := isWriteLock(lockType)
+	if status != nfs4OK {
+		return status
+	}
+	start, end, status := lockRange(offset, length)
+	if status != nfs4OK {
+		return status
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	node, status := c.lockFile()
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.files[node.Inode()]
+	if f == nil {
+		return nfs4OK
+	}
+	if l := f.conflict(lockOwner{clientID: client.id, owner: ownerName}, write, start, end); l != nil {
+		writeLockDenied(res, l)
+		return nfs4errDenied
+	}
+	return nfs4OK
+}
+
+// opLockuFn runs LOCKU
+func opLockuFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint32() // locktype
+	_ = args.Uint32() // seqid
+	sid := args.Stateid()
+	offset := args.Uint64()
+	length := args.Uint64()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	start, end, status := lockRange(offset, length)
+	if status != nfs4OK {
+		return status
+	}
+	sid, status = c.resolveStateid(sid)
+	if status != nfs4OK {
+		return status
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lockState, status := s.lookupState(client, sid)
+	if status != nfs4OK {
+		return status
+	}
+	if !lockState.isLock() {
+		return nfs4errBadStateid
+	}
+	lockState.file.unlock(lockState.lockOwner(), start, end)
+	lockState.bumpSeqid()
+	res.Stateid(lockState.sid)
+	c.setStateid(lockState.sid)
+	return nfs4OK
+}
+
+// opDelegreturnFn runs DELEGRETURN
+//
+// The server never hands out delegations so there are none to return.
+func opDelegreturnFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Stateid()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	return nfs4errBadStateid
+}
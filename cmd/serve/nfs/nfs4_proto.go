@@ -0,0 +1,308 @@
+//go:build unix
+
+package nfs
+
+// Constants from the NFSv4.1 protocol, RFC 8881
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rclone/rclone/vfs"
+)
+
+// RPC constants
+const (
+	rpcVersion     = 2
+	rpcCall        = 0
+	rpcReply       = 1
+	rpcMsgAccepted = 0
+	rpcMsgDenied   = 1
+
+	// accept_stat
+	rpcSuccess      = 0
+	rpcProgUnavail  = 1
+	rpcProgMismatch = 2
+	rpcProcUnavail  = 3
+	rpcGarbageArgs  = 4
+
+	// reject_stat
+	rpcMismatch  = 0
+	rpcAuthError = 1
+
+	// auth_stat
+	rpcAuthTooWeak = 5
+
+	// auth_flavor
+	rpcAuthNone = 0
+	rpcAuthSys  = 1
+	rpcAuthGSS  = 6
+
+	nfs4Program      = 100003
+	nfs4Version      = 4
+	nfs4ProcNull     = 0
+	nfs4ProcCompound = 1
+)
+
+// nfsstat4 is an NFSv4 status code
+type nfsstat4 uint32
+
+// Status codes
+const (
+	nfs4OK                   nfsstat4 = 0
+	nfs4errNoent             nfsstat4 = 2
+	nfs4errIO                nfsstat4 = 5
+	nfs4errAccess            nfsstat4 = 13
+	nfs4errExist             nfsstat4 = 17
+	nfs4errNotdir            nfsstat4 = 20
+	nfs4errIsdir             nfsstat4 = 21
+	nfs4errInval             nfsstat4 = 22
+	nfs4errFbig              nfsstat4 = 27
+	nfs4errNospc             nfsstat4 = 28
+	nfs4errRofs              nfsstat4 = 30
+	nfs4errNametoolong       nfsstat4 = 63
+	nfs4errNotempty          nfsstat4 = 66
+	nfs4errStale             nfsstat4 = 70
+	nfs4errBadCookie         nfsstat4 = 10003
+	nfs4errNotsupp           nfsstat4 = 10004
+	nfs4errToosmall          nfsstat4 = 10005
+	nfs4errBadtype           nfsstat4 = 10007
+	nfs4errDelay             nfsstat4 = 10008
+	nfs4errSame              nfsstat4 = 10009
+	nfs4errDenied            nfsstat4 = 10010
+	nfs4errShareDenied       nfsstat4 = 10015
+	nfs4errNofilehandle      nfsstat4 = 10020
+	nfs4errMinorVersMismatch nfsstat4 = 10021
+	nfs4errStaleClientid     nfsstat4 = 10022
+	nfs4errOldStateid        nfsstat4 = 10024
+	nfs4errBadStateid        nfsstat4 = 10025
+	nfs4errNotSame           nfsstat4 = 10027
+	nfs4errSymlink           nfsstat4 = 10029
+	nfs4errRestorefh         nfsstat4 = 10030
+	nfs4errAttrnotsupp       nfsstat4 = 10032
+	nfs4errNoGrace           nfsstat4 = 10033
+	nfs4errBadxdr            nfsstat4 = 10036
+	nfs4errLocksHeld         nfsstat4 = 10037
+	nfs4errOpenmode          nfsstat4 = 10038
+	nfs4errBadname           nfsstat4 = 10041
+	nfs4errOpIllegal         nfsstat4 = 10044
+	nfs4errBadsession        nfsstat4 = 10052
+	nfs4errBadslot           nfsstat4 = 10053
+	nfs4errCompleteAlready   nfsstat4 = 10054
+	nfs4errSeqMisordered     nfsstat4 = 10063
+	nfs4errSequencePos       nfsstat4 = 10064
+	nfs4errRetryUncachedRep  nfsstat4 = 10068
+	nfs4errTooManyOps        nfsstat4 = 10070
+	nfs4errOpNotInSession    nfsstat4 = 10071
+	nfs4errClientidBusy      nfsstat4 = 10074
+	nfs4errNotOnlyOp         nfsstat4 = 10081
+)
+
+// Operation numbers
+const (
+	opAccess            = 3
+	opClose             = 4
+	opCommit            = 5
+	opCreate            = 6
+	opDelegreturn       = 8
+	opGetattr           = 9
+	opGetfh             = 10
+	opLock              = 12
+	opLockt             = 13
+	opLocku             = 14
+	opLookup            = 15
+	opLookupp           = 16
+	opNverify           = 17
+	opOpen              = 18
+	opOpenDowngrade     = 21
+	opPutfh             = 22
+	opPutpubfh          = 23
+	opPutrootfh         = 24
+	opRead              = 25
+	opReaddir           = 26
+	opReadlink          = 27
+	opRemove            = 28
+	opRename            = 29
+	opRestorefh         = 31
+	opSavefh            = 32
+	opSecinfo           = 33
+	opSetattr           = 34
+	opVerify            = 37
+	opWrite             = 38
+	opBackchannelCtl    = 40
+	opBindConnToSession = 41
+	opExchangeID        = 42
+	opCreateSession     = 43
+	opDestroySession    = 44
+	opFreeStateid       = 45
+	opSecinfoNoName     = 52
+	opSequence          = 53
+	opTestStateid       = 55
+	opDestroyClientid   = 57
+	opReclaimComplete   = 58
+	opIllegal           = 10044
+)
+
+// File types
+const (
+	nf4Reg = 1
+	nf4Dir = 2
+	nf4Blk = 3
+	nf4Chr = 4
+	nf4Lnk = 5
+)
+
+// Attribute numbers
+const (
+	attrSupportedAttrs    = 0
+	attrType              = 1
+	attrFhExpireType      = 2
+	attrChange            = 3
+	attrSize              = 4
+	attrLinkSupport       = 5
+	attrSymlinkSupport    = 6
+	attrNamedAttr         = 7
+	attrFsid              = 8
+	attrUniqueHandles     = 9
+	attrLeaseTime         = 10
+	attrRdattrError       = 11
+	attrAclsupport        = 13
+	attrCansettime        = 15
+	attrCaseInsensitive   = 16
+	attrCasePreserving    = 17
+	attrChownRestricted   = 18
+	attrFilehandle        = 19
+	attrFileid            = 20
+	attrFilesAvail        = 21
+	attrFilesFree         = 22
+	attrFilesTotal        = 23
+	attrHomogeneous       = 26
+	attrMaxfilesize       = 27
+	attrMaxlink           = 28
+	attrMaxname           = 29
+	attrMaxread           = 30
+	attrMaxwrite          = 31
+	attrMode              = 33
+	attrNoTrunc           = 34
+	attrNumlinks          = 35
+	attrOwner             = 36
+	attrOwnerGroup        = 37
+	attrRawdev            = 41
+	attrSpaceAvail        = 42
+	attrSpaceFree         = 43
+	attrSpaceTotal        = 44
+	attrSpaceUsed         = 45
+	attrTimeAccess        = 47
+	attrTimeAccessSet     = 48
+	attrTimeDelta         = 51
+	attrTimeMetadata      = 52
+	attrTimeModify        = 53
+	attrTimeModifySet     = 54
+	attrMountedOnFileid   = 55
+	attrSuppattrExclcreat = 75
+)
+
+// ACCESS bits
+const (
+	access4Read    = 0x01
+	access4Lookup  = 0x02
+	access4Modify  = 0x04
+	access4Extend  = 0x08
+	access4Delete  = 0x10
+	access4Execute = 0x20
+)
+
+// OPEN constants
+const (
+	openShareAccessWrite = 2
+	openShareAccessBoth  = 3
+	openShareDenyBoth    = 3
+
+	open4Create = 1
+
+	createUnchecked  = 0
+	createGuarded    = 1
+	createExclusive  = 2
+	createExclusive1 = 3
+
+	claimNull     = 0
+	claimPrevious = 1
+	claimFH       = 4
+
+	open4ResultLocktypePosix = 0x4
+
+	openDelegateNone = 0
+)
+
+// Lock types
+const (
+	readLT   = 1
+	writeLT  = 2
+	readwLT  = 3
+	writewLT = 4
+)
+
+// WRITE stable_how4
+const (
+	unstable4 = 0
+	fileSync4 = 2
+)
+
+// Session and client id constants
+const (
+	exchgid4FlagUseNonPNFS = 0x00010000
+	exchgid4FlagConfirmedR = 0x80000000
+	exchgid4FlagMask       = 0x40070103
+
+	sp4None     = 0
+	sp4MachCred = 1
+
+	cdfs4Fore = 0x1
+)
+
+// stateid4 identifies open and lock state
+type stateid4 struct {
+	seqid uint32
+	other [12]byte
+}
+
+// Special stateids
+var (
+	anonymousStateid = stateid4{}
+	bypassStateid    = stateid4{seqid: 0xFFFFFFFF, other: [12]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}}
+	currentStateid   = stateid4{seqid: 1}
+	invalidStateid   = stateid4{seqid: 0xFFFFFFFF}
+)
+
+// nfs4Status converts an error from the VFS into an NFSv4 status
+func nfs4Status(err error) nfsstat4 {
+	var vfsErr vfs.Error
+	switch {
+	case err == nil:
+		return nfs4OK
+	case errors.Is(err, os.ErrNotExist):
+		return nfs4errNoent
+	case errors.Is(err, os.ErrExist):
+		return nfs4errExist
+	case errors.Is(err, os.ErrPermission):
+		return nfs4errAccess
+	case errors.Is(err, os.ErrInvalid):
+		return nfs4errInval
+	case errors.As(err, &vfsErr):
+		switch vfsErr {
+		case vfs.ENOTEMPTY:
+			return nfs4errNotempty
+		case vfs.EROFS:
+			return nfs4errRofs
+		case vfs.ENOSYS:
+			return nfs4errNotsupp
+		case vfs.ESPIPE, vfs.EBADF:
+			return nfs4errInval
+		case vfs.ELOOP:
+			return nfs4errSymlink
+		case vfs.ENOSPC:
+			return nfs4errNospc
+		}
+	}
+	return nfs4errIO
+}
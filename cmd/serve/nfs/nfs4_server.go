@@ -0,0 +1,489 @@
+//go:build unix
+
+package nfs
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+)
+
+// Limits of the NFSv4.1 server
+const (
+	nfs4LeaseTime      = 90 * time.Second // how long clients keep state without renewing
+	nfs4MaxSlots       = 64               // maximum number of slots in a session
+	nfs4MaxOps         = 64               // maximum number of operations in a compound
+	nfs4MaxIO          = 1 << 20          // maximum size of reads and writes
+	nfs4MaxRequestSize = nfs4MaxIO + 64*1024
+	nfs4MaxRecordSize  = 2 * nfs4MaxRequestSize
+)
+
+// server4 is an NFSv4.1 server
+//
+// It serves the VFS in h and uses the handle cache in h so the file
+// handles are the same as those used by the NFSv3 server.
+type server4 struct {
+	h            *Handler
+	vfs          *vfs.VFS
+	readOnly     bool
+	bootVerifier [8]byte // changes each time the server starts
+	serverOwner  []byte  // identifies this server to clients
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	state4                  // clients, sessions, opens and locks
+	closing  chan struct{}  // closed when the server is shutting down
+	wg       sync.WaitGroup // for the connections
+	shutdown bool
+}
+
+// newServer4 makes a new NFSv4.1 server for the handler
+func newServer4(h *Handler) (s *server4) {
+	s = &server4{
+		h:        h,
+		vfs:      h.vfs,
+		readOnly: h.vfs.Opt.ReadOnly || h.vfs.Opt.CacheMode == vfscommon.CacheModeOff,
+		conns:    map[net.Conn]struct{}{},
+		closing:  make(chan struct{}),
+	}
+	_, _ = rand.Read(s.bootVerifier[:])
+	s.serverOwner = fmt.Appendf(nil, "rclone-%x", s.bootVerifier)
+	s.state4.init()
+	return s
+}
+
+// serve accepts connections from l until it is closed
+func (s *server4) serve(l net.Listener) error {
+	go s.expireClients()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			shutdown := s.shutdown
+			s.mu.Unlock()
+			if shutdown {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.shutdown {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// close the connections and release all the state
+func (s *server4) close() {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return
+	}
+	s.shutdown = true
+	close(s.closing)
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	var handles []vfs.Handle
+	s.mu.Lock()
+	for _, c := range s.clients {
+		handles = append(handles, s.destroyClient(c)...)
+	}
+	s.mu.Unlock()
+	closeHandles(handles)
+}
+
+// expireClients removes the state of clients which haven't renewed
+// their lease until the server is closed
+func (s *server4) expireClients() {
+	ticker := time.NewTicker(nfs4LeaseTime / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+		// Give the clients twice the lease time to allow for
+		// clocks and networks
+		expired := time.Now().Add(-2 * nfs4LeaseTime)
+		var handles []vfs.Handle
+		s.mu.Lock()
+		for _, c := range s.clients {
+			if c.lastRenew.Before(expired) {
+				fs.Infof("nfs", "Client %q lease expired", c.ownerID)
+				handles = append(handles, s.destroyClient(c)...)
+			}
+		}
+		s.mu.Unlock()
+		closeHandles(handles)
+	}
+}
+
+// serveConn reads RPC calls from conn and replies to them
+//
+// Calls are processed concurrently as clients use one connection
+// for all the slots of a session.
+func (s *server4) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+	fs.Debugf("nfs", "New connection from %v", conn.RemoteAddr())
+	var (
+		writeMu sync.Mutex
+		calls   sync.WaitGroup
+		limit   = make(chan struct{}, nfs4MaxSlots)
+		in      = bufio.NewReaderSize(conn, 64*1024)
+	)
+	defer calls.Wait()
+	for {
+		record, err := readRecord(in)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				fs.Debugf("nfs", "Closing connection from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		limit <- struct{}{}
+		calls.Add(1)
+		go func() {
+			defer calls.Done()
+			defer func() { <-limit }()
+			reply := s.handleCall(record)
+			if reply == nil {
+				return
+			}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			err := writeRecord(conn, reply)
+			if err != nil {
+				fs.Debugf("nfs", "Failed to write reply to %v: %v", conn.RemoteAddr(), err)
+				_ = conn.Close()
+			}
+		}()
+	}
+}
+
+// readRecord reads an RPC record made of one or more fragments
+func readRecord(in io.Reader) (record []byte, err error) {
+	var header [4]byte
+	for {
+		_, err = io.ReadFull(in, header[:])
+		if err != nil {
+			return nil, err
+		}
+		fragment := binary.BigEndian.Uint32(header[:])
+		last := fragment&(1<<31) != 0
+		size := int(fragment &^ (1 << 31))
+		if len(record)+size > nfs4MaxRecordSize {
+			return nil, fmt.Errorf("RPC record too large: %d bytes", len(record)+size)
+		}
+		start := len(record)
+		record = append(record, make([]byte, size)...)
+		_, err = io.ReadFull(in, record[start:])
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return record, nil
+		}
+	}
+}
+
+// writeRecord writes reply as a single fragment RPC record
+func writeRecord(out io.Writer, reply []byte) error {
+	buf := make([]byte, 4, 4+len(reply))
+	binary.BigEndian.PutUint32(buf, uint32(len(reply))|1<<31)
+	buf = append(buf, reply...)
+	_, err := out.Write(buf)
+	return err
+}
+
+// handleCall decodes the RPC call in record and returns the reply
+//
+// It returns nil if there should be no reply.
+func (s *server4) handleCall(record []byte) []byte {
+	r := newXDRReader(record)
+	xid := r.Uint32()
+	msgType := r.Uint32()
+	if r.err != nil || msgType != rpcCall {
+		return nil
+	}
+	rpcvers := r.Uint32()
+	prog := r.Uint32()
+	vers := r.Uint32()
+	proc := r.Uint32()
+	credFlavor := r.Uint32()
+	_ = r.Opaque(400) // credentials
+	_ = r.Uint32()    // verifier flavor
+	_ = r.Opaque(400) // verifier
+	if r.err != nil {
+		return nil
+	}
+	w := &xdrWriter{}
+	w.Uint32(xid)
+	w.Uint32(rpcReply)
+	if rpcvers != rpcVersion {
+		w.Uint32(rpcMsgDenied)
+		w.Uint32(rpcMismatch)
+		w.Uint32(rpcVersion)
+		w.Uint32(rpcVersion)
+		return w.Bytes()
+	}
+	if credFlavor != rpcAuthNone && credFlavor != rpcAuthSys {
+		w.Uint32(rpcMsgDenied)
+		w.Uint32(rpcAuthError)
+		w.Uint32(rpcAuthTooWeak)
+		return w.Bytes()
+	}
+	w.Uint32(rpcMsgAccepted)
+	w.Uint32(rpcAuthNone) // verifier
+	w.Opaque(nil)
+	switch {
+	case prog != nfs4Program:
+		w.Uint32(rpcProgUnavail)
+	case vers != nfs4Version:
+		w.Uint32(rpcProgMismatch)
+		w.Uint32(nfs4Version)
+		w.Uint32(nfs4Version)
+	case proc == nfs4ProcNull:
+		w.Uint32(rpcSuccess)
+	case proc == nfs4ProcCompound:
+		reply, ok := s.compound(r)
+		if !ok {
+			w.Uint32(rpcGarbageArgs)
+		} else {
+			w.Uint32(rpcSuccess)
+			w.buf = append(w.buf, reply...)
+		}
+	default:
+		w.Uint32(rpcProcUnavail)
+	}
+	return w.Bytes()
+}
+
+// compoundState is the state kept while running the operations in a
+// COMPOUND
+type compoundState struct {
+	s         *server4
+	session   *nfs4Session
+	slot      *nfs4Slot
+	fh        []byte   // current filehandle, nil if not set
+	path      []string // path of the current filehandle
+	savedFH   []byte   // saved filehandle
+	savedPath []string
+	stateid   stateid4 // current stateid
+	haveSID   bool     // set if stateid is valid
+	savedSID  stateid4 // saved stateid
+	haveSaved bool     // set if savedSID is valid
+}
+
+// opFn runs an operation decoding the arguments from args and
+// writing the result body to res
+//
+// The body is only written to the reply if the status returned is
+// nfs4OK unless the operation needs a body with errors too.
+type opFn func(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4
+
+// ops are the operations the server supports
+var ops map[uint32]opFn
+
+// errorBodyOps are operations which return a body with errors
+var errorBodyOps = map[uint32]bool{
+	opSetattr: true,
+	opLock:    true,
+	opLockt:   true,
+}
+
+// sessionlessOps may be sent as the only operation in a COMPOUND
+// without a SEQUENCE first
+var sessionlessOps = map[uint32]bool{
+	opExchangeID:        true,
+	opCreateSession:     true,
+	opDestroySession:    true,
+	opBindConnToSession: true,
+	opDestroyClientid:   true,
+}
+
+// compound runs the COMPOUND procedure returning its reply or false if
+// the arguments couldn't be decoded
+func (s *server4) compound(r *xdrReader) (reply []byte, ok bool) {
+	tag := r.Opaque(1024)
+	minorVersion := r.Uint32()
+	nops := r.Uint32()
+	if r.err != nil {
+		return nil, false
+	}
+	results := &xdrWriter{}
+	status := nfs4OK
+	nresults := uint32(0)
+	c := &compoundState{s: s}
+	if minorVersion != 1 {
+		status = nfs4errMinorVersMismatch
+		nops = 0
+	}
+	for i := range nops {
+		op := r.Uint32()
+		if r.err != nil {
+			status = nfs4errBadxdr
+			break
+		}
+		res := &xdrWriter{}
+		if i == 0 && op == opSequence {
+			var cached []byte
+			cached, status = c.sequence(r, res, nops)
+			if cached != nil {
+				// This is a retry of a request in the reply cache
+				return cached, true
+			}
+		} else {
+			op, status = c.run(i, nops, op, r, res)
+		}
+		results.Uint32(op)
+		results.Uint32(uint32(status))
+		if status == nfs4OK || errorBodyOps[op] {
+			results.buf = append(results.buf, res.Bytes()...)
+		}
+		nresults++
+		if status != nfs4OK {
+			break
+		}
+	}
+	w := &xdrWriter{}
+	w.Uint32(uint32(status))
+	w.Opaque(tag)
+	w.Uint32(nresults)
+	w.buf = append(w.buf, results.Bytes()...)
+	c.saveReply(w.Bytes())
+	return w.Bytes(), true
+}
+
+// run the operation op which is number i of nops in the compound
+//
+// It returns the operation number to put in the reply which is
+// changed to OP_ILLEGAL for unknown operations.
+func (c *compoundState) run(i, nops, op uint32, args *xdrReader, res *xdrWriter) (uint32, nfsstat4) {
+	fn, found := ops[op]
+	switch {
+	case op < opAccess || op > opReclaimComplete:
+		return opIllegal, nfs4errOpIllegal
+	case op == opSequence:
+		return op, nfs4errSequencePos
+	case i == 0 && !sessionlessOps[op]:
+		return op, nfs4errOpNotInSession
+	case i == 0 && nops > 1:
+		return op, nfs4errNotOnlyOp
+	case !found:
+		return op, nfs4errNotsupp
+	}
+	status := fn(c, args, res)
+	if args.err != nil && status == nfs4OK {
+		status = nfs4errBadxdr
+	}
+	return op, status
+}
+
+// setFH sets the current filehandle to the one for path p
+//
+// This clears the current stateid.
+func (c *compoundState) setFH(p []string) {
+	c.path = p
+	c.fh = c.s.h.ToHandle(c.s.h.billyFS, p)
+	c.haveSID = false
+}
+
+// currentPath returns the VFS path of the current filehandle
+func (c *compoundState) currentPath() string {
+	return path.Join(c.path...)
+}
+
+// current returns the node for the current filehandle
+func (c *compoundState) current() (vfs.Node, nfsstat4) {
+	if c.fh == nil {
+		return nil, nfs4errNofilehandle
+	}
+	node, err := c.s.vfs.Stat(c.currentPath())
+	if errors.Is(err, vfs.ENOENT) {
+		return nil, nfs4errStale
+	} else if err != nil {
+		return nil, nfs4Status(err)
+	}
+	return node, nfs4OK
+}
+
+// currentDir returns the node for the current filehandle which must
+// be a directory
+func (c *compoundState) currentDir() (*vfs.Dir, nfsstat4) {
+	node, status := c.current()
+	if status != nfs4OK {
+		return nil, status
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		if file, ok := node.(*vfs.File); ok && file.IsSymlink() {
+			return nil, nfs4errSymlink
+		}
+		return nil, nfs4errNotdir
+	}
+	return dir, nfs4OK
+}
+
+// setStateid sets the current stateid
+func (c *compoundState) setStateid(sid stateid4) {
+	c.stateid = sid
+	c.haveSID = true
+}
+
+// resolveStateid replaces the special current stateid with the
+// current stateid
+func (c *compoundState) resolveStateid(sid stateid4) (stateid4, nfsstat4) {
+	if sid == currentStateid {
+		if !c.haveSID {
+			return sid, nfs4errBadStateid
+		}
+		return c.stateid, nfs4OK
+	}
+	return sid, nfs4OK
+}
+
+// checkName checks a component name is valid
+func checkName(name string) nfsstat4 {
+	switch {
+	case name == "":
+		return nfs4errInval
+	case name == "." || name == "..":
+		return nfs4errBadname
+	case len(name) > 255:
+		return nfs4errNametoolong
+	}
+	for i := range len(name) {
+		if name[i] == '/' || name[i] == 0 {
+			return nfs4errBadname
+		}
+	}
+	return nfs4OK
+}
@@ -0,0 +1,421 @@
+//go:build unix
+
+package nfs
+
+import (
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// Client, session and stateid operations
+
+// sequence runs the SEQUENCE operation which must start each COMPOUND
+// in a session
+//
+// If the request is a retry of one in the reply cache it returns the
+// cached reply.
+func (c *compoundState) sequence(args *xdrReader, res *xdrWriter, nops uint32) (cached []byte, status nfsstat4) {
+	var id [16]byte
+	copy(id[:], args.Fixed(16))
+	seqid := args.Uint32()
+	slotID := args.Uint32()
+	_ = args.Uint32() // highest_slotid
+	cacheThis := args.Bool()
+	if args.err != nil {
+		return nil, nfs4errBadxdr
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.sessions[id]
+	if sess == nil {
+		return nil, nfs4errBadsession
+	}
+	if slotID >= uint32(len(sess.slots)) {
+		return nil, nfs4errBadslot
+	}
+	slot := &sess.slots[slotID]
+	switch {
+	case slot.inUse:
+		return nil, nfs4errDelay
+	case seqid == slot.seqid:
+		if slot.reply == nil {
+			return nil, nfs4errRetryUncachedRep
+		}
+		return slot.reply, nfs4OK
+	case seqid != slot.seqid+1:
+		return nil, nfs4errSeqMisordered
+	case nops > sess.maxOps:
+		return nil, nfs4errTooManyOps
+	}
+	slot.seqid = seqid
+	slot.inUse = true
+	slot.cacheThis = cacheThis
+	slot.reply = nil
+	c.session = sess
+	c.slot = slot
+	sess.client.lastRenew = time.Now()
+
+	highest := uint32(len(sess.slots) - 1)
+	res.Fixed(id[:])
+	res.Uint32(seqid)
+	res.Uint32(slotID)
+	res.Uint32(highest) // highest_slotid
+	res.Uint32(highest) // target_highest_slotid
+	res.Uint32(0)       // status_flags
+	return nil, nfs4OK
+}
+
+// saveReply puts the reply in the slot's cache if requested and frees
+// the slot
+func (c *compoundState) saveReply(reply []byte) {
+	if c.slot == nil {
+		return
+	}
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	if c.slot.cacheThis {
+		c.slot.reply = reply
+	}
+	c.slot.inUse = false
+}
+
+// client returns the client of the session
+func (c *compoundState) client() (*nfs4Client, nfsstat4) {
+	if c.session == nil {
+		return nil, nfs4errOpNotInSession
+	}
+	return c.session.client, nfs4OK
+}
+
+// opExchangeIDFn runs EXCHANGE_ID
+func opExchangeIDFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	var verifier [8]byte
+	copy(verifier[:], args.Fixed(8))
+	ownerID := args.String(1024)
+	flags := args.Uint32()
+	how := args.Uint32()
+	switch how {
+	case sp4None:
+	case sp4MachCred:
+		// We accept this but don't offer state protection
+		_ = args.Bitmap() // spo_must_enforce
+		_ = args.Bitmap() // spo_must_allow
+	default:
+		return nfs4errNotsupp
+	}
+	if n := args.Uint32(); n > 1 { // client_impl_id
+		return nfs4errBadxdr
+	} else if n == 1 {
+		domain := args.String(1024)
+		name := args.String(1024)
+		_ = args.Int64()  // seconds
+		_ = args.Uint32() // nseconds
+		fs.Debugf("nfs", "Client %q implementation %q from %q", ownerID, name, domain)
+	}
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	if flags&^exchgid4FlagMask != 0 {
+		return nfs4errInval
+	}
+
+	s := c.s
+	s.mu.Lock()
+	client := s.owners[ownerID]
+	if client != nil && client.verifier != verifier {
+		// The client has rebooted so throw away its old state
+		fs.Debugf("nfs", "Client %q rebooted", ownerID)
+		defer closeHandles(s.destroyClient(client))
+		client = nil
+	}
+	if client == nil {
+		client = s.newClient(ownerID, verifier)
+	}
+	resFlags := uint32(exchgid4FlagUseNonPNFS)
+	if client.confirmed {
+		resFlags |= exchgid4FlagConfirmedR
+	}
+	res.Uint64(client.id)
+	res.Uint32(client.seq)
+	s.mu.Unlock()
+
+	res.Uint32(resFlags)
+	res.Uint32(sp4None)
+	res.Uint64(0) // server_owner.so_minor_id
+	res.Opaque(s.serverOwner)
+	res.Opaque(s.serverOwner) // server_scope
+	res.Uint32(1)             // server_impl_id
+	res.String("rclone.org")
+	res.String("rclone " + fs.Version)
+	res.Int64(0)
+	res.Uint32(0)
+	return nfs4OK
+}
+
+// channelAttrs are the channel_attrs4 of a session
+type channelAttrs struct {
+	headerPadSize        uint32
+	maxRequestSize       uint32
+	maxResponseSize      uint32
+	maxResponseSizeCache uint32
+	maxOperations        uint32
+	maxRequests          uint32
+}
+
+// readChannelAttrs reads a channel_attrs4
+func readChannelAttrs(args *xdrReader) (a channelAttrs) {
+	a.headerPadSize = args.Uint32()
+	a.maxRequestSize = args.Uint32()
+	a.maxResponseSize = args.Uint32()
+	a.maxResponseSizeCache = args.Uint32()
+	a.maxOperations = args.Uint32()
+	a.maxRequests = args.Uint32()
+	if n := args.Uint32(); n > 1 { // ca_rdma_ird
+		args.err = errBadXDR
+	} else if n == 1 {
+		_ = args.Uint32()
+	}
+	return a
+}
+
+// write a channel_attrs4
+func (a channelAttrs) write(res *xdrWriter) {
+	res.Uint32(a.headerPadSize)
+	res.Uint32(a.maxRequestSize)
+	res.Uint32(a.maxResponseSize)
+	res.Uint32(a.maxResponseSizeCache)
+	res.Uint32(a.maxOperations)
+	res.Uint32(a.maxRequests)
+	res.Uint32(0) // ca_rdma_ird
+}
+
+// readCallbackSecParms skips over the callback_sec_parms4 array
+func readCallbackSecParms(args *xdrReader) {
+	n := args.Uint32()
+	if n > 16 {
+		args.err = errBadXDR
+		return
+	}
+	for range n {
+		switch args.Uint32() {
+		case rpcAuthNone:
+		case rpcAuthSys:
+			_ = args.Uint32()    // stamp
+			_ = args.String(255) // machine name
+			_ = args.Uint32()    // uid
+			_ = args.Uint32()    // gid
+			if gids := args.Uint32(); gids > 16 {
+				args.err = errBadXDR
+			} else {
+				for range gids {
+					_ = args.Uint32()
+				}
+			}
+		case rpcAuthGSS:
+			_ = args.Uint32()     // service
+			_ = args.Opaque(1024) // handle from server
+			_ = args.Opaque(1024) // handle from client
+		default:
+			args.err = errBadXDR
+		}
+	}
+}
+
+// opCreateSessionFn runs CREATE_SESSION
+func opCreateSessionFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	clientID := args.Uint64()
+	seq := args.Uint32()
+	_ = args.Uint32() // flags
+	fore := readChannelAttrs(args)
+	back := readChannelAttrs(args)
+	_ = args.Uint32() // cb_program
+	readCallbackSecParms(args)
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client := s.clients[clientID]
+	switch {
+	case client == nil:
+		return nfs4errStaleClientid
+	case seq == client.seq-1 && client.sessionReply != nil:
+		// Replay of the last CREATE_SESSION
+		res.buf = append(res.buf, client.sessionReply...)
+		return nfs4OK
+	case seq != client.seq:
+		return nfs4errSeqMisordered
+	}
+
+	// Negotiate the fore channel attributes
+	fore.headerPadSize = 0
+	fore.maxRequestSize = min(fore.maxRequestSize, nfs4MaxRequestSize)
+	fore.maxResponseSize = min(fore.maxResponseSize, nfs4MaxRequestSize)
+	fore.maxResponseSizeCache = min(fore.maxResponseSizeCache, nfs4MaxRequestSize)
+	fore.maxOperations = min(fore.maxOperations, nfs4MaxOps)
+	fore.maxRequests = max(min(fore.maxRequests, nfs4MaxSlots), 1)
+	// We don't use the back channel but need to return something
+	back.headerPadSize = 0
+
+	sess := s.newSession(client, fore.maxRequests, fore.maxOperations)
+	client.confirmed = true
+	client.lastRenew = time.Now()
+
+	reply := &xdrWriter{}
+	reply.Fixed(sess.id[:])
+	reply.Uint32(seq)
+	reply.Uint32(0) // flags - no persistence or back channel
+	fore.write(reply)
+	back.write(reply)
+	client.sessionReply = reply.Bytes()
+	client.seq++
+	res.buf = append(res.buf, reply.Bytes()...)
+	return nfs4OK
+}
+
+// opDestroySessionFn runs DESTROY_SESSION
+func opDestroySessionFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	var id [16]byte
+	copy(id[:], args.Fixed(16))
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.sessions[id]
+	if sess == nil {
+		return nfs4errBadsession
+	}
+	delete(s.sessions, id)
+	delete(sess.client.sessions, id)
+	return nfs4OK
+}
+
+// opBindConnToSessionFn runs BIND_CONN_TO_SESSION
+func opBindConnToSessionFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	var id [16]byte
+	copy(id[:], args.Fixed(16))
+	_ = args.Uint32() // dir
+	_ = args.Bool()   // use_conn_in_rdma_mode
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	c.s.mu.Lock()
+	sess := c.s.sessions[id]
+	c.s.mu.Unlock()
+	if sess == nil {
+		return nfs4errBadsession
+	}
+	// Connections are only used for the fore channel
+	res.Fixed(id[:])
+	res.Uint32(cdfs4Fore)
+	res.Bool(false)
+	return nfs4OK
+}
+
+// opDestroyClientidFn runs DESTROY_CLIENTID
+func opDestroyClientidFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	clientID := args.Uint64()
+	if args.err != nil {
+		return nfs4errBadxdr
+	}
+	s := c.s
+	s.mu.Lock()
+	client := s.clients[clientID]
+	switch {
+	case client == nil:
+		s.mu.Unlock()
+		return nfs4errStaleClientid
+	case len(client.sessions) != 0:
+		s.mu.Unlock()
+		return nfs4errClientidBusy
+	}
+	handles := s.destroyClient(client)
+	s.mu.Unlock()
+	closeHandles(handles)
+	return nfs4OK
+}
+
+// opReclaimCompleteFn runs RECLAIM_COMPLETE
+//
+// There is no grace period as no state survives a restart so there is
+// nothing to reclaim.
+func opReclaimCompleteFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	oneFS := args.Bool()
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	if oneFS {
+		return nfs4OK
+	}
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	if client.reclaimComplete {
+		return nfs4errCompleteAlready
+	}
+	client.reclaimComplete = true
+	return nfs4OK
+}
+
+// opBackchannelCtlFn runs BACKCHANNEL_CTL
+func opBackchannelCtlFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	_ = args.Uint32() // cb_program
+	readCallbackSecParms(args)
+	_, status := c.client()
+	return status
+}
+
+// opFreeStateidFn runs FREE_STATEID
+func opFreeStateidFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	sid, status := c.resolveStateid(args.Stateid())
+	if status != nfs4OK {
+		return status
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, status := s.lookupState(client, sid)
+	switch {
+	case status != nfs4OK:
+		return status
+	case !state.isLock() || state.file.hasLocks(state.lockOwner()):
+		return nfs4errLocksHeld
+	}
+	delete(state.open.locks, state.owner)
+	s.removeState(state)
+	return nfs4OK
+}
+
+// opTestStateidFn runs TEST_STATEID
+func opTestStateidFn(c *compoundState, args *xdrReader, res *xdrWriter) nfsstat4 {
+	n := args.Uint32()
+	if n > 1024 {
+		return nfs4errBadxdr
+	}
+	sids := make([]stateid4, 0, n)
+	for range n {
+		sids = append(sids, args.Stateid())
+	}
+	client, status := c.client()
+	if status != nfs4OK {
+		return status
+	}
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	res.Uint32(n)
+	for _, sid := range sids {
+		_, status := c.s.lookupState(client, sid)
+		res.Uint32(uint32(status))
+	}
+	return nfs4OK
+}
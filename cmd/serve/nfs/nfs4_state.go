@@ -0,0 +1,409 @@
+//go:build unix
+
+package nfs
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/rclone/rclone/vfs"
+)
+
+// state4 holds the state of the NFSv4.1 clients
+//
+// It is protected by server4.mu
+type state4 struct {
+	s             *server4
+	clients       map[uint64]*nfs4Client    // by client id
+	owners        map[string]*nfs4Client    // by client owner
+	sessions      map[[16]byte]*nfs4Session // by session id
+	states        map[[12]byte]*nfs4State   // open and lock states by stateid.other
+	files         map[uint64]*nfs4File      // files with state by inode
+	dirChanges    map[uint64]uint64         // count of changes we made to directories by inode
+	exclVerifiers map[string][8]byte        // verifiers of exclusive creates by path
+	nextClientID  uint64
+	nextStateID   uint64
+}
+
+// nfs4Client is a client which has done EXCHANGE_ID
+type nfs4Client struct {
+	id              uint64
+	ownerID         string
+	verifier        [8]byte
+	confirmed       bool                      // set once CREATE_SESSION has been done
+	seq             uint32                    // next CREATE_SESSION sequence id
+	sessionReply    []byte                    // cached reply to last CREATE_SESSION
+	lastRenew       time.Time                 // last time the lease was renewed
+	sessions        map[[16]byte]*nfs4Session // sessions of this client
+	states          map[[12]byte]*nfs4State   // open and lock states of this client
+	reclaimComplete bool
+}
+
+// nfs4Session is a session created by CREATE_SESSION
+type nfs4Session struct {
+	id     [16]byte
+	client *nfs4Client
+	slots  []nfs4Slot
+	maxOps uint32
+}
+
+// nfs4Slot is a slot in a session's reply cache
+type nfs4Slot struct {
+	seqid     uint32
+	inUse     bool
+	cacheThis bool
+	reply     []byte // cached reply, nil if not cached
+}
+
+// lockOwner identifies the owner of byte-range locks
+type lockOwner struct {
+	clientID uint64
+	owner    string
+}
+
+// nfs4State is the state of an open or of the locks of a lock owner
+// within an open
+type nfs4State struct {
+	sid    stateid4
+	client *nfs4Client
+	file   *nfs4File
+	owner  string // open owner or lock owner
+
+	// Open states only
+	access   uint32                // OPEN4_SHARE_ACCESS_*
+	deny     uint32                // OPEN4_SHARE_DENY_*
+	handle   *openHandle           // open VFS handle
+	writable bool                  // set if handle is open for writing
+	locks    map[string]*nfs4State // lock states by lock owner
+
+	// Lock states only
+	open *nfs4State // the open this lock state belongs to
+}
+
+// isLock returns true if this is a lock state
+func (st *nfs4State) isLock() bool {
+	return st.open != nil
+}
+
+// lockOwner returns the owner of the locks of a lock state
+func (st *nfs4State) lockOwner() lockOwner {
+	return lockOwner{clientID: st.client.id, owner: st.owner}
+}
+
+// openHandle is the VFS handle of an open state
+//
+// READ, WRITE and COMMIT use the handle without holding s.mu so it is
+// only closed once the open has finished with it and the operations
+// using it have finished too. The fields are protected by s.mu.
+type openHandle struct {
+	vfs.Handle
+	users int  // number of operations using the handle
+	done  bool // set once the open has finished with the handle
+}
+
+// acquire marks the handle as in use by an operation
+//
+// Call with s.mu held.
+func (h *openHandle) acquire() {
+	h.users++
+}
+
+// release marks an operation as finished with the handle returning
+// the VFS handle if it should now be closed
+//
+// Call with s.mu held.
+func (h *openHandle) release() (handles []vfs.Handle) {
+	h.users--
+	return h.closeIfUnused()
+}
+
+// finish marks the open as finished with the handle returning the VFS
+// handle if it should now be closed
+//
+// Call with s.mu held.
+func (h *openHandle) finish() (handles []vfs.Handle) {
+	h.done = true
+	return h.closeIfUnused()
+}
+
+// closeIfUnused returns the VFS handle if nothing is using it
+func (h *openHandle) closeIfUnused() (handles []vfs.Handle) {
+	if h.done && h.users == 0 {
+		return []vfs.Handle{h.Handle}
+	}
+	return nil
+}
+
+// nfs4File holds the opens and locks of a file
+type nfs4File struct {
+	inode uint64
+	opens []*nfs4State
+	locks []byteLock
+}
+
+// byteLock is a POSIX byte-range lock on the range [start, end)
+type byteLock struct {
+	owner      lockOwner
+	write      bool
+	start, end uint64
+}
+
+// lockRange converts an offset and length into the range [start, end)
+//
+// A length of all ones means to the end of the file.
+func lockRange(offset, length uint64) (start, end uint64, status nfsstat4) {
+	if length == 0 {
+		return 0, 0, nfs4errInval
+	}
+	if length == math.MaxUint64 {
+		return offset, math.MaxUint64, nfs4OK
+	}
+	if offset+length < offset {
+		return 0, 0, nfs4errInval
+	}
+	return offset, offset + length, nfs4OK
+}
+
+// lockLength converts the end of a range back into a length
+func lockLength(start, end uint64) uint64 {
+	if end == math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return end - start
+}
+
+// conflict returns the first lock which stops owner taking the lock
+// or nil if there isn't one
+func (f *nfs4File) conflict(owner lockOwner, write bool, start, end uint64) *byteLock {
+	for i := range f.locks {
+		l := &f.locks[i]
+		if l.owner != owner && (write || l.write) && l.start < end && start < l.end {
+			return l
+		}
+	}
+	return nil
+}
+
+// unlock removes the locks owner holds in [start, end) splitting
+// locks which are partly in the range
+func (f *nfs4File) unlock(owner lockOwner, start, end uint64) {
+	locks := f.locks[:0:0]
+	for _, l := range f.locks {
+		if l.owner != owner || l.end <= start || end <= l.start {
+			locks = append(locks, l)
+			continue
+		}
+		if l.start < start {
+			before := l
+			before.end = start
+			locks = append(locks, before)
+		}
+		if end < l.end {
+			after := l
+			after.start = end
+			locks = append(locks, after)
+		}
+	}
+	f.locks = locks
+}
+
+// lock adds a lock for owner on [start, end) replacing any locks
+// owner already holds in the range
+func (f *nfs4File) lock(owner lockOwner, write bool, start, end uint64) {
+	f.unlock(owner, start, end)
+	f.locks = append(f.locks, byteLock{owner: owner, write: write, start: start, end: end})
+}
+
+// hasLocks returns true if owner holds any locks on the file
+func (f *nfs4File) hasLocks(owner lockOwner) bool {
+	for _, l := range f.locks {
+		if l.owner == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// init the state for the server
+func (st *state4) init() {
+	st.clients = map[uint64]*nfs4Client{}
+	st.owners = map[string]*nfs4Client{}
+	st.sessions = map[[16]byte]*nfs4Session{}
+	st.states = map[[12]byte]*nfs4State{}
+	st.files = map[uint64]*nfs4File{}
+	st.dirChanges = map[uint64]uint64{}
+	st.exclVerifiers = map[string][8]byte{}
+	// Start client ids from the boot time so they don't clash
+	// with those from a previous run of the server
+	st.nextClientID = uint64(time.Now().Unix()) << 32
+}
+
+// newClient makes a new client for ownerID
+func (st *state4) newClient(ownerID string, verifier [8]byte) *nfs4Client {
+	st.nextClientID++
+	c := &nfs4Client{
+		id:        st.nextClientID,
+		ownerID:   ownerID,
+		verifier:  verifier,
+		seq:       1,
+		lastRenew: time.Now(),
+		sessions:  map[[16]byte]*nfs4Session{},
+		states:    map[[12]byte]*nfs4State{},
+	}
+	st.clients[c.id] = c
+	st.owners[ownerID] = c
+	return c
+}
+
+// destroyClient removes the client and all its state returning the
+// VFS handles which need closing
+func (st *state4) destroyClient(c *nfs4Client) (handles []vfs.Handle) {
+	for _, state := range c.states {
+		if !state.isLock() {
+			handles = append(handles, st.closeOpen(state)...)
+		}
+	}
+	for id := range c.sessions {
+		delete(st.sessions, id)
+	}
+	delete(st.clients, c.id)
+	if st.owners[c.ownerID] == c {
+		delete(st.owners, c.ownerID)
+	}
+	return handles
+}
+
+// newSession makes a new session for the client
+func (st *state4) newSession(c *nfs4Client, slots, maxOps uint32) *nfs4Session {
+	sess := &nfs4Session{
+		client: c,
+		slots:  make([]nfs4Slot, slots),
+		maxOps: maxOps,
+	}
+	binary.BigEndian.PutUint64(sess.id[:], c.id)
+	_, _ = rand.Read(sess.id[8:])
+	st.sessions[sess.id] = sess
+	c.sessions[sess.id] = sess
+	return sess
+}
+
+// newState makes a new open or lock state
+func (st *state4) newState(c *nfs4Client, file *nfs4File, owner string) *nfs4State {
+	st.nextStateID++
+	state := &nfs4State{
+		client: c,
+		file:   file,
+		owner:  owner,
+	}
+	state.sid.seqid = 1
+	binary.BigEndian.PutUint32(state.sid.other[:], uint32(c.id))
+	binary.BigEndian.PutUint64(state.sid.other[4:], st.nextStateID)
+	st.states[state.sid.other] = state
+	c.states[state.sid.other] = state
+	return state
+}
+
+// removeState removes an open or lock state
+func (st *state4) removeState(state *nfs4State) {
+	delete(st.states, state.sid.other)
+	delete(state.client.states, state.sid.other)
+}
+
+// getFile returns the state of the file with inode, creating it if
+// necessary
+func (st *state4) getFile(inode uint64) *nfs4File {
+	f := st.files[inode]
+	if f == nil {
+		f = &nfs4File{inode: inode}
+		st.files[inode] = f
+	}
+	return f
+}
+
+// putFile removes the state of the file if it has no opens
+func (st *state4) putFile(f *nfs4File) {
+	if len(f.opens) == 0 {
+		delete(st.files, f.inode)
+	}
+}
+
+// closeOpen removes an open state and its lock states releasing
+// their locks
+//
+// It returns the VFS handle of the open if the caller should close it
+// once s.mu is unlocked. If it is still in use it is closed when the
+// last operation using it finishes.
+func (st *state4) closeOpen(open *nfs4State) (handles []vfs.Handle) {
+	for _, lockState := range open.locks {
+		open.file.unlock(lockState.lockOwner(), 0, math.MaxUint64)
+		st.removeState(lockState)
+	}
+	st.removeState(open)
+	f := open.file
+	for i, o := range f.opens {
+		if o == open {
+			f.opens = append(f.opens[:i], f.opens[i+1:]...)
+			break
+		}
+	}
+	st.putFile(f)
+	if open.handle != nil {
+		handles = append(handles, open.handle.finish()...)
+		open.handle = nil
+	}
+	return handles
+}
+
+// shareConflict returns true if opening with access and deny
+// conflicts with an existing open of the file other than except
+func (f *nfs4File) shareConflict(access, deny uint32, except *nfs4State) bool {
+	for _, o := range f.opens {
+		if o != except && (access&o.deny != 0 || deny&o.access != 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// findOpen returns the open state for the open owner on the file or
+// nil if there isn't one
+func (f *nfs4File) findOpen(c *nfs4Client, owner string) *nfs4State {
+	for _, o := range f.opens {
+		if o.client == c && o.owner == owner {
+			return o
+		}
+	}
+	return nil
+}
+
+// lookupState finds the state for sid checking it belongs to client c
+func (st *state4) lookupState(c *nfs4Client, sid stateid4) (*nfs4State, nfsstat4) {
+	state := st.states[sid.other]
+	if state == nil || state.client != c {
+		return nil, nfs4errBadStateid
+	}
+	switch {
+	case sid.seqid == 0:
+		// seqid 0 means the most recent stateid
+	case sid.seqid < state.sid.seqid:
+		return nil, nfs4errOldStateid
+	case sid.seqid > state.sid.seqid:
+		return nil, nfs4errBadStateid
+	}
+	return state, nfs4OK
+}
+
+// bumpSeqid increments the seqid of a state after it has changed
+func (state *nfs4State) bumpSeqid() {
+	state.sid.seqid++
+	if state.sid.seqid == 0 {
+		state.sid.seqid = 1
+	}
+}
+
+// dirChanged records that the server changed the directory with inode
+func (st *state4) dirChanged(inode uint64) {
+	st.dirChanges[inode]++
+}
@@ -0,0 +1,612 @@
+//go:build unix
+
+package nfs
+
+import (
+	"context"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient is a minimal NFSv4.1 client for testing the server
+type testClient struct {
+	t         *testing.T
+	conn      net.Conn
+	xid       uint32
+	clientID  uint64
+	sessionID [16]byte
+	seqid     uint32
+}
+
+// testOp encodes an operation with its arguments
+type testOp func(w *xdrWriter)
+
+// newTestServer starts an NFSv4.1 server serving dir
+func newTestServer(t *testing.T, dir string) (string, *vfs.VFS) {
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+	vfsOpt := vfscommon.Opt
+	vfsOpt.CacheMode = vfscommon.CacheModeWrites
+	vfsOpt.WriteBack = 0
+	VFS := vfs.New(f, &vfsOpt)
+	t.Cleanup(VFS.Shutdown)
+	opt := Opt
+	opt.ListenAddr = "localhost:0"
+	opt.Version = nfsVersion41
+	s, err := NewServer(ctx, VFS, &opt)
+	require.NoError(t, err)
+	go func() {
+		assert.NoError(t, s.Serve())
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, s.Shutdown())
+	})
+	return s.Addr().String(), VFS
+}
+
+// newTestClient connects to the server and sets up a session
+func newTestClient(t *testing.T, addr string, owner string) *testClient {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	tc := &testClient{t: t, conn: conn}
+
+	// EXCHANGE_ID
+	status, r := tc.compoundNoSession(1, func(w *xdrWriter) {
+		w.Uint32(opExchangeID)
+		w.Fixed([]byte("verifier"))
+		w.String(owner)
+		w.Uint32(0) // flags
+		w.Uint32(sp4None)
+		w.Uint32(0) // no impl id
+	})
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opExchangeID)
+	tc.clientID = r.Uint64()
+	seq := r.Uint32()
+
+	// CREATE_SESSION
+	status, r = tc.compoundNoSession(1, func(w *xdrWriter) {
+		w.Uint32(opCreateSession)
+		w.Uint64(tc.clientID)
+		w.Uint32(seq)
+		w.Uint32(0) // flags
+		for range 2 {
+			channelAttrs{maxRequestSize: 1 << 20, maxResponseSize: 1 << 20, maxResponseSizeCache: 8192, maxOperations: 16, maxRequests: 8}.write(w)
+		}
+		w.Uint32(0x40000000) // cb_program
+		w.Uint32(1)          // sec_parms
+		w.Uint32(rpcAuthNone)
+	})
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opCreateSession)
+	copy(tc.sessionID[:], r.Fixed(16))
+	assert.Equal(t, seq, r.Uint32())
+	return tc
+}
+
+// call makes an RPC call returning a reader for the reply body
+func (tc *testClient) call(prog, vers, proc uint32, args []byte) (acceptStat uint32, r *xdrReader) {
+	tc.xid++
+	w := &xdrWriter{}
+	w.Uint32(tc.xid)
+	w.Uint32(rpcCall)
+	w.Uint32(rpcVersion)
+	w.Uint32(prog)
+	w.Uint32(vers)
+	w.Uint32(proc)
+	w.Uint32(rpcAuthNone)
+	w.Opaque(nil)
+	w.Uint32(rpcAuthNone)
+	w.Opaque(nil)
+	w.buf = append(w.buf, args...)
+	require.NoError(tc.t, writeRecord(tc.conn, w.Bytes()))
+	reply, err := readRecord(tc.conn)
+	require.NoError(tc.t, err)
+	r = newXDRReader(reply)
+	assert.Equal(tc.t, tc.xid, r.Uint32())
+	assert.Equal(tc.t, uint32(rpcReply), r.Uint32())
+	require.Equal(tc.t, uint32(rpcMsgAccepted), r.Uint32())
+	_ = r.Uint32() // verifier
+	_ = r.Opaque(400)
+	return r.Uint32(), r
+}
+
+// compoundRaw sends a COMPOUND with the encoded ops
+func (tc *testClient) compoundRaw(minorVersion uint32, nops int, ops ...testOp) (nfsstat4, *xdrReader) {
+	w := &xdrWriter{}
+	w.String("test")
+	w.Uint32(minorVersion)
+	w.Uint32(uint32(nops))
+	for _, op := range ops {
+		op(w)
+	}
+	acceptStat, r := tc.call(nfs4Program, nfs4Version, nfs4ProcCompound, w.Bytes())
+	require.Equal(tc.t, uint32(rpcSuccess), acceptStat)
+	status := nfsstat4(r.Uint32())
+	assert.Equal(tc.t, "test", r.String(1024))
+	_ = r.Uint32() // number of results
+	return status, r
+}
+
+// compoundNoSession sends a COMPOUND without a SEQUENCE
+func (tc *testClient) compoundNoSession(nops int, ops ...testOp) (nfsstat4, *xdrReader) {
+	return tc.compoundRaw(1, nops, ops...)
+}
+
+// sequenceOp encodes a SEQUENCE op for the next request on slot 0
+func (tc *testClient) sequenceOp(seqid uint32, cacheThis bool) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opSequence)
+		w.Fixed(tc.sessionID[:])
+		w.Uint32(seqid)
+		w.Uint32(0) // slot
+		w.Uint32(0) // highest slot
+		w.Bool(cacheThis)
+	}
+}
+
+// compound sends the ops in a COMPOUND in the session returning a
+// reader positioned at the result of the first op
+func (tc *testClient) compound(ops ...testOp) (nfsstat4, *xdrReader) {
+	tc.seqid++
+	status, r := tc.compoundRaw(1, len(ops)+1, append([]testOp{tc.sequenceOp(tc.seqid, false)}, ops...)...)
+	tc.result(r, opSequence)
+	_ = r.Fixed(16)
+	_ = r.Uint32()
+	_ = r.Uint32()
+	_ = r.Uint32()
+	_ = r.Uint32()
+	_ = r.Uint32()
+	return status, r
+}
+
+// result reads the header of the result of op returning its status
+func (tc *testClient) result(r *xdrReader, op uint32) nfsstat4 {
+	require.Equal(tc.t, op, r.Uint32())
+	return nfsstat4(r.Uint32())
+}
+
+// Encoders for the ops used in the tests
+
+func putRootFH(w *xdrWriter) {
+	w.Uint32(opPutrootfh)
+}
+
+func lookup(name string) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opLookup)
+		w.String(name)
+	}
+}
+
+func getattr(attrs ...int) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opGetattr)
+		w.Bitmap(newBitmap(attrs...))
+	}
+}
+
+func openOp(owner, name string, access uint32, create bool) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opOpen)
+		w.Uint32(0) // seqid
+		w.Uint32(access)
+		w.Uint32(0) // deny
+		w.Uint64(0) // clientid
+		w.String(owner)
+		if create {
+			w.Uint32(open4Create)
+			w.Uint32(createUnchecked)
+			w.Bitmap(nil)
+			w.Opaque(nil)
+		} else {
+			w.Uint32(0)
+		}
+		w.Uint32(claimNull)
+		w.String(name)
+	}
+}
+
+func writeOp(offset uint64, data string) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opWrite)
+		w.Stateid(currentStateid)
+		w.Uint64(offset)
+		w.Uint32(fileSync4)
+		w.String(data)
+	}
+}
+
+func readOp(sid stateid4, offset uint64, count uint32) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opRead)
+		w.Stateid(sid)
+		w.Uint64(offset)
+		w.Uint32(count)
+	}
+}
+
+func closeOp(sid stateid4) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opClose)
+		w.Uint32(0)
+		w.Stateid(sid)
+	}
+}
+
+func lockOp(open stateid4, owner string, write bool, offset, length uint64) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opLock)
+		if write {
+			w.Uint32(writeLT)
+		} else {
+			w.Uint32(readLT)
+		}
+		w.Bool(false)
+		w.Uint64(offset)
+		w.Uint64(length)
+		w.Bool(true) // new lock owner
+		w.Uint32(0)
+		w.Stateid(open)
+		w.Uint32(0)
+		w.Uint64(0)
+		w.String(owner)
+	}
+}
+
+func lockuOp(sid stateid4, offset, length uint64) testOp {
+	return func(w *xdrWriter) {
+		w.Uint32(opLocku)
+		w.Uint32(writeLT)
+		w.Uint32(0)
+		w.Stateid(sid)
+		w.Uint64(offset)
+		w.Uint64(length)
+	}
+}
+
+// readOpenResult reads the result of OPEN returning the stateid
+func (tc *testClient) readOpenResult(r *xdrReader) stateid4 {
+	require.Equal(tc.t, nfs4OK, tc.result(r, opOpen))
+	sid := r.Stateid()
+	_ = r.Bool()
+	_ = r.Uint64()
+	_ = r.Uint64()
+	assert.Equal(tc.t, uint32(open4ResultLocktypePosix), r.Uint32())
+	_ = r.Bitmap()
+	assert.Equal(tc.t, uint32(openDelegateNone), r.Uint32())
+	return sid
+}
+
+func TestNFS4Session(t *testing.T) {
+	addr, _ := newTestServer(t, t.TempDir())
+	tc := newTestClient(t, addr, "client1")
+
+	// NULL procedure
+	acceptStat, _ := tc.call(nfs4Program, nfs4Version, nfs4ProcNull, nil)
+	assert.Equal(t, uint32(rpcSuccess), acceptStat)
+
+	// Wrong program and version
+	acceptStat, _ = tc.call(100005, 3, 0, nil)
+	assert.Equal(t, uint32(rpcProgUnavail), acceptStat)
+	acceptStat, r := tc.call(nfs4Program, 3, 0, nil)
+	assert.Equal(t, uint32(rpcProgMismatch), acceptStat)
+	assert.Equal(t, uint32(nfs4Version), r.Uint32())
+
+	// Wrong minor version
+	status, _ := tc.compoundRaw(0, 1, putRootFH)
+	assert.Equal(t, nfs4errMinorVersMismatch, status)
+
+	// Ops need a session
+	status, r = tc.compoundNoSession(1, putRootFH)
+	assert.Equal(t, nfs4errOpNotInSession, status)
+	assert.Equal(t, nfs4errOpNotInSession, tc.result(r, opPutrootfh))
+
+	// SEQUENCE must be first
+	status, _ = tc.compound(tc.sequenceOp(tc.seqid+1, false))
+	assert.Equal(t, nfs4errSequencePos, status)
+
+	// Unknown ops are illegal
+	status, r = tc.compound(func(w *xdrWriter) { w.Uint32(9999) })
+	assert.Equal(t, nfs4errOpIllegal, status)
+	assert.Equal(t, nfs4errOpIllegal, tc.result(r, opIllegal))
+
+	// Replies are replayed from the cache for retries
+	tc.seqid++
+	getattrOps := []testOp{tc.sequenceOp(tc.seqid, true), putRootFH, getattr(attrType)}
+	status, r1 := tc.compoundRaw(1, 3, getattrOps...)
+	require.Equal(t, nfs4OK, status)
+	status, r2 := tc.compoundRaw(1, 3, getattrOps...)
+	require.Equal(t, nfs4OK, status)
+	assert.Equal(t, r1.buf, r2.buf)
+
+	// Retries which weren't cached can't be replayed
+	status, _ = tc.compoundRaw(1, 1, tc.sequenceOp(tc.seqid-1, false))
+	assert.Equal(t, nfs4errSeqMisordered, status)
+	tc.seqid++
+	_, _ = tc.compoundRaw(1, 1, tc.sequenceOp(tc.seqid, false))
+	status, _ = tc.compoundRaw(1, 1, tc.sequenceOp(tc.seqid, false))
+	assert.Equal(t, nfs4errRetryUncachedRep, status)
+
+	// Root is a directory
+	status, r = tc.compound(putRootFH, getattr(attrType))
+	require.Equal(t, nfs4OK, status)
+	require.Equal(t, nfs4OK, tc.result(r, opPutrootfh))
+	require.Equal(t, nfs4OK, tc.result(r, opGetattr))
+	assert.Equal(t, bitmap4{1 << attrType}, r.Bitmap())
+	vals := newXDRReader(r.Opaque(1024))
+	assert.Equal(t, uint32(nf4Dir), vals.Uint32())
+}
+
+func TestNFS4Files(t *testing.T) {
+	dir := t.TempDir()
+	addr, VFS := newTestServer(t, dir)
+	tc := newTestClient(t, addr, "client1")
+
+	// Create and write a file
+	status, r := tc.compound(putRootFH, openOp("owner", "file.txt", openShareAccessBoth, true), writeOp(0, "hello world"))
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	sid := tc.readOpenResult(r)
+	require.Equal(t, nfs4OK, tc.result(r, opWrite))
+	assert.Equal(t, uint32(11), r.Uint32())
+	assert.Equal(t, uint32(fileSync4), r.Uint32())
+
+	// Read it back using the open
+	status, r = tc.compound(putRootFH, lookup("file.txt"), readOp(sid, 6, 100))
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	tc.result(r, opLookup)
+	require.Equal(t, nfs4OK, tc.result(r, opRead))
+	assert.True(t, r.Bool())
+	assert.Equal(t, "world", r.String(1024))
+
+	// Close it which writes it to the remote
+	status, _ = tc.compound(putRootFH, lookup("file.txt"), closeOp(sid))
+	require.Equal(t, nfs4OK, status)
+	VFS.WaitForWriters(10 * time.Second)
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+
+	// The stateid is no longer valid
+	status, _ = tc.compound(putRootFH, lookup("file.txt"), readOp(sid, 0, 100))
+	assert.Equal(t, nfs4errBadStateid, status)
+
+	// Read with the anonymous stateid
+	status, r = tc.compound(putRootFH, lookup("file.txt"), readOp(anonymousStateid, 0, 5))
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	tc.result(r, opLookup)
+	tc.result(r, opRead)
+	assert.False(t, r.Bool())
+	assert.Equal(t, "hello", r.String(1024))
+
+	// Size and type
+	status, r = tc.compound(putRootFH, lookup("file.txt"), getattr(attrType, attrSize))
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	tc.result(r, opLookup)
+	tc.result(r, opGetattr)
+	_ = r.Bitmap()
+	vals := newXDRReader(r.Opaque(1024))
+	assert.Equal(t, uint32(nf4Reg), vals.Uint32())
+	assert.Equal(t, uint64(11), vals.Uint64())
+
+	// Missing files
+	status, r = tc.compound(putRootFH, lookup("missing.txt"))
+	assert.Equal(t, nfs4errNoent, status)
+	tc.result(r, opPutrootfh)
+	assert.Equal(t, nfs4errNoent, tc.result(r, opLookup))
+
+	// Make a directory
+	status, r = tc.compound(putRootFH, func(w *xdrWriter) {
+		w.Uint32(opCreate)
+		w.Uint32(nf4Dir)
+		w.String("dir")
+		w.Bitmap(nil)
+		w.Opaque(nil)
+	})
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	tc.result(r, opCreate)
+	assert.False(t, r.Bool())
+	before, after := r.Uint64(), r.Uint64()
+	assert.NotEqual(t, before, after)
+	assert.DirExists(t, filepath.Join(dir, "dir"))
+
+	// Rename the file into it
+	status, _ = tc.compound(putRootFH, func(w *xdrWriter) { w.Uint32(opSavefh) }, lookup("dir"), func(w *xdrWriter) {
+		w.Uint32(opRename)
+		w.String("file.txt")
+		w.String("renamed.txt")
+	})
+	require.Equal(t, nfs4OK, status)
+	assert.FileExists(t, filepath.Join(dir, "dir", "renamed.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "file.txt"))
+
+	// List the directory
+	status, r = tc.compound(putRootFH, lookup("dir"), func(w *xdrWriter) {
+		w.Uint32(opReaddir)
+		w.Uint64(0)
+		w.Fixed(make([]byte, 8))
+		w.Uint32(4096)
+		w.Uint32(4096)
+		w.Bitmap(newBitmap(attrSize))
+	})
+	require.Equal(t, nfs4OK, status)
+	tc.result(r, opPutrootfh)
+	tc.result(r, opLookup)
+	tc.result(r, opReaddir)
+	_ = r.Fixed(8)
+	require.True(t, r.Bool())
+	assert.Equal(t, uint64(3), r.Uint64())
+	assert.Equal(t, "renamed.txt", r.String(1024))
+	_ = r.Bitmap()
+	vals = newXDRReader(r.Opaque(1024))
+	assert.Equal(t, uint64(11), vals.Uint64())
+	assert.False(t, r.Bool())
+	assert.True(t, r.Bool())
+
+	// Remove the file then the directory
+	status, _ = tc.compound(putRootFH, lookup("dir"), func(w *xdrWriter) {
+		w.Uint32(opRemove)
+		w.String("renamed.txt")
+	})
+	require.Equal(t, nfs4OK, status)
+	status, _ = tc.compound(putRootFH, func(w *xdrWriter) {
+		w.Uint32(opRemove)
+		w.String("dir")
+	})
+	require.Equal(t, nfs4OK, status)
+	assert.NoDirExists(t, filepath.Join(dir, "dir"))
+}
+
+func TestNFS4Locks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("potato"), 0666))
+	addr, _ := newTestServer(t, dir)
+	tc1 := newTestClient(t, addr, "client1")
+	tc2 := newTestClient(t, addr, "client2")
+
+	open := func(tc *testClient) stateid4 {
+		status, r := tc.compound(putRootFH, openOp("owner", "file.txt", openShareAccessBoth, false))
+		require.Equal(t, nfs4OK, status)
+		tc.result(r, opPutrootfh)
+		return tc.readOpenResult(r)
+	}
+	open1, open2 := open(tc1), open(tc2)
+
+	// Client 1 takes a write lock on the first 10 bytes
+	status, r := tc1.compound(putRootFH, lookup("file.txt"), lockOp(open1, "lock1", true, 0, 10))
+	require.Equal(t, nfs4OK, status)
+	tc1.result(r, opPutrootfh)
+	tc1.result(r, opLookup)
+	tc1.result(r, opLock)
+	lock1 := r.Stateid()
+
+	// Client 2 can't lock an overlapping range
+	status, r = tc2.compound(putRootFH, lookup("file.txt"), lockOp(open2, "lock2", false, 5, math.MaxUint64))
+	require.Equal(t, nfs4errDenied, status)
+	tc2.result(r, opPutrootfh)
+	tc2.result(r, opLookup)
+	assert.Equal(t, nfs4errDenied, tc2.result(r, opLock))
+	assert.Equal(t, uint64(0), r.Uint64())
+	assert.Equal(t, uint64(10), r.Uint64())
+	assert.Equal(t, uint32(writeLT), r.Uint32())
+	assert.Equal(t, tc1.clientID, r.Uint64())
+	assert.Equal(t, "lock1", r.String(1024))
+
+	// LOCKT sees the lock too
+	status, _ = tc2.compound(putRootFH, lookup("file.txt"), func(w *xdrWriter) {
+		w.Uint32(opLockt)
+		w.Uint32(readLT)
+		w.Uint64(9)
+		w.Uint64(1)
+		w.Uint64(tc2.clientID)
+		w.String("lock2")
+	})
+	assert.Equal(t, nfs4errDenied, status)
+
+	// But can lock a range which doesn't overlap
+	status, _ = tc2.compound(putRootFH, lookup("file.txt"), lockOp(open2, "lock2", false, 10, 10))
+	require.Equal(t, nfs4OK, status)
+
+	// Client 1 can't close with locks held
+	status, _ = tc1.compound(putRootFH, lookup("file.txt"), closeOp(open1))
+	assert.Equal(t, nfs4errLocksHeld, status)
+
+	// Once client 1 unlocks client 2 can take the lock
+	status, _ = tc1.compound(putRootFH, lookup("file.txt"), lockuOp(lock1, 0, math.MaxUint64))
+	require.Equal(t, nfs4OK, status)
+	status, _ = tc2.compound(putRootFH, lookup("file.txt"), lockOp(open2, "lock2", false, 5, math.MaxUint64))
+	require.Equal(t, nfs4OK, status)
+
+	// Now client 1 can close
+	status, _ = tc1.compound(putRootFH, lookup("file.txt"), closeOp(open1))
+	assert.Equal(t, nfs4OK, status)
+}
+
+func TestNFS4LockRanges(t *testing.T) {
+	a := lockOwner{clientID: 1, owner: "a"}
+	b := lockOwner{clientID: 2, owner: "b"}
+	f := &nfs4File{}
+
+	start, end, status := lockRange(10, math.MaxUint64)
+	require.Equal(t, nfs4OK, status)
+	assert.Equal(t, uint64(10), start)
+	assert.Equal(t, uint64(math.MaxUint64), end)
+	assert.Equal(t, uint64(math.MaxUint64), lockLength(start, end))
+	_, _, status = lockRange(10, 0)
+	assert.Equal(t, nfs4errInval, status)
+	_, _, status = lockRange(math.MaxUint64-1, 10)
+	assert.Equal(t, nfs4errInval, status)
+
+	// Read locks don't conflict with each other
+	f.lock(a, false, 0, 100)
+	assert.Nil(t, f.conflict(b, false, 50, 60))
+	assert.NotNil(t, f.conflict(b, true, 50, 60))
+	assert.Nil(t, f.conflict(a, true, 50, 60))
+
+	// Unlocking the middle splits the lock
+	f.unlock(a, 40, 60)
+	assert.Equal(t, []byteLock{
+		{owner: a, start: 0, end: 40},
+		{owner: a, start: 60, end: 100},
+	}, f.locks)
+	assert.Nil(t, f.conflict(b, true, 40, 60))
+
+	// Upgrading part of a lock replaces it
+	f.lock(a, true, 30, 70)
+	assert.Equal(t, []byteLock{
+		{owner: a, start: 0, end: 30},
+		{owner: a, start: 70, end: 100},
+		{owner: a, write: true, start: 30, end: 70},
+	}, f.locks)
+	assert.NotNil(t, f.conflict(b, false, 69, 70))
+	assert.True(t, f.hasLocks(a))
+	assert.False(t, f.hasLocks(b))
+
+	f.unlock(a, 0, math.MaxUint64)
+	assert.Empty(t, f.locks)
+}
+
+func TestNFS4OpenHandle(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("hello"), 0600))
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+	VFS := vfs.New(f, &vfscommon.Opt)
+	t.Cleanup(VFS.Shutdown)
+	fh, err := VFS.OpenFile("file", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	h := &openHandle{Handle: fh}
+
+	// A READ in progress keeps the handle open after a CLOSE
+	h.acquire()
+	assert.Empty(t, h.finish())
+	buf := make([]byte, 5)
+	n, err := h.ReadAt(buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+
+	// The last operation to finish closes it
+	handles := h.release()
+	require.Len(t, handles, 1)
+	closeHandles(handles)
+	_, err = h.ReadAt(buf, 0)
+	assert.Error(t, err)
+}
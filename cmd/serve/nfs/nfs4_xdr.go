@@ -0,0 +1,215 @@
+//go:build unix
+
+package nfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// errBadXDR is returned when the XDR being decoded is malformed
+var errBadXDR = errors.New("bad XDR")
+
+// xdrReader decodes XDR from a buffer
+//
+// The first error is remembered and all reads after it return zero
+// values so callers only need to check err once they have finished.
+type xdrReader struct {
+	buf []byte
+	err error
+}
+
+// newXDRReader makes a reader for buf
+func newXDRReader(buf []byte) *xdrReader {
+	return &xdrReader{buf: buf}
+}
+
+// next returns the next n bytes
+func (r *xdrReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = fmt.Errorf("%w: need %d bytes but only %d left", errBadXDR, n, len(r.buf))
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// Uint32 reads an unsigned 32 bit integer
+func (r *xdrReader) Uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+// Uint64 reads an unsigned 64 bit integer
+func (r *xdrReader) Uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// Int64 reads a signed 64 bit integer
+func (r *xdrReader) Int64() int64 {
+	return int64(r.Uint64())
+}
+
+// Bool reads a boolean
+func (r *xdrReader) Bool() bool {
+	return r.Uint32() != 0
+}
+
+// Fixed reads fixed length opaque data of n bytes
+func (r *xdrReader) Fixed(n int) []byte {
+	b := r.next(n)
+	r.next((4 - n%4) % 4)
+	return b
+}
+
+// Opaque reads variable length opaque data of at most limit bytes
+func (r *xdrReader) Opaque(limit int) []byte {
+	n := r.Uint32()
+	if r.err == nil && n > uint32(limit) {
+		r.err = fmt.Errorf("%w: opaque length %d exceeds %d", errBadXDR, n, limit)
+		return nil
+	}
+	return r.Fixed(int(n))
+}
+
+// String reads a string of at most limit bytes
+func (r *xdrReader) String(limit int) string {
+	return string(r.Opaque(limit))
+}
+
+// Bitmap reads a bitmap4
+func (r *xdrReader) Bitmap() bitmap4 {
+	n := r.Uint32()
+	if r.err == nil && n > 8 {
+		r.err = fmt.Errorf("%w: bitmap length %d too long", errBadXDR, n)
+		return nil
+	}
+	bm := make(bitmap4, 0, n)
+	for range n {
+		bm = append(bm, r.Uint32())
+	}
+	return bm
+}
+
+// Stateid reads a stateid4
+func (r *xdrReader) Stateid() stateid4 {
+	var sid stateid4
+	sid.seqid = r.Uint32()
+	copy(sid.other[:], r.Fixed(len(sid.other)))
+	return sid
+}
+
+// Remaining returns the number of bytes left
+func (r *xdrReader) Remaining() int {
+	return len(r.buf)
+}
+
+// xdrWriter encodes XDR into a buffer
+type xdrWriter struct {
+	buf []byte
+}
+
+// Bytes returns the encoded data
+func (w *xdrWriter) Bytes() []byte {
+	return w.buf
+}
+
+// Len returns the number of bytes encoded so far
+func (w *xdrWriter) Len() int {
+	return len(w.buf)
+}
+
+// Uint32 writes an unsigned 32 bit integer
+func (w *xdrWriter) Uint32(x uint32) {
+	w.buf = binary.BigEndian.AppendUint32(w.buf, x)
+}
+
+// Uint64 writes an unsigned 64 bit integer
+func (w *xdrWriter) Uint64(x uint64) {
+	w.buf = binary.BigEndian.AppendUint64(w.buf, x)
+}
+
+// Int64 writes a signed 64 bit integer
+func (w *xdrWriter) Int64(x int64) {
+	w.Uint64(uint64(x))
+}
+
+// Bool writes a boolean
+func (w *xdrWriter) Bool(x bool) {
+	if x {
+		w.Uint32(1)
+	} else {
+		w.Uint32(0)
+	}
+}
+
+// Fixed writes fixed length opaque data
+func (w *xdrWriter) Fixed(b []byte) {
+	w.buf = append(w.buf, b...)
+	for range (4 - len(b)%4) % 4 {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+// Opaque writes variable length opaque data
+func (w *xdrWriter) Opaque(b []byte) {
+	w.Uint32(uint32(len(b)))
+	w.Fixed(b)
+}
+
+// String writes a string
+func (w *xdrWriter) String(s string) {
+	w.Opaque([]byte(s))
+}
+
+// Bitmap writes a bitmap4
+func (w *xdrWriter) Bitmap(bm bitmap4) {
+	w.Uint32(uint32(len(bm)))
+	for _, x := range bm {
+		w.Uint32(x)
+	}
+}
+
+// Stateid writes a stateid4
+func (w *xdrWriter) Stateid(sid stateid4) {
+	w.Uint32(sid.seqid)
+	w.Fixed(sid.other[:])
+}
+
+// bitmap4 is a bitmap of attributes or operations
+type bitmap4 []uint32
+
+// isSet returns true if bit n is set
+func (bm bitmap4) isSet(n int) bool {
+	word := n / 32
+	return word < len(bm) && bm[word]&(1<<(n%32)) != 0
+}
+
+// set sets bit n, growing the bitmap if needed
+func (bm *bitmap4) set(n int) {
+	word := n / 32
+	for len(*bm) <= word {
+		*bm = append(*bm, 0)
+	}
+	(*bm)[word] |= 1 << (n % 32)
+}
+
+// newBitmap makes a bitmap with the bits passed in set
+func newBitmap(bits ...int) (bm bitmap4) {
+	for _, bit := range bits {
+		bm.set(bit)
+	}
+	return bm
+}
@@ -18,6 +18,7 @@ import (
 type Server struct {
 	opt                 Options
 	handler             nfs.Handler
+	server4             *server4        // set if serving NFSv4.1
 	ctx                 context.Context // for global config
 	listener            net.Listener
 	UnmountedExternally bool
@@ -41,6 +42,9 @@ func NewServer(ctx context.Context, vfs *vfs.VFS, opt *Options) (s *Server, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to make NFS handler: %w", err)
 	}
+	if s.opt.Version == nfsVersion41 {
+		s.server4 = newServer4(s.handler.(*Handler))
+	}
 	s.listener, err = net.Listen("tcp", s.opt.ListenAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open listening socket: %w", err)
@@ -55,11 +59,18 @@ func (s *Server) Addr() net.Addr {
 
 // Shutdown stops the server
 func (s *Server) Shutdown() error {
+	if s.server4 != nil {
+		s.server4.close()
+	}
 	return s.listener.Close()
 }
 
 // Serve starts the server
 func (s *Server) Serve() (err error) {
+	if s.server4 != nil {
+		fs.Logf(nil, "NFSv4.1 Server running at %s\n", s.listener.Addr())
+		return s.server4.serve(s.listener)
+	}
 	fs.Logf(nil, "NFS Server running at %s\n", s.listener.Addr())
 	return nfs.Serve(s.listener, s.handler)
 }
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"path"
 	"strings"
 	"time"
 
@@ -49,9 +50,30 @@ This config generated must have this extra parameter
 
 - |_root| - root to use for the backend
 
-And it may have this parameter
+And it may have these parameters
 
 - |_obscure| - comma separated strings for parameters to obscure
+- |_prefix| - directory under |_root| the user is restricted to
+- |_quota| - maximum size of the files the user may store, e.g. |10G|
+- |_permissions| - comma separated operations the user may do
+
+The permissions are |read| (open files for reading), |list| (list
+directories), |write| (create, modify and rename files and
+directories) and |delete| (remove files and directories), or one of
+the presets |all| (the default), |read-only| (|read,list|),
+|upload-only| (|write|) or |none|. Replacing an existing file, by
+renaming onto it or opening it with truncation, needs |delete| as well
+as |write|. Any operation which isn't permitted fails with a permission
+denied error.
+
+The quota is checked as files are written and a write which would take
+the user over it fails with a no space left on device error. The space
+in use is read from the backend every |--dir-cache-time| so files
+changed outside rclone will be accounted for eventually. The quota is
+also reported as the total size of the disk to clients which ask.
+
+These limits are enforced in the VFS so they work the same for every
+serve protocol.
 
 If password authentication was used by the client, input to the proxy
 process (on STDIN) would look similar to this:
@@ -106,6 +128,43 @@ before it takes effect.
 This can be used to build general purpose proxies to any kind of
 backend that rclone supports.
 
+### Users file
+
+If you don't need a program to make the backends, you can instead
+supply |--auth-users-file /path/to/users.json| to serve the remote
+given on the command line to a fixed set of users, each with their own
+root, quota and permissions. If |--auth-proxy| is also set the users
+file is ignored.
+
+|||json
+[
+  {
+    "user": "alice",
+    "pass": "$2y$10$4z8Mq3cRvl2TQmOeV4ZKz.jNZdq0kB2wV0PaQqV0cGmXG1C3BK6Wa",
+    "prefix": "alice",
+    "quota": "10G",
+    "permissions": "all"
+  },
+  {
+    "user": "uploads",
+    "pass": "secret",
+    "public_keys": ["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... uploads@example.com"],
+    "prefix": "incoming",
+    "permissions": "upload-only"
+  }
+]
+|||
+
+The |pass| may be in plain text or a bcrypt hash as made by
+|htpasswd -nB|. The |public_keys| are in |authorized_keys| format
+and are used by |rclone serve sftp|. The |prefix|, |quota| and
+|permissions| work in the same way as |_prefix|, |_quota| and
+|_permissions| above and may be left out.
+
+The file is read each time a user who isn't in the cache logs in, so
+it can be changed without restarting rclone, subject to the cache
+expiry described above.
+
 `, "|", "`")
 
 // OptionsInfo descripts the Options in use
@@ -113,11 +172,21 @@ var OptionsInfo = fs.Options{{
 	Name:    "auth_proxy",
 	Default: "",
 	Help:    "A program to use to create the backend from the auth",
+}, {
+	Name:    "auth_users_file",
+	Default: "",
+	Help:    "A JSON file of users with their passwords, roots, quotas and permissions",
 }}
 
 // Options is options for creating the proxy
 type Options struct {
-	AuthProxy string `config:"auth_proxy"`
+	AuthProxy     string `config:"auth_proxy"`
+	AuthUsersFile string `config:"auth_users_file"`
+}
+
+// InUse returns true if users should be authenticated with the proxy
+func (opt *Options) InUse() bool {
+	return opt.AuthProxy != "" || opt.AuthUsersFile != ""
 }
 
 // Opt is the default options
@@ -132,6 +201,7 @@ type Proxy struct {
 	cmdLine  []string // broken down command line
 	vfsCache *libcache.Cache
 	ctx      context.Context // for global config
+	f        fs.Fs           // the remote being served for the users file
 	Opt      Options
 	vfsOpt   vfscommon.Options
 }
@@ -144,10 +214,12 @@ type cacheEntry struct {
 
 // New creates a new proxy with the Options passed in
 //
-// Any VFS are created with the vfsOpt passed in.
-func New(ctx context.Context, opt *Options, vfsOpt *vfscommon.Options) *Proxy {
+// Any VFS are created with the vfsOpt passed in. Users from the
+// users file are served from f which may be nil if it isn't in use.
+func New(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Options) *Proxy {
 	return &Proxy{
 		ctx:      ctx,
+		f:        f,
 		Opt:      *opt,
 		cmdLine:  strings.Fields(opt.AuthProxy),
 		vfsCache: libcache.New(),
@@ -196,8 +268,79 @@ func (p *Proxy) run(in map[string]string) (config configmap.Simple, err error) {
 	return config, nil
 }
 
+// limits are the restrictions placed on the VFS of a user
+type limits struct {
+	prefix string          // directory under the root to serve
+	perms  vfs.Permissions // operations allowed
+	quota  int64           // max bytes stored or -1 for no quota
+}
+
+// parseLimits parses the prefix, quota and permissions of a user as
+// returned by the proxy or read from the users file
+func parseLimits(prefix, quota, permissions string) (lim limits, err error) {
+	lim = limits{perms: vfs.PermAll, quota: -1}
+	if prefix != "" {
+		// Cleaning this as an absolute path removes any leading ..
+		lim.prefix = strings.TrimPrefix(path.Clean("/"+prefix), "/")
+	}
+	if quota != "" {
+		var size fs.SizeSuffix
+		if err = size.Set(quota); err != nil {
+			return lim, fmt.Errorf("bad quota %q: %w", quota, err)
+		}
+		lim.quota = int64(size)
+	}
+	if permissions != "" {
+		lim.perms, err = vfs.ParsePermissions(permissions)
+		if err != nil {
+			return lim, err
+		}
+	}
+	return lim, nil
+}
+
 // call runs the auth proxy and returns a cacheEntry and an error
 func (p *Proxy) call(user, auth string, isPublicKey bool) (value any, err error) {
+	var (
+		newFs func() (fs.Fs, error)
+		lim   limits
+	)
+	if p.Opt.AuthProxy != "" {
+		newFs, lim, err = p.callProgram(user, auth, isPublicKey)
+	} else {
+		newFs, lim, err = p.callUsersFile(user, auth, isPublicKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Look for fs in the VFS cache
+	value, err = p.vfsCache.Get(user, func(key string) (value any, ok bool, err error) {
+		f, err := newFs()
+		if err != nil {
+			return nil, false, err
+		}
+		VFS := vfs.New(f, &p.vfsOpt)
+		VFS.SetLimits(lim.perms, lim.quota)
+
+		// We hash the auth here so we don't copy the auth more than we
+		// need to in memory. An attacker would find it easier to go
+		// after the unencrypted password in memory most likely.
+		entry := cacheEntry{
+			vfs:    VFS,
+			pwHash: sha256.Sum256([]byte(auth)),
+		}
+		return entry, true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("proxy: failed to create backend: %w", err)
+	}
+	return value, nil
+}
+
+// callProgram runs the auth proxy program returning a function to
+// create the Fs for the user and their limits
+func (p *Proxy) callProgram(user, auth string, isPublicKey bool) (newFs func() (fs.Fs, error), lim limits, err error) {
 	var config configmap.Simple
 	// Contact the proxy
 	if isPublicKey {
@@ -213,33 +356,39 @@ func (p *Proxy) call(user, auth string, isPublicKey bool) (value any, err error)
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, lim, err
 	}
 
 	// Look for required fields in the answer
 	fsName, ok := config.Get("type")
 	if !ok {
-		return nil, errors.New("proxy: type not set in result")
+		return nil, lim, errors.New("proxy: type not set in result")
 	}
 	root, ok := config.Get("_root")
 	if !ok {
-		return nil, errors.New("proxy: _root not set in result")
+		return nil, lim, errors.New("proxy: _root not set in result")
+	}
+	lim, err = parseLimits(config["_prefix"], config["_quota"], config["_permissions"])
+	if err != nil {
+		return nil, lim, fmt.Errorf("proxy: %w", err)
+	}
+	if lim.prefix != "" {
+		root = path.Join(root, lim.prefix)
 	}
 
 	// Find the backend
 	fsInfo, err := fs.Find(fsName)
 	if err != nil {
-		return nil, fmt.Errorf("proxy: couldn't find backend for %q: %w", fsName, err)
+		return nil, lim, fmt.Errorf("proxy: couldn't find backend for %q: %w", fsName, err)
 	}
 
 	// base name of config on user name.  This may appear in logs
 	name := "proxy-" + user
 	fsString := name + ":" + root
 
-	// Look for fs in the VFS cache
-	value, err = p.vfsCache.Get(user, func(key string) (value any, ok bool, err error) {
+	newFs = func() (fs.Fs, error) {
 		// Create the Fs from the cache
-		f, err := cache.GetFn(p.ctx, fsString, func(ctx context.Context, fsString string) (fs.Fs, error) {
+		return cache.GetFn(p.ctx, fsString, func(ctx context.Context, fsString string) (fs.Fs, error) {
 			// Update the config with the default values
 			for i := range fsInfo.Options {
 				o := &fsInfo.Options[i]
@@ -249,23 +398,8 @@ func (p *Proxy) call(user, auth string, isPublicKey bool) (value any, err error)
 			}
 			return fsInfo.NewFs(ctx, name, root, config)
 		})
-		if err != nil {
-			return nil, false, err
-		}
-
-		// We hash the auth here so we don't copy the auth more than we
-		// need to in memory. An attacker would find it easier to go
-		// after the unencrypted password in memory most likely.
-		entry := cacheEntry{
-			vfs:    vfs.New(f, &p.vfsOpt),
-			pwHash: sha256.Sum256([]byte(auth)),
-		}
-		return entry, true, nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("proxy: failed to create backend: %w", err)
 	}
-	return value, nil
+	return newFs, lim, nil
 }
 
 // Call runs the auth proxy with the username and password/public key provided
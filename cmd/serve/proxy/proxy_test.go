@@ -13,6 +13,7 @@ import (
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/config/obscure"
+	"github.com/rclone/rclone/vfs"
 	"github.com/rclone/rclone/vfs/vfscommon"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,7 +24,7 @@ func TestRun(t *testing.T) {
 	opt := Opt
 	cmd := "go run proxy_code.go"
 	opt.AuthProxy = cmd
-	p := New(context.Background(), &opt, &vfscommon.Opt)
+	p := New(context.Background(), nil, &opt, &vfscommon.Opt)
 
 	t.Run("Normal", func(t *testing.T) {
 		config, err := p.run(map[string]string{
@@ -234,3 +235,23 @@ func TestRun(t *testing.T) {
 		assert.Equal(t, 1, p.vfsCache.Entries())
 	})
 }
+
+func TestParseLimits(t *testing.T) {
+	lim, err := parseLimits("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, limits{perms: vfs.PermAll, quota: -1}, lim)
+
+	lim, err = parseLimits("../../home/me/", "1k", "read-only,delete")
+	require.NoError(t, err)
+	assert.Equal(t, limits{
+		prefix: "home/me",
+		perms:  vfs.PermRead | vfs.PermList | vfs.PermDelete,
+		quota:  1024,
+	}, lim)
+
+	_, err = parseLimits("", "potato", "")
+	assert.ErrorContains(t, err, "bad quota")
+
+	_, err = parseLimits("", "", "read,potato")
+	assert.ErrorContains(t, err, "unknown permission")
+}
@@ -0,0 +1,118 @@
+package proxy
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/fspath"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+// errAuthFailed is returned for unknown users and bad credentials so
+// clients can't find out which users exist
+var errAuthFailed = errors.New("proxy: authentication failed")
+
+// userEntry is a user read from the users file
+type userEntry struct {
+	User        string   `json:"user"`
+	Pass        string   `json:"pass"`
+	PublicKeys  []string `json:"public_keys"`
+	Prefix      string   `json:"prefix"`
+	Quota       string   `json:"quota"`
+	Permissions string   `json:"permissions"`
+}
+
+// readUsersFile reads the users file looking for user
+func readUsersFile(fileName, user string) (*userEntry, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: failed to read users file: %w", err)
+	}
+	var users []userEntry
+	if err = json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("proxy: failed to parse users file %q: %w", fileName, err)
+	}
+	for i := range users {
+		if users[i].User == user {
+			return &users[i], nil
+		}
+	}
+	fs.Debugf(nil, "proxy: unknown user %q", user)
+	return nil, errAuthFailed
+}
+
+// checkPass checks pass against the password in the users file which
+// may be plain text or a bcrypt hash
+func (u *userEntry) checkPass(pass string) bool {
+	if u.Pass == "" {
+		return false
+	}
+	if strings.HasPrefix(u.Pass, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(u.Pass), []byte(pass)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(u.Pass), []byte(pass)) == 1
+}
+
+// checkPublicKey checks the base64 encoded publicKey is one of the
+// user's keys which are in authorized_keys format
+func (u *userEntry) checkPublicKey(publicKey string) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return false
+	}
+	for _, line := range u.PublicKeys {
+		authorizedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			fs.Errorf(nil, "proxy: ignoring bad public key for user %q: %v", u.User, err)
+			continue
+		}
+		if subtle.ConstantTimeCompare(authorizedKey.Marshal(), key) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// callUsersFile authenticates the user from the users file returning
+// a function to create the Fs for the user and their limits
+func (p *Proxy) callUsersFile(user, auth string, isPublicKey bool) (newFs func() (fs.Fs, error), lim limits, err error) {
+	if p.f == nil {
+		return nil, lim, errors.New("proxy: no remote to serve users from")
+	}
+	u, err := readUsersFile(p.Opt.AuthUsersFile, user)
+	if err != nil {
+		return nil, lim, err
+	}
+	if isPublicKey {
+		if !u.checkPublicKey(auth) {
+			fs.Debugf(nil, "proxy: incorrect public key for user %q", user)
+			return nil, lim, errAuthFailed
+		}
+	} else if !u.checkPass(auth) {
+		fs.Debugf(nil, "proxy: incorrect password for user %q", user)
+		return nil, lim, errAuthFailed
+	}
+	lim, err = parseLimits(u.Prefix, u.Quota, u.Permissions)
+	if err != nil {
+		return nil, lim, fmt.Errorf("proxy: user %q: %w", user, err)
+	}
+	newFs = func() (fs.Fs, error) {
+		if lim.prefix == "" {
+			return p.f, nil
+		}
+		f, err := cache.Get(p.ctx, fspath.JoinRootPath(fs.ConfigString(p.f), lim.prefix))
+		if errors.Is(err, fs.ErrorIsFile) {
+			return nil, fmt.Errorf("prefix %q is a file", lim.prefix)
+		}
+		return f, err
+	}
+	return newFs, lim, nil
+}
@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestUsersFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "alice"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), []byte("hello"), 0666))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("alicepass"), bcrypt.MinCost)
+	require.NoError(t, err)
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	publicKey := base64.StdEncoding.EncodeToString(sshPub.Marshal())
+
+	users, err := json.Marshal([]userEntry{{
+		User:        "alice",
+		Pass:        string(hash),
+		Prefix:      "alice",
+		Quota:       "1M",
+		Permissions: "all",
+	}, {
+		User:        "bob",
+		Pass:        "bobpass",
+		PublicKeys:  []string{string(ssh.MarshalAuthorizedKey(sshPub))},
+		Permissions: "read-only",
+	}})
+	require.NoError(t, err)
+	usersFile := filepath.Join(dir, "users.json")
+	require.NoError(t, os.WriteFile(usersFile, users, 0666))
+
+	f, err := fs.NewFs(ctx, root)
+	require.NoError(t, err)
+	opt := Opt
+	opt.AuthUsersFile = usersFile
+	assert.True(t, opt.InUse())
+	p := New(ctx, f, &opt, &vfscommon.Opt)
+
+	t.Run("Prefix", func(t *testing.T) {
+		defer p.vfsCache.Clear()
+		VFS, vfsKey, err := p.Call("alice", "alicepass", false)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", vfsKey)
+		assert.Equal(t, filepath.Join(root, "alice"), VFS.Fs().Root())
+		perms, quota := VFS.Limits()
+		assert.Equal(t, vfs.PermAll, perms)
+		assert.Equal(t, int64(1<<20), quota)
+		_, err = VFS.Stat("file.txt")
+		assert.Equal(t, vfs.ENOENT, err)
+	})
+
+	t.Run("Permissions", func(t *testing.T) {
+		defer p.vfsCache.Clear()
+		VFS, _, err := p.Call("bob", "bobpass", false)
+		require.NoError(t, err)
+		assert.Equal(t, f.Root(), VFS.Fs().Root())
+		perms, quota := VFS.Limits()
+		assert.Equal(t, vfs.PermRead|vfs.PermList, perms)
+		assert.Equal(t, int64(-1), quota)
+		_, err = VFS.Stat("file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, vfs.EPERM, VFS.Remove("file.txt"))
+	})
+
+	t.Run("PublicKey", func(t *testing.T) {
+		defer p.vfsCache.Clear()
+		_, _, err := p.Call("bob", publicKey, true)
+		require.NoError(t, err)
+		p.vfsCache.Clear()
+		_, _, err = p.Call("alice", publicKey, true)
+		assert.Equal(t, errAuthFailed, err)
+	})
+
+	t.Run("BadAuth", func(t *testing.T) {
+		defer p.vfsCache.Clear()
+		// Unknown users can't be told apart from bad passwords
+		_, _, err := p.Call("alice", "bobpass", false)
+		assert.Equal(t, errAuthFailed, err)
+		_, _, err = p.Call("carol", "carolpass", false)
+		assert.Equal(t, errAuthFailed, err)
+		assert.Equal(t, 0, p.vfsCache.Entries())
+	})
+}
@@ -14,7 +14,9 @@ import (
 
 	"github.com/ncw/swift/v2"
 	"github.com/rclone/gofakes3"
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/readers"
 	"github.com/rclone/rclone/vfs"
 )
 
@@ -25,15 +27,21 @@ var (
 // s3Backend implements the gofacess3.Backend interface to make an S3
 // backend for gofakes3
 type s3Backend struct {
-	s    *Server
-	meta *sync.Map
+	s         *Server
+	meta      *sync.Map
+	tags      *sync.Map
+	versionMu sync.Mutex // held while changing versions
+	writeMu   sync.Mutex // protects writing
+	writing   map[objectKey]*objectLock
 }
 
 // newBackend creates a new SimpleBucketBackend.
-func newBackend(s *Server) gofakes3.Backend {
+func newBackend(s *Server) *s3Backend {
 	return &s3Backend{
-		s:    s,
-		meta: new(sync.Map),
+		s:       s,
+		meta:    new(sync.Map),
+		tags:    new(sync.Map),
+		writing: map[objectKey]*objectLock{},
 	}
 }
 
@@ -49,7 +57,7 @@ func (b *s3Backend) ListBuckets(ctx context.Context) ([]gofakes3.BucketInfo, err
 	}
 	var response []gofakes3.BucketInfo
 	for _, entry := range dirEntries {
-		if entry.IsDir() {
+		if entry.IsDir() && entry.Name() != versionsDir {
 			response = append(response, gofakes3.BucketInfo{
 				Name:         entry.Name(),
 				CreationDate: gofakes3.NewContentTime(entry.ModTime()),
@@ -105,9 +113,12 @@ func formatHeaderTime(t time.Time) string {
 }
 
 // HeadObject returns the fileinfo for the given object name.
-//
-// Note that the metadata is not supported yet.
 func (b *s3Backend) HeadObject(ctx context.Context, bucketName, objectName string) (*gofakes3.Object, error) {
+	return b.objectVersion(ctx, bucketName, objectName, "", nil, true)
+}
+
+// headObject returns the fileinfo for the current object.
+func (b *s3Backend) headObject(ctx context.Context, bucketName, objectName string) (*gofakes3.Object, error) {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return nil, err
@@ -157,6 +168,43 @@ func (b *s3Backend) HeadObject(ctx context.Context, bucketName, objectName strin
 
 // GetObject fetches the object from the filesystem.
 func (b *s3Backend) GetObject(ctx context.Context, bucketName, objectName string, rangeRequest *gofakes3.ObjectRangeRequest) (obj *gofakes3.Object, err error) {
+	obj, err = b.objectVersion(ctx, bucketName, objectName, "", rangeRequest, false)
+	if err != nil {
+		return nil, err
+	}
+	return b.auditGet(ctx, bucketName, objectName, obj)
+}
+
+// audited runs fn, which does op on bucketName/objectName (to newPath
+// for copies), and audit logs it.
+//
+// The s3 operations aren't single calls on the VFS so they are logged
+// through the Do hook of the audit VFS for the request.
+func (b *s3Backend) audited(ctx context.Context, op, bucketName, objectName, newPath string, fn func() (int64, error)) error {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return err
+	}
+	return b.s.audit.VFS(ctx, _vfs).Do(op, path.Join(bucketName, objectName), newPath, fn)
+}
+
+// auditGet arranges for the contents of obj to be audit logged as a
+// download when they are read
+func (b *s3Backend) auditGet(ctx context.Context, bucketName, objectName string, obj *gofakes3.Object) (*gofakes3.Object, error) {
+	if obj.IsDeleteMarker || obj.Contents == nil {
+		return obj, nil
+	}
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		_ = obj.Contents.Close()
+		return nil, err
+	}
+	obj.Contents = b.s.audit.VFS(ctx, _vfs).Reader(path.Join(bucketName, objectName), obj.Contents)
+	return obj, nil
+}
+
+// getObject fetches the current object from the filesystem.
+func (b *s3Backend) getObject(ctx context.Context, bucketName, objectName string, rangeRequest *gofakes3.ObjectRangeRequest) (obj *gofakes3.Object, err error) {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return nil, err
@@ -187,30 +235,11 @@ func (b *s3Backend) GetObject(ctx context.Context, bucketName, objectName string
 	size := node.Size()
 	hash := getFileHashByte(fobj, b.s.etagHashType)
 
-	in, err := file.Open(os.O_RDONLY)
-	if err != nil {
-		return nil, gofakes3.ErrInternal
-	}
-	defer func() {
-		// If an error occurs, the caller may not have access to Object.Body in order to close it:
-		if err != nil {
-			_ = in.Close()
-		}
-	}()
-
-	var rdr io.ReadCloser = in
-	rnge, err := rangeRequest.Range(size)
+	rdr, rnge, err := openRange(file, size, rangeRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	if rnge != nil {
-		if _, err := in.Seek(rnge.Start, io.SeekStart); err != nil {
-			return nil, err
-		}
-		rdr = limitReadCloser(rdr, in.Close, rnge.Length)
-	}
-
 	meta := map[string]string{
 		"Last-Modified": formatHeaderTime(node.ModTime()),
 		"Content-Type":  fs.MimeType(context.Background(), fobj),
@@ -291,6 +320,22 @@ func (b *s3Backend) PutObject(
 	bucketName, objectName string,
 	meta map[string]string,
 	input io.Reader, size int64,
+) (result gofakes3.PutObjectResult, err error) {
+	in := readers.NewCountingReader(input)
+	err = b.audited(ctx, audit.OpUpload, bucketName, objectName, "", func() (int64, error) {
+		result, err = b.putObject(ctx, bucketName, objectName, meta, in)
+		return int64(in.BytesRead()), err
+	})
+	return result, err
+}
+
+// putObject creates or overwrites the object with the given name
+// without audit logging it.
+func (b *s3Backend) putObject(
+	ctx context.Context,
+	bucketName, objectName string,
+	meta map[string]string,
+	input io.Reader,
 ) (result gofakes3.PutObjectResult, err error) {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
@@ -301,6 +346,18 @@ func (b *s3Backend) PutObject(
 		return result, gofakes3.BucketNotFound(bucketName)
 	}
 
+	tags, err := tagsFromMeta(meta)
+	if err != nil {
+		return result, err
+	}
+	status, err := b.versioningStatus(_vfs, bucketName)
+	if err != nil {
+		return result, err
+	}
+	if status != gofakes3.VersioningNone {
+		return b.putObjectVersion(_vfs, bucketName, objectName, meta, tags, input, status)
+	}
+
 	fp := path.Join(bucketName, objectName)
 	objectDir := path.Dir(fp)
 	// _, err = db.fs.Stat(objectDir)
@@ -339,6 +396,7 @@ func (b *s3Backend) PutObject(
 	}
 
 	b.meta.Store(fp, meta)
+	b.storeTags(fp, tags)
 
 	if val, ok := meta["X-Amz-Meta-Mtime"]; ok {
 		ti, err := swift.FloatStringToTime(val)
@@ -361,7 +419,7 @@ func (b *s3Backend) PutObject(
 // DeleteMulti deletes multiple objects in a single request.
 func (b *s3Backend) DeleteMulti(ctx context.Context, bucketName string, objects ...string) (result gofakes3.MultiDeleteResult, rerr error) {
 	for _, object := range objects {
-		if err := b.deleteObject(ctx, bucketName, object); err != nil {
+		if _, err := b.deleteObject(ctx, bucketName, object); err != nil {
 			fs.Errorf("serve s3", "delete object failed: %v", err)
 			result.Error = append(result.Error, gofakes3.ErrorResult{
 				Code:    gofakes3.ErrInternal,
@@ -380,34 +438,60 @@ func (b *s3Backend) DeleteMulti(ctx context.Context, bucketName string, objects
 
 // DeleteObject deletes the object with the given name.
 func (b *s3Backend) DeleteObject(ctx context.Context, bucketName, objectName string) (result gofakes3.ObjectDeleteResult, rerr error) {
-	return result, b.deleteObject(ctx, bucketName, objectName)
+	return b.deleteObject(ctx, bucketName, objectName)
 }
 
 // deleteObject deletes the object from the filesystem.
-func (b *s3Backend) deleteObject(ctx context.Context, bucketName, objectName string) error {
+//
+// If the bucket has versioning a delete marker is added instead.
+func (b *s3Backend) deleteObject(ctx context.Context, bucketName, objectName string) (result gofakes3.ObjectDeleteResult, err error) {
+	err = b.audited(ctx, audit.OpDelete, bucketName, objectName, "", func() (int64, error) {
+		result, err = b.removeObject(ctx, bucketName, objectName)
+		return 0, err
+	})
+	return result, err
+}
+
+// removeObject deletes the object without audit logging it
+func (b *s3Backend) removeObject(ctx context.Context, bucketName, objectName string) (result gofakes3.ObjectDeleteResult, err error) {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
-		return err
+		return result, err
 	}
 	_, err = _vfs.Stat(bucketName)
 	if err != nil {
-		return gofakes3.BucketNotFound(bucketName)
+		return result, gofakes3.BucketNotFound(bucketName)
+	}
+	status, err := b.versioningStatus(_vfs, bucketName)
+	if err != nil {
+		return result, err
+	}
+	if status != gofakes3.VersioningNone {
+		return b.deleteObjectVersioned(_vfs, bucketName, objectName, status)
 	}
 
 	fp := path.Join(bucketName, objectName)
 	// S3 does not report an error when attempting to delete a key that does not exist, so
 	// we need to skip IsNotExist errors.
 	if err := _vfs.Remove(fp); err != nil && !os.IsNotExist(err) {
-		return err
+		return result, err
 	}
+	b.tags.Delete(fp)
 
 	// FIXME: unsafe operation
 	rmdirRecursive(fp, _vfs)
-	return nil
+	return result, nil
 }
 
 // CreateBucket creates a new bucket.
 func (b *s3Backend) CreateBucket(ctx context.Context, name string) error {
+	return b.audited(ctx, audit.OpMkdir, name, "", "", func() (int64, error) {
+		return 0, b.createBucket(ctx, name)
+	})
+}
+
+// createBucket creates a new bucket without audit logging it
+func (b *s3Backend) createBucket(ctx context.Context, name string) error {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return err
@@ -429,6 +513,13 @@ func (b *s3Backend) CreateBucket(ctx context.Context, name string) error {
 
 // DeleteBucket deletes the bucket with the given name.
 func (b *s3Backend) DeleteBucket(ctx context.Context, name string) error {
+	return b.audited(ctx, audit.OpDelete, name, "", "", func() (int64, error) {
+		return 0, b.deleteBucket(ctx, name)
+	})
+}
+
+// deleteBucket deletes the bucket without audit logging it
+func (b *s3Backend) deleteBucket(ctx context.Context, name string) error {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return err
@@ -442,11 +533,14 @@ func (b *s3Backend) DeleteBucket(ctx context.Context, name string) error {
 		return gofakes3.ErrBucketNotEmpty
 	}
 
-	return nil
+	return b.removeVersions(_vfs, name)
 }
 
 // BucketExists checks if the bucket exists.
 func (b *s3Backend) BucketExists(ctx context.Context, name string) (exists bool, err error) {
+	if name == versionsDir {
+		return false, nil
+	}
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return false, err
@@ -461,6 +555,15 @@ func (b *s3Backend) BucketExists(ctx context.Context, name string) (exists bool,
 
 // CopyObject copy specified object from srcKey to dstKey.
 func (b *s3Backend) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, meta map[string]string) (result gofakes3.CopyObjectResult, err error) {
+	err = b.audited(ctx, audit.OpCopy, srcBucket, srcKey, path.Join(dstBucket, dstKey), func() (int64, error) {
+		result, err = b.copyObject(ctx, srcBucket, srcKey, dstBucket, dstKey, meta)
+		return 0, err
+	})
+	return result, err
+}
+
+// copyObject copies the object without audit logging it
+func (b *s3Backend) copyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, meta map[string]string) (result gofakes3.CopyObjectResult, err error) {
 	_vfs, err := b.s.getVFS(ctx)
 	if err != nil {
 		return result, err
@@ -490,7 +593,7 @@ func (b *s3Backend) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket
 		return
 	}
 
-	c, err := b.GetObject(ctx, srcBucket, srcKey, nil)
+	c, err := b.objectVersion(ctx, srcBucket, srcKey, "", nil, false)
 	if err != nil {
 		return
 	}
@@ -507,7 +610,7 @@ func (b *s3Backend) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket
 		meta["mtime"] = swift.TimeToFloatString(cStat.ModTime())
 	}
 
-	_, err = b.PutObject(ctx, dstBucket, dstKey, meta, c.Contents, c.Size)
+	_, err = b.putObject(ctx, dstBucket, dstKey, meta, c.Contents)
 	if err != nil {
 		return
 	}
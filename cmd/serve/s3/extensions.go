@@ -0,0 +1,332 @@
+package s3
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/rclone/gofakes3"
+	"github.com/rclone/gofakes3/signature"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// Error codes not known to gofakes3
+const (
+	errInvalidTag                   gofakes3.ErrorCode = "InvalidTag"
+	errPreconditionFailed           gofakes3.ErrorCode = "PreconditionFailed"
+	errAccessDenied                 gofakes3.ErrorCode = "AccessDenied"
+	errInvalidAccessKeyID           gofakes3.ErrorCode = "InvalidAccessKeyId"
+	errSignatureDoesNotMatch        gofakes3.ErrorCode = "SignatureDoesNotMatch"
+	errAuthorizationQueryParameters gofakes3.ErrorCode = "AuthorizationQueryParametersError"
+	errEntityTooLarge               gofakes3.ErrorCode = "EntityTooLarge"
+	errEntityTooSmall               gofakes3.ErrorCode = "EntityTooSmall"
+	errMaxPostPreDataLengthExceeded gofakes3.ErrorCode = "MaxPostPreDataLengthExceededError"
+)
+
+// errorResponse is the XML body of an S3 error
+type errorResponse struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message,omitempty"`
+}
+
+// errorStatus returns the HTTP status for an S3 error code
+func errorStatus(code gofakes3.ErrorCode) int {
+	switch code {
+	case errInvalidTag, errAuthorizationQueryParameters, errEntityTooLarge, errEntityTooSmall, errMaxPostPreDataLengthExceeded:
+		return http.StatusBadRequest
+	case errAccessDenied, errInvalidAccessKeyID, errSignatureDoesNotMatch:
+		return http.StatusForbidden
+	case errPreconditionFailed:
+		return http.StatusPreconditionFailed
+	}
+	return code.Status()
+}
+
+// writeError writes err to the client as an S3 error
+func writeError(rw http.ResponseWriter, r *http.Request, err error) {
+	code := gofakes3.ErrInternal
+	var s3Err gofakes3.Error
+	if errors.As(err, &s3Err) {
+		code = s3Err.ErrorCode()
+	} else {
+		fs.Errorf(r.URL.Path, "serve s3: %v", err)
+	}
+	resp := errorResponse{Code: string(code), Message: code.Message()}
+	var errResp *gofakes3.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Message != "" {
+		resp.Message = errResp.Message
+	}
+	rw.Header().Set("Content-Type", "application/xml")
+	rw.WriteHeader(errorStatus(code))
+	if r.Method != http.MethodHead {
+		_, _ = rw.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(rw).Encode(resp)
+	}
+}
+
+// writeXML writes v to the client as an XML document
+func writeXML(rw http.ResponseWriter, v any) error {
+	rw.Header().Set("Content-Type", "application/xml")
+	_, _ = rw.Write([]byte(xml.Header))
+	return xml.NewEncoder(rw).Encode(v)
+}
+
+// extensionsMiddleware serves the parts of the S3 API which gofakes3
+// doesn't support in the way rclone needs: versioning, object
+// tagging, conditional writes and POST policy uploads. Everything
+// else is passed on to next.
+func extensionsMiddleware(next http.Handler, w *Server) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if err := checkPresignExpiry(query); err != nil {
+			writeError(rw, r, err)
+			return
+		}
+		if bucket, object := w.bucketAndObject(r); isPostPolicy(r, query, object) {
+			// The signature is in the form so is checked by servePostPolicy
+			if err := w.servePostPolicy(rw, r, bucket); err != nil {
+				writeError(rw, r, err)
+			}
+			return
+		}
+		_, isTagging := query["tagging"]
+		isVersions := isVersionRequest(query)
+		isWrite := isObjectWrite(r, query)
+		isConditional := isWrite && isConditional(r)
+		if !isTagging && !isVersions && !isConditional {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		if !w.checkAuth(rw, r) {
+			return
+		}
+		bucket, object := w.bucketAndObject(r)
+		var err error
+		switch {
+		case isTagging:
+			err = w.serveTagging(rw, r, bucket, object)
+		case isVersions:
+			w.versionHandler(r).ServeHTTP(rw, r)
+		default:
+			err = w.serveConditional(rw, r, next, bucket, object)
+		}
+		if err != nil {
+			writeError(rw, r, err)
+		}
+	})
+}
+
+// serveConditional serves a conditional write with next once its
+// preconditions have been checked
+//
+// The object is locked so other conditional writes to it can't race
+// with checking the preconditions. Auth must have been checked first
+// so unauthenticated clients can't hold the lock.
+func (w *Server) serveConditional(rw http.ResponseWriter, r *http.Request, next http.Handler, bucket, object string) error {
+	unlock, err := w.backend.lockObject(r.Context(), bucket, object)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	err = w.backend.checkPreconditions(r.Context(), bucket, object, r.Header)
+	if err != nil {
+		return err
+	}
+	next.ServeHTTP(rw, r)
+	return nil
+}
+
+// isVersionRequest returns true if the request needs the versioning
+// support of the backend
+func isVersionRequest(query url.Values) bool {
+	if _, ok := query["uploadId"]; ok {
+		return false
+	}
+	if _, ok := query["uploads"]; ok {
+		return false
+	}
+	if _, ok := query["versioning"]; ok {
+		return true
+	}
+	if _, ok := query["versions"]; ok {
+		return true
+	}
+	return query.Get("versionId") != ""
+}
+
+// isConditional returns true if the request has an If-Match or
+// If-None-Match header
+func isConditional(r *http.Request) bool {
+	return r.Header.Get("If-Match") != "" || r.Header.Get("If-None-Match") != ""
+}
+
+// isObjectWrite returns true if the request uploads an object
+func isObjectWrite(r *http.Request, query url.Values) bool {
+	if _, ok := query["tagging"]; ok {
+		return false
+	}
+	switch r.Method {
+	case http.MethodPut:
+		// PutObject but not UploadPart
+		return query.Get("uploadId") == ""
+	case http.MethodPost:
+		// CompleteMultipartUpload
+		return query.Get("uploadId") != ""
+	}
+	return false
+}
+
+// checkAuth checks the request is signed in the same way gofakes3
+// does, writing an error and returning false if it isn't
+func (w *Server) checkAuth(rw http.ResponseWriter, r *http.Request) bool {
+	if len(w.opt.AuthKey) == 0 && w.proxy == nil {
+		return true
+	}
+	if result := signature.V4SignVerify(r); result != signature.ErrNone {
+		fs.Infof(r.URL.Path, "%s: Access Denied", r.RemoteAddr)
+		resp := signature.GetAPIError(result)
+		rw.Header().Set("Content-Type", "application/xml")
+		rw.WriteHeader(resp.HTTPStatusCode)
+		_, _ = rw.Write(signature.EncodeAPIErrorToResponse(resp))
+		return false
+	}
+	return true
+}
+
+// bucketAndObject returns the bucket and object the request is for
+func (w *Server) bucketAndObject(r *http.Request) (bucket, object string) {
+	p := strings.Trim(r.URL.Path, "/")
+	if !w.opt.ForcePathStyle {
+		bucket, _, _ = strings.Cut(r.Host, ".")
+		return bucket, p
+	}
+	bucket, object, _ = strings.Cut(p, "/")
+	return bucket, object
+}
+
+// versionHandler returns a gofakes3 handler with versioning enabled
+// for the request.
+//
+// gofakes3 doesn't pass the context to the versioning calls so the
+// backend is bound to the request here, which means the VFS chosen
+// by the auth proxy is used. Auth has already been checked.
+func (w *Server) versionHandler(r *http.Request) http.Handler {
+	query := r.URL.Query()
+	vb := &versionedBackend{
+		s3Backend: w.backend,
+		ctx:       r.Context(),
+		versionID: query.Get("versionId"),
+		urlEncode: query.Get("encoding-type") == "url",
+	}
+	faker := gofakes3.New(
+		vb,
+		gofakes3.WithHostBucket(!w.opt.ForcePathStyle),
+		gofakes3.WithLogger(logger{}),
+		gofakes3.WithRequestID(rand.Uint64()),
+		gofakes3.WithIntegrityCheck(true),
+	)
+	return faker.Server()
+}
+
+// objectLock is a lock on one object held while it is being written
+type objectLock struct {
+	mu    sync.Mutex
+	users int // number of requests holding or waiting for mu
+}
+
+// objectKey identifies an object in the VFS chosen by the auth proxy
+type objectKey struct {
+	vfs *vfs.VFS
+	key string
+}
+
+// lockObject locks the object for a conditional write returning a
+// function to unlock it
+func (b *s3Backend) lockObject(ctx context.Context, bucket, object string) (unlock func(), err error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := objectKey{vfs: _vfs, key: path.Join(bucket, object)}
+	b.writeMu.Lock()
+	l := b.writing[key]
+	if l == nil {
+		l = new(objectLock)
+		b.writing[key] = l
+	}
+	l.users++
+	b.writeMu.Unlock()
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		b.writeMu.Lock()
+		l.users--
+		if l.users == 0 {
+			delete(b.writing, key)
+		}
+		b.writeMu.Unlock()
+	}, nil
+}
+
+// checkPreconditions checks the If-Match and If-None-Match headers of
+// an upload against the current version of the object
+//
+// Call with the object locked so it can't change before it is written.
+func (b *s3Backend) checkPreconditions(ctx context.Context, bucket, object string, h http.Header) error {
+	ifMatch, ifNoneMatch := h.Get("If-Match"), h.Get("If-None-Match")
+	exists := true
+	obj, err := b.HeadObject(ctx, bucket, object)
+	if err != nil {
+		if !gofakes3.HasErrorCode(err, gofakes3.ErrNoSuchKey) {
+			return err
+		}
+		exists = false
+	} else if obj.IsDeleteMarker {
+		exists = false
+	}
+	var etag string
+	if exists {
+		etag = hex.EncodeToString(obj.Hash)
+		if obj.Contents != nil {
+			_ = obj.Contents.Close()
+		}
+	}
+	if ifMatch != "" {
+		if !exists {
+			return gofakes3.KeyNotFound(object)
+		}
+		if !etagMatches(ifMatch, etag) {
+			return errPreconditionFailed
+		}
+	}
+	if ifNoneMatch != "" && exists && etagMatches(ifNoneMatch, etag) {
+		return errPreconditionFailed
+	}
+	return nil
+}
+
+// etagMatches returns true if the list of ETags in an If-Match or
+// If-None-Match header matches etag
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		candidate = strings.TrimPrefix(candidate, "W/")
+		candidate = strings.Trim(candidate, `"`)
+		if candidate != "" && strings.EqualFold(candidate, etag) {
+			return true
+		}
+	}
+	return false
+}
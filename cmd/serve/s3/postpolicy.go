@@ -0,0 +1,459 @@
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/gofakes3"
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/fs"
+	httplib "github.com/rclone/rclone/lib/http"
+)
+
+const (
+	// signV4Algorithm is the only signing algorithm supported
+	signV4Algorithm = "AWS4-HMAC-SHA256"
+
+	// maxPresignExpires is the longest a presigned URL may be valid for
+	maxPresignExpires = 7 * 24 * 60 * 60
+
+	// maxPostFormSize is the maximum size of the form fields, not
+	// including the file, in a POST upload
+	maxPostFormSize = 1024 * 1024
+)
+
+// postForm is a parsed POST policy upload.
+//
+// The field names are lower cased as S3 treats them case
+// insensitively.
+type postForm struct {
+	fields      map[string]string // lower case name => value
+	names       map[string]string // lower case name => name as sent
+	file        io.Reader
+	fileName    string
+	contentType string // of the file part
+}
+
+// postPolicy is the decoded policy document of a POST upload
+type postPolicy struct {
+	Expiration string `json:"expiration"`
+	Conditions []any  `json:"conditions"`
+}
+
+// postResponse is returned when success_action_status is 201
+type postResponse struct {
+	XMLName  xml.Name `xml:"PostResponse"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// isPostPolicy returns true if the request is a browser based POST
+// upload to a bucket
+func isPostPolicy(r *http.Request, query url.Values, object string) bool {
+	if r.Method != http.MethodPost || object != "" || len(query) != 0 {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// checkPresignExpiry checks the X-Amz-Expires parameter of a
+// presigned URL is within the limits S3 allows. The signature and
+// expiry time are checked by gofakes3.
+func checkPresignExpiry(query url.Values) error {
+	if query.Get("X-Amz-Signature") == "" {
+		return nil
+	}
+	expires := query.Get("X-Amz-Expires")
+	if expires == "" {
+		return nil
+	}
+	seconds, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || seconds <= 0 {
+		return gofakes3.ErrorMessage(errAuthorizationQueryParameters, "X-Amz-Expires must be a positive integer")
+	}
+	if seconds > maxPresignExpires {
+		return gofakes3.ErrorMessage(errAuthorizationQueryParameters, "X-Amz-Expires must be less than a week (in seconds) that is 604800")
+	}
+	return nil
+}
+
+// readPostForm reads the form fields up to and including the start
+// of the file
+func readPostForm(r *http.Request) (*postForm, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, gofakes3.ErrorMessage(gofakes3.ErrMalformedPOSTRequest, err.Error())
+	}
+	form := &postForm{
+		fields: map[string]string{},
+		names:  map[string]string{},
+	}
+	size := 0
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, gofakes3.ErrorMessage(gofakes3.ErrIncorrectNumberOfFilesInPostRequest, "POST requires exactly one file upload per request.")
+		}
+		if err != nil {
+			return nil, gofakes3.ErrorMessage(gofakes3.ErrMalformedPOSTRequest, err.Error())
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		if strings.EqualFold(name, "file") {
+			// Any fields after the file are ignored
+			form.file = part
+			form.fileName = part.FileName()
+			form.contentType = part.Header.Get("Content-Type")
+			return form, nil
+		}
+		value, err := io.ReadAll(io.LimitReader(part, int64(maxPostFormSize-size+1)))
+		if err != nil {
+			return nil, gofakes3.ErrorMessage(gofakes3.ErrMalformedPOSTRequest, err.Error())
+		}
+		size += len(name) + len(value)
+		if size > maxPostFormSize {
+			return nil, gofakes3.ErrorMessage(errMaxPostPreDataLengthExceeded, "Your POST request fields preceding the upload file were too large.")
+		}
+		lower := strings.ToLower(name)
+		form.fields[lower] = string(value)
+		form.names[lower] = name
+	}
+}
+
+// signingKey makes the SigV4 signing key for the scope
+func signingKey(secret, date, region string) []byte {
+	sum := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := sum([]byte("AWS4"+secret), date)
+	key = sum(key, region)
+	key = sum(key, "s3")
+	return sum(key, "aws4_request")
+}
+
+// secretFor returns the secret for the access key
+func (w *Server) secretFor(accessKey string) (secret string, ok bool) {
+	if w.proxy != nil {
+		// The auth proxy accepts any access key with the secret
+		return w.s3Secret, true
+	}
+	secret, ok = authlistResolver(w.opt.AuthKey)[accessKey]
+	return secret, ok
+}
+
+// checkPostSignature checks the signature of the policy returning
+// the access key used
+func (w *Server) checkPostSignature(form *postForm) (accessKey string, err error) {
+	if form.fields["policy"] == "" {
+		return "", gofakes3.ErrorMessage(errAccessDenied, "Bucket POST must contain a field named 'policy'.")
+	}
+	if algorithm := form.fields["x-amz-algorithm"]; algorithm != signV4Algorithm {
+		return "", gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "Unsupported x-amz-algorithm %q", algorithm)
+	}
+	if form.fields["x-amz-signature"] == "" {
+		return "", gofakes3.ErrorMessage(errAccessDenied, "Bucket POST must contain a field named 'x-amz-signature'.")
+	}
+	// <access key>/<date>/<region>/s3/aws4_request
+	credential := strings.Split(form.fields["x-amz-credential"], "/")
+	if len(credential) < 5 || credential[len(credential)-2] != "s3" || credential[len(credential)-1] != "aws4_request" {
+		return "", gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid x-amz-credential")
+	}
+	n := len(credential)
+	accessKey = strings.Join(credential[:n-4], "/")
+	date, region := credential[n-4], credential[n-3]
+	secret, ok := w.secretFor(accessKey)
+	if !ok {
+		return "", errInvalidAccessKeyID
+	}
+	h := hmac.New(sha256.New, signingKey(secret, date, region))
+	h.Write([]byte(form.fields["policy"]))
+	want := hex.EncodeToString(h.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(strings.ToLower(form.fields["x-amz-signature"]))) {
+		return "", errSignatureDoesNotMatch
+	}
+	return accessKey, nil
+}
+
+// policyDenied makes the error for a form which doesn't match the policy
+func policyDenied(format string, args ...any) error {
+	return gofakes3.ErrorMessage(errAccessDenied, "Invalid according to Policy: "+fmt.Sprintf(format, args...))
+}
+
+// checkPolicy checks the form against the policy returning the
+// allowed range for the size of the file
+//
+// key is the key the file will be uploaded to after ${filename} has
+// been substituted. Conditions on the key must hold both for the key
+// as submitted in the form and for this.
+func checkPolicy(form *postForm, bucket string, key string, now time.Time) (minSize, maxSize int64, err error) {
+	minSize, maxSize = 0, -1
+	data, err := base64.StdEncoding.DecodeString(form.fields["policy"])
+	if err != nil {
+		return 0, 0, gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid 'Base64' encoding.")
+	}
+	var policy postPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return 0, 0, gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "Invalid Policy: %v", err)
+	}
+	expiration, err := time.Parse(time.RFC3339, policy.Expiration)
+	if err != nil {
+		return 0, 0, gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid 'expiration' value.")
+	}
+	if now.After(expiration) {
+		return 0, 0, policyDenied("Policy expired.")
+	}
+
+	values := make(map[string]string, len(form.fields)+1)
+	for name, value := range form.fields {
+		values[name] = value
+	}
+	values["bucket"] = bucket
+	checked := map[string]bool{}
+	check := func(op, name, want string) error {
+		name = strings.ToLower(strings.TrimPrefix(name, "$"))
+		gots := []string{values[name]}
+		if name == "key" && key != values[name] {
+			gots = append(gots, key)
+		}
+		checked[name] = true
+		for _, got := range gots {
+			switch op {
+			case "eq":
+				if got != want {
+					return policyDenied("Policy Condition failed: [\"eq\", \"$%s\", %q]", name, want)
+				}
+			case "starts-with":
+				if !strings.HasPrefix(got, want) {
+					return policyDenied("Policy Condition failed: [\"starts-with\", \"$%s\", %q]", name, want)
+				}
+			default:
+				return gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid condition %q", op)
+			}
+		}
+		return nil
+	}
+	for _, condition := range policy.Conditions {
+		switch condition := condition.(type) {
+		case map[string]any:
+			// {"name": "value"} is the same as ["eq", "$name", "value"]
+			for name, want := range condition {
+				s, ok := want.(string)
+				if !ok {
+					return 0, 0, gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid condition for %q", name)
+				}
+				if err := check("eq", name, s); err != nil {
+					return 0, 0, err
+				}
+			}
+		case []any:
+			if len(condition) != 3 {
+				return 0, 0, gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid Policy: Wrong number of arguments in condition.")
+			}
+			op, _ := condition[0].(string)
+			op = strings.ToLower(op)
+			if op == "content-length-range" {
+				lo, okLo := condition[1].(float64)
+				hi, okHi := condition[2].(float64)
+				if !okLo || !okHi || lo < 0 || hi < lo {
+					return 0, 0, gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid content-length-range.")
+				}
+				minSize, maxSize = int64(lo), int64(hi)
+				continue
+			}
+			name, okName := condition[1].(string)
+			want, okWant := condition[2].(string)
+			if !okName || !okWant {
+				return 0, 0, gofakes3.ErrorMessagef(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid %q condition.", op)
+			}
+			if err := check(op, name, want); err != nil {
+				return 0, 0, err
+			}
+		default:
+			return 0, 0, gofakes3.ErrorMessage(gofakes3.ErrInvalidArgument, "Invalid Policy: Invalid condition.")
+		}
+	}
+
+	// Every field in the form must be covered by the policy
+	for name := range form.fields {
+		switch {
+		case name == "policy", name == "x-amz-signature", strings.HasPrefix(name, "x-ignore-"):
+		case !checked[name]:
+			return 0, 0, policyDenied("Extra input fields: %s", form.names[name])
+		}
+	}
+	return minSize, maxSize, nil
+}
+
+// checkKey checks the key is safe to join to the bucket name.
+//
+// Keys with a leading "/" or with "." or ".." segments would escape
+// the prefix given in the policy, or the bucket, after the path is
+// cleaned.
+func checkKey(key string) error {
+	if strings.HasPrefix(key, "/") {
+		return gofakes3.ErrorInvalidArgument("key", key, "Key must not start with '/'.")
+	}
+	for segment := range strings.SplitSeq(key, "/") {
+		if segment == "." || segment == ".." {
+			return gofakes3.ErrorInvalidArgument("key", key, "Key must not contain '.' or '..' segments.")
+		}
+	}
+	return nil
+}
+
+// postMeta makes the object metadata from the form fields in the
+// same way gofakes3 does from the headers of a PUT
+func postMeta(form *postForm) map[string]string {
+	meta := map[string]string{}
+	for lower, value := range form.fields {
+		name := http.CanonicalHeaderKey(form.names[lower])
+		switch {
+		case strings.HasPrefix(name, "X-Amz-Meta-"), name == "X-Amz-Tagging", name == "X-Amz-Storage-Class":
+		case strings.HasPrefix(name, "Content-"), name == "Cache-Control", name == "Expires":
+		default:
+			continue
+		}
+		meta[name] = value
+	}
+	if _, ok := meta["Content-Type"]; !ok && form.contentType != "" {
+		meta["Content-Type"] = form.contentType
+	}
+	return meta
+}
+
+// sizeCheckReader returns an error if the data read isn't within
+// the size range
+type sizeCheckReader struct {
+	in       io.Reader
+	n        int64
+	min, max int64 // max < 0 for no limit
+}
+
+// Read implements io.Reader
+func (r *sizeCheckReader) Read(p []byte) (n int, err error) {
+	n, err = r.in.Read(p)
+	r.n += int64(n)
+	if r.max >= 0 && r.n > r.max {
+		return n, gofakes3.ErrorMessage(errEntityTooLarge, "Your proposed upload exceeds the maximum allowed size")
+	}
+	if err == io.EOF && r.n < r.min {
+		return n, gofakes3.ErrorMessage(errEntityTooSmall, "Your proposed upload is smaller than the minimum allowed size")
+	}
+	return n, err
+}
+
+// servePostPolicy handles browser based uploads using a POST
+// policy.
+//
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
+func (w *Server) servePostPolicy(rw http.ResponseWriter, r *http.Request, bucket string) error {
+	rw.Header().Set("Access-Control-Allow-Origin", "*")
+	rw.Header().Set("Access-Control-Expose-Headers", "ETag, Location")
+
+	form, err := readPostForm(r)
+	if err != nil {
+		return err
+	}
+
+	ctx := r.Context()
+	minSize, maxSize := int64(0), int64(-1)
+	if len(w.opt.AuthKey) > 0 || w.proxy != nil {
+		accessKey, err := w.checkPostSignature(form)
+		if err != nil {
+			return err
+		}
+		if _, ok := httplib.CtxGetUser(ctx); !ok {
+			ctx = audit.WithUser(ctx, accessKey)
+		}
+		if w.proxy != nil {
+			// The access key isn't in a header so the auth proxy
+			// middleware couldn't find the VFS
+			VFS, err := w.auth(accessKey)
+			if err != nil {
+				fs.Infof(r.URL.Path, "%s: Auth failed: %v", r.RemoteAddr, err)
+				return errAccessDenied
+			}
+			ctx = context.WithValue(ctx, ctxKeyID, VFS)
+		}
+	}
+	key := form.fields["key"]
+	if key == "" {
+		return gofakes3.ErrorInvalidArgument("key", "", "Bucket POST must contain a field named 'key'.  If it is specified, please check the order of the fields.")
+	}
+	key = strings.ReplaceAll(key, "${filename}", form.fileName)
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if form.fields["policy"] != "" {
+		minSize, maxSize, err = checkPolicy(form, bucket, key, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	hasher := md5.New()
+	in := io.TeeReader(&sizeCheckReader{in: form.file, min: minSize, max: maxSize}, hasher)
+	result, err := w.backend.PutObject(ctx, bucket, key, postMeta(form), in, -1)
+	if err != nil {
+		return err
+	}
+
+	etag := `"` + hex.EncodeToString(hasher.Sum(nil)) + `"`
+	location := &url.URL{Scheme: "http", Host: r.Host, Path: "/" + key}
+	if r.TLS != nil {
+		location.Scheme = "https"
+	}
+	if w.opt.ForcePathStyle {
+		location.Path = "/" + bucket + location.Path
+	}
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Location", location.String())
+	if result.VersionID != "" {
+		rw.Header().Set("x-amz-version-id", string(result.VersionID))
+	}
+
+	if redirect := form.fields["success_action_redirect"]; redirect != "" {
+		u, err := url.Parse(redirect)
+		if err == nil {
+			q := u.Query()
+			q.Set("bucket", bucket)
+			q.Set("key", key)
+			q.Set("etag", etag)
+			u.RawQuery = q.Encode()
+			http.Redirect(rw, r, u.String(), http.StatusSeeOther)
+			return nil
+		}
+	}
+	switch form.fields["success_action_status"] {
+	case "200":
+		rw.WriteHeader(http.StatusOK)
+	case "201":
+		rw.Header().Set("Content-Type", "application/xml")
+		rw.WriteHeader(http.StatusCreated)
+		return writeXML(rw, postResponse{Location: location.String(), Bucket: bucket, Key: key, ETag: etag})
+	default:
+		rw.WriteHeader(http.StatusNoContent)
+	}
+	return nil
+}
@@ -6,18 +6,29 @@ package s3
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/servetest"
 	"github.com/rclone/rclone/fs"
@@ -291,9 +302,621 @@ func TestListBucketsAuthProxy(t *testing.T) {
 	testListBuckets(t, cases, true)
 }
 
+func TestListBucketsAuthUsersFile(t *testing.T) {
+	fstest.Initialise()
+	f, err := fs.NewFs(context.Background(), "testdata")
+	require.NoError(t, err)
+
+	// The user is the MD5 of the access key
+	keyid := random.String(16)
+	users := fmt.Sprintf(`[{"user": %q, "pass": %q, "permissions": "read-only"}]`, stringToMd5Hash(keyid), keyid)
+	usersFile := filepath.Join(t.TempDir(), "users.json")
+	require.NoError(t, os.WriteFile(usersFile, []byte(users), 0666))
+	proxy.Opt.AuthUsersFile = usersFile
+	defer func() {
+		proxy.Opt.AuthUsersFile = ""
+	}()
+
+	endpoint, _, keysec, s := serveS3(t, f)
+	defer func() {
+		assert.NoError(t, s.server.Shutdown())
+	}()
+	testURL, _ := url.Parse(endpoint)
+	for _, test := range []struct {
+		keyid string
+		ok    bool
+	}{
+		{keyid, true},
+		{random.String(16), false},
+	} {
+		client, err := minio.New(testURL.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(test.keyid, keysec, ""),
+			Secure: false,
+		})
+		require.NoError(t, err)
+		buckets, err := client.ListBuckets(context.Background())
+		if !test.ok {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.NotEmpty(t, buckets)
+		assert.Equal(t, "mybucket", buckets[0].Name)
+
+		// The user's permissions are applied
+		_, err = client.PutObject(context.Background(), "mybucket", "new.txt", strings.NewReader("hello"), 5, minio.PutObjectOptions{})
+		assert.Error(t, err)
+	}
+}
+
 func TestRc(t *testing.T) {
 	servetest.TestRc(t, rc.Params{
 		"type":           "s3",
 		"vfs_cache_mode": "off",
 	})
 }
+
+// Start a server on a fresh remote with bucket in and return a minio
+// client connected to it
+func newMinioTest(t *testing.T, bucket string) (ctx context.Context, client *minio.Client) {
+	ctx = context.Background()
+	fstest.Initialise()
+	f, _, clean, err := fstest.RandomRemote()
+	require.NoError(t, err)
+	t.Cleanup(clean)
+	require.NoError(t, f.Mkdir(ctx, bucket))
+
+	endpoint, keyid, keysec, _ := serveS3(t, f)
+	testURL, err := url.Parse(endpoint)
+	require.NoError(t, err)
+	client, err = minio.New(testURL.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(keyid, keysec, ""),
+		Secure: false,
+	})
+	require.NoError(t, err)
+	return ctx, client
+}
+
+// Read the contents of an object
+func readMinioObject(ctx context.Context, t *testing.T, client *minio.Client, bucket, key string, opts minio.GetObjectOptions) (string, error) {
+	obj, err := client.GetObject(ctx, bucket, key, opts)
+	require.NoError(t, err)
+	defer func() {
+		_ = obj.Close()
+	}()
+	data, err := io.ReadAll(obj)
+	return string(data), err
+}
+
+func TestVersioningWithMinioClient(t *testing.T) {
+	const bucket, key = "bucket", "dir/file.txt"
+	ctx, client := newMinioTest(t, bucket)
+
+	put := func(contents string) minio.UploadInfo {
+		info, err := client.PutObject(ctx, bucket, key, bytes.NewBufferString(contents), int64(len(contents)), minio.PutObjectOptions{})
+		require.NoError(t, err)
+		return info
+	}
+	listVersions := func() (versions []minio.ObjectInfo) {
+		for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true, WithVersions: true}) {
+			require.NoError(t, obj.Err)
+			versions = append(versions, obj)
+		}
+		return versions
+	}
+
+	// Upload before versioning is enabled
+	put("zero")
+
+	config, err := client.GetBucketVersioning(ctx, bucket)
+	require.NoError(t, err)
+	assert.False(t, config.Enabled())
+	require.NoError(t, client.EnableVersioning(ctx, bucket))
+	config, err = client.GetBucketVersioning(ctx, bucket)
+	require.NoError(t, err)
+	assert.True(t, config.Enabled())
+
+	one := put("one")
+	two := put("two")
+	require.NotEqual(t, "", one.VersionID)
+	require.NotEqual(t, "", two.VersionID)
+	assert.NotEqual(t, one.VersionID, two.VersionID)
+
+	versions := listVersions()
+	require.Len(t, versions, 3)
+	assert.Equal(t, two.VersionID, versions[0].VersionID)
+	assert.True(t, versions[0].IsLatest)
+	assert.Equal(t, one.VersionID, versions[1].VersionID)
+	assert.Equal(t, "null", versions[2].VersionID)
+
+	for _, test := range []struct {
+		versionID string
+		want      string
+	}{
+		{"", "two"},
+		{two.VersionID, "two"},
+		{one.VersionID, "one"},
+		{"null", "zero"},
+	} {
+		got, err := readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{VersionID: test.versionID})
+		require.NoError(t, err, test.versionID)
+		assert.Equal(t, test.want, got, test.versionID)
+	}
+
+	// Deleting the object makes a delete marker
+	require.NoError(t, client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{}))
+	_, err = client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	assert.Equal(t, "NoSuchKey", minio.ToErrorResponse(err).Code)
+	versions = listVersions()
+	require.Len(t, versions, 4)
+	assert.True(t, versions[0].IsDeleteMarker)
+	got, err := readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{VersionID: one.VersionID})
+	require.NoError(t, err)
+	assert.Equal(t, "one", got)
+
+	// Deleting the delete marker restores the object
+	require.NoError(t, client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{VersionID: versions[0].VersionID}))
+	got, err = readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "two", got)
+
+	// Deleting the current version makes the previous one current
+	require.NoError(t, client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{VersionID: two.VersionID}))
+	got, err = readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "one", got)
+	assert.Len(t, listVersions(), 2)
+
+	// The versions aren't visible as a bucket
+	buckets, err := client.ListBuckets(ctx)
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	assert.Equal(t, bucket, buckets[0].Name)
+}
+
+func TestTaggingWithMinioClient(t *testing.T) {
+	const bucket = "bucket"
+	ctx, client := newMinioTest(t, bucket)
+
+	for _, versioned := range []bool{false, true} {
+		t.Run(fmt.Sprintf("versioned=%v", versioned), func(t *testing.T) {
+			if versioned {
+				require.NoError(t, client.EnableVersioning(ctx, bucket))
+			}
+			key := fmt.Sprintf("file-%v.txt", versioned)
+			_, err := client.PutObject(ctx, bucket, key, bytes.NewBufferString("hello"), 5, minio.PutObjectOptions{
+				UserTags: map[string]string{"colour": "blue"},
+			})
+			require.NoError(t, err)
+
+			getTags := func() map[string]string {
+				got, err := client.GetObjectTagging(ctx, bucket, key, minio.GetObjectTaggingOptions{})
+				require.NoError(t, err)
+				return got.ToMap()
+			}
+			assert.Equal(t, map[string]string{"colour": "blue"}, getTags())
+
+			newTags, err := tags.NewTags(map[string]string{"a": "1", "b": "two words"}, true)
+			require.NoError(t, err)
+			require.NoError(t, client.PutObjectTagging(ctx, bucket, key, newTags, minio.PutObjectTaggingOptions{}))
+			assert.Equal(t, map[string]string{"a": "1", "b": "two words"}, getTags())
+
+			// Tags don't change the object
+			got, err := readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "hello", got)
+
+			require.NoError(t, client.RemoveObjectTagging(ctx, bucket, key, minio.RemoveObjectTaggingOptions{}))
+			assert.Empty(t, getTags())
+
+			_, err = client.GetObjectTagging(ctx, bucket, "missing", minio.GetObjectTaggingOptions{})
+			assert.Equal(t, "NoSuchKey", minio.ToErrorResponse(err).Code)
+		})
+	}
+}
+
+func TestConditionalWritesWithMinioClient(t *testing.T) {
+	const bucket, key = "bucket", "file.txt"
+	ctx, client := newMinioTest(t, bucket)
+
+	put := func(contents string, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+		return client.PutObject(ctx, bucket, key, bytes.NewBufferString(contents), int64(len(contents)), opts)
+	}
+	assertPreconditionFailed := func(err error) {
+		t.Helper()
+		require.Error(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, minio.ToErrorResponse(err).StatusCode)
+	}
+
+	// If-None-Match: * only writes new objects
+	var opts minio.PutObjectOptions
+	opts.SetMatchETagExcept("*")
+	info, err := put("one", opts)
+	require.NoError(t, err)
+	_, err = put("two", opts)
+	assertPreconditionFailed(err)
+
+	// If-Match only writes if the ETag matches
+	opts = minio.PutObjectOptions{}
+	opts.SetMatchETag("00000000000000000000000000000000")
+	_, err = put("two", opts)
+	assertPreconditionFailed(err)
+	opts = minio.PutObjectOptions{}
+	opts.SetMatchETag(info.ETag)
+	_, err = put("two", opts)
+	require.NoError(t, err)
+
+	got, err := readMinioObject(ctx, t, client, bucket, key, minio.GetObjectOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "two", got)
+
+	// Only one of many concurrent If-None-Match: * writes succeeds
+	const n = 10
+	var (
+		wg      sync.WaitGroup
+		written atomic.Int32
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := minio.PutObjectOptions{}
+			opts.SetMatchETagExcept("*")
+			contents := fmt.Sprintf("race %d", i)
+			_, err := client.PutObject(ctx, bucket, "race.txt", bytes.NewBufferString(contents), int64(len(contents)), opts)
+			if err == nil {
+				written.Add(1)
+			} else {
+				assert.Equal(t, http.StatusPreconditionFailed, minio.ToErrorResponse(err).StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), written.Load())
+}
+
+// Uploads with a body which never arrives mustn't block conditional
+// writes to the same object
+func TestConditionalWriteNotBlocked(t *testing.T) {
+	const bucket, key = "bucket", "file.txt"
+	ctx := context.Background()
+	fstest.Initialise()
+	f, _, clean, err := fstest.RandomRemote()
+	require.NoError(t, err)
+	t.Cleanup(clean)
+	require.NoError(t, f.Mkdir(ctx, bucket))
+	endpoint, keyid, keysec, _ := serveS3(t, f)
+	testURL, err := url.Parse(endpoint)
+	require.NoError(t, err)
+	client, err := minio.New(testURL.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(keyid, keysec, ""),
+		Secure: false,
+	})
+	require.NoError(t, err)
+
+	// Start an unsigned conditional upload which sends the headers
+	// but not all of the body
+	conn, err := net.Dial("tcp", testURL.Host)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err = fmt.Fprintf(conn, "PUT /%s/%s HTTP/1.1\r\nHost: %s\r\nContent-Length: 1024\r\n\r\nslow", bucket, key, testURL.Host)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	// Start a signed unconditional upload which sends the headers but
+	// not all of the body
+	pr, pw := io.Pipe()
+	slowCtx, slowCancel := context.WithCancel(ctx)
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := client.PutObject(slowCtx, bucket, key, pr, 1024, minio.PutObjectOptions{DisableContentSha256: true})
+		slowDone <- err
+	}()
+	_, err = pw.Write([]byte("slow"))
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	// A signed conditional upload to the same object goes through
+	putCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var opts minio.PutObjectOptions
+	opts.SetMatchETagExcept("*")
+	_, err = client.PutObject(putCtx, bucket, key, bytes.NewBufferString("signed"), 6, opts)
+	require.NoError(t, err)
+
+	slowCancel()
+	_ = pw.Close()
+	<-slowDone
+}
+
+func TestPresignedURLsWithMinioClient(t *testing.T) {
+	const bucket = "bucket"
+	ctx, client := newMinioTest(t, bucket)
+
+	// Presigned PUT
+	u, err := client.PresignedPutObject(ctx, bucket, "dir/hello world.txt", time.Hour)
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewBufferString("hello"))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Presigned GET
+	u, err = client.PresignedGetObject(ctx, bucket, "dir/hello world.txt", time.Hour, nil)
+	require.NoError(t, err)
+	get := func(u string) (int, string) {
+		resp, err := http.Get(u)
+		require.NoError(t, err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+	status, body := get(u.String())
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello", body)
+
+	// Tampering with the URL breaks the signature
+	tampered := *u
+	tampered.Path = "/" + bucket + "/dir/other.txt"
+	status, body = get(tampered.String())
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, "SignatureDoesNotMatch")
+
+	// Out of range expiry is rejected
+	query := u.Query()
+	query.Set("X-Amz-Expires", "604801")
+	tampered.Path = u.Path
+	tampered.RawQuery = query.Encode()
+	status, body = get(tampered.String())
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "AuthorizationQueryParametersError")
+}
+
+// Upload file using the POST policy form returning the response
+func postPolicyUpload(ctx context.Context, t *testing.T, u *url.URL, formData map[string]string, contents string) *http.Response {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range formData {
+		require.NoError(t, mw.WriteField(name, value))
+	}
+	fw, err := mw.CreateFormFile("file", "upload.txt")
+	require.NoError(t, err)
+	_, err = io.WriteString(fw, contents)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestPostPolicyWithMinioClient(t *testing.T) {
+	const bucket = "bucket"
+	ctx, client := newMinioTest(t, bucket)
+
+	newPolicy := func(key string) *minio.PostPolicy {
+		policy := minio.NewPostPolicy()
+		require.NoError(t, policy.SetBucket(bucket))
+		require.NoError(t, policy.SetKey(key))
+		require.NoError(t, policy.SetExpires(time.Now().Add(time.Hour)))
+		require.NoError(t, policy.SetContentLengthRange(1, 10))
+		return policy
+	}
+	readBody := func(resp *http.Response) string {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		policy := newPolicy("uploads/one.txt")
+		require.NoError(t, policy.SetUserMetadata("colour", "blue"))
+		u, formData, err := client.PresignedPostPolicy(ctx, policy)
+		require.NoError(t, err)
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		_ = readBody(resp)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, resp.Header.Get("ETag"))
+
+		got, err := readMinioObject(ctx, t, client, bucket, "uploads/one.txt", minio.GetObjectOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", got)
+		info, err := client.StatObject(ctx, bucket, "uploads/one.txt", minio.StatObjectOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "blue", info.UserMetadata["Colour"])
+	})
+
+	t.Run("Status201", func(t *testing.T) {
+		policy := newPolicy("uploads/two.txt")
+		require.NoError(t, policy.SetSuccessStatusAction("201"))
+		u, formData, err := client.PresignedPostPolicy(ctx, policy)
+		require.NoError(t, err)
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Contains(t, body, "<Key>uploads/two.txt</Key>")
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		u, formData, err := client.PresignedPostPolicy(ctx, newPolicy("uploads/big.txt"))
+		require.NoError(t, err)
+		resp := postPolicyUpload(ctx, t, u, formData, "this is more than 10 bytes")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "EntityTooLarge")
+		_, err = client.StatObject(ctx, bucket, "uploads/big.txt", minio.StatObjectOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		u, formData, err := client.PresignedPostPolicy(ctx, newPolicy("uploads/three.txt"))
+		require.NoError(t, err)
+		formData["key"] = "uploads/other.txt"
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "Invalid according to Policy")
+	})
+
+	t.Run("KeyTraversal", func(t *testing.T) {
+		policy := minio.NewPostPolicy()
+		require.NoError(t, policy.SetBucket(bucket))
+		require.NoError(t, policy.SetKeyStartsWith("uploads/"))
+		require.NoError(t, policy.SetExpires(time.Now().Add(time.Hour)))
+		u, formData, err := client.PresignedPostPolicy(ctx, policy)
+		require.NoError(t, err)
+		formData["key"] = "uploads/../../escape.txt"
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "InvalidArgument")
+	})
+
+	t.Run("ExtraField", func(t *testing.T) {
+		u, formData, err := client.PresignedPostPolicy(ctx, newPolicy("uploads/four.txt"))
+		require.NoError(t, err)
+		formData["x-amz-meta-extra"] = "not in policy"
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "Extra input fields")
+	})
+
+	t.Run("BadSignature", func(t *testing.T) {
+		u, formData, err := client.PresignedPostPolicy(ctx, newPolicy("uploads/five.txt"))
+		require.NoError(t, err)
+		formData["x-amz-signature"] = strings.Repeat("0", 64)
+		resp := postPolicyUpload(ctx, t, u, formData, "hello")
+		body := readBody(resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, body, "SignatureDoesNotMatch")
+	})
+}
+
+func TestCheckPolicyExpired(t *testing.T) {
+	policy := base64.StdEncoding.EncodeToString([]byte(`{"expiration": "2020-01-01T00:00:00.000Z", "conditions": [{"bucket": "bucket"}, ["starts-with", "$key", ""]]}`))
+	form := &postForm{
+		fields: map[string]string{"key": "file.txt", "policy": policy},
+		names:  map[string]string{"key": "key", "policy": "policy"},
+	}
+	_, _, err := checkPolicy(form, "bucket", "file.txt", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	_, _, err = checkPolicy(form, "bucket", "file.txt", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "Policy expired")
+	_, _, err = checkPolicy(form, "other", "file.txt", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "Policy Condition failed")
+}
+
+func TestCheckPolicyFilename(t *testing.T) {
+	policy := base64.StdEncoding.EncodeToString([]byte(`{"expiration": "2020-01-01T00:00:00.000Z", "conditions": [{"bucket": "bucket"}, ["starts-with", "$key", "uploads/a"]]}`))
+	form := &postForm{
+		fields: map[string]string{"key": "uploads/a${filename}", "policy": policy},
+		names:  map[string]string{"key": "key", "policy": "policy"},
+	}
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, _, err := checkPolicy(form, "bucket", "uploads/afile.txt", now)
+	require.NoError(t, err)
+	// The key after substitution must match the policy too
+	_, _, err = checkPolicy(form, "bucket", "uploads/b.txt", now)
+	assert.ErrorContains(t, err, "Policy Condition failed")
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, test := range []struct {
+		key string
+		ok  bool
+	}{
+		{"file.txt", true},
+		{"uploads/file.txt", true},
+		{"uploads/..file.txt", true},
+		{"/file.txt", false},
+		{"../file.txt", false},
+		{"uploads/../../file.txt", false},
+		{"uploads/./file.txt", false},
+		{"uploads/..", false},
+	} {
+		err := checkKey(test.key)
+		if test.ok {
+			assert.NoError(t, err, test.key)
+		} else {
+			assert.Error(t, err, test.key)
+		}
+	}
+}
+
+func TestAuditLogWithMinioClient(t *testing.T) {
+	oldOpt := audit.Opt
+	t.Cleanup(func() { audit.Opt = oldOpt })
+
+	for _, anonymous := range []bool{false, true} {
+		t.Run(fmt.Sprintf("anonymous=%v", anonymous), func(t *testing.T) {
+			ctx := context.Background()
+			fstest.Initialise()
+			f, _, clean, err := fstest.RandomRemote()
+			require.NoError(t, err)
+			t.Cleanup(clean)
+			const bucket = "bucket"
+			require.NoError(t, f.Mkdir(ctx, bucket))
+
+			logFile := filepath.Join(t.TempDir(), "audit.log")
+			audit.Opt = audit.Options{AuditLog: logFile}
+			keyid, keysec := random.String(16), random.String(16)
+			opt := Opt // copy default options
+			if !anonymous {
+				opt.AuthKey = []string{fmt.Sprintf("%s,%s", keyid, keysec)}
+			}
+			opt.HTTP.ListenAddr = []string{endpoint}
+			w, err := newServer(ctx, f, &opt, &vfscommon.Opt, &proxy.Opt)
+			require.NoError(t, err)
+			go func() {
+				require.NoError(t, w.Serve())
+			}()
+			testURL, err := url.Parse(w.server.URLs()[0])
+			require.NoError(t, err)
+			newClient := func(secret string) *minio.Client {
+				client, err := minio.New(testURL.Host, &minio.Options{
+					Creds: credentials.NewStaticV4(keyid, secret, ""),
+				})
+				require.NoError(t, err)
+				return client
+			}
+
+			// A request with the wrong secret isn't logged as the user
+			_, err = newClient("wrong").PutObject(ctx, bucket, "bad.txt", strings.NewReader("bad"), 3, minio.PutObjectOptions{})
+			if !anonymous {
+				require.Error(t, err)
+			}
+			_, err = newClient(keysec).PutObject(ctx, bucket, "good.txt", strings.NewReader("good"), 4, minio.PutObjectOptions{})
+			require.NoError(t, err)
+			require.NoError(t, w.Shutdown())
+
+			data, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			users := map[string]string{}
+			for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+				var record audit.Record
+				require.NoError(t, json.Unmarshal([]byte(line), &record))
+				assert.Equal(t, audit.OpUpload, record.Op)
+				users[record.Path] = record.User
+			}
+			if anonymous {
+				// The signature isn't checked so the access key isn't trusted
+				assert.Equal(t, map[string]string{"/bucket/bad.txt": "", "/bucket/good.txt": ""}, users)
+			} else {
+				assert.Equal(t, map[string]string{"/bucket/good.txt": keyid}, users)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/rclone/gofakes3"
 	"github.com/rclone/gofakes3/signature"
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/hash"
@@ -27,6 +28,7 @@ type ctxKey int
 
 const (
 	ctxKeyID ctxKey = iota
+	ctxKeyAccessKey
 )
 
 // Server is a s3.FileSystem interface
@@ -36,11 +38,13 @@ type Server struct {
 	f            fs.Fs
 	_vfs         *vfs.VFS // don't use directly, use getVFS
 	faker        *gofakes3.GoFakeS3
+	backend      *s3Backend
 	handler      http.Handler
 	proxy        *proxy.Proxy
 	ctx          context.Context // for global config
 	s3Secret     string
 	etagHashType hash.Type
+	audit        *audit.Logger
 }
 
 // Make a new S3 Server to serve the remote
@@ -64,6 +68,11 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 		fs.Debugf(f, "Using hash %v for ETag", w.etagHashType)
 	}
 
+	w.audit, err = audit.New("s3", &audit.Opt)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(opt.AuthKey) == 0 {
 		fs.Logf("serve s3", "No auth provided so allowing anonymous access")
 	} else {
@@ -71,8 +80,9 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 	}
 
 	var newLogger logger
+	w.backend = newBackend(w)
 	w.faker = gofakes3.New(
-		newBackend(w),
+		w.backend,
 		gofakes3.WithHostBucket(!opt.ForcePathStyle),
 		gofakes3.WithLogger(newLogger),
 		gofakes3.WithRequestID(rand.Uint64()),
@@ -82,9 +92,10 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 	)
 
 	w.handler = w.faker.Server()
+	w.handler = extensionsMiddleware(w.handler, w)
 
-	if proxy.Opt.AuthProxy != "" {
-		w.proxy = proxy.New(ctx, proxyOpt, vfsOpt)
+	if proxyOpt.InUse() {
+		w.proxy = proxy.New(ctx, f, proxyOpt, vfsOpt)
 		// proxy auth middleware
 		w.handler = proxyAuthMiddleware(w.handler, w)
 		w.handler = authPairMiddleware(w.handler, w)
@@ -95,6 +106,10 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 			w.faker.AddAuthKeys(authlistResolver(opt.AuthKey))
 		}
 	}
+	w.handler = accessKeyMiddleware(w.handler, w)
+	if w.audit != nil {
+		w.handler = auditMiddleware(w.handler)
+	}
 
 	w.server, err = httplib.NewServer(ctx,
 		httplib.WithConfig(opt.HTTP),
@@ -156,12 +171,56 @@ func (w *Server) Addr() net.Addr {
 
 // Shutdown the server
 func (w *Server) Shutdown() error {
-	return w.server.Shutdown()
+	err := w.server.Shutdown()
+	if auditErr := w.audit.Close(); err == nil {
+		err = auditErr
+	}
+	return err
 }
 
-func authPairMiddleware(next http.Handler, ws *Server) http.Handler {
+// auditMiddleware identifies the client for the audit log
+//
+// The user is only known here if the HTTP auth checked it. Otherwise
+// it is set to the access key by accessKeyMiddleware.
+func auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := httplib.CtxGetUser(r.Context())
+		r = r.WithContext(audit.WithConn(r.Context(), user, r.RemoteAddr))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accessKeyMiddleware reads the access key the request is signed with
+// once and passes it down in the context to the auth middleware and
+// the audit log.
+//
+// If auth is in use the access key is recorded as the user for the
+// audit log. Requests whose signature doesn't match are rejected by
+// gofakes3 (or checkAuth) before they do anything, so only requests
+// which really were signed with the key are logged with it. Without
+// auth the signature isn't checked so the key isn't trusted.
+func accessKeyMiddleware(next http.Handler, ws *Server) http.Handler {
+	checked := len(ws.opt.AuthKey) > 0 || ws.proxy != nil
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		accessKey, _ := parseAccessKeyID(r)
+		ctx := context.WithValue(r.Context(), ctxKeyAccessKey, accessKey)
+		if _, ok := httplib.CtxGetUser(ctx); !ok && checked && accessKey != "" {
+			ctx = audit.WithUser(ctx, accessKey)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessKeyFromContext returns the access key stored by
+// accessKeyMiddleware
+func accessKeyFromContext(ctx context.Context) string {
+	accessKey, _ := ctx.Value(ctxKeyAccessKey).(string)
+	return accessKey
+}
+
+func authPairMiddleware(next http.Handler, ws *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessKey := accessKeyFromContext(r.Context())
 		// set the auth pair
 		authPair := map[string]string{
 			accessKey: ws.s3Secret,
@@ -173,7 +232,7 @@ func authPairMiddleware(next http.Handler, ws *Server) http.Handler {
 
 func proxyAuthMiddleware(next http.Handler, ws *Server) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		accessKey, _ := parseAccessKeyID(r)
+		accessKey := accessKeyFromContext(r.Context())
 		value, err := ws.auth(accessKey)
 		if err != nil {
 			fs.Infof(r.URL.Path, "%s: Auth failed: %v", r.RemoteAddr, err)
@@ -188,6 +247,15 @@ func proxyAuthMiddleware(next http.Handler, ws *Server) http.Handler {
 
 func parseAccessKeyID(r *http.Request) (accessKey string, error signature.ErrorCode) {
 	v4Auth := r.Header.Get("Authorization")
+	if v4Auth == "" {
+		// presigned URL
+		query := r.URL.Query()
+		if query.Get("X-Amz-Signature") != "" {
+			v4Auth = fmt.Sprintf("%s Credential=%s, SignedHeaders=%s, Signature=%s",
+				query.Get("X-Amz-Algorithm"), query.Get("X-Amz-Credential"),
+				query.Get("X-Amz-SignedHeaders"), query.Get("X-Amz-Signature"))
+		}
+	}
 	req, err := signature.ParseSignV4(v4Auth)
 	if err != signature.ErrNone {
 		return "", err
@@ -0,0 +1,225 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/rclone/gofakes3"
+)
+
+// Limits on object tags from the S3 docs
+const (
+	maxTags           = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// tagging is the XML document used by the object tagging calls
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  struct {
+		Tags []tag `xml:"Tag"`
+	} `xml:"TagSet"`
+}
+
+// tag is a single object tag
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// checkTag checks a tag against the S3 limits
+func checkTag(tags map[string]string, key, value string) error {
+	if _, found := tags[key]; found {
+		return gofakes3.ErrorMessagef(errInvalidTag, "Cannot provide multiple Tags with the same key %q", key)
+	}
+	if key == "" || utf8.RuneCountInString(key) > maxTagKeyLength {
+		return gofakes3.ErrorMessagef(errInvalidTag, "The TagKey you have provided is invalid: %q", key)
+	}
+	if utf8.RuneCountInString(value) > maxTagValueLength {
+		return gofakes3.ErrorMessagef(errInvalidTag, "The TagValue you have provided is invalid: %q", value)
+	}
+	if len(tags) >= maxTags {
+		return gofakes3.ErrorMessage(errInvalidTag, "Object tags cannot be greater than 10")
+	}
+	return nil
+}
+
+// tagsFromMeta removes the tagging headers from the metadata of an
+// upload returning the tags set by the X-Amz-Tagging header
+func tagsFromMeta(meta map[string]string) (tags map[string]string, err error) {
+	header, ok := meta["X-Amz-Tagging"]
+	delete(meta, "X-Amz-Tagging")
+	delete(meta, "X-Amz-Tagging-Count")
+	delete(meta, "X-Amz-Tagging-Directive")
+	if !ok || header == "" {
+		return nil, nil
+	}
+	values, err := url.ParseQuery(header)
+	if err != nil {
+		return nil, gofakes3.ErrorMessage(errInvalidTag, "The header 'x-amz-tagging' shall be encoded as UTF-8 then URLEncoded URL query parameters without tag name duplicates.")
+	}
+	tags = make(map[string]string, len(values))
+	for key, vals := range values {
+		if len(vals) != 1 {
+			return nil, gofakes3.ErrorMessagef(errInvalidTag, "Cannot provide multiple Tags with the same key %q", key)
+		}
+		if err := checkTag(tags, key, vals[0]); err != nil {
+			return nil, err
+		}
+		tags[key] = vals[0]
+	}
+	return tags, nil
+}
+
+// storeTags sets the tags of an object in a bucket without versioning
+func (b *s3Backend) storeTags(fp string, tags map[string]string) {
+	if len(tags) == 0 {
+		b.tags.Delete(fp)
+	} else {
+		b.tags.Store(fp, tags)
+	}
+}
+
+// objectTags reads the tags of a version of an object, or the
+// current version if versionID is empty, returning them and the
+// version ID.
+//
+// If replace is set the tags are replaced with newTags.
+func (b *s3Backend) objectTags(ctx context.Context, bucket, object, versionID string, replace bool, newTags map[string]string) (tags map[string]string, id string, err error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return nil, "", gofakes3.BucketNotFound(bucket)
+	}
+	status, err := b.versioningStatus(_vfs, bucket)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if status == gofakes3.VersioningNone {
+		if versionID != "" && versionID != nullVersionID {
+			return nil, "", gofakes3.ResourceError(gofakes3.ErrNoSuchVersion, versionID)
+		}
+		fp := path.Join(bucket, object)
+		node, err := _vfs.Stat(fp)
+		if err != nil || !node.IsFile() {
+			return nil, "", gofakes3.KeyNotFound(object)
+		}
+		if replace {
+			b.storeTags(fp, newTags)
+			return newTags, "", nil
+		}
+		if val, ok := b.tags.Load(fp); ok {
+			tags = val.(map[string]string)
+		}
+		return tags, "", nil
+	}
+
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	idx, err := b.loadIndex(_vfs, bucket, object)
+	if err != nil {
+		return nil, "", err
+	}
+	v, err := idx.find(versionID)
+	if err != nil {
+		return nil, "", err
+	}
+	if v.DeleteMarker {
+		return nil, "", gofakes3.ErrMethodNotAllowed
+	}
+	if replace {
+		v.Tags = newTags
+		if err := b.saveIndex(_vfs, bucket, idx); err != nil {
+			return nil, "", err
+		}
+	}
+	return v.Tags, v.ID, nil
+}
+
+// serveTagging handles the GetObjectTagging, PutObjectTagging and
+// DeleteObjectTagging calls
+func (w *Server) serveTagging(rw http.ResponseWriter, r *http.Request, bucket, object string) error {
+	if object == "" {
+		// Bucket tagging isn't supported
+		return gofakes3.ErrNotImplemented
+	}
+	ctx := r.Context()
+	versionID := r.URL.Query().Get("versionId")
+	var (
+		tags map[string]string
+		id   string
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		tags, id, err = w.backend.objectTags(ctx, bucket, object, versionID, false, nil)
+	case http.MethodPut:
+		tags, err = readTagging(r.Body)
+		if err != nil {
+			return err
+		}
+		_, id, err = w.backend.objectTags(ctx, bucket, object, versionID, true, tags)
+	case http.MethodDelete:
+		_, id, err = w.backend.objectTags(ctx, bucket, object, versionID, true, nil)
+	default:
+		return gofakes3.ErrMethodNotAllowed
+	}
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		rw.Header().Set("x-amz-version-id", id)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		out := tagging{Xmlns: "http://s3.amazonaws.com/doc/2006-03-01/"}
+		for _, key := range sortedKeys(tags) {
+			out.TagSet.Tags = append(out.TagSet.Tags, tag{Key: key, Value: tags[key]})
+		}
+		return writeXML(rw, out)
+	case http.MethodDelete:
+		rw.WriteHeader(http.StatusNoContent)
+	}
+	return nil
+}
+
+// readTagging reads the tags from the body of a PutObjectTagging call
+func readTagging(body io.Reader) (map[string]string, error) {
+	var in tagging
+	if err := xml.NewDecoder(body).Decode(&in); err != nil {
+		return nil, gofakes3.ErrorMessage(gofakes3.ErrMalformedXML, err.Error())
+	}
+	tags := make(map[string]string, len(in.TagSet.Tags))
+	for _, t := range in.TagSet.Tags {
+		if err := checkTag(tags, t.Key, t.Value); err != nil {
+			return nil, err
+		}
+		tags[t.Key] = t.Value
+	}
+	return tags, nil
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range maps.Keys(m) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -85,6 +85,34 @@ func getFileHash(node any, hashType hash.Type) string {
 	return hash
 }
 
+// openRange opens the file for reading the range requested
+func openRange(file *vfs.File, size int64, rangeRequest *gofakes3.ObjectRangeRequest) (rdr io.ReadCloser, rnge *gofakes3.ObjectRange, err error) {
+	in, err := file.Open(os.O_RDONLY)
+	if err != nil {
+		return nil, nil, gofakes3.ErrInternal
+	}
+	defer func() {
+		// If an error occurs, the caller may not have access to Object.Body in order to close it:
+		if err != nil {
+			_ = in.Close()
+		}
+	}()
+
+	rdr = in
+	rnge, err = rangeRequest.Range(size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rnge != nil {
+		if _, err = in.Seek(rnge.Start, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+		rdr = limitReadCloser(rdr, in.Close, rnge.Length)
+	}
+	return rdr, rnge, nil
+}
+
 func prefixParser(p *gofakes3.Prefix) (path, remaining string) {
 	idx := strings.LastIndexByte(p.Prefix, '/')
 	if idx < 0 {
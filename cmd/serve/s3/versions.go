@@ -0,0 +1,844 @@
+package s3
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/swift/v2"
+	"github.com/rclone/gofakes3"
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/vfs"
+)
+
+// Object versioning
+//
+// The versioning state of each bucket is kept in a hidden directory
+// in the root of the remote, versionsDir, which isn't a valid bucket
+// name so can't be accessed over S3. For each bucket it holds
+//
+//	versioning        - the versioning status "Enabled" or "Suspended"
+//	index/<hash>.json - the versionIndex of each object with versions
+//	data/<hash>/<id>  - the contents of the non current versions
+//	tmp/              - uploads in progress
+//
+// where <hash> is the MD5 of the object key.
+//
+// The current version of an object stays where it would be without
+// versioning so the remote looks the same to other users of it.
+
+const (
+	versionsDir    = ".rclone-versions"
+	versioningFile = "versioning"
+	nullVersionID  = "null"
+)
+
+// objectVersion describes one version of an object
+type objectVersion struct {
+	ID           string            `json:"id"`
+	DeleteMarker bool              `json:"delete_marker,omitempty"`
+	Time         time.Time         `json:"time"`           // when the version was created
+	Size         int64             `json:"size,omitempty"` // set when archived
+	Hash         string            `json:"hash,omitempty"` // set when archived
+	Meta         map[string]string `json:"meta,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// versionIndex holds the versions of an object newest first
+//
+// If the first version isn't a delete marker then it is the current
+// version of the object.
+type versionIndex struct {
+	Key      string           `json:"key"`
+	Versions []*objectVersion `json:"versions"`
+}
+
+// isCurrent returns true if v is the current version of the object
+func (idx *versionIndex) isCurrent(v *objectVersion) bool {
+	return len(idx.Versions) > 0 && idx.Versions[0] == v && !v.DeleteMarker
+}
+
+// find returns the version with versionID or the current version if
+// versionID is empty
+func (idx *versionIndex) find(versionID string) (*objectVersion, error) {
+	if versionID == "" {
+		if len(idx.Versions) == 0 || idx.Versions[0].DeleteMarker {
+			return nil, gofakes3.KeyNotFound(idx.Key)
+		}
+		return idx.Versions[0], nil
+	}
+	for _, v := range idx.Versions {
+		if v.ID == versionID {
+			return v, nil
+		}
+	}
+	return nil, gofakes3.ResourceError(gofakes3.ErrNoSuchVersion, versionID)
+}
+
+// remove removes the version from the index
+func (idx *versionIndex) remove(v *objectVersion) {
+	idx.Versions = slices.DeleteFunc(idx.Versions, func(x *objectVersion) bool {
+		return x == v
+	})
+}
+
+// deleteMarker returns an object representing the delete marker v
+func (idx *versionIndex) deleteMarker(v *objectVersion) *gofakes3.Object {
+	return &gofakes3.Object{
+		Name:           idx.Key,
+		VersionID:      gofakes3.VersionID(v.ID),
+		IsDeleteMarker: true,
+		Contents:       noOpReadCloser{},
+	}
+}
+
+// versionsPath returns the path of elem in the versions directory of
+// the bucket
+func versionsPath(bucket string, elem ...string) string {
+	return path.Join(append([]string{versionsDir, bucket}, elem...)...)
+}
+
+// indexPath returns the path of the versionIndex of key
+func indexPath(bucket, key string) string {
+	return versionsPath(bucket, "index", stringToMd5Hash(key)+".json")
+}
+
+// versionDataPath returns the path of the contents of a non current
+// version of key
+func versionDataPath(bucket, key, versionID string) string {
+	return versionsPath(bucket, "data", stringToMd5Hash(key), versionID)
+}
+
+// newVersionID returns the ID for a new version
+func newVersionID(status gofakes3.VersioningStatus) string {
+	if status == gofakes3.VersioningSuspended {
+		return nullVersionID
+	}
+	return random.String(32)
+}
+
+// versioningStatus reads the versioning status of the bucket
+func (b *s3Backend) versioningStatus(_vfs *vfs.VFS, bucket string) (gofakes3.VersioningStatus, error) {
+	data, err := _vfs.ReadFile(versionsPath(bucket, versioningFile))
+	if errors.Is(err, vfs.ENOENT) {
+		return gofakes3.VersioningNone, nil
+	} else if err != nil {
+		return gofakes3.VersioningNone, err
+	}
+	return gofakes3.VersioningStatus(strings.TrimSpace(string(data))), nil
+}
+
+// loadIndex reads the versionIndex of key
+//
+// If there is an object which isn't in the index, because it was
+// written before versioning was enabled or directly to the remote,
+// it is added as the null version.
+func (b *s3Backend) loadIndex(_vfs *vfs.VFS, bucket, key string) (*versionIndex, error) {
+	idx := &versionIndex{Key: key}
+	data, err := _vfs.ReadFile(indexPath(bucket, key))
+	if err == nil {
+		err = json.Unmarshal(data, idx)
+	} else if errors.Is(err, vfs.ENOENT) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(idx.Versions) > 0 && !idx.Versions[0].DeleteMarker {
+		return idx, nil
+	}
+	fp := path.Join(bucket, key)
+	node, err := _vfs.Stat(fp)
+	if err != nil || !node.IsFile() {
+		return idx, nil
+	}
+	v := &objectVersion{
+		ID:   nullVersionID,
+		Time: node.ModTime(),
+	}
+	if val, ok := b.meta.Load(fp); ok {
+		v.Meta = val.(map[string]string)
+	}
+	if val, ok := b.tags.Load(fp); ok {
+		v.Tags = val.(map[string]string)
+	}
+	idx.Versions = append([]*objectVersion{v}, idx.Versions...)
+	return idx, nil
+}
+
+// saveIndex writes the versionIndex, removing it if there are no
+// versions left
+func (b *s3Backend) saveIndex(_vfs *vfs.VFS, bucket string, idx *versionIndex) error {
+	p := indexPath(bucket, idx.Key)
+	if len(idx.Versions) == 0 {
+		if err := _vfs.Remove(p); err != nil && !errors.Is(err, vfs.ENOENT) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := _vfs.MkdirAll(path.Dir(p), 0777); err != nil {
+		return err
+	}
+	return _vfs.WriteFile(p, data, 0666)
+}
+
+// archiveCurrent moves the current version of the object out of the
+// way before a new version is added
+//
+// If versioning is suspended the null version is removed instead as
+// the new version replaces it.
+func (b *s3Backend) archiveCurrent(_vfs *vfs.VFS, bucket string, idx *versionIndex, status gofakes3.VersioningStatus) error {
+	fp := path.Join(bucket, idx.Key)
+	if len(idx.Versions) > 0 && !idx.Versions[0].DeleteMarker {
+		v := idx.Versions[0]
+		node, err := _vfs.Stat(fp)
+		switch {
+		case errors.Is(err, vfs.ENOENT):
+			// Removed from the remote directly
+			idx.remove(v)
+		case err != nil:
+			return err
+		case status == gofakes3.VersioningSuspended && v.ID == nullVersionID:
+			if err := b.removeVersionData(_vfs, bucket, idx, v); err != nil {
+				return err
+			}
+			idx.remove(v)
+		default:
+			v.Size = node.Size()
+			v.Hash = getFileHash(node, b.s.etagHashType)
+			if val, ok := b.meta.Load(fp); ok {
+				v.Meta = val.(map[string]string)
+			}
+			dst := versionDataPath(bucket, idx.Key, v.ID)
+			if err := _vfs.MkdirAll(path.Dir(dst), 0777); err != nil {
+				return err
+			}
+			if err := _vfs.Rename(fp, dst); err != nil {
+				return err
+			}
+			b.meta.Delete(fp)
+			b.tags.Delete(fp)
+		}
+	}
+	if status == gofakes3.VersioningSuspended {
+		if v, err := idx.find(nullVersionID); err == nil {
+			if err := b.removeVersionData(_vfs, bucket, idx, v); err != nil {
+				return err
+			}
+			idx.remove(v)
+		}
+	}
+	return nil
+}
+
+// removeVersionData removes the stored contents of v
+func (b *s3Backend) removeVersionData(_vfs *vfs.VFS, bucket string, idx *versionIndex, v *objectVersion) error {
+	if v.DeleteMarker {
+		return nil
+	}
+	if idx.isCurrent(v) {
+		fp := path.Join(bucket, idx.Key)
+		if err := _vfs.Remove(fp); err != nil && !errors.Is(err, vfs.ENOENT) {
+			return err
+		}
+		b.meta.Delete(fp)
+		b.tags.Delete(fp)
+		return nil
+	}
+	p := versionDataPath(bucket, idx.Key, v.ID)
+	if err := _vfs.Remove(p); err != nil && !errors.Is(err, vfs.ENOENT) {
+		return err
+	}
+	// remove the directory if it is now empty
+	_ = _vfs.Remove(path.Dir(p))
+	return nil
+}
+
+// restoreCurrent makes the newest version the current version again
+// after the version above it has been removed
+func (b *s3Backend) restoreCurrent(_vfs *vfs.VFS, bucket string, idx *versionIndex) error {
+	fp := path.Join(bucket, idx.Key)
+	if len(idx.Versions) == 0 || idx.Versions[0].DeleteMarker {
+		rmdirRecursive(fp, _vfs)
+		return nil
+	}
+	v := idx.Versions[0]
+	src := versionDataPath(bucket, idx.Key, v.ID)
+	if objectDir := path.Dir(fp); objectDir != "." {
+		if err := mkdirRecursive(objectDir, _vfs); err != nil {
+			return err
+		}
+	}
+	if err := _vfs.Rename(src, fp); err != nil {
+		return err
+	}
+	_ = _vfs.Remove(path.Dir(src))
+	if v.Meta != nil {
+		b.meta.Store(fp, v.Meta)
+	}
+	return nil
+}
+
+// setModTime sets the modification time of the object from its
+// metadata if present
+func (b *s3Backend) setModTime(_vfs *vfs.VFS, fp string, meta map[string]string) error {
+	for _, key := range []string{"X-Amz-Meta-Mtime", "mtime"} {
+		if val, ok := meta[key]; ok {
+			ti, err := swift.FloatStringToTime(val)
+			if err == nil {
+				b.storeModtime(fp, meta, val)
+				return _vfs.Chtimes(fp, ti, ti)
+			}
+		}
+	}
+	return nil
+}
+
+// putObjectVersion uploads a new version of an object to a bucket
+// with versioning
+func (b *s3Backend) putObjectVersion(_vfs *vfs.VFS, bucket, key string, meta, tags map[string]string, input io.Reader, status gofakes3.VersioningStatus) (result gofakes3.PutObjectResult, err error) {
+	// Upload to a temporary file first so the current version is
+	// left alone if the upload fails
+	tmp := versionsPath(bucket, "tmp", random.String(16))
+	if err := _vfs.MkdirAll(path.Dir(tmp), 0777); err != nil {
+		return result, err
+	}
+	f, err := _vfs.Create(tmp)
+	if err != nil {
+		return result, err
+	}
+	if _, err := io.Copy(f, input); err != nil {
+		_ = f.Close()
+		_ = _vfs.Remove(tmp)
+		return result, err
+	}
+	if err := f.Close(); err != nil {
+		_ = _vfs.Remove(tmp)
+		return result, err
+	}
+
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	idx, err := b.loadIndex(_vfs, bucket, key)
+	if err == nil {
+		err = b.archiveCurrent(_vfs, bucket, idx, status)
+	}
+	if err != nil {
+		_ = _vfs.Remove(tmp)
+		return result, err
+	}
+
+	fp := path.Join(bucket, key)
+	if objectDir := path.Dir(fp); objectDir != "." {
+		if err := mkdirRecursive(objectDir, _vfs); err != nil {
+			return result, err
+		}
+	}
+	if err := _vfs.Rename(tmp, fp); err != nil {
+		_ = _vfs.Remove(tmp)
+		return result, err
+	}
+	b.meta.Store(fp, meta)
+	if err := b.setModTime(_vfs, fp, meta); err != nil {
+		return result, err
+	}
+
+	v := &objectVersion{
+		ID:   newVersionID(status),
+		Time: time.Now(),
+		Meta: meta,
+		Tags: tags,
+	}
+	idx.Versions = append([]*objectVersion{v}, idx.Versions...)
+	if err := b.saveIndex(_vfs, bucket, idx); err != nil {
+		return result, err
+	}
+	result.VersionID = gofakes3.VersionID(v.ID)
+	return result, nil
+}
+
+// deleteObjectVersioned adds a delete marker to an object in a bucket
+// with versioning
+func (b *s3Backend) deleteObjectVersioned(_vfs *vfs.VFS, bucket, key string, status gofakes3.VersioningStatus) (result gofakes3.ObjectDeleteResult, err error) {
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	idx, err := b.loadIndex(_vfs, bucket, key)
+	if err != nil {
+		return result, err
+	}
+	if err := b.archiveCurrent(_vfs, bucket, idx, status); err != nil {
+		return result, err
+	}
+	rmdirRecursive(path.Join(bucket, key), _vfs)
+
+	v := &objectVersion{
+		ID:           newVersionID(status),
+		DeleteMarker: true,
+		Time:         time.Now(),
+	}
+	idx.Versions = append([]*objectVersion{v}, idx.Versions...)
+	if err := b.saveIndex(_vfs, bucket, idx); err != nil {
+		return result, err
+	}
+	result.IsDeleteMarker = true
+	result.VersionID = gofakes3.VersionID(v.ID)
+	return result, nil
+}
+
+// deleteObjectVersion permanently deletes a version of an object
+func (b *s3Backend) deleteObjectVersion(ctx context.Context, bucket, key, versionID string) (result gofakes3.ObjectDeleteResult, err error) {
+	err = b.audited(ctx, audit.OpDelete, bucket, key, "", func() (int64, error) {
+		result, err = b.removeObjectVersion(ctx, bucket, key, versionID)
+		return 0, err
+	})
+	return result, err
+}
+
+// removeObjectVersion permanently deletes a version of an object
+// without audit logging it
+func (b *s3Backend) removeObjectVersion(ctx context.Context, bucket, key, versionID string) (result gofakes3.ObjectDeleteResult, err error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return result, err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return result, gofakes3.BucketNotFound(bucket)
+	}
+
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	idx, err := b.loadIndex(_vfs, bucket, key)
+	if err != nil {
+		return result, err
+	}
+	v, err := idx.find(versionID)
+	if err != nil {
+		// S3 doesn't return an error for missing versions
+		return result, nil
+	}
+	current := idx.Versions[0] == v
+	if err := b.removeVersionData(_vfs, bucket, idx, v); err != nil {
+		return result, err
+	}
+	idx.remove(v)
+	if current {
+		if err := b.restoreCurrent(_vfs, bucket, idx); err != nil {
+			return result, err
+		}
+	}
+	if err := b.saveIndex(_vfs, bucket, idx); err != nil {
+		return result, err
+	}
+	result.IsDeleteMarker = v.DeleteMarker
+	result.VersionID = gofakes3.VersionID(v.ID)
+	return result, nil
+}
+
+// objectVersion fetches a version of an object, or the current
+// version if versionID is empty.
+//
+// If head is set the contents aren't opened.
+func (b *s3Backend) objectVersion(ctx context.Context, bucket, key, versionID string, rangeRequest *gofakes3.ObjectRangeRequest, head bool) (obj *gofakes3.Object, err error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return nil, gofakes3.BucketNotFound(bucket)
+	}
+	status, err := b.versioningStatus(_vfs, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := path.Join(bucket, key)
+	var tags map[string]string
+	if status == gofakes3.VersioningNone {
+		if versionID != "" && versionID != nullVersionID {
+			return nil, gofakes3.ResourceError(gofakes3.ErrNoSuchVersion, versionID)
+		}
+		if head {
+			obj, err = b.headObject(ctx, bucket, key)
+		} else {
+			obj, err = b.getObject(ctx, bucket, key, rangeRequest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if val, ok := b.tags.Load(fp); ok {
+			tags = val.(map[string]string)
+		}
+	} else {
+		b.versionMu.Lock()
+		defer b.versionMu.Unlock()
+
+		idx, err := b.loadIndex(_vfs, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		if versionID == "" && len(idx.Versions) > 0 && idx.Versions[0].DeleteMarker {
+			return idx.deleteMarker(idx.Versions[0]), nil
+		}
+		v, err := idx.find(versionID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case v.DeleteMarker:
+			return idx.deleteMarker(v), nil
+		case !idx.isCurrent(v):
+			obj, err = b.readVersion(_vfs, bucket, key, v, rangeRequest, head)
+		case head:
+			obj, err = b.headObject(ctx, bucket, key)
+		default:
+			obj, err = b.getObject(ctx, bucket, key, rangeRequest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for k, val := range v.Meta {
+			if _, found := obj.Metadata[k]; !found {
+				obj.Metadata[k] = val
+			}
+		}
+		obj.VersionID = gofakes3.VersionID(v.ID)
+		tags = v.Tags
+	}
+	if len(tags) > 0 {
+		obj.Metadata["X-Amz-Tagging-Count"] = strconv.Itoa(len(tags))
+	}
+	return obj, nil
+}
+
+// readVersion fetches a non current version of an object
+func (b *s3Backend) readVersion(_vfs *vfs.VFS, bucket, key string, v *objectVersion, rangeRequest *gofakes3.ObjectRangeRequest, head bool) (*gofakes3.Object, error) {
+	node, err := _vfs.Stat(versionDataPath(bucket, key, v.ID))
+	if err != nil || !node.IsFile() {
+		return nil, gofakes3.ResourceError(gofakes3.ErrNoSuchVersion, v.ID)
+	}
+	hash, _ := hex.DecodeString(v.Hash)
+	meta := map[string]string{
+		"Last-Modified": formatHeaderTime(v.Time),
+		"Content-Type":  fs.MimeTypeFromName(key),
+	}
+	maps.Copy(meta, v.Meta)
+	obj := &gofakes3.Object{
+		Name:     key,
+		Hash:     hash,
+		Metadata: meta,
+		Size:     v.Size,
+		Contents: noOpReadCloser{},
+	}
+	if head {
+		return obj, nil
+	}
+	obj.Contents, obj.Range, err = openRange(node.(*vfs.File), v.Size, rangeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// listBucketVersions lists all the versions of the objects in the bucket
+func (b *s3Backend) listBucketVersions(ctx context.Context, bucket string, prefix *gofakes3.Prefix, page *gofakes3.ListBucketVersionsPage) (*gofakes3.ListBucketVersionsResult, error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return nil, gofakes3.BucketNotFound(bucket)
+	}
+	if prefix == nil {
+		prefix = emptyPrefix
+	}
+
+	// same workaround as ListBucket
+	if strings.TrimSpace(prefix.Prefix) == "" {
+		prefix.HasPrefix = false
+	}
+	if strings.TrimSpace(prefix.Delimiter) == "" {
+		prefix.HasDelimiter = false
+	}
+	if page == nil {
+		page = &gofakes3.ListBucketVersionsPage{}
+	}
+	maxKeys := page.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	// Find the current objects
+	current := gofakes3.NewObjectList()
+	err = b.entryListR(_vfs, bucket, "", "", false, current)
+	if err != nil && err != gofakes3.ErrNoSuchKey {
+		return nil, err
+	}
+	currentByKey := make(map[string]*gofakes3.Content, len(current.Contents))
+	for _, item := range current.Contents {
+		currentByKey[item.Key] = item
+	}
+
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+
+	// Find the objects with versions
+	keys := make(map[string]struct{}, len(currentByKey))
+	for key := range currentByKey {
+		keys[key] = struct{}{}
+	}
+	entries, err := getDirEntries(versionsPath(bucket, "index"), _vfs)
+	if err != nil && err != gofakes3.ErrNoSuchKey {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := _vfs.ReadFile(versionsPath(bucket, "index", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var idx versionIndex
+		if err := json.Unmarshal(data, &idx); err != nil {
+			fs.Errorf("serve s3", "Ignoring corrupted version index %q: %v", entry.Name(), err)
+			continue
+		}
+		keys[idx.Key] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	result := gofakes3.NewListBucketVersionsResult(bucket, prefix, page)
+	var (
+		match gofakes3.PrefixMatch
+		count int64
+	)
+outer:
+	for _, key := range sortedKeys {
+		if !prefix.Match(key, &match) {
+			continue
+		}
+		if match.CommonPrefix {
+			if !page.HasKeyMarker || match.MatchedPart > page.KeyMarker {
+				result.AddPrefix(match.MatchedPart)
+			}
+			continue
+		}
+		started := !page.HasKeyMarker || key > page.KeyMarker
+		if !started && (key < page.KeyMarker || !page.HasVersionIDMarker) {
+			continue
+		}
+		idx, err := b.loadIndex(_vfs, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range idx.Versions {
+			if !started {
+				started = v.ID == string(page.VersionIDMarker)
+				continue
+			}
+			if count >= maxKeys {
+				result.IsTruncated = true
+				break outer
+			}
+			count++
+			result.NextKeyMarker = key
+			result.NextVersionIDMarker = gofakes3.VersionID(v.ID)
+			if v.DeleteMarker {
+				result.Versions = append(result.Versions, &gofakes3.DeleteMarker{
+					Key:          key,
+					VersionID:    gofakes3.VersionID(v.ID),
+					IsLatest:     i == 0,
+					LastModified: gofakes3.NewContentTime(v.Time),
+				})
+				continue
+			}
+			item := &gofakes3.Version{
+				Key:          key,
+				VersionID:    gofakes3.VersionID(v.ID),
+				IsLatest:     i == 0,
+				LastModified: gofakes3.NewContentTime(v.Time),
+				Size:         v.Size,
+				StorageClass: gofakes3.StorageStandard,
+				ETag:         `"` + v.Hash + `"`,
+			}
+			if c, ok := currentByKey[key]; ok && i == 0 {
+				item.Size = c.Size
+				item.ETag = `"` + c.ETag + `"`
+			}
+			result.Versions = append(result.Versions, item)
+		}
+	}
+	if !result.IsTruncated {
+		result.NextKeyMarker = ""
+		result.NextVersionIDMarker = ""
+	}
+	return result, nil
+}
+
+// versioningConfiguration reads the versioning configuration of the bucket
+func (b *s3Backend) versioningConfiguration(ctx context.Context, bucket string) (config gofakes3.VersioningConfiguration, err error) {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return config, err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return config, gofakes3.BucketNotFound(bucket)
+	}
+	config.Status, err = b.versioningStatus(_vfs, bucket)
+	return config, err
+}
+
+// setVersioningConfiguration enables or suspends versioning on the bucket
+func (b *s3Backend) setVersioningConfiguration(ctx context.Context, bucket string, config gofakes3.VersioningConfiguration) error {
+	_vfs, err := b.s.getVFS(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = _vfs.Stat(bucket)
+	if err != nil {
+		return gofakes3.BucketNotFound(bucket)
+	}
+	if config.MFADelete == gofakes3.MFADeleteEnabled {
+		return gofakes3.ErrNotImplemented
+	}
+	if config.Status == gofakes3.VersioningNone {
+		// Versioning can't be turned off once enabled, only suspended
+		return nil
+	}
+	p := versionsPath(bucket, versioningFile)
+	if err := _vfs.MkdirAll(path.Dir(p), 0777); err != nil {
+		return err
+	}
+	return _vfs.WriteFile(p, []byte(config.Status), 0666)
+}
+
+// removeVersions removes the versioning state and all the old
+// versions of the bucket
+func (b *s3Backend) removeVersions(_vfs *vfs.VFS, bucket string) error {
+	node, err := _vfs.Stat(versionsPath(bucket))
+	if errors.Is(err, vfs.ENOENT) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := node.RemoveAll(); err != nil {
+		return err
+	}
+	// remove the versions directory if it is now empty
+	_ = _vfs.Remove(versionsDir)
+	return nil
+}
+
+// versionedBackend adds the gofakes3.VersionedBackend methods to an
+// s3Backend for a single request.
+//
+// The VersionedBackend methods aren't passed a context which is
+// needed to find the VFS so one of these is made for each request
+// which uses versions.
+type versionedBackend struct {
+	*s3Backend
+	ctx       context.Context
+	versionID string // from the request - gofakes3 ignores "null"
+	urlEncode bool   // URL encode the keys in listings
+}
+
+var _ gofakes3.VersionedBackend = (*versionedBackend)(nil)
+
+// VersioningConfiguration reads the versioning configuration of the bucket
+func (vb *versionedBackend) VersioningConfiguration(bucket string) (gofakes3.VersioningConfiguration, error) {
+	return vb.versioningConfiguration(vb.ctx, bucket)
+}
+
+// SetVersioningConfiguration enables or suspends versioning on the bucket
+func (vb *versionedBackend) SetVersioningConfiguration(bucket string, config gofakes3.VersioningConfiguration) error {
+	return vb.setVersioningConfiguration(vb.ctx, bucket, config)
+}
+
+// GetObjectVersion fetches a version of an object
+func (vb *versionedBackend) GetObjectVersion(bucket, object string, versionID gofakes3.VersionID, rangeRequest *gofakes3.ObjectRangeRequest) (*gofakes3.Object, error) {
+	obj, err := vb.objectVersion(vb.ctx, bucket, object, string(versionID), rangeRequest, false)
+	if err != nil {
+		return nil, err
+	}
+	return vb.auditGet(vb.ctx, bucket, object, obj)
+}
+
+// HeadObjectVersion fetches the info of a version of an object
+func (vb *versionedBackend) HeadObjectVersion(bucket, object string, versionID gofakes3.VersionID) (*gofakes3.Object, error) {
+	return vb.objectVersion(vb.ctx, bucket, object, string(versionID), nil, true)
+}
+
+// DeleteObjectVersion permanently deletes a version of an object
+func (vb *versionedBackend) DeleteObjectVersion(bucket, object string, versionID gofakes3.VersionID) (gofakes3.ObjectDeleteResult, error) {
+	return vb.deleteObjectVersion(vb.ctx, bucket, object, string(versionID))
+}
+
+// ListBucketVersions lists all the versions of the objects in the bucket
+func (vb *versionedBackend) ListBucketVersions(bucket string, prefix *gofakes3.Prefix, page *gofakes3.ListBucketVersionsPage) (*gofakes3.ListBucketVersionsResult, error) {
+	result, err := vb.listBucketVersions(vb.ctx, bucket, prefix, page)
+	if err != nil || !vb.urlEncode {
+		return result, err
+	}
+	// gofakes3 only does this for ListObjects
+	result.Prefix = gofakes3.URLEncode(result.Prefix)
+	for i := range result.CommonPrefixes {
+		result.CommonPrefixes[i].Prefix = gofakes3.URLEncode(result.CommonPrefixes[i].Prefix)
+	}
+	for _, item := range result.Versions {
+		switch item := item.(type) {
+		case *gofakes3.Version:
+			item.Key = gofakes3.URLEncode(item.Key)
+		case *gofakes3.DeleteMarker:
+			item.Key = gofakes3.URLEncode(item.Key)
+		}
+	}
+	return result, nil
+}
+
+// GetObject fetches the version of the object in the request
+//
+// gofakes3 routes requests for the "null" version and HEAD requests
+// for any version here.
+func (vb *versionedBackend) GetObject(ctx context.Context, bucket, object string, rangeRequest *gofakes3.ObjectRangeRequest) (*gofakes3.Object, error) {
+	obj, err := vb.objectVersion(ctx, bucket, object, vb.versionID, rangeRequest, false)
+	if err != nil {
+		return nil, err
+	}
+	return vb.auditGet(ctx, bucket, object, obj)
+}
+
+// HeadObject fetches the info of the version of the object in the request
+func (vb *versionedBackend) HeadObject(ctx context.Context, bucket, object string) (*gofakes3.Object, error) {
+	return vb.objectVersion(ctx, bucket, object, vb.versionID, nil, true)
+}
+
+// DeleteObject deletes the version of the object in the request
+func (vb *versionedBackend) DeleteObject(ctx context.Context, bucket, object string) (gofakes3.ObjectDeleteResult, error) {
+	if vb.versionID != "" {
+		return vb.deleteObjectVersion(ctx, bucket, object, vb.versionID)
+	}
+	return vb.s3Backend.DeleteObject(ctx, bucket, object)
+}
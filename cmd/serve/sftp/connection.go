@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/pkg/sftp"
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/lib/terminal"
@@ -350,7 +351,7 @@ func serveChannel(rwc io.ReadWriteCloser, h sftp.Handlers, what string) error {
 	return nil
 }
 
-func serveStdio(f fs.Fs) error {
+func serveStdio(f fs.Fs) (err error) {
 	if terminal.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("refusing to run SFTP server directly on a terminal. Please let sshd start rclone, by connecting with sftp or sshfs")
 	}
@@ -358,7 +359,13 @@ func serveStdio(f fs.Fs) error {
 		stdin:  os.Stdin,
 		stdout: os.Stdout,
 	}
-	handlers := newVFSHandler(vfs.New(f, &vfscommon.Opt))
+	auditLog, err := audit.New("sftp", &audit.Opt)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(auditLog, &err)
+	ctx := audit.WithConn(context.Background(), os.Getenv("USER"), "stdio")
+	handlers := newVFSHandler(auditLog.VFS(ctx, vfs.New(f, &vfscommon.Opt)))
 	return serveChannel(sshChannel, handlers, "stdio")
 }
 
@@ -9,17 +9,18 @@ import (
 	"time"
 
 	"github.com/pkg/sftp"
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/vfs"
 )
 
 // vfsHandler converts the VFS to be served by SFTP
 type vfsHandler struct {
-	*vfs.VFS
+	*audit.VFS
 }
 
 // vfsHandler returns a Handlers object with the test handlers.
-func newVFSHandler(vfs *vfs.VFS) sftp.Handlers {
+func newVFSHandler(vfs *audit.VFS) sftp.Handlers {
 	v := vfsHandler{VFS: vfs}
 	return sftp.Handlers{
 		FileGet:  v,
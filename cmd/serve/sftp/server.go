@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/rclone/rclone/cmd/serve/audit"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config"
@@ -43,6 +44,7 @@ type server struct {
 	listener net.Listener
 	stopped  chan struct{} // for waiting on the listener to stop
 	proxy    *proxy.Proxy
+	audit    *audit.Logger
 }
 
 func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Options, proxyOpt *proxy.Options) (*server, error) {
@@ -52,8 +54,8 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 		opt:     *opt,
 		stopped: make(chan struct{}),
 	}
-	if proxy.Opt.AuthProxy != "" {
-		s.proxy = proxy.New(ctx, proxyOpt, vfsOpt)
+	if proxy.Opt.InUse() {
+		s.proxy = proxy.New(ctx, f, proxyOpt, vfsOpt)
 	} else {
 		s.vfs = vfs.New(f, vfsOpt)
 	}
@@ -61,6 +63,10 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 	if err != nil {
 		return nil, fmt.Errorf("sftp configuration failed: %w", err)
 	}
+	s.audit, err = audit.New("sftp", &audit.Opt)
+	if err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
@@ -112,7 +118,8 @@ func (s *server) acceptConnection(nConn net.Conn) {
 		_ = nConn.Close()
 		return
 	}
-	c.handlers = newVFSHandler(c.vfs)
+	ctx := audit.WithConn(s.ctx, sshConn.User(), nConn.RemoteAddr().String())
+	c.handlers = newVFSHandler(s.audit.VFS(ctx, c.vfs))
 
 	// Accept all channels
 	go c.handleChannels(chans)
@@ -145,7 +152,7 @@ func (s *server) configure() (err error) {
 	}
 
 	// Load the authorized keys
-	if s.opt.AuthorizedKeys != "" && proxy.Opt.AuthProxy == "" {
+	if s.opt.AuthorizedKeys != "" && !proxy.Opt.InUse() {
 		authKeysFile := env.ShellExpand(s.opt.AuthorizedKeys)
 		authorizedKeysMap, err = loadAuthorizedKeys(authKeysFile)
 		// If user set the flag away from the default then report an error
@@ -327,6 +334,9 @@ func (s *server) Shutdown() error {
 		err = nil
 	}
 	s.Wait()
+	if auditErr := s.audit.Close(); err == nil {
+		err = auditErr
+	}
 	return err
 }
 
@@ -10,6 +10,8 @@ import (
 
 	"github.com/rclone/rclone/cmd"
 	"github.com/rclone/rclone/cmd/serve"
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/cmd/serve/audit/auditflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
@@ -81,6 +83,7 @@ func AddFlags(flagSet *pflag.FlagSet, Opt *Options) {
 func init() {
 	vfsflags.AddFlags(Command.Flags())
 	proxyflags.AddFlags(Command.Flags())
+	auditflags.AddFlags(Command.Flags())
 	AddFlags(Command.Flags(), &Opt)
 	serve.Command.AddCommand(Command)
 	serve.AddRc("sftp", func(ctx context.Context, f fs.Fs, in rc.Params) (serve.Handle, error) {
@@ -178,7 +181,7 @@ used. Omitting "restrict" and using  ` + "`--sftp-path-override`" + ` to enable
 checksumming is possible but less secure and you could use the SFTP server
 provided by OpenSSH in this case.
 
-` + strings.TrimSpace(vfs.Help()+proxy.Help),
+` + strings.TrimSpace(vfs.Help()+proxy.Help+audit.Help),
 	Annotations: map[string]string{
 		"versionIntroduced": "v1.48",
 		"groups":            "Filter",
@@ -19,6 +19,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rclone/rclone/cmd"
 	cmdserve "github.com/rclone/rclone/cmd/serve"
+	"github.com/rclone/rclone/cmd/serve/audit"
+	"github.com/rclone/rclone/cmd/serve/audit/auditflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
@@ -72,6 +74,7 @@ func init() {
 	flags.AddFlagsFromOptions(flagSet, "", OptionsInfo)
 	vfsflags.AddFlags(flagSet)
 	proxyflags.AddFlags(flagSet)
+	auditflags.AddFlags(flagSet)
 	cmdserve.Command.AddCommand(Command)
 	cmdserve.AddRc("webdav", func(ctx context.Context, f fs.Fs, in rc.Params) (cmdserve.Handle, error) {
 		// Read VFS Opts
@@ -170,7 +173,7 @@ rclone --webdav-unix-socket /tmp/my.socket --webdav-url http://localhost lsf :we
 Note that there is no authentication on http protocol - this is expected to be
 done by the permissions on the socket.
 
-` + strings.TrimSpace(libhttp.Help(flagPrefix)+libhttp.TemplateHelp(flagPrefix)+libhttp.AuthHelp(flagPrefix)+vfs.Help()+proxy.Help),
+` + strings.TrimSpace(libhttp.Help(flagPrefix)+libhttp.TemplateHelp(flagPrefix)+libhttp.AuthHelp(flagPrefix)+vfs.Help()+proxy.Help+audit.Help),
 	Annotations: map[string]string{
 		"versionIntroduced": "v1.39",
 		"groups":            "Filter",
@@ -216,6 +219,7 @@ type WebDAV struct {
 	proxy         *proxy.Proxy
 	ctx           context.Context // for global config
 	etagHashType  hash.Type
+	audit         *audit.Logger
 }
 
 // check interface
@@ -240,14 +244,19 @@ func newWebDAV(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 	if w.etagHashType != hash.None {
 		fs.Debugf(f, "Using hash %v for ETag", w.etagHashType)
 	}
-	if proxyOpt.AuthProxy != "" {
-		w.proxy = proxy.New(ctx, proxyOpt, vfsOpt)
+	if proxyOpt.InUse() {
+		w.proxy = proxy.New(ctx, f, proxyOpt, vfsOpt)
 		// override auth
 		w.opt.Auth.CustomAuthFn = w.auth
 	} else {
 		w._vfs = vfs.New(f, vfsOpt)
 	}
 
+	w.audit, err = audit.New("webdav", &audit.Opt)
+	if err != nil {
+		return nil, err
+	}
+
 	w.server, err = libhttp.NewServer(ctx,
 		libhttp.WithConfig(w.opt.HTTP),
 		libhttp.WithAuth(w.opt.Auth),
@@ -295,19 +304,21 @@ func newWebDAV(ctx context.Context, f fs.Fs, opt *Options, vfsOpt *vfscommon.Opt
 }
 
 // Gets the VFS in use for this request
-func (w *WebDAV) getVFS(ctx context.Context) (VFS *vfs.VFS, err error) {
+//
+// The file operations done through it are audit logged with ctx.
+func (w *WebDAV) getVFS(ctx context.Context) (VFS *audit.VFS, err error) {
 	if w._vfs != nil {
-		return w._vfs, nil
+		return w.audit.VFS(ctx, w._vfs), nil
 	}
 	value := libhttp.CtxGetAuth(ctx)
 	if value == nil {
 		return nil, errors.New("no VFS found in context")
 	}
-	VFS, ok := value.(*vfs.VFS)
+	_vfs, ok := value.(*vfs.VFS)
 	if !ok {
 		return nil, fmt.Errorf("context value is not VFS: %#v", value)
 	}
-	return VFS, nil
+	return w.audit.VFS(ctx, _vfs), nil
 }
 
 // auth does proxy authorization
@@ -376,6 +387,13 @@ func (w *WebDAV) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	// Add URL Prefix back to path since webdavhandler needs to
 	// return absolute references.
 	r.URL.Path = w.opt.HTTP.BaseURL + r.URL.Path
+	if w.audit != nil {
+		user, ok := libhttp.CtxGetUser(r.Context())
+		if !ok {
+			user, _, _ = r.BasicAuth()
+		}
+		r = r.WithContext(audit.WithConn(r.Context(), user, r.RemoteAddr))
+	}
 	wrw := &webdavRW{ResponseWriter: rw}
 	w.webdavhandler.ServeHTTP(wrw, r)
 
@@ -449,7 +467,11 @@ func (w *WebDAV) Addr() net.Addr {
 
 // Shutdown the server
 func (w *WebDAV) Shutdown() error {
-	return w.server.Shutdown()
+	err := w.server.Shutdown()
+	if auditErr := w.audit.Close(); err == nil {
+		err = auditErr
+	}
+	return err
 }
 
 // logRequest is called by the webdav module on every request
@@ -464,12 +486,7 @@ func (w *WebDAV) Mkdir(ctx context.Context, name string, perm os.FileMode) (err
 	if err != nil {
 		return err
 	}
-	dir, leaf, err := VFS.StatParent(name)
-	if err != nil {
-		return err
-	}
-	_, err = dir.Mkdir(leaf)
-	return err
+	return VFS.Mkdir(name, perm)
 }
 
 // OpenFile opens a file or a directory
@@ -493,15 +510,7 @@ func (w *WebDAV) RemoveAll(ctx context.Context, name string) (err error) {
 	if err != nil {
 		return err
 	}
-	node, err := VFS.Stat(name)
-	if err != nil {
-		return err
-	}
-	err = node.RemoveAll()
-	if err != nil {
-		return err
-	}
-	return nil
+	return VFS.RemoveAll(name)
 }
 
 // Rename a file or a directory
@@ -3,13 +3,16 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/rclone/rclone/fs/operations/operationsflags"
 	"github.com/rclone/rclone/fs/sync"
+	"github.com/rclone/rclone/fs/watch"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +27,7 @@ func init() {
 	cmdFlags := commandDefinition.Flags()
 	flags.BoolVarP(cmdFlags, &createEmptySrcDirs, "create-empty-src-dirs", "", createEmptySrcDirs, "Create empty source dirs on destination after sync", "")
 	operationsflags.AddLoggerFlags(cmdFlags, &loggerOpt, &loggerFlagsOpt)
+	watch.AddFlags(cmdFlags)
 	loggerOpt.LoggerFn = operations.NewDefaultLoggerFn(&loggerOpt)
 }
 
@@ -80,6 +84,29 @@ object/directory found in source/destination - ignoring" errors.
 See [this forum post](https://forum.rclone.org/t/sync-not-clearing-duplicates/14372)
 for more info.
 
+### Watching for changes
+
+With |--watch| rclone keeps running after the sync and syncs again
+each time the source changes, until it is stopped. Only the paths
+which changed are looked at in each pass rather than listing
+everything again.
+
+Local sources are watched with inotify on Linux. This is the only OS
+where local changes are watched, so on other OSes local sources are
+synced in full every |--watch-poll-interval|. Remotes which support
+change notifications (for example Google Drive, Dropbox and OneDrive)
+are polled for changes every |--watch-poll-interval|. Anything else is
+synced in full every |--watch-poll-interval|.
+
+Changes are collected until there have been none for |--watch-delay|
+(default 5s) so a burst of changes is synced in one pass. Changes
+made directly to the destination aren't noticed until the next full
+pass, which happens after a pass fails.
+
+|||sh
+rclone sync --watch /home/user/documents remote:documents
+|||
+
 `, "|", "`") + operationsflags.Help(),
 	Annotations: map[string]string{
 		"groups": "Sync,Copy,Filter,Listing,Important",
@@ -99,6 +126,14 @@ for more info.
 				ctx = operations.WithSyncLogger(ctx, loggerOpt)
 			}
 
+			if watch.Opt.Watch {
+				if srcFileName != "" {
+					return errors.New("--watch can't be used when the source is a file")
+				}
+				return watch.Run(ctx, []fs.Fs{fsrc}, &watch.Opt, func(ctx context.Context, changes []*watch.ChangeSet) error {
+					return sync.SyncChanges(ctx, fdst, fsrc, createEmptySrcDirs, changes[0])
+				})
+			}
 			if srcFileName == "" {
 				return sync.Sync(ctx, fdst, fsrc, createEmptySrcDirs)
 			}
@@ -70,7 +70,8 @@ type Account struct {
 	withBuf  bool          // is using a buffered in
 	checking bool          // set if attached transfer is checking
 
-	tokenBucket buckets // per file bandwidth limiter (may be nil)
+	tokenBucket buckets  // per file bandwidth limiter (may be nil)
+	limiter     *Limiter // bandwidth limiter of the config in ctx (may be nil)
 
 	values accountValues
 }
@@ -79,6 +80,7 @@ type Account struct {
 type accountValues struct {
 	mu      sync.Mutex // Mutex for stat values.
 	bytes   int64      // Total number of bytes read
+	skipped int64      // Number of bytes in bytes which were skipped rather than read
 	max     int64      // if >=0 the max number of bytes to transfer
 	start   time.Time  // Start time of first read
 	lpTime  time.Time  // Time of last average measurement
@@ -92,15 +94,16 @@ const averagePeriod = 16 // period to do exponentially weighted averages over
 // the given size and name
 func newAccountSizeName(ctx context.Context, stats *StatsInfo, in io.ReadCloser, size int64, name string) *Account {
 	acc := &Account{
-		stats:  stats,
-		in:     in,
-		ctx:    ctx,
-		ci:     fs.GetConfig(ctx),
-		close:  in,
-		origIn: in,
-		size:   size,
-		name:   name,
-		exit:   make(chan struct{}),
+		stats:   stats,
+		in:      in,
+		ctx:     ctx,
+		ci:      fs.GetConfig(ctx),
+		limiter: GetLimiter(ctx),
+		close:   in,
+		origIn:  in,
+		size:    size,
+		name:    name,
+		exit:    make(chan struct{}),
 		values: accountValues{
 			avg:    0,
 			lpTime: time.Now(),
@@ -324,6 +327,19 @@ func (acc *Account) ServerSideMoveEnd(n int64) {
 	acc.serverSideEnd(n)
 }
 
+// SkipBytes accounts for n bytes of the file which don't need to be
+// transferred, for example because an interrupted upload which is
+// being resumed wrote them already.
+//
+// They count towards the progress of the file but not the bytes
+// transferred or the speed.
+func (acc *Account) SkipBytes(n int64) {
+	acc.values.mu.Lock()
+	acc.values.bytes += n
+	acc.values.skipped += n
+	acc.values.mu.Unlock()
+}
+
 // DryRun accounts for statistics without running the operation
 func (acc *Account) DryRun(n int64) {
 	acc.ServerSideTransferStart()
@@ -355,6 +371,7 @@ func (acc *Account) accountRead(n int) {
 	acc.stats.Bytes(int64(n))
 
 	TokenBucket.LimitBandwidth(TokenBucketSlotAccounting, n)
+	acc.limiter.LimitBandwidth(TokenBucketSlotAccounting, n)
 	acc.limitPerFileBandwidth(n)
 }
 
@@ -470,13 +487,14 @@ func (acc *Account) speed() (bps, current float64) {
 	}
 	acc.values.mu.Lock()
 	defer acc.values.mu.Unlock()
-	if acc.values.bytes == 0 {
+	read := acc.values.bytes - acc.values.skipped
+	if read == 0 {
 		return 0, 0
 	}
 	// Calculate speed from first read.
 	total := float64(time.Since(acc.values.start)) / float64(time.Second)
 	if total > 0 {
-		bps = float64(acc.values.bytes) / total
+		bps = float64(read) / total
 	} else {
 		bps = 0.0
 	}
@@ -0,0 +1,178 @@
+package accounting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/configstruct"
+	"golang.org/x/time/rate"
+)
+
+// Limiter holds the bandwidth and transactions per second limits for
+// a config which overrides the global one, for example with
+// "override.bwlimit" in the config section of a remote or with
+// "_config" in an rc job.
+//
+// These limits apply in addition to the global limits.
+//
+// A nil *Limiter is valid and doesn't limit anything.
+type Limiter struct {
+	mu         sync.Mutex
+	name       string         // what the limiter is for, for logging
+	bwLimit    fs.BwTimetable // bandwidth schedule
+	currLimit  fs.BwTimeSlot  // slot of bwLimit in use
+	started    bool           // set if currLimit has been set
+	nextUpdate time.Time      // when to check bwLimit again
+	curr       buckets        // bandwidth limiters for currLimit
+	tps        *rate.Limiter  // transactions per second limiter
+}
+
+type limiterContextKey struct{}
+
+// newLimiter makes a Limiter for the limits in ci which differ from
+// the global limits or returns nil if there aren't any.
+func newLimiter(name string, ci *fs.ConfigInfo) *Limiter {
+	globalCI := fs.GetConfig(context.Background())
+	bwLimitSet := ci.BwLimit.String() != globalCI.BwLimit.String()
+	tpsLimitSet := ci.TPSLimit > 0 && (ci.TPSLimit != globalCI.TPSLimit || ci.TPSLimitBurst != globalCI.TPSLimitBurst)
+	if !bwLimitSet && !tpsLimitSet {
+		return nil
+	}
+	l := &Limiter{name: name}
+	if bwLimitSet {
+		l.bwLimit = ci.BwLimit
+		l._update(time.Now())
+	}
+	if tpsLimitSet {
+		tpsBurst := max(ci.TPSLimitBurst, 1)
+		l.tps = rate.NewLimiter(rate.Limit(ci.TPSLimit), tpsBurst)
+		fs.Infof(name, "Starting transaction limiter: max %g transactions/s with burst %d", ci.TPSLimit, tpsBurst)
+	}
+	return l
+}
+
+// WithLimiter returns a copy of ctx with a Limiter for the limits in
+// the config of ctx which differ from the global limits.
+//
+// name is used to identify the limiter in the logs.
+func WithLimiter(ctx context.Context, name string) context.Context {
+	l := newLimiter(name, fs.GetConfig(ctx))
+	if l == nil && GetLimiter(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, limiterContextKey{}, l)
+}
+
+// The Limiters made by withRemoteLimiter by config name and limits
+var (
+	remoteLimitersMu sync.Mutex
+	remoteLimiters   = make(map[string]*Limiter)
+)
+
+// remoteLimitOptions are the config options which set the limits
+var remoteLimitOptions = []string{"bwlimit", "tpslimit", "tpslimit_burst"}
+
+// withRemoteLimiter is like WithLimiter but for the limits set with
+// "override." in config, the config of the remote with configName, so
+// all the Fs made for it share the same Limiter.
+//
+// The config in ctx isn't used as it may have limits for something
+// else, such as the rc job the remote was first made in, which would
+// then stay with the remote for as long as it is cached.
+//
+// configName includes the suffix for any overridden config.
+func withRemoteLimiter(ctx context.Context, configName string, config configmap.Getter) context.Context {
+	overrides := configmap.Simple{}
+	for _, name := range remoteLimitOptions {
+		if value, ok := config.Get("override." + name); ok {
+			overrides[name] = value
+		}
+	}
+	var l *Limiter
+	if len(overrides) > 0 {
+		key := configName + "|" + overrides.String()
+		remoteLimitersMu.Lock()
+		var ok bool
+		l, ok = remoteLimiters[key]
+		if !ok {
+			_, ci := fs.AddConfig(context.Background())
+			if err := configstruct.Set(overrides, ci); err != nil {
+				fs.Errorf(configName, "Failed to read limits: %v", err)
+			} else {
+				l = newLimiter(configName, ci)
+			}
+			remoteLimiters[key] = l
+		}
+		remoteLimitersMu.Unlock()
+	}
+	if l == nil && GetLimiter(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, limiterContextKey{}, l)
+}
+
+// GetLimiter returns the Limiter in ctx or nil if there isn't one
+func GetLimiter(ctx context.Context) *Limiter {
+	l, _ := ctx.Value(limiterContextKey{}).(*Limiter)
+	return l
+}
+
+// Update the bandwidth limiters if the bandwidth schedule has changed
+//
+// Call with lock held
+func (l *Limiter) _update(now time.Time) {
+	if len(l.bwLimit) == 0 || now.Before(l.nextUpdate) {
+		return
+	}
+	l.nextUpdate = now.Add(time.Minute)
+	limitNow := l.bwLimit.LimitAt(now)
+	if l.started && l.currLimit.Bandwidth == limitNow.Bandwidth {
+		return
+	}
+	l.started = true
+	l.currLimit = limitNow
+	if limitNow.Bandwidth.IsSet() {
+		l.curr = newTokenBucket(limitNow.Bandwidth)
+		fs.Infof(l.name, "Bandwidth limit set to %v Byte/s", &limitNow.Bandwidth)
+	} else {
+		l.curr._setOff()
+		fs.Infof(l.name, "Bandwidth limit off")
+	}
+}
+
+// LimitBandwidth sleeps for the correct amount of time for the
+// passage of n bytes according to the bandwidth limit for slot i
+func (l *Limiter) LimitBandwidth(i TokenBucketSlot, n int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l._update(time.Now())
+	tb := l.curr[i]
+	l.mu.Unlock()
+
+	if tb != nil {
+		err := tb.WaitN(context.Background(), n)
+		if err != nil {
+			fs.Errorf(l.name, "Token bucket error: %v", err)
+		}
+	}
+}
+
+// limitTPS limits the number of transactions per second if enabled
+func (l *Limiter) limitTPS(ctx context.Context) {
+	if l == nil || l.tps == nil {
+		return
+	}
+	tbErr := l.tps.Wait(ctx)
+	if tbErr != nil && tbErr != context.Canceled {
+		fs.Errorf(l.name, "HTTP token bucket error: %v", tbErr)
+	}
+}
+
+func init() {
+	fs.AddLimiter = withRemoteLimiter
+}
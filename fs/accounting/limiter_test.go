@@ -0,0 +1,141 @@
+package accounting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestWithLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	// Nothing different from the global config
+	newCtx := WithLimiter(ctx, "test")
+	assert.Equal(t, ctx, newCtx)
+	assert.Nil(t, GetLimiter(newCtx))
+
+	// Same as the global config
+	ctx2, _ := fs.AddConfig(ctx)
+	assert.Nil(t, GetLimiter(WithLimiter(ctx2, "test")))
+
+	// Bandwidth limit set
+	ctx3, ci3 := fs.AddConfig(ctx)
+	require.NoError(t, ci3.BwLimit.Set("1M:2M"))
+	ctx3 = WithLimiter(ctx3, "test")
+	l := GetLimiter(ctx3)
+	require.NotNil(t, l)
+	assert.Nil(t, l.tps)
+	require.NotNil(t, l.curr[TokenBucketSlotTransportTx])
+	require.NotNil(t, l.curr[TokenBucketSlotTransportRx])
+	assert.Equal(t, rate.Limit(1024*1024), l.curr[TokenBucketSlotTransportTx].Limit())
+	assert.Equal(t, rate.Limit(2*1024*1024), l.curr[TokenBucketSlotTransportRx].Limit())
+	assert.Equal(t, rate.Limit(2*1024*1024), l.curr[TokenBucketSlotAccounting].Limit())
+
+	// Derived contexts share the limiter
+	ctx4, _ := fs.AddConfig(ctx3)
+	assert.Equal(t, l, GetLimiter(ctx4))
+
+	// Unless they reset the limits to the global ones
+	ctx5, ci5 := fs.AddConfig(ctx3)
+	ci5.BwLimit = nil
+	assert.Nil(t, GetLimiter(WithLimiter(ctx5, "test")))
+
+	// TPS limit set
+	ctx6, ci6 := fs.AddConfig(ctx)
+	ci6.TPSLimit = 10
+	ci6.TPSLimitBurst = 5
+	l = GetLimiter(WithLimiter(ctx6, "test"))
+	require.NotNil(t, l)
+	require.NotNil(t, l.tps)
+	assert.Equal(t, rate.Limit(10), l.tps.Limit())
+	assert.Equal(t, 5, l.tps.Burst())
+	assert.True(t, l.curr._isOff())
+}
+
+func TestWithRemoteLimiter(t *testing.T) {
+	// Limits of an rc job in the context aren't used for the remote
+	jobCtx, ci := fs.AddConfig(context.Background())
+	require.NoError(t, ci.BwLimit.Set("1M"))
+	jobCtx = WithLimiter(jobCtx, "job")
+	require.NotNil(t, GetLimiter(jobCtx))
+	assert.Nil(t, GetLimiter(withRemoteLimiter(jobCtx, "remote", configmap.Simple{})))
+
+	// The remote's own limits are
+	config := configmap.Simple{"override.tpslimit": "10", "override.bwlimit": "2M"}
+	l := GetLimiter(withRemoteLimiter(jobCtx, "remote", config))
+	require.NotNil(t, l)
+	require.NotNil(t, l.tps)
+	assert.Equal(t, rate.Limit(10), l.tps.Limit())
+	require.NotNil(t, l.curr[TokenBucketSlotAccounting])
+	assert.Equal(t, rate.Limit(2*1024*1024), l.curr[TokenBucketSlotAccounting].Limit())
+
+	// and shared by all the Fs of the remote
+	assert.Same(t, l, GetLimiter(withRemoteLimiter(context.Background(), "remote", config)))
+	assert.NotSame(t, l, GetLimiter(withRemoteLimiter(context.Background(), "remote2", config)))
+}
+
+func TestLimiterSchedule(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	now := time.Now()
+	require.NoError(t, ci.BwLimit.Set("00:00,1M 12:00,off"))
+	l := newLimiter("test", ci)
+	require.NotNil(t, l)
+
+	// Pretend it is morning
+	morning := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.Local)
+	l.nextUpdate = time.Time{}
+	l._update(morning)
+	require.NotNil(t, l.curr[TokenBucketSlotAccounting])
+	assert.Equal(t, rate.Limit(1024*1024), l.curr[TokenBucketSlotAccounting].Limit())
+
+	// Not updated until nextUpdate
+	afternoon := time.Date(now.Year(), now.Month(), now.Day(), 13, 0, 0, 0, time.Local)
+	assert.Equal(t, morning.Add(time.Minute), l.nextUpdate)
+	l.nextUpdate = afternoon.Add(time.Second)
+	l._update(afternoon)
+	assert.NotNil(t, l.curr[TokenBucketSlotAccounting])
+
+	// Afternoon is off
+	l._update(afternoon.Add(time.Second))
+	assert.True(t, l.curr._isOff())
+
+	// A nil limiter does nothing
+	var nilLimiter *Limiter
+	nilLimiter.LimitBandwidth(TokenBucketSlotAccounting, 100)
+	nilLimiter.limitTPS(ctx)
+}
+
+func TestLimitTPSLimiter(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.TPSLimit = 100
+	ctx = WithLimiter(ctx, "test")
+	l := GetLimiter(ctx)
+	require.NotNil(t, l)
+
+	timeTransactions := func(ctx context.Context, n int, minTime, maxTime time.Duration, limiters ...*Limiter) {
+		start := time.Now()
+		for range n {
+			LimitTPS(ctx, limiters...)
+		}
+		dt := time.Since(start)
+		assert.True(t, dt >= minTime && dt <= maxTime, "Expecting time between %v and %v, got %v", minTime, maxTime, dt)
+	}
+
+	// Not limited without a limiter
+	timeTransactions(context.Background(), 50, 0, 100*time.Millisecond)
+
+	// Limited by the limiter in the context
+	timeTransactions(ctx, 50, 400*time.Millisecond, 5000*time.Millisecond)
+
+	// Limited by the limiter passed in
+	timeTransactions(context.Background(), 50, 400*time.Millisecond, 5000*time.Millisecond, l)
+
+	// Passing the limiter in the context again doesn't limit twice
+	timeTransactions(ctx, 50, 400*time.Millisecond, 900*time.Millisecond, l)
+}
@@ -2,8 +2,11 @@ package accounting
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rclone/rclone/fs"
 )
 
 var namespace = "rclone_"
@@ -117,3 +120,86 @@ func bool2Float(e bool) float64 {
 	}
 	return 0
 }
+
+// Backend operations recorded in the BackendMetrics
+const (
+	OpList   = "list"
+	OpGet    = "get"
+	OpPut    = "put"
+	OpDelete = "delete"
+	OpCopy   = "copy"
+	OpMove   = "move"
+)
+
+// BackendMetrics provide metrics for the operations rclone does on
+// the backends labelled by remote and operation.
+type BackendMetrics struct {
+	Calls    *prometheus.CounterVec
+	Errors   *prometheus.CounterVec
+	Duration *prometheus.HistogramVec
+}
+
+// NewBackendMetrics creates a new metrics instance, the instance
+// shall be assigned to DefaultBackendMetrics before any processing
+// takes place.
+func NewBackendMetrics(namespace string) *BackendMetrics {
+	labels := []string{"remote", "operation"}
+	return &BackendMetrics{
+		Calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "backend",
+			Name:      "calls_total",
+			Help:      "Number of calls to the backends by remote and operation",
+		}, labels),
+		Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "backend",
+			Name:      "errors_total",
+			Help:      "Number of calls to the backends which returned an error by remote and operation",
+		}, labels),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "backend",
+			Name:      "call_duration_seconds",
+			Help:      "Time taken by calls to the backends by remote and operation",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 16),
+		}, labels),
+	}
+}
+
+// DefaultBackendMetrics specifies the metrics used by BackendCall.
+var DefaultBackendMetrics = (*BackendMetrics)(nil)
+
+// Collectors returns all prometheus metrics as collectors for registration.
+func (m *BackendMetrics) Collectors() []prometheus.Collector {
+	if m == nil {
+		return nil
+	}
+	return []prometheus.Collector{
+		m.Calls,
+		m.Errors,
+		m.Duration,
+	}
+}
+
+// BackendCall records the start of the operation op on f in the
+// DefaultBackendMetrics.
+//
+// Call the function returned with the error from the operation when
+// it has finished.
+func BackendCall(f fs.Info, op string) (done func(err error)) {
+	m := DefaultBackendMetrics
+	if m == nil || f == nil {
+		return func(error) {}
+	}
+	// Remove any suffix added for overridden config
+	remote, _, _ := strings.Cut(f.Name(), "{")
+	start := time.Now()
+	return func(err error) {
+		m.Calls.WithLabelValues(remote, op).Inc()
+		if err != nil {
+			m.Errors.WithLabelValues(remote, op).Inc()
+		}
+		m.Duration.WithLabelValues(remote, op).Observe(time.Since(start).Seconds())
+	}
+}
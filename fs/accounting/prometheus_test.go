@@ -0,0 +1,40 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendCall(t *testing.T) {
+	ctx := context.Background()
+	f, err := mockfs.NewFs(ctx, "remote{abcde}", "root", nil)
+	require.NoError(t, err)
+
+	// Does nothing without metrics
+	BackendCall(f, OpList)(nil)
+
+	oldMetrics := DefaultBackendMetrics
+	DefaultBackendMetrics = NewBackendMetrics("test")
+	defer func() {
+		DefaultBackendMetrics = oldMetrics
+	}()
+	m := DefaultBackendMetrics
+
+	BackendCall(f, OpList)(nil)
+	BackendCall(f, OpList)(nil)
+	BackendCall(f, OpPut)(errors.New("failed"))
+	BackendCall(nil, OpGet)(nil)
+
+	// The suffix for overridden config is removed from the remote
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.Calls.WithLabelValues("remote", OpList)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.Errors.WithLabelValues("remote", OpList)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Calls.WithLabelValues("remote", OpPut)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.Errors.WithLabelValues("remote", OpPut)))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.Duration))
+}
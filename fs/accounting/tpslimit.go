@@ -24,11 +24,22 @@ func StartLimitTPS(ctx context.Context) {
 
 // LimitTPS limits the number of transactions per second if enabled.
 // It should be called once per transaction.
-func LimitTPS(ctx context.Context) {
+//
+// This applies the global limit, the limit of the Limiter in ctx if
+// any and the limits of any extra limiters passed in, such as the one
+// for the remote the transaction is for.
+func LimitTPS(ctx context.Context, limiters ...*Limiter) {
 	if tpsBucket != nil {
 		tbErr := tpsBucket.Wait(ctx)
 		if tbErr != nil && tbErr != context.Canceled {
 			fs.Errorf(nil, "HTTP token bucket error: %v", tbErr)
 		}
 	}
+	ctxLimiter := GetLimiter(ctx)
+	ctxLimiter.limitTPS(ctx)
+	for _, l := range limiters {
+		if l != ctxLimiter {
+			l.limitTPS(ctx)
+		}
+	}
 }
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/rclone/rclone/fs/config/configmap"
 )
 
 // Global
@@ -43,6 +45,30 @@ var (
 	// implementation from the fs
 	CountError = func(ctx context.Context, err error) error { return err }
 
+	// AddLimiter adds limiters for the bandwidth and transactions
+	// per second set with "override." in config, the config of the
+	// remote. name is the config name of the remote and all the Fs
+	// made for it share the same limiters.
+	//
+	// This is a function pointer to decouple the accounting
+	// implementation from the fs
+	AddLimiter = func(ctx context.Context, name string, config configmap.Getter) context.Context { return ctx }
+
+	// PacerWait records the time spent waiting for the pacer of a
+	// backend in the tracing span in ctx.
+	//
+	// This is a function pointer to decouple the tracing
+	// implementation from the fs
+	PacerWait = func(ctx context.Context, wait time.Duration) {}
+
+	// PacerRetry records a low level retry by the pacer of a
+	// backend, try being the number of the next try and err the
+	// reason, in the tracing span in ctx.
+	//
+	// This is a function pointer to decouple the tracing
+	// implementation from the fs
+	PacerRetry = func(ctx context.Context, try int, err error) {}
+
 	// ConfigProvider is the config key used for provider options
 	ConfigProvider = "provider"
 
@@ -151,6 +177,11 @@ var ConfigOptionsInfo = Options{{
 	Default: SizeSuffix(-1),
 	Help:    "When synchronizing, limit the total size of deletes",
 	Groups:  "Sync",
+}, {
+	Name:    "incremental",
+	Default: false,
+	Help:    "Only sync the paths the source reports as changed since the last --incremental run",
+	Groups:  "Sync",
 }, {
 	Name:    "track_renames",
 	Default: false,
@@ -277,6 +308,16 @@ var ConfigOptionsInfo = Options{{
 	Default: false,
 	Help:    "Use recursive list if available; uses more memory but fewer transactions",
 	Groups:  "Listing",
+}, {
+	Name:    "version_at",
+	Default: Time{},
+	Help:    "Show the source as it was at this time using file versions (read only)",
+	Groups:  "Listing",
+}, {
+	Name:    "list_cache_ttl",
+	Default: time.Duration(0),
+	Help:    "Cache destination listings on disk for this long when syncing (0 to disable)",
+	Groups:  "Listing,Sync",
 }, {
 	Name:    "list_cutoff",
 	Default: 1_000_000,
@@ -464,6 +505,11 @@ var ConfigOptionsInfo = Options{{
 	Default: SizeSuffix(64 * 1024 * 1024),
 	Help:    "Chunk size for multi-thread downloads / uploads, if not set by filesystem",
 	Groups:  "Copy",
+}, {
+	Name:    "resume_uploads",
+	Default: false,
+	Help:    "Save the state of multi-thread uploads so they can be resumed if interrupted",
+	Groups:  "Copy",
 }, {
 	Name:    "use_json_log",
 	Default: false,
@@ -592,6 +638,7 @@ type ConfigInfo struct {
 	DeleteMode                 DeleteMode        `config:"delete_mode"`
 	MaxDelete                  int64             `config:"max_delete"`
 	MaxDeleteSize              SizeSuffix        `config:"max_delete_size"`
+	Incremental                bool              `config:"incremental"`
 	TrackRenames               bool              `config:"track_renames"`          // Track file renames.
 	TrackRenamesStrategy       string            `config:"track_renames_strategy"` // Comma separated list of strategies used to track renames
 	Retries                    int               `config:"retries"`                // High-level retries
@@ -618,6 +665,8 @@ type ConfigInfo struct {
 	SuffixKeepExtension        bool              `config:"suffix_keep_extension"`
 	UseListR                   bool              `config:"fast_list"`
 	ListCutoff                 int               `config:"list_cutoff"`
+	VersionAt                  Time              `config:"version_at"`
+	ListCacheTTL               Duration          `config:"list_cache_ttl"`
 	BufferSize                 SizeSuffix        `config:"buffer_size"`
 	BwLimit                    BwTimetable       `config:"bwlimit"`
 	BwLimitFile                BwTimetable       `config:"bwlimit_file"`
@@ -656,6 +705,7 @@ type ConfigInfo struct {
 	MultiThreadSet             bool              `config:"multi_thread_set"`        // whether MultiThreadStreams was set (set in fs/config/configflags)
 	MultiThreadChunkSize       SizeSuffix        `config:"multi_thread_chunk_size"` // Chunk size for multi-thread downloads / uploads, if not set by filesystem
 	MultiThreadWriteBufferSize SizeSuffix        `config:"multi_thread_write_buffer_size"`
+	ResumeUploads              bool              `config:"resume_uploads"`
 	OrderBy                    string            `config:"order_by"` // instructions on how to order the transfer
 	UploadHeaders              []*HTTPOption     `config:"upload_headers"`
 	DownloadHeaders            []*HTTPOption     `config:"download_headers"`
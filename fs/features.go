@@ -95,6 +95,20 @@ type Features struct {
 	// uses polling, it should adhere to the given interval.
 	ChangeNotify func(context.Context, func(string, EntryType), <-chan time.Duration)
 
+	// ChangesSince calls notifyFunc with each path which has
+	// changed on the remote since token was issued and returns
+	// the token to use next time.
+	//
+	// If token is empty it returns a token for the current state
+	// of the remote without calling notifyFunc.
+	//
+	// It returns ErrorChangeTokenInvalid if token can no longer be
+	// used, in which case the caller should start again.
+	//
+	// The old path of a renamed or moved item may not be reported
+	// if the backend doesn't know it.
+	ChangesSince func(ctx context.Context, token string, notifyFunc func(string, EntryType)) (newToken string, err error)
+
 	// UnWrap returns the Fs that this Fs is wrapping
 	UnWrap func() Fs
 
@@ -174,6 +188,23 @@ type Features struct {
 	// immediately.
 	ListP func(ctx context.Context, dir string, callback ListRCallback) error
 
+	// ListAt lists the objects and directories in dir as they
+	// were at time t using the old versions of the files.
+	//
+	// dir should be "" to list the root, and should not have
+	// trailing slashes.
+	//
+	// This should return ErrDirNotFound if the directory isn't
+	// found.
+	//
+	// The Objects returned should read the contents of the
+	// version of the file current at time t.
+	ListAt func(ctx context.Context, dir string, t time.Time) (entries DirEntries, err error)
+
+	// NewObjectAt finds the Object at remote as it was at time t.
+	// If it can't be found it returns the error ErrorObjectNotFound.
+	NewObjectAt func(ctx context.Context, remote string, t time.Time) (Object, error)
+
 	// About gets quota information from the Fs
 	About func(ctx context.Context) (*Usage, error)
 
@@ -191,6 +222,13 @@ type Features struct {
 	//
 	OpenChunkWriter func(ctx context.Context, remote string, src ObjectInfo, options ...OpenOption) (info ChunkWriterInfo, writer ChunkWriter, err error)
 
+	// ResumeChunkWriter resumes an interrupted chunked upload
+	// using the state returned by ChunkWriterStater.State
+	//
+	// The chunks in state.Parts have been written already and
+	// won't be written again.
+	ResumeChunkWriter func(ctx context.Context, remote string, src ObjectInfo, state ChunkWriterState, options ...OpenOption) (info ChunkWriterInfo, writer ChunkWriter, err error)
+
 	// UserInfo returns info about the connected user
 	UserInfo func(ctx context.Context) (map[string]string, error)
 
@@ -310,6 +348,9 @@ func (ft *Features) Fill(ctx context.Context, f Fs) *Features {
 	if do, ok := f.(ChangeNotifier); ok {
 		ft.ChangeNotify = do.ChangeNotify
 	}
+	if do, ok := f.(ChangesSincer); ok {
+		ft.ChangesSince = do.ChangesSince
+	}
 	if do, ok := f.(UnWrapper); ok {
 		ft.UnWrap = do.UnWrap
 	}
@@ -345,6 +386,12 @@ func (ft *Features) Fill(ctx context.Context, f Fs) *Features {
 	if do, ok := f.(ListPer); ok {
 		ft.ListP = do.ListP
 	}
+	if do, ok := f.(ListAter); ok {
+		ft.ListAt = do.ListAt
+	}
+	if do, ok := f.(NewObjectAter); ok {
+		ft.NewObjectAt = do.NewObjectAt
+	}
 	if do, ok := f.(Abouter); ok {
 		ft.About = do.About
 	}
@@ -354,6 +401,9 @@ func (ft *Features) Fill(ctx context.Context, f Fs) *Features {
 	if do, ok := f.(OpenChunkWriter); ok {
 		ft.OpenChunkWriter = do.OpenChunkWriter
 	}
+	if do, ok := f.(ResumeChunkWriter); ok {
+		ft.ResumeChunkWriter = do.ResumeChunkWriter
+	}
 	if do, ok := f.(UserInfoer); ok {
 		ft.UserInfo = do.UserInfo
 	}
@@ -423,6 +473,9 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	if mask.ChangeNotify == nil {
 		ft.ChangeNotify = nil
 	}
+	if mask.ChangesSince == nil {
+		ft.ChangesSince = nil
+	}
 	// if mask.UnWrap == nil {
 	// 	ft.UnWrap = nil
 	// }
@@ -456,6 +509,12 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	if mask.ListP == nil {
 		ft.ListP = nil
 	}
+	if mask.ListAt == nil {
+		ft.ListAt = nil
+	}
+	if mask.NewObjectAt == nil {
+		ft.NewObjectAt = nil
+	}
 	if mask.About == nil {
 		ft.About = nil
 	}
@@ -465,6 +524,9 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	if mask.OpenChunkWriter == nil {
 		ft.OpenChunkWriter = nil
 	}
+	if mask.ResumeChunkWriter == nil {
+		ft.ResumeChunkWriter = nil
+	}
 	if mask.UserInfo == nil {
 		ft.UserInfo = nil
 	}
@@ -580,6 +642,20 @@ type ChangeNotifier interface {
 	ChangeNotify(context.Context, func(string, EntryType), <-chan time.Duration)
 }
 
+// ChangesSincer is an optional interface for Fs
+type ChangesSincer interface {
+	// ChangesSince calls notifyFunc with each path which has
+	// changed on the remote since token was issued and returns
+	// the token to use next time.
+	//
+	// If token is empty it returns a token for the current state
+	// of the remote without calling notifyFunc.
+	//
+	// It returns ErrorChangeTokenInvalid if token can no longer be
+	// used, in which case the caller should start again.
+	ChangesSince(ctx context.Context, token string, notifyFunc func(string, EntryType)) (newToken string, err error)
+}
+
 // EntryType can be associated with remote paths to identify their type
 type EntryType int
 
@@ -702,6 +778,29 @@ type ListPer interface {
 	ListP(ctx context.Context, dir string, callback ListRCallback) error
 }
 
+// ListAter is an optional interface for Fs
+type ListAter interface {
+	// ListAt lists the objects and directories in dir as they
+	// were at time t using the old versions of the files.
+	//
+	// dir should be "" to list the root, and should not have
+	// trailing slashes.
+	//
+	// This should return ErrDirNotFound if the directory isn't
+	// found.
+	//
+	// The Objects returned should read the contents of the
+	// version of the file current at time t.
+	ListAt(ctx context.Context, dir string, t time.Time) (entries DirEntries, err error)
+}
+
+// NewObjectAter is an optional interface for Fs
+type NewObjectAter interface {
+	// NewObjectAt finds the Object at remote as it was at time t.
+	// If it can't be found it returns the error ErrorObjectNotFound.
+	NewObjectAt(ctx context.Context, remote string, t time.Time) (Object, error)
+}
+
 // RangeSeeker is the interface that wraps the RangeSeek method.
 //
 // Some of the returns from Object.Open() may optionally implement
@@ -767,6 +866,35 @@ type ChunkWriter interface {
 	Abort(ctx context.Context) error
 }
 
+// ChunkWriterState is the state of a chunked upload which can be
+// saved so the upload can be resumed if it is interrupted
+type ChunkWriterState struct {
+	UploadID  string         `json:"upload_id"`  // backend ID of the upload
+	ChunkSize int64          `json:"chunk_size"` // size of the chunks
+	Parts     map[int]string `json:"parts"`      // backend ID, e.g. ETag, of each chunk number written
+}
+
+// ChunkWriterStater is an optional interface for ChunkWriter
+type ChunkWriterStater interface {
+	// State returns the state of the upload so far so it can be
+	// resumed with ResumeChunkWriter
+	//
+	// Only chunks which have been written successfully should be
+	// returned. This may be called concurrently with WriteChunk.
+	State() ChunkWriterState
+}
+
+// ResumeChunkWriter is an optional interface for Fs to implement
+// resuming chunked writing
+type ResumeChunkWriter interface {
+	// ResumeChunkWriter resumes an interrupted chunked upload
+	// using the state returned by ChunkWriterStater.State
+	//
+	// The chunks in state.Parts have been written already and
+	// won't be written again.
+	ResumeChunkWriter(ctx context.Context, remote string, src ObjectInfo, state ChunkWriterState, options ...OpenOption) (info ChunkWriterInfo, writer ChunkWriter, err error)
+}
+
 // UserInfoer is an optional interface for Fs
 type UserInfoer interface {
 	// UserInfo returns info about the connected user
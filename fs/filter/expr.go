@@ -0,0 +1,628 @@
+package filter
+
+// The --filter-expr expression language
+//
+// An expression is made of comparisons of object properties with
+// values combined with the boolean operators and, or, not and unless,
+// for example
+//
+//	name =~ "*.log" and (age > 30d or size > 1G) unless meta.tag == "keep"
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// exprEnv supplies the properties of the object being filtered
+//
+// The properties are read lazily as some of them are expensive.
+type exprEnv interface {
+	remote() string
+	size() int64
+	modTime() time.Time
+	metadata() fs.Metadata
+	mimeType() string
+	tier() string
+	hash(ht hash.Type) string
+}
+
+// exprValues is an exprEnv for an object which isn't available,
+// only its properties
+//
+// The properties which can only be read from an object, the tier and
+// the hashes, are empty. See exprObjectOnly.
+type exprValues struct {
+	Remote   string
+	Size     int64
+	ModTime  time.Time
+	Metadata fs.Metadata
+}
+
+func (e *exprValues) remote() string           { return e.Remote }
+func (e *exprValues) size() int64              { return e.Size }
+func (e *exprValues) modTime() time.Time       { return e.ModTime }
+func (e *exprValues) metadata() fs.Metadata    { return e.Metadata }
+func (e *exprValues) mimeType() string         { return fs.MimeTypeFromName(e.Remote) }
+func (e *exprValues) tier() string             { return "" }
+func (e *exprValues) hash(ht hash.Type) string { return "" }
+
+// exprObject is an exprEnv which reads the properties from an fs.Object
+type exprObject struct {
+	ctx         context.Context
+	o           fs.Object
+	haveModTime bool
+	mt          time.Time
+	haveMeta    bool
+	meta        fs.Metadata
+}
+
+// newExprObject makes an exprEnv for o
+//
+// metadata should be set if it has been read already.
+func newExprObject(ctx context.Context, o fs.Object, metadata fs.Metadata) *exprObject {
+	return &exprObject{
+		ctx:      ctx,
+		o:        o,
+		haveMeta: metadata != nil,
+		meta:     metadata,
+	}
+}
+
+func (e *exprObject) remote() string { return e.o.Remote() }
+func (e *exprObject) size() int64    { return e.o.Size() }
+
+func (e *exprObject) modTime() time.Time {
+	if !e.haveModTime {
+		e.mt = e.o.ModTime(e.ctx)
+		e.haveModTime = true
+	}
+	return e.mt
+}
+
+func (e *exprObject) metadata() fs.Metadata {
+	if !e.haveMeta {
+		var err error
+		e.meta, err = fs.GetMetadata(e.ctx, e.o)
+		if err != nil {
+			fs.Errorf(e.o, "Failed to read metadata: %v", err)
+		}
+		e.haveMeta = true
+	}
+	return e.meta
+}
+
+func (e *exprObject) mimeType() string {
+	return fs.MimeType(e.ctx, e.o)
+}
+
+func (e *exprObject) tier() string {
+	if do, ok := e.o.(fs.GetTierer); ok {
+		return do.GetTier()
+	}
+	return ""
+}
+
+func (e *exprObject) hash(ht hash.Type) string {
+	sum, err := e.o.Hash(e.ctx, ht)
+	if err != nil && !errors.Is(err, hash.ErrUnsupported) {
+		fs.Errorf(e.o, "Failed to read %v hash: %v", ht, err)
+	}
+	return sum
+}
+
+// exprNode is a node in a parsed expression
+type exprNode interface {
+	// eval returns whether the object in env matches
+	eval(env exprEnv) bool
+	// String returns the node as an expression
+	String() string
+}
+
+// exprAnd is true if both sides are true
+type exprAnd struct{ l, r exprNode }
+
+func (n *exprAnd) eval(env exprEnv) bool { return n.l.eval(env) && n.r.eval(env) }
+func (n *exprAnd) String() string        { return fmt.Sprintf("(%v and %v)", n.l, n.r) }
+
+// exprOr is true if either side is true
+type exprOr struct{ l, r exprNode }
+
+func (n *exprOr) eval(env exprEnv) bool { return n.l.eval(env) || n.r.eval(env) }
+func (n *exprOr) String() string        { return fmt.Sprintf("(%v or %v)", n.l, n.r) }
+
+// exprNot inverts its operand
+type exprNot struct{ x exprNode }
+
+func (n *exprNot) eval(env exprEnv) bool { return !n.x.eval(env) }
+func (n *exprNot) String() string        { return fmt.Sprintf("not %v", n.x) }
+
+// exprObjectOnly returns the properties used in n which can only be
+// read from an object, not from the values exprValues has
+func exprObjectOnly(n exprNode) (names []string) {
+	switch n := n.(type) {
+	case *exprAnd:
+		return append(exprObjectOnly(n.l), exprObjectOnly(n.r)...)
+	case *exprOr:
+		return append(exprObjectOnly(n.l), exprObjectOnly(n.r)...)
+	case *exprNot:
+		return exprObjectOnly(n.x)
+	case *exprCompare:
+		switch n.field {
+		case fieldTier:
+			return []string{"tier"}
+		case fieldHash:
+			return []string{"hash." + n.key}
+		}
+	}
+	return nil
+}
+
+// exprField is a property of the object which can be compared
+type exprField int
+
+// The properties which can be used in expressions
+const (
+	fieldName exprField = iota
+	fieldPath
+	fieldSize
+	fieldModTime
+	fieldAge
+	fieldMime
+	fieldTier
+	fieldMeta
+	fieldHash
+)
+
+// exprFields maps the simple field names to fields
+var exprFields = map[string]exprField{
+	"name":    fieldName,
+	"path":    fieldPath,
+	"size":    fieldSize,
+	"modtime": fieldModTime,
+	"age":     fieldAge,
+	"mime":    fieldMime,
+	"tier":    fieldTier,
+}
+
+// exprCompare compares a field of the object with a value
+type exprCompare struct {
+	field   exprField
+	key     string         // metadata key or hash name
+	ht      hash.Type      // hash type if fieldHash
+	op      string         // the operator as written
+	timeOp  string         // op to compare modification times with
+	literal string         // the value as written
+	str     string         // string value
+	re      *regexp.Regexp // for =~ and !~
+	n       int64          // size value
+	t       time.Time      // time value for modtime and age
+}
+
+// String returns the comparison as an expression
+func (n *exprCompare) String() string {
+	name := n.key
+	switch n.field {
+	case fieldMeta:
+		name = "meta." + n.key
+	case fieldHash:
+		name = "hash." + n.key
+	default:
+		for k, v := range exprFields {
+			if v == n.field {
+				name = k
+			}
+		}
+	}
+	return fmt.Sprintf("%s %s %s", name, n.op, strconv.Quote(n.literal))
+}
+
+// compare returns the result of op given cmp which is -1, 0, +1 for
+// less, equal, greater
+func compare(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// eval the comparison
+func (n *exprCompare) eval(env exprEnv) bool {
+	switch n.field {
+	case fieldSize:
+		size := env.size()
+		if size < 0 {
+			// Objects of unknown size never match
+			return false
+		}
+		return compare(n.op, cmp.Compare(size, n.n))
+	case fieldModTime, fieldAge:
+		return compare(n.timeOp, env.modTime().Compare(n.t))
+	}
+	var value string
+	switch n.field {
+	case fieldName:
+		value = path.Base(env.remote())
+	case fieldPath:
+		value = env.remote()
+	case fieldMime:
+		value = env.mimeType()
+	case fieldTier:
+		value = env.tier()
+	case fieldMeta:
+		value = env.metadata()[n.key]
+	case fieldHash:
+		value = env.hash(n.ht)
+	}
+	switch n.op {
+	case "=~":
+		return n.re.MatchString(value)
+	case "!~":
+		return !n.re.MatchString(value)
+	}
+	return compare(n.op, strings.Compare(value, n.str))
+}
+
+// exprToken is a lexical token
+type exprToken struct {
+	kind  byte   // '(' ')' 'o' for an operator, 'w' for a word, 's' for a string, 0 for EOF
+	value string // the text of the token, unquoted for strings
+	pos   int    // offset of the token in the expression
+}
+
+// exprOps are the comparison operators, longest first
+var exprOps = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!"}
+
+// lexExpr splits the expression into tokens
+func lexExpr(expr string) (tokens []exprToken, err error) {
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, exprToken{kind: c, value: string(c), pos: i})
+			i++
+		case c == '"':
+			// Double quoted strings have Go escapes
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			value, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("bad string at offset %d: %w", i, err)
+			}
+			tokens = append(tokens, exprToken{kind: 's', value: value, pos: i})
+			i = end + 1
+		case c == '\'':
+			// Single quoted strings are raw
+			end := strings.IndexByte(expr[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, exprToken{kind: 's', value: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			op := ""
+			for _, o := range exprOps {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op != "" {
+				tokens = append(tokens, exprToken{kind: 'o', value: op, pos: i})
+				i += len(op)
+				continue
+			}
+			end := i
+			for end < len(expr) && !strings.ContainsRune(" \t\n\r()\"'=!<>&|", rune(expr[end])) {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, exprToken{kind: 'w', value: expr[i:end], pos: i})
+			i = end
+		}
+	}
+	tokens = append(tokens, exprToken{pos: len(expr)})
+	return tokens, nil
+}
+
+// exprParser is a recursive descent parser for expressions
+type exprParser struct {
+	tokens     []exprToken
+	i          int
+	ignoreCase bool
+	now        time.Time
+}
+
+// parseExpr parses expr returning the root node
+//
+// Times relative to now such as ages are resolved using now.
+func parseExpr(expr string, ignoreCase bool, now time.Time) (exprNode, error) {
+	tokens, err := lexExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{
+		tokens:     tokens,
+		ignoreCase: ignoreCase,
+		now:        now,
+	}
+	node, err := p.parseUnless()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != 0 {
+		return nil, p.errorf(tok, "expecting end of expression")
+	}
+	return node, nil
+}
+
+// peek returns the current token
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.i]
+}
+
+// next returns the current token and advances past it
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.i]
+	if tok.kind != 0 {
+		p.i++
+	}
+	return tok
+}
+
+// isKeyword returns true if tok is one of the keywords or operators
+func isKeyword(tok exprToken, words ...string) bool {
+	if tok.kind != 'w' && tok.kind != 'o' {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(tok.value, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorf returns an error about tok
+func (p *exprParser) errorf(tok exprToken, format string, a ...any) error {
+	found := "end of expression"
+	if tok.kind != 0 {
+		found = strconv.Quote(tok.value)
+	}
+	return fmt.Errorf("%s but found %s at offset %d", fmt.Sprintf(format, a...), found, tok.pos)
+}
+
+// parseUnless parses: or ("unless" or)*
+func (p *exprParser) parseUnless() (exprNode, error) {
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "unless") {
+		p.next()
+		r, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		node = &exprAnd{l: node, r: &exprNot{x: r}}
+	}
+	return node, nil
+}
+
+// parseOr parses: and ("or" and)*
+func (p *exprParser) parseOr() (exprNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or", "||") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		node = &exprOr{l: node, r: r}
+	}
+	return node, nil
+}
+
+// parseAnd parses: not ("and" not)*
+func (p *exprParser) parseAnd() (exprNode, error) {
+	node, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "and", "&&") {
+		p.next()
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		node = &exprAnd{l: node, r: r}
+	}
+	return node, nil
+}
+
+// parseNot parses: ("not" not) | "(" unless ")" | comparison
+func (p *exprParser) parseNot() (exprNode, error) {
+	tok := p.peek()
+	switch {
+	case isKeyword(tok, "not", "!"):
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{x: x}, nil
+	case tok.kind == '(':
+		p.next()
+		node, err := p.parseUnless()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != ')' {
+			return nil, p.errorf(tok, "expecting )")
+		}
+		return node, nil
+	}
+	return p.parseCompare()
+}
+
+// parseCompare parses: field op value
+func (p *exprParser) parseCompare() (exprNode, error) {
+	tok := p.next()
+	if tok.kind != 'w' || isKeyword(tok, "and", "or", "not", "unless") {
+		return nil, p.errorf(tok, "expecting a property name")
+	}
+	n := &exprCompare{}
+	name := tok.value
+	lowerName := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lowerName, "meta."):
+		n.field = fieldMeta
+		n.key = strings.ToLower(name[len("meta."):])
+	case strings.HasPrefix(lowerName, "hash."):
+		n.field = fieldHash
+		n.key = name[len("hash."):]
+		if err := n.ht.Set(n.key); err != nil {
+			return nil, fmt.Errorf("bad hash %q at offset %d: %w", name, tok.pos, err)
+		}
+	default:
+		field, ok := exprFields[lowerName]
+		if !ok {
+			return nil, fmt.Errorf("unknown property %q at offset %d", name, tok.pos)
+		}
+		n.field = field
+	}
+	if (n.field == fieldMeta || n.field == fieldHash) && n.key == "" {
+		return nil, fmt.Errorf("missing key in %q at offset %d", name, tok.pos)
+	}
+	opTok := p.next()
+	switch opTok.value {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		n.op = opTok.value
+	default:
+		return nil, p.errorf(opTok, "expecting a comparison operator after %q", name)
+	}
+	valueTok := p.next()
+	if valueTok.kind != 'w' && valueTok.kind != 's' {
+		return nil, p.errorf(valueTok, "expecting a value after %q", n.op)
+	}
+	n.literal = valueTok.value
+	err := p.setValue(n)
+	if err != nil {
+		return nil, fmt.Errorf("bad value %q for %q at offset %d: %w", n.literal, name, valueTok.pos, err)
+	}
+	return n, nil
+}
+
+// setValue parses the literal of n according to its field
+func (p *exprParser) setValue(n *exprCompare) (err error) {
+	switch n.field {
+	case fieldSize, fieldModTime, fieldAge:
+		if n.op == "=~" || n.op == "!~" {
+			return fmt.Errorf("can't use %q with %q", n.op, "size, modtime or age")
+		}
+	}
+	switch n.field {
+	case fieldSize:
+		n.n, err = parseExprSize(n.literal)
+	case fieldModTime:
+		n.t, err = fs.ParseTime(n.literal)
+		n.timeOp = n.op
+	case fieldAge:
+		var age time.Duration
+		age, err = fs.ParseDuration(n.literal)
+		// A larger age is an earlier modification time so
+		// compare the times the other way round
+		n.t = p.now.Add(-age)
+		n.timeOp = map[string]string{"==": "==", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}[n.op]
+	case fieldName:
+		if n.op == "=~" || n.op == "!~" {
+			n.re, err = GlobStringToRegexp(n.literal, true, p.ignoreCase)
+		}
+	case fieldPath:
+		if n.op == "=~" || n.op == "!~" {
+			n.re, err = GlobPathToRegexp(n.literal, p.ignoreCase)
+		}
+	default:
+		if n.op == "=~" || n.op == "!~" {
+			n.re, err = GlobStringToRegexp(n.literal, true, p.ignoreCase)
+		}
+	}
+	n.str = n.literal
+	return err
+}
+
+// parseExprSize parses a size in expressions
+//
+// Unlike --min-size a number without a suffix is in bytes. The size
+// must be a whole number of bytes which fits in an int64.
+func parseExprSize(s string) (int64, error) {
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i < 0 {
+		size, err := strconv.ParseInt(s, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("size %q: %w", s, strconv.ErrRange)
+		}
+		return size, err
+	}
+	var size, multiplier fs.SizeSuffix
+	if err := size.Set(s); err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, errors.New("size can't be off")
+	}
+	// Check the size in floating point as converting an out of
+	// range float to an int64 doesn't give an error
+	if err := multiplier.Set("1" + s[i:]); err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, err
+	}
+	value *= float64(multiplier)
+	if value >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q: %w", s, strconv.ErrRange)
+	}
+	if value != math.Trunc(value) {
+		return 0, fmt.Errorf("size %q isn't a whole number of bytes", s)
+	}
+	return int64(value), nil
+}
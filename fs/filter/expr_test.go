@@ -0,0 +1,236 @@
+package filter
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexExpr(t *testing.T) {
+	tokens, err := lexExpr(`name=~"*.log"&&!(size>=1G||meta.tag != 'a b')`)
+	require.NoError(t, err)
+	var got []string
+	for _, tok := range tokens {
+		got = append(got, string(tok.kind)+tok.value)
+	}
+	assert.Equal(t, []string{
+		"wname", "o=~", "s*.log", "o&&", "o!", "((", "wsize", "o>=", "w1G",
+		"o||", "wmeta.tag", "o!=", "sa b", "))", "\x00",
+	}, got)
+
+	for _, in := range []string{`"unterminated`, `'unterminated`, `"bad \q"`, `name = "x"`} {
+		_, err := lexExpr(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{in: `size > 1G`, want: `size > "1G"`},
+		{in: `a`, err: `unknown property "a" at offset 0`},
+		{in: `name =~ "*.log" and size > 1G or age > 30d`, want: `((name =~ "*.log" and size > "1G") or age > "30d")`},
+		{in: `name =~ "*.log" and (size > 1G or age > 30d)`, want: `(name =~ "*.log" and (size > "1G" or age > "30d"))`},
+		{in: `name == a unless meta.Tag == keep`, want: `(name == "a" and not meta.tag == "keep")`},
+		{in: `!name == a && not path == b`, want: `(not name == "a" and not path == "b")`},
+		{in: `age <= 1h`, want: `age <= "1h"`},
+		{in: `hash.MD5 == abc`, want: `hash.MD5 == "abc"`},
+		{in: `hash.potato == abc`, err: `bad hash "hash.potato" at offset 0: unknown hash type "potato"`},
+		{in: `meta. == abc`, err: `missing key in "meta." at offset 0`},
+		{in: `size =~ 1G`, err: `bad value "1G" for "size" at offset 8: can't use "=~" with "size, modtime or age"`},
+		{in: `size > potato`, err: `bad value "potato" for "size" at offset 7: bad suffix 'o'`},
+		{in: `age > potato`, err: `bad value "potato" for "age" at offset 6`},
+		{in: `size > 1G)`, err: `expecting end of expression but found ")" at offset 9`},
+		{in: `(size > 1G`, err: `expecting ) but found end of expression at offset 10`},
+		{in: `size`, err: `expecting a comparison operator after "size" but found end of expression at offset 4`},
+		{in: `size >`, err: `expecting a value after ">" but found end of expression at offset 6`},
+		{in: `and`, err: `expecting a property name but found "and" at offset 0`},
+		{in: ``, err: `expecting a property name but found end of expression at offset 0`},
+	} {
+		node, err := parseExpr(test.in, false, now)
+		if test.err != "" {
+			require.Error(t, err, test.in)
+			assert.Contains(t, err.Error(), test.err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, node.String(), test.in)
+	}
+}
+
+func TestParseExprSize(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want int64
+		err  bool
+	}{
+		{in: "0", want: 0},
+		{in: "100", want: 100},
+		{in: "1k", want: 1024},
+		{in: "1.5M", want: 3 * 1024 * 1024 / 2},
+		{in: "1GiB", want: 1024 * 1024 * 1024},
+		{in: "off", err: true},
+		{in: "", err: true},
+		{in: "9223372036854775807", want: math.MaxInt64},
+		{in: "9223372036854775808", err: true},
+		{in: "1e30G", err: true},
+		{in: "8192P", err: true},
+		{in: "1.5", err: true},
+		{in: "0.5b", err: true},
+		{in: "-1k", err: true},
+	} {
+		got, err := parseExprSize(test.in)
+		if test.err {
+			assert.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			assert.Equal(t, test.want, got, test.in)
+		}
+	}
+}
+
+func TestExprEval(t *testing.T) {
+	now := time.Now()
+	env := &exprValues{
+		Remote:   "dir/file.log",
+		Size:     2 << 30,
+		ModTime:  now.Add(-40 * 24 * time.Hour),
+		Metadata: fs.Metadata{"tag": "keep", "owner": "bob"},
+	}
+	for _, test := range []struct {
+		in   string
+		want bool
+	}{
+		{`name == "file.log"`, true},
+		{`name == "dir/file.log"`, false},
+		{`name =~ "*.log"`, true},
+		{`name =~ "*.LOG"`, false},
+		{`name !~ "*.log"`, false},
+		{`path == "dir/file.log"`, true},
+		{`path =~ "*.log"`, true},
+		{`path =~ "/*.log"`, false},
+		{`path =~ "dir/**"`, true},
+		{`path < "e"`, true},
+		{`size > 1G`, true},
+		{`size >= 2G`, true},
+		{`size > 2G`, false},
+		{`size == 2147483648`, true},
+		{`size < 3G`, true},
+		{`age > 30d`, true},
+		{`age < 30d`, false},
+		{`age < 50d`, true},
+		{`modtime < 2000-01-01`, false},
+		{`modtime > 2000-01-01`, true},
+		{`modtime > 1w`, false},
+		{`mime == text/plain`, false},
+		{`mime =~ "*/*"`, true},
+		{`tier == ""`, true},
+		{`meta.tag == keep`, true},
+		{`meta.tag != keep`, false},
+		{`meta.missing == ""`, true},
+		{`meta.owner =~ "b*"`, true},
+		{`hash.md5 == ""`, true},
+		{`name =~ "*.log" and (age > 30d or size > 1G) unless meta.tag == "keep"`, false},
+		{`name =~ "*.log" and (age > 30d or size > 1G) unless meta.tag == "other"`, true},
+		{`not size > 1G or age > 30d`, true},
+		{`not (size > 1G or age > 30d)`, false},
+	} {
+		node, err := parseExpr(test.in, false, now)
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, node.eval(env), test.in)
+	}
+
+	// Case insensitive globs
+	node, err := parseExpr(`name =~ "*.LOG"`, true, now)
+	require.NoError(t, err)
+	assert.True(t, node.eval(env))
+
+	// Unknown sizes never match
+	node, err = parseExpr(`size < 1G`, false, now)
+	require.NoError(t, err)
+	assert.False(t, node.eval(&exprValues{Size: -1}))
+}
+
+func TestExprObjectOnly(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{`name == "file.log" and size > 1G`, nil},
+		{`tier == "cold"`, []string{"tier"}},
+		{`size > 1G or not (hash.md5 == "" unless hash.sha1 != "")`, []string{"hash.md5", "hash.sha1"}},
+	} {
+		node, err := parseExpr(test.in, false, time.Now())
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, exprObjectOnly(node), test.in)
+	}
+}
+
+func TestNewFilterExpr(t *testing.T) {
+	opt := Opt
+	opt.FilterExpr = `name =~ "*.jpg" and size < 100`
+	f, err := NewFilter(&opt)
+	require.NoError(t, err)
+	assert.False(t, f.InActive())
+	assert.Contains(t, f.DumpFilters(), `Expression is: (name =~ "*.jpg" and size < "100")`)
+	testInclude(t, f, []includeTest{
+		{"file1.jpg", 99, 0, true},
+		{"file2.jpg", 100, 0, false},
+		{"file3.png", 99, 0, false},
+		{"potato/file4.jpg", 99, 0, true},
+	})
+	// Directories aren't filtered by expressions
+	testDirInclude(t, f, []includeDirTest{
+		{"potato", true},
+	})
+
+	assert.Equal(t, "", f.exprObject)
+
+	opt.FilterExpr = `tier == "cold" or hash.md5 == ""`
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Equal(t, "tier, hash.md5", f.exprObject)
+	assert.True(t, f.Include("file.txt", 1, time.Now(), nil))
+
+	opt.FilterExpr = `size <`
+	_, err = NewFilter(&opt)
+	assert.ErrorContains(t, err, "filter: --filter-expr: expecting a value")
+
+	// --files-from can't be mixed with it
+	opt.FilterExpr = `size < 1`
+	opt.FilesFrom = []string{testFile(t, "file1\n")}
+	_, err = NewFilter(&opt)
+	assert.Error(t, err)
+}
+
+func TestNewFilterExprIncludeObject(t *testing.T) {
+	ctx := context.Background()
+	opt := Opt
+	opt.FilterExpr = `hash.md5 == "5d41402abc4b2a76b9719d911017c592" and modtime < 2020-01-01`
+	f, err := NewFilter(&opt)
+	require.NoError(t, err)
+	old := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		content string
+		modTime time.Time
+		want    bool
+	}{
+		{"hello", old, true},
+		{"potato", old, false},
+		{"hello", time.Now(), false},
+	} {
+		o := mockobject.New("file.txt").WithContent([]byte(test.content), mockobject.SeekModeNone)
+		require.NoError(t, o.SetModTime(ctx, test.modTime))
+		assert.Equal(t, test.want, f.IncludeObject(ctx, o), test.content)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rclone/rclone/fs"
@@ -75,6 +76,11 @@ var OptionsInfo = fs.Options{{
 	Default: "",
 	Help:    "Partition filenames by hash k/n or randomly @/n",
 	Groups:  "Filter",
+}, {
+	Name:    "filter_expr",
+	Default: "",
+	Help:    "Only transfer files matching this expression, e.g. 'size > 1G and age < 30d'",
+	Groups:  "Filter",
 }, {
 	Name:     "filter",
 	Default:  []string{},
@@ -152,6 +158,7 @@ type Options struct {
 	MaxSize        fs.SizeSuffix `config:"max_size"`
 	IgnoreCase     bool          `config:"ignore_case"`
 	HashFilter     string        `config:"hash_filter"`
+	FilterExpr     string        `config:"filter_expr"`
 }
 
 func init() {
@@ -181,6 +188,9 @@ type Filter struct {
 	dirs        FilesMap // dirs from filesFrom
 	hashFilterN uint64   // if non 0 do hash filtering
 	hashFilterK uint64   // select partition K/N
+	expr        exprNode // parsed --filter-expr if set
+	exprObject  string   // properties in expr which need an object
+	exprWarn    *sync.Once
 }
 
 // NewFilter parses the command line options and creates a Filter
@@ -215,6 +225,16 @@ func NewFilter(opt *Options) (f *Filter, err error) {
 		fs.Debugf(nil, "Using --hash-filter %d/%d", f.hashFilterK, f.hashFilterN)
 	}
 
+	if f.Opt.FilterExpr != "" {
+		f.expr, err = parseExpr(f.Opt.FilterExpr, f.Opt.IgnoreCase, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("filter: --filter-expr: %w", err)
+		}
+		fs.Debugf(nil, "Using --filter-expr %v", f.expr)
+		f.exprObject = strings.Join(exprObjectOnly(f.expr), ", ")
+		f.exprWarn = new(sync.Once)
+	}
+
 	err = parseRules(&f.Opt.RulesOpt, f.Add, f.Clear)
 	if err != nil {
 		return nil, err
@@ -414,7 +434,8 @@ func (f *Filter) InActive() bool {
 		f.dirRules.len() == 0 &&
 		f.metaRules.len() == 0 &&
 		len(f.Opt.ExcludeFile) == 0 &&
-		f.hashFilterN == 0)
+		f.hashFilterN == 0 &&
+		f.expr == nil)
 }
 
 // IncludeRemote returns whether this remote passes the filter rules.
@@ -504,7 +525,27 @@ func (f *Filter) DirContainsExcludeFile(ctx context.Context, fremote fs.Fs, remo
 
 // Include returns whether this object should be included into the
 // sync or not and logs the reason for exclusion if not included
+//
+// The tier and hashes used by --filter-expr can't be read from these
+// values so they are empty. A warning is logged the first time that
+// happens.
 func (f *Filter) Include(remote string, size int64, modTime time.Time, metadata fs.Metadata) bool {
+	if f.exprObject != "" {
+		f.exprWarn.Do(func() {
+			fs.Logf(remote, "--filter-expr uses %s which can't be read here so is treated as empty", f.exprObject)
+		})
+	}
+	return f.include(remote, size, modTime, metadata, &exprValues{
+		Remote:   remote,
+		Size:     size,
+		ModTime:  modTime,
+		Metadata: metadata,
+	})
+}
+
+// include implements Include and IncludeObject reading any properties
+// the --filter-expr needs from env
+func (f *Filter) include(remote string, size int64, modTime time.Time, metadata fs.Metadata, env exprEnv) bool {
 	// filesFrom takes precedence
 	if f.files != nil {
 		_, include := f.files[remote]
@@ -547,8 +588,13 @@ func (f *Filter) Include(remote string, size int64, modTime time.Time, metadata
 	include := f.IncludeRemote(remote)
 	if !include {
 		fs.Debugf(remote, "Excluded (Path Filter)")
+		return false
 	}
-	return include
+	if f.expr != nil && !f.expr.eval(env) {
+		fs.Debugf(remote, "Excluded (Expression Filter)")
+		return false
+	}
+	return true
 }
 
 // IncludeObject returns whether this object should be included into
@@ -572,7 +618,7 @@ func (f *Filter) IncludeObject(ctx context.Context, o fs.Object) bool {
 		}
 
 	}
-	return f.Include(o.Remote(), o.Size(), modTime, metadata)
+	return f.include(o.Remote(), o.Size(), modTime, metadata, newExprObject(ctx, o, metadata))
 }
 
 // DumpFilters dumps the filters in textual form, 1 per line
@@ -590,6 +636,9 @@ func (f *Filter) DumpFilters() string {
 	if f.Opt.MaxSize >= 0 {
 		rules = append(rules, fmt.Sprintf("Maximum size is: %s", f.Opt.MaxSize.ByteUnit()))
 	}
+	if f.expr != nil {
+		rules = append(rules, fmt.Sprintf("Expression is: %v", f.expr))
+	}
 	rules = append(rules, "--- File filter rules ---")
 	for _, rule := range f.fileRules.rules {
 		rules = append(rules, rule.String())
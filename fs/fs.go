@@ -50,6 +50,7 @@ var (
 	ErrorNotImplemented              = errors.New("optional feature not implemented")
 	ErrorCommandNotFound             = errors.New("command not found")
 	ErrorFileNameTooLong             = errors.New("file name too long")
+	ErrorChangeTokenInvalid          = errors.New("change token is no longer valid")
 )
 
 // CheckClose is a utility function used to check the return from
@@ -19,6 +19,7 @@ type Dialer struct {
 	net.Dialer
 	timeout time.Duration
 	tclass  int
+	limiter *accounting.Limiter
 }
 
 // NewDialer creates a Dialer structure with Timeout, Keepalive,
@@ -32,6 +33,7 @@ func NewDialer(ctx context.Context) *Dialer {
 		},
 		timeout: time.Duration(ci.Timeout),
 		tclass:  int(ci.TrafficClass),
+		limiter: accounting.GetLimiter(ctx),
 	}
 	if ci.BindAddr != nil {
 		dialer.Dialer.LocalAddr = &net.TCPAddr{IP: ci.BindAddr}
@@ -39,6 +41,14 @@ func NewDialer(ctx context.Context) *Dialer {
 	return dialer
 }
 
+// SetLimiter sets the limiter used for the bandwidth of the
+// connections in addition to the global one.
+//
+// This defaults to the limiter in the context passed to NewDialer.
+func (d *Dialer) SetLimiter(l *accounting.Limiter) {
+	d.limiter = l
+}
+
 // Dial connects to the network address.
 func (d *Dialer) Dial(network, address string) (net.Conn, error) {
 	return d.DialContext(context.Background(), network, address)
@@ -87,6 +97,7 @@ func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.
 	t := &timeoutConn{
 		Conn:    c,
 		timeout: d.timeout,
+		limiter: d.limiter,
 	}
 	return t, t.nudgeDeadline()
 }
@@ -95,6 +106,7 @@ func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.
 type timeoutConn struct {
 	net.Conn
 	timeout time.Duration
+	limiter *accounting.Limiter
 }
 
 // Nudge the deadline for an idle timeout on by c.timeout if non-zero
@@ -110,6 +122,7 @@ func (c *timeoutConn) Read(b []byte) (n int, err error) {
 	// Ideally we would LimitBandwidth(len(b)) here and replace tokens we didn't use
 	n, err = c.Conn.Read(b)
 	accounting.TokenBucket.LimitBandwidth(accounting.TokenBucketSlotTransportRx, n)
+	c.limiter.LimitBandwidth(accounting.TokenBucketSlotTransportRx, n)
 	if err == nil && n > 0 && c.timeout > 0 {
 		err = c.nudgeDeadline()
 	}
@@ -119,6 +132,7 @@ func (c *timeoutConn) Read(b []byte) (n int, err error) {
 // Write bytes with rate limiting and idle timeouts
 func (c *timeoutConn) Write(b []byte) (n int, err error) {
 	accounting.TokenBucket.LimitBandwidth(accounting.TokenBucketSlotTransportTx, len(b))
+	c.limiter.LimitBandwidth(accounting.TokenBucketSlotTransportTx, len(b))
 	n, err = c.Conn.Write(b)
 	if err == nil && n > 0 && c.timeout > 0 {
 		err = c.nudgeDeadline()
@@ -46,10 +46,19 @@ var (
 	}
 )
 
+// The transports made by NewTransport for remotes with a Limiter
+var (
+	limitedTransportsMu sync.Mutex
+	limitedTransports   = make(map[*accounting.Limiter]*Transport)
+)
+
 // ResetTransport resets the existing transport, allowing it to take new settings.
 // Should only be used for testing.
 func ResetTransport() {
 	noTransport = new(sync.Once)
+	limitedTransportsMu.Lock()
+	clear(limitedTransports)
+	limitedTransportsMu.Unlock()
 }
 
 // LoadKeyPair loads a TLS certificate and private key from PEM-encoded files,
@@ -288,15 +297,33 @@ func NewTransportCustom(ctx context.Context, customize func(*http.Transport)) *T
 	}
 
 	// Wrap that http.Transport in our own transport
-	return newTransport(ci, t)
+	tr := newTransport(ci, t)
+	tr.limiter = accounting.GetLimiter(ctx)
+	tr.remote = fs.RemoteName(ctx)
+	return tr
 }
 
 // NewTransport returns an http.RoundTripper with the correct timeouts
+//
+// The connections are shared with the other users of NewTransport
+// unless ctx has a Limiter, as the bandwidth limit is applied to the
+// connections. Those get a transport per Limiter instead.
 func NewTransport(ctx context.Context) *Transport {
+	if limiter := accounting.GetLimiter(ctx); limiter != nil {
+		limitedTransportsMu.Lock()
+		defer limitedTransportsMu.Unlock()
+		tr, ok := limitedTransports[limiter]
+		if !ok {
+			tr = NewTransportCustom(ctx, nil)
+			limitedTransports[limiter] = tr
+		}
+		return tr.forRemote(fs.RemoteName(ctx))
+	}
 	(*noTransport).Do(func() {
 		transport = NewTransportCustom(ctx, nil)
+		transport.remote = ""
 	})
-	return transport
+	return transport.forRemote(fs.RemoteName(ctx))
 }
 
 // NewClient returns an http.Client with the correct timeouts
@@ -340,20 +367,43 @@ type Transport struct {
 	userAgent     string
 	headers       []*fs.HTTPOption
 	metrics       *Metrics
+	limiter       *accounting.Limiter // limits for the remote if any
+	remote        string              // name of the remote for the metrics if known
 	// Mutex for serializing attempts at reloading the certificates
-	reloadMutex sync.Mutex
+	reloadMutex *sync.Mutex
 }
 
 // newTransport wraps the http.Transport passed in and logs all
 // roundtrips including the body if logBody is set.
 func newTransport(ci *fs.ConfigInfo, transport *http.Transport) *Transport {
 	return &Transport{
-		Transport: transport,
-		ci:        ci,
-		dump:      ci.Dump,
-		userAgent: ci.UserAgent,
-		headers:   ci.Headers,
-		metrics:   DefaultMetrics,
+		Transport:   transport,
+		ci:          ci,
+		dump:        ci.Dump,
+		userAgent:   ci.UserAgent,
+		headers:     ci.Headers,
+		metrics:     DefaultMetrics,
+		reloadMutex: new(sync.Mutex),
+	}
+}
+
+// forRemote returns a Transport sharing the connections of t which
+// labels the metrics with remote
+func (t *Transport) forRemote(remote string) *Transport {
+	if remote == t.remote {
+		return t
+	}
+	return &Transport{
+		Transport:     t.Transport,
+		ci:            t.ci,
+		dump:          t.dump,
+		filterRequest: t.filterRequest,
+		userAgent:     t.userAgent,
+		headers:       t.headers,
+		metrics:       t.metrics,
+		limiter:       t.limiter,
+		remote:        remote,
+		reloadMutex:   t.reloadMutex,
 	}
 }
 
@@ -467,8 +517,16 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 		t.reloadCertificates()
 	}
 
+	// Start a tracing span if enabled
+	span := startHTTPSpan(req)
+	defer func() {
+		span.end(resp, err)
+	}()
+
 	// Limit transactions per second if required
-	accounting.LimitTPS(req.Context())
+	tpsStart := time.Now()
+	accounting.LimitTPS(req.Context(), t.limiter)
+	span.waited("tpslimit wait", time.Since(tpsStart))
 	// Force user agent
 	req.Header.Set("User-Agent", t.userAgent)
 	// Set user defined headers
@@ -493,7 +551,9 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 		logMutex.Unlock()
 	}
 	// Do round trip
+	start := time.Now()
 	resp, err = t.Transport.RoundTrip(req)
+	duration := time.Since(start)
 	// Logf response
 	if t.dump&(fs.DumpHeaders|fs.DumpBodies|fs.DumpAuth|fs.DumpRequests|fs.DumpResponses) != 0 {
 		logMutex.Lock()
@@ -508,8 +568,12 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 		fs.Debugf(nil, "%s", separatorResp)
 		logMutex.Unlock()
 	}
-	// Update metrics
-	t.metrics.onResponse(req, resp)
+	// Update metrics, preferring the remote the request was made for
+	remote := fs.RemoteName(req.Context())
+	if remote == "" {
+		remote = t.remote
+	}
+	t.metrics.onResponse(req, resp, remote, duration)
 
 	if err == nil {
 		checkServerTime(req, resp)
@@ -17,6 +17,8 @@ import (
 	"time"
 
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fstest/mockfs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -175,3 +177,82 @@ func TestCertificates(t *testing.T) {
 	_, err = client.Get(ts.URL)
 	assert.NoError(t, err)
 }
+
+// The transports NewTransport returned to the fshttptest backend by root
+var testTransports = map[string]*Transport{}
+
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name: "fshttptest",
+		NewFs: func(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, error) {
+			testTransports[root] = NewTransport(ctx)
+			return mockfs.NewFs(ctx, name, root, m)
+		},
+	})
+}
+
+func TestNewTransportRemotes(t *testing.T) {
+	ResetTransport()
+	defer ResetTransport()
+	// Don't use the client certificates other tests may have set
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.ClientCert, ci.ClientKey = "", ""
+	t.Setenv("RCLONE_CONFIG_FSHTTPTESTA_TYPE", "fshttptest")
+	t.Setenv("RCLONE_CONFIG_FSHTTPTESTB_TYPE", "fshttptest")
+	for _, remote := range []string{
+		"fshttptesta:plaina",
+		"fshttptestb:plainb",
+		"fshttptesta,override.bwlimit=1M:slow",
+		"fshttptesta,override.bwlimit=1M:slow2",
+		"fshttptestb,override.bwlimit=2M:slower",
+	} {
+		_, err := fs.NewFs(ctx, remote)
+		require.NoError(t, err, remote)
+	}
+	plainA, plainB := testTransports["plaina"], testTransports["plainb"]
+	slow, slow2, slower := testTransports["slow"], testTransports["slow2"], testTransports["slower"]
+
+	// Remotes without limits share the connections but not the
+	// remote name for the metrics
+	assert.Nil(t, plainA.limiter)
+	assert.True(t, plainA.Transport == plainB.Transport)
+	assert.Equal(t, "fshttptesta", plainA.remote)
+	assert.Equal(t, "fshttptestb", plainB.remote)
+
+	// Remotes with different limits have their own
+	require.NotNil(t, slow.limiter)
+	require.NotNil(t, slower.limiter)
+	assert.True(t, slow.limiter != slower.limiter)
+	assert.True(t, slow.Transport != slower.Transport)
+	assert.True(t, plainA.Transport != slow.Transport)
+	assert.Equal(t, "fshttptesta", slow.remote)
+	assert.Equal(t, "fshttptestb", slower.remote)
+
+	// but the Fs made for the same remote share them
+	assert.True(t, slow == slow2)
+}
+
+func TestTransportMetricsRemote(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.ClientCert, ci.ClientKey = "", ""
+	tr := newTransport(ci, http.DefaultTransport.(*http.Transport)).forRemote("transport")
+	tr.metrics = NewMetrics("test")
+
+	get := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+		require.NoError(t, err)
+		resp, err := tr.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// The remote of the request is used if known
+	get(fs.WithRemoteName(ctx, "request"))
+	assert.True(t, tr.metrics.Duration.DeleteLabelValues("request", "GET", "200"))
+
+	// otherwise the remote of the transport
+	get(ctx)
+	assert.True(t, tr.metrics.Duration.DeleteLabelValues("transport", "GET", "200"))
+}
@@ -3,6 +3,7 @@ package fshttp
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -10,6 +11,7 @@ import (
 // Metrics provide Transport HTTP level metrics.
 type Metrics struct {
 	StatusCode *prometheus.CounterVec
+	Duration   *prometheus.HistogramVec
 }
 
 // NewMetrics creates a new metrics instance, the instance shall be assigned to
@@ -21,6 +23,13 @@ func NewMetrics(namespace string) *Metrics {
 			Subsystem: "http",
 			Name:      "status_code",
 		}, []string{"host", "method", "code"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to receive the response headers of HTTP requests by remote, method and status code",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"remote", "method", "code"}),
 	}
 }
 
@@ -34,10 +43,11 @@ func (m *Metrics) Collectors() []prometheus.Collector {
 	}
 	return []prometheus.Collector{
 		m.StatusCode,
+		m.Duration,
 	}
 }
 
-func (m *Metrics) onResponse(req *http.Request, resp *http.Response) {
+func (m *Metrics) onResponse(req *http.Request, resp *http.Response, remote string, duration time.Duration) {
 	if m == nil {
 		return
 	}
@@ -47,5 +57,7 @@ func (m *Metrics) onResponse(req *http.Request, resp *http.Response) {
 		statusCode = resp.StatusCode
 	}
 
-	m.StatusCode.WithLabelValues(req.Host, req.Method, fmt.Sprint(statusCode)).Inc()
+	code := fmt.Sprint(statusCode)
+	m.StatusCode.WithLabelValues(req.Host, req.Method, code).Inc()
+	m.Duration.WithLabelValues(remote, req.Method, code).Observe(duration.Seconds())
 }
@@ -0,0 +1,75 @@
+// Tracing of HTTP requests
+
+package fshttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rclone/rclone/fs/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// minWait is the shortest wait recorded as an event in the span
+const minWait = time.Millisecond
+
+// httpSpan is the tracing span for an HTTP request
+//
+// A nil *httpSpan is valid and records nothing.
+type httpSpan struct {
+	span trace.Span
+}
+
+// startHTTPSpan starts a span for req or returns nil if tracing is
+// disabled.
+//
+// Waiting for the pacer and retries are recorded in the parent span
+// by the pacer.
+func startHTTPSpan(req *http.Request) *httpSpan {
+	if !tracing.Enabled() {
+		return nil
+	}
+	ctx := req.Context()
+	// Don't record the query or user as they may contain secrets
+	u := *req.URL
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", u.String()),
+		attribute.String("server.address", req.URL.Host),
+	}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, attribute.Int64("http.request.body.size", req.ContentLength))
+	}
+	_, span := tracing.StartSpan(ctx, "HTTP "+req.Method, attrs...)
+	return &httpSpan{span: span}
+}
+
+// waited records a wait of d if it was significant
+func (s *httpSpan) waited(name string, d time.Duration) {
+	if s == nil || d < minWait {
+		return
+	}
+	s.span.AddEvent(name, trace.WithAttributes(attribute.Float64("rclone.wait_seconds", d.Seconds())))
+}
+
+// end the span with the result of the request
+func (s *httpSpan) end(resp *http.Response, err error) {
+	if s == nil {
+		return
+	}
+	if resp != nil {
+		s.span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.ContentLength >= 0 {
+			s.span.SetAttributes(attribute.Int64("http.response.body.size", resp.ContentLength))
+		}
+		if err == nil && resp.StatusCode >= 400 {
+			s.span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	tracing.End(s.span, err)
+}
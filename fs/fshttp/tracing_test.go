@@ -0,0 +1,72 @@
+package fshttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/tracing"
+	"github.com/rclone/rclone/lib/pacer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "trace.json")
+	oldTP := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(oldTP)
+	require.NoError(t, tracing.Start(ctx, &tracing.Options{File: file, SampleRatio: 1, ServiceName: "rclone"}))
+
+	// Fail the first request only
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	// Don't use the client certificates from other tests
+	clientCtx, ci := fs.AddConfig(ctx)
+	ci.ClientCert = ""
+	ci.ClientKey = ""
+	client := NewClient(clientCtx)
+
+	// Retry the request with the pacer which records the retries
+	opCtx, span := tracing.StartSpan(ctx, "copy")
+	p := fs.NewPacer(ctx, pacer.NewDefault(pacer.MinSleep(10*time.Millisecond)))
+	p.SetRetries(2)
+	err := p.CallContext(opCtx, func() (bool, error) {
+		req, err := http.NewRequestWithContext(opCtx, "GET", ts.URL+"/file?secret=potato", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		return resp.StatusCode == http.StatusServiceUnavailable, resp.Body.Close()
+	})
+	require.NoError(t, err)
+	tracing.End(span, nil)
+
+	tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	require.True(t, ok)
+	require.NoError(t, tp.ForceFlush(ctx))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	trace := string(data)
+
+	assert.Equal(t, 2, strings.Count(trace, `"Name":"HTTP GET"`))
+	assert.Contains(t, trace, `"Value":"`+ts.URL+`/file"`)
+	assert.NotContains(t, trace, "potato")
+	assert.Contains(t, trace, `"Key":"http.response.status_code","Value":{"Type":"INT64","Value":503}`)
+	assert.Equal(t, 1, strings.Count(trace, `"Name":"pacer retry"`))
+	assert.Contains(t, trace, `"Name":"pacer wait"`)
+}
@@ -23,7 +23,9 @@ import (
 // Files will be returned in sorted order
 func DirSorted(ctx context.Context, f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
 	// Get unfiltered entries from the fs
+	done := accounting.BackendCall(f, accounting.OpList)
 	entries, err = f.List(ctx, dir)
+	done(err)
 	accounting.Stats(ctx).Listed(int64(len(entries)))
 	if err != nil {
 		return nil, err
@@ -40,7 +42,11 @@ func DirSorted(ctx context.Context, f fs.Fs, includeAll bool, dir string) (entri
 }
 
 // listP for every backend
-func listP(ctx context.Context, f fs.Fs, dir string, callback fs.ListRCallback) error {
+func listP(ctx context.Context, f fs.Fs, dir string, callback fs.ListRCallback) (err error) {
+	done := accounting.BackendCall(f, accounting.OpList)
+	defer func() {
+		done(err)
+	}()
 	if doListP := f.Features().ListP; doListP != nil {
 		return doListP(ctx, dir, callback)
 	}
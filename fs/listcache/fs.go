@@ -0,0 +1,412 @@
+package listcache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/dirtree"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// Fs wraps an fs.Fs so its directory listings are read from and saved
+// to the listing cache.
+//
+// Only List and ListR are cached. Everything else is passed straight
+// through and the objects returned refer to the wrapped Fs. ListP isn't
+// supported so paged listings use List instead.
+type Fs struct {
+	fs.Fs
+	features *fs.Features
+	c        *cache
+	ttl      time.Duration
+	modTime  bool // set if modification times should be cached
+	hashes   bool // set if hashes should be cached
+}
+
+// New returns a wrapper for f which caches its directory listings
+//
+// If f supports ChangeNotify then this is started so that changes on
+// the remote invalidate the cached listings.
+func New(ctx context.Context, f fs.Fs) (*Fs, error) {
+	ci := fs.GetConfig(ctx)
+	if ci.ListCacheTTL <= 0 {
+		return nil, fmt.Errorf("listing cache not enabled: set --list-cache-ttl")
+	}
+	if ci.Metadata {
+		return nil, fmt.Errorf("listing cache can't be used with --metadata")
+	}
+	c, err := getCache(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	useTTL(time.Duration(ci.ListCacheTTL))
+	features := f.Features()
+	lf := &Fs{
+		Fs:      f,
+		c:       c,
+		ttl:     time.Duration(ci.ListCacheTTL),
+		modTime: !ci.SizeOnly && !ci.CheckSum && !features.SlowModTime,
+		hashes:  ci.CheckSum && !features.SlowHash,
+	}
+	lf.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          true,
+		ReadMimeType:            true,
+		ReadMetadata:            true,
+		ReadDirMetadata:         true,
+		CanHaveEmptyDirectories: true,
+		BucketBased:             true,
+		BucketBasedRootOK:       true,
+		SlowModTime:             true,
+		SlowHash:                true,
+	}).Fill(ctx, lf).Mask(ctx, f)
+	lf.changeNotify(ctx)
+	return lf, nil
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features { return f.features }
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return f.Fs.String() + " (list cache)"
+}
+
+// changeNotify starts the change notifications for the wrapped Fs
+// unless they are running already
+func (f *Fs) changeNotify(ctx context.Context) {
+	doChangeNotify := f.Fs.Features().ChangeNotify
+	if doChangeNotify == nil {
+		return
+	}
+	f.c.mu.Lock()
+	running := f.c.notifying
+	f.c.notifying = true
+	f.c.mu.Unlock()
+	if running {
+		return
+	}
+	pollInterval := min(f.ttl, maxPollInterval)
+	pollIntervalChan := make(chan time.Duration, 1)
+	pollIntervalChan <- pollInterval
+	doChangeNotify(context.Background(), func(remote string, entryType fs.EntryType) {
+		fs.Debugf(f.Fs, "listing cache: change notify for %q", remote)
+		if entryType == fs.EntryDirectory {
+			f.c.invalidateDir(f.Fs, remote)
+		} else {
+			f.c.invalidate(parentKey(f.Fs, remote), "")
+		}
+	}, pollIntervalChan)
+	fs.Debugf(f.Fs, "listing cache: change notify polling every %v", pollInterval)
+}
+
+// List the objects and directories in dir into entries
+//
+// The listing is read from the cache if there is a valid entry,
+// otherwise it is read from the remote and saved in the cache.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	k := key(f.Fs, dir)
+	if rec := f.c.get(k, f.ttl); rec != nil {
+		fs.Debugf(fs.LogDirName(f.Fs, dir), "Using cached listing from %v", rec.Listed.Format(time.RFC3339))
+		return f.fromRecord(dir, rec), nil
+	}
+	listed := time.Now()
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	f.c.put(k, f.toRecord(ctx, listed, entries))
+	return entries, nil
+}
+
+// ListR lists the objects and directories of the Fs starting from dir
+// recursively into out.
+//
+// The listing is read from the cache if every directory below dir has
+// a valid entry, otherwise it is read from the remote with ListR and
+// each directory in it is saved in the cache.
+//
+// This is only used if the wrapped Fs supports ListR.
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) error {
+	if entries, ok := f.cachedTree(dir); ok {
+		fs.Debugf(fs.LogDirName(f.Fs, dir), "Using cached recursive listing")
+		return callback(entries)
+	}
+	var (
+		mu     sync.Mutex
+		tree   = dirtree.New()
+		listed = time.Now()
+	)
+	err := f.Fs.Features().ListR(ctx, dir, func(entries fs.DirEntries) error {
+		mu.Lock()
+		for _, e := range entries {
+			tree.AddEntry(e)
+		}
+		mu.Unlock()
+		return callback(entries)
+	})
+	if err != nil {
+		return err
+	}
+	// Save every directory, including the empty ones and the parents
+	// which bucket based remotes don't return
+	if _, ok := tree[dir]; !ok {
+		tree[dir] = nil
+	}
+	tree.CheckParents(dir)
+	for dirPath, entries := range tree {
+		f.c.put(key(f.Fs, dirPath), f.toRecord(ctx, listed, entries))
+	}
+	return nil
+}
+
+// cachedTree reads the listing of dir and all the directories below it
+// from the cache, returning ok=false if any of them isn't cached.
+func (f *Fs) cachedTree(dir string) (entries fs.DirEntries, ok bool) {
+	todo := []string{dir}
+	for len(todo) > 0 {
+		dirPath := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		rec := f.c.get(key(f.Fs, dirPath), f.ttl)
+		if rec == nil {
+			return nil, false
+		}
+		for _, e := range f.fromRecord(dirPath, rec) {
+			if _, isDir := e.(fs.Directory); isDir {
+				todo = append(todo, e.Remote())
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, true
+}
+
+// toRecord converts entries into a record for saving
+func (f *Fs) toRecord(ctx context.Context, listed time.Time, entries fs.DirEntries) *record {
+	rec := &record{
+		Listed:  listed,
+		Entries: make([]entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		item := entry{
+			Name: path.Base(e.Remote()),
+			Size: e.Size(),
+		}
+		switch x := e.(type) {
+		case fs.Directory:
+			item.IsDir = true
+			item.Items = x.Items()
+			item.ModTime = x.ModTime(ctx)
+			item.ID = x.ID()
+		case fs.Object:
+			if f.modTime {
+				item.ModTime = x.ModTime(ctx)
+			}
+			if f.hashes {
+				item.Hashes = map[hash.Type]string{}
+				for _, ht := range f.Fs.Hashes().Array() {
+					sum, err := x.Hash(ctx, ht)
+					if err == nil && sum != "" {
+						item.Hashes[ht] = sum
+					}
+				}
+			}
+		}
+		rec.Entries = append(rec.Entries, item)
+	}
+	return rec
+}
+
+// fromRecord converts a saved record for dir into entries
+func (f *Fs) fromRecord(dir string, rec *record) (entries fs.DirEntries) {
+	entries = make(fs.DirEntries, 0, len(rec.Entries))
+	for _, item := range rec.Entries {
+		remote := path.Join(dir, item.Name)
+		if item.IsDir {
+			d := fs.NewDir(remote, item.ModTime).SetSize(item.Size).SetItems(item.Items).SetID(item.ID)
+			entries = append(entries, &Dir{Dir: d, f: f.Fs})
+			continue
+		}
+		entries = append(entries, &Object{
+			f:       f.Fs,
+			remote:  remote,
+			size:    item.Size,
+			modTime: item.ModTime,
+			hashes:  item.Hashes,
+		})
+	}
+	return entries
+}
+
+// Dir is a directory read from the listing cache
+type Dir struct {
+	*fs.Dir
+	f fs.Fs
+}
+
+// Fs returns the Fs that this directory is part of
+func (d *Dir) Fs() fs.Info { return d.f }
+
+// Object is an object read from the listing cache
+//
+// It returns the size, modification time and hashes from the cache
+// where it can. Anything else finds the object on the remote and
+// passes the call on to it.
+type Object struct {
+	f       fs.Fs                // the remote this object is on
+	remote  string               // path of the object
+	size    int64                // size of the object
+	modTime time.Time            // modification time - zero if not cached
+	hashes  map[hash.Type]string // hashes - nil if not cached
+	mu      sync.Mutex           // protects o
+	o       fs.Object            // the object on the remote once found
+}
+
+// resolve finds the object on the remote
+func (o *Object) resolve(ctx context.Context) (fs.Object, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.o == nil {
+		obj, err := o.f.NewObject(ctx, o.remote)
+		if err != nil {
+			return nil, err
+		}
+		o.o = obj
+	}
+	return o.o, nil
+}
+
+// Unwrap returns the object on the remote if o is an Object read from
+// the listing cache, otherwise it returns o.
+//
+// Use this before passing o to backend methods which need an object of
+// their own type, such as server-side Copy and Move.
+func Unwrap(ctx context.Context, o fs.Object) fs.Object {
+	co, ok := o.(*Object)
+	if !ok {
+		return o
+	}
+	obj, err := co.resolve(ctx)
+	if err != nil {
+		fs.Debugf(o, "listing cache: failed to find object: %v", err)
+		return o
+	}
+	return obj
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info { return o.f }
+
+// String returns a description of the Object
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *Object) Remote() string { return o.remote }
+
+// Size returns the size of the file
+func (o *Object) Size() int64 { return o.size }
+
+// Storable says whether this object can be stored
+func (o *Object) Storable() bool { return true }
+
+// ModTime returns the modification time of the object
+func (o *Object) ModTime(ctx context.Context) time.Time {
+	if !o.modTime.IsZero() {
+		return o.modTime
+	}
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		fs.Debugf(o, "listing cache: failed to read modification time: %v", err)
+		return o.modTime
+	}
+	return obj.ModTime(ctx)
+}
+
+// Hash returns the selected checksum of the file
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if sum, ok := o.hashes[ht]; ok {
+		return sum, nil
+	}
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return obj.Hash(ctx, ht)
+}
+
+// SetModTime sets the modification time of the object
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	defer Invalidate(ctx, o.f, o.remote)
+	return obj.SetModTime(ctx, t)
+}
+
+// Open opens the file for read
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Open(ctx, options...)
+}
+
+// Update replaces the object with the contents of in
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	defer Invalidate(ctx, o.f, o.remote)
+	return obj.Update(ctx, in, src, options...)
+}
+
+// Remove removes the object
+func (o *Object) Remove(ctx context.Context) error {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	defer Invalidate(ctx, o.f, o.remote)
+	return obj.Remove(ctx)
+}
+
+// MimeType returns the content type of the object if known
+func (o *Object) MimeType(ctx context.Context) string {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return ""
+	}
+	return fs.MimeType(ctx, obj)
+}
+
+// Metadata returns metadata for the object
+func (o *Object) Metadata(ctx context.Context) (fs.Metadata, error) {
+	obj, err := o.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fs.GetMetadata(ctx, obj)
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs         = (*Fs)(nil)
+	_ fs.ListRer    = (*Fs)(nil)
+	_ fs.Object     = (*Object)(nil)
+	_ fs.Metadataer = (*Object)(nil)
+	_ fs.MimeTyper  = (*Object)(nil)
+	_ fs.Directory  = (*Dir)(nil)
+)
@@ -0,0 +1,367 @@
+// Package listcache implements a persistent cache of directory
+// listings so repeated syncs to large remotes only need to list the
+// directories which could have changed.
+//
+// The listings are stored in a key-value database in the cache
+// directory. A cached listing is used until it is older than
+// --list-cache-ttl, or until it is invalidated by rclone writing to the
+// directory or by a change notification from the remote.
+//
+// Only writes made by rclone with the cache enabled invalidate
+// listings, so writes from anything else, or from rclone runs without
+// --list-cache-ttl, aren't seen until the listing expires.
+package listcache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/lib/atexit"
+	"github.com/rclone/rclone/lib/kv"
+)
+
+// facility is the name of the key-value database
+const facility = "listcache"
+
+// maxPollInterval is the longest interval used for change notifications
+const maxPollInterval = time.Minute
+
+// maxPruneInterval is the longest interval between pruning the
+// expired invalidations
+const maxPruneInterval = time.Minute
+
+// Enabled returns true if the listing cache is configured
+func Enabled(ctx context.Context) bool {
+	return fs.GetConfig(ctx).ListCacheTTL > 0
+}
+
+// record is a cached directory listing
+type record struct {
+	Listed  time.Time // when the listing was started
+	Entries []entry   // the entries in the directory
+}
+
+// entry is a cached directory entry
+type entry struct {
+	Name    string               // leaf name
+	IsDir   bool                 // set if this is a directory
+	Size    int64                // size of the object or directory
+	Items   int64                // number of items in the directory
+	ModTime time.Time            // modification time - zero if not cached
+	ID      string               // ID of the directory if known
+	Hashes  map[hash.Type]string // hashes of the object if cached
+}
+
+func (r *record) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *record) decode(data []byte) error {
+	return gob.NewDecoder(bytes.NewBuffer(data)).Decode(r)
+}
+
+// cache is the open database for a remote and root
+type cache struct {
+	db        *kv.DB
+	mu        sync.Mutex
+	notifying bool // set if change notify is running
+}
+
+var (
+	cachesMu sync.Mutex
+	caches   = map[string]*cache{} // open caches by remote and root
+	exitOnce sync.Once
+)
+
+// The in memory invalidations used to stop listings which raced with
+// a change being saved. These are shared by the caches of all the
+// roots as the database keys are absolute paths.
+//
+// An invalidation older than the longest TTL in use can't stop a
+// listing being saved which would still be valid, so these are pruned
+// once they are that old.
+var (
+	invalidMu  sync.Mutex
+	keys       = map[string]time.Time{} // directories invalidated
+	prefixes   = map[string]time.Time{} // directory trees invalidated
+	invalidTTL time.Duration            // longest TTL of the listing caches
+	pruned     time.Time                // when the invalidations were last pruned
+)
+
+// useTTL records that listings are being cached for ttl
+func useTTL(ttl time.Duration) {
+	invalidMu.Lock()
+	invalidTTL = max(invalidTTL, ttl)
+	invalidMu.Unlock()
+}
+
+// pruneInvalid removes the invalidations which have expired
+//
+// Call with invalidMu held.
+func pruneInvalid(now time.Time) {
+	if now.Sub(pruned) < min(invalidTTL, maxPruneInterval) {
+		return
+	}
+	pruned = now
+	for k, t := range keys {
+		if now.Sub(t) >= invalidTTL {
+			delete(keys, k)
+		}
+	}
+	for prefix, t := range prefixes {
+		if now.Sub(t) >= invalidTTL {
+			delete(prefixes, prefix)
+		}
+	}
+}
+
+// getCache opens the database for f if necessary
+func getCache(ctx context.Context, f fs.Fs) (*cache, error) {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+	name := fs.ConfigString(f)
+	if c := caches[name]; c != nil {
+		return c, nil
+	}
+	if !kv.Supported() {
+		return nil, kv.ErrUnsupported
+	}
+	db, err := kv.Start(ctx, facility, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open listing cache: %w", err)
+	}
+	c := &cache{
+		db: db,
+	}
+	caches[name] = c
+	exitOnce.Do(func() {
+		atexit.Register(stopAll)
+	})
+	return c, nil
+}
+
+// stopAll closes all the open databases
+func stopAll() {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+	for name, c := range caches {
+		_ = c.db.Stop(false)
+		delete(caches, name)
+	}
+	invalidMu.Lock()
+	clear(keys)
+	clear(prefixes)
+	invalidTTL = 0
+	pruned = time.Time{}
+	invalidMu.Unlock()
+}
+
+// remote returns the remote part of the database keys for f
+//
+// This is the config string without the root so that remotes which
+// share a name, like connection strings or remotes with overridden
+// config, don't share listings.
+func remote(f fs.Info) string {
+	name := strings.TrimSuffix(fs.ConfigString(f), f.Root())
+	if name == "" {
+		// local paths have no remote name in their config string
+		name = "local:"
+	}
+	return name
+}
+
+// key returns the database key for dir in f
+//
+// This uses the absolute path so different roots of the same remote
+// share listings.
+func key(f fs.Info, dir string) string {
+	return remote(f) + path.Join(f.Root(), dir)
+}
+
+// keyPrefix returns the prefix of the keys in the tree below dir in f
+func keyPrefix(f fs.Info, dir string) string {
+	k := key(f, dir)
+	if strings.HasSuffix(k, ":") || strings.HasSuffix(k, "/") {
+		return k
+	}
+	return k + "/"
+}
+
+// parentKey returns the database key for the directory containing dir
+// in f, or "" if dir is the top of the remote
+func parentKey(f fs.Info, dir string) string {
+	abs := path.Join(f.Root(), dir)
+	if abs == "" || abs == "/" {
+		return ""
+	}
+	parent := path.Dir(abs)
+	if parent == "." {
+		parent = ""
+	}
+	return remote(f) + parent
+}
+
+// get returns the record for k or nil if there isn't a valid one
+func (c *cache) get(k string, ttl time.Duration) *record {
+	op := &opGet{key: k}
+	if err := c.db.Do(false, op); err != nil {
+		if !errors.Is(err, kv.ErrEmpty) {
+			fs.Debugf(k, "listing cache read failed: %v", err)
+		}
+		return nil
+	}
+	if op.rec == nil || time.Since(op.rec.Listed) >= ttl {
+		return nil
+	}
+	return op.rec
+}
+
+// put saves rec as k unless it was invalidated while it was being listed
+func (c *cache) put(k string, rec *record) {
+	invalidMu.Lock()
+	stale := !keys[k].Before(rec.Listed)
+	for prefix, t := range prefixes {
+		if strings.HasPrefix(k, prefix) && !t.Before(rec.Listed) {
+			stale = true
+		}
+	}
+	invalidMu.Unlock()
+	if stale {
+		return
+	}
+	if err := c.db.Do(true, &opPut{key: k, rec: rec}); err != nil {
+		fs.Debugf(k, "listing cache write failed: %v", err)
+	}
+}
+
+// invalidate removes the directory k and optionally all the
+// directories with prefix from the cache
+func (c *cache) invalidate(k string, prefix string) {
+	now := time.Now()
+	invalidMu.Lock()
+	pruneInvalid(now)
+	keys[k] = now
+	if prefix != "" {
+		prefixes[prefix] = now
+	}
+	invalidMu.Unlock()
+	if err := c.db.Do(true, &opDelete{key: k, prefix: prefix}); err != nil && !errors.Is(err, kv.ErrEmpty) {
+		fs.Debugf(k, "listing cache invalidate failed: %v", err)
+	}
+}
+
+// invalidateDir removes the listings of dir, the directories below it
+// and the directory containing it
+func (c *cache) invalidateDir(f fs.Info, dir string) {
+	c.invalidate(key(f, dir), keyPrefix(f, dir))
+	if k := parentKey(f, dir); k != "" {
+		c.invalidate(k, "")
+	}
+}
+
+// opGet reads a record
+type opGet struct {
+	key string
+	rec *record
+}
+
+func (op *opGet) Do(ctx context.Context, b kv.Bucket) error {
+	data := b.Get([]byte(op.key))
+	if data == nil {
+		return nil
+	}
+	rec := new(record)
+	if err := rec.decode(data); err != nil {
+		fs.Debugf(op.key, "listing cache decoding failed: %v", err)
+		return nil
+	}
+	op.rec = rec
+	return nil
+}
+
+// opPut writes a record
+type opPut struct {
+	key string
+	rec *record
+}
+
+func (op *opPut) Do(ctx context.Context, b kv.Bucket) error {
+	data, err := op.rec.encode()
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(op.key), data)
+}
+
+// opDelete deletes a record and optionally all the records under prefix
+type opDelete struct {
+	key    string
+	prefix string
+}
+
+func (op *opDelete) Do(ctx context.Context, b kv.Bucket) error {
+	keys := []string{op.key}
+	if op.prefix != "" {
+		cur := b.Cursor()
+		for bkey, _ := cur.Seek([]byte(op.prefix)); bkey != nil && bytes.HasPrefix(bkey, []byte(op.prefix)); bkey, _ = cur.Next() {
+			keys = append(keys, string(bkey))
+		}
+	}
+	for _, k := range keys {
+		if err := b.Delete([]byte(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the cache for f if the listing cache is enabled
+func lookup(ctx context.Context, info fs.Info) *cache {
+	if !Enabled(ctx) {
+		return nil
+	}
+	f, ok := info.(fs.Fs)
+	if !ok {
+		return nil
+	}
+	c, err := getCache(ctx, f)
+	if err != nil {
+		fs.Debugf(f, "%v", err)
+		return nil
+	}
+	return c
+}
+
+// Invalidate removes the listing of the directory containing remote
+// from the cache.
+//
+// Call this after creating, modifying or removing remote on f.
+func Invalidate(ctx context.Context, f fs.Info, remote string) {
+	if c := lookup(ctx, f); c != nil {
+		c.invalidate(parentKey(f, remote), "")
+	}
+}
+
+// InvalidateDir removes the listings of dir, all the directories
+// below it and the directory containing it from the cache.
+//
+// Call this after creating, modifying or removing the directory dir
+// on f.
+func InvalidateDir(ctx context.Context, f fs.Info, dir string) {
+	if c := lookup(ctx, f); c != nil {
+		c.invalidateDir(f, dir)
+	}
+}
@@ -0,0 +1,307 @@
+package listcache
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup makes a local remote with a file in and a context with the
+// listing cache enabled
+func setup(t *testing.T, ttl time.Duration) (context.Context, fs.Fs, string) {
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	t.Cleanup(func() {
+		stopAll()
+		_ = config.SetCacheDir(oldCacheDir)
+	})
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.ListCacheTTL = fs.Duration(ttl)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file1"), []byte("hello"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0700))
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+	return ctx, f, dir
+}
+
+// names sorts the entries and returns their names, checking they come
+// from the cache if cached is set
+func names(t *testing.T, entries fs.DirEntries, cached bool) (got []string) {
+	sort.Sort(entries)
+	for _, entry := range entries {
+		switch entry.(type) {
+		case *Object, *Dir:
+			assert.True(t, cached, "unexpected cached entry %v", entry)
+		default:
+			assert.False(t, cached, "unexpected uncached entry %v", entry)
+		}
+		got = append(got, entry.Remote())
+	}
+	return got
+}
+
+func TestNew(t *testing.T) {
+	ctx, f, _ := setup(t, time.Hour)
+	lf, err := New(ctx, f)
+	require.NoError(t, err)
+	assert.Contains(t, lf.String(), "(list cache)")
+	assert.Nil(t, lf.Features().ListR)
+	assert.Nil(t, lf.Features().Purge)
+	assert.False(t, lf.Features().FilterAware)
+
+	_, err = New(context.Background(), f)
+	assert.ErrorContains(t, err, "not enabled")
+}
+
+func TestList(t *testing.T) {
+	ctx, f, dir := setup(t, time.Hour)
+	lf, err := New(ctx, f)
+	require.NoError(t, err)
+
+	// First listing is from the remote
+	entries, err := lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, false))
+
+	// A change made behind rclone's back isn't seen
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file2"), []byte("hello world"), 0600))
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, true))
+
+	// The cached object reads the real one
+	o, ok := entries[0].(*Object)
+	require.True(t, ok)
+	assert.Equal(t, int64(5), o.Size())
+	assert.Equal(t, f, o.Fs())
+	in, err := o.Open(ctx)
+	require.NoError(t, err)
+	data, err := io.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	assert.Equal(t, "hello", string(data))
+	assert.NotEqual(t, o, Unwrap(ctx, o))
+
+	// The cached directory is on the remote
+	d, ok := entries[1].(*Dir)
+	require.True(t, ok)
+	assert.Equal(t, f, d.Fs())
+
+	// Another root of the same remote shares the listings
+	fsub, err := fs.NewFs(ctx, filepath.Join(dir, "sub"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "file3"), []byte("x"), 0600))
+	InvalidateDir(ctx, fsub, "")
+	cachesMu.Lock()
+	assert.Contains(t, caches, fs.ConfigString(f))
+	assert.Contains(t, caches, fs.ConfigString(fsub))
+	cachesMu.Unlock()
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "file2", "sub"}, names(t, entries, false))
+	entries, err = lf.List(ctx, "sub")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub/file3"}, names(t, entries, false))
+
+	// Invalidating a file relists its directory only
+	Invalidate(ctx, f, "sub/file3")
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "file2", "sub"}, names(t, entries, true))
+	entries, err = lf.List(ctx, "sub")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub/file3"}, names(t, entries, false))
+}
+
+// listRFs is an fs.Fs which supports ListR, counting the calls to it
+type listRFs struct {
+	fs.Fs
+	features *fs.Features
+	calls    int
+}
+
+func (f *listRFs) Features() *fs.Features { return f.features }
+
+func (f *listRFs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) error {
+	f.calls++
+	entries, err := f.Fs.List(ctx, dir)
+	if err != nil {
+		return err
+	}
+	if err = callback(entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, ok := entry.(fs.Directory); ok {
+			if err = f.ListR(ctx, entry.Remote(), callback); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recursive lists dir with ListR returning the sorted names
+func recursive(ctx context.Context, t *testing.T, f fs.Fs, dir string, cached bool) []string {
+	var all fs.DirEntries
+	require.NoError(t, f.Features().ListR(ctx, dir, func(entries fs.DirEntries) error {
+		all = append(all, entries...)
+		return nil
+	}))
+	return names(t, all, cached)
+}
+
+func TestListR(t *testing.T) {
+	ctx, f, dir := setup(t, time.Hour)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "file3"), []byte("hello"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub", "empty"), 0700))
+	rf := &listRFs{Fs: f}
+	rf.features = (&fs.Features{}).Fill(ctx, rf)
+	lf, err := New(ctx, rf)
+	require.NoError(t, err)
+	require.NotNil(t, lf.Features().ListR)
+
+	// First listing is from the remote
+	want := []string{"file1", "sub", "sub/empty", "sub/file3"}
+	assert.Equal(t, want, recursive(ctx, t, lf, "", false))
+	calls := rf.calls
+
+	// It fills the cache for every directory listed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "file4"), []byte("hello"), 0600))
+	entries, err := lf.List(ctx, "sub")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub/empty", "sub/file3"}, names(t, entries, true))
+	entries, err = lf.List(ctx, "sub/empty")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// So the next recursive listing is read from the cache
+	assert.Equal(t, want, recursive(ctx, t, lf, "", true))
+	assert.Equal(t, calls, rf.calls)
+
+	// Until a directory in it is invalidated
+	Invalidate(ctx, f, "sub/file4")
+	assert.Equal(t, []string{"file1", "sub", "sub/empty", "sub/file3", "sub/file4"}, recursive(ctx, t, lf, "", false))
+	assert.Greater(t, rf.calls, calls)
+}
+
+func TestTTL(t *testing.T) {
+	ctx, f, dir := setup(t, 100*time.Millisecond)
+	lf, err := New(ctx, f)
+	require.NoError(t, err)
+
+	_, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file2"), []byte("hello world"), 0600))
+	entries, err := lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, true))
+
+	time.Sleep(150 * time.Millisecond)
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "file2", "sub"}, names(t, entries, false))
+}
+
+func TestRemoveInvalidates(t *testing.T) {
+	ctx, f, _ := setup(t, time.Hour)
+	lf, err := New(ctx, f)
+	require.NoError(t, err)
+	entries, err := lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, false))
+
+	// Removing a cached object invalidates the listing
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, true))
+	require.NoError(t, entries[0].(fs.Object).Remove(ctx))
+	entries, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub"}, names(t, entries, false))
+}
+
+func TestInvalidationsPruned(t *testing.T) {
+	ctx, f, _ := setup(t, 100*time.Millisecond)
+	_, err := New(ctx, f)
+	require.NoError(t, err)
+
+	InvalidateDir(ctx, f, "sub")
+	invalidMu.Lock()
+	assert.Len(t, keys, 2)
+	assert.Len(t, prefixes, 1)
+	invalidMu.Unlock()
+
+	// Once the invalidations are older than the TTL they are
+	// removed the next time something is invalidated
+	time.Sleep(150 * time.Millisecond)
+	Invalidate(ctx, f, "file1")
+	invalidMu.Lock()
+	assert.Len(t, keys, 1)
+	assert.Empty(t, prefixes)
+	invalidMu.Unlock()
+}
+
+func TestCacheHashes(t *testing.T) {
+	ctx, f, _ := setup(t, time.Hour)
+	ci := fs.GetConfig(ctx)
+	ci.CheckSum = true
+	lf, err := New(ctx, f)
+	require.NoError(t, err)
+	// local has slow hashes so they aren't cached by default
+	assert.False(t, lf.hashes)
+	assert.False(t, lf.modTime)
+	lf.hashes = true
+
+	_, err = lf.List(ctx, "")
+	require.NoError(t, err)
+	entries, err := lf.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"file1", "sub"}, names(t, entries, true))
+	o := entries[0].(*Object)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", o.hashes[hash.MD5])
+	sum, err := o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", sum)
+	assert.Nil(t, o.o, "shouldn't have read the object")
+
+	// The modification time isn't cached so is read from the object
+	assert.False(t, o.ModTime(ctx).IsZero())
+	assert.NotNil(t, o.o)
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, "/tmp/dir")
+	require.NoError(t, err)
+	assert.Equal(t, "local:/tmp/dir/sub", key(f, "sub"))
+	assert.Equal(t, "local:/tmp/dir/sub/", keyPrefix(f, "sub"))
+	assert.Equal(t, "local:/tmp/dir", parentKey(f, "sub"))
+	assert.Equal(t, "local:/tmp", parentKey(f, ""))
+	f, err = fs.NewFs(ctx, "/")
+	require.NoError(t, err)
+	assert.Equal(t, "local:/", keyPrefix(f, ""))
+	assert.Equal(t, "", parentKey(f, ""))
+
+	// Remotes with different config don't share keys
+	f, err = fs.NewFs(ctx, ":local,case_insensitive:/tmp/dir")
+	require.NoError(t, err)
+	k := key(f, "sub")
+	assert.NotEqual(t, "local:/tmp/dir/sub", k)
+	assert.True(t, strings.HasSuffix(k, ":/tmp/dir/sub"), k)
+	assert.Equal(t, strings.TrimSuffix(k, "/sub"), parentKey(f, "sub"))
+}
@@ -5,6 +5,8 @@
 package log
 
 import (
+	"fmt"
+	"io"
 	"runtime"
 
 	"github.com/rclone/rclone/fs"
@@ -15,3 +17,8 @@ func startSysLog(handler *OutputHandler) bool {
 	fs.Fatalf(nil, "--syslog not supported on %s platform", runtime.GOOS)
 	return false
 }
+
+// NewSyslogWriter returns an error as syslog isn't supported
+func NewSyslogWriter(tag string) (io.Writer, error) {
+	return nil, fmt.Errorf("syslog not supported on %s platform", runtime.GOOS)
+}
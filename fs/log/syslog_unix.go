@@ -5,6 +5,8 @@
 package log
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"log/syslog"
 	"os"
@@ -73,3 +75,17 @@ func startSysLog(handler *OutputHandler) bool {
 	})
 	return true
 }
+
+// NewSyslogWriter returns a writer which sends each Write to syslog at
+// NOTICE level using --syslog-facility and the tag given.
+func NewSyslogWriter(tag string) (io.Writer, error) {
+	facility, ok := syslogFacilityMap[Opt.SyslogFacility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q - man syslog for list", Opt.SyslogFacility)
+	}
+	w, err := syslog.New(syslog.LOG_NOTICE|facility, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start syslog: %w", err)
+	}
+	return w, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/rclone/rclone/fs/dirtree"
 	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/list"
+	"github.com/rclone/rclone/fs/listcache"
 	"github.com/rclone/rclone/fs/walk"
 	"github.com/rclone/rclone/lib/transform"
 	"golang.org/x/text/unicode/norm"
@@ -62,7 +63,16 @@ func (m *March) init(ctx context.Context) {
 	ci := fs.GetConfig(ctx)
 	m.srcListDir = m.makeListDir(ctx, m.Fsrc, m.SrcIncludeAll, m.srcKey)
 	if !m.NoTraverse {
-		m.dstListDir = m.makeListDir(ctx, m.Fdst, m.DstIncludeAll, m.dstKey)
+		fdst := m.Fdst
+		if listcache.Enabled(ctx) {
+			lf, err := listcache.New(ctx, m.Fdst)
+			if err != nil {
+				fs.Logf(m.Fdst, "Not using listing cache: %v", err)
+			} else {
+				fdst = lf
+			}
+		}
+		m.dstListDir = m.makeListDir(ctx, fdst, m.DstIncludeAll, m.dstKey)
 	}
 	// Now create the matching transform
 	// ..normalise the UTF8 first
@@ -43,6 +43,7 @@ func NewFs(ctx context.Context, path string) (Fs, error) {
 	if err != nil {
 		return nil, err
 	}
+	remoteName := configName
 	overridden := fsInfo.Options.Overridden(config)
 	if len(overridden) > 0 {
 		extraConfig := overridden.String()
@@ -72,6 +73,8 @@ func NewFs(ctx context.Context, path string) (Fs, error) {
 	if err != nil {
 		return nil, err
 	}
+	ctx = AddLimiter(ctx, configName, config)
+	ctx = WithRemoteName(ctx, remoteName)
 	f, err := fsInfo.NewFs(ctx, configName, fsPath, config)
 	if f != nil && (err == nil || err == ErrorIsFile) {
 		addReverse(f, fsInfo)
@@ -79,6 +82,27 @@ func NewFs(ctx context.Context, path string) (Fs, error) {
 	return f, err
 }
 
+type remoteNameKeyType struct{}
+
+// Context key for the remote name
+var remoteNameKey = remoteNameKeyType{}
+
+// WithRemoteName returns a copy of ctx recording that it is for the
+// remote called name.
+func WithRemoteName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, remoteNameKey, name)
+}
+
+// RemoteName returns the name of the remote being created with ctx as
+// set by NewFs, or "" if not known.
+//
+// This is the name in the config file without any suffix for
+// overridden config, so it can be used to label metrics.
+func RemoteName(ctx context.Context) string {
+	name, _ := ctx.Value(remoteNameKey).(string)
+	return name
+}
+
 // Add "global" config or "override" to ctx and the global config if required.
 //
 // This looks through keys prefixed with "global." or "override." in
@@ -0,0 +1,103 @@
+package object
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// readerAtSkip is the largest gap between the end of the last read
+// and the start of the next one which will be read and discarded
+// rather than reopening the object.
+const readerAtSkip = 64 * 1024
+
+// ReaderAt implements io.ReaderAt on an fs.Object using range requests
+//
+// An open stream is kept between calls so sequential reads, and
+// reads with small gaps between them, only need one request. Other
+// reads reopen the object at the new offset.
+//
+// It is safe to call ReadAt from multiple goroutines but the calls
+// are serialised.
+type ReaderAt struct {
+	ctx  context.Context
+	o    fs.Object
+	opts []fs.OpenOption
+	mu   sync.Mutex
+	rc   io.ReadCloser // open stream or nil
+	pos  int64         // offset of the next byte from rc
+}
+
+// NewReaderAt makes a ReaderAt reading from o with Open, passing the
+// options given to each call. It should be closed after use.
+func NewReaderAt(ctx context.Context, o fs.Object, options ...fs.OpenOption) *ReaderAt {
+	return &ReaderAt{
+		ctx:  ctx,
+		o:    o,
+		opts: options,
+	}
+}
+
+// Size returns the size of the underlying object
+func (r *ReaderAt) Size() int64 {
+	return r.o.Size()
+}
+
+// closeStream closes the open stream if any - call with mu held
+func (r *ReaderAt) closeStream() (err error) {
+	if r.rc != nil {
+		err = r.rc.Close()
+		r.rc = nil
+	}
+	return err
+}
+
+// ReadAt reads len(p) bytes at offset off
+func (r *ReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	size := r.o.Size()
+	if size >= 0 && off >= size {
+		return 0, io.EOF
+	}
+	if r.rc != nil && off > r.pos && off-r.pos <= readerAtSkip {
+		skipped, err := io.CopyN(io.Discard, r.rc, off-r.pos)
+		r.pos += skipped
+		if err != nil {
+			_ = r.closeStream()
+		}
+	}
+	if r.rc == nil || r.pos != off {
+		_ = r.closeStream()
+		options := append([]fs.OpenOption{&fs.RangeOption{Start: off, End: -1}}, r.opts...)
+		r.rc, err = r.o.Open(r.ctx, options...)
+		if err != nil {
+			return 0, err
+		}
+		r.pos = off
+	}
+	n, err = io.ReadFull(r.rc, p)
+	r.pos += int64(n)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if err != nil {
+		_ = r.closeStream()
+	}
+	return n, err
+}
+
+// Close the ReaderAt, releasing any open stream
+func (r *ReaderAt) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeStream()
+}
+
+// Check interfaces
+var (
+	_ io.ReaderAt = (*ReaderAt)(nil)
+	_ io.Closer   = (*ReaderAt)(nil)
+)
@@ -0,0 +1,72 @@
+package object_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingObject counts the calls to Open
+type countingObject struct {
+	*object.MemoryObject
+	opens int
+}
+
+func (o *countingObject) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o.opens++
+	return o.MemoryObject.Open(ctx, options...)
+}
+
+func TestReaderAt(t *testing.T) {
+	ctx := context.Background()
+	content := make([]byte, 256*1024)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+	o := &countingObject{MemoryObject: object.NewMemoryObject("file", time.Now(), content)}
+	r := object.NewReaderAt(ctx, o)
+	assert.Equal(t, int64(len(content)), r.Size())
+
+	read := func(off int64, n int) []byte {
+		buf := make([]byte, n)
+		got, err := r.ReadAt(buf, off)
+		if err != io.EOF {
+			require.NoError(t, err)
+		}
+		return buf[:got]
+	}
+
+	// Sequential reads use one stream
+	assert.Equal(t, content[0:100], read(0, 100))
+	assert.Equal(t, content[100:1000], read(100, 900))
+	assert.Equal(t, 1, o.opens)
+
+	// A small gap is skipped over
+	assert.Equal(t, content[2000:3000], read(2000, 1000))
+	assert.Equal(t, 1, o.opens)
+
+	// Seeking backwards reopens
+	assert.Equal(t, content[10:20], read(10, 10))
+	assert.Equal(t, 2, o.opens)
+
+	// A large gap reopens
+	assert.Equal(t, content[200000:200010], read(200000, 10))
+	assert.Equal(t, 3, o.opens)
+
+	// Reading past the end returns io.EOF with a short read
+	buf := make([]byte, 100)
+	n, err := r.ReadAt(buf, int64(len(content))-10)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, content[len(content)-10:], buf[:n])
+	n, err = r.ReadAt(buf, int64(len(content)))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 0, n)
+
+	require.NoError(t, r.Close())
+}
@@ -44,6 +44,7 @@ type CheckOpt struct {
 	Match        io.Writer // matching files
 	Differ       io.Writer // differing files
 	Error        io.Writer // files with errors of some kind
+	Report       *Report   // structured report of each file if set
 }
 
 // checkMarch is used to march over two Fses in the same way as
@@ -61,9 +62,15 @@ type checkMarch struct {
 	opt             CheckOpt
 }
 
-// report outputs the fileName to out if required and to the combined log
-func (c *checkMarch) report(o fs.DirEntry, out io.Writer, sigil rune) {
+// report outputs the fileName of src (or dst if src is nil) to out if
+// required and to the combined log and records it in the report
+func (c *checkMarch) report(src, dst fs.DirEntry, out io.Writer, sigil rune, err error) {
+	o := src
+	if o == nil {
+		o = dst
+	}
 	c.reportFilename(o.String(), out, sigil)
+	c.opt.Report.Log(c.ctx, Sigil(sigil), src, dst, err)
 }
 
 func (c *checkMarch) reportFilename(filename string, out io.Writer, sigil rune) {
@@ -87,7 +94,7 @@ func (c *checkMarch) DstOnly(dst fs.DirEntry) (recurse bool) {
 		_ = fs.CountError(c.ctx, err)
 		c.differences.Add(1)
 		c.srcFilesMissing.Add(1)
-		c.report(dst, c.opt.MissingOnSrc, '-')
+		c.report(nil, dst, c.opt.MissingOnSrc, '-', err)
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
 		if c.opt.OneWay {
@@ -109,7 +116,7 @@ func (c *checkMarch) SrcOnly(src fs.DirEntry) (recurse bool) {
 		_ = fs.CountError(c.ctx, err)
 		c.differences.Add(1)
 		c.dstFilesMissing.Add(1)
-		c.report(src, c.opt.MissingOnDst, '+')
+		c.report(src, nil, c.opt.MissingOnDst, '+', err)
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
 		return true
@@ -157,16 +164,16 @@ func (c *checkMarch) Match(ctx context.Context, dst, src fs.DirEntry) (recurse b
 				if err != nil {
 					fs.Errorf(src, "%v", err)
 					_ = fs.CountError(ctx, err)
-					c.report(src, c.opt.Error, '!')
+					c.report(src, dst, c.opt.Error, '!', err)
 				} else if differ {
 					c.differences.Add(1)
 					err := errors.New("files differ")
 					// the checkFn has already logged the reason
 					_ = fs.CountError(ctx, err)
-					c.report(src, c.opt.Differ, '*')
+					c.report(src, dst, c.opt.Differ, '*', err)
 				} else {
 					c.matches.Add(1)
-					c.report(src, c.opt.Match, '=')
+					c.report(src, dst, c.opt.Match, '=', nil)
 					if noHash {
 						c.noHashes.Add(1)
 						fs.Debugf(dstX, "OK - could not check hash")
@@ -181,7 +188,7 @@ func (c *checkMarch) Match(ctx context.Context, dst, src fs.DirEntry) (recurse b
 			_ = fs.CountError(ctx, err)
 			c.differences.Add(1)
 			c.dstFilesMissing.Add(1)
-			c.report(src, c.opt.MissingOnDst, '+')
+			c.report(src, nil, c.opt.MissingOnDst, '+', err)
 		}
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
@@ -194,7 +201,7 @@ func (c *checkMarch) Match(ctx context.Context, dst, src fs.DirEntry) (recurse b
 		_ = fs.CountError(ctx, err)
 		c.differences.Add(1)
 		c.srcFilesMissing.Add(1)
-		c.report(dst, c.opt.MissingOnSrc, '-')
+		c.report(nil, dst, c.opt.MissingOnSrc, '-', err)
 
 	default:
 		panic("Bad object in DirEntries")
@@ -459,6 +466,7 @@ func CheckSum(ctx context.Context, fsrc, fsum fs.Fs, sumFile string, hashType ha
 		}
 		c.dstFilesMissing.Add(1)
 		c.reportFilename(filename, opt.MissingOnDst, '+')
+		c.opt.Report.logPath(ctx, MissingOnDst, filename, nil, nil, err, false)
 	}
 
 	return c.reportResults(ctx, lastErr)
@@ -486,7 +494,7 @@ func (c *checkMarch) checkSum(ctx context.Context, obj fs.Object, download bool,
 		fs.Errorf(obj, "%v", err)
 		c.differences.Add(1)
 		c.srcFilesMissing.Add(1)
-		c.report(obj, c.opt.MissingOnSrc, '-')
+		c.report(nil, obj, c.opt.MissingOnSrc, '-', err)
 		return
 	}
 
@@ -533,22 +541,22 @@ func (c *checkMarch) matchSum(ctx context.Context, sumHash, objHash string, obj
 	case err != nil:
 		_ = fs.CountError(ctx, err)
 		fs.Errorf(obj, "Failed to calculate hash: %v", err)
-		c.report(obj, c.opt.Error, '!')
+		c.report(nil, obj, c.opt.Error, '!', err)
 	case sumHash == "":
 		err = errors.New("duplicate file")
 		_ = fs.CountError(ctx, err)
 		fs.Errorf(obj, "%v", err)
-		c.report(obj, c.opt.Error, '!')
+		c.report(nil, obj, c.opt.Error, '!', err)
 	case objHash == "":
 		fs.Debugf(nil, "%v = %s (sum)", hashType, sumHash)
 		fs.Debugf(obj, "%v - could not check hash (%v)", hashType, c.opt.Fdst)
 		c.noHashes.Add(1)
 		c.matches.Add(1)
-		c.report(obj, c.opt.Match, '=')
+		c.report(nil, obj, c.opt.Match, '=', nil)
 	case objHash == sumHash:
 		fs.Debugf(obj, "%v = %s OK", hashType, sumHash)
 		c.matches.Add(1)
-		c.report(obj, c.opt.Match, '=')
+		c.report(nil, obj, c.opt.Match, '=', nil)
 	default:
 		err = errors.New("files differ")
 		_ = fs.CountError(ctx, err)
@@ -556,7 +564,7 @@ func (c *checkMarch) matchSum(ctx context.Context, sumHash, objHash string, obj
 		fs.Debugf(obj, "%v = %s (%v)", hashType, objHash, c.opt.Fdst)
 		fs.Errorf(obj, "%v", err)
 		c.differences.Add(1)
-		c.report(obj, c.opt.Differ, '*')
+		c.report(nil, obj, c.opt.Differ, '*', err)
 	}
 }
 
@@ -19,9 +19,12 @@ import (
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/listcache"
+	"github.com/rclone/rclone/fs/tracing"
 	"github.com/rclone/rclone/lib/atexit"
 	"github.com/rclone/rclone/lib/pacer"
 	"github.com/rclone/rclone/lib/transform"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // State of the copy
@@ -33,6 +36,7 @@ type copy struct {
 	src           fs.Object            // source object
 	ci            *fs.ConfigInfo       // current config
 	maxTries      int                  // max number of tries to do the copy
+	retries       int                  // number of low level retries done
 	doUpdate      bool                 // whether we are updating an existing file or not
 	hashType      hash.Type            // common hash to use
 	hashOption    *fs.HashesOption     // open option for the common hash
@@ -151,7 +155,9 @@ func (c *copy) serverSideCopy(ctx context.Context) (actionTaken string, newDst f
 	}
 	in := c.tr.Account(ctx, nil) // account the transfer
 	in.ServerSideTransferStart()
+	done := accounting.BackendCall(c.f, accounting.OpCopy)
 	newDst, err = doCopy(ctx, c.src, c.remoteForCopy)
+	done(err)
 	if err == nil {
 		in.ServerSideCopyEnd(newDst.Size()) // account the bytes for the server-side transfer
 	}
@@ -211,6 +217,7 @@ func (c *copy) updateOrPut(ctx context.Context, in io.ReadCloser, uploadOptions
 	if c.src.Remote() != c.remoteForCopy {
 		wrappedSrc = fs.NewOverrideRemote(c.src, c.remoteForCopy)
 	}
+	done := accounting.BackendCall(c.f, accounting.OpPut)
 	if c.doUpdate && c.inplace {
 		err = c.dst.Update(ctx, inAcc, wrappedSrc, uploadOptions...)
 		// Make sure newDst is c.dst since we updated it
@@ -220,6 +227,7 @@ func (c *copy) updateOrPut(ctx context.Context, in io.ReadCloser, uploadOptions
 	} else {
 		newDst, err = c.f.Put(ctx, inAcc, wrappedSrc, uploadOptions...)
 	}
+	done(err)
 	closeErr := inAcc.Close()
 	if err == nil {
 		err = closeErr
@@ -300,6 +308,10 @@ func (c *copy) copy(ctx context.Context) (newDst fs.Object, err error) {
 	var actionTaken string
 	retry := true
 	for tries := 0; retry && tries < c.maxTries; tries++ {
+		c.retries = tries
+		if tries > 0 {
+			tracing.Event(ctx, "retry", attribute.Int("rclone.try", tries+1))
+		}
 		// Check we haven't hit any accounting limits
 		err = c.checkLimits(ctx)
 		if err != nil {
@@ -381,16 +393,26 @@ func (c *copy) copy(ctx context.Context) (newDst fs.Object, err error) {
 // be nil.
 func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
 	ci := fs.GetConfig(ctx)
+	src = listcache.Unwrap(ctx, src)
 	tr := accounting.Stats(ctx).NewTransfer(src, f)
+	start := time.Now()
+	ctx, span := tracing.StartSpan(ctx, "copy", transferAttributes(f, remote, src)...)
+	var c *copy
 	defer func() {
+		tracing.End(span, err)
 		tr.Done(ctx, err)
+		retries := 0
+		if c != nil {
+			retries = c.retries
+		}
+		GetReport(ctx).record(ctx, ReportCopy, reportPath(ctx, dst, remote), src, newDst, start, retries, err)
 	}()
 	if SkipDestructive(ctx, src, "copy") {
 		in := tr.Account(ctx, nil)
 		in.DryRun(src.Size())
 		return newDst, nil
 	}
-	c := &copy{
+	c = &copy{
 		f:           f,
 		dstFeatures: f.Features(),
 		dst:         dst,
@@ -412,6 +434,7 @@ func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Obj
 	if err != nil {
 		return nil, err
 	}
+	defer listcache.Invalidate(ctx, f, c.remote)
 	// Do the copy now everything is set up
 	return c.copy(ctx)
 }
@@ -420,3 +443,15 @@ func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Obj
 func CopyFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
 	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, true, false)
 }
+
+// transferAttributes returns the tracing attributes for a transfer
+// of src to remote on f
+func transferAttributes(f fs.Fs, remote string, src fs.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("rclone.src.fs", fs.ConfigString(src.Fs())),
+		attribute.String("rclone.src.remote", src.Remote()),
+		attribute.String("rclone.dst.fs", fs.ConfigString(f)),
+		attribute.String("rclone.dst.remote", remote),
+		attribute.Int64("rclone.size", src.Size()),
+	}
+}
@@ -90,6 +90,8 @@ type LoggerOpt struct {
 	DestAfter     io.Writer     // files that exist on the destination post-sync
 	JSON          *bytes.Buffer // used by bisync to read/write struct as JSON
 	DeleteModeOff bool          //affects whether Logger expects MissingOnSrc to be deleted
+	DoMove        bool          // affects whether Report expects the source of a Match to be deleted
+	Report        *Report       // structured report of each file if set
 
 	// lsf options for destAfter
 	ListFormat ListFormat
@@ -175,7 +177,11 @@ func GetLoggerOpt(ctx context.Context) LoggerOpt {
 // WithSyncLogger starts a new logger with the options passed in and saves it to ctx for retrieval later
 func WithSyncLogger(ctx context.Context, opt LoggerOpt) context.Context {
 	ctx = WithLoggerOpt(ctx, opt)
+	if opt.Report != nil {
+		ctx = WithReport(ctx, opt.Report)
+	}
 	return WithLogger(ctx, func(ctx context.Context, sigil Sigil, src, dst fs.DirEntry, err error) {
+		opt.Report.logSync(ctx, sigil, src, dst, err)
 		if opt.LoggerFn != nil {
 			opt.LoggerFn(ctx, sigil, src, dst, err)
 		} else {
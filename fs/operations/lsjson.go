@@ -284,7 +284,9 @@ func StatJSON(ctx context.Context, fsrc fs.Fs, remote string, opt *ListJSONOpt)
 			return nil, nil
 		}
 		// Check the root directory exists
+		done := accounting.BackendCall(fsrc, accounting.OpList)
 		entries, err := fsrc.List(ctx, "")
+		done(err)
 		accounting.Stats(ctx).Listed(int64(len(entries)))
 		if err != nil {
 			return nil, err
@@ -323,7 +325,9 @@ func StatJSON(ctx context.Context, fsrc fs.Fs, remote string, opt *ListJSONOpt)
 	if parent == "." || parent == "/" {
 		parent = ""
 	}
+	done := accounting.BackendCall(fsrc, accounting.OpList)
 	entries, err := fsrc.List(ctx, parent)
+	done(err)
 	accounting.Stats(ctx).Listed(int64(len(entries)))
 	if err == fs.ErrorDirNotFound {
 		return nil, nil
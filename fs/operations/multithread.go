@@ -164,9 +164,33 @@ func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object,
 		return nil, fmt.Errorf("multi-thread copy: can't copy zero sized file")
 	}
 
-	info, chunkWriter, err := openChunkWriter(ctx, remote, src, options...)
-	if err != nil {
-		return nil, fmt.Errorf("multi-thread copy: failed to open chunk writer: %w", err)
+	var (
+		info        fs.ChunkWriterInfo
+		chunkWriter fs.ChunkWriter
+		doneChunks  map[int]string
+		resume      *resumeState
+	)
+	if ci.ResumeUploads && !usingOpenWriterAt {
+		resume = newResumeState(ctx, f, remote, src)
+		info, chunkWriter, doneChunks = resume.open(ctx, f, remote, src, options...)
+	}
+	if chunkWriter == nil {
+		info, chunkWriter, err = openChunkWriter(ctx, remote, src, options...)
+		if err != nil {
+			return nil, fmt.Errorf("multi-thread copy: failed to open chunk writer: %w", err)
+		}
+	}
+	stater, canResume := chunkWriter.(fs.ChunkWriterStater)
+	if resume != nil && !canResume {
+		fs.Debugf(src, "multi-thread copy: can't resume uploads to %v", f)
+		resume = nil
+	}
+	if resume != nil {
+		err = resume.save(stater)
+		if err != nil {
+			fs.Errorf(src, "multi-thread copy: failed to save upload state: %v", err)
+			resume = nil
+		}
 	}
 
 	uploadCtx, cancel := context.WithCancel(ctx)
@@ -177,6 +201,15 @@ func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object,
 		if info.LeavePartsOnError || uploadedOK {
 			return
 		}
+		// Leave the parts if resuming so the upload can carry on
+		// where it left off next time, unless it can't succeed.
+		if resume != nil {
+			if resumable(err) {
+				return
+			}
+			fs.Debugf(src, "multi-thread copy: not resuming upload after error: %v", err)
+			resume.remove()
+		}
 		fs.Debugf(src, "multi-thread copy: cancelling transfer on exit")
 		abortErr := chunkWriter.Abort(ctx)
 		if abortErr != nil {
@@ -226,9 +259,22 @@ func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object,
 		if gCtx.Err() != nil {
 			break
 		}
+		if _, done := doneChunks[chunk]; done {
+			start := int64(chunk) * mc.partSize
+			end := min(start+mc.partSize, mc.size)
+			mc.acc.SkipBytes(end - start)
+			fs.Debugf(src, "multi-thread copy: chunk %d/%d already written", chunk+1, mc.numChunks)
+			continue
+		}
 		chunk := chunk
 		g.Go(func() error {
-			return mc.copyChunk(gCtx, chunk, chunkWriter)
+			err := mc.copyChunk(gCtx, chunk, chunkWriter)
+			if err == nil && resume != nil {
+				if saveErr := resume.save(stater); saveErr != nil {
+					fs.Errorf(src, "multi-thread copy: failed to save upload state: %v", saveErr)
+				}
+			}
+			return err
 		})
 	}
 
@@ -241,6 +287,9 @@ func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object,
 		return nil, fmt.Errorf("multi-thread copy: failed to close object after copy: %w", err)
 	}
 	uploadedOK = true // file is definitely uploaded OK so no need to abort
+	if resume != nil {
+		resume.remove()
+	}
 
 	obj, err := f.NewObject(ctx, remote)
 	if err != nil {
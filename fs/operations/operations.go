@@ -32,7 +32,9 @@ import (
 	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fs/fshttp"
 	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/listcache"
 	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fs/tracing"
 	"github.com/rclone/rclone/fs/walk"
 	"github.com/rclone/rclone/lib/atexit"
 	"github.com/rclone/rclone/lib/errcount"
@@ -428,6 +430,7 @@ func move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.
 	origRemote := remote // avoid double-transform on fallback to copy
 	remote = transform.Path(ctx, remote, false)
 	ci := fs.GetConfig(ctx)
+	src = listcache.Unwrap(ctx, src)
 	newDst = dst
 	if ci.DryRun && dst != nil && SameObject(src, dst) && src.Remote() == transform.Path(ctx, dst.Remote(), false) {
 		return // avoid SkipDestructive log for objects that won't really be moved
@@ -438,12 +441,18 @@ func move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.
 	} else {
 		tr = accounting.Stats(ctx).NewCheckingTransfer(src, "moving")
 	}
+	report, start := GetReport(ctx), time.Now()
+	ctx, span := tracing.StartSpan(ctx, "move", transferAttributes(fdst, remote, src)...)
 	defer func() {
 		if err == nil {
 			accounting.Stats(ctx).Renames(1)
 		}
+		tracing.End(span, err)
 		tr.Done(ctx, err)
+		report.record(ctx, ReportMove, reportPath(ctx, dst, origRemote), src, newDst, start, 0, err)
 	}()
+	// The copy and deletes below are reported as part of the move
+	ctx = WithReport(ctx, nil)
 	action := "move"
 	if remote != src.Remote() {
 		action += " to " + remote
@@ -478,7 +487,11 @@ func move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.
 		// Move dst <- src
 		in := tr.Account(ctx, nil) // account the transfer
 		in.ServerSideTransferStart()
+		done := accounting.BackendCall(fdst, accounting.OpMove)
 		newDst, err = doMove(ctx, src, remote)
+		done(err)
+		listcache.Invalidate(ctx, fdst, remote)
+		listcache.Invalidate(ctx, src.Fs(), src.Remote())
 		switch err {
 		case nil:
 			if newDst != nil && src.String() != newDst.String() {
@@ -543,8 +556,11 @@ func SuffixName(ctx context.Context, remote string) string {
 // deleting
 func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs) (err error) {
 	tr := accounting.Stats(ctx).NewCheckingTransfer(dst, "deleting")
+	start := time.Now()
+	reportAction, reportDst := ReportDelete, dst
 	defer func() {
 		tr.Done(ctx, err)
+		GetReport(ctx).record(ctx, reportAction, dst.Remote(), nil, reportDst, start, 0, err)
 	}()
 	err = accounting.Stats(ctx).DeleteFile(ctx, dst.Size())
 	if err != nil {
@@ -553,14 +569,23 @@ func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs
 	action, actioned := "delete", "Deleted"
 	if backupDir != nil {
 		action, actioned = "move into backup dir", "Moved into backup dir"
+		reportAction = ReportBackup
 	}
 	skip := SkipDestructive(ctx, dst, action)
 	if skip {
 		// do nothing
 	} else if backupDir != nil {
-		err = MoveBackupDir(ctx, backupDir, dst)
+		// The move is reported as part of this
+		var backup fs.Object
+		backup, err = moveBackupDir(WithReport(ctx, nil), backupDir, dst)
+		if backup != nil {
+			reportDst = backup
+		}
 	} else {
+		done := accounting.BackendCall(dst.Fs(), accounting.OpDelete)
 		err = dst.Remove(ctx)
+		done(err)
+		listcache.Invalidate(ctx, dst.Fs(), dst.Remote())
 	}
 	if err != nil {
 		fs.Errorf(dst, "Couldn't %s: %v", action, err)
@@ -1050,6 +1075,7 @@ func Mkdir(ctx context.Context, f fs.Fs, dir string) error {
 	}
 	fs.Infof(fs.LogDirName(f, dir), "Making directory")
 	err := f.Mkdir(ctx, dir)
+	listcache.InvalidateDir(ctx, f, dir)
 	if err != nil {
 		err = fs.CountError(ctx, err)
 		return err
@@ -1072,6 +1098,7 @@ func MkdirMetadata(ctx context.Context, f fs.Fs, dir string, metadata fs.Metadat
 	}
 	fs.Debugf(fs.LogDirName(f, dir), "Making directory with metadata")
 	newDst, err = do(ctx, dir, metadata)
+	listcache.InvalidateDir(ctx, f, dir)
 	if err != nil {
 		err = fs.CountError(ctx, err)
 		return nil, err
@@ -1111,6 +1138,7 @@ func MkdirModTime(ctx context.Context, f fs.Fs, dir string, modTime time.Time) (
 	// The directory was created with Mkdir then we should try to set the time
 	if do := f.Features().DirSetModTime; do != nil {
 		err = do(ctx, dir, modTime)
+		listcache.Invalidate(ctx, f, dir)
 	}
 	fs.Infof(logName, "Made directory with modification time %v", modTime)
 	return newDst, err
@@ -1124,6 +1152,7 @@ func TryRmdir(ctx context.Context, f fs.Fs, dir string) error {
 		return nil
 	}
 	fs.Infof(fs.LogDirName(f, dir), "Removing directory")
+	defer listcache.InvalidateDir(ctx, f, dir)
 	return f.Rmdir(ctx, dir)
 }
 
@@ -1147,6 +1176,7 @@ func Purge(ctx context.Context, f fs.Fs, dir string) (err error) {
 			return nil
 		}
 		err = doPurge(ctx, dir)
+		listcache.InvalidateDir(ctx, f, dir)
 		if errors.Is(err, fs.ErrorCantPurge) {
 			doFallbackPurge = true
 		}
@@ -1450,13 +1480,17 @@ func rcatSrc(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadClos
 			if err != nil {
 				return fmt.Errorf("failed to rewind temporary spool file: %v", err)
 			}
+			done := accounting.BackendCall(fdst, accounting.OpPut)
 			dst, err = fdst.Put(ctx, rs, objInfo, options...)
+			done(err)
 			return err
 		})
 	} else {
 		// Upload with PutStream with no retries
 		objInfo := object.NewStaticObjectInfo(dstFileName, modTime, -1, false, nil, fsrc).WithMetadata(meta)
+		done := accounting.BackendCall(fdst, accounting.OpPut)
 		dst, err = doPutStream(ctx, streamIn, objInfo, options...)
+		done(err)
 	}
 	if err != nil {
 		return dst, err
@@ -1816,7 +1850,9 @@ func RcatSize(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadClo
 		}
 
 		info := object.NewStaticObjectInfo(dstFileName, modTime, size, true, nil, fdst).WithMetadata(meta)
+		done := accounting.BackendCall(fdst, accounting.OpPut)
 		obj, err = fdst.Put(ctx, in, info)
+		done(err)
 		if err != nil {
 			fs.Errorf(dstFileName, "Post request put error: %v", err)
 
@@ -1936,10 +1972,16 @@ func BackupDir(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, srcFileName string)
 
 // MoveBackupDir moves a file to the backup dir
 func MoveBackupDir(ctx context.Context, backupDir fs.Fs, dst fs.Object) (err error) {
+	_, err = moveBackupDir(ctx, backupDir, dst)
+	return err
+}
+
+// moveBackupDir moves a file to the backup dir returning the moved
+// object
+func moveBackupDir(ctx context.Context, backupDir fs.Fs, dst fs.Object) (backup fs.Object, err error) {
 	remoteWithSuffix := SuffixName(ctx, dst.Remote())
 	overwritten, _ := backupDir.NewObject(ctx, remoteWithSuffix)
-	_, err = Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
-	return err
+	return Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
 }
 
 // needsMoveCaseInsensitive returns true if moveCaseInsensitive is needed
@@ -2640,6 +2682,11 @@ func CopyDirMetadata(ctx context.Context, f fs.Fs, dst fs.Directory, dir string,
 	if SkipDestructive(ctx, logName, "update directory metadata") {
 		return nil, nil
 	}
+	if dst != nil {
+		defer listcache.Invalidate(ctx, f, dst.Remote())
+	} else {
+		defer listcache.Invalidate(ctx, f, dir)
+	}
 
 	// Options for the directory metadata
 	options := []fs.OpenOption{}
@@ -2704,6 +2751,7 @@ func SetDirModTime(ctx context.Context, f fs.Fs, dst fs.Directory, dir string, m
 	if dst != nil {
 		dir = dst.Remote()
 	}
+	defer listcache.Invalidate(ctx, f, dir)
 
 	// Try to set the ModTime with the Directory.SetModTime method first as this is the most efficient
 	if dst != nil {
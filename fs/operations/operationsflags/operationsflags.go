@@ -5,8 +5,11 @@ package operationsflags
 import (
 	"context"
 	_ "embed"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/rclone/rclone/fs"
@@ -34,11 +37,13 @@ type AddLoggerFlagsOptions struct {
 	Differ       string // differing files
 	ErrFile      string // files with errors of some kind
 	DestAfter    string // files that exist on the destination post-sync
+	Report       string // structured report of what happened to each file
+	ReportFormat string // format of Report - json or csv
 }
 
 // AnySet checks if any of the logger flags have a non-blank value
 func (o AddLoggerFlagsOptions) AnySet() bool {
-	return anyNotBlank(o.Combined, o.MissingOnSrc, o.MissingOnDst, o.Match, o.Differ, o.ErrFile, o.DestAfter)
+	return anyNotBlank(o.Combined, o.MissingOnSrc, o.MissingOnDst, o.Match, o.Differ, o.ErrFile, o.DestAfter, o.Report)
 }
 
 func anyNotBlank(s ...string) bool {
@@ -59,6 +64,7 @@ func AddLoggerFlags(cmdFlags *pflag.FlagSet, opt *operations.LoggerOpt, flagsOpt
 	flags.StringVarP(cmdFlags, &flagsOpt.Differ, "differ", "", flagsOpt.Differ, "Report all non-matching files to this file", "Sync")
 	flags.StringVarP(cmdFlags, &flagsOpt.ErrFile, "error", "", flagsOpt.ErrFile, "Report all files with errors (hashing or reading) to this file", "Sync")
 	flags.StringVarP(cmdFlags, &flagsOpt.DestAfter, "dest-after", "", flagsOpt.DestAfter, "Report all files that exist on the dest post-sync", "Sync")
+	AddReportFlags(cmdFlags, &flagsOpt.Report, &flagsOpt.ReportFormat)
 
 	// lsf flags for destAfter
 	flags.StringVarP(cmdFlags, &opt.Format, "format", "F", "p", "Output format - see lsf help for details", "Sync")
@@ -74,6 +80,57 @@ func AddLoggerFlags(cmdFlags *pflag.FlagSet, opt *operations.LoggerOpt, flagsOpt
 	// flags.BoolVarP(cmdFlags, &recurse, "recursive", "R", false, "Recurse into the listing", "")
 }
 
+// AddReportFlags adds the flags for a structured report to cmdFlags
+func AddReportFlags(cmdFlags *pflag.FlagSet, report, reportFormat *string) {
+	flags.StringVarP(cmdFlags, report, "report", "", *report, "Write a JSON or CSV report of what happened to each file to this file", "Sync")
+	flags.StringVarP(cmdFlags, reportFormat, "report-format", "", *reportFormat, "Format of the --report: json|csv (default from the file extension)", "Sync")
+}
+
+// OpenReport opens name to write a report in format
+//
+// If format is empty it is csv if name ends in .csv, otherwise json.
+// If name is "-" the report is written to stdout. See
+// operations.NewReport for hashes.
+//
+// It returns the report and a function to finish it off and close
+// the output.
+func OpenReport(name, format string, hashes bool) (report *operations.Report, close func() error, err error) {
+	if format == "" {
+		format = "json"
+		if strings.EqualFold(filepath.Ext(name), ".csv") {
+			format = "csv"
+		}
+	}
+	format = strings.ToLower(format)
+	if !slices.Contains(operations.ReportFormats, format) {
+		return nil, nil, fmt.Errorf("unknown --report-format %q - must be one of %s", format, strings.Join(operations.ReportFormats, ", "))
+	}
+	if name == "-" {
+		report, err = operations.NewReport(os.Stdout, format, hashes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return report, report.Close, nil
+	}
+	out, err := os.Create(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err = operations.NewReport(out, format, hashes)
+	if err != nil {
+		_ = out.Close()
+		return nil, nil, err
+	}
+	return report, func() error {
+		err := report.Close()
+		closeErr := out.Close()
+		if err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
 // ConfigureLoggers verifies and sets up writers for log files requested via CLI flags
 func ConfigureLoggers(ctx context.Context, fdst fs.Fs, command *cobra.Command, opt *operations.LoggerOpt, flagsOpt AddLoggerFlagsOptions) (func(), error) {
 	closers := []io.Closer{}
@@ -123,7 +180,22 @@ func ConfigureLoggers(ctx context.Context, fdst fs.Fs, command *cobra.Command, o
 		return nil, err
 	}
 
+	ci := fs.GetConfig(ctx)
+	var closeReport func() error
+	if flagsOpt.Report != "" {
+		var err error
+		opt.Report, closeReport, err = OpenReport(flagsOpt.Report, flagsOpt.ReportFormat, ci.CheckSum)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	close := func() {
+		if closeReport != nil {
+			if err := closeReport(); err != nil {
+				fs.Errorf(nil, "Failed to write --report: %v", err)
+			}
+		}
 		for _, closer := range closers {
 			err := closer.Close()
 			if err != nil {
@@ -132,7 +204,6 @@ func ConfigureLoggers(ctx context.Context, fdst fs.Fs, command *cobra.Command, o
 		}
 	}
 
-	ci := fs.GetConfig(ctx)
 	if ci.NoTraverse && opt.Combined != nil {
 		fs.LogPrintf(fs.LogLevelWarning, nil, "--no-traverse does not list any deletes (-) in --combined output\n")
 	}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/fserrors"
 )
 
@@ -144,7 +145,9 @@ func (h *ReOpen) open() error {
 	if h.tries > h.maxTries {
 		h.err = errTooManyTries
 	} else {
+		done := accounting.BackendCall(h.src.Fs(), accounting.OpGet)
 		h.rc, h.err = h.src.Open(h.ctx, opts...)
+		done(h.err)
 	}
 	if h.err != nil {
 		if h.tries > 1 {
@@ -0,0 +1,417 @@
+// Structured report of what happened to each file
+
+package operations
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/lib/transform"
+)
+
+// Actions in the report for files which were changed. Files which
+// were only compared have the Sigil as the action, eg "match".
+const (
+	ReportCopy   = "copy"
+	ReportMove   = "move"
+	ReportDelete = "delete"
+	ReportBackup = "move_to_backup" // deleted by moving it into --backup-dir
+)
+
+// ReportFormats are the output formats supported by NewReport
+var ReportFormats = []string{"json", "csv"}
+
+// ReportEntry is the record of what happened to a single file
+type ReportEntry struct {
+	Path     string     `json:"path"`                // path of the file relative to the root
+	Action   string     `json:"action"`              // what was done to the file
+	Reason   string     `json:"reason,omitempty"`    // the comparison which caused the action, eg "differ"
+	DryRun   bool       `json:"dry_run,omitempty"`   // set if the action was skipped by --dry-run
+	SrcSize  *int64     `json:"src_size,omitempty"`  // size of the source if known
+	DstSize  *int64     `json:"dst_size,omitempty"`  // size of the destination if known
+	HashType string     `json:"hash_type,omitempty"` // type of SrcHash and DstHash
+	SrcHash  string     `json:"src_hash,omitempty"`  // hash of the source
+	DstHash  string     `json:"dst_hash,omitempty"`  // hash of the destination
+	Started  *time.Time `json:"started,omitempty"`   // when the action was started
+	Duration float64    `json:"duration"`            // time taken by the action in seconds
+	Retries  int        `json:"retries"`             // number of times the action was retried
+	Error    string     `json:"error,omitempty"`     // error if the action failed
+}
+
+// reportActions maps the sigils onto the actions in the report
+var reportActions = map[Sigil]string{
+	MissingOnSrc:  "missing_on_src",
+	MissingOnDst:  "missing_on_dst",
+	Match:         "match",
+	Differ:        "differ",
+	TransferError: "error",
+}
+
+// Report writes a ReportEntry for each file seen by a sync, copy,
+// move or check as soon as it is recorded.
+//
+// The entries of failed actions are held back until the action is
+// retried or the Report is closed so that the retries of an action
+// make a single entry.
+//
+// In a sync, copy or move the comparisons which lead to an action are
+// held back until the action is done and become its Reason, so each
+// file has a single entry. If the action isn't done they are written
+// when the Report is closed.
+//
+// A nil *Report is valid and records nothing.
+type Report struct {
+	mu       sync.Mutex
+	format   string
+	out      io.Writer
+	csv      *csv.Writer
+	n        int                     // number of entries written
+	err      error                   // first error writing the report
+	pending  map[string]*ReportEntry // failed actions which may be retried
+	compared map[string]*ReportEntry // comparisons waiting for their action
+	hashes   bool                    // add hashes of compared files
+}
+
+type reportContextKey struct{}
+
+// NewReport makes a new Report which writes to out in format which
+// should be one of ReportFormats.
+//
+// If hashes is set then the hashes of files which were compared are
+// added to the report. The hashes of files which were transferred
+// are always added if available.
+//
+// Call Close when finished to finish off the report.
+func NewReport(out io.Writer, format string, hashes bool) (*Report, error) {
+	r := &Report{
+		format:   format,
+		out:      out,
+		pending:  make(map[string]*ReportEntry),
+		compared: make(map[string]*ReportEntry),
+		hashes:   hashes,
+	}
+	switch format {
+	case "json":
+		_, r.err = io.WriteString(out, "[")
+	case "csv":
+		r.csv = csv.NewWriter(out)
+		_ = r.csv.Write([]string{"path", "action", "reason", "dry_run", "src_size", "dst_size", "hash_type", "src_hash", "dst_hash", "started", "duration", "retries", "error"})
+		r.csv.Flush()
+		r.err = r.csv.Error()
+	default:
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r, nil
+}
+
+// WithReport returns a copy of ctx in which the file operations are
+// recorded in r
+func WithReport(ctx context.Context, r *Report) context.Context {
+	return context.WithValue(ctx, reportContextKey{}, r)
+}
+
+// GetReport returns the Report stored in ctx or nil if there isn't one
+func GetReport(ctx context.Context) *Report {
+	r, _ := ctx.Value(reportContextKey{}).(*Report)
+	return r
+}
+
+// reportPath returns the path to use in the report for an operation
+// on dst or remote if dst is nil
+func reportPath(ctx context.Context, dst fs.Object, remote string) string {
+	if dst != nil {
+		return dst.Remote()
+	}
+	return transform.Path(ctx, remote, false)
+}
+
+// objectHash returns the hash of o or "" if it couldn't be read
+func objectHash(ctx context.Context, o fs.Object, ht hash.Type) string {
+	if o == nil || ht == hash.None {
+		return ""
+	}
+	sum, err := o.Hash(ctx, ht)
+	if err != nil {
+		fs.Debugf(o, "Failed to read hash for report: %v", err)
+		return ""
+	}
+	return sum
+}
+
+// update fills in the sizes and hashes of src and dst if known
+//
+// Call with r.mu held.
+func (e *ReportEntry) update(src, dst fs.Object, ht hash.Type, srcHash, dstHash string) {
+	if src != nil {
+		size := src.Size()
+		e.SrcSize = &size
+	}
+	if dst != nil {
+		size := dst.Size()
+		e.DstSize = &size
+	}
+	if srcHash != "" || dstHash != "" {
+		e.HashType = ht.String()
+		e.SrcHash = srcHash
+		e.DstHash = dstHash
+	}
+}
+
+// Log records the result of comparing src and dst. It has the
+// signature of a LoggerFn.
+func (r *Report) Log(ctx context.Context, sigil Sigil, src, dst fs.DirEntry, err error) {
+	if r == nil || err == fs.ErrorIsDir {
+		return
+	}
+	srcObj, _ := src.(fs.Object)
+	dstObj, _ := dst.(fs.Object)
+	path, ok := comparedPath(ctx, srcObj, dstObj)
+	if !ok {
+		return
+	}
+	r.logPath(ctx, sigil, path, srcObj, dstObj, err, false)
+}
+
+// logSync records the result of comparing src and dst in a sync, copy
+// or move
+//
+// The comparisons which the sync will act on are held back to become
+// the Reason of the action.
+func (r *Report) logSync(ctx context.Context, sigil Sigil, src, dst fs.DirEntry, err error) {
+	if r == nil || err == fs.ErrorIsDir {
+		return
+	}
+	if sigil == TransferError && err == nil {
+		// the sync logs these after deleting the source of a move
+		return
+	}
+	srcObj, _ := src.(fs.Object)
+	dstObj, _ := dst.(fs.Object)
+	path, ok := comparedPath(ctx, srcObj, dstObj)
+	if !ok {
+		return
+	}
+	opt := GetLoggerOpt(ctx)
+	var hold bool
+	switch sigil {
+	case Differ, MissingOnDst:
+		hold = true
+	case MissingOnSrc:
+		hold = !opt.DeleteModeOff
+	case Match:
+		hold = opt.DoMove
+	}
+	r.logPath(ctx, sigil, path, srcObj, dstObj, err, hold && err == nil)
+}
+
+// comparedPath returns the path to use in the report for comparing
+// src and dst and false if neither is an object
+func comparedPath(ctx context.Context, src, dst fs.Object) (path string, ok bool) {
+	switch {
+	case dst != nil:
+		return dst.Remote(), true
+	case src != nil:
+		return transform.Path(ctx, src.Remote(), false), true
+	}
+	return "", false
+}
+
+// logPath records the result of comparing src and dst at path
+//
+// If hold is set the entry is held back until the action for path is
+// recorded.
+func (r *Report) logPath(ctx context.Context, sigil Sigil, path string, src, dst fs.Object, err error, hold bool) {
+	if r == nil {
+		return
+	}
+	ht := hash.None
+	var srcHash, dstHash string
+	if r.hashes && err == nil && src != nil && dst != nil && (sigil == Match || sigil == Differ) {
+		ht, _ = CommonHash(ctx, src.Fs(), dst.Fs())
+		srcHash = objectHash(ctx, src, ht)
+		dstHash = objectHash(ctx, dst, ht)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e := r.pending[path]; e != nil {
+		// the comparison belongs to a failed action or its retry
+		if err != nil && e.Error == "" {
+			e.Error = err.Error()
+		}
+		return
+	}
+	if e := r.compared[path]; e != nil {
+		// the action wasn't done, eg because of an error
+		delete(r.compared, path)
+		if err != nil && e.Error == "" {
+			e.Error = err.Error()
+		}
+		r.write(e)
+		if sigil == TransferError {
+			return
+		}
+	}
+	e := &ReportEntry{Path: path, Action: reportActions[sigil]}
+	e.update(src, dst, ht, srcHash, dstHash)
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if hold {
+		r.compared[path] = e
+		return
+	}
+	r.write(e)
+}
+
+// record adds the result of doing action to src making dst at path
+// which was started at start, retried retries times and returned err.
+func (r *Report) record(ctx context.Context, action string, path string, src, dst fs.Object, start time.Time, retries int, err error) {
+	if r == nil {
+		return
+	}
+	duration := time.Since(start).Seconds()
+	dryRun := fs.GetConfig(ctx).DryRun
+	ht := hash.None
+	var srcHash, dstHash string
+	if err == nil && !dryRun && action != ReportDelete && dst != nil {
+		if src != nil {
+			ht, _ = CommonHash(ctx, src.Fs(), dst.Fs())
+		} else {
+			ht = dst.Fs().Hashes().GetOne()
+		}
+		if action == ReportCopy {
+			srcHash = objectHash(ctx, src, ht)
+		}
+		dstHash = objectHash(ctx, dst, ht)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reason := ""
+	if c := r.compared[path]; c != nil {
+		reason = c.Action
+		delete(r.compared, path)
+	}
+	e := r.pending[path]
+	if e != nil && e.Action == action {
+		// the action was done again by a high level retry
+		e.Retries++
+	} else {
+		if e != nil {
+			r.write(e)
+		}
+		e = &ReportEntry{Path: path, Action: action, Started: &start}
+	}
+	if reason != "" {
+		e.Reason = reason
+	}
+	e.DryRun = dryRun
+	e.Retries += retries
+	e.Duration += duration
+	e.update(src, dst, ht, srcHash, dstHash)
+	if err != nil {
+		e.Error = err.Error()
+		r.pending[path] = e
+		return
+	}
+	e.Error = ""
+	delete(r.pending, path)
+	r.write(e)
+}
+
+// write writes e to the report
+//
+// Call with r.mu held.
+func (r *Report) write(e *ReportEntry) {
+	if r.err != nil {
+		return
+	}
+	switch r.format {
+	case "json":
+		r.err = writeReportJSON(r.out, e, r.n == 0)
+	case "csv":
+		r.err = writeReportCSV(r.csv, e)
+	}
+	r.n++
+}
+
+// Close writes the entries of the actions which failed and the
+// comparisons which weren't acted on and finishes off the report. It
+// returns the first error writing the report.
+func (r *Report) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, held := range []map[string]*ReportEntry{r.pending, r.compared} {
+		for _, path := range slices.Sorted(maps.Keys(held)) {
+			r.write(held[path])
+			delete(held, path)
+		}
+	}
+	if r.err == nil && r.format == "json" {
+		_, r.err = io.WriteString(r.out, "\n]\n")
+	}
+	return r.err
+}
+
+// writeReportJSON writes e as a line of a JSON list
+func writeReportJSON(out io.Writer, e *ReportEntry, first bool) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if first {
+		sep = "\n"
+	}
+	if _, err = io.WriteString(out, sep); err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// writeReportCSV writes e as a CSV record
+func writeReportCSV(w *csv.Writer, e *ReportEntry) error {
+	size := func(p *int64) string {
+		if p == nil {
+			return ""
+		}
+		return strconv.FormatInt(*p, 10)
+	}
+	started := ""
+	if e.Started != nil {
+		started = e.Started.Format(time.RFC3339Nano)
+	}
+	_ = w.Write([]string{
+		e.Path,
+		e.Action,
+		e.Reason,
+		strconv.FormatBool(e.DryRun),
+		size(e.SrcSize),
+		size(e.DstSize),
+		e.HashType,
+		e.SrcHash,
+		e.DstHash,
+		started,
+		strconv.FormatFloat(e.Duration, 'f', -1, 64),
+		strconv.Itoa(e.Retries),
+		e.Error,
+	})
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,254 @@
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReport makes a JSON report in memory
+//
+// The entries function closes the report and returns its entries
+// indexed by path.
+func newReport(t *testing.T, hashes bool) (report *operations.Report, entries func() map[string]operations.ReportEntry) {
+	var buf bytes.Buffer
+	report, err := operations.NewReport(&buf, "json", hashes)
+	require.NoError(t, err)
+	return report, func() map[string]operations.ReportEntry {
+		require.NoError(t, report.Close())
+		var list []operations.ReportEntry
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+		entries := map[string]operations.ReportEntry{}
+		for _, e := range list {
+			_, found := entries[e.Path]
+			assert.False(t, found, "duplicate entry for %q", e.Path)
+			entries[e.Path] = e
+		}
+		return entries
+	}
+}
+
+func TestReportCopyMoveDelete(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	report, reportEntries := newReport(t, false)
+	ctx = operations.WithReport(ctx, report)
+	haveHash := r.Flocal.Hashes().Overlap(r.Fremote.Hashes()).Count() > 0
+
+	file1 := r.WriteFile("file1", "hello", t1)
+	file2 := r.WriteFile("file2", "hello world", t1)
+	file3 := r.WriteObject(ctx, "file3", "potato", t1)
+
+	src1, err := r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	_, err = operations.Copy(ctx, r.Fremote, nil, file1.Path, src1)
+	require.NoError(t, err)
+
+	src2, err := r.Flocal.NewObject(ctx, file2.Path)
+	require.NoError(t, err)
+	_, err = operations.Move(ctx, r.Fremote, nil, "moved", src2)
+	require.NoError(t, err)
+
+	dst3, err := r.Fremote.NewObject(ctx, file3.Path)
+	require.NoError(t, err)
+	require.NoError(t, operations.DeleteFile(ctx, dst3))
+
+	entries := reportEntries()
+	assert.Len(t, entries, 3)
+
+	e := entries["file1"]
+	assert.Equal(t, operations.ReportCopy, e.Action)
+	require.NotNil(t, e.SrcSize)
+	require.NotNil(t, e.DstSize)
+	assert.Equal(t, int64(5), *e.SrcSize)
+	assert.Equal(t, int64(5), *e.DstSize)
+	assert.NotNil(t, e.Started)
+	assert.Equal(t, 0, e.Retries)
+	assert.Equal(t, "", e.Error)
+	if haveHash {
+		assert.NotEqual(t, "", e.HashType)
+		assert.NotEqual(t, "", e.SrcHash)
+		assert.Equal(t, e.SrcHash, e.DstHash)
+	}
+
+	e = entries["moved"]
+	assert.Equal(t, operations.ReportMove, e.Action)
+	require.NotNil(t, e.DstSize)
+	assert.Equal(t, int64(11), *e.DstSize)
+	assert.Equal(t, "", e.Error)
+
+	e = entries["file3"]
+	assert.Equal(t, operations.ReportDelete, e.Action)
+	assert.Equal(t, "", e.SrcHash)
+	assert.Equal(t, "", e.DstHash)
+}
+
+func TestReportBackupDir(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	report, reportEntries := newReport(t, false)
+	ctx = operations.WithReport(ctx, report)
+
+	file1 := r.WriteObject(ctx, "file1", "potato", t1)
+	backupDir, err := fs.NewFs(ctx, r.FremoteName+"/backup")
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	require.NoError(t, operations.DeleteFileWithBackupDir(ctx, dst, backupDir))
+
+	// The move into the backup dir isn't reported separately
+	entries := reportEntries()
+	assert.Len(t, entries, 1)
+	e := entries["file1"]
+	assert.Equal(t, operations.ReportBackup, e.Action)
+	require.NotNil(t, e.DstSize)
+	assert.Equal(t, int64(6), *e.DstSize)
+	assert.Equal(t, "", e.Error)
+}
+
+func TestReportDryRun(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	ci.DryRun = true
+	r := fstest.NewRun(t)
+	report, reportEntries := newReport(t, false)
+	ctx = operations.WithReport(ctx, report)
+
+	file1 := r.WriteFile("file1", "hello", t1)
+	src1, err := r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	_, err = operations.Copy(ctx, r.Fremote, nil, file1.Path, src1)
+	require.NoError(t, err)
+	r.CheckRemoteItems(t)
+
+	entries := reportEntries()
+	e := entries["file1"]
+	assert.Equal(t, operations.ReportCopy, e.Action)
+	assert.True(t, e.DryRun)
+	assert.Nil(t, e.DstSize)
+}
+
+func TestReportCheck(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	haveHash := r.Flocal.Hashes().Overlap(r.Fremote.Hashes()).Count() > 0
+
+	r.WriteBoth(ctx, "same", "hello", t1)
+	r.WriteFile("differ", "hello", t1)
+	r.WriteObject(ctx, "differ", "hello world", t1)
+	r.WriteFile("srconly", "potato", t1)
+	r.WriteObject(ctx, "dstonly", "potato", t1)
+
+	report, reportEntries := newReport(t, true)
+	opt := operations.CheckOpt{
+		Fdst:   r.Fremote,
+		Fsrc:   r.Flocal,
+		Report: report,
+	}
+	err := operations.Check(ctx, &opt)
+	require.Error(t, err)
+
+	entries := reportEntries()
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "match", entries["same"].Action)
+	if haveHash {
+		assert.Equal(t, hash.MD5.String(), entries["same"].HashType)
+		assert.NotEqual(t, "", entries["same"].SrcHash)
+		assert.Equal(t, entries["same"].SrcHash, entries["same"].DstHash)
+	}
+	assert.Equal(t, "differ", entries["differ"].Action)
+	assert.NotEqual(t, "", entries["differ"].Error)
+	assert.Equal(t, "missing_on_dst", entries["srconly"].Action)
+	assert.Nil(t, entries["srconly"].DstSize)
+	assert.Equal(t, "missing_on_src", entries["dstonly"].Action)
+	assert.Nil(t, entries["dstonly"].SrcSize)
+}
+
+func TestReportRetry(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	report, reportEntries := newReport(t, false)
+	ctx = operations.WithReport(ctx, report)
+
+	// Copy a source which has gone away so the copy fails
+	file1 := r.WriteFile("file1", "hello", t1)
+	src1, err := r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	require.NoError(t, src1.Remove(ctx))
+	_, err = operations.Copy(ctx, r.Fremote, nil, file1.Path, src1)
+	require.Error(t, err)
+
+	// Then retry it successfully
+	file1 = r.WriteFile("file1", "hello", t1)
+	src1, err = r.Flocal.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	_, err = operations.Copy(ctx, r.Fremote, nil, file1.Path, src1)
+	require.NoError(t, err)
+
+	// A copy which is never retried is written when the report is closed
+	file2 := r.WriteFile("file2", "hello", t1)
+	src2, err := r.Flocal.NewObject(ctx, file2.Path)
+	require.NoError(t, err)
+	require.NoError(t, src2.Remove(ctx))
+	_, err = operations.Copy(ctx, r.Fremote, nil, file2.Path, src2)
+	require.Error(t, err)
+
+	entries := reportEntries()
+	assert.Len(t, entries, 2)
+	e := entries["file1"]
+	assert.Equal(t, operations.ReportCopy, e.Action)
+	assert.GreaterOrEqual(t, e.Retries, 1)
+	assert.Equal(t, "", e.Error)
+	e = entries["file2"]
+	assert.Equal(t, operations.ReportCopy, e.Action)
+	assert.NotEqual(t, "", e.Error)
+}
+
+func TestReportWrite(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+
+	for _, format := range operations.ReportFormats {
+		var buf bytes.Buffer
+		report, err := operations.NewReport(&buf, format, false)
+		require.NoError(t, err)
+		ctx := operations.WithReport(ctx, report)
+		for _, name := range []string{"b", "a"} {
+			file := r.WriteFile(name, "hello", t1)
+			src, err := r.Flocal.NewObject(ctx, file.Path)
+			require.NoError(t, err)
+			_, err = operations.Copy(ctx, r.Fremote, nil, file.Path, src)
+			require.NoError(t, err)
+		}
+		require.NoError(t, report.Close())
+
+		switch format {
+		case "json":
+			var entries []operations.ReportEntry
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+			require.Len(t, entries, 2)
+			assert.Equal(t, "b", entries[0].Path)
+			assert.Equal(t, "a", entries[1].Path)
+			assert.Equal(t, operations.ReportCopy, entries[0].Action)
+		case "csv":
+			records, err := csv.NewReader(&buf).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, 3)
+			assert.Equal(t, []string{"path", "action", "reason", "dry_run", "src_size", "dst_size", "hash_type", "src_hash", "dst_hash", "started", "duration", "retries", "error"}, records[0])
+			assert.Equal(t, []string{"b", "copy", "", "false", "5", "5"}, records[1][:6])
+			assert.Equal(t, "a", records[2][0])
+		}
+	}
+
+	_, err := operations.NewReport(&bytes.Buffer{}, "xml", false)
+	assert.Error(t, err)
+}
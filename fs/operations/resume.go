@@ -0,0 +1,158 @@
+package operations
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/fserrors"
+)
+
+// resumeDir is the directory in the cache dir the upload state is
+// saved in
+const resumeDir = "resume"
+
+// resumeFile is the format of the file the upload state is saved in
+type resumeFile struct {
+	Remote      string              `json:"remote"`      // the destination of the upload
+	Fingerprint string              `json:"fingerprint"` // fingerprint of the source
+	State       fs.ChunkWriterState `json:"state"`       // state of the upload
+}
+
+// resumeState saves the state of a chunked upload to a file in the
+// cache directory so it can be resumed if rclone is interrupted.
+type resumeState struct {
+	mu          sync.Mutex
+	path        string // where the state is saved
+	remote      string // the destination of the upload
+	fingerprint string // fingerprint of the source
+}
+
+// newResumeState makes a resumeState for uploading src to remote on f
+func newResumeState(ctx context.Context, f fs.Fs, remote string, src fs.Object) *resumeState {
+	dst := fs.ConfigString(f) + "/" + remote
+	sum := md5.Sum([]byte(dst))
+	return &resumeState{
+		path:        filepath.Join(config.GetCacheDir(), resumeDir, hex.EncodeToString(sum[:])+".json"),
+		remote:      dst,
+		fingerprint: fs.Fingerprint(ctx, src, true),
+	}
+}
+
+// load reads the saved state if there is any
+func (rs *resumeState) load() (file *resumeFile, err error) {
+	data, err := os.ReadFile(rs.path)
+	if err != nil {
+		return nil, err
+	}
+	file = new(resumeFile)
+	err = json.Unmarshal(data, file)
+	if err != nil {
+		return nil, fmt.Errorf("corrupted resume file %q: %w", rs.path, err)
+	}
+	if file.Remote != rs.remote {
+		return nil, fmt.Errorf("resume file %q is for %q", rs.path, file.Remote)
+	}
+	return file, nil
+}
+
+// open resumes the upload from the saved state
+//
+// It returns a nil writer if the upload can't be resumed. If the
+// source has changed since the state was saved the old upload is
+// aborted so it doesn't leave parts behind.
+func (rs *resumeState) open(ctx context.Context, f fs.Fs, remote string, src fs.Object, options ...fs.OpenOption) (info fs.ChunkWriterInfo, writer fs.ChunkWriter, done map[int]string) {
+	file, err := rs.load()
+	if errors.Is(err, os.ErrNotExist) {
+		return info, nil, nil
+	} else if err != nil {
+		fs.Logf(src, "multi-thread copy: can't resume upload: %v", err)
+		rs.remove()
+		return info, nil, nil
+	}
+	resumeChunkWriter := f.Features().ResumeChunkWriter
+	if resumeChunkWriter == nil {
+		rs.remove()
+		return info, nil, nil
+	}
+	info, writer, err = resumeChunkWriter(ctx, remote, src, file.State, options...)
+	if err != nil {
+		fs.Logf(src, "multi-thread copy: can't resume upload %q - starting again: %v", file.State.UploadID, err)
+		rs.remove()
+		return info, nil, nil
+	}
+	var changed string
+	if file.Fingerprint != rs.fingerprint {
+		changed = "source"
+	} else if info.ChunkSize != file.State.ChunkSize {
+		changed = "chunk size"
+	}
+	if changed != "" {
+		fs.Logf(src, "multi-thread copy: %s changed since upload %q was interrupted - starting again", changed, file.State.UploadID)
+		if err := writer.Abort(ctx); err != nil {
+			fs.Debugf(src, "multi-thread copy: failed to abort old upload: %v", err)
+		}
+		rs.remove()
+		return info, nil, nil
+	}
+	fs.Infof(src, "multi-thread copy: resuming upload %q with %d chunks already written", file.State.UploadID, len(file.State.Parts))
+	return info, writer, file.State.Parts
+}
+
+// resumable returns whether an upload which stopped with err should be
+// left so it can be resumed.
+//
+// err is nil if rclone is being interrupted. Errors which won't go away
+// by trying again, such as permission denied, abort the upload so its
+// parts aren't left on the remote.
+func resumable(err error) bool {
+	return err == nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		fserrors.IsRetryError(err) ||
+		fserrors.ShouldRetry(err)
+}
+
+// save writes the state of the upload so far
+func (rs *resumeState) save(writer fs.ChunkWriterStater) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	data, err := json.Marshal(&resumeFile{
+		Remote:      rs.remote,
+		Fingerprint: rs.fingerprint,
+		State:       writer.State(),
+	})
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(rs.path), 0700)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file and rename so the state is
+	// never partially written.
+	tmpPath := rs.path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, rs.path)
+}
+
+// remove deletes the saved state
+func (rs *resumeState) remove() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	err := os.Remove(rs.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fs.Debugf(nil, "Failed to remove resume file: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resumeFs is a mock Fs which can resume chunked uploads
+type resumeFs struct {
+	fs.Fs
+	features  *fs.Features
+	chunkSize int64
+	resumed   []fs.ChunkWriterState
+	aborted   int
+}
+
+func newResumeFs(t *testing.T) *resumeFs {
+	ctx := context.Background()
+	f, err := mockfs.NewFs(ctx, "resume", "root", nil)
+	require.NoError(t, err)
+	rf := &resumeFs{Fs: f, chunkSize: 10}
+	rf.features = (&fs.Features{}).Fill(ctx, rf)
+	return rf
+}
+
+func (f *resumeFs) Features() *fs.Features { return f.features }
+
+func (f *resumeFs) OpenChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, options ...fs.OpenOption) (fs.ChunkWriterInfo, fs.ChunkWriter, error) {
+	return fs.ChunkWriterInfo{ChunkSize: f.chunkSize}, &resumeChunkWriter{f: f, id: "new", chunkSize: f.chunkSize, parts: map[int]string{}}, nil
+}
+
+func (f *resumeFs) ResumeChunkWriter(ctx context.Context, remote string, src fs.ObjectInfo, state fs.ChunkWriterState, options ...fs.OpenOption) (fs.ChunkWriterInfo, fs.ChunkWriter, error) {
+	f.resumed = append(f.resumed, state)
+	return fs.ChunkWriterInfo{ChunkSize: f.chunkSize}, &resumeChunkWriter{f: f, id: state.UploadID, chunkSize: f.chunkSize, parts: state.Parts}, nil
+}
+
+// resumeChunkWriter records the chunks written
+type resumeChunkWriter struct {
+	f         *resumeFs
+	id        string
+	chunkSize int64
+	mu        sync.Mutex
+	parts     map[int]string
+}
+
+func (w *resumeChunkWriter) WriteChunk(ctx context.Context, chunkNumber int, reader io.ReadSeeker) (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.parts[chunkNumber] = "etag"
+	return 10, nil
+}
+
+func (w *resumeChunkWriter) Close(ctx context.Context) error { return nil }
+
+func (w *resumeChunkWriter) Abort(ctx context.Context) error {
+	w.f.aborted++
+	return nil
+}
+
+func (w *resumeChunkWriter) State() fs.ChunkWriterState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	parts := make(map[int]string, len(w.parts))
+	for k, v := range w.parts {
+		parts[k] = v
+	}
+	return fs.ChunkWriterState{UploadID: w.id, ChunkSize: w.chunkSize, Parts: parts}
+}
+
+func TestResumeState(t *testing.T) {
+	ctx := context.Background()
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+
+	f := newResumeFs(t)
+	t1 := fstest.Time("2001-02-03T04:05:06Z")
+	t2 := fstest.Time("2011-12-25T12:59:59Z")
+	src := object.NewStaticObjectInfo("file", t1, 100, true, nil, nil)
+	srcObj := &resumeSrc{ObjectInfo: src}
+
+	// Nothing to resume
+	rs := newResumeState(ctx, f, "file", srcObj)
+	_, writer, done := rs.open(ctx, f, "file", srcObj)
+	assert.Nil(t, writer)
+	assert.Nil(t, done)
+
+	// Save the state of a partial upload
+	_, writer, err := f.OpenChunkWriter(ctx, "file", src)
+	require.NoError(t, err)
+	_, err = writer.WriteChunk(ctx, 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, rs.save(writer.(fs.ChunkWriterStater)))
+
+	// A different destination doesn't resume it
+	other := newResumeState(ctx, f, "other", srcObj)
+	_, writer, _ = other.open(ctx, f, "other", srcObj)
+	assert.Nil(t, writer)
+
+	// Resume the upload
+	rs = newResumeState(ctx, f, "file", srcObj)
+	info, writer, done := rs.open(ctx, f, "file", srcObj)
+	require.NotNil(t, writer)
+	assert.Equal(t, int64(10), info.ChunkSize)
+	assert.Equal(t, map[int]string{1: "etag"}, done)
+	require.Len(t, f.resumed, 1)
+	assert.Equal(t, "new", f.resumed[0].UploadID)
+
+	// Changing the source aborts the old upload
+	changed := &resumeSrc{ObjectInfo: object.NewStaticObjectInfo("file", t2, 100, true, nil, nil)}
+	rs = newResumeState(ctx, f, "file", changed)
+	_, writer, _ = rs.open(ctx, f, "file", changed)
+	assert.Nil(t, writer)
+	assert.Equal(t, 1, f.aborted)
+
+	// The state was removed
+	_, err = rs.load()
+	assert.Error(t, err)
+}
+
+func TestResumeStateChunkSize(t *testing.T) {
+	ctx := context.Background()
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+
+	f := newResumeFs(t)
+	t1 := fstest.Time("2001-02-03T04:05:06Z")
+	srcObj := &resumeSrc{ObjectInfo: object.NewStaticObjectInfo("file", t1, 100, true, nil, nil)}
+	rs := newResumeState(ctx, f, "file", srcObj)
+	_, writer, err := f.OpenChunkWriter(ctx, "file", srcObj)
+	require.NoError(t, err)
+	_, err = writer.WriteChunk(ctx, 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, rs.save(writer.(fs.ChunkWriterStater)))
+
+	// Changing the chunk size aborts the old upload
+	f.chunkSize = 20
+	_, writer, _ = rs.open(ctx, f, "file", srcObj)
+	assert.Nil(t, writer)
+	assert.Equal(t, 1, f.aborted)
+	_, err = rs.load()
+	assert.Error(t, err)
+}
+
+func TestResumable(t *testing.T) {
+	assert.True(t, resumable(nil))
+	assert.True(t, resumable(context.Canceled))
+	assert.True(t, resumable(fmt.Errorf("upload: %w", io.ErrUnexpectedEOF)))
+	assert.True(t, resumable(fserrors.RetryErrorf("try again")))
+	assert.False(t, resumable(errors.New("access denied")))
+	assert.False(t, resumable(fserrors.NoLowLevelRetryError(io.EOF)))
+}
+
+// resumeSrc makes an ObjectInfo into an Object for use as a source
+type resumeSrc struct {
+	fs.ObjectInfo
+}
+
+func (o *resumeSrc) SetModTime(ctx context.Context, t time.Time) error { return nil }
+
+func (o *resumeSrc) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	return nil, fs.ErrorNotImplemented
+}
+
+func (o *resumeSrc) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return fs.ErrorNotImplemented
+}
+
+func (o *resumeSrc) Remove(ctx context.Context) error { return nil }
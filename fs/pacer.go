@@ -15,6 +15,13 @@ type Pacer struct {
 	*pacer.Pacer
 }
 
+// pacerTracer records the waits and retries of the pacer with
+// PacerWait and PacerRetry
+type pacerTracer struct{}
+
+func (pacerTracer) Wait(ctx context.Context, wait time.Duration)  { PacerWait(ctx, wait) }
+func (pacerTracer) Retry(ctx context.Context, try int, err error) { PacerRetry(ctx, try, err) }
+
 type logCalculator struct {
 	pacer.Calculator
 }
@@ -30,6 +37,8 @@ func NewPacer(ctx context.Context, c pacer.Calculator) *Pacer {
 			pacer.MaxConnectionsOption(maxConnections),
 			pacer.RetriesOption(retries),
 			pacer.CalculatorOption(c),
+			pacer.NameOption(RemoteName(ctx)),
+			pacer.TracerOption(pacerTracer{}),
 		),
 	}
 	p.SetCalculator(c)
@@ -17,6 +17,8 @@ import (
 	"github.com/rclone/rclone/fs/cache"
 	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/fs/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Fill in these to avoid circular dependencies
@@ -101,6 +103,13 @@ func (job *Job) OnFinish(fn func()) func() {
 
 // run the job until completion writing the return status
 func (job *Job) run(ctx context.Context, fn rc.Func, in rc.Params) {
+	ctx, span := tracing.StartJob(ctx, "rc job",
+		attribute.Int64("rclone.job.id", job.ID),
+		attribute.String("rclone.job.group", job.Group),
+	)
+	defer func() {
+		tracing.End(span, job.realErr)
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			job.finish(nil, fmt.Errorf("panic received: %v \n%s", r, string(debug.Stack())))
@@ -230,6 +239,7 @@ func getConfig(ctx context.Context, in rc.Params) (context.Context, error) {
 		return ctx, err
 	}
 	delete(in, "_config") // remove the parameter
+	ctx = accounting.WithLimiter(ctx, "rc job")
 	return ctx, nil
 }
 
@@ -83,6 +83,11 @@ var OptionsInfo = fs.Options{{
 	Default: fs.Duration(10 * time.Second),
 	Help:    "Interval to check for expired async jobs",
 	Groups:  "RC",
+}, {
+	Name:    "rc_schedule",
+	Default: "",
+	Help:    "Path to a file of rc commands to run on a schedule (rcd only)",
+	Groups:  "RC",
 }, {
 	Name:    "metrics_addr",
 	Default: []string{},
@@ -122,6 +127,7 @@ type Options struct {
 	MetricsTemplate     libhttp.TemplateConfig `config:"metrics"`
 	JobExpireDuration   fs.Duration            `config:"rc_job_expire_duration"`
 	JobExpireInterval   fs.Duration            `config:"rc_job_expire_interval"`
+	Schedule            string                 `config:"rc_schedule"` // file of commands to run on a schedule
 }
 
 // Opt is the default values used for Options
@@ -13,6 +13,7 @@ import (
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fs/rc/jobs"
 	libhttp "github.com/rclone/rclone/lib/http"
+	"github.com/rclone/rclone/lib/pacer"
 )
 
 const path = "/metrics"
@@ -29,6 +30,18 @@ func init() {
 	}
 	fshttp.DefaultMetrics = m
 
+	bm := accounting.NewBackendMetrics("rclone")
+	for _, c := range bm.Collectors() {
+		prometheus.MustRegister(c)
+	}
+	accounting.DefaultBackendMetrics = bm
+
+	pm := pacer.NewMetrics("rclone")
+	for _, c := range pm.Collectors() {
+		prometheus.MustRegister(c)
+	}
+	pacer.DefaultMetrics = pm
+
 	promHandlerFunc = promhttp.Handler().ServeHTTP
 }
 
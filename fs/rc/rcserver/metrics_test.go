@@ -8,8 +8,10 @@ import (
 	"testing"
 
 	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/config/configfile"
+	"github.com/rclone/rclone/fs/list"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +53,30 @@ func TestMetrics(t *testing.T) {
 	testMetricsServer(t, tests, &opt)
 }
 
+func TestBackendMetrics(t *testing.T) {
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+	_, err = list.DirSorted(ctx, f, false, "")
+	require.NoError(t, err)
+
+	tests := []testRun{{
+		Name:     "Backend Calls Metric",
+		URL:      "metrics",
+		Method:   "GET",
+		Status:   http.StatusOK,
+		Contains: regexp.MustCompile(`rclone_backend_calls_total\{operation="list",remote="local"\} [1-9]`),
+	}, {
+		Name:     "Backend Duration Metric",
+		URL:      "metrics",
+		Method:   "GET",
+		Status:   http.StatusOK,
+		Contains: regexp.MustCompile(`rclone_backend_call_duration_seconds_count\{operation="list",remote="local"\} [1-9]`),
+	}}
+	opt := newMetricsTestOpt()
+	testMetricsServer(t, tests, &opt)
+}
+
 func makeMetricsTestCases(stats *accounting.StatsInfo) (tests []testRun) {
 	tests = []testRun{{
 		Name:     "Bytes Transferred Metric",
@@ -0,0 +1,237 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed cron expression
+//
+// This understands the standard 5 field format
+//
+//	minute hour day-of-month month day-of-week
+//
+// where each field can be `*`, a number, a range `a-b`, a step `*/n`
+// or `a-b/n`, or a comma separated list of these. Months and days of
+// the week can also be given by their three letter English names.
+//
+// As in Vixie cron, if both day-of-month and day-of-week are
+// restricted then a time matches if either of them matches.
+//
+// The descriptors @yearly, @annually, @monthly, @weekly, @daily,
+// @midnight and @hourly are accepted, as is `@every <duration>` to run
+// at a fixed interval.
+type Cron struct {
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	domStar  bool          // day of month started with *
+	dowStar  bool          // day of week started with *
+	interval time.Duration // set for @every
+	text     string        // the original expression
+}
+
+// cronField describes the limits of a cron field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string // names for the values starting from min
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+// cronDescriptors are the @ shortcuts for common schedules
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression
+func ParseCron(text string) (*Cron, error) {
+	text = strings.TrimSpace(text)
+	c := &Cron{text: text}
+	if rest, ok := strings.CutPrefix(text, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("bad @every interval: %w", err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("@every interval must be at least 1s: %v", interval)
+		}
+		c.interval = interval
+		return c, nil
+	}
+	if expanded, ok := cronDescriptors[strings.ToLower(text)]; ok {
+		text = expanded
+	} else if strings.HasPrefix(text, "@") {
+		return nil, fmt.Errorf("unknown cron descriptor %q", text)
+	}
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields but has %d", text, len(fields))
+	}
+	var err error
+	if c.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if c.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if c.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if c.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if c.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// 7 is Sunday as well as 0
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	// As in Vixie cron, a field starting with * (eg */2) doesn't
+	// restrict the day, so both day fields must match
+	c.domStar = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	c.dowStar = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+	return c, nil
+}
+
+// String returns the original expression
+func (c *Cron) String() string {
+	return c.text
+}
+
+// value parses a single number or name in the field
+func (cf *cronField) value(s string) (int, error) {
+	for i, name := range cf.names {
+		if strings.EqualFold(s, name) {
+			return cf.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad %s %q", cf.name, s)
+	}
+	if n < cf.min || n > cf.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", cf.name, n, cf.min, cf.max)
+	}
+	return n, nil
+}
+
+// parse parses the field into a bitmap of the values it matches
+func (cf *cronField) parse(field string) (set uint64, err error) {
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("bad step %q in %s", stepPart, cf.name)
+			}
+		}
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = cf.min, cf.max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			if lo, err = cf.value(loPart); err != nil {
+				return 0, err
+			}
+			if hi, err = cf.value(hiPart); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("bad %s range %q", cf.name, rangePart)
+			}
+		default:
+			if lo, err = cf.value(rangePart); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				hi = cf.max
+			}
+		}
+		for i := lo; i <= hi; i += step {
+			set |= 1 << i
+		}
+	}
+	if set == 0 {
+		return 0, errors.New("empty " + cf.name)
+	}
+	return set, nil
+}
+
+// dayMatches returns true if the day of t matches the expression
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<t.Day()) != 0
+	dowMatch := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// maxSearch is how far ahead Next looks before giving up
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first time after t which matches the expression,
+// or the zero time if there isn't one.
+func (c *Cron) Next(t time.Time) time.Time {
+	if c.interval > 0 {
+		return t.Add(c.interval)
+	}
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		if c.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<t.Minute()) == 0 {
+			// skip straight to the next matching minute in this hour if there is one
+			next := c.minute >> (t.Minute() + 1)
+			if next == 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(next)+1) * time.Minute)
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
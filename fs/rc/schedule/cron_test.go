@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 * ",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"* * * foo *",
+		"@fortnightly",
+		"@every potato",
+		"@every 10ms",
+	} {
+		_, err := ParseCron(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Saturday
+	start := time.Date(2025, 3, 15, 10, 30, 20, 0, time.UTC)
+	for _, test := range []struct {
+		text string
+		want string
+	}{
+		{"* * * * *", "2025-03-15 10:31"},
+		{"30 * * * *", "2025-03-15 11:30"},
+		{"*/15 * * * *", "2025-03-15 10:45"},
+		{"5,50 * * * *", "2025-03-15 10:50"},
+		{"10-20/5 * * * *", "2025-03-15 11:10"},
+		{"0 3 * * *", "2025-03-16 03:00"},
+		{"@daily", "2025-03-16 00:00"},
+		{"@hourly", "2025-03-15 11:00"},
+		{"@weekly", "2025-03-16 00:00"},
+		{"@monthly", "2025-04-01 00:00"},
+		{"@yearly", "2026-01-01 00:00"},
+		{"0 9 * * mon-fri", "2025-03-17 09:00"},
+		{"0 9 * * 7", "2025-03-16 09:00"},
+		{"0 0 1 jan,jul *", "2025-07-01 00:00"},
+		{"0 0 31 * *", "2025-03-31 00:00"},
+		{"0 0 29 2 *", "2028-02-29 00:00"},
+		// day of month or day of week as both are restricted
+		{"0 0 20 * mon", "2025-03-17 00:00"},
+		{"0 0 16 * fri", "2025-03-16 00:00"},
+		// day of month and day of week as */2 isn't a restriction
+		{"0 0 */2 * tue", "2025-03-25 00:00"},
+		{"0 0 20 * */2", "2025-03-20 00:00"},
+	} {
+		c, err := ParseCron(test.text)
+		require.NoError(t, err, test.text)
+		assert.Equal(t, test.want, c.Next(start).Format("2006-01-02 15:04"), test.text)
+		assert.Equal(t, test.text, c.String())
+	}
+}
+
+func TestCronEvery(t *testing.T) {
+	c, err := ParseCron("@every 1h30m")
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 15, 10, 30, 20, 0, time.UTC)
+	assert.Equal(t, start.Add(90*time.Minute), c.Next(start))
+}
+
+func TestCronNever(t *testing.T) {
+	c, err := ParseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(time.Now()).IsZero())
+}
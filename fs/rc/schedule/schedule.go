@@ -0,0 +1,436 @@
+// Package schedule runs rc commands on a cron like schedule.
+//
+// The schedule is read from the file passed to --rc-schedule when
+// running rclone rcd. Each run is started as an rc job so it can be
+// monitored and stopped with the job/* calls, and the results of the
+// last run of each entry are saved in the cache directory so they
+// survive restarts.
+package schedule
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/fs/rc/jobs"
+	"gopkg.in/yaml.v3"
+)
+
+// stateDir is the directory in the cache dir the results are saved in
+const stateDir = "schedule"
+
+// Entry is a command to run on a schedule as read from the schedule file
+type Entry struct {
+	Name     string    `yaml:"name" json:"name"`         // unique name of the entry
+	Schedule string    `yaml:"schedule" json:"schedule"` // cron expression
+	Command  string    `yaml:"command" json:"command"`   // rc command to run, e.g. sync/sync
+	Params   rc.Params `yaml:"params" json:"-"`          // parameters for the command
+}
+
+// Result is the outcome of a run of an Entry
+type Result struct {
+	JobID     int64     `json:"jobid"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Duration  float64   `json:"duration"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error"`
+}
+
+// errRunning is returned when an entry is started while its previous
+// run is still going
+var errRunning = errors.New("previous run is still running")
+
+// entry is an Entry being scheduled
+type entry struct {
+	Entry
+	cron    *Cron
+	fn      rc.Func
+	mu      sync.Mutex
+	next    time.Time // time of the next scheduled run
+	running *jobs.Job // the job if running
+	last    *Result   // result of the last run if any
+	skipped int       // number of runs skipped as the previous run was still going
+}
+
+// Scheduler runs the entries in a schedule file
+type Scheduler struct {
+	statePath string
+	entries   []*entry
+	byName    map[string]*entry
+	saveMu    sync.Mutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Load reads the schedule file at path and the saved results for it
+//
+// The schedule file is YAML (or JSON) containing a list of entries
+// with the fields in Entry.
+func Load(path string) (*Scheduler, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+	var entries []Entry
+	err = yaml.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %q: %w", path, err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum([]byte(absPath))
+	s := &Scheduler{
+		statePath: filepath.Join(config.GetCacheDir(), stateDir, hex.EncodeToString(sum[:])+".json"),
+		byName:    make(map[string]*entry, len(entries)),
+	}
+	for i, e := range entries {
+		if e.Name == "" {
+			return nil, fmt.Errorf("schedule entry %d: name missing", i+1)
+		}
+		if _, found := s.byName[e.Name]; found {
+			return nil, fmt.Errorf("schedule entry %q: duplicate name", e.Name)
+		}
+		cron, err := ParseCron(e.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %q: %w", e.Name, err)
+		}
+		call := rc.Calls.Get(e.Command)
+		if call == nil {
+			return nil, fmt.Errorf("schedule entry %q: unknown command %q", e.Name, e.Command)
+		}
+		if call.NeedsRequest || call.NeedsResponse {
+			return nil, fmt.Errorf("schedule entry %q: command %q can't be scheduled", e.Name, e.Command)
+		}
+		if e.Params == nil {
+			e.Params = rc.Params{}
+		}
+		se := &entry{
+			Entry: e,
+			cron:  cron,
+			fn:    call.Fn,
+		}
+		s.entries = append(s.entries, se)
+		s.byName[e.Name] = se
+	}
+	s.loadResults()
+	return s, nil
+}
+
+// loadResults reads the results of the last runs
+func (s *Scheduler) loadResults() {
+	data, err := os.ReadFile(s.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		fs.Logf(nil, "schedule: failed to read previous results: %v", err)
+		return
+	}
+	var results map[string]*Result
+	err = json.Unmarshal(data, &results)
+	if err != nil {
+		fs.Logf(nil, "schedule: ignoring corrupted results file %q: %v", s.statePath, err)
+		return
+	}
+	for name, result := range results {
+		if e := s.byName[name]; e != nil {
+			e.last = result
+		}
+	}
+}
+
+// saveResults writes the results of the last runs
+func (s *Scheduler) saveResults() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	results := make(map[string]*Result, len(s.entries))
+	for _, e := range s.entries {
+		e.mu.Lock()
+		if e.last != nil {
+			results[e.Name] = e.last
+		}
+		e.mu.Unlock()
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(s.statePath), 0700)
+	if err != nil {
+		return err
+	}
+	tmpPath := s.statePath + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.statePath)
+}
+
+// run starts e as an rc job returning its ID
+//
+// It returns errRunning if the previous run of e hasn't finished.
+func (s *Scheduler) run(e *entry) (jobID int64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.running != nil {
+		return e.running.ID, errRunning
+	}
+	in := e.Params.Copy()
+	in["_async"] = true
+	if _, found := in["_group"]; !found {
+		in["_group"] = "schedule/" + e.Name
+	}
+	startTime := time.Now()
+	job, _, err := jobs.NewJob(context.Background(), e.fn, in)
+	if err != nil {
+		// The job failed to start so record that as the result
+		e.last = &Result{
+			StartTime: startTime,
+			EndTime:   time.Now(),
+			Error:     err.Error(),
+		}
+		go s.save()
+		return 0, err
+	}
+	fs.Infof(nil, "schedule: started %q as job %d", e.Name, job.ID)
+	e.running = job
+	job.OnFinish(func() {
+		s.finished(e, job)
+	})
+	return job.ID, nil
+}
+
+// finished records the result of job which is a run of e
+func (s *Scheduler) finished(e *entry, job *jobs.Job) {
+	result := &Result{
+		JobID:     job.ID,
+		StartTime: job.StartTime,
+		EndTime:   job.EndTime,
+		Duration:  job.Duration,
+		Success:   job.Success,
+		Error:     job.Error,
+	}
+	if result.Success {
+		fs.Infof(nil, "schedule: %q (job %d) finished successfully in %.1fs", e.Name, job.ID, job.Duration)
+	} else {
+		fs.Errorf(nil, "schedule: %q (job %d) failed: %s", e.Name, job.ID, job.Error)
+	}
+	e.mu.Lock()
+	e.running = nil
+	e.last = result
+	e.mu.Unlock()
+	s.save()
+}
+
+// save writes the results logging any errors
+func (s *Scheduler) save() {
+	if err := s.saveResults(); err != nil {
+		fs.Errorf(nil, "schedule: failed to save results: %v", err)
+	}
+}
+
+// loop runs e at the times in its schedule until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.wg.Done()
+	for {
+		next := e.cron.Next(time.Now())
+		e.mu.Lock()
+		e.next = next
+		e.mu.Unlock()
+		if next.IsZero() {
+			fs.Logf(nil, "schedule: %q will never run again", e.Name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		jobID, err := s.run(e)
+		if errors.Is(err, errRunning) {
+			e.mu.Lock()
+			e.skipped++
+			e.mu.Unlock()
+			fs.Logf(nil, "schedule: skipping %q as the previous run (job %d) is still running", e.Name, jobID)
+		} else if err != nil {
+			fs.Errorf(nil, "schedule: failed to start %q: %v", e.Name, err)
+		}
+	}
+}
+
+var (
+	activeMu sync.Mutex
+	active   *Scheduler
+)
+
+// Start loads the schedule file at path and starts running it
+//
+// Only one schedule can be running at once.
+func Start(ctx context.Context, path string) (*Scheduler, error) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if active != nil {
+		return nil, errors.New("schedule already running")
+	}
+	s, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.loop(ctx, e)
+	}
+	active = s
+	fs.Infof(nil, "schedule: loaded %d entries from %q", len(s.entries), path)
+	return s, nil
+}
+
+// Stop stops scheduling new runs
+//
+// Runs which have already started are left to finish.
+func (s *Scheduler) Stop() {
+	activeMu.Lock()
+	if active == s {
+		active = nil
+	}
+	activeMu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// getActive returns the running Scheduler or an error
+func getActive() (*Scheduler, error) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if active == nil {
+		return nil, errors.New("no schedule loaded - use --rc-schedule with rclone rcd")
+	}
+	return active, nil
+}
+
+func init() {
+	rc.Add(rc.Call{
+		Path:  "schedule/list",
+		Fn:    rcList,
+		Title: "List the scheduled commands and the results of their last runs",
+		Help: `This lists the entries in the schedule loaded with --rc-schedule.
+
+Parameters: None.
+
+Results:
+
+- entries - array of entries in the order they are in the schedule file
+    - name - name of the entry
+    - schedule - cron expression
+    - command - the rc command being run
+    - next - time of the next scheduled run
+    - running - boolean true if the entry is running now
+    - jobid - id of the running job if running
+    - skipped - number of runs skipped as the previous run hadn't finished
+    - last - result of the last run if any
+        - jobid - id of the job
+        - startTime - time the run started
+        - endTime - time the run finished
+        - duration - time in seconds that the run took
+        - success - boolean - true for success false otherwise
+        - error - error from the run or empty string for no error
+
+The parameters of the commands aren't returned as they may contain
+credentials.
+`,
+	})
+}
+
+// List the entries in the schedule
+func rcList(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	s, err := getActive()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]rc.Params, 0, len(s.entries))
+	for _, e := range s.entries {
+		e.mu.Lock()
+		item := rc.Params{
+			"name":     e.Name,
+			"schedule": e.Schedule,
+			"command":  e.Command,
+			"next":     e.next,
+			"running":  e.running != nil,
+			"skipped":  e.skipped,
+		}
+		if e.running != nil {
+			item["jobid"] = e.running.ID
+		}
+		if e.last != nil {
+			last := *e.last
+			item["last"] = &last
+		}
+		e.mu.Unlock()
+		entries = append(entries, item)
+	}
+	return rc.Params{"entries": entries}, nil
+}
+
+func init() {
+	rc.Add(rc.Call{
+		Path:         "schedule/run",
+		AuthRequired: true,
+		Fn:           rcRun,
+		Title:        "Run a scheduled command now",
+		Help: `This starts the named entry in the schedule loaded with
+--rc-schedule straight away as an async job. It doesn't change when
+the entry is next scheduled to run.
+
+Parameters:
+
+- name - name of the entry to run
+
+Results:
+
+- jobid - id of the job started
+
+It is an error to run an entry whose previous run hasn't finished yet.
+`,
+	})
+}
+
+// Run an entry in the schedule
+func rcRun(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	name, err := in.GetString("name")
+	if err != nil {
+		return nil, err
+	}
+	s, err := getActive()
+	if err != nil {
+		return nil, err
+	}
+	e := s.byName[name]
+	if e == nil {
+		return nil, rc.NewErrParamInvalid(fmt.Errorf("no schedule entry called %q", name))
+	}
+	jobID, err := s.run(e)
+	if errors.Is(err, errRunning) {
+		return nil, fmt.Errorf("can't run %q: %w (job %d)", name, err, jobID)
+	} else if err != nil {
+		return nil, err
+	}
+	return rc.Params{"jobid": jobID}, nil
+}
@@ -0,0 +1,178 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// block is closed to let the schedule/test-block calls finish
+var block = make(chan struct{})
+
+func init() {
+	rc.Add(rc.Call{
+		Path: "schedule/test-block",
+		Fn: func(ctx context.Context, in rc.Params) (rc.Params, error) {
+			select {
+			case <-block:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return nil, nil
+		},
+	})
+	rc.Add(rc.Call{
+		Path: "schedule/test-error",
+		Fn: func(ctx context.Context, in rc.Params) (rc.Params, error) {
+			return nil, errors.New("test error")
+		},
+	})
+}
+
+const testSchedule = `
+- name: noop
+  schedule: "@every 1h"
+  command: rc/noop
+  params:
+    potato: 1
+- name: block
+  schedule: "0 3 * * *"
+  command: schedule/test-block
+- name: error
+  schedule: "@daily"
+  command: schedule/test-error
+`
+
+// writeSchedule writes the schedule to a file returning its path
+func writeSchedule(t *testing.T, schedule string) string {
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	t.Cleanup(func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	})
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(schedule), 0600))
+	return path
+}
+
+// waitFinished waits for the entry called name to finish running
+func waitFinished(t *testing.T, s *Scheduler, name string) *Result {
+	e := s.byName[name]
+	for range 500 {
+		e.mu.Lock()
+		running, last := e.running, e.last
+		e.mu.Unlock()
+		if running == nil && last != nil {
+			return last
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %q to finish", name)
+	return nil
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, test := range []struct {
+		schedule string
+		want     string
+	}{
+		{"potato", "failed to parse"},
+		{"- schedule: '@daily'\n  command: rc/noop\n", "name missing"},
+		{"- name: a\n  schedule: '@daily'\n  command: rc/noop\n- name: a\n  schedule: '@daily'\n  command: rc/noop\n", "duplicate name"},
+		{"- name: a\n  schedule: '@fortnightly'\n  command: rc/noop\n", "unknown cron descriptor"},
+		{"- name: a\n  schedule: '@daily'\n  command: potato/noop\n", "unknown command"},
+	} {
+		_, err := Load(writeSchedule(t, test.schedule))
+		assert.ErrorContains(t, err, test.want, test.schedule)
+	}
+	_, err := Load(filepath.Join(t.TempDir(), "notfound.yaml"))
+	assert.ErrorContains(t, err, "failed to read schedule")
+}
+
+func TestSchedule(t *testing.T) {
+	ctx := context.Background()
+	path := writeSchedule(t, testSchedule)
+
+	// The rc calls fail if the schedule isn't running
+	_, err := rcList(ctx, rc.Params{})
+	assert.ErrorContains(t, err, "no schedule loaded")
+
+	s, err := Start(ctx, path)
+	require.NoError(t, err)
+	_, err = Start(ctx, path)
+	assert.ErrorContains(t, err, "already running")
+
+	out, err := rcList(ctx, rc.Params{})
+	require.NoError(t, err)
+	entries := out["entries"].([]rc.Params)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "noop", entries[0]["name"])
+	assert.Equal(t, "rc/noop", entries[0]["command"])
+	assert.Equal(t, false, entries[0]["running"])
+	assert.Nil(t, entries[0]["last"])
+
+	// Run an entry now
+	_, err = rcRun(ctx, rc.Params{"name": "potato"})
+	assert.ErrorContains(t, err, "no schedule entry")
+	out, err = rcRun(ctx, rc.Params{"name": "noop"})
+	require.NoError(t, err)
+	jobID := out["jobid"].(int64)
+	result := waitFinished(t, s, "noop")
+	assert.Equal(t, jobID, result.JobID)
+	assert.True(t, result.Success)
+
+	// Failures are recorded
+	_, err = rcRun(ctx, rc.Params{"name": "error"})
+	require.NoError(t, err)
+	result = waitFinished(t, s, "error")
+	assert.False(t, result.Success)
+	assert.Equal(t, "test error", result.Error)
+
+	// Overlapping runs are prevented
+	out, err = rcRun(ctx, rc.Params{"name": "block"})
+	require.NoError(t, err)
+	blockID := out["jobid"].(int64)
+	_, err = rcRun(ctx, rc.Params{"name": "block"})
+	assert.ErrorContains(t, err, "still running")
+	out, err = rcList(ctx, rc.Params{})
+	require.NoError(t, err)
+	entries = out["entries"].([]rc.Params)
+	assert.Equal(t, true, entries[1]["running"])
+	assert.Equal(t, blockID, entries[1]["jobid"])
+	close(block)
+	result = waitFinished(t, s, "block")
+	assert.True(t, result.Success)
+
+	s.Stop()
+	_, err = rcList(ctx, rc.Params{})
+	assert.ErrorContains(t, err, "no schedule loaded")
+
+	// The results are loaded again on restart
+	s, err = Load(path)
+	require.NoError(t, err)
+	require.NotNil(t, s.byName["noop"].last)
+	assert.Equal(t, jobID, s.byName["noop"].last.JobID)
+	require.NotNil(t, s.byName["error"].last)
+	assert.Equal(t, "test error", s.byName["error"].last.Error)
+}
+
+func TestScheduleLoop(t *testing.T) {
+	path := writeSchedule(t, "- name: noop\n  schedule: '@every 1s'\n  command: rc/noop\n")
+	s, err := Start(context.Background(), path)
+	require.NoError(t, err)
+	defer s.Stop()
+	result := waitFinished(t, s, "noop")
+	assert.True(t, result.Success)
+	e := s.byName["noop"]
+	e.mu.Lock()
+	assert.True(t, e.next.After(result.StartTime))
+	e.mu.Unlock()
+}
@@ -0,0 +1,278 @@
+package sync
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/watch"
+)
+
+// incrementalDir is the directory in the cache dir the change tokens
+// are saved in
+const incrementalDir = "incremental"
+
+// incrementalFile is the format of the file the change token is saved in
+type incrementalFile struct {
+	Source      string            `json:"source"`          // the source of the sync
+	Destination string            `json:"destination"`     // the destination of the sync
+	Token       string            `json:"token"`           // change token of the source after the last sync
+	Paths       map[string]string `json:"paths,omitempty"` // ID => path of the source entries after the last sync
+}
+
+// incrementalState saves the change token of the source between runs
+// of --incremental
+type incrementalState struct {
+	path string
+	src  string
+	dst  string
+}
+
+// newIncrementalState makes an incrementalState for syncing fsrc to fdst
+//
+// The filters are part of the key so changing them starts again with
+// a full sync.
+func newIncrementalState(ctx context.Context, fdst, fsrc fs.Fs) *incrementalState {
+	src, dst := fs.ConfigString(fsrc), fs.ConfigString(fdst)
+	fi := filter.GetConfig(ctx)
+	sum := md5.Sum(fmt.Appendf(nil, "%s\x00%s\x00%v", src, dst, fi.Opt))
+	return &incrementalState{
+		path: filepath.Join(config.GetCacheDir(), incrementalDir, hex.EncodeToString(sum[:])+".json"),
+		src:  src,
+		dst:  dst,
+	}
+}
+
+// load reads the saved change token and the paths of the source
+// entries by ID
+func (is *incrementalState) load() (token string, paths map[string]string, err error) {
+	data, err := os.ReadFile(is.path)
+	if err != nil {
+		return "", nil, err
+	}
+	var file incrementalFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return "", nil, fmt.Errorf("corrupted incremental state file %q: %w", is.path, err)
+	}
+	if file.Source != is.src || file.Destination != is.dst {
+		return "", nil, fmt.Errorf("incremental state file %q is for %q to %q", is.path, file.Source, file.Destination)
+	}
+	return file.Token, file.Paths, nil
+}
+
+// save writes the change token and the paths of the source entries by ID
+func (is *incrementalState) save(token string, paths map[string]string) error {
+	data, err := json.Marshal(&incrementalFile{
+		Source:      is.src,
+		Destination: is.dst,
+		Token:       token,
+		Paths:       paths,
+	})
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(is.path), 0700)
+	if err != nil {
+		return err
+	}
+	tmpPath := is.path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, is.path)
+}
+
+// idPaths records the paths of the source entries which have IDs
+//
+// Some backends can't report the old path of a renamed or moved
+// entry, so these are used to find it from the entry's ID next time.
+type idPaths struct {
+	mu    sync.Mutex
+	paths map[string]string // ID => path
+}
+
+// newIDPaths makes an empty idPaths
+func newIDPaths() *idPaths {
+	return &idPaths{paths: map[string]string{}}
+}
+
+// entryID returns the ID of entry or "" if it doesn't have one
+func entryID(entry fs.DirEntry) string {
+	if do, ok := entry.(fs.IDer); ok {
+		return do.ID()
+	}
+	if o, ok := entry.(fs.Object); ok {
+		if do, ok := fs.UnWrapObject(o).(fs.IDer); ok {
+			return do.ID()
+		}
+	}
+	return ""
+}
+
+// record notes the path of entry if it has an ID
+func (ip *idPaths) record(entry fs.DirEntry) {
+	if ip == nil {
+		return
+	}
+	id := entryID(entry)
+	if id == "" {
+		return
+	}
+	ip.mu.Lock()
+	ip.paths[id] = entry.Remote()
+	ip.mu.Unlock()
+}
+
+// addOldPaths adds the old paths of the entries in changes which
+// have been renamed or moved since the paths were saved.
+//
+// It lists the directory containing each changed path and looks up
+// the IDs found in paths.
+func addOldPaths(ctx context.Context, fsrc fs.Fs, changes *watch.ChangeSet, paths map[string]string) error {
+	if len(paths) == 0 || changes.IncludePath("", false) {
+		return nil
+	}
+	dirs := map[string]struct{}{}
+	for _, remote := range changes.Paths() {
+		dir := path.Dir(remote)
+		if dir == "." {
+			dir = ""
+		}
+		dirs[dir] = struct{}{}
+	}
+	for dir := range dirs {
+		entries, err := fsrc.List(ctx, dir)
+		if errors.Is(err, fs.ErrorDirNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to list %q to find renamed entries: %w", dir, err)
+		}
+		for _, entry := range entries {
+			oldPath, found := paths[entryID(entry)]
+			if !found || oldPath == entry.Remote() {
+				continue
+			}
+			entryType := fs.EntryObject
+			if _, isDir := entry.(fs.Directory); isDir {
+				entryType = fs.EntryDirectory
+			}
+			fs.Debugf(entry, "Renamed from %q since the last sync", oldPath)
+			changes.Add(oldPath, entryType)
+		}
+	}
+	return nil
+}
+
+// updatePaths returns the saved paths updated with the paths recorded
+// in an incremental sync of changes.
+//
+// Saved entries in the changed paths which weren't seen this time
+// have been deleted so are removed.
+func updatePaths(paths map[string]string, changes *watch.ChangeSet, seen *idPaths) map[string]string {
+	newPaths := make(map[string]string, len(paths))
+	for id, remote := range paths {
+		if !changes.IncludePath(remote, false) {
+			newPaths[id] = remote
+		}
+	}
+	for id, remote := range seen.paths {
+		newPaths[id] = remote
+	}
+	return newPaths
+}
+
+// skipUnchanged returns true if this is an incremental sync and
+// entry hasn't changed
+func (s *syncCopyMove) skipUnchanged(entry fs.DirEntry) bool {
+	if s.changes == nil || s.changes.Include(entry) {
+		return false
+	}
+	if _, isDir := entry.(fs.Directory); !isDir {
+		s.markParentNotEmpty(entry)
+	}
+	return true
+}
+
+// runIncremental calls fn to sync fsrc to fdst with the paths which
+// have changed since the last incremental sync.
+//
+// If there was no previous sync or the change token has expired then
+// fn is called with nil changes to do a full sync. fn should record
+// the source entries it sees in ids. The new change token is only
+// saved if fn succeeds.
+func runIncremental(ctx context.Context, fdst, fsrc fs.Fs, fn func(changes *watch.ChangeSet, ids *idPaths) error) error {
+	ci := fs.GetConfig(ctx)
+	changesSince := fsrc.Features().ChangesSince
+	if changesSince == nil {
+		fs.Logf(fsrc, "Doing a full sync as --incremental isn't supported by the source")
+		return fn(nil, nil)
+	}
+	state := newIncrementalState(ctx, fdst, fsrc)
+	token, paths, err := state.load()
+	if errors.Is(err, os.ErrNotExist) {
+		fs.Infof(fsrc, "Doing a full sync as there is no saved change token")
+	} else if err != nil {
+		fs.Logf(fsrc, "Doing a full sync: %v", err)
+	}
+	var (
+		changes  *watch.ChangeSet
+		newToken string
+	)
+	if token != "" {
+		changes = watch.NewChangeSet()
+		newToken, err = changesSince(ctx, token, changes.Add)
+		if errors.Is(err, fs.ErrorChangeTokenInvalid) {
+			fs.Logf(fsrc, "Doing a full sync as the saved change token is no longer valid")
+			changes = nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read changes from source: %w", err)
+		}
+	}
+	if changes == nil {
+		// Read the token before the sync so changes made during
+		// the sync are seen next time.
+		newToken, err = changesSince(ctx, "", nil)
+		if err != nil {
+			return fmt.Errorf("failed to read change token from source: %w", err)
+		}
+	} else if changes.Len() == 0 {
+		fs.Infof(fsrc, "No changes on the source since the last sync")
+	} else {
+		err = addOldPaths(ctx, fsrc, changes, paths)
+		if err != nil {
+			return err
+		}
+		fs.Infof(fsrc, "Syncing %d changed paths", changes.Len())
+	}
+	ids := newIDPaths()
+	if changes == nil || changes.Len() > 0 {
+		err = fn(changes, ids)
+		if err != nil {
+			return err
+		}
+	}
+	if ci.DryRun {
+		return nil
+	}
+	newPaths := ids.paths
+	if changes != nil {
+		newPaths = updatePaths(paths, changes, ids)
+	}
+	err = state.save(newToken, newPaths)
+	if err != nil {
+		return fmt.Errorf("failed to save change token: %w", err)
+	}
+	return nil
+}
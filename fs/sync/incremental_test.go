@@ -0,0 +1,196 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changesFs is an Fs which reports the changes it is told about
+type changesFs struct {
+	fs.Fs
+	features *fs.Features
+	token    int
+	changes  []string
+	invalid  bool
+	ids      map[string]string // path => ID of entries which have IDs
+}
+
+// idObject is an Object with an ID
+type idObject struct {
+	fs.Object
+	id string
+}
+
+func (o idObject) ID() string { return o.id }
+
+func newChangesFs(ctx context.Context, f fs.Fs) *changesFs {
+	cf := &changesFs{Fs: f}
+	cf.features = (&fs.Features{}).Fill(ctx, cf)
+	return cf
+}
+
+func (f *changesFs) Features() *fs.Features { return f.features }
+
+// List the directory giving IDs to the entries in f.ids
+func (f *changesFs) List(ctx context.Context, dir string) (fs.DirEntries, error) {
+	entries, err := f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		id, ok := f.ids[entry.Remote()]
+		if !ok {
+			continue
+		}
+		switch x := entry.(type) {
+		case fs.Object:
+			entries[i] = idObject{Object: x, id: id}
+		case fs.Directory:
+			entries[i] = fs.NewDirCopy(ctx, x).SetID(id)
+		}
+	}
+	return entries, nil
+}
+
+func (f *changesFs) ChangesSince(ctx context.Context, token string, notifyFunc func(string, fs.EntryType)) (string, error) {
+	if token != "" {
+		if f.invalid {
+			return "", fs.ErrorChangeTokenInvalid
+		}
+		for _, change := range f.changes {
+			notifyFunc(change, fs.EntryObject)
+		}
+	}
+	f.token++
+	f.changes = nil
+	f.invalid = false
+	return string(rune('a' + f.token)), nil
+}
+
+func TestSyncIncremental(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+	ci.Incremental = true
+	fsrc := newChangesFs(ctx, r.Flocal)
+
+	// The first sync is a full sync
+	file1 := r.WriteFile("dir/file1", "potato", t1)
+	file2 := r.WriteFile("file2", "carrot", t1)
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file1, file2)
+	assert.Equal(t, 1, fsrc.token)
+
+	// Only changed files are synced
+	file1 = r.WriteFile("dir/file1", "potato2", t2)
+	file3 := r.WriteFile("dir/file3", "beetroot", t2)
+	file4 := r.WriteFile("file4", "parsnip", t2)
+	fsrc.changes = []string{"dir/file1", "dir/file3"}
+	accounting.GlobalStats().ResetCounters()
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	assert.Equal(t, int64(2), accounting.GlobalStats().GetTransfers())
+	r.CheckRemoteItems(t, file1, file2, file3)
+	assert.Equal(t, 2, fsrc.token)
+
+	// Deleted files which are reported are deleted
+	r.CheckLocalItems(t, file1, file2, file3, file4)
+	require.NoError(t, os.RemoveAll(filepath.Join(r.LocalName, "dir")))
+	fsrc.changes = []string{"dir"}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file2)
+
+	// An invalid token does a full sync
+	fsrc.invalid = true
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file2, file4)
+	assert.Equal(t, 4, fsrc.token)
+
+	// A dry run reads the changes but doesn't save the new token
+	fsrc.changes = []string{"file2"}
+	ci.DryRun = true
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	token, _, err := newIncrementalState(ctx, r.Fremote, fsrc).load()
+	require.NoError(t, err)
+	assert.Equal(t, "e", token)
+}
+
+func TestSyncIncrementalRename(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+	ci.Incremental = true
+	fsrc := newChangesFs(ctx, r.Flocal)
+	fsrc.ids = map[string]string{
+		"dir1":       "id-dir1",
+		"dir1/file1": "id-file1",
+		"dir2":       "id-dir2",
+		"dir2/file2": "id-file2",
+	}
+
+	// The first sync is a full sync which records the IDs
+	file1 := r.WriteFile("dir1/file1", "potato", t1)
+	file2 := r.WriteFile("dir2/file2", "carrot", t1)
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file1, file2)
+
+	// A renamed file is reported with its new path only, like
+	// drive, box and onedrive do, but the old name is deleted
+	require.NoError(t, os.Rename(filepath.Join(r.LocalName, "dir1", "file1"), filepath.Join(r.LocalName, "dir1", "file1-renamed")))
+	delete(fsrc.ids, "dir1/file1")
+	fsrc.ids["dir1/file1-renamed"] = "id-file1"
+	file1.Path = "dir1/file1-renamed"
+	fsrc.changes = []string{"dir1/file1-renamed"}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file1, file2)
+
+	// The new path is saved so a move to another directory is found too
+	require.NoError(t, os.Rename(filepath.Join(r.LocalName, "dir1", "file1-renamed"), filepath.Join(r.LocalName, "dir2", "file1")))
+	delete(fsrc.ids, "dir1/file1-renamed")
+	fsrc.ids["dir2/file1"] = "id-file1"
+	file1.Path = "dir2/file1"
+	fsrc.changes = []string{"dir2/file1"}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file1, file2)
+
+	// A renamed directory is found from its ID
+	require.NoError(t, os.Rename(filepath.Join(r.LocalName, "dir2"), filepath.Join(r.LocalName, "dir3")))
+	fsrc.ids = map[string]string{
+		"dir1":       "id-dir1",
+		"dir3":       "id-dir2",
+		"dir3/file1": "id-file1",
+		"dir3/file2": "id-file2",
+	}
+	file1.Path = "dir3/file1"
+	file2.Path = "dir3/file2"
+	fsrc.changes = []string{"dir3"}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	r.CheckRemoteItems(t, file1, file2)
+
+	_, paths, err := newIncrementalState(ctx, r.Fremote, fsrc).load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"id-dir1":  "dir1",
+		"id-dir2":  "dir3",
+		"id-file1": "dir3/file1",
+		"id-file2": "dir3/file2",
+	}, paths)
+}
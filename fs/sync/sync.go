@@ -19,6 +19,7 @@ import (
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/march"
 	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/watch"
 	"github.com/rclone/rclone/lib/errcount"
 	"github.com/rclone/rclone/lib/transform"
 	"golang.org/x/sync/errgroup"
@@ -97,6 +98,8 @@ type syncCopyMove struct {
 	setDirModTimesMaxLevel int                    // max level of the directories to set
 	modifiedDirs           map[string]struct{}    // dirs with changed contents (if s.setDirModTimeAfter)
 	allowOverlap           bool                   // whether we allow src and dst to overlap (i.e. for convmv)
+	changes                *watch.ChangeSet       // if set only sync these paths (for --incremental and --watch)
+	ids                    *idPaths               // if set record the paths of the source entries by ID (for --incremental)
 }
 
 // For keeping track of delayed modtime sets
@@ -171,6 +174,7 @@ func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 	if deleteMode == fs.DeleteModeOff {
 		loggerOpt := operations.GetLoggerOpt(ctx)
 		loggerOpt.DeleteModeOff = true
+		loggerOpt.DoMove = DoMove
 		loggerOpt.LoggerFn = s.logger
 		ctx = operations.WithLoggerOpt(ctx, loggerOpt)
 	}
@@ -1031,6 +1035,9 @@ func (s *syncCopyMove) run() error {
 
 // DstOnly have an object which is in the destination only
 func (s *syncCopyMove) DstOnly(dst fs.DirEntry) (recurse bool) {
+	if s.skipUnchanged(dst) {
+		return false
+	}
 	if s.deleteMode == fs.DeleteModeOff {
 		if s.usingLogger {
 			switch x := dst.(type) {
@@ -1225,6 +1232,10 @@ func (s *syncCopyMove) setDelayedDirModTimes(ctx context.Context) error {
 
 // SrcOnly have an object which is in the source only
 func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
+	s.ids.record(src)
+	if s.skipUnchanged(src) {
+		return false
+	}
 	if s.deleteMode == fs.DeleteModeOnly {
 		return false
 	}
@@ -1274,6 +1285,10 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 
 // Match is called when src and dst are present, so sync src to dst
 func (s *syncCopyMove) Match(ctx context.Context, dst, src fs.DirEntry) (recurse bool) {
+	s.ids.record(src)
+	if s.skipUnchanged(src) {
+		return false
+	}
 	switch srcX := src.(type) {
 	case fs.Object:
 		s.markParentNotEmpty(src)
@@ -1345,6 +1360,20 @@ func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 	if deleteMode != fs.DeleteModeOff && DoMove {
 		return fserrors.FatalError(errors.New("can't delete and move at the same time"))
 	}
+	if ci.Incremental && !allowOverlap {
+		return runIncremental(ctx, fdst, fsrc, func(changes *watch.ChangeSet, ids *idPaths) error {
+			return runSyncCopyMoveChanges(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs, copyEmptySrcDirs, allowOverlap, changes, ids)
+		})
+	}
+	return runSyncCopyMoveChanges(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs, copyEmptySrcDirs, allowOverlap, nil, nil)
+}
+
+// runSyncCopyMoveChanges does the work for runSyncCopyMove
+//
+// If changes is set then only those paths are synced. If ids is set
+// the paths of the source entries are recorded in it.
+func runSyncCopyMoveChanges(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool, copyEmptySrcDirs bool, allowOverlap bool, changes *watch.ChangeSet, ids *idPaths) error {
+	ci := fs.GetConfig(ctx)
 	// Run an extra pass to delete only
 	if deleteMode == fs.DeleteModeBefore {
 		if ci.TrackRenames {
@@ -1355,6 +1384,8 @@ func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 		if err != nil {
 			return err
 		}
+		do.changes = changes
+		do.ids = ids
 		err = do.run()
 		if err != nil {
 			return err
@@ -1366,6 +1397,8 @@ func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 	if err != nil {
 		return err
 	}
+	do.changes = changes
+	do.ids = ids
 	return do.run()
 }
 
@@ -1375,6 +1408,14 @@ func Sync(ctx context.Context, fdst, fsrc fs.Fs, copyEmptySrcDirs bool) error {
 	return runSyncCopyMove(ctx, fdst, fsrc, ci.DeleteMode, false, false, copyEmptySrcDirs, false)
 }
 
+// SyncChanges syncs the paths in changes from fsrc into fdst
+//
+// If changes is nil then everything is synced, like Sync.
+func SyncChanges(ctx context.Context, fdst, fsrc fs.Fs, copyEmptySrcDirs bool, changes *watch.ChangeSet) error {
+	ci := fs.GetConfig(ctx)
+	return runSyncCopyMoveChanges(ctx, fdst, fsrc, ci.DeleteMode, false, false, copyEmptySrcDirs, false, changes, nil)
+}
+
 // CopyDir copies fsrc into fdst
 func CopyDir(ctx context.Context, fdst, fsrc fs.Fs, copyEmptySrcDirs bool) error {
 	return runSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOff, false, false, copyEmptySrcDirs, false)
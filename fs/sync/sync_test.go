@@ -5,11 +5,13 @@ package sync
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
@@ -22,6 +24,7 @@ import (
 	"github.com/rclone/rclone/cmd/bisync/bilib"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fs/hash"
@@ -804,6 +807,45 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 	r.CheckRemoteItems(t, file1)
 }
 
+// Sync with the listing cache enabled and check that changes made by
+// the sync invalidate the cached destination listings.
+func TestSyncWithListCache(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+	ci.ListCacheTTL = fs.Duration(time.Hour)
+
+	file1 := r.WriteFile("dir/file1", "potato", t1)
+	file2 := r.WriteFile("file2", "carrot", t1)
+
+	// Sync twice so the second sync fills the cache
+	for range 2 {
+		require.NoError(t, Sync(ctx, r.Fremote, r.Flocal, false))
+		r.CheckRemoteItems(t, file1, file2)
+	}
+
+	// Update a file and delete another using the cached listing
+	file1 = r.WriteFile("dir/file1", "potato2", t2)
+	require.NoError(t, os.Remove(filepath.Join(r.LocalName, "file2")))
+	r.CheckLocalItems(t, file1)
+	accounting.GlobalStats().ResetCounters()
+	require.NoError(t, Sync(ctx, r.Fremote, r.Flocal, false))
+	assert.Equal(t, toyFileTransfers(r), accounting.GlobalStats().GetTransfers())
+	r.CheckRemoteItems(t, file1)
+
+	// The next sync sees the changes
+	accounting.GlobalStats().ResetCounters()
+	require.NoError(t, Sync(ctx, r.Fremote, r.Flocal, false))
+	assert.Equal(t, int64(0), accounting.GlobalStats().GetTransfers())
+	assert.Equal(t, int64(0), accounting.GlobalStats().GetDeletes())
+	r.CheckRemoteItems(t, file1)
+}
+
 // Create a file and sync it. Change the last modified date and the
 // file contents but not the size.  If we're only doing sync by size
 // only, we expect nothing to to be transferred on the second sync.
@@ -3104,3 +3146,71 @@ func testLoggerVsLsf(ctx context.Context, fdst, fsrc fs.Fs, logger *bytes.Buffer
 		require.NoError(t, err)
 	}
 }
+
+// Test the report of a sync has one entry for each file with the
+// comparison as the reason for the action
+func TestSyncReport(t *testing.T) {
+	for _, mode := range []string{"sync", "copy", "move"} {
+		t.Run(mode, func(t *testing.T) {
+			ctx := context.Background()
+			r := fstest.NewRun(t)
+			r.WriteBoth(ctx, "same", "hello", t1)
+			r.WriteFile("chg", "hello", t2)
+			r.WriteObject(ctx, "chg", "hello world", t1)
+			r.WriteFile("new", "potato", t1)
+			r.WriteObject(ctx, "old", "carrot", t1)
+
+			var buf bytes.Buffer
+			report, err := operations.NewReport(&buf, "json", false)
+			require.NoError(t, err)
+			ctx = operations.WithSyncLogger(ctx, operations.LoggerOpt{
+				LoggerFn: func(context.Context, operations.Sigil, fs.DirEntry, fs.DirEntry, error) {},
+				Report:   report,
+			})
+
+			switch mode {
+			case "sync":
+				err = Sync(ctx, r.Fremote, r.Flocal, false)
+			case "copy":
+				err = CopyDir(ctx, r.Fremote, r.Flocal, false)
+			case "move":
+				err = MoveDir(ctx, r.Fremote, r.Flocal, false, false)
+			}
+			require.NoError(t, err)
+			require.NoError(t, report.Close())
+
+			var list []operations.ReportEntry
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+			entries := map[string]operations.ReportEntry{}
+			for _, e := range list {
+				_, found := entries[e.Path]
+				assert.False(t, found, "duplicate entry for %q", e.Path)
+				entries[e.Path] = e
+			}
+
+			transfer := operations.ReportCopy
+			if mode == "move" {
+				transfer = operations.ReportMove
+			}
+			assert.Equal(t, transfer, entries["chg"].Action)
+			assert.Equal(t, "differ", entries["chg"].Reason)
+			assert.Equal(t, transfer, entries["new"].Action)
+			assert.Equal(t, "missing_on_dst", entries["new"].Reason)
+			switch mode {
+			case "sync":
+				assert.Equal(t, "match", entries["same"].Action)
+				assert.Equal(t, operations.ReportDelete, entries["old"].Action)
+				assert.Equal(t, "missing_on_src", entries["old"].Reason)
+			case "copy":
+				assert.Equal(t, "match", entries["same"].Action)
+				assert.Equal(t, "missing_on_src", entries["old"].Action)
+				assert.Equal(t, "", entries["old"].Reason)
+			case "move":
+				assert.Equal(t, operations.ReportDelete, entries["same"].Action)
+				assert.Equal(t, "match", entries["same"].Reason)
+				assert.Equal(t, "missing_on_src", entries["old"].Action)
+			}
+			assert.Len(t, entries, 4)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package tracing implements optional OpenTelemetry tracing of
+// commands, transfers and HTTP calls.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/atexit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OptionsInfo describes the Options in use
+var OptionsInfo = fs.Options{{
+	Name:    "tracing_endpoint",
+	Default: "",
+	Help:    "Send OpenTelemetry traces to this OTLP/HTTP endpoint, e.g. http://localhost:4318",
+	Groups:  "Debugging",
+}, {
+	Name:    "tracing_file",
+	Default: "",
+	Help:    "Write OpenTelemetry traces to this file as JSON",
+	Groups:  "Debugging",
+}, {
+	Name:    "tracing_sample_ratio",
+	Default: 1.0,
+	Help:    "Fraction of commands and rc jobs to trace",
+	Groups:  "Debugging",
+}, {
+	Name:    "tracing_service_name",
+	Default: "rclone",
+	Help:    "Service name to use in the traces",
+	Groups:  "Debugging",
+}}
+
+// Options contains options for tracing
+type Options struct {
+	Endpoint    string  `config:"tracing_endpoint"`
+	File        string  `config:"tracing_file"`
+	SampleRatio float64 `config:"tracing_sample_ratio"`
+	ServiceName string  `config:"tracing_service_name"`
+}
+
+// Opt is the default options
+var Opt Options
+
+func init() {
+	fs.RegisterGlobalOptions(fs.OptionsInfo{Name: "tracing", Opt: &Opt, Options: OptionsInfo})
+	fs.PacerWait = pacerWait
+	fs.PacerRetry = pacerRetry
+}
+
+var (
+	enabled atomic.Bool
+
+	rootMu   sync.Mutex
+	rootSpan trace.Span // span for the current command if set
+)
+
+// tracerName is the name of the instrumentation
+const tracerName = "github.com/rclone/rclone"
+
+// tracer returns the tracer to make spans with
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Enabled returns true if tracing has been started
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Start tracing if configured in opt
+//
+// The traces are flushed when rclone exits.
+func Start(ctx context.Context, opt *Options) error {
+	if opt.Endpoint == "" && opt.File == "" {
+		return nil
+	}
+	var (
+		tpOpts  []sdktrace.TracerProviderOption
+		closers []io.Closer
+	)
+	if opt.Endpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opt.Endpoint))
+		if err != nil {
+			return fmt.Errorf("failed to start OTLP trace exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	}
+	if opt.File != "" {
+		out, err := os.Create(opt.File)
+		if err != nil {
+			return fmt.Errorf("failed to open trace file: %w", err)
+		}
+		closers = append(closers, out)
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			_ = out.Close()
+			return fmt.Errorf("failed to start trace file exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	}
+	res := resource.NewSchemaless(
+		attribute.String("service.name", opt.ServiceName),
+		attribute.String("service.version", fs.Version),
+	)
+	tpOpts = append(tpOpts,
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opt.SampleRatio))),
+	)
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+	otel.SetTracerProvider(tp)
+	enabled.Store(true)
+	fs.Infof(nil, "Started OpenTelemetry tracing")
+	atexit.Register(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := tp.Shutdown(ctx)
+		for _, c := range closers {
+			err = errors.Join(err, c.Close())
+		}
+		if err != nil {
+			fs.Errorf(nil, "Failed to flush traces: %v", err)
+		}
+	})
+	return nil
+}
+
+// StartCommand starts the span for the command name
+//
+// Spans started from contexts without a span become children of
+// this span. Call the function returned with the result of the
+// command to end the span.
+func StartCommand(ctx context.Context, name string, attrs ...attribute.KeyValue) (spanCtx context.Context, end func(err error)) {
+	if !Enabled() {
+		return ctx, func(error) {}
+	}
+	spanCtx, span := tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	rootMu.Lock()
+	rootSpan = span
+	rootMu.Unlock()
+	return spanCtx, func(err error) {
+		rootMu.Lock()
+		if rootSpan == span {
+			rootSpan = nil
+		}
+		rootMu.Unlock()
+		End(span, err)
+	}
+}
+
+// StartSpan starts a span called name as a child of the span in ctx
+// or the command span if there isn't one.
+//
+// It returns the context containing the span which should be passed
+// to the operations which are part of it. The span must be ended
+// with End.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !Enabled() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		rootMu.Lock()
+		if rootSpan != nil {
+			ctx = trace.ContextWithSpan(ctx, rootSpan)
+		}
+		rootMu.Unlock()
+	}
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartJob starts a span like StartSpan for a job, such as an rc
+// job, in a new trace linked to the command span.
+//
+// This means that long running commands, like rclone rcd, don't make
+// a single trace containing all the jobs.
+func StartJob(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !Enabled() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	opts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithAttributes(attrs...)}
+	rootMu.Lock()
+	if rootSpan != nil {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: rootSpan.SpanContext()}))
+	}
+	rootMu.Unlock()
+	return tracer().Start(ctx, name, opts...)
+}
+
+// End the span recording err if set
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Event adds an event called name to the span in ctx if it is
+// being recorded
+func Event(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+// minPacerWait is the shortest wait for the pacer recorded as an
+// event
+const minPacerWait = time.Millisecond
+
+// pacerWait records a wait for the pacer in the span in ctx if it is
+// significant
+func pacerWait(ctx context.Context, wait time.Duration) {
+	if wait >= minPacerWait {
+		Event(ctx, "pacer wait", attribute.Float64("rclone.wait_seconds", wait.Seconds()))
+	}
+}
+
+// pacerRetry records a low level retry in the span in ctx
+func pacerRetry(ctx context.Context, try int, err error) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{attribute.Int("rclone.try", try)}
+	if err != nil {
+		attrs = append(attrs, attribute.String("error", err.Error()))
+	}
+	Event(ctx, "pacer retry", attrs...)
+}
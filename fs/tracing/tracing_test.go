@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startRecorder starts tracing to a span recorder for the test
+func startRecorder(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	oldTP := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	enabled.Store(true)
+	t.Cleanup(func() {
+		enabled.Store(false)
+		otel.SetTracerProvider(oldTP)
+		_ = tp.Shutdown(context.Background())
+	})
+	return sr
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, Enabled())
+	require.NoError(t, Start(ctx, &Options{}))
+	assert.False(t, Enabled())
+
+	cmdCtx, end := StartCommand(ctx, "command")
+	assert.Equal(t, ctx, cmdCtx)
+	end(nil)
+
+	spanCtx, span := StartSpan(ctx, "copy")
+	assert.Equal(t, ctx, spanCtx)
+	assert.False(t, span.IsRecording())
+	End(span, errors.New("ignored"))
+}
+
+func TestSpans(t *testing.T) {
+	sr := startRecorder(t)
+	ctx := context.Background()
+
+	cmdCtx, endCommand := StartCommand(ctx, "command")
+	cmdSpan := trace.SpanFromContext(cmdCtx)
+
+	// Spans from a context without a span are children of the command
+	opCtx, opSpan := StartSpan(ctx, "copy")
+	assert.Equal(t, cmdSpan.SpanContext().SpanID(), opSpan.(sdktrace.ReadOnlySpan).Parent().SpanID())
+
+	// Spans from the operation context are its children
+	_, childSpan := StartSpan(opCtx, "child")
+	assert.Equal(t, opSpan.SpanContext().SpanID(), childSpan.(sdktrace.ReadOnlySpan).Parent().SpanID())
+	End(childSpan, nil)
+
+	Event(opCtx, "retry")
+	End(opSpan, errors.New("potato"))
+
+	// Jobs start a new trace
+	_, jobSpan := StartJob(ctx, "job")
+	assert.NotEqual(t, cmdSpan.SpanContext().TraceID(), jobSpan.SpanContext().TraceID())
+	ro := jobSpan.(sdktrace.ReadOnlySpan)
+	require.Len(t, ro.Links(), 1)
+	assert.Equal(t, cmdSpan.SpanContext().SpanID(), ro.Links()[0].SpanContext.SpanID())
+	End(jobSpan, nil)
+
+	endCommand(nil)
+
+	// The command span is no longer the default parent
+	_, orphan := StartSpan(ctx, "orphan")
+	assert.False(t, orphan.(sdktrace.ReadOnlySpan).Parent().IsValid())
+	End(orphan, nil)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range sr.Ended() {
+		spans[span.Name()] = span
+	}
+	assert.Len(t, spans, 5)
+	assert.Equal(t, codes.Error, spans["copy"].Status().Code)
+	assert.Equal(t, "potato", spans["copy"].Status().Description)
+	require.Len(t, spans["copy"].Events(), 2)
+	assert.Equal(t, "retry", spans["copy"].Events()[0].Name)
+	assert.Equal(t, "exception", spans["copy"].Events()[1].Name)
+	assert.Equal(t, codes.Unset, spans["command"].Status().Code)
+}
+
+func TestStartFile(t *testing.T) {
+	ctx := context.Background()
+	oldTP := otel.GetTracerProvider()
+	defer func() {
+		enabled.Store(false)
+		otel.SetTracerProvider(oldTP)
+	}()
+	file := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, Start(ctx, &Options{File: file, SampleRatio: 1, ServiceName: "rclone"}))
+	assert.True(t, Enabled())
+
+	_, span := StartSpan(ctx, "test span")
+	End(span, nil)
+	tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	require.True(t, ok)
+	require.NoError(t, tp.ForceFlush(ctx))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"test span"`)
+	assert.Contains(t, string(data), `"service.name"`)
+}
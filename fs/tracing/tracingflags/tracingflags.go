@@ -0,0 +1,13 @@
+// Package tracingflags implements command line flags to set up tracing
+package tracingflags
+
+import (
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/tracing"
+	"github.com/spf13/pflag"
+)
+
+// AddFlags adds the tracing flags to the flagSet
+func AddFlags(flagSet *pflag.FlagSet) {
+	flags.AddFlagsFromOptions(flagSet, "", tracing.OptionsInfo)
+}
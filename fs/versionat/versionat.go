@@ -0,0 +1,188 @@
+// Package versionat provides a read only view of an Fs as it was at
+// a point in time, using the ListAt and NewObjectAt features of the
+// backend.
+package versionat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// ErrorReadOnly is returned for attempts to modify an Fs which is
+// showing files as they were at a point in time.
+var ErrorReadOnly = errors.New("can't modify files when using --version-at")
+
+// Fs is a read only view of an fs.Fs as it was at a point in time
+type Fs struct {
+	fs.Fs
+	t        time.Time
+	features *fs.Features
+}
+
+// New returns a read only view of f as it was at time t
+//
+// It returns an error if the backend of f doesn't support reading
+// old versions of files.
+func New(ctx context.Context, f fs.Fs, t time.Time) (*Fs, error) {
+	if f.Features().ListAt == nil {
+		return nil, fmt.Errorf("%v doesn't support --version-at", f)
+	}
+	vf := &Fs{
+		Fs: f,
+		t:  t,
+	}
+	// Only features which don't modify the remote are passed on
+	vf.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          true,
+		ReadMimeType:            true,
+		ReadMetadata:            true,
+		ReadDirMetadata:         true,
+		CanHaveEmptyDirectories: true,
+		BucketBased:             true,
+		BucketBasedRootOK:       true,
+		GetTier:                 true,
+		SlowModTime:             true,
+		SlowHash:                true,
+	}).Fill(ctx, vf).Mask(ctx, f)
+	return vf, nil
+}
+
+// Time returns the time that the Fs is showing
+func (f *Fs) Time() time.Time { return f.t }
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("%v at %s", f.Fs, f.t.Format(time.RFC3339))
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features { return f.features }
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs { return f.Fs }
+
+// wrapEntries wraps the Objects in entries so they can't be modified
+func (f *Fs) wrapEntries(entries fs.DirEntries) fs.DirEntries {
+	for i, entry := range entries {
+		if o, ok := entry.(fs.Object); ok {
+			entries[i] = &Object{Object: o, f: f}
+		}
+	}
+	return entries
+}
+
+// List the objects and directories in dir into entries as they
+// were at the time of the Fs.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.Features().ListAt(ctx, dir, f.t)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrapEntries(entries), nil
+}
+
+// NewObject finds the Object at remote as it was at the time of the
+// Fs. If it can't be found it returns the error ErrorObjectNotFound.
+//
+// If the backend can't find single objects then the parent
+// directory is listed to find it.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if newObjectAt := f.Fs.Features().NewObjectAt; newObjectAt != nil {
+		o, err := newObjectAt(ctx, remote, f.t)
+		if err != nil {
+			return nil, err
+		}
+		return &Object{Object: o, f: f}, nil
+	}
+	dir := path.Dir(remote)
+	if dir == "." {
+		dir = ""
+	}
+	entries, err := f.List(ctx, dir)
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		return nil, fs.ErrorObjectNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if o, ok := entry.(fs.Object); ok && o.Remote() == remote {
+			return o, nil
+		}
+	}
+	return nil, fs.ErrorObjectNotFound
+}
+
+// Put is not supported
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return nil, ErrorReadOnly
+}
+
+// Mkdir is not supported
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return ErrorReadOnly
+}
+
+// Rmdir is not supported
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	return ErrorReadOnly
+}
+
+// Object is a version of a file which can't be modified
+type Object struct {
+	fs.Object
+	f *Fs
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info { return o.f }
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object { return o.Object }
+
+// MimeType returns the content type of the Object if known
+func (o *Object) MimeType(ctx context.Context) string {
+	if do, ok := o.Object.(fs.MimeTyper); ok {
+		return do.MimeType(ctx)
+	}
+	return ""
+}
+
+// Metadata returns metadata for the Object if known
+func (o *Object) Metadata(ctx context.Context) (fs.Metadata, error) {
+	if do, ok := o.Object.(fs.Metadataer); ok {
+		return do.Metadata(ctx)
+	}
+	return nil, nil
+}
+
+// SetModTime is not supported
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	return ErrorReadOnly
+}
+
+// Update is not supported
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return ErrorReadOnly
+}
+
+// Remove is not supported
+func (o *Object) Remove(ctx context.Context) error {
+	return ErrorReadOnly
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+	_ fs.MimeTyper       = (*Object)(nil)
+	_ fs.Metadataer      = (*Object)(nil)
+)
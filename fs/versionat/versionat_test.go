@@ -0,0 +1,125 @@
+package versionat
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/rclone/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = fstest.Time("2001-02-03T04:05:06Z")
+	t2 = fstest.Time("2011-12-25T12:59:59Z")
+)
+
+// timeFs is a mock Fs which has file1 at t1 and file1 and file2 at t2
+type timeFs struct {
+	fs.Fs
+	features *fs.Features
+}
+
+func newTimeFs(t *testing.T) *timeFs {
+	ctx := context.Background()
+	f, err := mockfs.NewFs(ctx, "mock", "root", nil)
+	require.NoError(t, err)
+	tf := &timeFs{Fs: f}
+	tf.features = (&fs.Features{}).Fill(ctx, tf)
+	return tf
+}
+
+func (f *timeFs) Features() *fs.Features { return f.features }
+
+func (f *timeFs) ListAt(ctx context.Context, dir string, t time.Time) (entries fs.DirEntries, err error) {
+	if dir != "" {
+		return nil, fs.ErrorDirNotFound
+	}
+	if !t.Before(t1) {
+		entries = append(entries, mockobject.New("file1"))
+	}
+	if !t.Before(t2) {
+		entries = append(entries, mockobject.New("file2"))
+	}
+	return entries, nil
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	f, err := mockfs.NewFs(ctx, "mock", "root", nil)
+	require.NoError(t, err)
+	_, err = New(ctx, f, t1)
+	assert.ErrorContains(t, err, "doesn't support --version-at")
+
+	vf, err := New(ctx, newTimeFs(t), t1)
+	require.NoError(t, err)
+	assert.Equal(t, t1, vf.Time())
+	assert.Contains(t, vf.String(), "2001-02-03T04:05:06Z")
+	assert.Nil(t, vf.Features().Purge)
+	assert.Nil(t, vf.Features().ListAt)
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	tf := newTimeFs(t)
+	for _, test := range []struct {
+		t    time.Time
+		want []string
+	}{
+		{t1.Add(-time.Second), nil},
+		{t1, []string{"file1"}},
+		{t2, []string{"file1", "file2"}},
+	} {
+		vf, err := New(ctx, tf, test.t)
+		require.NoError(t, err)
+		entries, err := vf.List(ctx, "")
+		require.NoError(t, err)
+		var got []string
+		for _, entry := range entries {
+			_, ok := entry.(*Object)
+			assert.True(t, ok)
+			got = append(got, entry.Remote())
+		}
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestNewObject(t *testing.T) {
+	ctx := context.Background()
+	vf, err := New(ctx, newTimeFs(t), t1)
+	require.NoError(t, err)
+
+	o, err := vf.NewObject(ctx, "file1")
+	require.NoError(t, err)
+	assert.Equal(t, "file1", o.Remote())
+	assert.Equal(t, vf, o.Fs())
+
+	_, err = vf.NewObject(ctx, "file2")
+	assert.ErrorIs(t, err, fs.ErrorObjectNotFound)
+	_, err = vf.NewObject(ctx, "dir/file1")
+	assert.ErrorIs(t, err, fs.ErrorObjectNotFound)
+}
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	vf, err := New(ctx, newTimeFs(t), t2)
+	require.NoError(t, err)
+
+	src := object.NewStaticObjectInfo("new", t1, 1, true, nil, nil)
+	_, err = vf.Put(ctx, strings.NewReader("x"), src)
+	assert.Equal(t, ErrorReadOnly, err)
+	assert.Equal(t, ErrorReadOnly, vf.Mkdir(ctx, "dir"))
+	assert.Equal(t, ErrorReadOnly, vf.Rmdir(ctx, "dir"))
+
+	o, err := vf.NewObject(ctx, "file2")
+	require.NoError(t, err)
+	assert.Equal(t, ErrorReadOnly, o.Remove(ctx))
+	assert.Equal(t, ErrorReadOnly, o.SetModTime(ctx, t1))
+	assert.Equal(t, ErrorReadOnly, o.Update(ctx, strings.NewReader("x"), src))
+}
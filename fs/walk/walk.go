@@ -296,6 +296,7 @@ func listR(ctx context.Context, f fs.Fs, path string, includeAll bool, listType
 		dm = newDirMap(path)
 	}
 	var mu sync.Mutex
+	done := accounting.BackendCall(f, accounting.OpList)
 	err := doListR(ctx, path, func(entries fs.DirEntries) (err error) {
 		accounting.Stats(ctx).Listed(int64(len(entries)))
 		if synthesizeDirs {
@@ -330,6 +331,7 @@ func listR(ctx context.Context, f fs.Fs, path string, includeAll bool, listType
 		defer mu.Unlock()
 		return fn(entries)
 	})
+	done(err)
 	if err != nil {
 		return err
 	}
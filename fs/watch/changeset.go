@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"path"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// ChangeSet is a set of paths reported as changed on a remote
+type ChangeSet struct {
+	paths map[string]fs.EntryType // paths reported as changed
+	dirs  map[string]struct{}     // directories containing changed paths
+}
+
+// NewChangeSet makes an empty ChangeSet
+func NewChangeSet() *ChangeSet {
+	return &ChangeSet{
+		paths: map[string]fs.EntryType{},
+		dirs:  map[string]struct{}{},
+	}
+}
+
+// parentDir returns the directory containing remote
+func parentDir(remote string) string {
+	dir := path.Dir(remote)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// Add records remote as changed
+//
+// It has the signature needed for the notifyFunc of ChangeNotify.
+func (c *ChangeSet) Add(remote string, entryType fs.EntryType) {
+	if old, found := c.paths[remote]; !found || old != fs.EntryDirectory {
+		c.paths[remote] = entryType
+	}
+	if remote == "" {
+		return
+	}
+	for dir := parentDir(remote); ; dir = parentDir(dir) {
+		if _, found := c.dirs[dir]; found {
+			break
+		}
+		c.dirs[dir] = struct{}{}
+		if dir == "" {
+			break
+		}
+	}
+}
+
+// AddSet records all the paths in other as changed
+func (c *ChangeSet) AddSet(other *ChangeSet) {
+	for remote, entryType := range other.paths {
+		c.Add(remote, entryType)
+	}
+}
+
+// Len returns the number of paths reported as changed
+func (c *ChangeSet) Len() int {
+	return len(c.paths)
+}
+
+// Paths returns the paths reported as changed in no particular order
+func (c *ChangeSet) Paths() []string {
+	paths := make([]string, 0, len(c.paths))
+	for remote := range c.paths {
+		paths = append(paths, remote)
+	}
+	return paths
+}
+
+// Include returns true if a sync needs to look at entry
+//
+// This is true if entry or any directory above it was reported as
+// changed. Directories containing changes are included so the sync
+// can find them. Paths reported as objects are treated as directory
+// trees too, as some backends can't tell what type a deleted item
+// was.
+func (c *ChangeSet) Include(entry fs.DirEntry) bool {
+	_, isDir := entry.(fs.Directory)
+	return c.IncludePath(entry.Remote(), isDir)
+}
+
+// IncludePath is like Include for a path which is a directory if
+// isDir is set
+func (c *ChangeSet) IncludePath(remote string, isDir bool) bool {
+	if isDir {
+		if _, found := c.dirs[remote]; found {
+			return true
+		}
+	}
+	for p := remote; ; p = parentDir(p) {
+		if _, found := c.paths[p]; found {
+			return true
+		}
+		if p == "" {
+			return false
+		}
+	}
+}
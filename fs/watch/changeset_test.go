@@ -0,0 +1,51 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest/mockdir"
+	"github.com/rclone/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeSet(t *testing.T) {
+	c := NewChangeSet()
+	c.Add("a/b/file", fs.EntryObject)
+	c.Add("c/d", fs.EntryDirectory)
+	c.Add("e", fs.EntryObject)
+
+	for _, test := range []struct {
+		entry fs.DirEntry
+		want  bool
+	}{
+		{mockdir.New("a"), true},
+		{mockdir.New("a/b"), true},
+		{mockobject.New("a/b/file"), true},
+		{mockobject.New("a/b/file2"), false},
+		{mockobject.New("a/file"), false},
+		{mockdir.New("a/c"), false},
+		{mockdir.New("c"), true},
+		{mockdir.New("c/d"), true},
+		{mockobject.New("c/d/e/f"), true},
+		{mockobject.New("c/e"), false},
+		{mockobject.New("e"), true},
+		{mockobject.New("e/f"), true}, // e might have been a directory
+		{mockobject.New("f"), false},
+	} {
+		assert.Equal(t, test.want, c.Include(test.entry), test.entry.Remote())
+	}
+
+	assert.True(t, c.IncludePath("a", true))
+	assert.False(t, c.IncludePath("a", false))
+	assert.True(t, c.IncludePath("c/d/e", false))
+
+	other := NewChangeSet()
+	other.Add("g/h", fs.EntryObject)
+	c.AddSet(other)
+	assert.Equal(t, 4, c.Len())
+	assert.True(t, c.IncludePath("g", true))
+
+	c.Add("", fs.EntryDirectory)
+	assert.True(t, c.Include(mockobject.New("f")))
+}
@@ -0,0 +1,147 @@
+//go:build linux
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	rfs "github.com/rclone/rclone/fs"
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask is the events watched for on each directory
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_ATTRIB |
+	unix.IN_CLOSE_WRITE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// inotify watches a directory tree with inotify
+type inotify struct {
+	root   string
+	fd     int
+	notify func(string, rfs.EntryType)
+	mu     sync.Mutex
+	dirs   map[int]string // watch descriptor to path relative to root
+}
+
+// watchLocal watches the directory tree at root calling notify with
+// each path which changes until ctx is cancelled
+func watchLocal(ctx context.Context, root string, notify func(string, rfs.EntryType)) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("failed to start inotify: %w", err)
+	}
+	// Using an *os.File makes reads use the runtime poller, so
+	// closing it stops the reader
+	file := os.NewFile(uintptr(fd), "inotify")
+	w := &inotify{
+		root:   root,
+		fd:     fd,
+		notify: notify,
+		dirs:   map[int]string{},
+	}
+	err = w.addTree("")
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = file.Close()
+	}()
+	go w.read(file)
+	return nil
+}
+
+// addTree adds watches for dir and all the directories under it
+func (w *inotify) addTree(dir string) error {
+	return filepath.WalkDir(filepath.Join(w.root, filepath.FromSlash(dir)), func(osPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may have gone already or be unreadable
+			rfs.Debugf(nil, "inotify: skipping %q: %v", osPath, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(w.root, osPath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, osPath, inotifyMask)
+		if errors.Is(err, unix.ENOSPC) {
+			return fmt.Errorf("too many directories to watch with inotify - try increasing fs.inotify.max_user_watches: %w", err)
+		} else if err != nil {
+			rfs.Debugf(nil, "inotify: failed to watch %q: %v", osPath, err)
+			return nil
+		}
+		w.mu.Lock()
+		w.dirs[wd] = rel
+		w.mu.Unlock()
+		return nil
+	})
+}
+
+// read events from file until it is closed
+func (w *inotify) read(file *os.File) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				rfs.Errorf(nil, "inotify: stopped watching %q: %v", w.root, err)
+			}
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(event.Len)]
+			offset += unix.SizeofInotifyEvent + int(event.Len)
+			w.handle(event, strings.TrimRight(string(nameBytes), "\x00"))
+		}
+	}
+}
+
+// handle a single inotify event
+func (w *inotify) handle(event *unix.InotifyEvent, name string) {
+	if event.Mask&unix.IN_Q_OVERFLOW != 0 {
+		// Events were lost so everything needs looking at
+		w.notify("", rfs.EntryDirectory)
+		return
+	}
+	w.mu.Lock()
+	dir, ok := w.dirs[int(event.Wd)]
+	if event.Mask&unix.IN_IGNORED != 0 {
+		delete(w.dirs, int(event.Wd))
+	}
+	w.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+	remote := path.Join(dir, name)
+	entryType := rfs.EntryObject
+	if event.Mask&unix.IN_ISDIR != 0 {
+		entryType = rfs.EntryDirectory
+		if event.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+			err := w.addTree(remote)
+			if err != nil {
+				rfs.Errorf(nil, "inotify: %v", err)
+			}
+		}
+	}
+	w.notify(remote, entryType)
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package watch
+
+import (
+	"context"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// watchLocal isn't supported on this OS so change notifications or
+// polling are used instead
+func watchLocal(ctx context.Context, root string, notify func(string, fs.EntryType)) error {
+	return errNotSupported
+}
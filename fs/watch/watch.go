@@ -0,0 +1,241 @@
+// Package watch runs syncs continuously as remotes change
+//
+// Changes are read from inotify for local paths on Linux and from
+// ChangeNotify for remotes which support it. Only inotify is
+// implemented for local paths, so on other OSes they are polled by
+// doing a full pass every --watch-poll-interval, as are remotes which
+// don't support ChangeNotify.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/spf13/pflag"
+)
+
+// Options control the watching
+type Options struct {
+	Watch        bool        // keep running and sync again when the source changes
+	Delay        fs.Duration // wait until there have been no changes for this long
+	PollInterval fs.Duration // how often to check remotes without change notifications
+}
+
+// Opt is the options set by command line flags
+var Opt = Options{
+	Delay:        fs.Duration(5 * time.Second),
+	PollInterval: fs.Duration(time.Minute),
+}
+
+// AddFlags adds the watch flags to the command
+//
+// These are command flags rather than global options as only the
+// commands which call Run use them.
+func AddFlags(flagSet *pflag.FlagSet) {
+	flags.BoolVarP(flagSet, &Opt.Watch, "watch", "", Opt.Watch, "Keep running and sync again whenever the source changes", "Sync")
+	flags.FVarP(flagSet, &Opt.Delay, "watch-delay", "", "Wait until there have been no changes for this long before syncing", "Sync")
+	flags.FVarP(flagSet, &Opt.PollInterval, "watch-poll-interval", "", "How often to check remotes without change notifications", "Sync")
+}
+
+// errNotSupported is returned by watchLocal if inotify isn't available
+var errNotSupported = errors.New("watching local paths isn't supported on this OS")
+
+// PassFn is called by Run to sync the changes
+//
+// changes has an entry for each Fs passed to Run holding the paths
+// which changed on it since the last pass. An entry is nil if all of
+// that Fs needs looking at, which is always the case for the first
+// pass.
+type PassFn func(ctx context.Context, changes []*ChangeSet) error
+
+// watcher collects the changes to the Fses being watched
+type watcher struct {
+	opt     *Options
+	mu      sync.Mutex
+	pending []*ChangeSet  // changes since the last pass, nil for everything
+	changed chan struct{} // signalled when there is a change
+}
+
+// add records a change to remote on the i-th Fs
+func (w *watcher) add(i int, remote string, entryType fs.EntryType) {
+	w.mu.Lock()
+	if w.pending[i] != nil {
+		w.pending[i].Add(remote, entryType)
+	}
+	w.mu.Unlock()
+	w.signal()
+}
+
+// addAll records that everything on the i-th Fs needs looking at
+func (w *watcher) addAll(i int) {
+	w.mu.Lock()
+	w.pending[i] = nil
+	w.mu.Unlock()
+	w.signal()
+}
+
+// signal that there has been a change without blocking
+func (w *watcher) signal() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the pending changes and starts collecting new ones
+func (w *watcher) take() (changes []*ChangeSet) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	changes = w.pending
+	w.pending = make([]*ChangeSet, len(changes))
+	for i := range w.pending {
+		w.pending[i] = NewChangeSet()
+	}
+	return changes
+}
+
+// start watching the i-th Fs f
+func (w *watcher) start(ctx context.Context, i int, f fs.Fs) error {
+	notify := func(remote string, entryType fs.EntryType) {
+		fs.Debugf(f, "Change notification for %q", remote)
+		w.add(i, remote, entryType)
+	}
+	if f.Features().IsLocal {
+		err := watchLocal(ctx, f.Root(), notify)
+		if err == nil {
+			fs.Infof(f, "Watching for changes with inotify")
+			return nil
+		}
+		if !errors.Is(err, errNotSupported) {
+			return fmt.Errorf("failed to watch %v: %w", fs.ConfigString(f), err)
+		}
+	}
+	if changeNotify := f.Features().ChangeNotify; changeNotify != nil {
+		pollInterval := make(chan time.Duration, 1)
+		pollInterval <- time.Duration(w.opt.PollInterval)
+		changeNotify(ctx, notify, pollInterval)
+		go func() {
+			<-ctx.Done()
+			close(pollInterval)
+		}()
+		fs.Infof(f, "Watching for changes with change notifications every %v", w.opt.PollInterval)
+		return nil
+	}
+	fs.Logf(f, "Doesn't support change notifications so syncing everything every %v", w.opt.PollInterval)
+	go func() {
+		ticker := time.NewTicker(time.Duration(w.opt.PollInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.addAll(i)
+			}
+		}
+	}()
+	return nil
+}
+
+// wait for a change then for opt.Delay with no changes
+//
+// If retry is set it also returns after that long without a change.
+// It returns false if ctx is cancelled.
+func (w *watcher) wait(ctx context.Context, retry time.Duration) bool {
+	var timeout <-chan time.Time
+	if retry > 0 {
+		timeout = time.After(retry)
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.changed:
+	case <-timeout:
+		return true
+	}
+	// Don't wait for more than 10 delays so a steady stream of
+	// changes still gets synced
+	delay := time.Duration(w.opt.Delay)
+	deadline := time.Now().Add(10 * delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.changed:
+			if time.Now().Add(delay).Before(deadline) {
+				timer.Reset(delay)
+			}
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// Run calls pass to sync everything then keeps running, calling pass
+// again with the paths which changed on fss each time they change. It
+// returns when ctx is cancelled.
+//
+// Changes are collected until there have been none for opt.Delay. If
+// a pass fails the error is logged and the next pass looks at
+// everything, unless the error is fatal when Run returns it.
+func Run(ctx context.Context, fss []fs.Fs, opt *Options, pass PassFn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := &watcher{
+		opt:     opt,
+		pending: make([]*ChangeSet, len(fss)),
+		changed: make(chan struct{}, 1),
+	}
+	// Start watching before the first pass so changes made during
+	// it are seen
+	for i, f := range fss {
+		err := w.start(ctx, i, f)
+		if err != nil {
+			return err
+		}
+	}
+	var retry time.Duration
+	for {
+		changes := w.take()
+		if needsPass(changes) {
+			accounting.Stats(ctx).ResetErrors()
+			err := pass(ctx, changes)
+			if fserrors.IsFatalError(err) {
+				return err
+			} else if err != nil {
+				fs.Errorf(nil, "Sync failed - will sync everything after the next change or in %v: %v", opt.PollInterval, err)
+				w.mu.Lock()
+				for i := range w.pending {
+					w.pending[i] = nil
+				}
+				w.mu.Unlock()
+				retry = time.Duration(opt.PollInterval)
+			} else {
+				retry = 0
+				fs.Infof(nil, "Waiting for changes")
+			}
+		}
+		if !w.wait(ctx, retry) {
+			return nil
+		}
+	}
+}
+
+// needsPass returns true if there is anything to sync
+func needsPass(changes []*ChangeSet) bool {
+	for _, c := range changes {
+		if c == nil || c.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	_ "github.com/rclone/rclone/backend/memory"
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runWatch starts Run on f sending the changes for each pass to the
+// returned channel. pass is called first if set.
+func runWatch(t *testing.T, f fs.Fs, opt *Options, pass PassFn) <-chan *ChangeSet {
+	ctx, cancel := context.WithCancel(context.Background())
+	passes := make(chan *ChangeSet, 10)
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, []fs.Fs{f}, opt, func(ctx context.Context, changes []*ChangeSet) error {
+			require.Len(t, changes, 1)
+			passes <- changes[0]
+			if pass != nil {
+				return pass(ctx, changes)
+			}
+			return nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return passes
+}
+
+// next returns the changes for the next pass
+func next(t *testing.T, passes <-chan *ChangeSet) *ChangeSet {
+	t.Helper()
+	select {
+	case changes := <-passes:
+		return changes
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a pass")
+	}
+	return nil
+}
+
+func TestRunLocal(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inotify is only supported on linux")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0777))
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+	passes := runWatch(t, f, &Options{
+		Delay:        fs.Duration(50 * time.Millisecond),
+		PollInterval: fs.Duration(time.Hour),
+	}, nil)
+
+	// The first pass looks at everything
+	assert.Nil(t, next(t, passes))
+
+	// Changes in existing and new directories are reported together
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "file1"), []byte("hello"), 0666))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "new"), 0777))
+	changes := next(t, passes)
+	require.NotNil(t, changes)
+	assert.True(t, changes.IncludePath("sub/file1", false))
+	assert.True(t, changes.IncludePath("new", true))
+	assert.False(t, changes.IncludePath("other", false))
+
+	// New directories are watched too
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new", "file2"), []byte("potato"), 0666))
+	changes = next(t, passes)
+	require.NotNil(t, changes)
+	assert.True(t, changes.IncludePath("new/file2", false))
+	assert.False(t, changes.IncludePath("sub/file1", false))
+}
+
+func TestRunPoll(t *testing.T) {
+	f, err := fs.NewFs(context.Background(), ":memory:")
+	require.NoError(t, err)
+	require.Nil(t, f.Features().ChangeNotify)
+	passes := runWatch(t, f, &Options{
+		Delay:        fs.Duration(10 * time.Millisecond),
+		PollInterval: fs.Duration(100 * time.Millisecond),
+	}, nil)
+
+	// Everything is looked at each poll
+	assert.Nil(t, next(t, passes))
+	assert.Nil(t, next(t, passes))
+}
+
+func TestRunRetry(t *testing.T) {
+	f, err := fs.NewFs(context.Background(), ":memory:")
+	require.NoError(t, err)
+	fail := true
+	passes := runWatch(t, f, &Options{
+		Delay:        fs.Duration(10 * time.Millisecond),
+		PollInterval: fs.Duration(100 * time.Millisecond),
+	}, func(ctx context.Context, changes []*ChangeSet) error {
+		if fail {
+			fail = false
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	// A failed pass is retried
+	assert.Nil(t, next(t, passes))
+	assert.Nil(t, next(t, passes))
+}
@@ -0,0 +1,19 @@
+// Package archive contains utilities shared by the archive backend
+// and the archive commands.
+package archive
+
+import (
+	"path"
+	"strings"
+)
+
+// CleanName makes an archive member name into a path within the
+// archive which is safe to use as a remote path, stopping it escaping
+// from the archive or a destination it is extracted to.
+//
+// It returns "" if there is nothing left.
+func CleanName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,26 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanName(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"file.txt", "file.txt"},
+		{"dir/file.txt", "dir/file.txt"},
+		{"dir/", "dir"},
+		{"/abs/file.txt", "abs/file.txt"},
+		{"../../etc/passwd", "etc/passwd"},
+		{"dir/../../file.txt", "file.txt"},
+		{`dir\file.txt`, "dir/file.txt"},
+		{"./", ""},
+		{"", ""},
+	} {
+		assert.Equal(t, test.want, CleanName(test.in), test.in)
+	}
+}
@@ -0,0 +1,115 @@
+// Package cdc implements content defined chunking.
+//
+// This is an implementation of FastCDC with normalized chunking. A
+// rolling "gear" hash is computed over the data and a chunk boundary
+// is declared whenever the masked hash is zero. Because boundaries
+// depend only on the local content, inserting or removing bytes in
+// one part of a file only changes the chunks around the edit and the
+// rest of the file still deduplicates.
+//
+// The gear table and the masks must never change as that would
+// change where the boundaries fall for existing data.
+package cdc
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// gear is the table of random values used by the rolling hash
+var gear [256]uint64
+
+func init() {
+	// splitmix64 with a fixed seed so the table is stable
+	seed := uint64(0x7263_6c6f_6e65_6364) // "rclonecd"
+	for i := range gear {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+}
+
+// Chunker splits a stream into content defined chunks
+type Chunker struct {
+	in      io.Reader
+	buf     []byte // holds up to maxSize bytes of pending data
+	n       int    // number of bytes of pending data in buf
+	eof     bool   // set when in has been exhausted
+	minSize int    // no boundary is looked for before this
+	avgSize int    // boundaries are harder to find before this and easier after
+	maxSize int    // a boundary is forced here
+	maskS   uint64 // mask used before avgSize
+	maskL   uint64 // mask used after avgSize
+}
+
+// New makes a chunker which reads from in and produces
+// chunks of on average avgSize bytes. avgSize is rounded down to a
+// power of 2. Chunks are never smaller than avgSize/4 (except the
+// last one) or bigger than avgSize*4.
+func New(in io.Reader, avgSize int) (*Chunker, error) {
+	if avgSize < 256 {
+		return nil, errors.New("chunk size must be at least 256 bytes")
+	}
+	avgBits := bits.Len(uint(avgSize)) - 1
+	avgSize = 1 << avgBits
+	return &Chunker{
+		in:      in,
+		buf:     make([]byte, avgSize*4),
+		minSize: avgSize / 4,
+		avgSize: avgSize,
+		maxSize: avgSize * 4,
+		// Use the top bits of the hash as they carry the most history
+		maskS: ((uint64(1) << (avgBits + 1)) - 1) << (64 - (avgBits + 1)),
+		maskL: ((uint64(1) << (avgBits - 1)) - 1) << (64 - (avgBits - 1)),
+	}, nil
+}
+
+// Next returns the next chunk or io.EOF when there are no more.
+func (c *Chunker) Next() ([]byte, error) {
+	// Top up the buffer
+	if !c.eof && c.n < len(c.buf) {
+		m, err := io.ReadFull(c.in, c.buf[c.n:])
+		c.n += m
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+	cut := c.cutPoint(c.buf[:c.n])
+	chunk := make([]byte, cut)
+	copy(chunk, c.buf[:cut])
+	// Shift the remaining data to the start of the buffer
+	c.n = copy(c.buf, c.buf[cut:c.n])
+	return chunk, nil
+}
+
+// cutPoint returns the length of the first chunk in data
+func (c *Chunker) cutPoint(data []byte) int {
+	n := len(data)
+	if n <= c.minSize {
+		return n
+	}
+	normal := min(c.avgSize, n)
+	var fp uint64
+	i := c.minSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
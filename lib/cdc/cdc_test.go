@@ -0,0 +1,60 @@
+package cdc
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunker(t *testing.T) {
+	const avg = 4096
+	data := make([]byte, 256*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	split := func(data []byte) (chunks []string) {
+		c, err := New(bytes.NewReader(data), avg)
+		require.NoError(t, err)
+		var total int
+		for {
+			chunk, err := c.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(chunk), 4*avg)
+			total += len(chunk)
+			chunks = append(chunks, string(chunk))
+		}
+		assert.Equal(t, len(data), total)
+		return chunks
+	}
+
+	chunks := split(data)
+	assert.Greater(t, len(chunks), 256*1024/(4*avg))
+	for _, chunk := range chunks[:len(chunks)-1] {
+		assert.GreaterOrEqual(t, len(chunk), avg/4)
+	}
+
+	// Inserting some bytes in the middle should only change the
+	// chunks near the insertion point
+	edited := append(append(append([]byte{}, data[:100000]...), "inserted"...), data[100000:]...)
+	editedChunks := split(edited)
+	seen := map[string]bool{}
+	for _, chunk := range chunks {
+		seen[chunk] = true
+	}
+	changed := 0
+	for _, chunk := range editedChunks {
+		if !seen[chunk] {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 2)
+
+	// Empty input has no chunks
+	assert.Empty(t, split(nil))
+}
@@ -42,6 +42,20 @@ htpasswd -B htpasswd anotherUser
 
 The password file can be updated while rclone is running.
 
+To authenticate with JWT bearer tokens from an OpenID Connect or
+other single sign on provider, use ` + "`--{{ .Prefix }}jwt-jwks`" + ` to give the URL (or
+a local file) of the provider's JSON Web Key Set. Clients then send
+` + "`Authorization: Bearer <token>`" + ` and the token's signature, expiry and
+not before time are checked. Tokens without an expiry are rejected. Use
+` + "`--{{ .Prefix }}jwt-issuer`" + ` and ` + "`--{{ .Prefix }}jwt-audience`" + ` to require the ` + "`iss`" + ` and ` + "`aud`" + `
+claims to match. Without an audience rclone accepts tokens the provider
+issued for any application, so rclone warns at startup if it isn't set. The user name is read from the claim
+given by ` + "`--{{ .Prefix }}jwt-user-claim`" + ` (default ` + "`sub`" + `) which may be a
+nested claim such as ` + "`realm.user`" + `. The keys are reloaded hourly, or
+sooner if a token is signed with an unknown key. This can't be
+combined with the htpasswd file or single user and password and the
+server won't start if they are set together.
+
 Use ` + "`--{{ .Prefix }}realm`" + ` to set the authentication realm.
 
 Use ` + "`--{{ .Prefix }}salt`" + ` to change the password hashing salt from the default.
@@ -96,6 +110,22 @@ var AuthConfigInfo = fs.Options{{
 	Name:    "user_from_header",
 	Default: "",
 	Help:    "User name from a defined HTTP header",
+}, {
+	Name:    "jwt_jwks",
+	Default: "",
+	Help:    "URL or file of the JSON Web Key Set to check JWT bearer tokens with",
+}, {
+	Name:    "jwt_issuer",
+	Default: "",
+	Help:    "Issuer JWT bearer tokens must have",
+}, {
+	Name:    "jwt_audience",
+	Default: "",
+	Help:    "Audience JWT bearer tokens must have",
+}, {
+	Name:    "jwt_user_claim",
+	Default: "sub",
+	Help:    "Claim in JWT bearer tokens to use as the user name",
 }}
 
 // AuthConfig contains options for the http authentication
@@ -106,6 +136,10 @@ type AuthConfig struct {
 	BasicPass      string       `config:"pass"`             // password for BasicUser
 	Salt           string       `config:"salt"`             // password hashing salt
 	UserFromHeader string       `config:"user_from_header"` // retrieve user name from a defined HTTP header
+	JWTJWKS        string       `config:"jwt_jwks"`         // URL or file of the JWKS to check bearer tokens with
+	JWTIssuer      string       `config:"jwt_issuer"`       // required issuer of bearer tokens
+	JWTAudience    string       `config:"jwt_audience"`     // required audience of bearer tokens
+	JWTUserClaim   string       `config:"jwt_user_claim"`   // claim to use as the user name
 	CustomAuthFn   CustomAuthFn `json:"-" config:"-"`       // custom Auth (not set by command line flags)
 }
 
@@ -117,6 +151,10 @@ func (cfg *AuthConfig) AddFlagsPrefix(flagSet *pflag.FlagSet, prefix string) {
 	flags.StringVarP(flagSet, &cfg.BasicPass, prefix+"pass", "", cfg.BasicPass, "Password for authentication", prefix)
 	flags.StringVarP(flagSet, &cfg.Salt, prefix+"salt", "", cfg.Salt, "Password hashing salt", prefix)
 	flags.StringVarP(flagSet, &cfg.UserFromHeader, prefix+"user-from-header", "", cfg.UserFromHeader, "Retrieve the username from a specified HTTP header if no other authentication methods are configured (ideal for proxied setups)", prefix)
+	flags.StringVarP(flagSet, &cfg.JWTJWKS, prefix+"jwt-jwks", "", cfg.JWTJWKS, "URL or file of the JSON Web Key Set to check JWT bearer tokens with", prefix)
+	flags.StringVarP(flagSet, &cfg.JWTIssuer, prefix+"jwt-issuer", "", cfg.JWTIssuer, "Issuer JWT bearer tokens must have", prefix)
+	flags.StringVarP(flagSet, &cfg.JWTAudience, prefix+"jwt-audience", "", cfg.JWTAudience, "Audience JWT bearer tokens must have", prefix)
+	flags.StringVarP(flagSet, &cfg.JWTUserClaim, prefix+"jwt-user-claim", "", cfg.JWTUserClaim, "Claim in JWT bearer tokens to use as the user name", prefix)
 }
 
 // AddAuthFlagsPrefix adds flags to the flag set for AuthConfig
@@ -130,6 +168,7 @@ func AddAuthFlagsPrefix(flagSet *pflag.FlagSet, prefix string, cfg *AuthConfig)
 // can be removed when all callers have been converted.
 func DefaultAuthCfg() AuthConfig {
 	return AuthConfig{
-		Salt: "dlPL2MqE",
+		Salt:         "dlPL2MqE",
+		JWTUserClaim: "sub",
 	}
 }
@@ -0,0 +1,394 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rclone/rclone/fs"
+)
+
+// How often the JSON Web Key Set is reloaded
+const (
+	jwksRefresh    = time.Hour   // reload the keys this often
+	jwksMinRefresh = time.Minute // but no more often than this for unknown key IDs
+)
+
+// Signing methods accepted for JWT bearer tokens. Symmetric methods
+// and "none" are never accepted.
+var jwtValidMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+var errUnknownKeyID = errors.New("unknown key ID")
+
+// Client used to fetch the JSON Web Key Set. fshttp can't be used as
+// it imports this package via rc.
+var jwksClient = &http.Client{Timeout: time.Minute}
+
+// jwk is a single key from a JSON Web Key Set as defined in RFC 7517
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwkKey is a parsed public key
+type jwkKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// decodeBigInt decodes a base64url encoded big endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// publicKey converts the JWK into a public key
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("bad n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("bad e: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("bad e: too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var (
+			curve     elliptic.Curve
+			ecdhCurve ecdh.Curve
+		)
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("bad x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("bad y: %w", err)
+		}
+		// Check the point is on the curve by making an uncompressed point
+		size := (curve.Params().BitSize + 7) / 8
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("bad point: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.X, "="))
+		if err != nil {
+			return nil, fmt.Errorf("bad x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("bad x: wrong length")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// parseJWKS parses a JSON Web Key Set returning the signing keys in it
+func parseJWKS(data []byte) ([]jwkKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	var keys []jwkKey
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			fs.Debugf(nil, "JWT: ignoring key %q from JWKS: %v", k.Kid, err)
+			continue
+		}
+		keys = append(keys, jwkKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable keys in JWKS")
+	}
+	return keys, nil
+}
+
+// jwksSource loads a JSON Web Key Set from a URL or a file
+//
+// The keys are reloaded every jwksRefresh or when a token is signed
+// with a key which isn't known, which happens when keys are rotated.
+type jwksSource struct {
+	source string
+	mu     sync.Mutex
+	keys   []jwkKey
+	loaded time.Time
+}
+
+// fetchJWKS reads a JSON Web Key Set from a URL
+func fetchJWKS(ctx context.Context, url string) (data []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := jwksClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: %s", resp.Status)
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	return data, nil
+}
+
+// load reads the keys from the source
+func (s *jwksSource) load(ctx context.Context) ([]jwkKey, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(s.source, "http://") || strings.HasPrefix(s.source, "https://") {
+		data, err = fetchJWKS(ctx, s.source)
+	} else {
+		data, err = os.ReadFile(s.source)
+		if err != nil {
+			err = fmt.Errorf("failed to read JWKS: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(data)
+}
+
+// get returns the keys, reloading them if they are older than maxAge
+//
+// If the reload fails the old keys are used if there are any.
+func (s *jwksSource) get(ctx context.Context, maxAge time.Duration) ([]jwkKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys != nil && time.Since(s.loaded) < maxAge {
+		return s.keys, nil
+	}
+	keys, err := s.load(ctx)
+	s.loaded = time.Now()
+	if err != nil {
+		if s.keys != nil {
+			fs.Errorf(nil, "JWT: using old keys: %v", err)
+			return s.keys, nil
+		}
+		return nil, err
+	}
+	fs.Debugf(nil, "JWT: loaded %d keys from %q", len(keys), s.source)
+	s.keys = keys
+	return keys, nil
+}
+
+// findKey finds the key the token was signed with
+func findKey(keys []jwkKey, token *jwt.Token) (crypto.PublicKey, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(keys) == 1 {
+		return keys[0].key, nil
+	}
+	for _, k := range keys {
+		if k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q is for %s not %s", kid, k.alg, token.Method.Alg())
+		}
+		return k.key, nil
+	}
+	return nil, errUnknownKeyID
+}
+
+// keyFunc returns the key to check the signature of token with
+func (s *jwksSource) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		keys, err := s.get(ctx, jwksRefresh)
+		if err != nil {
+			return nil, err
+		}
+		key, err := findKey(keys, token)
+		if errors.Is(err, errUnknownKeyID) {
+			// The keys may have been rotated so try loading them again
+			keys, err = s.get(ctx, jwksMinRefresh)
+			if err != nil {
+				return nil, err
+			}
+			key, err = findKey(keys, token)
+		}
+		return key, err
+	}
+}
+
+// jwtValidator checks JWT bearer tokens
+type jwtValidator struct {
+	keys      *jwksSource
+	issuer    string // if set the iss claim must match
+	audience  string // if set the aud claim must contain this
+	userClaim string // claim to read the user name from
+}
+
+// newJWTValidator makes a validator from the auth config
+func newJWTValidator(cfg *AuthConfig) *jwtValidator {
+	userClaim := cfg.JWTUserClaim
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+	return &jwtValidator{
+		keys:      &jwksSource{source: cfg.JWTJWKS},
+		issuer:    cfg.JWTIssuer,
+		audience:  cfg.JWTAudience,
+		userClaim: userClaim,
+	}
+}
+
+// claimString looks up a claim which may be nested using dots, eg
+// "realm.user", returning it as a string
+func claimString(claims jwt.MapClaims, name string) (string, bool) {
+	var value any = map[string]any(claims)
+	for part := range strings.SplitSeq(name, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		value, ok = m[part]
+		if !ok {
+			return "", false
+		}
+	}
+	s, ok := value.(string)
+	return s, ok && s != ""
+}
+
+// validate checks the token returning the user name from it
+func (v *jwtValidator) validate(ctx context.Context, tokenString string) (user string, err error) {
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, v.keys.keyFunc(ctx), jwt.WithValidMethods(jwtValidMethods))
+	if err != nil {
+		return "", err
+	}
+	// The parser only checks exp if it is present
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return "", errors.New("token has no expiry")
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return "", errors.New("token has wrong issuer")
+	}
+	if v.audience != "" && !claims.VerifyAudience(v.audience, true) {
+		return "", errors.New("token has wrong audience")
+	}
+	user, ok := claimString(claims, v.userClaim)
+	if !ok {
+		return "", fmt.Errorf("token has no %q claim", v.userClaim)
+	}
+	return user, nil
+}
+
+// parseBearer returns the token from an Authorization: Bearer header
+func parseBearer(r *http.Request) (token string, ok bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// MiddlewareAuthJWT instantiates middleware that authenticates JWT
+// bearer tokens against the keys, issuer and audience in cfg, taking
+// the user name from a claim in the token.
+func MiddlewareAuthJWT(cfg *AuthConfig) Middleware {
+	fs.Infof(nil, "Using JWT bearer tokens with keys from %q", cfg.JWTJWKS)
+	if cfg.JWTAudience == "" {
+		fs.Logf(nil, "Warning: --jwt-audience is not set so tokens the provider issued for any application will be accepted")
+	}
+	v := newJWTValidator(cfg)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip auth for CORS preflight
+			if r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := parseBearer(r)
+			if ok {
+				user, err := v.validate(r.Context(), token)
+				if err == nil {
+					r = r.WithContext(context.WithValue(r.Context(), ctxKeyUser, user))
+					next.ServeHTTP(w, r)
+					return
+				}
+				fs.Infof(r.URL.Path, "%s: Bearer token rejected: %v", r.RemoteAddr, err)
+			}
+
+			code := http.StatusUnauthorized
+			w.Header().Set("Content-Type", "text/plain")
+			challenge := fmt.Sprintf("Bearer realm=%q", cfg.Realm)
+			if ok {
+				challenge += `, error="invalid_token"`
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, http.StatusText(code), code)
+		})
+	}
+}
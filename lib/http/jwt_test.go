@@ -0,0 +1,277 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// b64 encodes b as base64url without padding as used in JWKs
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// testJWK makes the JWK for a public key
+func testJWK(t *testing.T, kid string, key any) map[string]string {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return map[string]string{"kty": "EC", "kid": kid, "crv": k.Curve.Params().Name, "x": b64(k.X.FillBytes(make([]byte, size))), "y": b64(k.Y.FillBytes(make([]byte, size)))}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k)}
+	}
+	t.Fatalf("unknown key type %T", key)
+	return nil
+}
+
+// testJWKS makes a JSON Web Key Set from the JWKs passed in
+func testJWKS(t *testing.T, keys ...map[string]string) []byte {
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+// testToken makes a signed token
+func testToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	badEC := testJWK(t, "bad", &ecKey.PublicKey)
+	badEC["y"] = badEC["x"]
+	enc := testJWK(t, "enc", &rsaKey.PublicKey)
+	enc["use"] = "enc"
+
+	keys, err := parseJWKS(testJWKS(t,
+		testJWK(t, "rsa", &rsaKey.PublicKey),
+		testJWK(t, "ec", &ecKey.PublicKey),
+		testJWK(t, "ed", edPub),
+		badEC,
+		enc,
+		map[string]string{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+	))
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.Equal(t, "rsa", keys[0].kid)
+	assert.True(t, rsaKey.PublicKey.Equal(keys[0].key))
+	assert.Equal(t, "ec", keys[1].kid)
+	assert.True(t, ecKey.PublicKey.Equal(keys[1].key))
+	assert.Equal(t, "ed", keys[2].kid)
+	assert.True(t, edPub.Equal(keys[2].key))
+
+	_, err = parseJWKS([]byte(`{"keys":[]}`))
+	assert.ErrorContains(t, err, "no usable keys")
+	_, err = parseJWKS([]byte(`potato`))
+	assert.ErrorContains(t, err, "failed to parse JWKS")
+}
+
+func TestClaimString(t *testing.T) {
+	claims := jwt.MapClaims{
+		"sub":   "subject",
+		"num":   float64(1),
+		"realm": map[string]any{"user": "nested"},
+	}
+	for _, test := range []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"sub", "subject", true},
+		{"realm.user", "nested", true},
+		{"realm.missing", "", false},
+		{"sub.user", "", false},
+		{"num", "", false},
+		{"missing", "", false},
+	} {
+		got, ok := claimString(claims, test.name)
+		assert.Equal(t, test.wantOK, ok, test.name)
+		assert.Equal(t, test.want, got, test.name)
+	}
+}
+
+func TestMiddlewareAuthJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, testJWKS(t,
+		testJWK(t, "rsa", &rsaKey.PublicKey),
+		testJWK(t, "ec", &ecKey.PublicKey),
+		testJWK(t, "ed", edPub),
+	), 0666))
+
+	s, err := NewServer(context.Background(), WithConfig(Config{
+		ListenAddr: []string{"127.0.0.1:0"},
+	}), WithAuth(AuthConfig{
+		Realm:        "test",
+		JWTJWKS:      jwksFile,
+		JWTIssuer:    "https://issuer.example.com",
+		JWTAudience:  "rclone",
+		JWTUserClaim: "preferred_username",
+	}))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Shutdown())
+	}()
+	s.Router().Mount("/", testAuthUserHandler())
+	s.Serve()
+	assert.True(t, s.UsingAuth())
+	url := testGetServerURL(t, s)
+
+	claims := func(edit func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":                "https://issuer.example.com",
+			"aud":                []string{"other", "rclone"},
+			"sub":                "1234",
+			"preferred_username": "alice",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+		}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name     string
+		token    string
+		wantUser string
+	}{
+		{"RS256", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(nil)), "alice"},
+		{"PS256", testToken(t, jwt.SigningMethodPS256, "rsa", rsaKey, claims(nil)), "alice"},
+		{"ES256", testToken(t, jwt.SigningMethodES256, "ec", ecKey, claims(nil)), "alice"},
+		{"EdDSA", testToken(t, jwt.SigningMethodEdDSA, "ed", edKey, claims(nil)), "alice"},
+		{"WrongKey", testToken(t, jwt.SigningMethodRS256, "rsa", otherKey, claims(nil)), ""},
+		{"UnknownKeyID", testToken(t, jwt.SigningMethodRS256, "other", rsaKey, claims(nil)), ""},
+		{"HMAC", testToken(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), claims(nil)), ""},
+		{"Expired", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+		})), ""},
+		{"NoExpiry", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			delete(c, "exp")
+		})), ""},
+		{"NotBefore", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			c["nbf"] = time.Now().Add(time.Hour).Unix()
+		})), ""},
+		{"WrongIssuer", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			c["iss"] = "https://evil.example.com"
+		})), ""},
+		{"WrongAudience", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			c["aud"] = "other"
+		})), ""},
+		{"NoUserClaim", testToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			delete(c, "preferred_username")
+		})), ""},
+		{"Garbage", "potato", ""},
+		{"NoToken", "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", url, nil)
+			require.NoError(t, err)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			if test.wantUser == "" {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+				assert.Contains(t, resp.Header.Get("WWW-Authenticate"), `Bearer realm="test"`)
+				return
+			}
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			testExpectRespBody(t, resp, []byte(test.wantUser))
+		})
+	}
+}
+
+func TestJWTWithBasicAuth(t *testing.T) {
+	_, err := NewServer(context.Background(), WithConfig(Config{
+		ListenAddr: []string{"127.0.0.1:0"},
+	}), WithAuth(AuthConfig{
+		JWTJWKS:   "jwks.json",
+		BasicUser: "user",
+		BasicPass: "pass",
+	}))
+	assert.ErrorContains(t, err, "JWT bearer token auth can't be used")
+}
+
+func TestJWKSRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var (
+		jwks    atomic.Value
+		fetches atomic.Int32
+	)
+	jwks.Store(testJWKS(t, testJWK(t, "old", &oldKey.PublicKey)))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write(jwks.Load().([]byte))
+	}))
+	defer ts.Close()
+
+	v := newJWTValidator(&AuthConfig{JWTJWKS: ts.URL})
+	ctx := context.Background()
+	claims := jwt.MapClaims{"sub": "bob", "exp": time.Now().Add(time.Hour).Unix()}
+
+	user, err := v.validate(ctx, testToken(t, jwt.SigningMethodRS256, "old", oldKey, claims))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// A new key isn't found until the keys are old enough to reload
+	jwks.Store(testJWKS(t, testJWK(t, "new", &newKey.PublicKey)))
+	newToken := testToken(t, jwt.SigningMethodRS256, "new", newKey, claims)
+	_, err = v.validate(ctx, newToken)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	v.keys.loaded = time.Now().Add(-2 * jwksMinRefresh)
+	user, err = v.validate(ctx, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+	assert.Equal(t, int32(2), fetches.Load())
+}
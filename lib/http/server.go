@@ -335,7 +335,10 @@ func NewServer(ctx context.Context, options ...Option) (*Server, error) {
 
 	s.mux.Use(MiddlewareCORS(s.cfg.AllowOrigin))
 
-	s.initAuth()
+	err = s.initAuth()
+	if err != nil {
+		return nil, err
+	}
 
 	// (Only) listen on FDs provided by the service manager, if any.
 	sdListeners, err := sdActivation.ListenersWithNames()
@@ -396,13 +399,19 @@ func NewServer(ctx context.Context, options ...Option) (*Server, error) {
 	return s, nil
 }
 
-func (s *Server) initAuth() {
+func (s *Server) initAuth() error {
 	s.usingAuth = false
 	altUsernameEnabled := s.auth.HtPasswd == "" && s.auth.BasicUser == ""
 
+	if s.auth.JWTJWKS != "" && !altUsernameEnabled {
+		return errors.New("JWT bearer token auth can't be used with htpasswd or user and password auth")
+	}
+
 	if altUsernameEnabled {
 		s.usingAuth = true
-		if s.auth.UserFromHeader != "" {
+		if s.auth.JWTJWKS != "" {
+			s.mux.Use(MiddlewareAuthJWT(&s.auth))
+		} else if s.auth.UserFromHeader != "" {
 			s.mux.Use(MiddlewareAuthGetUserFromHeader(s.auth.UserFromHeader))
 		} else if s.tlsConfig != nil && s.tlsConfig.ClientAuth != tls.NoClientCert {
 			s.mux.Use(MiddlewareAuthCertificateUser())
@@ -415,20 +424,20 @@ func (s *Server) initAuth() {
 	if s.auth.CustomAuthFn != nil {
 		s.usingAuth = true
 		s.mux.Use(MiddlewareAuthCustom(s.auth.CustomAuthFn, s.auth.Realm, altUsernameEnabled))
-		return
+		return nil
 	}
 
 	if s.auth.HtPasswd != "" {
 		s.usingAuth = true
 		s.mux.Use(MiddlewareAuthHtpasswd(s.auth.HtPasswd, s.auth.Realm))
-		return
+		return nil
 	}
 
 	if s.auth.BasicUser != "" {
 		s.usingAuth = true
 		s.mux.Use(MiddlewareAuthBasic(s.auth.BasicUser, s.auth.BasicPass, s.auth.Realm, s.auth.Salt))
-		return
 	}
+	return nil
 }
 
 func (s *Server) initTemplate() error {
@@ -2,6 +2,7 @@
 package pacer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime"
@@ -35,18 +36,32 @@ type Pacer struct {
 	pacer      chan struct{} // To pace the operations
 	connTokens chan struct{} // Connection tokens
 	state      State
+	metrics    *pacerMetrics // metrics for this pacer if enabled
 }
 type pacerOptions struct {
 	maxConnections int         // Maximum number of concurrent connections
 	retries        int         // Max number of retries
 	calculator     Calculator  // switchable pacing algorithm - call with mu held
 	invoker        InvokerFunc // wrapper function used to invoke the target function
+	name           string      // name of the pacer for the metrics
+	tracer         Tracer      // records the waits and retries of calls if set
 }
 
 // InvokerFunc is the signature of the wrapper function used to invoke the
 // target function in Pacer.
 type InvokerFunc func(try, tries int, f Paced) (bool, error)
 
+// Tracer records the time spent waiting for the pacer and the
+// retries of the calls made with CallContext and CallNoRetryContext,
+// for example in the tracing span in ctx.
+type Tracer interface {
+	// Wait records a wait for the pacer
+	Wait(ctx context.Context, wait time.Duration)
+	// Retry records that the call will be retried for the try'th
+	// time because of err
+	Retry(ctx context.Context, try int, err error)
+}
+
 // Option can be used in New to configure the Pacer.
 type Option func(*pacerOptions)
 
@@ -70,6 +85,17 @@ func InvokerOption(invoker InvokerFunc) Option {
 	return func(p *pacerOptions) { p.invoker = invoker }
 }
 
+// NameOption sets the name used to label the metrics of the new Pacer,
+// for example the name of the remote it is for.
+func NameOption(name string) Option {
+	return func(p *pacerOptions) { p.name = name }
+}
+
+// TracerOption sets a Tracer for the new Pacer.
+func TracerOption(tracer Tracer) Option {
+	return func(p *pacerOptions) { p.tracer = tracer }
+}
+
 // Paced is a function which is called by the Call and CallNoRetry
 // methods.  It should return a boolean, true if it would like to be
 // retried, and an error.  This error may be returned or returned
@@ -88,6 +114,7 @@ func New(options ...Option) *Pacer {
 	p := &Pacer{
 		pacerOptions: opts,
 		pacer:        make(chan struct{}, 1),
+		metrics:      DefaultMetrics.forPacer(opts.name),
 	}
 	if p.calculator == nil {
 		p.SetCalculator(nil)
@@ -154,16 +181,23 @@ func (p *Pacer) ModifyCalculator(f func(Calculator)) {
 //
 // This must be called as a pair with endCall.
 //
-// This waits for the pacer token
-func (p *Pacer) beginCall(limitConnections bool) {
+// This waits for the pacer token, recording the wait with the Tracer
+// if set.
+func (p *Pacer) beginCall(ctx context.Context, limitConnections bool) {
 	// pacer starts with a token in and whenever we take one out
 	// XXX ms later we put another in.  We could do this with a
 	// Ticker more accurately, but then we'd have to work out how
 	// not to run it when it wasn't needed
+	start := time.Now()
 	<-p.pacer
 	if limitConnections {
 		<-p.connTokens
 	}
+	wait := time.Since(start)
+	p.metrics.onWait(wait)
+	if p.tracer != nil {
+		p.tracer.Wait(ctx, wait)
+	}
 
 	p.mu.Lock()
 	// Restart the timer
@@ -221,19 +255,27 @@ func pacerReentered() bool {
 }
 
 // call implements Call but with settable retries
-func (p *Pacer) call(fn Paced, retries int) (err error) {
+//
+// The waits and retries are recorded with the Tracer if set.
+func (p *Pacer) call(ctx context.Context, fn Paced, retries int) (err error) {
 	var retry bool
 	limitConnections := false
 	if p.maxConnections > 0 && !pacerReentered() {
 		limitConnections = true
 	}
 	for i := 1; i <= retries; i++ {
-		p.beginCall(limitConnections)
+		p.beginCall(ctx, limitConnections)
 		retry, err = p.invoker(i, retries, fn)
 		p.endCall(retry, err, limitConnections)
 		if !retry {
 			break
 		}
+		if i < retries {
+			p.metrics.onRetry()
+			if p.tracer != nil {
+				p.tracer.Retry(ctx, i+1, err)
+			}
+		}
 	}
 	return err
 }
@@ -245,10 +287,19 @@ func (p *Pacer) call(fn Paced, retries int) (err error) {
 // error. This error may be returned wrapped in a RetryError if the
 // number of retries is exceeded.
 func (p *Pacer) Call(fn Paced) (err error) {
+	return p.CallContext(context.Background(), fn)
+}
+
+// CallContext is like Call but passes ctx to the Tracer which records
+// the time spent waiting for the pacer and the retries.
+//
+// Backends should use this with the context of the operation so the
+// waits and retries are recorded in its tracing span.
+func (p *Pacer) CallContext(ctx context.Context, fn Paced) (err error) {
 	p.mu.Lock()
 	retries := p.retries
 	p.mu.Unlock()
-	return p.call(fn, retries)
+	return p.call(ctx, fn, retries)
 }
 
 // CallNoRetry paces the remote operations to not exceed the limits
@@ -257,7 +308,13 @@ func (p *Pacer) Call(fn Paced) (err error) {
 // This calls fn and wraps the output in a RetryError if it would like
 // it to be retried
 func (p *Pacer) CallNoRetry(fn Paced) error {
-	return p.call(fn, 1)
+	return p.CallNoRetryContext(context.Background(), fn)
+}
+
+// CallNoRetryContext is like CallNoRetry but passes ctx to the Tracer
+// which records the time spent waiting for the pacer.
+func (p *Pacer) CallNoRetryContext(ctx context.Context, fn Paced) error {
+	return p.call(ctx, fn, 1)
 }
 
 func invoke(try, tries int, f Paced) (bool, error) {
@@ -1,6 +1,7 @@
 package pacer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -108,7 +110,7 @@ func waitForPace(p *Pacer, duration time.Duration) (when time.Time) {
 func TestBeginCall(t *testing.T) {
 	p := New(MaxConnectionsOption(10), CalculatorOption(NewDefault(MinSleep(1*time.Millisecond))))
 	emptyTokens(p)
-	go p.beginCall(true)
+	go p.beginCall(context.Background(), true)
 	if !waitForPace(p, 10*time.Millisecond).IsZero() {
 		t.Errorf("beginSleep fired too early #1")
 	}
@@ -131,7 +133,7 @@ func TestBeginCall(t *testing.T) {
 func TestBeginCallZeroConnections(t *testing.T) {
 	p := New(MaxConnectionsOption(0), CalculatorOption(NewDefault(MinSleep(1*time.Millisecond))))
 	emptyTokens(p)
-	go p.beginCall(false)
+	go p.beginCall(context.Background(), false)
 	if !waitForPace(p, 10*time.Millisecond).IsZero() {
 		t.Errorf("beginSleep fired too early #1")
 	}
@@ -295,7 +297,7 @@ func TestCallFixed(t *testing.T) {
 	p := New(CalculatorOption(NewDefault(MinSleep(1*time.Millisecond), MaxSleep(2*time.Millisecond))))
 
 	dp := &dummyPaced{retry: false}
-	err := p.call(dp.fn, 10)
+	err := p.call(context.Background(), dp.fn, 10)
 	assert.Equal(t, 1, dp.called)
 	assert.Equal(t, errFoo, err)
 }
@@ -304,7 +306,7 @@ func Test_callRetry(t *testing.T) {
 	p := New(CalculatorOption(NewDefault(MinSleep(1*time.Millisecond), MaxSleep(2*time.Millisecond))))
 
 	dp := &dummyPaced{retry: true}
-	err := p.call(dp.fn, 10)
+	err := p.call(context.Background(), dp.fn, 10)
 	assert.Equal(t, 10, dp.called)
 	assert.Equal(t, errFoo, err)
 }
@@ -318,6 +320,37 @@ func TestCall(t *testing.T) {
 	assert.Equal(t, errFoo, err)
 }
 
+// testTracer records the calls to the Tracer
+type testTracer struct {
+	mu      sync.Mutex
+	waits   int
+	retries []int
+}
+
+func (t *testTracer) Wait(ctx context.Context, wait time.Duration) {
+	t.mu.Lock()
+	t.waits++
+	t.mu.Unlock()
+}
+
+func (t *testTracer) Retry(ctx context.Context, try int, err error) {
+	t.mu.Lock()
+	t.retries = append(t.retries, try)
+	t.mu.Unlock()
+}
+
+func TestCallContextTracer(t *testing.T) {
+	tracer := &testTracer{}
+	p := New(RetriesOption(3), TracerOption(tracer), CalculatorOption(NewDefault(MinSleep(1*time.Millisecond), MaxSleep(2*time.Millisecond))))
+
+	dp := &dummyPaced{retry: true}
+	err := p.CallContext(context.Background(), dp.fn)
+	assert.Equal(t, 3, dp.called)
+	assert.Equal(t, errFoo, err)
+	assert.Equal(t, 3, tracer.waits)
+	assert.Equal(t, []int{2, 3}, tracer.retries)
+}
+
 func TestCallParallel(t *testing.T) {
 	p := New(MaxConnectionsOption(3), RetriesOption(1), CalculatorOption(NewDefault(MinSleep(100*time.Microsecond), MaxSleep(1*time.Millisecond))))
 
@@ -466,3 +499,26 @@ func TestIsRetryAfter_False(t *testing.T) {
 		t.Error("IsRetryAfter = true for non-retry error, want false")
 	}
 }
+
+func TestMetrics(t *testing.T) {
+	oldMetrics := DefaultMetrics
+	DefaultMetrics = NewMetrics("test")
+	defer func() {
+		DefaultMetrics = oldMetrics
+	}()
+	p := New(NameOption("remote"), RetriesOption(5), CalculatorOption(NewDefault(MinSleep(1*time.Millisecond), MaxSleep(2*time.Millisecond))))
+
+	dp := &dummyPaced{retry: true}
+	err := p.Call(dp.fn)
+	assert.Equal(t, 5, dp.called)
+	assert.Equal(t, errFoo, err)
+
+	dp = &dummyPaced{retry: false}
+	err = p.Call(dp.fn)
+	assert.Equal(t, 1, dp.called)
+	assert.Equal(t, errFoo, err)
+
+	assert.Equal(t, 4.0, testutil.ToFloat64(DefaultMetrics.Retries.WithLabelValues("remote")))
+	assert.Greater(t, testutil.ToFloat64(DefaultMetrics.WaitTime.WithLabelValues("remote")), 0.0)
+	assert.Equal(t, 0.0, testutil.ToFloat64(DefaultMetrics.Retries.WithLabelValues("other")))
+}
@@ -0,0 +1,80 @@
+package pacer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics provide Pacer level metrics labelled with the name of the
+// Pacer.
+type Metrics struct {
+	Retries  *prometheus.CounterVec
+	WaitTime *prometheus.CounterVec
+}
+
+// NewMetrics creates a new metrics instance, the instance shall be assigned to
+// DefaultMetrics before any processing takes place.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		Retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pacer",
+			Name:      "retries_total",
+			Help:      "Number of calls retried by the pacer",
+		}, []string{"remote"}),
+		WaitTime: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pacer",
+			Name:      "wait_seconds_total",
+			Help:      "Total time calls spent waiting for the pacer including backoff after retries",
+		}, []string{"remote"}),
+	}
+}
+
+// DefaultMetrics specifies metrics used for new Pacers.
+var DefaultMetrics = (*Metrics)(nil)
+
+// Collectors returns all prometheus metrics as collectors for registration.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	if m == nil {
+		return nil
+	}
+	return []prometheus.Collector{
+		m.Retries,
+		m.WaitTime,
+	}
+}
+
+// pacerMetrics are the metrics for a single Pacer
+//
+// A nil *pacerMetrics is valid and records nothing.
+type pacerMetrics struct {
+	retries  prometheus.Counter
+	waitTime prometheus.Counter
+}
+
+// forPacer returns the metrics for the Pacer called name
+func (m *Metrics) forPacer(name string) *pacerMetrics {
+	if m == nil {
+		return nil
+	}
+	return &pacerMetrics{
+		retries:  m.Retries.WithLabelValues(name),
+		waitTime: m.WaitTime.WithLabelValues(name),
+	}
+}
+
+func (m *pacerMetrics) onRetry() {
+	if m == nil {
+		return
+	}
+	m.retries.Inc()
+}
+
+func (m *pacerMetrics) onWait(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.waitTime.Add(d.Seconds())
+}
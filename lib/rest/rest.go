@@ -18,7 +18,9 @@ import (
 	"sync"
 
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/tracing"
 	"github.com/rclone/rclone/lib/readers"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Client contains the info to sustain the API
@@ -260,6 +262,13 @@ func (api *Client) Call(ctx context.Context, opts *Opts) (resp *http.Response, e
 	if opts == nil {
 		return nil, errors.New("call() called with nil opts")
 	}
+	ctx, span := tracing.StartSpan(ctx, "REST "+opts.Method,
+		attribute.String("http.request.method", opts.Method),
+		attribute.String("rclone.rest.path", opts.Path),
+	)
+	defer func() {
+		tracing.End(span, err)
+	}()
 	url := api.rootURL
 	if opts.RootURL != "" {
 		url = opts.RootURL
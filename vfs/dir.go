@@ -1003,6 +1003,9 @@ func (d *Dir) Stat(name string) (node Node, err error) {
 // ReadDirAll reads the contents of the directory sorted
 func (d *Dir) ReadDirAll() (items Nodes, err error) {
 	// fs.Debugf(d.path, "Dir.ReadDirAll")
+	if err = d.vfs.checkPerm(PermList); err != nil {
+		return nil, err
+	}
 	d.mu.Lock()
 	err = d._readDir()
 	if err != nil {
@@ -1055,6 +1058,9 @@ func (d *Dir) Create(name string, flags int) (*File, error) {
 	if d.vfs.Opt.ReadOnly {
 		return nil, EROFS
 	}
+	if err = d.vfs.checkPerm(PermWrite); err != nil {
+		return nil, err
+	}
 	if err = d.SetModTime(time.Now()); err != nil {
 		fs.Errorf(d, "Dir.Create failed to set modtime on parent dir: %v", err)
 		return nil, err
@@ -1068,6 +1074,9 @@ func (d *Dir) Mkdir(name string) (*Dir, error) {
 	if d.vfs.Opt.ReadOnly {
 		return nil, EROFS
 	}
+	if err := d.vfs.checkPerm(PermWrite); err != nil {
+		return nil, err
+	}
 	path := path.Join(d.path, name)
 	node, err := d.stat(name)
 	switch err {
@@ -1106,6 +1115,9 @@ func (d *Dir) Remove() error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.checkPerm(PermDelete); err != nil {
+		return err
+	}
 	// Check directory is empty first
 	empty, err := d.isEmpty()
 	if err != nil {
@@ -1134,6 +1146,9 @@ func (d *Dir) RemoveAll() error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.checkPerm(PermDelete); err != nil {
+		return err
+	}
 	// Remove contents of the directory
 	nodes, err := d.ReadDirAll()
 	if err != nil {
@@ -1162,6 +1177,9 @@ func (d *Dir) RemoveName(name string) error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.checkPerm(PermDelete); err != nil {
+		return err
+	}
 	// fs.Debugf(path, "Dir.Remove")
 	node, err := d.stat(name)
 	if err != nil {
@@ -1181,6 +1199,15 @@ func (d *Dir) Rename(oldName, newName string, destDir *Dir) error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.checkPerm(PermWrite); err != nil {
+		return err
+	}
+	// Renaming onto an existing entry replaces it so needs delete too
+	if d.vfs.checkPerm(PermDelete) != nil {
+		if _, err := destDir.stat(newName); err == nil {
+			return EPERM
+		}
+	}
 	oldPath := path.Join(d.path, oldName)
 	newPath := path.Join(destDir.path, newName)
 	// fs.Debugf(oldPath, "Dir.Rename to %q", newPath)
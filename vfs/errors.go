@@ -21,6 +21,7 @@ const (
 	EROFS
 	ENOSYS
 	ELOOP
+	ENOSPC
 )
 
 // Errors which have exact counterparts in os
@@ -40,6 +41,7 @@ var errorNames = []string{
 	EROFS:     "Read only file system",
 	ENOSYS:    "Function not implemented",
 	ELOOP:     "Too many symbolic links",
+	ENOSPC:    "No space left on device",
 }
 
 // Error renders the error as a string
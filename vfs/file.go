@@ -272,6 +272,9 @@ func (f *File) rename(ctx context.Context, destDir *Dir, newName string) error {
 				fs.Errorf(f.Path(), "File.Rename error: %v", err)
 				return err
 			}
+			if dstOverwritten != nil {
+				d.vfs.quotaRemoved(dstOverwritten.Size())
+			}
 
 			// newObject can be nil here for example if --dry-run
 			if newObject == nil {
@@ -462,6 +465,9 @@ func (f *File) SetModTime(modTime time.Time) error {
 	if f.d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := f.d.vfs.checkPerm(PermWrite); err != nil {
+		return err
+	}
 
 	f.pendingModTime = modTime
 
@@ -672,6 +678,9 @@ func (f *File) Remove() (err error) {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err = d.vfs.checkPerm(PermDelete); err != nil {
+		return err
+	}
 
 	// Remove the object from the cache
 	wasWriting := false
@@ -679,6 +688,7 @@ func (f *File) Remove() (err error) {
 		wasWriting = d.vfs.cache.Remove(f.CachePath())
 	}
 
+	size := f.Size()
 	f.muRW.Lock() // muRW must be locked before mu to avoid
 	f.mu.Lock()   // deadlock in RWFileHandle.openPending and .close
 	if f.o != nil {
@@ -700,6 +710,7 @@ func (f *File) Remove() (err error) {
 	// called with File.mu released when there is no error removing the underlying file
 	if err == nil {
 		d.delObject(f.Name())
+		d.vfs.quotaRemoved(size)
 	}
 	return err
 }
@@ -868,6 +879,25 @@ func (f *File) Open(flags int) (fd Handle, err error) {
 		return nil, EPERM
 	}
 
+	// Check the access asked for is permitted. Reading a file which
+	// is being truncated doesn't reveal its contents.
+	if read && flags&os.O_TRUNC == 0 {
+		if err = f.VFS().checkPerm(PermRead); err != nil {
+			return nil, err
+		}
+	}
+	if write || flags&(os.O_TRUNC|os.O_CREATE) != 0 {
+		if err = f.VFS().checkPerm(PermWrite); err != nil {
+			return nil, err
+		}
+	}
+	// Truncating an existing file throws its contents away
+	if flags&os.O_TRUNC != 0 && f.exists() {
+		if err = f.VFS().checkPerm(PermDelete); err != nil {
+			return nil, err
+		}
+	}
+
 	// If append is set then set read to force openRW
 	if flags&os.O_APPEND != 0 {
 		read = true
@@ -0,0 +1,222 @@
+// Per user limits on what a VFS may do
+
+package vfs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+// Permissions is a set of operations which may be done on a VFS
+type Permissions uint8
+
+// Permissions which can be granted
+const (
+	PermRead   Permissions = 1 << iota // open files for reading
+	PermList                           // read directory listings
+	PermWrite                          // create, modify and rename files and directories
+	PermDelete                         // remove files and directories
+	PermNone   Permissions = 0
+	PermAll                = PermRead | PermList | PermWrite | PermDelete
+)
+
+var permissionNames = []struct {
+	name string
+	perm Permissions
+}{
+	{"read", PermRead},
+	{"list", PermList},
+	{"write", PermWrite},
+	{"delete", PermDelete},
+}
+
+// Named sets of permissions
+var permissionPresets = map[string]Permissions{
+	"all":         PermAll,
+	"none":        PermNone,
+	"read-only":   PermRead | PermList,
+	"upload-only": PermWrite,
+}
+
+// ParsePermissions parses a comma separated list of permissions
+// (read, list, write, delete) or presets (all, none, read-only,
+// upload-only).
+func ParsePermissions(s string) (perms Permissions, err error) {
+	for name := range strings.SplitSeq(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if preset, ok := permissionPresets[name]; ok {
+			perms |= preset
+			continue
+		}
+		found := false
+		for _, p := range permissionNames {
+			if p.name == name {
+				perms |= p.perm
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown permission %q", name)
+		}
+	}
+	return perms, nil
+}
+
+// String turns the permissions into a comma separated list
+func (perms Permissions) String() string {
+	var names []string
+	for _, p := range permissionNames {
+		if perms&p.perm != 0 {
+			names = append(names, p.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
+// SetLimits restricts the operations allowed on the VFS to perms and
+// the total size of the files in it to quota bytes. A negative quota
+// means no quota.
+//
+// This should be called before the VFS is used.
+func (vfs *VFS) SetLimits(perms Permissions, quota int64) {
+	vfs.perms = perms
+	vfs.quota = quota
+}
+
+// Limits returns the permissions and quota set with SetLimits
+func (vfs *VFS) Limits() (perms Permissions, quota int64) {
+	return vfs.perms, vfs.quota
+}
+
+// checkPerm returns EPERM unless all of perm are allowed
+func (vfs *VFS) checkPerm(perm Permissions) error {
+	if vfs.perms&perm != perm {
+		return EPERM
+	}
+	return nil
+}
+
+// refreshQuota reads the size of the files from the remote if it
+// hasn't been read in the last DirCacheTime.
+//
+// The remote is walked in the background without quotaMu held. Only
+// the first read is waited for - after that the previous usage is
+// used until the new one is ready.
+func (vfs *VFS) refreshQuota() {
+	vfs.quotaMu.Lock()
+	haveUsage := !vfs.quotaTime.IsZero()
+	if haveUsage && time.Since(vfs.quotaTime) < time.Duration(vfs.Opt.DirCacheTime) {
+		vfs.quotaMu.Unlock()
+		return
+	}
+	reading := vfs.quotaReading
+	if reading == nil {
+		reading = make(chan struct{})
+		vfs.quotaReading = reading
+		go vfs.readQuota(reading)
+	}
+	vfs.quotaMu.Unlock()
+	if !haveUsage {
+		<-reading
+	}
+}
+
+// readQuota walks the remote to find the bytes in use, closing done
+// when finished.
+func (vfs *VFS) readQuota(done chan struct{}) {
+	defer close(done)
+	vfs.quotaMu.Lock()
+	delta := vfs.quotaDelta
+	vfs.quotaMu.Unlock()
+
+	var used int64
+	err := walk.ListR(vfs.ctx, vfs.f, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		entries.ForObject(func(o fs.Object) {
+			used += o.Size()
+		})
+		return nil
+	})
+
+	vfs.quotaMu.Lock()
+	defer vfs.quotaMu.Unlock()
+	if errors.Is(err, fs.ErrorDirNotFound) {
+		// Nothing has been written yet
+		err = nil
+	}
+	if err != nil {
+		fs.Errorf(vfs.f, "Failed to read quota usage: %v", err)
+	} else {
+		vfs.quotaUsed = used
+		// Keep the changes made during the walk as it may not
+		// have seen them
+		vfs.quotaDelta -= delta
+	}
+	vfs.quotaTime = time.Now()
+	vfs.quotaReading = nil
+}
+
+// quotaUsage returns the bytes in use counted against the quota
+//
+// The size of the files is read from the remote every DirCacheTime
+// and the bytes written and removed since are added to it.
+func (vfs *VFS) quotaUsage() int64 {
+	vfs.refreshQuota()
+	vfs.quotaMu.Lock()
+	defer vfs.quotaMu.Unlock()
+	return vfs.quotaUsed + vfs.quotaDelta
+}
+
+// checkQuota returns ENOSPC if writing n more bytes would exceed the
+// quota, otherwise it accounts for them.
+func (vfs *VFS) checkQuota(n int64) error {
+	if vfs.quota < 0 || n <= 0 {
+		return nil
+	}
+	vfs.refreshQuota()
+	vfs.quotaMu.Lock()
+	defer vfs.quotaMu.Unlock()
+	if vfs.quotaUsed+vfs.quotaDelta+n > vfs.quota {
+		return ENOSPC
+	}
+	vfs.quotaDelta += n
+	return nil
+}
+
+// quotaRemoved accounts for n bytes being removed from the remote
+func (vfs *VFS) quotaRemoved(n int64) {
+	if vfs.quota < 0 || n <= 0 {
+		return
+	}
+	// Read the usage first otherwise the first walk drops the credit
+	vfs.refreshQuota()
+	vfs.quotaMu.Lock()
+	vfs.quotaDelta -= n
+	vfs.quotaMu.Unlock()
+}
+
+// quotaRestored accounts for n bytes given back with quotaRemoved
+// which are still on the remote, for example because the upload
+// replacing them failed.
+//
+// Unlike checkQuota this always succeeds as the bytes are in use.
+func (vfs *VFS) quotaRestored(n int64) {
+	if vfs.quota < 0 || n <= 0 {
+		return
+	}
+	vfs.refreshQuota()
+	vfs.quotaMu.Lock()
+	vfs.quotaDelta += n
+	vfs.quotaMu.Unlock()
+}
@@ -0,0 +1,250 @@
+package vfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePermissions(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    Permissions
+		wantStr string
+		wantErr bool
+	}{
+		{in: "", want: PermNone, wantStr: "none"},
+		{in: "all", want: PermAll, wantStr: "read,list,write,delete"},
+		{in: "read-only", want: PermRead | PermList, wantStr: "read,list"},
+		{in: "upload-only", want: PermWrite, wantStr: "write"},
+		{in: " Read , delete", want: PermRead | PermDelete, wantStr: "read,delete"},
+		{in: "read-only,write", want: PermRead | PermList | PermWrite, wantStr: "read,list,write"},
+		{in: "read,potato", wantErr: true},
+	} {
+		got, err := ParsePermissions(test.in)
+		if test.wantErr {
+			assert.Error(t, err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.wantStr, got.String(), test.in)
+	}
+}
+
+func TestVFSPermissions(t *testing.T) {
+	r, vfs := newTestVFS(t)
+	file1 := r.WriteObject(t.Context(), "dir/file1", "file1 contents", t1)
+	r.CheckRemoteItems(t, file1)
+
+	// read only
+	vfs.SetLimits(PermRead|PermList, -1)
+
+	_, err := vfs.ReadDir("dir")
+	require.NoError(t, err)
+	fd, err := vfs.Open("dir/file1")
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	_, err = vfs.OpenFile("dir/file2", os.O_WRONLY|os.O_CREATE, 0777)
+	assert.Equal(t, EPERM, err)
+	assert.Equal(t, EPERM, vfs.Mkdir("dir2", 0777))
+	assert.Equal(t, EPERM, vfs.Remove("dir/file1"))
+	assert.Equal(t, EPERM, vfs.Rename("dir/file1", "dir/file3"))
+
+	// upload only
+	vfs.SetLimits(PermWrite, -1)
+
+	_, err = vfs.ReadDir("dir")
+	assert.Equal(t, EPERM, err)
+	_, err = vfs.Open("dir/file1")
+	assert.Equal(t, EPERM, err)
+	assert.Equal(t, EPERM, vfs.Remove("dir/file1"))
+
+	// Stat still works so clients can check uploads
+	_, err = vfs.Stat("dir/file1")
+	require.NoError(t, err)
+
+	fd, err = vfs.Create("dir/file2")
+	require.NoError(t, err)
+	_, err = fd.WriteString("file2")
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	// Replacing an existing file needs delete
+	vfs.WaitForWriters(waitForWritersDelay)
+	assert.Equal(t, EPERM, vfs.Rename("dir/file2", "dir/file1"))
+	_, err = vfs.OpenFile("dir/file1", os.O_WRONLY|os.O_TRUNC, 0777)
+	assert.Equal(t, EPERM, err)
+
+	// delete as well
+	vfs.SetLimits(PermWrite|PermDelete, -1)
+	require.NoError(t, vfs.Remove("dir/file1"))
+
+	vfs.WaitForWriters(waitForWritersDelay)
+	_, err = r.Fremote.NewObject(t.Context(), "dir/file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	o, err := r.Fremote.NewObject(t.Context(), "dir/file2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), o.Size())
+}
+
+func TestVFSQuota(t *testing.T) {
+	r, vfs := newTestVFS(t)
+	file1 := r.WriteObject(t.Context(), "file1", "0123456789", t1)
+	r.CheckRemoteItems(t, file1)
+
+	vfs.SetLimits(PermAll, 25)
+	perms, quota := vfs.Limits()
+	assert.Equal(t, PermAll, perms)
+	assert.Equal(t, int64(25), quota)
+
+	total, used, free := vfs.Statfs()
+	assert.Equal(t, int64(25), total)
+	assert.Equal(t, int64(10), used)
+	assert.Equal(t, int64(15), free)
+
+	// fits in the quota
+	fd, err := vfs.Create("file2")
+	require.NoError(t, err)
+	_, err = fd.WriteString("0123456789")
+	require.NoError(t, err)
+
+	// doesn't fit
+	_, err = fd.WriteString("0123456789")
+	assert.Equal(t, ENOSPC, err)
+	require.NoError(t, fd.Close())
+
+	total, used, free = vfs.Statfs()
+	assert.Equal(t, int64(25), total)
+	assert.Equal(t, int64(20), used)
+	assert.Equal(t, int64(5), free)
+
+	// removing a file frees up space
+	vfs.WaitForWriters(waitForWritersDelay)
+	require.NoError(t, vfs.Remove("file1"))
+	_, used, _ = vfs.Statfs()
+	assert.Equal(t, int64(10), used)
+}
+
+func TestVFSQuotaFailedWrite(t *testing.T) {
+	r, vfs := newTestVFS(t)
+	file1 := r.WriteObject(t.Context(), "file1", "0123456789", t1)
+	r.CheckRemoteItems(t, file1)
+	vfs.SetLimits(PermAll, 25)
+
+	fd, err := vfs.Create("file2")
+	require.NoError(t, err)
+	_, err = fd.WriteString("0123456789")
+	require.NoError(t, err)
+	_, used, _ := vfs.Statfs()
+	assert.Equal(t, int64(20), used)
+
+	// An upload which fails gives back the quota it used
+	fh, ok := fd.(*WriteFileHandle)
+	require.True(t, ok)
+	require.NoError(t, fh.pipeWriter.CloseWithError(errors.New("aborted")))
+	assert.Error(t, fd.Close())
+	_, used, _ = vfs.Statfs()
+	assert.Equal(t, int64(10), used)
+}
+
+func TestVFSQuotaTruncate(t *testing.T) {
+	opt := vfscommon.Opt
+	opt.CacheMode = vfscommon.CacheModeWrites
+	_, vfs := newTestVFSOpt(t, &opt)
+	vfs.SetLimits(PermAll, 25)
+
+	fd, err := vfs.OpenFile("file1", os.O_RDWR|os.O_CREATE, 0777)
+	require.NoError(t, err)
+	_, err = fd.WriteString("0123456789")
+	require.NoError(t, err)
+
+	// growing the file counts against the quota
+	assert.Equal(t, ENOSPC, fd.Truncate(30))
+	require.NoError(t, fd.Truncate(20))
+	_, used, _ := vfs.Statfs()
+	assert.Equal(t, int64(20), used)
+
+	// and shrinking it gives the space back
+	require.NoError(t, fd.Truncate(5))
+	_, used, _ = vfs.Statfs()
+	assert.Equal(t, int64(5), used)
+	require.NoError(t, fd.Close())
+}
+
+func TestVFSQuotaRewrite(t *testing.T) {
+	for _, cacheMode := range []vfscommon.CacheMode{vfscommon.CacheModeOff, vfscommon.CacheModeWrites} {
+		t.Run(cacheMode.String(), func(t *testing.T) {
+			opt := vfscommon.Opt
+			opt.CacheMode = cacheMode
+			r, vfs := newTestVFSOpt(t, &opt)
+			file1 := r.WriteObject(t.Context(), "file1", "0123456789abcdefghij", t1)
+			r.CheckRemoteItems(t, file1)
+			vfs.SetLimits(PermAll, 25)
+
+			// Rewriting the file only counts the new contents
+			fd, err := vfs.OpenFile("file1", os.O_WRONLY|os.O_TRUNC, 0777)
+			require.NoError(t, err)
+			_, err = fd.WriteString("abcdefghij0123456789")
+			require.NoError(t, err)
+			require.NoError(t, fd.Close())
+			_, used, _ := vfs.Statfs()
+			assert.Equal(t, int64(20), used)
+		})
+	}
+}
+
+func TestVFSQuotaFailedRewrite(t *testing.T) {
+	// The memory backend keeps the old file if an upload fails
+	f, err := fs.NewFs(t.Context(), ":memory:"+t.Name())
+	require.NoError(t, err)
+	_, err = f.Put(t.Context(), strings.NewReader("0123456789"), object.NewStaticObjectInfo("file1", t1, 10, true, nil, nil))
+	require.NoError(t, err)
+	vfs := New(f, nil)
+	t.Cleanup(func() { cleanupVFS(t, vfs) })
+	vfs.SetLimits(PermAll, 25)
+
+	fd, err := vfs.OpenFile("file1", os.O_WRONLY|os.O_TRUNC, 0777)
+	require.NoError(t, err)
+	_, err = fd.WriteString("abcde")
+	require.NoError(t, err)
+	_, used, _ := vfs.Statfs()
+	assert.Equal(t, int64(5), used)
+
+	// The old file is still there after the upload fails so is
+	// charged for again
+	fh, ok := fd.(*WriteFileHandle)
+	require.True(t, ok)
+	require.NoError(t, fh.pipeWriter.CloseWithError(errors.New("aborted")))
+	assert.Error(t, fd.Close())
+	o, err := f.NewObject(t.Context(), "file1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), o.Size())
+	_, used, _ = vfs.Statfs()
+	assert.Equal(t, int64(10), used)
+}
+
+func TestVFSQuotaMissingRoot(t *testing.T) {
+	r := fstest.NewRun(t)
+	f, err := fs.NewFs(t.Context(), r.FremoteName+"/missing")
+	require.NoError(t, err)
+	vfs := New(f, nil)
+	t.Cleanup(func() { cleanupVFS(t, vfs) })
+	vfs.SetLimits(PermAll, 25)
+
+	// A root which doesn't exist yet has nothing in use
+	vfs.quotaUsed = 99
+	done := make(chan struct{})
+	vfs.readQuota(done)
+	<-done
+	assert.Equal(t, int64(0), vfs.quotaUsed)
+}
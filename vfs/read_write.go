@@ -27,7 +27,8 @@ type RWFileHandle struct {
 	offset      int64 // file pointer offset
 	closed      bool  // set if handle has been closed
 	opened      bool
-	writeCalled bool // if any Write() methods have been called
+	writeCalled bool  // if any Write() methods have been called
+	reserved    int64 // bytes charged against the quota by this handle
 }
 
 // Lock performs Unix locking, not supported
@@ -167,6 +168,11 @@ func (fh *RWFileHandle) close() (err error) {
 	if fh.opened {
 		err = fh.item.Close(fh.file.setObject)
 		fh.opened = false
+		if err != nil {
+			// The file wasn't stored so give back the quota it used
+			fh.unreserve(fh.reserved)
+		}
+		fh.reserved = 0
 	} else {
 		// apply any pending mod times if any
 		_ = fh.file.applyPendingModTime()
@@ -344,6 +350,14 @@ func (fh *RWFileHandle) _writeAt(b []byte, off int64, release bool) (n int, err
 		fh.offset = size
 		off = fh.offset
 	}
+	// Only bytes which extend the file count against the quota
+	grow := off + int64(len(b)) - fh._size()
+	if err = fh.file.VFS().checkQuota(grow); err != nil {
+		return n, err
+	}
+	if grow > 0 {
+		fh.reserved += grow
+	}
 	fh.writeCalled = true
 	if release {
 		// Do the writing with fh.mu unlocked
@@ -354,6 +368,8 @@ func (fh *RWFileHandle) _writeAt(b []byte, off int64, release bool) (n int, err
 		fh.mu.Lock()
 	}
 	if err != nil {
+		// Give back the quota for the bytes which weren't written
+		fh.unreserve(min(grow, int64(len(b)-n)))
 		return n, err
 	}
 
@@ -393,8 +409,32 @@ func (fh *RWFileHandle) _truncate(size int64) (err error) {
 	if size == fh._size() {
 		return nil
 	}
+	// Growing the file counts against the quota and shrinking it
+	// gives the space back
+	oldSize := fh._size()
+	if size < oldSize {
+		fh.unreserve(oldSize - size)
+	} else if err = fh.file.VFS().checkQuota(size - oldSize); err != nil {
+		return err
+	} else {
+		fh.reserved += size - oldSize
+	}
 	fh.file.setSize(size)
-	return fh.item.Truncate(size)
+	if err = fh.item.Truncate(size); err != nil && size > oldSize {
+		fh.unreserve(size - oldSize)
+	}
+	return err
+}
+
+// unreserve gives back n bytes of quota
+//
+// call with the lock held
+func (fh *RWFileHandle) unreserve(n int64) {
+	if n <= 0 {
+		return
+	}
+	fh.reserved = max(fh.reserved-n, 0)
+	fh.file.VFS().quotaRemoved(n)
 }
 
 // Truncate file to given size
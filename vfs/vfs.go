@@ -175,17 +175,25 @@ var (
 
 // VFS represents the top level filing system
 type VFS struct {
-	f           fs.Fs
-	root        *Dir
-	Opt         vfscommon.Options
-	cache       *vfscache.Cache
-	cancel      context.CancelFunc
-	cancelCache context.CancelFunc
-	usageMu     sync.Mutex
-	usageTime   time.Time
-	usage       *fs.Usage
-	pollChan    chan time.Duration
-	inUse       atomic.Int32 // count of number of opens
+	f            fs.Fs
+	root         *Dir
+	Opt          vfscommon.Options
+	cache        *vfscache.Cache
+	ctx          context.Context // cancelled when the VFS is shut down
+	cancel       context.CancelFunc
+	cancelCache  context.CancelFunc
+	usageMu      sync.Mutex
+	usageTime    time.Time
+	usage        *fs.Usage
+	pollChan     chan time.Duration
+	inUse        atomic.Int32  // count of number of opens
+	perms        Permissions   // operations allowed - see SetLimits
+	quota        int64         // max bytes stored or -1 for no quota
+	quotaMu      sync.Mutex    // protects the following
+	quotaTime    time.Time     // when quotaUsed was read
+	quotaUsed    int64         // bytes in use when last read
+	quotaDelta   int64         // bytes written less bytes removed since quotaUsed was read
+	quotaReading chan struct{} // closed when the read of quotaUsed in progress is done
 }
 
 // Keep track of active VFS keyed on fs.ConfigString(f)
@@ -201,7 +209,10 @@ func New(f fs.Fs, opt *vfscommon.Options) *VFS {
 	ctx, cancel := context.WithCancel(context.Background())
 	vfs := &VFS{
 		f:      f,
+		ctx:    ctx,
 		cancel: cancel,
+		perms:  PermAll,
+		quota:  -1,
 	}
 	vfs.inUse.Store(1)
 
@@ -696,6 +707,12 @@ func (vfs *VFS) Statfs() (total, used, free int64) {
 		total = int64(vfs.Opt.DiskSpaceTotalSize)
 	}
 
+	if vfs.quota >= 0 {
+		used = vfs.quotaUsage()
+		total = vfs.quota
+		free = max(total-used, 0)
+	}
+
 	total, used, free = fillInMissingSizes(total, used, free, unknownFreeBytes)
 	return
 }
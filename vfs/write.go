@@ -27,6 +27,8 @@ type WriteFileHandle struct {
 	writeCalled bool // set the first time Write() is called
 	opened      bool
 	truncated   bool
+	reserved    int64 // bytes charged against the quota by this handle
+	replaced    int64 // bytes of the file being replaced given back to the quota
 }
 
 // Check interfaces
@@ -80,6 +82,10 @@ func (fh *WriteFileHandle) openPending() (err error) {
 		fh.o = o
 		fh.result <- err
 	}()
+	// The upload replaces the existing file so its space is given
+	// back, unless the upload fails
+	fh.replaced = fh.file.Size()
+	fh.file.VFS().quotaRemoved(fh.replaced)
 	fh.file.setSize(0)
 	fh.truncated = true
 	fh.file.Dir().addObject(fh.file) // make sure the directory has this object in it now
@@ -142,11 +148,17 @@ func (fh *WriteFileHandle) writeAt(p []byte, off int64) (n int, err error) {
 	if err = fh.openPending(); err != nil {
 		return 0, err
 	}
+	if err = fh.file.VFS().checkQuota(int64(len(p))); err != nil {
+		return 0, err
+	}
 	fh.writeCalled = true
 	n, err = fh.pipeWriter.Write(p)
 	fh.offset += int64(n)
+	fh.reserved += int64(n)
 	fh.file.setSize(fh.offset)
 	if err != nil {
+		// Give back the quota for the bytes which weren't written
+		fh.file.VFS().quotaRemoved(int64(len(p) - n))
 		fs.Errorf(fh.remote, "WriteFileHandle.Write error: %v", err)
 		return 0, err
 	}
@@ -206,10 +218,25 @@ func (fh *WriteFileHandle) close() (err error) {
 	if err == nil {
 		fh.file.setObject(fh.o)
 		err = writeCloseErr
-	} else if fh.file.getObject() == nil {
-		// Remove vfs file entry when no object is present
-		_ = fh.file.Remove()
+	} else {
+		// The upload failed so give back the quota it used
+		fh.file.VFS().quotaRemoved(fh.reserved)
+		if fh.replaced > 0 {
+			// Charge again for the file it was replacing if
+			// the backend left it in place
+			if o, err := fh.file.Fs().NewObject(context.TODO(), fh.remote); err == nil {
+				fh.file.VFS().quotaRestored(o.Size())
+			}
+		}
+		if fh.file.getObject() == nil {
+			// Remove vfs file entry when no object is present,
+			// zeroing its size as its quota is given back already
+			fh.file.setSize(0)
+			_ = fh.file.Remove()
+		}
 	}
+	fh.reserved = 0
+	fh.replaced = 0
 	return err
 }
 